@@ -0,0 +1,324 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/validation"
+)
+
+// probeTarget is a host and port combination evaluated when metrics are
+// requested.
+type probeTarget struct {
+	// name is the hostname or FQDN used for SNI and hostname verification.
+	// This is empty for targets specified by IP Address.
+	name string
+
+	// ipAddress is the IP Address used to open the connection.
+	ipAddress string
+
+	// port is the TCP port used to open the connection.
+	port int
+}
+
+// probeResult is the outcome of retrieving and evaluating the certificate
+// chain for a probe target.
+type probeResult struct {
+	target    probeTarget
+	certChain []*x509.Certificate
+	problems  int
+	duration  time.Duration
+	err       error
+}
+
+// probeTargets expands the given host patterns and ports into a flat list of
+// probe targets.
+func probeTargets(hosts []netutils.HostPattern, ports []int) []probeTarget {
+	var targets []probeTarget
+
+	for _, host := range hosts {
+		var name string
+		if host.Resolved {
+			name = host.Given
+		}
+
+		// Ports advertised by a DNS SRV record override the ports specified
+		// for all host patterns.
+		targetPorts := ports
+		if len(host.Ports) > 0 {
+			targetPorts = host.Ports
+		}
+
+		for _, ipAddr := range host.Expanded {
+			for _, port := range targetPorts {
+				targets = append(targets, probeTarget{
+					name:      name,
+					ipAddress: ipAddr,
+					port:      port,
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+// hostLabel returns the value used for the host metric label, falling back
+// to the IP Address for targets without a hostname.
+func (pt probeTarget) hostLabel() string {
+	if pt.name != "" {
+		return pt.name
+	}
+
+	return pt.ipAddress
+}
+
+// probe retrieves and evaluates the certificate chain for the given target.
+func probe(cfg *config.Config, target probeTarget, log zerolog.Logger) probeResult {
+	start := time.Now()
+
+	certChain, _, err := netutils.GetCertsWithOptions(
+		target.name,
+		target.ipAddress,
+		target.port,
+		cfg.Timeout(),
+		cfg.CertRetrievalOptions(),
+		log,
+	)
+
+	result := probeResult{
+		target:    target,
+		certChain: certChain,
+		err:       err,
+	}
+
+	if err == nil && len(certChain) == 0 {
+		result.err = certs.ErrNoCertsFound
+	}
+
+	if result.err == nil {
+		// Hostname validation check results are ignored for targets
+		// specified by IP Address.
+		validationResults, _ := validation.Run(
+			cfg,
+			target.name,
+			"",
+			validation.Endpoint{
+				HostVal: target.name,
+				IPAddr:  target.ipAddress,
+				Port:    target.port,
+			},
+			certChain,
+			certs.CertBlocklist{},
+			nil,
+			time.Time{},
+			log,
+		)
+		result.problems = validationResults.NumFailed()
+	}
+
+	result.duration = time.Since(start)
+
+	return result
+}
+
+// probeAll evaluates all given targets concurrently, limited by the
+// sysadmin-specified scan rate limit. Results are returned in the same order
+// as the given targets.
+func probeAll(cfg *config.Config, targets []probeTarget, log zerolog.Logger) []probeResult {
+	results := make([]probeResult, len(targets))
+	rateLimiter := make(chan struct{}, cfg.ScanRateLimit)
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		rateLimiter <- struct{}{}
+
+		go func(i int, target probeTarget) {
+			defer func() {
+				<-rateLimiter
+				wg.Done()
+			}()
+
+			results[i] = probe(cfg, target, log)
+
+			if results[i].err != nil {
+				log.Debug().
+					Err(results[i].err).
+					Str("host", target.name).
+					Str("ip_address", target.ipAddress).
+					Int("port", target.port).
+					Msg("Failed to retrieve certificate chain")
+			}
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+// escapeLabelValue escapes a metric label value per the Prometheus text
+// exposition format.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// metricFamily is a collection of samples for a single metric name.
+type metricFamily struct {
+	name    string
+	help    string
+	samples []string
+}
+
+// add records a sample for the metric family using the given label
+// name/value pairs.
+func (mf *metricFamily) add(value float64, labels ...string) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(
+			"%s=\"%s\"",
+			labels[i],
+			escapeLabelValue(labels[i+1]),
+		))
+	}
+
+	mf.samples = append(mf.samples, fmt.Sprintf(
+		"%s{%s} %s",
+		mf.name,
+		strings.Join(pairs, ","),
+		strconv.FormatFloat(value, 'f', -1, 64),
+	))
+}
+
+// write emits the metric family in the Prometheus text exposition format.
+func (mf metricFamily) write(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", mf.name, mf.help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", mf.name)
+
+	for _, sample := range mf.samples {
+		buf.WriteString(sample)
+		buf.WriteString("\n")
+	}
+}
+
+// renderMetrics converts the given probe results into the Prometheus text
+// exposition format.
+func renderMetrics(results []probeResult) []byte {
+	probeSuccess := metricFamily{
+		name: "cert_probe_success",
+		help: "Whether the certificate chain was successfully retrieved.",
+	}
+	probeDuration := metricFamily{
+		name: "cert_probe_duration_seconds",
+		help: "Time taken to retrieve and evaluate the certificate chain.",
+	}
+	chainProblems := metricFamily{
+		name: "cert_chain_problems",
+		help: "Number of failed validation checks for the certificate chain.",
+	}
+	notAfter := metricFamily{
+		name: "cert_not_after_seconds",
+		help: "Expiration time of the certificate in seconds since the Unix epoch.",
+	}
+	expiresInDays := metricFamily{
+		name: "cert_expires_in_days",
+		help: "Number of days until the certificate expires; negative if expired.",
+	}
+
+	for _, result := range results {
+		host := result.target.hostLabel()
+		ipAddr := result.target.ipAddress
+		port := strconv.Itoa(result.target.port)
+
+		success := 1.0
+		if result.err != nil {
+			success = 0
+		}
+
+		probeSuccess.add(success, "host", host, "ip_address", ipAddr, "port", port)
+		probeDuration.add(result.duration.Seconds(), "host", host, "ip_address", ipAddr, "port", port)
+
+		if result.err != nil {
+			continue
+		}
+
+		chainProblems.add(float64(result.problems), "host", host, "ip_address", ipAddr, "port", port)
+
+		for idx, cert := range result.certChain {
+			// An error is only returned for a nil certificate.
+			daysRemaining, _ := certs.ExpiresInDays(cert)
+
+			labels := []string{
+				"host", host,
+				"ip_address", ipAddr,
+				"port", port,
+				"index", strconv.Itoa(idx + 1),
+				"chain_position", certs.ChainPosition(cert, result.certChain).String(),
+				"common_name", cert.Subject.CommonName,
+				"serial", certs.FormatCertSerialNumber(cert.SerialNumber),
+			}
+
+			notAfter.add(float64(cert.NotAfter.Unix()), labels...)
+			expiresInDays.add(float64(daysRemaining), labels...)
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, mf := range []metricFamily{
+		probeSuccess,
+		probeDuration,
+		chainProblems,
+		notAfter,
+		expiresInDays,
+	} {
+		mf.write(&buf)
+	}
+
+	return buf.Bytes()
+}
+
+// metricsHandler returns an HTTP handler which evaluates all targets and
+// emits the results in the Prometheus text exposition format each time
+// metrics are requested.
+func metricsHandler(cfg *config.Config, targets []probeTarget, log zerolog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		results := probeAll(cfg, targets, log)
+		body := renderMetrics(results)
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := w.Write(body); err != nil {
+			log.Error().Err(err).Msg("Failed to write metrics response")
+		}
+
+		log.Debug().
+			Str("remote_addr", r.RemoteAddr).
+			Int("targets", len(targets)).
+			Dur("duration", time.Since(start)).
+			Msg("Metrics request completed")
+	})
+}
@@ -0,0 +1,216 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// API endpoints served by the monitor.
+const (
+	targetsPath string = "/api/v1/targets"
+	healthPath  string = "/healthz"
+)
+
+// Health status values reported by the health endpoint.
+const (
+	healthStarting string = "starting"
+	healthOK       string = "ok"
+	healthStale    string = "stale"
+)
+
+// targetsResponse is the response body for the targets list endpoint.
+type targetsResponse struct {
+	// States is the number of targets in each state.
+	States map[string]int `json:"states"`
+
+	// Targets is the most recent status of each (matching) target.
+	Targets []targetStatus `json:"targets"`
+}
+
+// healthResponse is the response body for the health endpoint.
+type healthResponse struct {
+	Status                string     `json:"status"`
+	Version               string     `json:"version"`
+	Targets               int        `json:"targets"`
+	ChecksCompleted       int        `json:"checks_completed"`
+	LastCheckCompletedAt  *time.Time `json:"last_check_completed_at,omitempty"`
+	CheckIntervalSeconds  float64    `json:"check_interval_seconds"`
+	UptimeSeconds         float64    `json:"uptime_seconds"`
+	StaleThresholdSeconds float64    `json:"stale_threshold_seconds"`
+}
+
+// errorResponse is the response body used to report a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeJSON emits the given value as the JSON response body using the given
+// HTTP status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}, log zerolog.Logger) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Error().Err(err).Msg("Failed to write API response")
+	}
+}
+
+// writeError emits a JSON error response using the given HTTP status code.
+func writeError(w http.ResponseWriter, code int, msg string, log zerolog.Logger) {
+	writeJSON(w, code, errorResponse{Error: msg}, log)
+}
+
+// allowReadOnly rejects requests using methods other than GET or HEAD,
+// returning false if the request was rejected.
+func allowReadOnly(w http.ResponseWriter, r *http.Request, log zerolog.Logger) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+
+	w.Header().Set("Allow", "GET, HEAD")
+	writeError(w, http.StatusMethodNotAllowed, fmt.Sprintf("method %s not allowed", r.Method), log)
+
+	return false
+}
+
+// targetsHandler returns an HTTP handler which emits the most recent status
+// of all targets (optionally limited to those in the state given by the
+// state query parameter) or, for requests to the path of a specific target
+// ID (e.g., /api/v1/targets/3), the most recent status of that target.
+func targetsHandler(state *monitorState, log zerolog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowReadOnly(w, r, log) {
+			return
+		}
+
+		idValue := strings.Trim(strings.TrimPrefix(r.URL.Path, targetsPath), "/")
+		if idValue != "" {
+			id, err := strconv.Atoi(idValue)
+			if err != nil {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("invalid target ID %q", idValue), log)
+				return
+			}
+
+			status, ok := state.Status(id)
+			if !ok {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("target %d not found", id), log)
+				return
+			}
+
+			writeJSON(w, http.StatusOK, status, log)
+
+			return
+		}
+
+		stateFilter := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("state")))
+
+		resp := targetsResponse{
+			States:  make(map[string]int),
+			Targets: []targetStatus{},
+		}
+
+		for _, status := range state.Statuses() {
+			resp.States[status.State]++
+
+			if stateFilter != "" && status.State != stateFilter {
+				continue
+			}
+
+			resp.Targets = append(resp.Targets, status)
+		}
+
+		writeJSON(w, http.StatusOK, resp, log)
+	})
+}
+
+// staleThreshold returns the time allowed since the most recent check of
+// all targets completed (or the monitor was started) before the monitor is
+// considered unhealthy.
+func staleThreshold(cfg *config.Config) time.Duration {
+	return 2*cfg.CheckInterval() + cfg.Timeout()
+}
+
+// healthHandler returns an HTTP handler which reports whether targets are
+// being checked on schedule. A 503 Service Unavailable status is returned if
+// a check of all targets has not completed within twice the check interval
+// (plus the connection timeout).
+func healthHandler(cfg *config.Config, state *monitorState, log zerolog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowReadOnly(w, r, log) {
+			return
+		}
+
+		rounds, lastCompleted := state.Rounds()
+
+		resp := healthResponse{
+			Status:                healthOK,
+			Version:               config.Version(),
+			Targets:               len(state.Statuses()),
+			ChecksCompleted:       rounds,
+			CheckIntervalSeconds:  cfg.CheckInterval().Seconds(),
+			UptimeSeconds:         time.Since(state.started).Seconds(),
+			StaleThresholdSeconds: staleThreshold(cfg).Seconds(),
+		}
+
+		since := state.started
+		if rounds > 0 {
+			completed := lastCompleted.UTC()
+			resp.LastCheckCompletedAt = &completed
+			since = lastCompleted
+		}
+
+		code := http.StatusOK
+		switch {
+		case time.Since(since) > staleThreshold(cfg):
+			resp.Status = healthStale
+			code = http.StatusServiceUnavailable
+
+		case rounds == 0:
+			resp.Status = healthStarting
+		}
+
+		writeJSON(w, code, resp, log)
+	})
+}
+
+// newAPIHandler returns the HTTP handler serving the monitor API.
+func newAPIHandler(cfg *config.Config, state *monitorState, log zerolog.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(targetsPath, targetsHandler(state, log))
+	mux.Handle(targetsPath+"/", targetsHandler(state, log))
+	mux.Handle(healthPath, healthHandler(cfg, state, log))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", r.URL.Path), log)
+			return
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\n\nTarget results are available at %s and %s/{id}\nHealth status is available at %s\n",
+			config.Version(),
+			targetsPath,
+			targetsPath,
+			healthPath,
+		)
+	})
+
+	return mux
+}
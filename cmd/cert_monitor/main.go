@@ -0,0 +1,117 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:generate go-winres make --product-version=git-tag --file-version=git-tag
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/profiling"
+)
+
+// Timeouts applied to the HTTP server used to expose the API.
+const (
+	serverReadHeaderTimeout time.Duration = 5 * time.Second
+	serverShutdownTimeout   time.Duration = 10 * time.Second
+)
+
+func main() {
+
+	// Setup configuration by parsing user-provided flags.
+	cfg, cfgErr := config.New(config.AppType{Monitor: true})
+	switch {
+	case errors.Is(cfgErr, config.ErrVersionRequested):
+		fmt.Println(config.Version())
+
+		return
+
+	case cfgErr != nil:
+
+		// We make some assumptions when setting up our logger as we do not
+		// have a working configuration based on sysadmin-specified choices.
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr}
+		logger := zerolog.New(consoleWriter).With().Timestamp().Caller().Logger()
+
+		logger.Err(cfgErr).Msg("Error initializing application")
+
+		os.Exit(1)
+	}
+
+	log := cfg.Log.With().Logger()
+
+	stopProfiling, err := profiling.Start(cfg.ProfileCPU, cfg.ProfileMem)
+	if err != nil {
+		log.Error().Err(err).Msg("Error starting profiling")
+
+		os.Exit(1)
+	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			log.Error().Err(err).Msg("Error writing profiles")
+		}
+	}()
+
+	targets, err := resolveTargets(cfg, log)
+	if err != nil {
+		log.Error().Err(err).Msg("Error expanding hosts")
+
+		os.Exit(1)
+	}
+
+	state := newMonitorState(targets)
+
+	server := &http.Server{
+		Addr:              cfg.ListenAddress,
+		Handler:           newAPIHandler(cfg, state, log),
+		ReadHeaderTimeout: serverReadHeaderTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go runSchedule(ctx, cfg, state, log)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info().
+			Int("targets", len(targets)).
+			Msg("Starting API server")
+
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("API server failed")
+
+			os.Exit(1)
+		}
+
+	case <-ctx.Done():
+		log.Info().Msg("Shutdown requested, stopping API server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("Failed to gracefully stop API server")
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// newTestConfig instantiates the configuration using the given CLI flag
+// values as the sysadmin would.
+func newTestConfig(t *testing.T, args ...string) *config.Config {
+	t.Helper()
+
+	// Save old command-line arguments so that we can restore them later
+	// https://stackoverflow.com/questions/33723300/how-to-test-the-passing-of-arguments-in-golang
+	oldArgs := os.Args
+	t.Cleanup(func() {
+		os.Args = oldArgs
+	})
+
+	os.Args = append([]string{"cert_monitor"}, args...)
+
+	// Reset parsed flags by discarding the previous default flagset
+	// and creating a new one from scratch.
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+	cfg, err := config.New(config.AppType{Monitor: true})
+	if err != nil {
+		t.Fatalf("Error encountered when instantiating configuration: %v", err)
+	}
+
+	return cfg
+}
+
+// TestAssertWorkingConfigValidation asserts that the validation for the most
+// common flag combinations works as expected.
+func TestAssertWorkingConfigValidation(t *testing.T) {
+	cfg := newTestConfig(t,
+		"--"+config.HostsFlagLong, "127.0.0.1",
+		"--"+config.PortsFlagLong, "443",
+		"--"+config.CheckIntervalFlag, "15",
+	)
+
+	if cfg.CheckInterval() != 15*time.Minute {
+		t.Errorf("want check interval %v; got %v", 15*time.Minute, cfg.CheckInterval())
+	}
+}
+
+// TestProfilingFlags asserts that the CPU and memory profiling flags are
+// supported.
+func TestProfilingFlags(t *testing.T) {
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.prof")
+	memProfile := filepath.Join(dir, "mem.prof")
+
+	cfg := newTestConfig(t,
+		"--"+config.HostsFlagLong, "127.0.0.1",
+		"--"+config.ProfileCPUFlag, cpuProfile,
+		"--"+config.ProfileMemFlag, memProfile,
+	)
+
+	if cfg.ProfileCPU != cpuProfile || cfg.ProfileMem != memProfile {
+		t.Errorf("want profiles %s and %s; got %s and %s", cpuProfile, memProfile, cfg.ProfileCPU, cfg.ProfileMem)
+	}
+}
+
+func TestMonitorAPI(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	_, serverPort, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to parse test server address: %v", err)
+	}
+
+	// A second port with no listener.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	_, closedPort, _ := net.SplitHostPort(listener.Addr().String())
+	_ = listener.Close()
+
+	cfg := newTestConfig(t,
+		"--"+config.HostsFlagLong, "127.0.0.1",
+		"--"+config.PortsFlagLong, serverPort+","+closedPort,
+		"--"+config.TimeoutFlagLong, "5",
+	)
+
+	hosts, err := cfg.Hosts()
+	if err != nil {
+		t.Fatalf("Failed to expand hosts: %v", err)
+	}
+
+	targets := monitorTargets(hosts, cfg.CertPorts())
+	if len(targets) != 2 {
+		t.Fatalf("want 2 targets; got %d", len(targets))
+	}
+
+	state := newMonitorState(targets)
+	handler := newAPIHandler(cfg, state, zerolog.Nop())
+
+	get := func(path string, v interface{}) int {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if v != nil {
+			if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+				t.Fatalf("Failed to decode %s response: %v", path, err)
+			}
+		}
+
+		return rec.Code
+	}
+
+	// Targets are reported as pending until checked.
+	var health healthResponse
+	if code := get(healthPath, &health); code != http.StatusOK || health.Status != healthStarting {
+		t.Errorf("want %d status and %q health; got %d and %q", http.StatusOK, healthStarting, code, health.Status)
+	}
+
+	var list targetsResponse
+	get(targetsPath, &list)
+	if list.States[statePending] != 2 {
+		t.Errorf("want 2 pending targets; got %v", list.States)
+	}
+
+	checkAll(cfg, targets, state, zerolog.Nop())
+
+	if code := get(healthPath, &health); code != http.StatusOK || health.Status != healthOK || health.ChecksCompleted != 1 {
+		t.Errorf("want %d status and %q health after 1 check; got %d and %+v", http.StatusOK, healthOK, code, health)
+	}
+
+	list = targetsResponse{}
+	get(targetsPath+"?state=critical", &list)
+	if len(list.Targets) != 1 || strconv.Itoa(list.Targets[0].Port) != closedPort || list.Targets[0].Error == "" {
+		t.Errorf("want CRITICAL state with error for port %s only; got %+v", closedPort, list.Targets)
+	}
+
+	// The self-signed test server certificate is retrieved and evaluated.
+	var status targetStatus
+	if code := get(targetsPath+"/1", &status); code != http.StatusOK {
+		t.Fatalf("want %d status for target 1; got %d", http.StatusOK, code)
+	}
+
+	switch {
+	case strconv.Itoa(status.Port) != serverPort:
+		t.Errorf("want target 1 port %s; got %d", serverPort, status.Port)
+	case len(status.Certs) == 0 || len(status.Checks) == 0:
+		t.Errorf("want certificates and validation checks for target 1; got %+v", status)
+	case status.CheckedAt == nil || status.StateChangedAt == nil || !status.StateChangedAt.Equal(*status.CheckedAt):
+		t.Errorf("want state change recorded at first check; got %+v", status)
+	}
+
+	// Validity dates are also provided as seconds since the Unix epoch.
+	for _, cert := range status.Certs {
+		if cert.NotAfterEpoch != cert.NotAfter.Unix() || cert.NotBeforeEpoch != cert.NotBefore.Unix() {
+			t.Errorf("want epoch validity dates matching %v and %v; got %d and %d",
+				cert.NotBefore, cert.NotAfter, cert.NotBeforeEpoch, cert.NotAfterEpoch)
+		}
+	}
+
+	// The state change time is carried over when the state is unchanged.
+	firstChange := *status.StateChangedAt
+	checkAll(cfg, targets, state, zerolog.Nop())
+	get(targetsPath+"/1", &status)
+	if !status.StateChangedAt.Equal(firstChange) {
+		t.Errorf("want state change time %v; got %v", firstChange, status.StateChangedAt)
+	}
+
+	for _, path := range []string{targetsPath + "/3", targetsPath + "/abc", "/api/v1/unknown"} {
+		if code := get(path, nil); code != http.StatusNotFound {
+			t.Errorf("want %d status for %s; got %d", http.StatusNotFound, path, code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, targetsPath, strings.NewReader("{}")))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("want %d status for POST; got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+
+	// The monitor is unhealthy if checks have not completed on schedule.
+	state.mu.Lock()
+	state.lastRoundCompleted = time.Now().Add(-2 * staleThreshold(cfg))
+	state.mu.Unlock()
+
+	if code := get(healthPath, &health); code != http.StatusServiceUnavailable || health.Status != healthStale {
+		t.Errorf("want %d status and %q health; got %d and %q", http.StatusServiceUnavailable, healthStale, code, health.Status)
+	}
+}
+
+func TestMonitorStateSetTargets(t *testing.T) {
+	unchanged := monitorTarget{name: "www.example.com", ipAddress: "192.0.2.10", port: 443}
+	removed := monitorTarget{name: "www.example.com", ipAddress: "192.0.2.11", port: 443}
+	added := monitorTarget{name: "www.example.com", ipAddress: "192.0.2.12", port: 443}
+
+	state := newMonitorState([]monitorTarget{removed, unchanged})
+
+	checkedAt := time.Now().UTC()
+	for _, status := range state.Statuses() {
+		status.State = "OK"
+		status.CheckedAt = &checkedAt
+		state.update(status)
+	}
+
+	// Updated DNS records no longer resolve to one IP Address and now
+	// resolve to another.
+	state.setTargets([]monitorTarget{unchanged, added})
+
+	targets := state.Targets()
+	if len(targets) != 2 || targets[0] != unchanged || targets[1] != added {
+		t.Fatalf("want targets %v; got %v", []monitorTarget{unchanged, added}, targets)
+	}
+
+	first, ok := state.Status(1)
+	switch {
+	case !ok:
+		t.Fatal("want status for target 1")
+	case first.IPAddress != unchanged.ipAddress || first.State != "OK" || first.StateChangedAt == nil:
+		t.Errorf("want status carried over for target 1; got %+v", first)
+	}
+
+	second, ok := state.Status(2)
+	switch {
+	case !ok:
+		t.Fatal("want status for target 2")
+	case second.IPAddress != added.ipAddress || second.State != statePending:
+		t.Errorf("want pending status for new target 2; got %+v", second)
+	}
+
+	if _, ok := state.Status(3); ok {
+		t.Error("want no status for target 3")
+	}
+}
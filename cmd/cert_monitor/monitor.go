@@ -0,0 +1,473 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/textutils"
+	"github.com/atc0005/check-cert/internal/validation"
+)
+
+// statePending is the state reported for a target which has not yet been
+// checked.
+const statePending string = "PENDING"
+
+// monitorTarget is a host and port combination checked on a schedule.
+type monitorTarget struct {
+	// name is the hostname or FQDN used for SNI and hostname verification.
+	// This is empty for targets specified by IP Address.
+	name string
+
+	// ipAddress is the IP Address used to open the connection.
+	ipAddress string
+
+	// port is the TCP port used to open the connection.
+	port int
+}
+
+// resolveTargets expands and resolves the sysadmin-specified host patterns
+// and ports into a flat list of monitored targets. A warning is logged for
+// each IPv6 range which is too large to monitor in full.
+func resolveTargets(cfg *config.Config, log zerolog.Logger) ([]monitorTarget, error) {
+	hosts, err := cfg.Hosts()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range hosts {
+		if host.Truncated {
+			log.Warn().
+				Str("host_pattern", host.Given).
+				Int("hosts_monitored", len(host.Expanded)).
+				Msg("IPv6 range too large to monitor in full; only the first addresses are monitored")
+		}
+	}
+
+	return monitorTargets(hosts, cfg.CertPorts()), nil
+}
+
+// monitorTargets expands the given host patterns and ports into a flat list
+// of monitored targets.
+func monitorTargets(hosts []netutils.HostPattern, ports []int) []monitorTarget {
+	var targets []monitorTarget
+
+	for _, host := range hosts {
+		var name string
+		if host.Resolved {
+			name = host.Given
+		}
+
+		// Ports advertised by a DNS SRV record override the ports specified
+		// for all host patterns.
+		targetPorts := ports
+		if len(host.Ports) > 0 {
+			targetPorts = host.Ports
+		}
+
+		for _, ipAddr := range host.Expanded {
+			for _, port := range targetPorts {
+				targets = append(targets, monitorTarget{
+					name:      name,
+					ipAddress: ipAddr,
+					port:      port,
+				})
+			}
+		}
+	}
+
+	return targets
+}
+
+// checkResult is the machine-readable representation of a single
+// validation check result.
+type checkResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	State   string `json:"state"`
+	Summary string `json:"summary"`
+}
+
+// certResult is the machine-readable representation of a certificate in a
+// retrieved certificate chain.
+type certResult struct {
+	Subject           string    `json:"subject"`
+	CommonName        string    `json:"common_name"`
+	SANsEntries       []string  `json:"sans_entries"`
+	Issuer            string    `json:"issuer"`
+	SerialNumber      string    `json:"serial"`
+	NotBefore         time.Time `json:"not_before"`
+	NotBeforeEpoch    int64     `json:"not_before_epoch"`
+	NotBeforeHuman    string    `json:"not_before_human"`
+	NotAfter          time.Time `json:"not_after"`
+	NotAfterEpoch     int64     `json:"not_after_epoch"`
+	NotAfterHuman     string    `json:"not_after_human"`
+	DaysRemaining     int       `json:"days_remaining"`
+	ChainPosition     string    `json:"chain_position"`
+	FingerprintSHA256 string    `json:"fingerprint_sha256"`
+}
+
+// targetStatus is the machine-readable representation of the most recent
+// check of a monitored target.
+type targetStatus struct {
+	ID              int           `json:"id"`
+	Host            string        `json:"host"`
+	IPAddress       string        `json:"ip_address"`
+	Port            int           `json:"port"`
+	State           string        `json:"state"`
+	Summary         string        `json:"summary"`
+	Error           string        `json:"error,omitempty"`
+	CheckedAt       *time.Time    `json:"checked_at,omitempty"`
+	DurationSeconds float64       `json:"duration_seconds"`
+	StateChangedAt  *time.Time    `json:"state_changed_at,omitempty"`
+	Checks          []checkResult `json:"checks"`
+	Certs           []certResult  `json:"certs"`
+}
+
+// newPendingStatus returns the status reported for the given target before
+// it is first checked.
+func newPendingStatus(id int, target monitorTarget) targetStatus {
+	host := target.name
+	if host == "" {
+		host = target.ipAddress
+	}
+
+	return targetStatus{
+		ID:        id,
+		Host:      host,
+		IPAddress: target.ipAddress,
+		Port:      target.port,
+		State:     statePending,
+		Summary:   "Not yet checked",
+		Checks:    []checkResult{},
+		Certs:     []certResult{},
+	}
+}
+
+// checkTarget retrieves and evaluates the certificate chain for the given
+// target, returning the resulting status. Failure to retrieve a certificate
+// chain results in a CRITICAL state.
+func checkTarget(cfg *config.Config, id int, target monitorTarget, log zerolog.Logger) targetStatus {
+	start := time.Now()
+
+	status := newPendingStatus(id, target)
+
+	certChain, _, err := netutils.GetCertsWithOptions(
+		target.name,
+		target.ipAddress,
+		target.port,
+		cfg.Timeout(),
+		cfg.CertRetrievalOptions(),
+		log,
+	)
+
+	if err == nil && len(certChain) == 0 {
+		err = certs.ErrNoCertsFound
+	}
+
+	switch {
+	case err != nil:
+		status.State = nagios.StateCRITICALLabel
+		status.Summary = fmt.Sprintf(
+			"%s: Error fetching certificates from port %d on %s",
+			nagios.StateCRITICALLabel,
+			target.port,
+			status.Host,
+		)
+		status.Error = err.Error()
+
+	default:
+		// Hostname validation check results are ignored for targets
+		// specified by IP Address.
+		validationResults, _ := validation.Run(
+			cfg,
+			target.name,
+			"",
+			validation.Endpoint{
+				HostVal: target.name,
+				IPAddr:  target.ipAddress,
+				Port:    target.port,
+			},
+			certChain,
+			certs.CertBlocklist{},
+			nil,
+			time.Time{},
+			log,
+		)
+		validationResults.Sort()
+
+		status.State = validationResults.ServiceState().Label
+		status.Summary = validationResults.OneLineSummary()
+
+		for _, result := range validationResults {
+			status.Checks = append(status.Checks, checkResult{
+				Name:    result.CheckName(),
+				Status:  result.ValidationStatus(),
+				State:   result.ServiceState().Label,
+				Summary: result.String(),
+			})
+		}
+
+		for _, cert := range certChain {
+			// An error is only returned for a nil certificate. Expired
+			// certificates report a negative number of days.
+			daysRemaining, _ := certs.ExpiresInDays(cert)
+
+			sansEntries := cert.DNSNames
+			if sansEntries == nil {
+				sansEntries = []string{}
+			}
+
+			fingerprint := sha256.Sum256(cert.Raw)
+
+			status.Certs = append(status.Certs, certResult{
+				Subject:           cert.Subject.String(),
+				CommonName:        cert.Subject.CommonName,
+				SANsEntries:       sansEntries,
+				Issuer:            cert.Issuer.String(),
+				SerialNumber:      certs.FormatCertSerialNumber(cert.SerialNumber),
+				NotBefore:         cert.NotBefore.UTC(),
+				NotBeforeEpoch:    cert.NotBefore.Unix(),
+				NotBeforeHuman:    cert.NotBefore.Format(certs.CertValidityDateLayout),
+				NotAfter:          cert.NotAfter.UTC(),
+				NotAfterEpoch:     cert.NotAfter.Unix(),
+				NotAfterHuman:     cert.NotAfter.Format(certs.CertValidityDateLayout),
+				DaysRemaining:     daysRemaining,
+				ChainPosition:     certs.ChainPosition(cert, certChain).String(),
+				FingerprintSHA256: textutils.BytesToDelimitedHexStr(fingerprint[:], ":"),
+			})
+		}
+	}
+
+	checkedAt := time.Now().UTC()
+	status.CheckedAt = &checkedAt
+	status.DurationSeconds = time.Since(start).Seconds()
+
+	return status
+}
+
+// monitorState records the most recent status of each monitored target.
+// It is safe for concurrent use.
+type monitorState struct {
+	mu sync.RWMutex
+
+	// targets is the collection of monitored targets indexed by target ID
+	// minus one.
+	targets []monitorTarget
+
+	// statuses is the most recent status of each target indexed by target
+	// ID minus one.
+	statuses []targetStatus
+
+	// started is the time that the monitor was started.
+	started time.Time
+
+	// lastRoundCompleted is the time that the most recent check of all
+	// targets completed.
+	lastRoundCompleted time.Time
+
+	// rounds is the number of completed checks of all targets.
+	rounds int
+}
+
+// newMonitorState returns the state for the given targets. Targets are
+// assigned IDs starting at 1 in the order given.
+func newMonitorState(targets []monitorTarget) *monitorState {
+	statuses := make([]targetStatus, len(targets))
+	for i, target := range targets {
+		statuses[i] = newPendingStatus(i+1, target)
+	}
+
+	return &monitorState{
+		targets:  targets,
+		statuses: statuses,
+		started:  time.Now(),
+	}
+}
+
+// setTargets replaces the monitored targets with the given targets. Targets
+// are assigned IDs starting at 1 in the order given. The most recent status
+// of a target which was already monitored is carried over; new targets are
+// reported as pending until checked.
+func (ms *monitorState) setTargets(targets []monitorTarget) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	previous := make(map[monitorTarget]targetStatus, len(ms.targets))
+	for i, target := range ms.targets {
+		previous[target] = ms.statuses[i]
+	}
+
+	statuses := make([]targetStatus, len(targets))
+	for i, target := range targets {
+		status, ok := previous[target]
+		switch {
+		case ok:
+			status.ID = i + 1
+		default:
+			status = newPendingStatus(i+1, target)
+		}
+
+		statuses[i] = status
+	}
+
+	ms.targets = targets
+	ms.statuses = statuses
+}
+
+// Targets returns a copy of the monitored targets.
+func (ms *monitorState) Targets() []monitorTarget {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	targets := make([]monitorTarget, len(ms.targets))
+	copy(targets, ms.targets)
+
+	return targets
+}
+
+// update records the given status for the target. The time of the most
+// recent state change is carried over unless the state has changed.
+func (ms *monitorState) update(status targetStatus) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	previous := ms.statuses[status.ID-1]
+
+	switch {
+	case previous.State != status.State:
+		status.StateChangedAt = status.CheckedAt
+	default:
+		status.StateChangedAt = previous.StateChangedAt
+	}
+
+	ms.statuses[status.ID-1] = status
+}
+
+// completeRound records that a check of all targets has completed.
+func (ms *monitorState) completeRound(completed time.Time) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.lastRoundCompleted = completed
+	ms.rounds++
+}
+
+// Statuses returns a copy of the most recent status of each target.
+func (ms *monitorState) Statuses() []targetStatus {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	statuses := make([]targetStatus, len(ms.statuses))
+	copy(statuses, ms.statuses)
+
+	return statuses
+}
+
+// Status returns the most recent status of the target with the given ID.
+func (ms *monitorState) Status(id int) (targetStatus, bool) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if id < 1 || id > len(ms.statuses) {
+		return targetStatus{}, false
+	}
+
+	return ms.statuses[id-1], true
+}
+
+// Rounds returns the number of completed checks of all targets and the time
+// that the most recent check completed.
+func (ms *monitorState) Rounds() (int, time.Time) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	return ms.rounds, ms.lastRoundCompleted
+}
+
+// checkAll checks all given targets concurrently, limited by the
+// sysadmin-specified scan rate limit, recording each status as the check
+// completes.
+func checkAll(cfg *config.Config, targets []monitorTarget, state *monitorState, log zerolog.Logger) {
+	rateLimiter := make(chan struct{}, cfg.ScanRateLimit)
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		rateLimiter <- struct{}{}
+
+		go func(id int, target monitorTarget) {
+			defer func() {
+				<-rateLimiter
+				wg.Done()
+			}()
+
+			status := checkTarget(cfg, id, target, log)
+			state.update(status)
+
+			log.Debug().
+				Str("host", target.name).
+				Str("ip_address", target.ipAddress).
+				Int("port", target.port).
+				Str("state", status.State).
+				Msg("Checked target")
+		}(i+1, target)
+	}
+
+	wg.Wait()
+
+	state.completeRound(time.Now())
+}
+
+// runSchedule checks all monitored targets immediately and then once per
+// sysadmin-specified check interval until the given context is cancelled.
+// The sysadmin-specified hosts are resolved again before each subsequent
+// check so that changes to DNS records are picked up. The previously
+// monitored targets are checked if the hosts cannot be resolved.
+func runSchedule(ctx context.Context, cfg *config.Config, state *monitorState, log zerolog.Logger) {
+	ticker := time.NewTicker(cfg.CheckInterval())
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		targets := state.Targets()
+		checkAll(cfg, targets, state, log)
+
+		log.Info().
+			Int("targets", len(targets)).
+			Dur("duration", time.Since(start)).
+			Msg("Completed check of all targets")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		resolved, err := resolveTargets(cfg, log)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Msg("Error expanding hosts, checking previously resolved targets")
+
+			continue
+		}
+
+		state.setTargets(resolved)
+	}
+}
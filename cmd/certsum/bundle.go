@@ -0,0 +1,329 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// bundleFormatVersion is the version of the scan results bundle layout.
+const bundleFormatVersion int = 1
+
+// Names of the entries in a scan results bundle.
+const (
+	bundleMetadataFile string = "metadata.json"
+	bundleResultsFile  string = "results.json"
+	bundleCertsDir     string = "certs/"
+)
+
+// bundleMaxEntrySize is the maximum size of a single entry read from a scan
+// results bundle. This guards against corrupted or malicious bundles.
+const bundleMaxEntrySize int64 = 64 * 1024 * 1024
+
+// ErrInvalidBundle indicates that a scan results bundle could not be
+// imported.
+var ErrInvalidBundle = errors.New("invalid scan results bundle")
+
+// bundleChain records where a certificate chain was discovered along with
+// the bundle entry holding the certificate chain in PEM format.
+type bundleChain struct {
+	Host          string   `json:"host"`
+	IPAddress     string   `json:"ip_address"`
+	Port          int      `json:"port"`
+	CorrelationID string   `json:"correlation_id"`
+	VerifiedNames []string `json:"verified_names"`
+	CertFile      string   `json:"cert_file"`
+}
+
+// bundleMetadata describes the scan recorded by a scan results bundle.
+type bundleMetadata struct {
+	FormatVersion int           `json:"bundle_format_version"`
+	Generator     string        `json:"generator"`
+	Created       time.Time     `json:"created"`
+	ScanHost      string        `json:"scan_host"`
+	Ports         []int         `json:"ports"`
+	AgeWarning    int           `json:"age_warning"`
+	AgeCritical   int           `json:"age_critical"`
+	Chains        []bundleChain `json:"chains"`
+}
+
+// bundleCertFile returns the name of the bundle entry used for the
+// certificate chain discovered at the given IP Address and port.
+func bundleCertFile(index int, ipAddr string, port int) string {
+	// IPv6 addresses contain characters which are awkward in file names.
+	name := strings.NewReplacer(":", "_", "%", "_").Replace(ipAddr)
+
+	return fmt.Sprintf("%s%03d-%s-%d.pem", bundleCertsDir, index+1, name, port)
+}
+
+// writeBundle writes the discovered certificate chains to the specified file
+// as a gzip compressed tar archive. The archive contains scan metadata, the
+// scan results in JSON format (as emitted by the json output format) and
+// each certificate chain in PEM format. The file is overwritten if it
+// already exists.
+func writeBundle(
+	filename string,
+	discoveredChains certs.DiscoveredCertChains,
+	ports []int,
+	ageCritical int,
+	ageWarning int,
+) (err error) {
+
+	scanHost, _ := os.Hostname()
+
+	metadata := bundleMetadata{
+		FormatVersion: bundleFormatVersion,
+		Generator:     config.Version(),
+		Created:       time.Now().UTC(),
+		ScanHost:      scanHost,
+		Ports:         ports,
+		AgeWarning:    ageWarning,
+		AgeCritical:   ageCritical,
+		Chains:        make([]bundleChain, 0, len(discoveredChains)),
+	}
+
+	pemFiles := make([][]byte, 0, len(discoveredChains))
+	for i, certChain := range discoveredChains {
+		var pemData bytes.Buffer
+		for _, cert := range certChain.Certs {
+			if err := pem.Encode(&pemData, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+				return fmt.Errorf(
+					"failed to encode certificate chain for %s:%d: %w",
+					certChain.IPAddress,
+					certChain.Port,
+					err,
+				)
+			}
+		}
+
+		pemFiles = append(pemFiles, pemData.Bytes())
+		metadata.Chains = append(metadata.Chains, bundleChain{
+			Host:          certChain.Name,
+			IPAddress:     certChain.IPAddress,
+			Port:          certChain.Port,
+			CorrelationID: certChain.CorrelationID,
+			VerifiedNames: certChain.VerifiedNames(),
+			CertFile:      bundleCertFile(i, certChain.IPAddress, certChain.Port),
+		})
+	}
+
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle metadata: %w", err)
+	}
+
+	resultsJSON, err := json.MarshalIndent(
+		newScanResults(discoveredChains, ageCritical, ageWarning),
+		"",
+		"  ",
+	)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan results: %w", err)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file %q: %w", filename, err)
+	}
+
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close bundle file %q: %w", filename, closeErr)
+		}
+	}()
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+
+	addEntry := func(name string, content []byte) error {
+		hdr := tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: metadata.Created,
+		}
+
+		if err := tw.WriteHeader(&hdr); err != nil {
+			return fmt.Errorf("failed to add %q to bundle: %w", name, err)
+		}
+
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("failed to add %q to bundle: %w", name, err)
+		}
+
+		return nil
+	}
+
+	if err := addEntry(bundleMetadataFile, metadataJSON); err != nil {
+		return err
+	}
+
+	if err := addEntry(bundleResultsFile, resultsJSON); err != nil {
+		return err
+	}
+
+	for i, chain := range metadata.Chains {
+		if err := addEntry(chain.CertFile, pemFiles[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle file %q: %w", filename, err)
+	}
+
+	if err := gzw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle file %q: %w", filename, err)
+	}
+
+	return nil
+}
+
+// readBundle reads the certificate chains recorded in the specified scan
+// results bundle along with the bundle metadata. Each certificate chain is
+// evaluated using the given CRITICAL and WARNING thresholds (specified in
+// number of days from this moment) and validation options with hostname
+// verification performed against the names verified when the bundle was
+// created.
+func readBundle(
+	filename string,
+	ageCritical int,
+	ageWarning int,
+	validationOptions certs.CertChainValidationOptions,
+) (certs.DiscoveredCertChains, bundleMetadata, error) {
+
+	var metadata bundleMetadata
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, metadata, fmt.Errorf("failed to open bundle file %q: %w", filename, err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	gzr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, metadata, fmt.Errorf("%w: %q: %w", ErrInvalidBundle, filename, err)
+	}
+
+	// Entries are read into memory only; nothing is extracted to disk.
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, metadata, fmt.Errorf("%w: %q: %w", ErrInvalidBundle, filename, err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Size > bundleMaxEntrySize {
+			return nil, metadata, fmt.Errorf(
+				"%w: entry %q exceeds maximum size of %d bytes",
+				ErrInvalidBundle,
+				hdr.Name,
+				bundleMaxEntrySize,
+			)
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, metadata, fmt.Errorf("%w: %q: %w", ErrInvalidBundle, filename, err)
+		}
+
+		entries[hdr.Name] = content
+	}
+
+	metadataJSON, ok := entries[bundleMetadataFile]
+	if !ok {
+		return nil, metadata, fmt.Errorf(
+			"%w: %q entry not found",
+			ErrInvalidBundle,
+			bundleMetadataFile,
+		)
+	}
+
+	if err := json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, metadata, fmt.Errorf(
+			"%w: failed to decode %q entry: %w",
+			ErrInvalidBundle,
+			bundleMetadataFile,
+			err,
+		)
+	}
+
+	if metadata.FormatVersion != bundleFormatVersion {
+		return nil, metadata, fmt.Errorf(
+			"%w: unsupported bundle format version %d (supported: %d)",
+			ErrInvalidBundle,
+			metadata.FormatVersion,
+			bundleFormatVersion,
+		)
+	}
+
+	discoveredChains := make(certs.DiscoveredCertChains, 0, len(metadata.Chains))
+	for _, chain := range metadata.Chains {
+		pemData, ok := entries[chain.CertFile]
+		if !ok {
+			return nil, metadata, fmt.Errorf(
+				"%w: certificate chain entry %q for %s:%d not found",
+				ErrInvalidBundle,
+				chain.CertFile,
+				chain.IPAddress,
+				chain.Port,
+			)
+		}
+
+		certChain, _, err := certs.ParsePEMCertificates(pemData)
+		if err != nil {
+			return nil, metadata, fmt.Errorf(
+				"%w: failed to parse certificate chain entry %q: %w",
+				ErrInvalidBundle,
+				chain.CertFile,
+				err,
+			)
+		}
+
+		discoveredChains = append(discoveredChains, certs.DiscoveredCertChain{
+			Name:          chain.Host,
+			IPAddress:     chain.IPAddress,
+			Port:          chain.Port,
+			Certs:         certChain,
+			CorrelationID: chain.CorrelationID,
+			ValidationResults: validateCertChain(
+				certChain,
+				chain.VerifiedNames,
+				ageCritical,
+				ageWarning,
+				validationOptions,
+			),
+		})
+	}
+
+	return discoveredChains, metadata, nil
+}
@@ -9,6 +9,7 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"strings"
 	"sync"
@@ -64,6 +65,7 @@ func certScanCollector(
 
 			default:
 				log.Debug().
+					Str("correlation_id", result.CorrelationID).
 					Str("result", fmt.Sprintf("%v", result)).
 					Msg("certScanCollector received new result")
 				*discoveredCertChains = append(*discoveredCertChains, result)
@@ -84,9 +86,18 @@ func certScanner(
 	portScanResultsChan <-chan netutils.PortCheckResult,
 	showHostsWithClosedPorts bool,
 	showPortScanResults bool,
+	showProgress bool,
 	timeout time.Duration,
+	retrievalOptions netutils.CertRetrievalOptions,
+	resolvedNames map[string][]string,
+	ageCritical int,
+	ageWarning int,
+	validationOptions certs.CertChainValidationOptions,
 	certScanResultsChan chan<- certs.DiscoveredCertChain,
 	rateLimiter chan struct{}, // needs to allow send & receive
+	throttle *hostThrottle,
+	progress *scanProgress,
+	checkpoint *scanCheckpoint,
 	log zerolog.Logger,
 	wg *sync.WaitGroup,
 ) {
@@ -129,11 +140,25 @@ func certScanner(
 				return
 			}
 
-			log.Debug().Msgf("certScanner: Received %v on portScanResultsChan", portScanResult)
+			// Include the identifier assigned to the scan target in all log
+			// events associated with the target.
+			targetLog := log.With().
+				Str("correlation_id", portScanResult.CorrelationID).
+				Logger()
 
-			log.Debug().Msg("Send heartbeat to indicate that we are still receiving values")
+			targetLog.Debug().Msgf("certScanner: Received %v on portScanResultsChan", portScanResult)
+
+			targetLog.Debug().Msg("Send heartbeat to indicate that we are still receiving values")
 			heartBeatChan <- struct{}{}
 
+			progress.PortScanned()
+
+			// Closed ports require no further work; open ports are recorded
+			// as completed once certificate retrieval has been attempted.
+			if !portScanResult.Open {
+				checkpoint.Complete(portScanResult.IPAddress.String(), portScanResult.Port, nil)
+			}
+
 			// unless user opted to show hosts with *all* closed ports, skip the
 			// host and continue to the next one
 			if !showHostsWithClosedPorts && !portScanResult.Open {
@@ -156,6 +181,9 @@ func certScanner(
 			}
 
 			switch {
+			case !showProgress:
+				// Progress output is suppressed for machine-readable
+				// output formats.
 			case showPortScanResults:
 				fmt.Printf("%s: [%s]\n", hostLabel, portScanResult.Summary())
 			default:
@@ -165,7 +193,7 @@ func certScanner(
 			// abort early if context has been cancelled
 			if ctx.Err() != nil {
 				errMsg := "certScanner: ports: context cancelled or expired"
-				log.Error().
+				targetLog.Error().
 					Str("host", portScanResult.Host).
 					Str("ip_address", portScanResult.IPAddress.String()).
 					Int("port", portScanResult.Port).
@@ -177,18 +205,18 @@ func certScanner(
 
 			if portScanResult.Open {
 
-				log.Debug().
+				targetLog.Debug().
 					Str("host", portScanResult.Host).
 					Str("ip_address", portScanResult.IPAddress.String()).
 					Int("port", portScanResult.Port).
 					Msg("Open port found; attempting to retrieve certificate chain")
 
-				log.Debug().Msg("certScanner: incrementing waitgroup")
+				targetLog.Debug().Msg("certScanner: incrementing waitgroup")
 				certScanWG.Add(1)
 
-				log.Debug().Msg("Reserving spot in cert scan rate limiter")
+				targetLog.Debug().Msg("Reserving spot in cert scan rate limiter")
 				rateLimiter <- struct{}{}
-				log.Debug().
+				targetLog.Debug().
 					Int("reserved", len(rateLimiter)).
 					Msg("Cert scan rate limiter reservation added")
 
@@ -226,6 +254,19 @@ func certScanner(
 						}
 					}()
 
+					// Space out connection attempts to this IP Address. The
+					// port is not recorded as completed if the scan is
+					// cancelled while waiting.
+					if err := throttle.Wait(ctx, psResult.IPAddress.String()); err != nil {
+						log.Debug().
+							Err(err).
+							Str("ip_address", psResult.IPAddress.String()).
+							Int("port", psResult.Port).
+							Msg("context cancelled or expired while throttled")
+
+						return
+					}
+
 					var certFetchErr error
 					log.Debug().
 						Str("host", psResult.Host).
@@ -238,15 +279,16 @@ func certScanner(
 					// while we unintentionally connect to another IP (by way
 					// of using a name/FQDN to open the connection) to
 					// retrieve the certificate chain.
-					certChain, certFetchErr := netutils.GetCerts(
+					certChain, _, certFetchErr := netutils.GetCertsWithOptions(
 						psResult.Host,
 						psResult.IPAddress.String(),
 						psResult.Port,
 						timeout,
+						retrievalOptions,
 						log,
 					)
 					if certFetchErr != nil {
-						if !showPortScanResults {
+						if showProgress && !showPortScanResults {
 							// will need to insert a newline in-between error
 							// output if we're not showing port summary results
 							fmt.Println()
@@ -261,23 +303,93 @@ func certScanner(
 						// os.Exit(1)
 						// TODO: Decide whether fetch errors are critical or just warning level
 
+						checkpoint.Complete(psResult.IPAddress.String(), psResult.Port, nil)
+
 						return
 					}
 
-					log.Debug().Msg("Attempting to send cert chain on resultsChan")
-					resultsChan <- certs.DiscoveredCertChain{
-						Name:      psResult.Host,
-						IPAddress: psResult.IPAddress.String(),
-						Port:      psResult.Port,
-						Certs:     certChain,
+					validationResults := validateCertChain(
+						certChain,
+						resolvedNames[psResult.IPAddress.String()],
+						ageCritical,
+						ageWarning,
+						validationOptions,
+					)
+
+					for _, result := range validationResults {
+						if result.IsFailed() {
+							log.Debug().
+								Err(result.Err()).
+								Str("check", result.CheckName()).
+								Str("ip_address", psResult.IPAddress.String()).
+								Int("port", psResult.Port).
+								Msg("Validation check failed for discovered certificate chain")
+						}
 					}
 
+					discoveredCertChain := certs.DiscoveredCertChain{
+						Name:              psResult.Host,
+						IPAddress:         psResult.IPAddress.String(),
+						Port:              psResult.Port,
+						Certs:             certChain,
+						CorrelationID:     psResult.CorrelationID,
+						ValidationResults: validationResults,
+					}
+
+					checkpoint.Complete(psResult.IPAddress.String(), psResult.Port, &discoveredCertChain)
+					progress.ChainFound()
+
+					log.Debug().Msg("Attempting to send cert chain on resultsChan")
+					resultsChan <- discoveredCertChain
+
 					log.Debug().Msg("Finished child cert scanner goroutine")
 
-				}(ctx, portScanResult, timeout, certScanResultsChan, log)
+				}(ctx, portScanResult, timeout, certScanResultsChan, targetLog)
 
 			}
 
 		}
 	}
 }
+
+// validateCertChain evaluates the given certificate chain once so that all
+// summaries and exports share the same validation check results. The
+// certificate chain is evaluated for expired or expiring soon certificates
+// using the given CRITICAL and WARNING thresholds (specified in number of
+// days from this moment) and hostname verification is performed against
+// each of the given hostname or FQDN values. Any certificates that the
+// sysadmin opted to ignore via the given validation options are excluded from
+// expiration validation.
+func validateCertChain(
+	certChain []*x509.Certificate,
+	names []string,
+	ageCritical int,
+	ageWarning int,
+	validationOptions certs.CertChainValidationOptions,
+) certs.CertChainValidationResults {
+	if len(certChain) == 0 {
+		return nil
+	}
+
+	results := make(certs.CertChainValidationResults, 0, len(names)+1)
+	results.Add(certs.ValidateExpiration(
+		certChain,
+		ageCritical,
+		ageWarning,
+		true,
+		false,
+		validationOptions,
+	))
+
+	for _, name := range names {
+		results.Add(certs.ValidateHostname(
+			certChain,
+			name,
+			"",
+			"",
+			certs.CertChainValidationOptions{},
+		))
+	}
+
+	return results
+}
@@ -0,0 +1,444 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/rs/zerolog"
+)
+
+// checkpointFormatVersion is the version of the scan checkpoint file layout.
+const checkpointFormatVersion int = 1
+
+// checkpointInterval is the minimum time between checkpoint file updates
+// while a scan is in progress. The checkpoint file is also updated once the
+// scan stops (completed, timed out or interrupted).
+const checkpointInterval time.Duration = 10 * time.Second
+
+// ErrInvalidCheckpoint indicates that a scan checkpoint file could not be
+// loaded.
+var ErrInvalidCheckpoint = errors.New("invalid scan checkpoint")
+
+// checkpointChain records where a certificate chain was discovered along
+// with the certificate chain in PEM format.
+type checkpointChain struct {
+	Host          string   `json:"host"`
+	IPAddress     string   `json:"ip_address"`
+	Port          int      `json:"port"`
+	CorrelationID string   `json:"correlation_id"`
+	VerifiedNames []string `json:"verified_names"`
+	PEM           string   `json:"pem"`
+}
+
+// checkpointFile is the layout of a scan checkpoint file.
+type checkpointFile struct {
+	FormatVersion int               `json:"checkpoint_format_version"`
+	Started       time.Time         `json:"started"`
+	Updated       time.Time         `json:"updated"`
+	Completed     []string          `json:"completed"`
+	Chains        []checkpointChain `json:"chains"`
+}
+
+// scanCheckpoint tracks the scan targets (IP Address and port) completed
+// during a scan along with the certificate chains discovered so that an
+// interrupted scan can be resumed by a later run. Methods are safe for
+// concurrent use and for use with a nil scanCheckpoint (no-op).
+type scanCheckpoint struct {
+	mu        sync.Mutex
+	filename  string
+	started   time.Time
+	lastSaved time.Time
+	completed map[string]struct{}
+	chains    []checkpointChain
+	log       zerolog.Logger
+}
+
+// checkpointKey returns the key used to record the given scan target.
+func checkpointKey(ipAddr string, port int) string {
+	return net.JoinHostPort(ipAddr, strconv.Itoa(port))
+}
+
+// loadScanCheckpoint loads the scan checkpoint recorded in the specified
+// file by an earlier (interrupted) scan. An empty scan checkpoint is
+// returned if the file does not exist.
+func loadScanCheckpoint(filename string, log zerolog.Logger) (*scanCheckpoint, error) {
+	cp := scanCheckpoint{
+		filename:  filename,
+		started:   time.Now().UTC(),
+		lastSaved: time.Now(),
+		completed: make(map[string]struct{}),
+		log:       log,
+	}
+
+	data, err := os.ReadFile(filepath.Clean(filename))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return &cp, nil
+
+	case err != nil:
+		return nil, fmt.Errorf(
+			"failed to read scan checkpoint file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	var recorded checkpointFile
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf(
+			"%w: failed to decode %q: %w",
+			ErrInvalidCheckpoint,
+			filename,
+			err,
+		)
+	}
+
+	if recorded.FormatVersion != checkpointFormatVersion {
+		return nil, fmt.Errorf(
+			"%w: unsupported checkpoint format version %d (supported: %d)",
+			ErrInvalidCheckpoint,
+			recorded.FormatVersion,
+			checkpointFormatVersion,
+		)
+	}
+
+	cp.started = recorded.Started
+	cp.chains = recorded.Chains
+	for _, key := range recorded.Completed {
+		cp.completed[key] = struct{}{}
+	}
+
+	return &cp, nil
+}
+
+// Resumed indicates whether scan targets were completed by an earlier run.
+func (cp *scanCheckpoint) Resumed() bool {
+	if cp == nil {
+		return false
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return len(cp.completed) > 0
+}
+
+// NumCompleted returns the number of completed scan targets.
+func (cp *scanCheckpoint) NumCompleted() int {
+	if cp == nil {
+		return 0
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return len(cp.completed)
+}
+
+// IsCompleted indicates whether the scan target at the given IP Address and
+// port has been completed.
+func (cp *scanCheckpoint) IsCompleted(ipAddr string, port int) bool {
+	if cp == nil {
+		return false
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	_, ok := cp.completed[checkpointKey(ipAddr, port)]
+
+	return ok
+}
+
+// Complete records the scan target at the given IP Address and port as
+// completed along with the certificate chain discovered there (if any). The
+// checkpoint file is updated if the checkpoint interval has elapsed since
+// it was last updated.
+func (cp *scanCheckpoint) Complete(ipAddr string, port int, discovered *certs.DiscoveredCertChain) {
+	if cp == nil {
+		return
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	cp.completed[checkpointKey(ipAddr, port)] = struct{}{}
+
+	if discovered != nil {
+		var pemData bytes.Buffer
+		for _, cert := range discovered.Certs {
+			// Encoding to an in-memory buffer does not fail for
+			// certificates retrieved from a server.
+			_ = pem.Encode(&pemData, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		}
+
+		cp.chains = append(cp.chains, checkpointChain{
+			Host:          discovered.Name,
+			IPAddress:     discovered.IPAddress,
+			Port:          discovered.Port,
+			CorrelationID: discovered.CorrelationID,
+			VerifiedNames: discovered.VerifiedNames(),
+			PEM:           pemData.String(),
+		})
+	}
+
+	if time.Since(cp.lastSaved) < checkpointInterval {
+		return
+	}
+
+	if err := cp.save(); err != nil {
+		cp.log.Error().Err(err).Msg("Failed to update scan checkpoint file")
+	}
+}
+
+// Save writes the scan checkpoint to the checkpoint file.
+func (cp *scanCheckpoint) Save() error {
+	if cp == nil {
+		return nil
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	return cp.save()
+}
+
+// save writes the scan checkpoint to the checkpoint file. The file is
+// replaced as a whole so that an interrupted write does not leave a
+// partially written file behind. The caller is expected to hold the lock.
+func (cp *scanCheckpoint) save() error {
+	recorded := checkpointFile{
+		FormatVersion: checkpointFormatVersion,
+		Started:       cp.started,
+		Updated:       time.Now().UTC(),
+		Completed:     make([]string, 0, len(cp.completed)),
+		Chains:        cp.chains,
+	}
+
+	for key := range cp.completed {
+		recorded.Completed = append(recorded.Completed, key)
+	}
+	sort.Strings(recorded.Completed)
+
+	data, err := json.Marshal(recorded)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to encode scan checkpoint for file %q: %w",
+			cp.filename,
+			err,
+		)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(cp.filename), filepath.Base(cp.filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf(
+			"failed to create temporary file for scan checkpoint file %q: %w",
+			cp.filename,
+			err,
+		)
+	}
+
+	// Remove the temporary file if it is not successfully renamed.
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+
+		return fmt.Errorf(
+			"failed to write scan checkpoint file %q: %w",
+			cp.filename,
+			err,
+		)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf(
+			"failed to close scan checkpoint file %q: %w",
+			cp.filename,
+			err,
+		)
+	}
+
+	if err := os.Rename(tmpFile.Name(), cp.filename); err != nil {
+		return fmt.Errorf(
+			"failed to replace scan checkpoint file %q: %w",
+			cp.filename,
+			err,
+		)
+	}
+
+	cp.lastSaved = time.Now()
+
+	return nil
+}
+
+// Remove removes the checkpoint file once a scan has completed so that the
+// next run performs a full scan.
+func (cp *scanCheckpoint) Remove() error {
+	if cp == nil {
+		return nil
+	}
+
+	if err := os.Remove(cp.filename); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf(
+			"failed to remove scan checkpoint file %q: %w",
+			cp.filename,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// DiscoveredCertChains returns the certificate chains discovered by earlier
+// runs. Each certificate chain is evaluated using the given CRITICAL and
+// WARNING thresholds (specified in number of days from this moment) and
+// validation options with hostname verification performed against the
+// names verified when the certificate chain was discovered.
+func (cp *scanCheckpoint) DiscoveredCertChains(
+	ageCritical int,
+	ageWarning int,
+	validationOptions certs.CertChainValidationOptions,
+) (certs.DiscoveredCertChains, error) {
+	if cp == nil {
+		return nil, nil
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	discoveredChains := make(certs.DiscoveredCertChains, 0, len(cp.chains))
+	for _, chain := range cp.chains {
+		certChain, _, err := certs.ParsePEMCertificates([]byte(chain.PEM))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: failed to parse certificate chain for %s:%d: %w",
+				ErrInvalidCheckpoint,
+				chain.IPAddress,
+				chain.Port,
+				err,
+			)
+		}
+
+		discoveredChains = append(discoveredChains, certs.DiscoveredCertChain{
+			Name:          chain.Host,
+			IPAddress:     chain.IPAddress,
+			Port:          chain.Port,
+			Certs:         certChain,
+			CorrelationID: chain.CorrelationID,
+			ValidationResults: validateCertChain(
+				certChain,
+				chain.VerifiedNames,
+				ageCritical,
+				ageWarning,
+				validationOptions,
+			),
+		})
+	}
+
+	return discoveredChains, nil
+}
+
+// pendingScanTargets returns the given host patterns with the scan targets
+// (IP Address and port) already completed according to the given scan
+// checkpoint removed. IP Addresses with only some ports completed are split
+// into a separate host pattern listing the remaining ports; IP Addresses
+// with all ports completed are removed. The given host patterns are not
+// modified.
+func pendingScanTargets(hosts []netutils.HostPattern, ports []int, cp *scanCheckpoint) []netutils.HostPattern {
+	if !cp.Resumed() {
+		return hosts
+	}
+
+	pending := make([]netutils.HostPattern, 0, len(hosts))
+	for _, host := range hosts {
+		hostPorts := ports
+		if len(host.Ports) > 0 {
+			hostPorts = host.Ports
+		}
+
+		var fullyPending []string
+		var partial []netutils.HostPattern
+		for _, ipAddr := range host.Expanded {
+			var remaining []int
+			for _, port := range hostPorts {
+				if !cp.IsCompleted(ipAddr, port) {
+					remaining = append(remaining, port)
+				}
+			}
+
+			switch {
+			case len(remaining) == len(hostPorts):
+				fullyPending = append(fullyPending, ipAddr)
+
+			case len(remaining) > 0:
+				partialHost := host
+				partialHost.Expanded = []string{ipAddr}
+				partialHost.Ports = remaining
+				partial = append(partial, partialHost)
+			}
+		}
+
+		if len(fullyPending) > 0 {
+			pendingHost := host
+			pendingHost.Expanded = fullyPending
+			pending = append(pending, pendingHost)
+		}
+
+		pending = append(pending, partial...)
+	}
+
+	return pending
+}
+
+// saveCheckpointOnInterrupt records the given scan checkpoint to the
+// checkpoint file if the application is interrupted (e.g., via Ctrl+C)
+// before the given done channel is closed. The application exits once the
+// checkpoint file is updated.
+func saveCheckpointOnInterrupt(cp *scanCheckpoint, done <-chan struct{}, log zerolog.Logger) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	select {
+	case <-done:
+		return
+
+	case sig := <-sigChan:
+		if err := cp.Save(); err != nil {
+			log.Error().Err(err).Msg("Failed to update scan checkpoint file")
+			os.Exit(1)
+		}
+
+		log.Warn().
+			Str("signal", sig.String()).
+			Str("state_file", cp.filename).
+			Int("completed_targets", cp.NumCompleted()).
+			Msg("Scan interrupted; progress recorded to state file for resumption")
+
+		os.Exit(1)
+	}
+}
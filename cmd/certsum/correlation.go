@@ -0,0 +1,36 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync/atomic"
+)
+
+// correlationIDLength is the number of random bytes used to generate a
+// correlation ID.
+const correlationIDLength int = 8
+
+// correlationIDFallbackCounter provides unique correlation ID values if
+// random values are unavailable.
+var correlationIDFallbackCounter atomic.Uint64
+
+// newCorrelationID generates a short identifier used to associate log
+// events and result records with a specific scan target (IP Address and
+// port). This allows interleaved log events emitted by many concurrent
+// goroutines to be reconstructed per target.
+func newCorrelationID() string {
+	b := make([]byte, correlationIDLength)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("seq-%d", correlationIDFallbackCounter.Add(1))
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+)
+
+// writeSummaryCSV writes the discovered certificate chains to the specified
+// file in CSV format. Each certificate in a discovered chain is written as a
+// separate row. The file is overwritten if it already exists.
+func writeSummaryCSV(
+	filename string,
+	discoveredChains certs.DiscoveredCertChains,
+	ageCritical int,
+	ageWarning int,
+) (err error) {
+
+	now := time.Now().UTC()
+	certsExpireAgeWarning := now.AddDate(0, 0, ageWarning)
+	certsExpireAgeCritical := now.AddDate(0, 0, ageCritical)
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create output file %q: %w", filename, err)
+	}
+
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close output file %q: %w", filename, closeErr)
+		}
+	}()
+
+	w := csv.NewWriter(f)
+
+	header := []string{
+		"host",
+		"ip",
+		"port",
+		"subject",
+		"issuer",
+		"serial",
+		"not_after",
+		"days_remaining",
+		"status",
+		"correlation_id",
+		"not_after_epoch",
+	}
+
+	if err := w.Write(header); err != nil {
+		return fmt.Errorf("failed to write header to output file %q: %w", filename, err)
+	}
+
+	for _, certChain := range discoveredChains {
+		for _, cert := range certChain.Certs {
+			// An error is only returned for a nil certificate. Expired
+			// certificates report a negative number of days.
+			daysRemaining, _ := certs.ExpiresInDays(cert)
+
+			record := []string{
+				certChain.Name,
+				certChain.IPAddress,
+				strconv.Itoa(certChain.Port),
+				cert.Subject.String(),
+				cert.Issuer.String(),
+				certs.FormatCertSerialNumber(cert.SerialNumber),
+				cert.NotAfter.UTC().Format(time.RFC3339),
+				strconv.Itoa(daysRemaining),
+				certStatus(cert, certsExpireAgeCritical, certsExpireAgeWarning, certChain.IsIgnoredCert(cert)),
+				certChain.CorrelationID,
+				strconv.FormatInt(cert.NotAfter.Unix(), 10),
+			}
+
+			if err := w.Write(record); err != nil {
+				return fmt.Errorf("failed to write record to output file %q: %w", filename, err)
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to flush output file %q: %w", filename, err)
+	}
+
+	return nil
+}
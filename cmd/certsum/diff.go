@@ -0,0 +1,272 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// ErrInvalidPreviousScan indicates that the results of a previous scan
+// could not be loaded.
+var ErrInvalidPreviousScan = errors.New("invalid previous scan results")
+
+// scanEndpointChange records the changes to the leaf certificate served at
+// an endpoint (IP Address and port) since a previous scan.
+type scanEndpointChange struct {
+	previous scanResultChain
+	current  scanResultChain
+	changes  []string
+}
+
+// scanDiff records the differences between the results of a previous scan
+// and the current scan.
+type scanDiff struct {
+	// added is the collection of endpoints discovered by the current scan
+	// which were not found by the previous scan.
+	added []scanResultChain
+
+	// removed is the collection of endpoints found by the previous scan
+	// which were not discovered by the current scan.
+	removed []scanResultChain
+
+	// changed is the collection of endpoints where the leaf certificate has
+	// changed since the previous scan.
+	changed []scanEndpointChange
+}
+
+// IsEmpty indicates whether no differences were found.
+func (d scanDiff) IsEmpty() bool {
+	return len(d.added) == 0 && len(d.removed) == 0 && len(d.changed) == 0
+}
+
+// endpointKey returns the key used to match the certificate chain found at
+// an endpoint between scans.
+func endpointKey(chain scanResultChain) string {
+	return net.JoinHostPort(chain.IPAddress, strconv.Itoa(chain.Port))
+}
+
+// loadPreviousScanResults loads the certificate chains recorded in the
+// specified file by a previous scan using the json (single document) or
+// ndjson (one document per certificate chain) output formats.
+func loadPreviousScanResults(filename string) ([]scanResultChain, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read previous scan results file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	var results scanResults
+	if err := json.Unmarshal(data, &results); err == nil && results.Chains != nil {
+		return results.Chains, nil
+	}
+
+	var chains []scanResultChain
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var chain scanResultChain
+		err := dec.Decode(&chain)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil || chain.IPAddress == "" {
+			return nil, fmt.Errorf(
+				"%w: %q is not in json or ndjson output format",
+				ErrInvalidPreviousScan,
+				filename,
+			)
+		}
+
+		chains = append(chains, chain)
+	}
+
+	return chains, nil
+}
+
+// leafCertChanges returns a description of each change to the leaf
+// certificate (the first certificate in the chain) between the given
+// certificate chains found at the same endpoint.
+func leafCertChanges(previous scanResultChain, current scanResultChain) []string {
+	switch {
+	case len(previous.Certs) == 0 && len(current.Certs) == 0:
+		return nil
+
+	case len(previous.Certs) == 0 || len(current.Certs) == 0:
+		return []string{fmt.Sprintf(
+			"certificates changed: %d -> %d",
+			len(previous.Certs),
+			len(current.Certs),
+		)}
+	}
+
+	prevLeaf := previous.Certs[0]
+	curLeaf := current.Certs[0]
+
+	var changes []string
+
+	if prevLeaf.FingerprintSHA256 != curLeaf.FingerprintSHA256 {
+		changes = append(changes, fmt.Sprintf(
+			"fingerprint changed: %s -> %s",
+			prevLeaf.FingerprintSHA256,
+			curLeaf.FingerprintSHA256,
+		))
+	}
+
+	if prevLeaf.Issuer != curLeaf.Issuer {
+		changes = append(changes, fmt.Sprintf(
+			"issuer changed: %q -> %q",
+			prevLeaf.Issuer,
+			curLeaf.Issuer,
+		))
+	}
+
+	if !prevLeaf.NotAfter.Equal(curLeaf.NotAfter) {
+		changes = append(changes, fmt.Sprintf(
+			"expiration changed: %s -> %s",
+			prevLeaf.NotAfter.UTC().Format(time.RFC3339),
+			curLeaf.NotAfter.UTC().Format(time.RFC3339),
+		))
+	}
+
+	return changes
+}
+
+// compareScanResults compares the certificate chains found by a previous
+// scan against those found by the current scan. Certificate chains are
+// matched by endpoint (IP Address and port).
+func compareScanResults(previous []scanResultChain, current []scanResultChain) scanDiff {
+	previousByEndpoint := make(map[string]scanResultChain, len(previous))
+	for _, chain := range previous {
+		previousByEndpoint[endpointKey(chain)] = chain
+	}
+
+	currentByEndpoint := make(map[string]scanResultChain, len(current))
+	for _, chain := range current {
+		currentByEndpoint[endpointKey(chain)] = chain
+	}
+
+	var diff scanDiff
+
+	for _, chain := range current {
+		prevChain, ok := previousByEndpoint[endpointKey(chain)]
+		if !ok {
+			diff.added = append(diff.added, chain)
+
+			continue
+		}
+
+		if changes := leafCertChanges(prevChain, chain); len(changes) > 0 {
+			diff.changed = append(diff.changed, scanEndpointChange{
+				previous: prevChain,
+				current:  chain,
+				changes:  changes,
+			})
+		}
+	}
+
+	for _, chain := range previous {
+		if _, ok := currentByEndpoint[endpointKey(chain)]; !ok {
+			diff.removed = append(diff.removed, chain)
+		}
+	}
+
+	sortChains := func(chains []scanResultChain) {
+		sort.SliceStable(chains, func(i, j int) bool {
+			return endpointKey(chains[i]) < endpointKey(chains[j])
+		})
+	}
+	sortChains(diff.added)
+	sortChains(diff.removed)
+	sort.SliceStable(diff.changed, func(i, j int) bool {
+		return endpointKey(diff.changed[i].current) < endpointKey(diff.changed[j].current)
+	})
+
+	return diff
+}
+
+// leafCertSummary returns the common name and expiration date of the leaf
+// certificate in the given certificate chain.
+func leafCertSummary(chain scanResultChain) string {
+	if len(chain.Certs) == 0 {
+		return "(no certificates)"
+	}
+
+	return fmt.Sprintf(
+		"%s (expires %s)",
+		chain.Certs[0].CommonName,
+		chain.Certs[0].NotAfter.UTC().Format(time.RFC3339),
+	)
+}
+
+// printScanDiff emits the differences between the results of the specified
+// previous scan and the current scan.
+func printScanDiff(filename string, diff scanDiff) {
+	fmt.Printf("\nChanges since previous scan (%s):", filename)
+
+	if diff.IsEmpty() {
+		fmt.Printf(" none\n")
+
+		return
+	}
+
+	fmt.Printf(
+		" %d new, %d disappeared, %d changed\n\n",
+		len(diff.added),
+		len(diff.removed),
+		len(diff.changed),
+	)
+
+	tw := tabwriter.NewWriter(os.Stdout, 4, 8, 2, '\t', 0)
+
+	_, _ = fmt.Fprintf(tw, "Change\tHost\tEndpoint\tDetails\n")
+	_, _ = fmt.Fprintf(tw, "---\t---\t---\t---\n")
+
+	for _, chain := range diff.added {
+		_, _ = fmt.Fprintf(tw, "NEW\t%s\t%s\t%s\n", chain.Host, endpointKey(chain), leafCertSummary(chain))
+	}
+
+	for _, chain := range diff.removed {
+		_, _ = fmt.Fprintf(tw, "DISAPPEARED\t%s\t%s\t%s\n", chain.Host, endpointKey(chain), leafCertSummary(chain))
+	}
+
+	for _, change := range diff.changed {
+		for i, detail := range change.changes {
+			label := "CHANGED"
+			host := change.current.Host
+			endpoint := endpointKey(change.current)
+			if i > 0 {
+				label, host, endpoint = "", "", ""
+			}
+
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", label, host, endpoint, detail)
+		}
+	}
+
+	_, _ = fmt.Fprintln(tw)
+	if err := tw.Flush(); err != nil {
+		log.Printf(
+			"error occurred flushing tabwriter: %v",
+			err,
+		)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+)
+
+// InfluxDB line protocol measurement names used for scan results.
+const (
+	influxMeasurementChain string = "certsum_chain"
+	influxMeasurementCert  string = "certsum_cert"
+)
+
+// influxTagReplacer escapes characters with special meaning in InfluxDB line
+// protocol tag keys and values.
+var influxTagReplacer = strings.NewReplacer(
+	`,`, `\,`,
+	`=`, `\=`,
+	` `, `\ `,
+)
+
+// influxFieldStringReplacer escapes characters with special meaning in
+// InfluxDB line protocol string field values.
+var influxFieldStringReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+)
+
+// influxLine assembles a single InfluxDB line protocol record. Tags with
+// empty values are omitted as the line protocol does not permit them.
+func influxLine(measurement string, tags []string, fields []string, timestamp time.Time) string {
+	var sb strings.Builder
+
+	sb.WriteString(measurement)
+
+	for i := 0; i+1 < len(tags); i += 2 {
+		if tags[i+1] == "" {
+			continue
+		}
+
+		fmt.Fprintf(
+			&sb,
+			",%s=%s",
+			influxTagReplacer.Replace(tags[i]),
+			influxTagReplacer.Replace(tags[i+1]),
+		)
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(strings.Join(fields, ","))
+	sb.WriteString(" ")
+	sb.WriteString(strconv.FormatInt(timestamp.UnixNano(), 10))
+
+	return sb.String()
+}
+
+// influxIntField formats an integer field for use in an InfluxDB line
+// protocol record.
+func influxIntField(key string, value int64) string {
+	return fmt.Sprintf("%s=%di", key, value)
+}
+
+// influxStringField formats a string field for use in an InfluxDB line
+// protocol record.
+func influxStringField(key string, value string) string {
+	return fmt.Sprintf("%s=\"%s\"", key, influxFieldStringReplacer.Replace(value))
+}
+
+// printSummaryInflux emits the discovered certificate chains to stdout in
+// InfluxDB line protocol format. A summary record is emitted for each
+// certificate chain along with a record for each certificate in the chain.
+// All records share the same timestamp so that values from a single scan are
+// grouped together.
+func printSummaryInflux(
+	discoveredChains certs.DiscoveredCertChains,
+	ageCritical int,
+	ageWarning int,
+) error {
+
+	now := time.Now().UTC()
+	certsExpireAgeWarning := now.AddDate(0, 0, ageWarning)
+	certsExpireAgeCritical := now.AddDate(0, 0, ageCritical)
+
+	w := bufio.NewWriter(os.Stdout)
+
+	for _, certChain := range discoveredChains {
+		chain := newScanResultChain(certChain, certsExpireAgeCritical, certsExpireAgeWarning)
+
+		chainTags := []string{
+			"host", chain.Host,
+			"ip_address", chain.IPAddress,
+			"port", strconv.Itoa(chain.Port),
+		}
+
+		fmt.Fprintln(w, influxLine(
+			influxMeasurementChain,
+			chainTags,
+			[]string{
+				influxIntField("total_certs", int64(chain.TotalCerts)),
+				influxIntField("expired_certs", int64(chain.ExpiredCerts)),
+				influxIntField("expiring_certs", int64(chain.ExpiringCerts)),
+				influxIntField("hostname_mismatches", int64(len(chain.HostnameMismatches))),
+				influxIntField("problems", int64(chain.Problems)),
+			},
+			now,
+		))
+
+		for idx, cert := range chain.Certs {
+			certTags := append(
+				append([]string{}, chainTags...),
+				"index", strconv.Itoa(idx+1),
+				"chain_position", cert.ChainPosition.Keyword(),
+				"common_name", cert.CommonName,
+				"serial", cert.SerialNumber,
+			)
+
+			fmt.Fprintln(w, influxLine(
+				influxMeasurementCert,
+				certTags,
+				[]string{
+					influxIntField("days_remaining", int64(cert.DaysRemaining)),
+					influxIntField("not_before", cert.NotBefore.Unix()),
+					influxIntField("not_after", cert.NotAfter.Unix()),
+					influxStringField("status", cert.Status),
+				},
+				now,
+			))
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write scan results: %w", err)
+	}
+
+	return nil
+}
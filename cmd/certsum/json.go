@@ -0,0 +1,217 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// scanResultCert is the machine-readable representation of a certificate
+// found in a discovered certificate chain.
+type scanResultCert struct {
+	Subject           string                      `json:"subject"`
+	CommonName        string                      `json:"common_name"`
+	SANsEntries       []string                    `json:"sans_entries"`
+	Issuer            string                      `json:"issuer"`
+	SerialNumber      string                      `json:"serial"`
+	NotBefore         time.Time                   `json:"not_before"`
+	NotBeforeEpoch    int64                       `json:"not_before_epoch"`
+	NotBeforeHuman    string                      `json:"not_before_human"`
+	NotAfter          time.Time                   `json:"not_after"`
+	NotAfterEpoch     int64                       `json:"not_after_epoch"`
+	NotAfterHuman     string                      `json:"not_after_human"`
+	DaysRemaining     int                         `json:"days_remaining"`
+	ChainPosition     certs.CertChainPosition     `json:"chain_position"`
+	Signature         certs.SignatureVerification `json:"signature_verification"`
+	Status            string                      `json:"status"`
+	FingerprintSHA256 string                      `json:"fingerprint_sha256"`
+}
+
+// scanResultChain is the machine-readable representation of a certificate
+// chain discovered during a scan.
+type scanResultChain struct {
+	Host               string           `json:"host"`
+	IPAddress          string           `json:"ip_address"`
+	Port               int              `json:"port"`
+	CorrelationID      string           `json:"correlation_id"`
+	TotalCerts         int              `json:"total_certs"`
+	ExpiredCerts       int              `json:"expired_certs"`
+	ExpiringCerts      int              `json:"expiring_certs"`
+	IgnoredCerts       int              `json:"ignored_certs"`
+	HostnameMismatches []string         `json:"hostname_mismatches"`
+	State              string           `json:"state"`
+	FailedChecks       []string         `json:"failed_checks"`
+	Problems           int              `json:"problems"`
+	Certs              []scanResultCert `json:"certs"`
+}
+
+// scanResults is the machine-readable representation of all certificate
+// chains discovered during a scan.
+type scanResults struct {
+	TotalChains int               `json:"total_chains"`
+	Problems    int               `json:"problems"`
+	Chains      []scanResultChain `json:"chains"`
+}
+
+// newScanResultChain converts a discovered certificate chain into its
+// machine-readable representation.
+func newScanResultChain(
+	certChain certs.DiscoveredCertChain,
+	certsExpireAgeCritical time.Time,
+	certsExpireAgeWarning time.Time,
+) scanResultChain {
+
+	// Certificates that the sysadmin opted to ignore are not counted as
+	// expired or expiring.
+	evaluatedCerts := make([]*x509.Certificate, 0, len(certChain.Certs))
+	for _, cert := range certChain.Certs {
+		if !certChain.IsIgnoredCert(cert) {
+			evaluatedCerts = append(evaluatedCerts, cert)
+		}
+	}
+
+	numExpired := certs.NumExpiredCerts(evaluatedCerts)
+	numExpiring := certs.NumExpiringCerts(
+		evaluatedCerts,
+		certsExpireAgeCritical,
+		certsExpireAgeWarning,
+	)
+
+	hostnameMismatches := certChain.HostnameMismatches()
+
+	// Hostname verification is recorded once per resolved name so the
+	// same check name may be listed multiple times.
+	failedChecks := textutils.DedupeList(certChain.ValidationResults.NotOKCheckNames())
+	sort.Strings(failedChecks)
+
+	result := scanResultChain{
+		Host:               certChain.Name,
+		IPAddress:          certChain.IPAddress,
+		Port:               certChain.Port,
+		CorrelationID:      certChain.CorrelationID,
+		TotalCerts:         len(certChain.Certs),
+		ExpiredCerts:       numExpired,
+		ExpiringCerts:      numExpiring,
+		IgnoredCerts:       len(certChain.Certs) - len(evaluatedCerts),
+		HostnameMismatches: hostnameMismatches,
+		State:              certChain.ValidationResults.ServiceState().Label,
+		FailedChecks:       failedChecks,
+		Problems:           numExpired + numExpiring + len(hostnameMismatches),
+		Certs:              make([]scanResultCert, 0, len(certChain.Certs)),
+	}
+
+	for _, cert := range certChain.Certs {
+		// An error is only returned for a nil certificate. Expired
+		// certificates report a negative number of days.
+		daysRemaining, _ := certs.ExpiresInDays(cert)
+
+		sansEntries := cert.DNSNames
+		if sansEntries == nil {
+			sansEntries = []string{}
+		}
+
+		fingerprint := sha256.Sum256(cert.Raw)
+
+		result.Certs = append(result.Certs, scanResultCert{
+			Subject:           cert.Subject.String(),
+			CommonName:        cert.Subject.CommonName,
+			SANsEntries:       sansEntries,
+			Issuer:            cert.Issuer.String(),
+			SerialNumber:      certs.FormatCertSerialNumber(cert.SerialNumber),
+			NotBefore:         cert.NotBefore.UTC(),
+			NotBeforeEpoch:    cert.NotBefore.Unix(),
+			NotBeforeHuman:    cert.NotBefore.Format(certs.CertValidityDateLayout),
+			NotAfter:          cert.NotAfter.UTC(),
+			NotAfterEpoch:     cert.NotAfter.Unix(),
+			NotAfterHuman:     cert.NotAfter.Format(certs.CertValidityDateLayout),
+			DaysRemaining:     daysRemaining,
+			ChainPosition:     certs.ChainPosition(cert, certChain.Certs),
+			Signature:         certs.IssuerSignatureVerification(cert, certChain.Certs),
+			Status:            certStatus(cert, certsExpireAgeCritical, certsExpireAgeWarning, certChain.IsIgnoredCert(cert)),
+			FingerprintSHA256: textutils.BytesToDelimitedHexStr(fingerprint[:], ":"),
+		})
+	}
+
+	return result
+}
+
+// newScanResults converts the discovered certificate chains into their
+// machine-readable representation. Certificates are evaluated using the
+// given CRITICAL and WARNING thresholds (specified in number of days from
+// this moment).
+func newScanResults(
+	discoveredChains certs.DiscoveredCertChains,
+	ageCritical int,
+	ageWarning int,
+) scanResults {
+
+	now := time.Now().UTC()
+	certsExpireAgeWarning := now.AddDate(0, 0, ageWarning)
+	certsExpireAgeCritical := now.AddDate(0, 0, ageCritical)
+
+	chains := make([]scanResultChain, 0, len(discoveredChains))
+	for _, certChain := range discoveredChains {
+		chains = append(
+			chains,
+			newScanResultChain(certChain, certsExpireAgeCritical, certsExpireAgeWarning),
+		)
+	}
+
+	return scanResults{
+		TotalChains: len(chains),
+		Problems:    discoveredChains.NumProblems(certsExpireAgeCritical, certsExpireAgeWarning),
+		Chains:      chains,
+	}
+}
+
+// printSummaryJSON emits the discovered certificate chains to stdout in JSON
+// format. If specified, each certificate chain is emitted as a separate JSON
+// document on its own line (NDJSON) instead of a single JSON document.
+func printSummaryJSON(
+	discoveredChains certs.DiscoveredCertChains,
+	ageCritical int,
+	ageWarning int,
+	ndjson bool,
+) error {
+
+	results := newScanResults(discoveredChains, ageCritical, ageWarning)
+
+	enc := json.NewEncoder(os.Stdout)
+
+	if ndjson {
+		for _, chain := range results.Chains {
+			if err := enc.Encode(chain); err != nil {
+				return fmt.Errorf(
+					"failed to encode scan results for %s:%d: %w",
+					chain.IPAddress,
+					chain.Port,
+					err,
+				)
+			}
+		}
+
+		return nil
+	}
+
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(results); err != nil {
+		return fmt.Errorf("failed to encode scan results: %w", err)
+	}
+
+	return nil
+}
@@ -13,14 +13,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/rs/zerolog"
 	zlog "github.com/rs/zerolog/log"
 
 	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/config"
 	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/profiling"
+	"github.com/atc0005/check-cert/internal/stats"
 )
 
 func main() {
@@ -33,6 +38,11 @@ func main() {
 
 		return
 
+	case errors.Is(cfgErr, config.ErrSchemaRequested):
+		fmt.Print(scanResultsSchema)
+
+		return
+
 	case cfgErr != nil:
 		// We're using the standalone Err function from rs/zerolog/log as we
 		// do not have a working configuration.
@@ -49,7 +59,286 @@ func main() {
 		Str("app_timeout", fmt.Sprintf("%v", cfg.TimeoutAppInactivity())).
 		Logger()
 
-	expandedHostsList := cfg.Hosts()
+	stopProfiling, err := profiling.Start(cfg.ProfileCPU, cfg.ProfileMem)
+	if err != nil {
+		log.Error().Err(err).Msg("Error starting profiling")
+
+		return
+	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			log.Error().Err(err).Msg("Error writing profiles")
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	validationOptions := certs.CertChainValidationOptions{
+		IgnoredFingerprints: cfg.IgnoredFingerprints(),
+	}
+
+	// Load the results of the previous scan before scanning so that an
+	// unusable file is reported without waiting for the scan to complete.
+	var previousScan []scanResultChain
+	if cfg.CompareTo != "" {
+		previousScan, err = loadPreviousScanResults(cfg.CompareTo)
+		if err != nil {
+			log.Error().Err(err).Msg("Error loading previous scan results")
+
+			return
+		}
+	}
+
+	var discoveredCertChains certs.DiscoveredCertChains
+	var importedBundle *bundleMetadata
+	var excludedIPs []string
+
+	scanStart := time.Now()
+
+	// If requested, record run statistics for the scan once complete.
+	// Statistics are not recorded for imported scan results.
+	var scanErr error
+	defer func() {
+		if cfg.StatsFile == "" || importedBundle != nil {
+			return
+		}
+
+		record := newRunStatsRecord(discoveredCertChains, scanErr, time.Since(scanStart))
+		if err := stats.AppendFile(cfg.StatsFile, record); err != nil {
+			log.Error().Err(err).Msg("Failed to record run statistics")
+		}
+	}()
+
+	switch {
+	case cfg.ImportBundle != "":
+		chains, metadata, err := readBundle(
+			cfg.ImportBundle,
+			cfg.AgeCritical,
+			cfg.AgeWarning,
+			validationOptions,
+		)
+		if err != nil {
+			log.Error().Err(err).Msg("Error importing scan results bundle")
+
+			return
+		}
+
+		log.Debug().
+			Str("bundle_created", metadata.Created.String()).
+			Str("bundle_scan_host", metadata.ScanHost).
+			Int("bundle_chains", len(chains)).
+			Msg("Scan results bundle imported")
+
+		discoveredCertChains = chains
+		importedBundle = &metadata
+
+	default:
+		chains, excluded, err := scanCertChains(ctx, cancel, cfg, validationOptions, log)
+		if err != nil {
+			log.Error().Err(err).Msg("Error performing certificates scan")
+			scanErr = err
+
+			return
+		}
+
+		discoveredCertChains = chains
+		excludedIPs = excluded
+	}
+
+	log.Debug().Msgf("Discovered cert chains: %v", discoveredCertChains)
+
+	if cfg.OutputFile != "" {
+		if err := writeSummaryCSV(
+			cfg.OutputFile,
+			discoveredCertChains,
+			cfg.AgeCritical,
+			cfg.AgeWarning,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to write scan results to output file")
+		}
+	}
+
+	if cfg.ExportBundle != "" {
+		if err := writeBundle(
+			cfg.ExportBundle,
+			discoveredCertChains,
+			cfg.CertPorts(),
+			cfg.AgeCritical,
+			cfg.AgeWarning,
+		); err != nil {
+			log.Error().Err(err).Msg("Failed to write scan results bundle")
+		}
+	}
+
+	if cfg.WebhookURL != "" {
+		switch {
+		case ctx.Err() != nil:
+			// Endpoints not reached by an aborted scan would otherwise be
+			// indistinguishable from endpoints which were not found.
+			log.Error().Msg("Skipped sending scan results to webhook; scan incomplete")
+
+		default:
+			if err := sendScanResultsWebhook(
+				cfg.WebhookURL,
+				cfg.WebhookAuthHeaderName,
+				cfg.WebhookAuthHeaderProvider(),
+				newScanResults(discoveredCertChains, cfg.AgeCritical, cfg.AgeWarning),
+				cfg.Timeout(),
+			); err != nil {
+				log.Error().Err(err).Msg("Failed to send scan results to webhook")
+			}
+		}
+	}
+
+	if cfg.OutputFormat != config.OutputFormatText {
+		if ctx.Err() != nil {
+			log.Error().
+				Err(ctx.Err()).
+				Dur("scan_duration", time.Since(scanStart)).
+				Msg("Certificates scan aborted due to application timeout")
+		}
+
+		var err error
+		switch cfg.OutputFormat {
+		case config.OutputFormatInflux:
+			err = printSummaryInflux(
+				discoveredCertChains,
+				cfg.AgeCritical,
+				cfg.AgeWarning,
+			)
+		case config.OutputFormatMarkdown:
+			printSummaryMarkdown(
+				discoveredCertChains,
+				cfg.AgeCritical,
+				cfg.AgeWarning,
+			)
+		default:
+			err = printSummaryJSON(
+				discoveredCertChains,
+				cfg.AgeCritical,
+				cfg.AgeWarning,
+				cfg.OutputFormat == config.OutputFormatNDJSON,
+			)
+		}
+
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to emit scan results")
+		}
+
+		return
+	}
+
+	if !cfg.ShowPortScanResults {
+		// will need to insert a newline before showing cert summary
+		// output if we did not include port summary results as we checked
+		// examined certs earlier
+		fmt.Println()
+	}
+
+	switch {
+
+	case importedBundle != nil:
+		fmt.Printf(
+			"Imported %d certificate chains from bundle created %v on %q\n",
+			len(discoveredCertChains),
+			importedBundle.Created.Format(time.RFC3339),
+			importedBundle.ScanHost,
+		)
+
+	case ctx.Err() != nil:
+		fmt.Printf(
+			"Certificates scan aborted after %v due to application timeout.\n",
+			time.Since(scanStart),
+		)
+	default:
+		fmt.Printf(
+			"Completed certificates scan in %v\n",
+			time.Since(scanStart),
+		)
+	}
+
+	if len(cfg.ExcludedHostPatterns()) > 0 && importedBundle == nil {
+		fmt.Printf(
+			"Excluded %d IPs from scan matching %d exclusion patterns: %s\n",
+			len(excludedIPs),
+			len(cfg.ExcludedHostPatterns()),
+			strings.Join(cfg.ExcludedHostPatterns(), ", "),
+		)
+	}
+
+	switch {
+	case cfg.ShowOverview:
+		printSummaryHighLevel(
+			cfg.ShowHostsWithValidCerts,
+			discoveredCertChains,
+			cfg.AgeCritical,
+			cfg.AgeWarning,
+		)
+
+	default:
+		printSummaryDetailedLevel(
+			cfg.ShowValidCerts,
+			discoveredCertChains,
+			cfg.AgeCritical,
+			cfg.AgeWarning,
+		)
+	}
+
+	printExpiryCliffs(
+		discoveredCertChains,
+		cfg.ExpiryCliffWindow(),
+		cfg.ExpiryCliffLeadTime(),
+	)
+
+	if cfg.CompareTo != "" {
+		switch {
+		case ctx.Err() != nil:
+			// Endpoints not reached by an aborted scan would otherwise be
+			// reported as disappeared.
+			fmt.Printf("\nComparison with previous scan (%s) skipped; scan incomplete.\n", cfg.CompareTo)
+
+		default:
+			current := newScanResults(discoveredCertChains, cfg.AgeCritical, cfg.AgeWarning)
+			printScanDiff(cfg.CompareTo, compareScanResults(previousScan, current.Chains))
+		}
+	}
+
+}
+
+// scanCertChains scans the user-specified hosts and ports for certificate
+// chains, evaluating each discovered certificate chain using the given
+// validation options. The scan is aborted early if the given context is
+// canceled (e.g., due to application inactivity). The IP Addresses removed
+// from the scan by the user-specified exclusions are also returned. If a
+// state file is specified, scan progress is recorded there and an earlier
+// incomplete scan is resumed. An error is returned if the hosts file or
+// state file cannot be loaded.
+func scanCertChains(
+	ctx context.Context,
+	cancel context.CancelFunc,
+	cfg *config.Config,
+	validationOptions certs.CertChainValidationOptions,
+	log zerolog.Logger,
+) (certs.DiscoveredCertChains, []string, error) {
+	expandedHostsList, err := cfg.Hosts()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error expanding hosts: %w", err)
+	}
+
+	if cfg.HostsFile != "" {
+		fileHosts, err := netutils.LoadHostsFile(cfg.HostsFile, cfg.Resolver(), cfg.DNSTimeout())
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading hosts file: %w", err)
+		}
+
+		log.Debug().
+			Int("hosts_file_entries", len(fileHosts)).
+			Msg("Hosts file loaded")
+
+		expandedHostsList = append(expandedHostsList, fileHosts...)
+	}
+
 	log.Debug().Msgf("Host values before deduping: %v", expandedHostsList)
 	log.Debug().Msgf("Total host values before deduping: %d", len(expandedHostsList))
 
@@ -57,8 +346,67 @@ func main() {
 	log.Debug().Msgf("Total host values after deduping: %d", len(expandedHostsList))
 	log.Debug().Msgf("Host values after deduping: %v", expandedHostsList)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	// Exclusions are applied after deduping so that an IP Address is
+	// removed regardless of how many host patterns it was expanded from.
+	excludedHosts, err := cfg.ExcludedHosts()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error expanding excluded hosts: %w", err)
+	}
+
+	expandedHostsList, excludedIPs := netutils.ExcludeHosts(expandedHostsList, excludedHosts)
+	if len(excludedHosts) > 0 {
+		log.Debug().
+			Int("excluded_ips", len(excludedIPs)).
+			Strs("excluded", excludedIPs).
+			Msg("Host exclusions applied")
+	}
+
+	for _, host := range expandedHostsList {
+		if host.Truncated {
+			log.Warn().
+				Str("host_pattern", host.Given).
+				Int("hosts_scanned", len(host.Expanded)).
+				Msg("IPv6 range too large to scan in full; only the first addresses are scanned")
+		}
+	}
+
+	// Discovered certificate chains are verified against each hostname or
+	// FQDN which resolved to the IP Address where the chain was found.
+	resolvedNames := netutils.ResolvedNamesIndex(expandedHostsList)
+
+	// If requested, resume an earlier interrupted scan by skipping the scan
+	// targets it completed and reporting the certificate chains it found.
+	var checkpoint *scanCheckpoint
+	var previousCertChains certs.DiscoveredCertChains
+	if cfg.StateFile != "" {
+		checkpoint, err = loadScanCheckpoint(cfg.StateFile, log)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading scan state file: %w", err)
+		}
+
+		previousCertChains, err = checkpoint.DiscoveredCertChains(
+			cfg.AgeCritical,
+			cfg.AgeWarning,
+			validationOptions,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading scan state file: %w", err)
+		}
+
+		if checkpoint.Resumed() {
+			log.Debug().
+				Str("state_file", cfg.StateFile).
+				Int("completed_targets", checkpoint.NumCompleted()).
+				Int("cert_chains", len(previousCertChains)).
+				Msg("Resuming scan from state file")
+
+			expandedHostsList = pendingScanTargets(expandedHostsList, cfg.CertPorts(), checkpoint)
+		}
+
+		scanDone := make(chan struct{})
+		defer close(scanDone)
+		go saveCheckpointOnInterrupt(checkpoint, scanDone, log)
+	}
 
 	heartBeatChan := make(chan struct{})
 	go heartBeatMonitor(ctx, cancel, heartBeatChan, cfg.TimeoutAppInactivity(), log)
@@ -67,13 +415,38 @@ func main() {
 	var certScanWG sync.WaitGroup
 	var collWG sync.WaitGroup
 
+	numScanTargets := countScanTargets(expandedHostsList, cfg.CertPorts())
+	scanRateLimit := cfg.ScanRateLimitFor(numScanTargets)
+
+	log.Debug().
+		Int("scan_targets", numScanTargets).
+		Int("scan_rate_limit", scanRateLimit).
+		Bool("scan_rate_limit_calculated", cfg.ScanRateLimit == 0).
+		Msg("Scan rate limit determined")
+
 	// limit the total number of concurrent port scans (user-specified with
-	// fallback default)
-	portScanRateLimiter := make(chan struct{}, cfg.ScanRateLimit)
+	// fallback to a calculated value)
+	portScanRateLimiter := make(chan struct{}, scanRateLimit)
 
 	// limit the total number of hosts concurrently processed independently
 	// from port scan limit (in an effort to avoid deadlocks)
-	hostRateLimiter := make(chan struct{}, cfg.ScanRateLimit)
+	hostRateLimiter := make(chan struct{}, scanRateLimit)
+
+	// space out connection attempts to the same IP Address (if requested)
+	// independently from the scan rate limit
+	throttle := newHostThrottle(cfg.HostDelay(), cfg.ScanJitter())
+
+	var numScanIPs int
+	for _, host := range expandedHostsList {
+		numScanIPs += len(host.Expanded)
+	}
+
+	// report scan progress on the requested interval or on demand (e.g.,
+	// via SIGUSR1) until the scan completes
+	progress := newScanProgress(numScanIPs, numScanTargets)
+	progressDone := make(chan struct{})
+	defer close(progressDone)
+	go reportScanProgress(os.Stderr, progress, cfg.ProgressInterval(), progressDone)
 
 	// results are collected and passed per port
 	portScanResultsChan := make(chan netutils.PortCheckResult)
@@ -82,8 +455,6 @@ func main() {
 
 	var discoveredCertChains certs.DiscoveredCertChains
 
-	scanStart := time.Now()
-
 	// Spin off cert check results collector, pass pointer to allow modifying
 	// collection of discovered cert chains directly.
 	collWG.Add(1)
@@ -106,10 +477,16 @@ func main() {
 		portScanResultsChan,
 		portScanRateLimiter,
 		hostRateLimiter,
+		throttle,
+		progress,
 		log,
 		&portScanWG,
 	)
 
+	// Progress output is only emitted alongside the human-readable summary
+	// so that machine-readable output can be piped elsewhere as-is.
+	showProgress := cfg.OutputFormat == config.OutputFormatText
+
 	certScanWG.Add(1)
 	log.Debug().Msg("Starting certScanner ...")
 	go certScanner(
@@ -118,25 +495,39 @@ func main() {
 		portScanResultsChan,
 		cfg.ShowHostsWithClosedPorts,
 		cfg.ShowPortScanResults,
+		showProgress,
 		cfg.Timeout(),
+		cfg.CertRetrievalOptions(),
+		resolvedNames,
+		cfg.AgeCritical,
+		cfg.AgeWarning,
+		validationOptions,
 		certScanResultsChan,
 		portScanRateLimiter,
+		throttle,
+		progress,
+		checkpoint,
 		log,
 		&certScanWG,
 	)
 
-	fmt.Printf(
-		"Beginning cert scan against %d IPs expanded from %d unique host patterns using ports: %v\n",
-		func() int {
-			var targetIPs int
-			for _, host := range expandedHostsList {
-				targetIPs += len(host.Expanded)
-			}
-			return targetIPs
-		}(),
-		len(expandedHostsList),
-		cfg.CertPorts(),
-	)
+	if showProgress && checkpoint.Resumed() {
+		fmt.Printf(
+			"Resuming cert scan from state file %q (%d targets previously completed, %d cert chains previously found)\n",
+			cfg.StateFile,
+			checkpoint.NumCompleted(),
+			len(previousCertChains),
+		)
+	}
+
+	if showProgress {
+		fmt.Printf(
+			"Beginning cert scan against %d IPs expanded from %d unique host patterns using ports: %v\n",
+			numScanIPs,
+			len(expandedHostsList),
+			cfg.CertPorts(),
+		)
+	}
 
 	log.Debug().Msg("wait for port scan attempts to complete")
 	portScanWG.Wait()
@@ -147,45 +538,23 @@ func main() {
 	log.Debug().Msg("wait for cert check results collection goroutine to finish")
 	collWG.Wait()
 
-	log.Debug().Msgf("Discovered cert chains: %v", discoveredCertChains)
-
-	if !cfg.ShowPortScanResults {
-		// will need to insert a newline before showing cert summary
-		// output if we did not include port summary results as we checked
-		// examined certs earlier
-		fmt.Println()
-	}
-
-	switch {
+	// Progress for an incomplete scan is recorded so that the scan can be
+	// resumed; the state file is no longer needed once the scan completes.
+	if checkpoint != nil {
+		switch {
+		case ctx.Err() != nil:
+			if err := checkpoint.Save(); err != nil {
+				log.Error().Err(err).Msg("Failed to update scan checkpoint file")
+			}
 
-	case ctx.Err() != nil:
-		fmt.Printf(
-			"Certificates scan aborted after %v due to application timeout.\n",
-			time.Since(scanStart),
-		)
-	default:
-		fmt.Printf(
-			"Completed certificates scan in %v\n",
-			time.Since(scanStart),
-		)
+		default:
+			if err := checkpoint.Remove(); err != nil {
+				log.Error().Err(err).Msg("Failed to remove scan checkpoint file")
+			}
+		}
 	}
 
-	switch {
-	case cfg.ShowOverview:
-		printSummaryHighLevel(
-			cfg.ShowHostsWithValidCerts,
-			discoveredCertChains,
-			cfg.AgeCritical,
-			cfg.AgeWarning,
-		)
-
-	default:
-		printSummaryDetailedLevel(
-			cfg.ShowValidCerts,
-			discoveredCertChains,
-			cfg.AgeCritical,
-			cfg.AgeWarning,
-		)
-	}
+	discoveredCertChains = append(previousCertChains, discoveredCertChains...)
 
+	return discoveredCertChains, excludedIPs, nil
 }
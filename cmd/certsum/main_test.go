@@ -8,12 +8,32 @@
 package main
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/secrets"
 )
 
 // TestAssertWorkingConfigValidation asserts that the validation for the most
@@ -76,3 +96,768 @@ func TestAssertWorkingConfigValidation(t *testing.T) {
 	}
 
 }
+
+// TestScanResultsMatchSchema asserts that the machine-readable scan results
+// conform to the embedded JSON Schema document.
+func TestScanResultsMatchSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(scanResultsSchema), &schema); err != nil {
+		t.Fatalf("Failed to decode embedded schema: %v", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 0, 10),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	ageCritical, ageWarning := 15, 30
+	certChain := []*x509.Certificate{cert}
+
+	discoveredChain := certs.DiscoveredCertChain{
+		Name:          "www.example.com",
+		IPAddress:     "192.0.2.1",
+		Port:          443,
+		Certs:         certChain,
+		CorrelationID: "0123456789abcdef",
+		ValidationResults: validateCertChain(
+			certChain,
+			[]string{"www.example.com", "example.org"},
+			ageCritical,
+			ageWarning,
+			certs.CertChainValidationOptions{},
+		),
+	}
+
+	chain := newScanResultChain(
+		discoveredChain,
+		now.AddDate(0, 0, ageCritical),
+		now.AddDate(0, 0, ageWarning),
+	)
+
+	results := scanResults{
+		TotalChains: 1,
+		Problems:    1,
+		Chains:      []scanResultChain{chain},
+	}
+
+	tests := map[string]struct {
+		value  interface{}
+		schema string
+	}{
+		"json":   {value: results, schema: "#"},
+		"ndjson": {value: chain, schema: "#/$defs/chain"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Failed to encode scan results: %v", err)
+			}
+
+			var doc interface{}
+			if err := json.Unmarshal(encoded, &doc); err != nil {
+				t.Fatalf("Failed to decode scan results: %v", err)
+			}
+
+			for _, err := range validateSchema(schema, resolveSchemaRef(schema, tt.schema), doc, "") {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// newTestDiscoveredChain returns a discovered certificate chain for the
+// given host with a self-signed certificate expiring at the given time.
+func newTestDiscoveredChain(t *testing.T, name string, ipAddr string, notAfter time.Time) certs.DiscoveredCertChain {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	certChain := []*x509.Certificate{cert}
+
+	return certs.DiscoveredCertChain{
+		Name:      name,
+		IPAddress: ipAddr,
+		Port:      443,
+		Certs:     certChain,
+		ValidationResults: validateCertChain(
+			certChain,
+			[]string{name},
+			15,
+			30,
+			certs.CertChainValidationOptions{},
+		),
+	}
+}
+
+// TestPrintSummaryJSON asserts that the json output format emits a single
+// document covering all certificate chains and that the ndjson output
+// format emits one document per certificate chain.
+func TestPrintSummaryJSON(t *testing.T) {
+	now := time.Now()
+	discoveredChains := certs.DiscoveredCertChains{
+		newTestDiscoveredChain(t, "www.example.com", "192.0.2.1", now.AddDate(0, 0, 90)),
+		newTestDiscoveredChain(t, "mail.example.com", "192.0.2.2", now.AddDate(0, 0, 10)),
+	}
+
+	capture := func(t *testing.T, ndjson bool) []byte {
+		t.Helper()
+
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatalf("Failed to create pipe: %v", err)
+		}
+
+		oldStdout := os.Stdout
+		os.Stdout = w
+		printErr := printSummaryJSON(discoveredChains, 15, 30, ndjson)
+		os.Stdout = oldStdout
+		_ = w.Close()
+
+		if printErr != nil {
+			t.Fatalf("Failed to print scan results: %v", printErr)
+		}
+
+		output, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Failed to read scan results: %v", err)
+		}
+
+		return output
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var results scanResults
+		if err := json.Unmarshal(capture(t, false), &results); err != nil {
+			t.Fatalf("Failed to decode scan results: %v", err)
+		}
+
+		if results.TotalChains != 2 || len(results.Chains) != 2 {
+			t.Fatalf("want 2 certificate chains; got %d (%d listed)", results.TotalChains, len(results.Chains))
+		}
+
+		if results.Problems != 1 {
+			t.Errorf("want 1 problem; got %d", results.Problems)
+		}
+
+		for _, chain := range results.Chains {
+			wantExpiring := 0
+			if chain.Host == "mail.example.com" {
+				wantExpiring = 1
+			}
+
+			if chain.ExpiringCerts != wantExpiring {
+				t.Errorf("%s: want %d expiring certs; got %d", chain.Host, wantExpiring, chain.ExpiringCerts)
+			}
+
+			if len(chain.Certs) != 1 || chain.Certs[0].CommonName != chain.Host {
+				t.Errorf("%s: unexpected certificate details: %+v", chain.Host, chain.Certs)
+			}
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		lines := strings.Split(strings.TrimSpace(string(capture(t, true))), "\n")
+		if len(lines) != len(discoveredChains) {
+			t.Fatalf("want %d lines; got %d", len(discoveredChains), len(lines))
+		}
+
+		for i, line := range lines {
+			var chain scanResultChain
+			if err := json.Unmarshal([]byte(line), &chain); err != nil {
+				t.Fatalf("Failed to decode line %d: %v", i+1, err)
+			}
+
+			if chain.Host != discoveredChains[i].Name || chain.IPAddress != discoveredChains[i].IPAddress {
+				t.Errorf("line %d: want %s/%s; got %s/%s", i+1,
+					discoveredChains[i].Name, discoveredChains[i].IPAddress, chain.Host, chain.IPAddress)
+			}
+		}
+	})
+}
+
+// TestBundleRoundTrip asserts that certificate chains exported to a scan
+// results bundle are imported with the same discovery details and
+// validation check results.
+func TestBundleRoundTrip(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 0, 90),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	ageCritical, ageWarning := 15, 30
+	certChain := []*x509.Certificate{cert}
+	verifiedNames := []string{"www.example.com", "mail.example.com"}
+
+	exported := certs.DiscoveredCertChains{
+		{
+			Name:          "www.example.com",
+			IPAddress:     "2001:db8::1",
+			Port:          8443,
+			Certs:         certChain,
+			CorrelationID: "0123456789abcdef",
+			ValidationResults: validateCertChain(
+				certChain,
+				verifiedNames,
+				ageCritical,
+				ageWarning,
+				certs.CertChainValidationOptions{},
+			),
+		},
+	}
+
+	bundleFile := filepath.Join(t.TempDir(), "scan.tar.gz")
+	if err := writeBundle(bundleFile, exported, []int{8443}, ageCritical, ageWarning); err != nil {
+		t.Fatalf("Failed to write bundle: %v", err)
+	}
+
+	imported, metadata, err := readBundle(bundleFile, ageCritical, ageWarning, certs.CertChainValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %v", err)
+	}
+
+	if metadata.FormatVersion != bundleFormatVersion || len(metadata.Ports) != 1 || metadata.Ports[0] != 8443 {
+		t.Errorf("Unexpected bundle metadata: %+v", metadata)
+	}
+
+	if len(imported) != 1 {
+		t.Fatalf("want 1 imported certificate chain; got %d", len(imported))
+	}
+
+	got := imported[0]
+	want := exported[0]
+	switch {
+	case got.Name != want.Name, got.IPAddress != want.IPAddress, got.Port != want.Port, got.CorrelationID != want.CorrelationID:
+		t.Errorf("want discovery details %s/%s:%d (%s); got %s/%s:%d (%s)",
+			want.Name, want.IPAddress, want.Port, want.CorrelationID,
+			got.Name, got.IPAddress, got.Port, got.CorrelationID)
+
+	case len(got.Certs) != 1 || !got.Certs[0].Equal(cert):
+		t.Errorf("imported certificate chain does not match exported certificate chain")
+	}
+
+	if gotMismatches := got.HostnameMismatches(); len(gotMismatches) != 1 || gotMismatches[0] != "mail.example.com" {
+		t.Errorf("want hostname mismatches [mail.example.com]; got %v", gotMismatches)
+	}
+
+	if _, _, err := readBundle(filepath.Join(t.TempDir(), "missing.tar.gz"), ageCritical, ageWarning, certs.CertChainValidationOptions{}); err == nil {
+		t.Error("want error reading missing bundle; got nil")
+	}
+}
+
+func TestScanCheckpointResume(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	now := time.Now()
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.AddDate(0, 0, 90),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	stateFile := filepath.Join(t.TempDir(), "scan-state.json")
+
+	cp, err := loadScanCheckpoint(stateFile, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("Failed to load missing state file: %v", err)
+	}
+
+	if cp.Resumed() {
+		t.Fatal("want new scan for missing state file; got resumed scan")
+	}
+
+	cp.Complete("192.168.2.1", 443, nil)
+	cp.Complete("192.168.2.1", 8443, nil)
+	cp.Complete("192.168.2.2", 443, &certs.DiscoveredCertChain{
+		Name:          "www.example.com",
+		IPAddress:     "192.168.2.2",
+		Port:          443,
+		Certs:         []*x509.Certificate{cert},
+		CorrelationID: "0123456789abcdef",
+	})
+
+	if err := cp.Save(); err != nil {
+		t.Fatalf("Failed to save state file: %v", err)
+	}
+
+	resumed, err := loadScanCheckpoint(stateFile, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("Failed to load state file: %v", err)
+	}
+
+	if !resumed.Resumed() || resumed.NumCompleted() != 3 {
+		t.Fatalf("want 3 completed targets; got %d", resumed.NumCompleted())
+	}
+
+	hosts := []netutils.HostPattern{
+		{Given: "192.168.2.0/30", Expanded: []string{"192.168.2.1", "192.168.2.2", "192.168.2.3"}, Range: true},
+	}
+
+	pending := pendingScanTargets(hosts, []int{443, 8443}, resumed)
+
+	got := make([]string, 0, len(pending))
+	for _, host := range pending {
+		got = append(got, fmt.Sprintf("%v:%v", host.Expanded, host.Ports))
+	}
+
+	want := []string{"[192.168.2.3]:[]", "[192.168.2.2]:[8443]"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("want pending scan targets %v; got %v", want, got)
+	}
+
+	if len(hosts[0].Expanded) != 3 {
+		t.Errorf("want given host patterns unmodified; got %d IP Addresses", len(hosts[0].Expanded))
+	}
+
+	chains, err := resumed.DiscoveredCertChains(15, 30, certs.CertChainValidationOptions{})
+	switch {
+	case err != nil:
+		t.Fatalf("Failed to load discovered certificate chains: %v", err)
+
+	case len(chains) != 1:
+		t.Fatalf("want 1 discovered certificate chain; got %d", len(chains))
+
+	case chains[0].IPAddress != "192.168.2.2" || chains[0].Port != 443 || !chains[0].Certs[0].Equal(cert):
+		t.Errorf("discovered certificate chain does not match recorded certificate chain")
+	}
+
+	if err := resumed.Remove(); err != nil {
+		t.Fatalf("Failed to remove state file: %v", err)
+	}
+
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("want state file removed; got %v", err)
+	}
+}
+
+// resolveSchemaRef returns the schema definition referenced by the given
+// local JSON pointer (e.g., "#/$defs/chain").
+func resolveSchemaRef(root map[string]interface{}, ref string) map[string]interface{} {
+	def := root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if part == "" {
+			continue
+		}
+		def, _ = def[part].(map[string]interface{})
+	}
+
+	return def
+}
+
+// validateSchema applies the subset of JSON Schema keywords used by the
+// embedded schema document to the given decoded JSON value and returns any
+// violations found.
+func validateSchema(root map[string]interface{}, def map[string]interface{}, value interface{}, path string) []error {
+	if def == nil {
+		return []error{fmt.Errorf("%s: schema definition not found", path)}
+	}
+
+	if ref, ok := def["$ref"].(string); ok {
+		return validateSchema(root, resolveSchemaRef(root, ref), value, path)
+	}
+
+	var errs []error
+
+	if enum, ok := def["enum"].([]interface{}); ok {
+		var found bool
+		for _, item := range enum {
+			if item == value {
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, fmt.Errorf("%s: value %v not in %v", path, value, enum))
+		}
+	}
+
+	switch def["type"] {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf("%s: expected object, got %T", path, value))
+		}
+
+		props, _ := def["properties"].(map[string]interface{})
+
+		required, _ := def["required"].([]interface{})
+		for _, key := range required {
+			if _, ok := obj[key.(string)]; !ok {
+				errs = append(errs, fmt.Errorf("%s: missing required property %q", path, key))
+			}
+		}
+
+		for key, v := range obj {
+			propDef, ok := props[key].(map[string]interface{})
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unexpected property %q", path, key))
+
+				continue
+			}
+			errs = append(errs, validateSchema(root, propDef, v, path+"/"+key)...)
+		}
+
+		for key := range props {
+			var isRequired bool
+			for _, r := range required {
+				if r == key {
+					isRequired = true
+				}
+			}
+			if !isRequired {
+				errs = append(errs, fmt.Errorf("%s: property %q is not listed as required", path, key))
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return append(errs, fmt.Errorf("%s: expected array, got %T", path, value))
+		}
+
+		itemDef, _ := def["items"].(map[string]interface{})
+		for i, item := range items {
+			errs = append(errs, validateSchema(root, itemDef, item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return append(errs, fmt.Errorf("%s: expected string, got %T", path, value))
+		}
+
+		if def["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid date-time: %w", path, err))
+			}
+		}
+
+	case "integer":
+		num, ok := value.(float64)
+		if !ok || num != float64(int64(num)) {
+			return append(errs, fmt.Errorf("%s: expected integer, got %v", path, value))
+		}
+
+		if minimum, ok := def["minimum"].(float64); ok && num < minimum {
+			errs = append(errs, fmt.Errorf("%s: value %v below minimum %v", path, num, minimum))
+		}
+
+		if maximum, ok := def["maximum"].(float64); ok && num > maximum {
+			errs = append(errs, fmt.Errorf("%s: value %v above maximum %v", path, num, maximum))
+		}
+
+	default:
+		errs = append(errs, fmt.Errorf("%s: unsupported schema type %v", path, def["type"]))
+	}
+
+	return errs
+}
+
+func TestCompareScanResults(t *testing.T) {
+	expires := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	chain := func(ipAddr string, port int, fingerprint string, issuer string, notAfter time.Time) scanResultChain {
+		return scanResultChain{
+			IPAddress: ipAddr,
+			Port:      port,
+			Certs: []scanResultCert{
+				{
+					CommonName:        "www.example.com",
+					Issuer:            issuer,
+					NotAfter:          notAfter,
+					FingerprintSHA256: fingerprint,
+				},
+			},
+		}
+	}
+
+	previous := scanResults{
+		Chains: []scanResultChain{
+			chain("192.0.2.10", 443, "AA", "CN=Example CA", expires),
+			chain("192.0.2.11", 443, "BB", "CN=Example CA", expires),
+			chain("192.0.2.12", 443, "CC", "CN=Example CA", expires),
+		},
+	}
+
+	current := []scanResultChain{
+		chain("192.0.2.10", 443, "AA", "CN=Example CA", expires),
+		chain("192.0.2.12", 443, "DD", "CN=Other CA", expires.AddDate(0, 3, 0)),
+		chain("192.0.2.13", 8443, "EE", "CN=Example CA", expires),
+	}
+
+	// The previous scan results are loaded from both supported output
+	// formats.
+	dir := t.TempDir()
+
+	jsonFile := filepath.Join(dir, "previous.json")
+	data, err := json.Marshal(previous)
+	if err != nil {
+		t.Fatalf("Failed to encode previous scan results: %v", err)
+	}
+	if err := os.WriteFile(jsonFile, data, 0o600); err != nil {
+		t.Fatalf("Failed to write previous scan results: %v", err)
+	}
+
+	ndjsonFile := filepath.Join(dir, "previous.ndjson")
+	var ndjson strings.Builder
+	for _, c := range previous.Chains {
+		line, err := json.Marshal(c)
+		if err != nil {
+			t.Fatalf("Failed to encode previous scan results: %v", err)
+		}
+		ndjson.Write(line)
+		ndjson.WriteString("\n")
+	}
+	if err := os.WriteFile(ndjsonFile, []byte(ndjson.String()), 0o600); err != nil {
+		t.Fatalf("Failed to write previous scan results: %v", err)
+	}
+
+	for _, filename := range []string{jsonFile, ndjsonFile} {
+		loaded, err := loadPreviousScanResults(filename)
+		if err != nil {
+			t.Fatalf("Failed to load previous scan results from %s: %v", filename, err)
+		}
+
+		diff := compareScanResults(loaded, current)
+
+		switch {
+		case len(diff.added) != 1 || diff.added[0].IPAddress != "192.0.2.13":
+			t.Errorf("want new endpoint 192.0.2.13; got %+v", diff.added)
+		case len(diff.removed) != 1 || diff.removed[0].IPAddress != "192.0.2.11":
+			t.Errorf("want disappeared endpoint 192.0.2.11; got %+v", diff.removed)
+		case len(diff.changed) != 1 || len(diff.changed[0].changes) != 3:
+			t.Errorf("want fingerprint, issuer and expiration changes for 192.0.2.12; got %+v", diff.changed)
+		}
+	}
+
+	invalidFile := filepath.Join(dir, "previous.csv")
+	if err := os.WriteFile(invalidFile, []byte("host,ip_address,port\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write previous scan results: %v", err)
+	}
+
+	if _, err := loadPreviousScanResults(invalidFile); !errors.Is(err, ErrInvalidPreviousScan) {
+		t.Errorf("want error %v; got %v", ErrInvalidPreviousScan, err)
+	}
+}
+
+func TestHostThrottle(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Delay", func(t *testing.T) {
+		throttle := newHostThrottle(100*time.Millisecond, 0)
+
+		for i := 0; i < 3; i++ {
+			want := now.Add(time.Duration(i) * 100 * time.Millisecond)
+			if got := throttle.reserve("192.0.2.1", now); !got.Equal(want) {
+				t.Errorf("slot %d: want %v; got %v", i, want, got)
+			}
+		}
+
+		// Connection attempts to other IP Addresses are not delayed.
+		if got := throttle.reserve("192.0.2.2", now); !got.Equal(now) {
+			t.Errorf("want %v for other IP Address; got %v", now, got)
+		}
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		throttle := newHostThrottle(100*time.Millisecond, 50*time.Millisecond)
+
+		previous := throttle.reserve("192.0.2.1", now)
+		if previous.Before(now) || previous.After(now.Add(50*time.Millisecond)) {
+			t.Errorf("want first slot within jitter of %v; got %v", now, previous)
+		}
+
+		for i := 0; i < 10; i++ {
+			slot := throttle.reserve("192.0.2.1", now)
+			if gap := slot.Sub(previous); gap < 100*time.Millisecond || gap > 150*time.Millisecond {
+				t.Errorf("want gap between slots of 100ms to 150ms; got %v", gap)
+			}
+			previous = slot
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		throttle := newHostThrottle(0, 0)
+		if throttle != nil {
+			t.Fatal("want nil throttle when delay and jitter are not specified")
+		}
+
+		if err := throttle.Wait(context.Background(), "192.0.2.1"); err != nil {
+			t.Errorf("want no error; got %v", err)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		throttle := newHostThrottle(time.Hour, 0)
+		_ = throttle.reserve("192.0.2.1", time.Now())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := throttle.Wait(ctx, "192.0.2.1"); !errors.Is(err, context.Canceled) {
+			t.Errorf("want error %v; got %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestScanProgress(t *testing.T) {
+	progress := newScanProgress(2, 8)
+	progress.start = time.Now().Add(-10 * time.Second)
+
+	if _, ok := progress.ETA(time.Now()); ok {
+		t.Error("want no ETA before any ports are scanned")
+	}
+
+	for i := 0; i < 2; i++ {
+		progress.PortScanned()
+	}
+	progress.ChainFound()
+
+	// 2 of 8 ports scanned in 10 seconds leaves 6 ports at 5 seconds each.
+	eta, ok := progress.ETA(progress.start.Add(10 * time.Second))
+	if !ok || eta != 30*time.Second {
+		t.Errorf("want ETA of 30s; got %v (%t)", eta, ok)
+	}
+
+	want := "Progress: 0/2 hosts completed, 2/8 ports scanned, 1 cert chains found, elapsed 10s, ETA 30s"
+	if got := progress.String(); got != want {
+		t.Errorf("want %q; got %q", want, got)
+	}
+
+	// Progress is not tracked when a scan is not performed.
+	var disabled *scanProgress
+	disabled.PortScanned()
+	if _, ok := disabled.ETA(time.Now()); ok {
+		t.Error("want no ETA for nil progress")
+	}
+}
+
+func TestSendScanResultsWebhook(t *testing.T) {
+	results := scanResults{
+		TotalChains: 1,
+		Chains: []scanResultChain{
+			{Host: "www.example.com", IPAddress: "192.0.2.10", Port: 443},
+		},
+	}
+
+	var received scanResults
+	var authHeader string
+	status := http.StatusAccepted
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != http.MethodPost:
+			t.Errorf("want POST request; got %s", r.Method)
+		case r.Header.Get("Content-Type") != "application/json":
+			t.Errorf("want JSON content type; got %q", r.Header.Get("Content-Type"))
+		}
+
+		authHeader = r.Header.Get("ph-auth-token")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("Failed to decode webhook request: %v", err)
+		}
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("CERTSUM_WEBHOOK_AUTH", "abc123")
+	provider := secrets.EnvProvider{Name: "CERTSUM_WEBHOOK_AUTH"}
+
+	if err := sendScanResultsWebhook(server.URL, "ph-auth-token", provider, results, 5*time.Second); err != nil {
+		t.Fatalf("want no error; got %v", err)
+	}
+
+	if authHeader != "abc123" {
+		t.Errorf("want auth header value %q; got %q", "abc123", authHeader)
+	}
+
+	if received.TotalChains != 1 || len(received.Chains) != 1 || received.Chains[0].IPAddress != "192.0.2.10" {
+		t.Errorf("scan results not received as sent; got %+v", received)
+	}
+
+	// Failure responses are reported.
+	status = http.StatusUnauthorized
+	if err := sendScanResultsWebhook(server.URL, "ph-auth-token", nil, results, 5*time.Second); err == nil {
+		t.Errorf("want error for %d response; got nil", status)
+	}
+
+	// The request is not sent if the header value cannot be retrieved.
+	provider = secrets.EnvProvider{Name: "CERTSUM_WEBHOOK_AUTH_MISSING"}
+	if err := sendScanResultsWebhook(server.URL, "ph-auth-token", provider, results, 5*time.Second); !errors.Is(err, secrets.ErrSecretNotFound) {
+		t.Errorf("want error %v; got %v", secrets.ErrSecretNotFound, err)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// printSummaryMarkdown emits the discovered certificate chains to stdout as
+// Markdown tables suitable for pasting into wikis and chat. An overview table
+// lists each certificate chain followed by a table listing each certificate
+// in those chains.
+func printSummaryMarkdown(
+	discoveredChains certs.DiscoveredCertChains,
+	ageCritical int,
+	ageWarning int,
+) {
+
+	now := time.Now().UTC()
+	certsExpireAgeWarning := now.AddDate(0, 0, ageWarning)
+	certsExpireAgeCritical := now.AddDate(0, 0, ageCritical)
+
+	chains := make([]scanResultChain, 0, len(discoveredChains))
+	for _, certChain := range discoveredChains {
+		chains = append(
+			chains,
+			newScanResultChain(certChain, certsExpireAgeCritical, certsExpireAgeWarning),
+		)
+	}
+
+	fmt.Println("## Certificate chains summary")
+	fmt.Println()
+	fmt.Printf(
+		"%d certificate chains discovered, %d problems found\n",
+		len(chains),
+		discoveredChains.NumProblems(certsExpireAgeCritical, certsExpireAgeWarning),
+	)
+	fmt.Println()
+
+	fmt.Println(textutils.MarkdownTableHeader(
+		"Host",
+		"IP Address",
+		"Port",
+		"Certs",
+		"Expired",
+		"Expiring",
+		"Hostname Mismatches",
+		"Problems",
+	))
+
+	for _, chain := range chains {
+		fmt.Println(textutils.MarkdownTableRow(
+			chain.Host,
+			chain.IPAddress,
+			strconv.Itoa(chain.Port),
+			strconv.Itoa(chain.TotalCerts),
+			strconv.Itoa(chain.ExpiredCerts),
+			strconv.Itoa(chain.ExpiringCerts),
+			strings.Join(chain.HostnameMismatches, ", "),
+			strconv.Itoa(chain.Problems),
+		))
+	}
+
+	fmt.Println()
+	fmt.Println("## Certificates")
+	fmt.Println()
+
+	fmt.Println(textutils.MarkdownTableHeader(
+		"Host",
+		"IP Address",
+		"Port",
+		"Position",
+		"Subject",
+		"Issuer",
+		"Serial",
+		"Expiration",
+		"Days Remaining",
+		"Status",
+	))
+
+	for _, chain := range chains {
+		for _, cert := range chain.Certs {
+			fmt.Println(textutils.MarkdownTableRow(
+				chain.Host,
+				chain.IPAddress,
+				strconv.Itoa(chain.Port),
+				cert.ChainPosition.String(),
+				cert.Subject,
+				cert.Issuer,
+				cert.SerialNumber,
+				cert.NotAfter.Format(certs.CertValidityDateLayout),
+				strconv.Itoa(cert.DaysRemaining),
+				cert.Status,
+			))
+		}
+	}
+}
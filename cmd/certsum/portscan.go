@@ -30,6 +30,8 @@ func portScanner(
 	portScanResultsChan chan<- netutils.PortCheckResult,
 	portScanRateLimiter chan struct{}, // needs to allow send & receive
 	hostRateLimiter chan struct{}, // needs to allow send & receive
+	throttle *hostThrottle,
+	progress *scanProgress,
 	log zerolog.Logger,
 	wg *sync.WaitGroup,
 ) {
@@ -93,10 +95,17 @@ func portScanner(
 				hostVal = host.Given
 			}
 
+			// Ports specified for the host pattern (e.g., via a hosts file)
+			// override the ports specified for all host patterns.
+			targetPorts := ports
+			if len(host.Ports) > 0 {
+				targetPorts = host.Ports
+			}
+
 			scanTarget := netutils.PortCheckTarget{
 				Name:      hostVal,
 				IPAddress: ipAddr,
-				Ports:     ports,
+				Ports:     targetPorts,
 			}
 
 			// process all specified ports for the current host
@@ -110,6 +119,7 @@ func portScanner(
 
 					log.Debug().Msg("host goroutine defer triggered")
 					// indicate completion of scanning specified ports on host
+					progress.HostCompleted()
 					hostsWG.Done()
 					log.Debug().Msg("hostsWG.Done() called")
 
@@ -126,7 +136,7 @@ func portScanner(
 				}()
 
 				var portChecksWG sync.WaitGroup
-				for _, port := range ports {
+				for _, port := range target.Ports {
 
 					// abort early if context has been cancelled
 					if ctx.Err() != nil {
@@ -149,6 +159,20 @@ func portScanner(
 						return
 					}
 
+					// Space out connection attempts to this IP Address before
+					// reserving a spot so that waiting on this host does not
+					// hold up port scans for other hosts.
+					if err := throttle.Wait(ctx, target.IPAddress); err != nil {
+						log.Error().
+							Str("name", target.Name).
+							Str("ip_address", target.IPAddress).
+							Int("port", port).
+							Err(err).
+							Msg("portScanner: ports: context cancelled or expired while throttled")
+
+						return
+					}
+
 					// indicate that we are launching a goroutine that will be
 					// tracked and reserve a spot in the (intentionally limited)
 					// channel shared with per-host (parent) goroutines.
@@ -160,7 +184,15 @@ func portScanner(
 						Int("reserved", len(portScanRateLimiter)).
 						Msg("Port scan rate limiter reservation added")
 
-					log.Debug().Msg("Starting child port scanner goroutine")
+					// Each scan target (IP Address and port) is assigned an
+					// identifier included in all log events and results
+					// associated with the target.
+					correlationID := newCorrelationID()
+					targetLog := log.With().
+						Str("correlation_id", correlationID).
+						Logger()
+
+					targetLog.Debug().Msg("Starting child port scanner goroutine")
 					go func(
 						ctx context.Context,
 						target netutils.PortCheckTarget,
@@ -205,6 +237,7 @@ func portScanner(
 							Int("port", port).
 							Msg("Checking port on target")
 						portState := netutils.CheckPort(target, port, scanTimeout)
+						portState.CorrelationID = correlationID
 
 						// if portState.Err != nil {
 						//
@@ -232,7 +265,7 @@ func portScanner(
 
 						log.Debug().Msg("Finished child port scanner goroutine")
 
-					}(ctx, target, port, timeout, portScanResultsChan, log)
+					}(ctx, target, port, timeout, portScanResultsChan, targetLog)
 
 				}
 
@@ -260,3 +293,20 @@ func portScanner(
 	log.Debug().Msg("Finished parent port scanner goroutine")
 
 }
+
+// countScanTargets returns the number of IP Address and port combinations
+// to be scanned for the given host patterns. Ports specified for a host
+// pattern override the given ports.
+func countScanTargets(hosts []netutils.HostPattern, ports []int) int {
+	var total int
+	for _, host := range hosts {
+		numPorts := len(ports)
+		if len(host.Ports) > 0 {
+			numPorts = len(host.Ports)
+		}
+
+		total += len(host.Expanded) * numPorts
+	}
+
+	return total
+}
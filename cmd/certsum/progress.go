@@ -0,0 +1,144 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// scanProgress tracks the progress of a scan so that it can be reported
+// while the scan is underway. All methods are safe for concurrent use and
+// for use with a nil scanProgress.
+type scanProgress struct {
+	start time.Time
+
+	totalHosts int64
+	totalPorts int64
+
+	hostsCompleted atomic.Int64
+	portsScanned   atomic.Int64
+	chainsFound    atomic.Int64
+}
+
+// newScanProgress returns a scanProgress for a scan of the given number of
+// IP Addresses and IP Address and port combinations.
+func newScanProgress(totalHosts int, totalPorts int) *scanProgress {
+	return &scanProgress{
+		start:      time.Now(),
+		totalHosts: int64(totalHosts),
+		totalPorts: int64(totalPorts),
+	}
+}
+
+// HostCompleted records that all ports for an IP Address have been scanned.
+func (sp *scanProgress) HostCompleted() {
+	if sp == nil {
+		return
+	}
+	sp.hostsCompleted.Add(1)
+}
+
+// PortScanned records that a port has been scanned.
+func (sp *scanProgress) PortScanned() {
+	if sp == nil {
+		return
+	}
+	sp.portsScanned.Add(1)
+}
+
+// ChainFound records that a certificate chain has been retrieved.
+func (sp *scanProgress) ChainFound() {
+	if sp == nil {
+		return
+	}
+	sp.chainsFound.Add(1)
+}
+
+// ETA returns the estimated time remaining for the scan based on the rate
+// at which ports have been scanned so far. False is returned if no ports
+// have been scanned yet.
+func (sp *scanProgress) ETA(now time.Time) (time.Duration, bool) {
+	if sp == nil {
+		return 0, false
+	}
+
+	scanned := sp.portsScanned.Load()
+	if scanned == 0 {
+		return 0, false
+	}
+
+	remaining := sp.totalPorts - scanned
+	if remaining <= 0 {
+		return 0, true
+	}
+
+	elapsed := now.Sub(sp.start)
+	perPort := elapsed / time.Duration(scanned)
+
+	return perPort * time.Duration(remaining), true
+}
+
+// String provides a one-line summary of the scan progress.
+func (sp *scanProgress) String() string {
+	if sp == nil {
+		return "Progress: unavailable"
+	}
+
+	now := time.Now()
+
+	eta := "unknown"
+	if remaining, ok := sp.ETA(now); ok {
+		eta = remaining.Round(time.Second).String()
+	}
+
+	return fmt.Sprintf(
+		"Progress: %d/%d hosts completed, %d/%d ports scanned, %d cert chains found, elapsed %s, ETA %s",
+		sp.hostsCompleted.Load(),
+		sp.totalHosts,
+		sp.portsScanned.Load(),
+		sp.totalPorts,
+		sp.chainsFound.Load(),
+		now.Sub(sp.start).Round(time.Second),
+		eta,
+	)
+}
+
+// reportScanProgress writes a summary of the scan progress to the given
+// writer on the given interval (if greater than zero) and whenever a
+// progress signal (e.g., SIGUSR1 where supported) is received until the
+// given done channel is closed.
+func reportScanProgress(
+	w io.Writer,
+	sp *scanProgress,
+	interval time.Duration,
+	done <-chan struct{},
+) {
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	sigChan, stop := notifyProgressSignal()
+	defer stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-tick:
+		case <-sigChan:
+		}
+
+		_, _ = fmt.Fprintf(w, "\n%s\n", sp)
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyProgressSignal returns a channel which receives a value when the
+// application is sent SIGUSR1 (e.g., `kill -USR1 <pid>`) along with a
+// function to stop relaying the signal.
+func notifyProgressSignal() (<-chan os.Signal, func()) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGUSR1)
+
+	return sigChan, func() { signal.Stop(sigChan) }
+}
@@ -0,0 +1,18 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build windows
+
+package main
+
+import "os"
+
+// notifyProgressSignal returns a nil channel as SIGUSR1 is not supported on
+// this platform; progress is only reported on the configured interval.
+func notifyProgressSignal() (<-chan os.Signal, func()) {
+	return nil, func() {}
+}
@@ -0,0 +1,22 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	_ "embed"
+)
+
+// scanResultsSchema is the JSON Schema document describing the json output
+// format. Each document emitted by the ndjson output format is described by
+// the chain definition within this schema.
+//
+// Changes to the scanResults, scanResultChain or scanResultCert types are
+// expected to be reflected here; tests assert that they remain in sync.
+//
+//go:embed schema/scan-results.schema.json
+var scanResultsSchema string
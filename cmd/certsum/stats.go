@@ -0,0 +1,33 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/stats"
+)
+
+// newRunStatsRecord creates a run statistics record for a scan from the
+// discovered certificate chains and the error (if any) which aborted the
+// scan. Each failed (non-ignored) validation check for a discovered
+// certificate chain is recorded as a failure.
+func newRunStatsRecord(discoveredCertChains certs.DiscoveredCertChains, scanErr error, runtime time.Duration) stats.Record {
+	errs := []error{scanErr}
+
+	for _, chain := range discoveredCertChains {
+		for _, result := range chain.ValidationResults {
+			if result.IsFailed() {
+				errs = append(errs, result.Err())
+			}
+		}
+	}
+
+	return stats.NewRecord("certsum", len(discoveredCertChains), errs, runtime)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
 	"fmt"
 	"log"
 	"os"
@@ -9,8 +11,34 @@ import (
 	"time"
 
 	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+	"github.com/atc0005/go-nagios"
 )
 
+// certStatus returns a service check state label for the given certificate
+// based on the specified expiration thresholds. A status of IGNORED is
+// returned for certificates that the sysadmin opted to ignore.
+func certStatus(cert *x509.Certificate, certsExpireAgeCritical time.Time, certsExpireAgeWarning time.Time, ignored bool) string {
+	switch {
+	case ignored:
+		return strings.ToUpper(certs.ValidationStatusIgnored)
+	case certs.IsExpiredCert(cert):
+		return nagios.StateCRITICALLabel
+	case cert.NotAfter.Before(certsExpireAgeCritical):
+		return nagios.StateCRITICALLabel
+	case certs.IsExpiringCert(cert, certsExpireAgeCritical, certsExpireAgeWarning):
+		return nagios.StateWARNINGLabel
+	default:
+		return nagios.StateOKLabel
+	}
+}
+
+// hostnameMismatchSummary returns a brief note listing the given hostname or
+// FQDN values which failed hostname verification.
+func hostnameMismatchSummary(names []string) string {
+	return fmt.Sprintf(" [HOSTNAME MISMATCH: %s]", strings.Join(names, ", "))
+}
+
 func printSummaryHighLevel(
 	showAllHosts bool,
 	discoveredChains certs.DiscoveredCertChains,
@@ -68,16 +96,18 @@ func printSummaryHighLevel(
 
 	for _, certChain := range discoveredChains {
 
-		hasExpiredCert := certs.HasExpiredCert(certChain.Certs)
-		hasExpiringCert := certs.HasExpiringCert(
-			certChain.Certs,
-			certsExpireAgeCritical,
-			certsExpireAgeWarning,
-		)
+		// Use the validation check results recorded when the certificate
+		// chain was discovered.
+		expirationResult := certChain.ExpirationResult(ageCritical, ageWarning)
+
+		hostnameMismatches := certChain.HostnameMismatches()
+		hasIssues := expirationResult.HasExpiredCerts() ||
+			expirationResult.HasExpiringCerts() ||
+			len(hostnameMismatches) > 0
 
 		var statusIcon string
 		switch {
-		case hasExpiredCert || hasExpiringCert:
+		case hasIssues:
 			statusIcon = "\xE2\x9B\x94 (!!)"
 		default:
 			statusIcon = "\xE2\x9C\x85 (OK)"
@@ -85,7 +115,7 @@ func printSummaryHighLevel(
 
 		// Skip listing IP Addresses with certs without issues *unless*
 		// specifically requested.
-		if !hasExpiredCert && !hasExpiringCert && !showAllHosts {
+		if !hasIssues && !showAllHosts {
 			continue
 		}
 
@@ -94,20 +124,11 @@ func printSummaryHighLevel(
 			name = strings.Join(certChain.Certs[0].DNSNames, ", ")
 		}
 
-		// Explicitly enable expiration validation.
-		validationOptions := certs.CertChainValidationOptions{
-			IgnoreValidationResultExpiration: false,
+		chainSummary := expirationResult.Overview()
+		if len(hostnameMismatches) > 0 {
+			chainSummary += hostnameMismatchSummary(hostnameMismatches)
 		}
 
-		validationResults := certs.ValidateExpiration(
-			certChain.Certs,
-			ageCritical,
-			ageWarning,
-			true,
-			false,
-			validationOptions,
-		)
-
 		switch {
 		case hasHostNameVal:
 			_, _ = fmt.Fprintf(
@@ -118,7 +139,7 @@ func printSummaryHighLevel(
 				certChain.Port,
 				name,
 				statusIcon,
-				validationResults.Overview(),
+				chainSummary,
 				certs.FormatCertSerialNumber(certChain.Certs[0].SerialNumber),
 			)
 		default:
@@ -129,7 +150,7 @@ func printSummaryHighLevel(
 				certChain.Port,
 				name,
 				statusIcon,
-				validationResults.Overview(),
+				chainSummary,
 				certs.FormatCertSerialNumber(certChain.Certs[0].SerialNumber),
 			)
 		}
@@ -201,29 +222,44 @@ func printSummaryDetailedLevel(
 	}
 
 	for _, certChain := range discoveredChains {
-		for _, cert := range certChain.Certs {
+		hostnameMismatches := certChain.HostnameMismatches()
+
+		for idx, cert := range certChain.Certs {
 
-			isExpiredCert := certs.IsExpiredCert(cert)
-			isExpiringCert := certs.IsExpiringCert(
+			// Certificates that the sysadmin opted to ignore are listed only
+			// if all certificates are requested.
+			isIgnoredCert := certChain.IsIgnoredCert(cert)
+
+			isExpiredCert := !isIgnoredCert && certs.IsExpiredCert(cert)
+			isExpiringCert := !isIgnoredCert && certs.IsExpiringCert(
 				cert,
 				certsExpireAgeCritical,
 				certsExpireAgeWarning,
 			)
 
+			// Hostname verification is performed against the first
+			// certificate in the chain.
+			hasHostnameMismatch := idx == 0 && len(hostnameMismatches) > 0
+
 			// Skip listing Certificates in the chain which are valid *unless*
 			// specifically requested.
-			if !isExpiredCert && !isExpiringCert && !showAllCerts {
+			if !isExpiredCert && !isExpiringCert && !hasHostnameMismatch && !showAllCerts {
 				continue
 			}
 
 			var statusIcon string
 			switch {
-			case isExpiredCert || isExpiringCert:
+			case isExpiredCert || isExpiringCert || hasHostnameMismatch:
 				statusIcon = "\xE2\x9B\x94"
 			default:
 				statusIcon = "\xE2\x9C\x85"
 			}
 
+			certSummary := certs.ExpirationStatus(cert, certsExpireAgeCritical, certsExpireAgeWarning, isIgnoredCert)
+			if hasHostnameMismatch {
+				certSummary += hostnameMismatchSummary(hostnameMismatches)
+			}
+
 			name := cert.Subject.CommonName
 			if name == "" {
 				name = strings.Join(cert.DNSNames, ", ")
@@ -240,7 +276,7 @@ func printSummaryDetailedLevel(
 					name,
 					statusIcon,
 					certs.ChainPosition(cert, certChain.Certs),
-					certs.ExpirationStatus(cert, certsExpireAgeCritical, certsExpireAgeWarning, false),
+					certSummary,
 					certs.FormatCertSerialNumber(cert.SerialNumber),
 				)
 			default:
@@ -252,7 +288,7 @@ func printSummaryDetailedLevel(
 					name,
 					statusIcon,
 					certs.ChainPosition(cert, certChain.Certs),
-					certs.ExpirationStatus(cert, certsExpireAgeCritical, certsExpireAgeWarning, false),
+					certSummary,
 					certs.FormatCertSerialNumber(cert.SerialNumber),
 				)
 			}
@@ -269,3 +305,73 @@ func printSummaryDetailedLevel(
 		)
 	}
 }
+
+// printExpiryCliffs emits a consolidated warning for each group of
+// certificates discovered across all certificate chains which expire within
+// the given window of each other during the given lead time. Certificates
+// served by multiple hosts are listed once along with each location where
+// they were found. Certificates that the sysadmin opted to ignore are
+// excluded. Nothing is emitted if no expiry cliffs are found.
+func printExpiryCliffs(
+	discoveredChains certs.DiscoveredCertChains,
+	window time.Duration,
+	leadTime time.Duration,
+) {
+
+	var allCerts []*x509.Certificate
+	positions := make(map[[sha256.Size]byte]string)
+	locations := make(map[[sha256.Size]byte][]string)
+
+	for _, certChain := range discoveredChains {
+		location := fmt.Sprintf("%s:%d", certChain.IPAddress, certChain.Port)
+		if certChain.Name != "" {
+			location = fmt.Sprintf("%s (%s)", certChain.Name, location)
+		}
+
+		for _, cert := range certChain.Certs {
+			if certChain.IsIgnoredCert(cert) {
+				continue
+			}
+
+			fingerprint := sha256.Sum256(cert.Raw)
+			if _, ok := positions[fingerprint]; !ok {
+				positions[fingerprint] = certs.ChainPosition(cert, certChain.Certs).String()
+				allCerts = append(allCerts, cert)
+			}
+			locations[fingerprint] = append(locations[fingerprint], location)
+		}
+	}
+
+	cliffs := certs.ExpiryCliffs(allCerts, window, leadTime)
+	if len(cliffs) == 0 {
+		return
+	}
+
+	fmt.Printf(
+		"WARNING: %d expiry cliff(s) found; bulk renewals require additional lead time:\n",
+		len(cliffs),
+	)
+
+	for _, cliff := range cliffs {
+		fmt.Printf("\n  - %s\n", cliff)
+
+		for _, cert := range cliff.Certs {
+			fingerprint := sha256.Sum256(cert.Raw)
+
+			name := cert.Subject.CommonName
+			if name == "" {
+				name = strings.Join(cert.DNSNames, ", ")
+			}
+
+			fmt.Printf(
+				"      - %q (%s, serial %s) found on %s\n",
+				name,
+				positions[fingerprint],
+				certs.FormatCertSerialNumber(cert.SerialNumber),
+				strings.Join(textutils.DedupeList(locations[fingerprint]), ", "),
+			)
+		}
+	}
+
+	fmt.Println()
+}
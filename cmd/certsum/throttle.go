@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// hostThrottle spaces out connection attempts (port checks and certificate
+// retrieval) to the same IP Address and optionally adds a random delay
+// before each connection attempt. Unlike the scan rate limit which applies
+// to all scan targets, this throttle applies per IP Address so that
+// scanning many ports on a single host does not trip connection rate
+// protection or intrusion detection signatures.
+//
+// A nil hostThrottle does not delay connection attempts.
+type hostThrottle struct {
+	// delay is the minimum time between connection attempts to the same IP
+	// Address.
+	delay time.Duration
+
+	// jitter is the maximum random delay added before each connection
+	// attempt.
+	jitter time.Duration
+
+	mu sync.Mutex
+
+	// next records the earliest time that the next connection attempt to
+	// an IP Address is permitted.
+	next map[string]time.Time
+}
+
+// newHostThrottle returns a throttle for connection attempts using the
+// given minimum delay between connection attempts to the same IP Address
+// and maximum random delay before each connection attempt. If neither is
+// specified nil is returned.
+func newHostThrottle(delay time.Duration, jitter time.Duration) *hostThrottle {
+	if delay <= 0 && jitter <= 0 {
+		return nil
+	}
+
+	return &hostThrottle{
+		delay:  delay,
+		jitter: jitter,
+		next:   make(map[string]time.Time),
+	}
+}
+
+// reserve claims the next permitted connection slot for the given IP
+// Address and returns the time at which the connection attempt may begin.
+// Slots for the same IP Address are separated by at least the configured
+// delay; the random delay is applied as part of the slot so that it does
+// not shorten this separation.
+func (ht *hostThrottle) reserve(ipAddr string, now time.Time) time.Time {
+	var jitter time.Duration
+	if ht.jitter > 0 {
+		// nolint:gosec
+		jitter = time.Duration(rand.Int63n(int64(ht.jitter) + 1))
+	}
+
+	ht.mu.Lock()
+	defer ht.mu.Unlock()
+
+	slot := now
+	if next, ok := ht.next[ipAddr]; ok && next.After(slot) {
+		slot = next
+	}
+	slot = slot.Add(jitter)
+
+	ht.next[ipAddr] = slot.Add(ht.delay)
+
+	return slot
+}
+
+// Wait blocks until a connection attempt to the given IP Address is
+// permitted or the context is cancelled. The context error is returned if
+// the context is cancelled first.
+func (ht *hostThrottle) Wait(ctx context.Context, ipAddr string) error {
+	if ht == nil {
+		return nil
+	}
+
+	wait := time.Until(ht.reserve(ipAddr, time.Now()))
+	if wait <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
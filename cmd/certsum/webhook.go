@@ -0,0 +1,76 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/secrets"
+)
+
+// sendScanResultsWebhook sends the given scan results as a JSON document
+// (the json output format) to the specified URL using a POST request. If
+// specified, the value retrieved from the given secret provider is sent
+// using the given authentication header name. An error is returned if the
+// endpoint does not respond with a success (2xx) status code within the
+// given timeout.
+func sendScanResultsWebhook(
+	webhookURL string,
+	authHeaderName string,
+	authHeader secrets.Provider,
+	results scanResults,
+	timeout time.Duration,
+) error {
+	body, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to encode scan results: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to prepare webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if authHeader != nil {
+		value, err := authHeader.Secret(ctx)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to retrieve webhook authentication header value from %s: %w",
+				authHeader.Source(),
+				err,
+			)
+		}
+		req.Header.Set(authHeaderName, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send scan results to webhook: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"unexpected response from webhook: %s",
+			resp.Status,
+		)
+	}
+
+	return nil
+}
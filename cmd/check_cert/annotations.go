@@ -10,6 +10,7 @@ package main
 import (
 	// "syscall"
 
+	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/go-nagios"
 )
 
@@ -19,6 +20,44 @@ import (
 // field as a cause of this issue.
 // const connectionResetByPeerAdvice string = "consider checking certificate/port bindings (e.g., IIS Site Bindings)"
 
+// keyUsageMismatchAdvice offers advice to the sysadmin when certificates in
+// a chain have Key Usage values inappropriate for their tier or key type.
+// This is commonly the result of a mis-templated internal CA.
+const keyUsageMismatchAdvice string = "review the certificate templates used by the issuing CA; CA certificates require keyCertSign and cRLSign, leaf certificates require digitalSignature plus keyEncipherment (RSA) or keyAgreement (ECDSA) as appropriate"
+
+// newerCertInCTLogsAdvice offers advice to the sysadmin when a certificate
+// newer than the one served has been recorded in Certificate Transparency
+// logs. This is commonly the result of a renewal which was not deployed to
+// all servers or a service which was not restarted after renewal.
+const newerCertInCTLogsAdvice string = "confirm that the renewed certificate was installed on this server and that the service was restarted or reloaded"
+
+// renewalWindowStartedAdvice offers advice to the sysadmin when the renewal
+// window suggested by the Certificate Authority for a certificate has
+// started.
+const renewalWindowStartedAdvice string = "renew the certificate now as suggested by the issuing CA (e.g., run the ACME client renewal with ARI support); review the explanation URL (if provided) for the reason the renewal window was moved"
+
+// legacyTLSVersionAcceptedAdvice offers advice to the sysadmin when a server
+// negotiates a TLS protocol version older than the required minimum version.
+const legacyTLSVersionAcceptedAdvice string = "disable legacy protocol versions in the server or load balancer TLS configuration (e.g., ssl_protocols for nginx, SSLProtocol for Apache httpd or the SCHANNEL protocol registry settings for IIS)"
+
+// weakCipherSuitesAcceptedAdvice offers advice to the sysadmin when a server
+// accepts known-weak cipher suites.
+const weakCipherSuitesAcceptedAdvice string = "restrict the server or load balancer TLS configuration to AEAD cipher suites (e.g., ssl_ciphers for nginx, SSLCipherSuite for Apache httpd or the SCHANNEL cipher suite order policy for IIS) and disable TLS 1.0"
+
+// expiryCliffAdvice offers advice to the sysadmin when multiple
+// certificates in a chain expire within the same short window.
+const expiryCliffAdvice string = "plan the renewal of all listed certificates together well ahead of the earliest expiration date; bulk renewals (e.g., reissuing an internal CA and the certificates it signed) require more lead time than renewing a single certificate"
+
+// systemClockSuspectAdvice offers advice to the sysadmin when the system
+// clock of the monitoring host appears to be incorrect.
+const systemClockSuspectAdvice string = "verify the date and time on the monitoring host and that time synchronization (e.g., NTP, chrony or the Windows Time service) is working; validation results are unreliable until the system clock is corrected"
+
+// certChainsDifferAdvice offers advice to the sysadmin when the certificate
+// chain served by a compared service differs from the one served by the
+// monitored service. This is commonly the result of a renewed certificate
+// which was not yet deployed to both services.
+const certChainsDifferAdvice string = "confirm that the same (renewed) certificate chain is installed on both services before switching traffic between them"
+
 // annotateError is a helper function used to add additional human-readable
 // explanation for errors encountered during plugin execution. We first apply
 // common advice for more general errors then apply advice specific to errors
@@ -34,6 +73,14 @@ func annotateErrors(plugin *nagios.Plugin) {
 
 	// Override specific error with project-specific feedback.
 	// errorAdviceMap[syscall.ECONNRESET] = connectionResetByPeerAdvice
+	errorAdviceMap[certs.ErrCertChainKeyUsageMismatch] = keyUsageMismatchAdvice
+	errorAdviceMap[certs.ErrNewerCertInCTLogs] = newerCertInCTLogsAdvice
+	errorAdviceMap[certs.ErrRenewalWindowStarted] = renewalWindowStartedAdvice
+	errorAdviceMap[certs.ErrLegacyTLSVersionAccepted] = legacyTLSVersionAcceptedAdvice
+	errorAdviceMap[certs.ErrWeakCipherSuitesAccepted] = weakCipherSuitesAcceptedAdvice
+	errorAdviceMap[certs.ErrCertChainExpiryCliff] = expiryCliffAdvice
+	errorAdviceMap[certs.ErrSystemClockSuspect] = systemClockSuspectAdvice
+	errorAdviceMap[ErrCertChainsDiffer] = certChainsDifferAdvice
 
 	// Apply error advice annotations.
 	plugin.AnnotateRecordedErrors(errorAdviceMap)
@@ -0,0 +1,285 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// ErrCertChainsDiffer indicates that the certificate chain (or validation
+// check results) for a compared service differ from those of the service
+// specified by the server flag.
+var ErrCertChainsDiffer = errors.New("certificate chains differ")
+
+// certSummary returns a brief description of the given certificate used to
+// note differences between certificate chains.
+func certSummary(cert *x509.Certificate) string {
+	return fmt.Sprintf(
+		"%s (serial %s, expires %s, SHA-256 %x)",
+		cert.Subject.CommonName,
+		certs.FormatCertSerialNumber(cert.SerialNumber),
+		cert.NotAfter.Format("2006-01-02"),
+		sha256.Sum256(cert.Raw),
+	)
+}
+
+// certChainDifferences compares the given certificate chains position by
+// position and returns a description of each difference found. An empty
+// collection is returned if the certificate chains are identical.
+func certChainDifferences(certChain []*x509.Certificate, otherChain []*x509.Certificate) []string {
+	var differences []string
+
+	if len(certChain) != len(otherChain) {
+		differences = append(differences, fmt.Sprintf(
+			"number of certificates: %d vs %d",
+			len(certChain),
+			len(otherChain),
+		))
+	}
+
+	numCerts := len(certChain)
+	if len(otherChain) > numCerts {
+		numCerts = len(otherChain)
+	}
+
+	for i := 0; i < numCerts; i++ {
+		switch {
+		case i >= len(otherChain):
+			differences = append(differences, fmt.Sprintf(
+				"certificate %d: %s vs not present",
+				i+1,
+				certSummary(certChain[i]),
+			))
+
+		case i >= len(certChain):
+			differences = append(differences, fmt.Sprintf(
+				"certificate %d: not present vs %s",
+				i+1,
+				certSummary(otherChain[i]),
+			))
+
+		case !certChain[i].Equal(otherChain[i]):
+			differences = append(differences, fmt.Sprintf(
+				"certificate %d: %s vs %s",
+				i+1,
+				certSummary(certChain[i]),
+				certSummary(otherChain[i]),
+			))
+		}
+	}
+
+	return differences
+}
+
+// validationDifferences compares the status of each validation check
+// performed for both collections of validation check results and returns a
+// description of each difference found. An empty collection is returned if
+// the validation check outcomes are identical.
+func validationDifferences(results certs.CertChainValidationResults, otherResults certs.CertChainValidationResults) []string {
+	otherStatus := make(map[string]string, len(otherResults))
+	for _, result := range otherResults {
+		otherStatus[result.CheckName()] = result.ValidationStatus()
+	}
+
+	var differences []string
+	for _, result := range results {
+		status, ok := otherStatus[result.CheckName()]
+		if !ok {
+			status = "not performed"
+		}
+
+		if status != result.ValidationStatus() {
+			differences = append(differences, fmt.Sprintf(
+				"%s: %s vs %s",
+				result.CheckName(),
+				result.ValidationStatus(),
+				status,
+			))
+		}
+
+		delete(otherStatus, result.CheckName())
+	}
+
+	// Validation checks only performed for the compared service.
+	for _, result := range otherResults {
+		if _, ok := otherStatus[result.CheckName()]; ok {
+			differences = append(differences, fmt.Sprintf(
+				"%s: not performed vs %s",
+				result.CheckName(),
+				result.ValidationStatus(),
+			))
+		}
+	}
+
+	return differences
+}
+
+// compareReport provides a section of the detailed output listing the
+// differences found between the service specified by the server flag and
+// the compared service.
+func compareReport(primaryState nagios.ServiceState, compared targetCheckResult, chainDiffs []string, checkDiffs []string) string {
+	var report strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&report,
+		"%s**COMPARISON WITH %s: %s**%s%s",
+		nagios.CheckOutputEOL,
+		compared.label,
+		compared.state.Label,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	if compared.err != nil {
+		_, _ = fmt.Fprintf(
+			&report,
+			"Error fetching certificates for %s: %v%s",
+			compared.certChainSource,
+			compared.err,
+			nagios.CheckOutputEOL,
+		)
+
+		return report.String()
+	}
+
+	_, _ = fmt.Fprintf(
+		&report,
+		"%d certs retrieved for %s%s",
+		len(compared.certChain),
+		compared.certChainSource,
+		nagios.CheckOutputEOL,
+	)
+
+	if len(chainDiffs) == 0 && len(checkDiffs) == 0 {
+		_, _ = fmt.Fprintf(
+			&report,
+			"%s* No differences found%s",
+			nagios.CheckOutputEOL,
+			nagios.CheckOutputEOL,
+		)
+
+		return report.String()
+	}
+
+	writeDiffs := func(heading string, diffs []string) {
+		if len(diffs) == 0 {
+			return
+		}
+
+		_, _ = fmt.Fprintf(&report, "%s* %s:%s", nagios.CheckOutputEOL, heading, nagios.CheckOutputEOL)
+		for _, diff := range diffs {
+			_, _ = fmt.Fprintf(&report, "  - %s%s", diff, nagios.CheckOutputEOL)
+		}
+	}
+
+	writeDiffs(
+		fmt.Sprintf("Certificate chain differences (this service vs %s)", compared.label),
+		chainDiffs,
+	)
+	writeDiffs(
+		fmt.Sprintf(
+			"Validation check differences (this service [%s] vs %s [%s])",
+			primaryState.Label,
+			compared.label,
+			compared.state.Label,
+		),
+		checkDiffs,
+	)
+
+	return report.String()
+}
+
+// runComparison evaluates the certificate chain for the sysadmin-specified
+// service to compare against and notes any differences from the given
+// certificate chain and validation check results in the detailed output. If
+// differences are found an error is recorded and an OK plugin state is
+// raised to WARNING.
+func runComparison(
+	plugin *nagios.Plugin,
+	cfg *config.Config,
+	certChain []*x509.Certificate,
+	validationResults certs.CertChainValidationResults,
+	policies targetPolicies,
+	blocklist certs.CertBlocklist,
+	netBudget *budget.Budget,
+	deadline time.Time,
+	log zerolog.Logger,
+) {
+	if cfg.CompareWith == "" {
+		return
+	}
+
+	target, err := cfg.CompareWithTarget()
+	if err != nil {
+		// Already asserted by config validation.
+		log.Error().Err(err).Msg("Invalid comparison target")
+
+		return
+	}
+
+	compared := evaluateTarget(cfg, target, targetLabel(target), policies, blocklist, netBudget, deadline, log)
+
+	var chainDiffs []string
+	var checkDiffs []string
+	switch {
+	case compared.err != nil:
+		chainDiffs = []string{"certificate chain not retrieved"}
+	default:
+		chainDiffs = certChainDifferences(certChain, compared.certChain)
+		checkDiffs = validationDifferences(validationResults, compared.validationResults)
+	}
+
+	primaryState := nagios.ServiceState{
+		Label:    nagios.ExitCodeToStateLabel(plugin.ExitStatusCode),
+		ExitCode: plugin.ExitStatusCode,
+	}
+
+	log.Debug().
+		Str("compare_with", compared.label).
+		Str("compared_state", compared.state.Label).
+		Int("chain_differences", len(chainDiffs)).
+		Int("validation_differences", len(checkDiffs)).
+		Msg("Compared certificate chains")
+
+	plugin.LongServiceOutput += compareReport(primaryState, compared, chainDiffs, checkDiffs)
+
+	if len(chainDiffs) == 0 && len(checkDiffs) == 0 {
+		return
+	}
+
+	plugin.AddError(fmt.Errorf(
+		"%w: %d certificate chain and %d validation check differences found for %s",
+		ErrCertChainsDiffer,
+		len(chainDiffs),
+		len(checkDiffs),
+		compared.label,
+	))
+
+	// A more severe (or UNKNOWN) state for this service takes precedence.
+	if plugin.ExitStatusCode == nagios.StateOKExitCode {
+		plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Certificate chain differs from %s [%d chain differences, %d validation differences]",
+			nagios.StateWARNINGLabel,
+			compared.label,
+			len(chainDiffs),
+			len(checkDiffs),
+		)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/go-nagios"
+)
+
+// daysRemainingUnknown is the value emitted in place of the number of days
+// until the soonest certificate expiration if this could not be determined.
+const daysRemainingUnknown int = -1
+
+// daysRemaining returns the number of days until the soonest expiration of
+// a certificate in the given certificate chains. Zero is returned if a
+// certificate has already expired. If a certificate chain is empty (e.g.,
+// the certificate chain could not be retrieved) or no certificate chains are
+// given, daysRemainingUnknown is returned.
+func daysRemaining(certChains ...[]*x509.Certificate) int {
+	if len(certChains) == 0 {
+		return daysRemainingUnknown
+	}
+
+	soonest := daysRemainingUnknown
+	for _, certChain := range certChains {
+		cert := certs.NextToExpire(certChain, false)
+		if cert == nil {
+			return daysRemainingUnknown
+		}
+
+		days, err := certs.ExpiresInDays(cert)
+		if err != nil {
+			return daysRemainingUnknown
+		}
+
+		if days < 0 {
+			days = 0
+		}
+
+		if soonest == daysRemainingUnknown || days < soonest {
+			soonest = days
+		}
+	}
+
+	return soonest
+}
+
+// applyDaysRemainingOnly replaces the plugin output with the number of days
+// until the soonest expiration of a certificate in the given certificate
+// chains if requested. The exit code is left as-is so that it continues to
+// reflect the final plugin state.
+func applyDaysRemainingOnly(plugin *nagios.Plugin, cfg *config.Config, certChains ...[]*x509.Certificate) {
+	if !cfg.DaysRemainingOnly {
+		return
+	}
+
+	_, _ = fmt.Fprintln(plugin.OutputTarget(), daysRemaining(certChains...))
+
+	plugin.SetOutputTarget(io.Discard)
+}
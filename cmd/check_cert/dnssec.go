@@ -0,0 +1,82 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// checkServerDNSSEC determines whether the DNS server reports the DNS answer
+// for the given expanded server value as authenticated using DNSSEC. A nil
+// result is returned if this was not requested or if the server value is an
+// IP Address (no name resolution performed). An error is returned if the DNS
+// server to query cannot be determined.
+func checkServerDNSSEC(cfg *config.Config, expandedHost netutils.HostPattern, log zerolog.Logger) (*netutils.DNSSECResult, error) {
+	if cfg.ResolverDNSSECMode == "" || cfg.ResolverDNSSECMode == config.ResolverDNSSECModeOff || !expandedHost.Resolved {
+		return nil, nil
+	}
+
+	validator, err := cfg.AnswerValidator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup DNSSEC status check: %w", err)
+	}
+
+	result := validator.ValidateAnswer(expandedHost.Given)
+
+	log.Debug().
+		Str("name", result.Name).
+		Str("dns_server", result.Server).
+		Str("dnssec_status", string(result.Status)).
+		Bool("ad_bit", result.AuthenticatedData).
+		AnErr("dnssec_error", result.Err).
+		Msg("DNSSEC status check of DNS answer completed")
+
+	return &result, nil
+}
+
+// reportDNSSEC notes the DNSSEC status reported by the DNS server for the
+// DNS answer for the server value in the detailed output if verbose output
+// was requested or the answer was not reported as authenticated. If the
+// sysadmin opted to be warned of an unauthenticated answer, the service
+// state is raised to WARNING (if not already more severe).
+func reportDNSSEC(plugin *nagios.Plugin, cfg *config.Config, result *netutils.DNSSECResult) {
+	if result == nil {
+		return
+	}
+
+	if cfg.VerboseOutput || !result.Secure() {
+		plugin.LongServiceOutput += fmt.Sprintf(
+			"%s%s%s",
+			nagios.CheckOutputEOL,
+			result.String(),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	if result.Secure() || cfg.ResolverDNSSECMode != config.ResolverDNSSECModeWarn {
+		return
+	}
+
+	plugin.AddError(fmt.Errorf("%s: %w", result, netutils.ErrInsecureResolution))
+
+	// A more severe (or UNKNOWN) state for this service takes precedence.
+	if plugin.ExitStatusCode == nagios.StateOKExitCode {
+		plugin.ExitStatusCode = nagios.StateWARNINGExitCode
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: DNS answer for %q not reported as authenticated using DNSSEC by DNS server (%s)",
+			nagios.StateWARNINGLabel,
+			result.Name,
+			result.Status,
+		)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strconv"
+
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/exechook"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/go-nagios"
+)
+
+// runExecHook invokes the sysadmin-specified executable with the JSON encoded
+// certificate metadata payload (including connection metadata) for the
+// evaluated certificate chain provided via stdin. The final service check
+// state is provided via environment variables.
+//
+// Output from the executable is logged and not included in plugin output.
+// The executable is given the same amount of time to complete as is allowed
+// for retrieving a certificate chain.
+func runExecHook(certChain []*x509.Certificate, plugin *nagios.Plugin, cfg *config.Config, ipAddr string, retrievalStats netutils.CertRetrievalStats) error {
+	results, err := encodeCertChainPayload(certChain, plugin, cfg, ipAddr, retrievalStats)
+	if err != nil {
+		return fmt.Errorf("failed to generate results for exec hook: %w", err)
+	}
+
+	env := []string{
+		exechook.Var(exechook.EnvServiceState, nagios.ExitCodeToStateLabel(plugin.ExitStatusCode)),
+		exechook.Var(exechook.EnvExitCode, strconv.Itoa(plugin.ExitStatusCode)),
+		exechook.Var(exechook.EnvServer, cfg.Server),
+		exechook.Var(exechook.EnvPort, strconv.Itoa(cfg.Port)),
+		exechook.Var(exechook.EnvFilename, cfg.InputFilename),
+	}
+
+	return exechook.Run(cfg.ExecHook, results, env, cfg.Timeout(), cfg.Log)
+}
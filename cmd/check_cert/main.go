@@ -14,17 +14,25 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 
+	"github.com/atc0005/check-cert/internal/budget"
 	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/config"
 	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/stats"
+	"github.com/atc0005/check-cert/internal/validation"
 	"github.com/atc0005/go-nagios"
 )
 
 func main() {
 
+	// Record when execution began so that the plugin timeout (if specified)
+	// accounts for all time spent, not just time spent on validation checks.
+	pluginStart := time.Now()
+
 	plugin := nagios.NewPlugin()
 
 	plugin.EnablePluginOutputSizePerfDataMetric()
@@ -82,22 +90,236 @@ func main() {
 		Str("expected_sans_entries", cfg.SANsEntries.String()).
 		Logger()
 
+	// If requested, replace plugin output with the number of days until the
+	// soonest expiration of the evaluated certificate chains once all other
+	// deferred functions (including the exec hook) have run. If no
+	// certificate chains were evaluated (e.g., due to an error loading
+	// settings) the value for an unknown number of days is emitted.
+	var evaluatedCertChains [][]*x509.Certificate
+	defer func() {
+		applyDaysRemainingOnly(plugin, cfg, evaluatedCertChains...)
+	}()
+
+	// If requested, record run statistics once all other deferred functions
+	// (aside from those above) have run so that all recorded errors and
+	// evaluated certificate chains are included.
+	if cfg.StatsFile != "" {
+		defer func() {
+			record := stats.NewRecord("check_cert", len(evaluatedCertChains), plugin.Errors, time.Since(pluginStart))
+			if err := stats.AppendFile(cfg.StatsFile, record); err != nil {
+				log.Error().Err(err).Msg("Failed to record run statistics")
+			}
+		}()
+	}
+
+	// Optional validation checks are skipped as the plugin timeout
+	// approaches so that results for completed checks are still emitted.
+	var deadline time.Time
+	if cfg.PluginTimeout() > 0 {
+		deadline = pluginStart.Add(cfg.PluginTimeout())
+
+		log.Debug().
+			Time("deadline", deadline).
+			Msg("Plugin timeout deadline set")
+	}
+
+	// A system clock set to a time before this application was built will
+	// cause mass validation failures (e.g., for every monitored service at
+	// once), so this is reported as a problem with the monitoring host.
+	if clockErr := certs.CheckSystemClock(time.Now(), config.BuildTime()); clockErr != nil {
+		log.Error().Err(clockErr).Msg("System clock appears to be incorrect")
+
+		plugin.AddError(clockErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: System clock on monitoring host appears to be incorrect",
+			nagios.StateUNKNOWNLabel,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+		annotateErrors(plugin)
+
+		return
+	}
+
+	// Load the blocklist before attempting to retrieve certificates so that
+	// a problem with the file is reported without waiting on a remote
+	// server.
+	var blocklist certs.CertBlocklist
+	if cfg.BlocklistFile != "" {
+		var blocklistErr error
+		blocklist, blocklistErr = certs.LoadCertBlocklistFile(cfg.BlocklistFile)
+		if blocklistErr != nil {
+			log.Error().Err(blocklistErr).Msg("Error loading certificate blocklist")
+
+			plugin.AddError(blocklistErr)
+			plugin.ServiceOutput = fmt.Sprintf(
+				"%s: Error loading certificate blocklist %q",
+				nagios.StateUNKNOWNLabel,
+				cfg.BlocklistFile,
+			)
+			plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+			return
+		}
+
+		log.Debug().
+			Int("blocklist_entries", blocklist.Len()).
+			Msg("Certificate blocklist loaded")
+	}
+
+	// Network-dependent validation checks share a single budget for the
+	// external requests made and bytes downloaded during this execution.
+	netBudget := budget.New(cfg.MaxExternalRequests, cfg.MaxDownloadBytes)
+	defer func() {
+		requests, bytes := netBudget.Usage()
+		log.Debug().
+			Int("external_requests", requests).
+			Int64("downloaded_bytes", bytes).
+			Msg("Network budget usage")
+	}()
+
+	// Load the policy file before attempting to retrieve certificates so
+	// that a problem with the file (or policy settings) is reported without
+	// waiting on a remote server.
+	policies, policiesErr := loadTargetPolicies(cfg)
+	if policiesErr != nil {
+		log.Error().Err(policiesErr).Msg("Error loading policy file")
+
+		plugin.AddError(policiesErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error loading policy file %q",
+			nagios.StateUNKNOWNLabel,
+			cfg.PolicyFile,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return
+	}
+
+	// If a socket was specified, targets are provided by each request
+	// received until interrupted.
+	if cfg.Socket != "" {
+		runSocketServer(plugin, cfg, policies, blocklist, log)
+
+		return
+	}
+
+	// If a trust store was specified, each certificate in the bundle is
+	// evaluated separately instead of as a certificate chain.
+	if cfg.TrustStore != "" {
+		defer applyBriefWhenOK(plugin, cfg, log)
+		defer annotateErrors(plugin)
+		defer applySimulatedState(plugin, cfg, log)
+		defer applyStateMappings(plugin, cfg, log)
+
+		evaluatedCertChains = append(evaluatedCertChains, runTrustStoreCheck(plugin, cfg, log))
+
+		return
+	}
+
+	// If a list of ports or a targets file was specified, each target is
+	// evaluated separately and the results combined into a single service
+	// check result.
+	targets, targetsErr := loadTargets(cfg)
+	if targetsErr != nil {
+		log.Error().Err(targetsErr).Msg("Error loading targets")
+
+		plugin.AddError(targetsErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error loading targets file %q",
+			nagios.StateUNKNOWNLabel,
+			cfg.TargetsFile,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return
+	}
+
+	if len(targets) > 0 {
+		log.Debug().
+			Int("targets", len(targets)).
+			Msg("Evaluating multiple targets")
+
+		var results []targetCheckResult
+
+		// Generate the aggregate payload once the final plugin state is
+		// known so that the recorded service check state agrees with it.
+		defer func() {
+			if !cfg.EmitPayload && !cfg.EmitPayloadWithFullChain {
+				return
+			}
+
+			if err := addTargetsPayload(plugin, cfg, results); err != nil {
+				log.Error().
+					Err(err).
+					Msg("failed to add encoded payload")
+
+				plugin.Errors = append(plugin.Errors, err)
+
+				plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+				plugin.ServiceOutput = fmt.Sprintf(
+					"%s: Failed to add encoded payload",
+					nagios.StateUNKNOWNLabel,
+				)
+			}
+		}()
+
+		defer applyBriefWhenOK(plugin, cfg, log)
+		defer annotateErrors(plugin)
+		defer applySimulatedState(plugin, cfg, log)
+		defer applyStateMappings(plugin, cfg, log)
+
+		results = runTargetsChecks(plugin, cfg, targets, policies, blocklist, netBudget, deadline, log)
+		evaluatedCertChains = targetsCertChains(results)
+
+		return
+	}
+
+	// Settings from the first matching policy (if any) replace those
+	// specified via flags for the single target evaluated.
+	cfg, policyName := policies.configFor(cfg, cfg.Server, cfg.DNSName, nil, log)
+
 	// We declare these earlier so that they can be referenced by closures
 	// (e.g., adding certificate metadata payload to plugin).
 	var (
 		certChain       []*x509.Certificate
 		certChainSource string
 		ipAddr          string
+		resolveTime     time.Duration
+		retrievalStats  netutils.CertRetrievalStats
+		endpoint        validation.Endpoint
+		dnssecResult    *netutils.DNSSECResult
 	)
 
-	// We run this function next to last so that we have access to the latest
+	// Record the certificate chain (if any) for use by the days remaining
+	// output mode.
+	defer func() {
+		evaluatedCertChains = [][]*x509.Certificate{certChain}
+	}()
+
+	// If requested, run the exec hook after all other deferred functions
+	// (aside from emitting plugin output) so that it receives the final
+	// plugin state. A hook failure is logged but does not change the plugin
+	// state; the hook is an integration point and not part of the check.
+	defer func(cc *[]*x509.Certificate, p *nagios.Plugin, c *config.Config, ip *string, rs *netutils.CertRetrievalStats) {
+		if c.ExecHook == "" {
+			return
+		}
+
+		if err := runExecHook(*cc, p, c, *ip, *rs); err != nil {
+			log.Error().
+				Err(err).
+				Msg("failed to run exec hook")
+		}
+	}(&certChain, plugin, cfg, &ipAddr, &retrievalStats)
+
+	// We run this function near the end so that we have access to the latest
 	// state of the plugin, including any errors registered with the plugin
 	// (e.g., after any annotations have been applied).
-	defer func(cc *[]*x509.Certificate, p *nagios.Plugin, c *config.Config, ip *string) {
+	defer func(cc *[]*x509.Certificate, p *nagios.Plugin, c *config.Config, ip *string, rs *netutils.CertRetrievalStats) {
 		if cfg.EmitPayload || cfg.EmitPayloadWithFullChain {
 			// We intentionally use different var names to prevent capturing
 			// outside variable values at time of deferring this closure.
-			payloadErr := addCertChainPayload(*cc, p, c, *ip)
+			payloadErr := addCertChainPayload(*cc, p, c, *ip, *rs)
 			if payloadErr != nil {
 				log.Error().
 					Err(payloadErr).
@@ -123,13 +345,26 @@ func main() {
 		// latest value for the variable at the time of execution (otherwise
 		// it would capture only the value at the time the function is
 		// deferred).
-	}(&certChain, plugin, cfg, &ipAddr)
+	}(&certChain, plugin, cfg, &ipAddr, &retrievalStats)
+
+	// If requested, omit the detailed report output once the final plugin
+	// state is known.
+	defer applyBriefWhenOK(plugin, cfg, log)
 
 	// Annotate all errors (if any) with remediation advice just before
 	// generating the certificate metadata payload and ending plugin
 	// execution.
 	defer annotateErrors(plugin)
 
+	// If requested, replace the final plugin state with a simulated state
+	// once any state overrides have been applied.
+	defer applySimulatedState(plugin, cfg, log)
+
+	// Apply any requested service check state overrides before errors are
+	// annotated and the certificate metadata payload is generated so that
+	// both reflect the final plugin state.
+	defer applyStateMappings(plugin, cfg, log)
+
 	// Honor request to parse filename first
 	switch {
 	case cfg.InputFilename != "":
@@ -193,18 +428,13 @@ func main() {
 
 	case cfg.Server != "":
 
-		log.Debug().Msg("Expanding given host pattern in order to obtain IP Address")
-		expandedHost, expandErr := netutils.ExpandHost(cfg.Server)
-		switch {
-		case expandErr != nil:
-			log.Error().Err(expandErr).Msg(
-				"Error expanding given host pattern")
-
+		expandedHost, expandMsg, expandErr := expandServer(cfg.Server, cfg.Resolver(), cfg.DNSTimeout(), log)
+		if expandErr != nil {
 			plugin.AddError(expandErr)
 			plugin.ServiceOutput = fmt.Sprintf(
-				"%s: Error expanding given host pattern %q to target IP Address",
+				"%s: %s",
 				nagios.StateCRITICALLabel,
-				cfg.Server,
+				expandMsg,
 			)
 			plugin.ExitStatusCode = nagios.StateCRITICALExitCode
 
@@ -212,127 +442,53 @@ func main() {
 			// have a connection to the remote server and there isn't anything
 			// further we can do
 			return
+		}
 
-		// Fail early for IP Ranges. While we could just grab the first
-		// expanded IP Address, this may be a potential source of confusion
-		// best avoided.
-		case expandedHost.Range:
-			invalidHostPatternErr := errors.New("invalid host pattern")
-			msg := fmt.Sprintf(
-				"Given host pattern invalid; " +
-					"host pattern is a CIDR or partial IP range",
-			)
-			log.Error().Err(invalidHostPatternErr).Msg(msg)
+		var dnssecErr error
+		dnssecResult, dnssecErr = checkServerDNSSEC(cfg, expandedHost, log)
+		switch {
+		case dnssecErr != nil:
+			log.Error().Err(dnssecErr).Msg("Error checking DNSSEC status of DNS answer")
 
-			plugin.AddError(invalidHostPatternErr)
+			plugin.AddError(dnssecErr)
 			plugin.ServiceOutput = fmt.Sprintf(
-				"%s: %s",
-				nagios.StateCRITICALLabel,
-				msg,
+				"%s: Error checking DNSSEC status of DNS answer for %q",
+				nagios.StateUNKNOWNLabel,
+				cfg.Server,
 			)
-			plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+			plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
 
-			// no need to go any further, we *want* to exit right away; we don't
-			// have a connection to the remote server and there isn't anything
-			// further we can do
 			return
 
-		case len(expandedHost.Expanded) == 0:
-			expandHostErr := errors.New("host pattern expansion failed")
-			msg := "Error expanding given host value to IP Address"
-
-			log.Error().Err(expandHostErr).Msg(msg)
+		// A certificate chain retrieved from a (possibly) hijacked name
+		// provides false assurance, so we stop here if requested.
+		case dnssecResult != nil && !dnssecResult.Secure() && cfg.ResolverDNSSECMode == config.ResolverDNSSECModeFail:
+			log.Error().
+				Err(netutils.ErrInsecureResolution).
+				Str("dnssec_status", string(dnssecResult.Status)).
+				Msg("DNS answer not reported as authenticated using DNSSEC by DNS server")
 
-			plugin.AddError(expandHostErr)
+			plugin.AddError(fmt.Errorf("%s: %w", dnssecResult, netutils.ErrInsecureResolution))
 			plugin.ServiceOutput = fmt.Sprintf(
-				"%s: %s",
+				"%s: DNS answer for %q not reported as authenticated using DNSSEC by DNS server (%s); skipped retrieving certificate chain",
 				nagios.StateCRITICALLabel,
-				msg,
+				cfg.Server,
+				dnssecResult.Status,
 			)
+			plugin.LongServiceOutput = dnssecResult.String()
 			plugin.ExitStatusCode = nagios.StateCRITICALExitCode
 
-			// no need to go any further, we *want* to exit right away; we don't
-			// have a connection to the remote server and there isn't anything
-			// further we can do
 			return
-
-		case len(expandedHost.Expanded) > 1:
-
-			ipAddrs := zerolog.Arr()
-			for _, ip := range expandedHost.Expanded {
-				ipAddrs.Str(ip)
-			}
-
-			log.Debug().
-				Int("num_ip_addresses", len(expandedHost.Expanded)).
-				Array("ip_addresses", ipAddrs).
-				Msg("Multiple IP Addresses resolved from given host pattern")
-			log.Debug().Msg("Using first IP Address, ignoring others")
 		}
 
 		// Grab first IP Address from the resolved collection. We'll
 		// explicitly use it for cert retrieval and note it in the report
 		// output.
 		ipAddr = expandedHost.Expanded[0]
+		resolveTime = expandedHost.ResolveTime
 
-		// Server Name Indication (SNI) support is used to request a specific
-		// certificate chain from a remote server.
-		//
-		// We use the value specified by the `server` flag to open a
-		// connection to the remote server. If available, we use the DNS Name
-		// value specified by the DNA Name flag as our host value, otherwise
-		// we fallback to using the value specified by the server flag as our
-		// host value.
-		//
-		// For a service with only one certificate chain the host value is
-		// less important, but for a host with multiple certificate chains
-		// having the correct host value is crucial.
 		var hostVal string
-		switch {
-
-		// We have a resolved IP Address and a sysadmin-specified DNS Name
-		// value to use for a SNI-enabled certificate retrieval attempt.
-		case expandedHost.Resolved && cfg.DNSName != "":
-			hostVal = cfg.DNSName
-			certChainSource = fmt.Sprintf(
-				"service running on %s (%s) at port %d using host value %q",
-				expandedHost.Given,
-				ipAddr,
-				cfg.Port,
-				hostVal,
-			)
-
-		// We have a valid IP Address to use for opening the connection and a
-		// sysadmin-specified DNS Name value to use for a SNI-enabled
-		// certificate retrieval attempt.
-		case cfg.DNSName != "":
-			hostVal = cfg.DNSName
-			certChainSource = fmt.Sprintf(
-				"service running on %s at port %d using host value %q",
-				ipAddr,
-				cfg.Port,
-				hostVal,
-			)
-
-		// We have a resolved IP Address, but not a sysadmin-specified DNS
-		// Name value. We'll use the resolvable name/FQDN for a SNI-enabled
-		// certificate retrieval attempt.
-		case expandedHost.Resolved && cfg.DNSName == "":
-			hostVal = expandedHost.Given
-			certChainSource = fmt.Sprintf(
-				"service running on %s (%s) at port %d using host value %q",
-				expandedHost.Given,
-				ipAddr,
-				cfg.Port,
-				expandedHost.Given,
-			)
-		default:
-			certChainSource = fmt.Sprintf(
-				"service running on %s at port %d",
-				ipAddr,
-				cfg.Port,
-			)
-		}
+		hostVal, certChainSource = serverHostValue(cfg.DNSName, expandedHost, ipAddr, cfg.Port)
 
 		log.Debug().
 			Str("server", cfg.Server).
@@ -342,25 +498,28 @@ func main() {
 			Int("port", cfg.Port).
 			Msg("Retrieving certificate chain")
 		var certFetchErr error
-		certChain, certFetchErr = netutils.GetCerts(
+		certChain, retrievalStats, certFetchErr = netutils.GetCertsWithOptions(
 			hostVal,
 			ipAddr,
 			cfg.Port,
 			cfg.Timeout(),
+			cfg.CertRetrievalOptions(),
 			log,
 		)
 		if certFetchErr != nil {
 			log.Error().Err(certFetchErr).Msg(
 				"Error fetching certificates chain")
 
+			state := certFetchFailureState(cfg, certFetchErr)
+
 			plugin.AddError(certFetchErr)
 			plugin.ServiceOutput = fmt.Sprintf(
 				"%s: Error fetching certificates from port %d on %s",
-				nagios.StateCRITICALLabel,
+				state.Label,
 				cfg.Port,
 				cfg.Server,
 			)
-			plugin.ExitStatusCode = nagios.StateCRITICALExitCode
+			plugin.ExitStatusCode = state.ExitCode
 
 			// no need to go any further, we *want* to exit right away; we don't
 			// have a connection to the remote server and there isn't anything
@@ -369,6 +528,12 @@ func main() {
 
 		}
 
+		endpoint = validation.Endpoint{
+			HostVal: hostVal,
+			IPAddr:  ipAddr,
+			Port:    cfg.Port,
+		}
+
 	}
 
 	// NOTE: Not sure this would ever be reached due to:
@@ -395,6 +560,8 @@ func main() {
 		return
 	}
 
+	certChainSource = withPolicyName(certChainSource, policyName)
+
 	// Prepend a baseline lead-in that summarizes the number of certificates
 	// retrieved and from which target host/IP Address.
 	defer func() {
@@ -420,7 +587,25 @@ func main() {
 		)
 	}()
 
-	validationResults := runValidationChecks(cfg, certChain, log)
+	// Every validation check result is suspect if the system clock is
+	// wrong, so the problem is reported once instead of as a collection of
+	// (likely CRITICAL) validation check failures.
+	if clockErr := certs.CheckSystemClockForCertChain(certChain, time.Now()); clockErr != nil {
+		log.Error().Err(clockErr).Msg("System clock appears to be incorrect")
+
+		plugin.AddError(clockErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: System clock on monitoring host appears to be incorrect; skipped evaluating %d certs",
+			nagios.StateUNKNOWNLabel,
+			len(certChain),
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return
+	}
+
+	validationResults, validationTimings := validation.Run(cfg, cfg.Server, cfg.DNSName, endpoint, certChain, blocklist, netBudget, deadline, log)
+	timings := checkTimings(validationTimings)
 
 	// validationResults.Sort()
 	for _, item := range validationResults {
@@ -431,7 +616,9 @@ func main() {
 		)
 	}
 
-	pd, perfDataErr := getPerfData(certChain, cfg.AgeCritical, cfg.AgeWarning)
+	ageCritical, ageWarning := validation.ExpirationThresholds(cfg, certChain)
+	lifeCritical, lifeWarning := validation.ExpirationPercentThresholds(cfg, certChain)
+	pd, perfDataErr := getPerfData(certChain, ageCritical, ageWarning, lifeCritical, lifeWarning)
 	if perfDataErr != nil {
 		log.Error().
 			Err(perfDataErr).
@@ -449,6 +636,14 @@ func main() {
 		return
 	}
 
+	if retrievalStats.Attempts > 0 {
+		pd = append(pd, retrievalPerfData(resolveTime, retrievalStats)...)
+	}
+
+	if cfg.ShowCheckTimings {
+		pd = append(pd, timings.perfData("")...)
+	}
+
 	if err := plugin.AddPerfData(false, pd...); err != nil {
 		log.Error().
 			Err(err).
@@ -503,4 +698,26 @@ func main() {
 
 	}
 
+	runComparison(plugin, cfg, certChain, validationResults, policies, blocklist, netBudget, deadline, log)
+
+	if cfg.ShowCheckTimings {
+		plugin.LongServiceOutput += timings.report()
+	}
+
+	if cfg.ShowRemediation {
+		plugin.LongServiceOutput += remediationReport(chainRemediations(
+			certChain,
+			remediationSource{
+				server:   cfg.Server,
+				dnsName:  cfg.DNSName,
+				port:     cfg.Port,
+				filename: cfg.InputFilename,
+			},
+		))
+	}
+
+	reportSANsDelta(plugin, cfg, certChain, log)
+
+	reportDNSSEC(plugin, cfg, dnssecResult)
+
 }
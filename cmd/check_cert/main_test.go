@@ -8,12 +8,16 @@
 package main
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"flag"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/atc0005/check-cert/internal/config"
 	"github.com/atc0005/go-nagios"
@@ -464,3 +468,531 @@ func TestEmptyClientPerfDataAndConstructedPluginProducesDefaultTimeMetric(t *tes
 		t.Logf("OK: Emitted performance data contains the expected time metric.")
 	}
 }
+
+// TestDaysRemaining asserts that the number of days until the soonest
+// certificate expiration is determined across all given certificate chains
+// and that -1 is used when this cannot be determined.
+func TestDaysRemaining(t *testing.T) {
+	t.Parallel()
+
+	// The extra hour guards against rounding down to the previous day.
+	certExpiringIn := func(days int) *x509.Certificate {
+		return &x509.Certificate{
+			NotAfter: time.Now().Add(time.Duration(days)*24*time.Hour + time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		certChains [][]*x509.Certificate
+		want       int
+	}{
+		{
+			name:       "NoCertChains",
+			certChains: nil,
+			want:       -1,
+		},
+		{
+			name:       "SingleCertChain",
+			certChains: [][]*x509.Certificate{{certExpiringIn(90), certExpiringIn(30)}},
+			want:       30,
+		},
+		{
+			name: "SoonestAcrossCertChains",
+			certChains: [][]*x509.Certificate{
+				{certExpiringIn(90)},
+				{certExpiringIn(10), certExpiringIn(400)},
+			},
+			want: 10,
+		},
+		{
+			name:       "ExpiredCert",
+			certChains: [][]*x509.Certificate{{certExpiringIn(90), certExpiringIn(-5)}},
+			want:       0,
+		},
+		{
+			name: "CertChainNotRetrieved",
+			certChains: [][]*x509.Certificate{
+				{certExpiringIn(90)},
+				nil,
+			},
+			want: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := daysRemaining(tt.certChains...); got != tt.want {
+				t.Errorf("daysRemaining() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAggregateState asserts that the combined service check state for
+// multiple targets is determined using the chosen aggregate strategy.
+func TestAggregateState(t *testing.T) {
+	t.Parallel()
+
+	criticalState := nagios.ServiceState{
+		Label:    nagios.StateCRITICALLabel,
+		ExitCode: nagios.StateCRITICALExitCode,
+	}
+
+	tests := []struct {
+		name        string
+		strategy    string
+		numProblems int
+		numTargets  int
+		want        int
+	}{
+		{
+			name:        "WorstStrategyOneProblem",
+			strategy:    config.AggregateStrategyWorst,
+			numProblems: 1,
+			numTargets:  200,
+			want:        nagios.StateCRITICALExitCode,
+		},
+		{
+			name:        "PercentageBelowWarning",
+			strategy:    config.AggregateStrategyPercentage,
+			numProblems: 1,
+			numTargets:  200,
+			want:        nagios.StateOKExitCode,
+		},
+		{
+			name:        "PercentageAtWarning",
+			strategy:    config.AggregateStrategyPercentage,
+			numProblems: 20,
+			numTargets:  200,
+			want:        nagios.StateWARNINGExitCode,
+		},
+		{
+			name:        "PercentageAtCritical",
+			strategy:    config.AggregateStrategyPercentage,
+			numProblems: 50,
+			numTargets:  200,
+			want:        nagios.StateCRITICALExitCode,
+		},
+		{
+			name:        "CountNoProblems",
+			strategy:    config.AggregateStrategyCount,
+			numProblems: 0,
+			numTargets:  200,
+			want:        nagios.StateOKExitCode,
+		},
+		{
+			name:        "CountAtWarning",
+			strategy:    config.AggregateStrategyCount,
+			numProblems: 1,
+			numTargets:  200,
+			want:        nagios.StateWARNINGExitCode,
+		},
+		{
+			name:        "CountAtCritical",
+			strategy:    config.AggregateStrategyCount,
+			numProblems: 5,
+			numTargets:  200,
+			want:        nagios.StateCRITICALExitCode,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.Config{AggregateStrategy: tt.strategy}
+
+			got := aggregateState(&cfg, criticalState, tt.numProblems, tt.numTargets)
+			if got.ExitCode != tt.want {
+				t.Errorf("aggregateState() exit code = %d, want %d", got.ExitCode, tt.want)
+			}
+		})
+	}
+}
+
+// TestCertChainDifferences asserts that differences between certificate
+// chains are reported by position.
+func TestCertChainDifferences(t *testing.T) {
+	t.Parallel()
+
+	newCert := func(name string, raw byte) *x509.Certificate {
+		return &x509.Certificate{
+			Raw:          []byte{raw},
+			Subject:      pkix.Name{CommonName: name},
+			SerialNumber: big.NewInt(int64(raw)),
+		}
+	}
+
+	leaf := newCert("www.example.com", 1)
+	renewedLeaf := newCert("www.example.com", 2)
+	intermediate := newCert("Example Intermediate", 3)
+
+	tests := []struct {
+		name       string
+		certChain  []*x509.Certificate
+		otherChain []*x509.Certificate
+		want       int
+	}{
+		{
+			name:       "Identical",
+			certChain:  []*x509.Certificate{leaf, intermediate},
+			otherChain: []*x509.Certificate{leaf, intermediate},
+			want:       0,
+		},
+		{
+			name:       "RenewedLeaf",
+			certChain:  []*x509.Certificate{leaf, intermediate},
+			otherChain: []*x509.Certificate{renewedLeaf, intermediate},
+			want:       1,
+		},
+		{
+			name:       "MissingIntermediate",
+			certChain:  []*x509.Certificate{leaf, intermediate},
+			otherChain: []*x509.Certificate{leaf},
+			want:       2,
+		},
+		{
+			name:       "ExtraCertificate",
+			certChain:  []*x509.Certificate{renewedLeaf},
+			otherChain: []*x509.Certificate{leaf, intermediate},
+			want:       3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := certChainDifferences(tt.certChain, tt.otherChain)
+			if len(got) != tt.want {
+				t.Errorf("certChainDifferences() returned %d differences, want %d: %v", len(got), tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSocketTarget(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Config{Port: 443}
+
+	tests := []struct {
+		name     string
+		req      socketRequest
+		wantPort int
+		err      error
+	}{
+		{
+			name:     "DefaultPort",
+			req:      socketRequest{Server: "www.example.com"},
+			wantPort: 443,
+		},
+		{
+			name:     "ExplicitPort",
+			req:      socketRequest{Server: "www.example.com", Port: 8443},
+			wantPort: 8443,
+		},
+		{
+			name: "MissingServer",
+			req:  socketRequest{Server: " ", Port: 8443},
+			err:  ErrInvalidSocketRequest,
+		},
+		{
+			name: "InvalidPort",
+			req:  socketRequest{Server: "www.example.com", Port: 99999},
+			err:  ErrInvalidSocketRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, err := socketTarget(cfg, tt.req)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("socketTarget() error = %v, want %v", err, tt.err)
+			}
+
+			if target.Port != tt.wantPort {
+				t.Errorf("socketTarget() port = %d, want %d", target.Port, tt.wantPort)
+			}
+		})
+	}
+}
+
+// TestNewSocketResponseLeafExpiration asserts that the leaf certificate
+// expiration date is provided in each supported format.
+func TestNewSocketResponseLeafExpiration(t *testing.T) {
+	t.Parallel()
+
+	root, rootKey := newRemediationTestCert(t, 1, "Test Root CA", true, nil, nil, nil)
+	leaf, _ := newRemediationTestCert(t, 2, "www.example.com", false, root, rootKey, nil)
+
+	resp := newSocketResponse(
+		&config.Config{},
+		targetCheckResult{certChain: []*x509.Certificate{leaf, root}},
+	)
+
+	switch {
+	case resp.LeafNotBefore != leaf.NotBefore.Unix():
+		t.Errorf("want not before epoch %d; got %d", leaf.NotBefore.Unix(), resp.LeafNotBefore)
+	case resp.LeafNotAfter != leaf.NotAfter.Unix():
+		t.Errorf("want not after epoch %d; got %d", leaf.NotAfter.Unix(), resp.LeafNotAfter)
+	case resp.LeafNotAfterRFC3339 != leaf.NotAfter.UTC().Format(time.RFC3339):
+		t.Errorf("want RFC3339 not after %s; got %s", leaf.NotAfter.UTC().Format(time.RFC3339), resp.LeafNotAfterRFC3339)
+	case resp.LeafNotAfterHuman == "":
+		t.Error("want human-readable not after value; got empty string")
+	}
+
+	if resp := newSocketResponse(&config.Config{}, targetCheckResult{}); resp.LeafNotAfter != 0 || resp.LeafNotAfterRFC3339 != "" {
+		t.Errorf("want empty leaf expiration without certificate chain; got %+v", resp)
+	}
+}
+
+// newTestPluginConfig returns a plugin configuration initialized from the
+// given CLI flags and values as the sysadmin would specify them.
+func newTestPluginConfig(t *testing.T, flagsAndValues ...string) *config.Config {
+	t.Helper()
+
+	oldArgs := os.Args
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	os.Args = append([]string{"check_cert", "--server", "www.example.com"}, flagsAndValues...)
+
+	// Reset parsed flags by discarding the previous default flagset and
+	// creating a new one from scratch.
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+	cfg, err := config.New(config.AppType{Plugin: true})
+	if err != nil {
+		t.Fatalf("Error encountered when instantiating configuration: %v", err)
+	}
+
+	return cfg
+}
+
+// TestApplyStateMappings asserts that a service check state override
+// replaces the exit code and the state label in the one-line summary while
+// noting the original state in the detailed output.
+func TestApplyStateMappings(t *testing.T) {
+	tests := []struct {
+		name          string
+		mappings      []string
+		exitCode      int
+		serviceOutput string
+		wantExitCode  int
+		wantOutput    string
+		wantNote      bool
+	}{
+		{
+			name:          "WarningMappedToCritical",
+			mappings:      []string{"--map-state", "WARNING=CRITICAL"},
+			exitCode:      nagios.StateWARNINGExitCode,
+			serviceOutput: "WARNING: Certificate expires soon",
+			wantExitCode:  nagios.StateCRITICALExitCode,
+			wantOutput:    "CRITICAL: Certificate expires soon",
+			wantNote:      true,
+		},
+		{
+			name:          "UnmappedStateUnchanged",
+			mappings:      []string{"--map-state", "WARNING=CRITICAL"},
+			exitCode:      nagios.StateOKExitCode,
+			serviceOutput: "OK: Certificate valid",
+			wantExitCode:  nagios.StateOKExitCode,
+			wantOutput:    "OK: Certificate valid",
+		},
+		{
+			name:          "SummaryWithoutStateLabel",
+			mappings:      []string{"--map-state", "UNKNOWN=WARNING"},
+			exitCode:      nagios.StateUNKNOWNExitCode,
+			serviceOutput: "Error retrieving certificates",
+			wantExitCode:  nagios.StateWARNINGExitCode,
+			wantOutput:    "WARNING: Error retrieving certificates",
+			wantNote:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestPluginConfig(t, tt.mappings...)
+
+			plugin := nagios.NewPlugin()
+			plugin.ExitStatusCode = tt.exitCode
+			plugin.ServiceOutput = tt.serviceOutput
+			plugin.LongServiceOutput = "details"
+
+			applyStateMappings(plugin, cfg, cfg.Log)
+
+			if plugin.ExitStatusCode != tt.wantExitCode {
+				t.Errorf("exit code = %d, want %d", plugin.ExitStatusCode, tt.wantExitCode)
+			}
+
+			if plugin.ServiceOutput != tt.wantOutput {
+				t.Errorf("service output = %q, want %q", plugin.ServiceOutput, tt.wantOutput)
+			}
+
+			gotNote := strings.Contains(plugin.LongServiceOutput, "NOTE:")
+			if gotNote != tt.wantNote {
+				t.Errorf("override note present = %t, want %t", gotNote, tt.wantNote)
+			}
+		})
+	}
+}
+
+// TestApplyBriefWhenOK asserts that the detailed output is omitted for OK
+// results (including those produced by a service check state override) while
+// retaining any note describing the override.
+func TestApplyBriefWhenOK(t *testing.T) {
+	tests := []struct {
+		name           string
+		flags          []string
+		exitCode       int
+		serviceOutput  string
+		wantExitCode   int
+		wantOutput     string
+		wantLongOutput string
+	}{
+		{
+			name:           "OKTrimmed",
+			flags:          []string{"--brief-when-ok"},
+			exitCode:       nagios.StateOKExitCode,
+			serviceOutput:  "OK: Certificate valid",
+			wantExitCode:   nagios.StateOKExitCode,
+			wantOutput:     "OK: Certificate valid",
+			wantLongOutput: "",
+		},
+		{
+			name:           "WarningNotTrimmed",
+			flags:          []string{"--brief-when-ok"},
+			exitCode:       nagios.StateWARNINGExitCode,
+			serviceOutput:  "WARNING: Certificate expires soon",
+			wantExitCode:   nagios.StateWARNINGExitCode,
+			wantOutput:     "WARNING: Certificate expires soon",
+			wantLongOutput: "details",
+		},
+		{
+			name:           "WarningMappedToOKKeepsNote",
+			flags:          []string{"--brief-when-ok", "--map-state", "WARNING=OK"},
+			exitCode:       nagios.StateWARNINGExitCode,
+			serviceOutput:  "WARNING: Certificate expires soon",
+			wantExitCode:   nagios.StateOKExitCode,
+			wantOutput:     "OK: Certificate expires soon",
+			wantLongOutput: stateMappingNote(nagios.StateWARNINGLabel, nagios.StateOKLabel),
+		},
+		{
+			name:           "CriticalMappedToWarningNotTrimmed",
+			flags:          []string{"--brief-when-ok", "--map-state", "CRITICAL=WARNING"},
+			exitCode:       nagios.StateCRITICALExitCode,
+			serviceOutput:  "CRITICAL: Certificate expired",
+			wantExitCode:   nagios.StateWARNINGExitCode,
+			wantOutput:     "WARNING: Certificate expired",
+			wantLongOutput: "details" + nagios.CheckOutputEOL + nagios.CheckOutputEOL + stateMappingNote(nagios.StateCRITICALLabel, nagios.StateWARNINGLabel),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newTestPluginConfig(t, tt.flags...)
+
+			plugin := nagios.NewPlugin()
+			plugin.ExitStatusCode = tt.exitCode
+			plugin.ServiceOutput = tt.serviceOutput
+			plugin.LongServiceOutput = "details"
+
+			// Applied in the same order as the deferred calls in main.
+			applyStateMappings(plugin, cfg, cfg.Log)
+			applyBriefWhenOK(plugin, cfg, cfg.Log)
+
+			if plugin.ExitStatusCode != tt.wantExitCode {
+				t.Errorf("exit code = %d, want %d", plugin.ExitStatusCode, tt.wantExitCode)
+			}
+
+			if plugin.ServiceOutput != tt.wantOutput {
+				t.Errorf("service output = %q, want %q", plugin.ServiceOutput, tt.wantOutput)
+			}
+
+			if plugin.LongServiceOutput != tt.wantLongOutput {
+				t.Errorf("long service output = %q, want %q", plugin.LongServiceOutput, tt.wantLongOutput)
+			}
+		})
+	}
+}
+
+// TestGetPerfDataLifeRemainingThresholds asserts that percentage of lifetime
+// remaining thresholds are emitted as ranges which alert when the value
+// falls below the threshold.
+func TestGetPerfDataLifeRemainingThresholds(t *testing.T) {
+	t.Parallel()
+
+	certChain := []*x509.Certificate{
+		{
+			Subject:   pkix.Name{CommonName: "www.example.com"},
+			NotBefore: time.Now().Add(-45 * 24 * time.Hour),
+			NotAfter:  time.Now().Add(45 * 24 * time.Hour),
+		},
+	}
+
+	tests := []struct {
+		name         string
+		critPercent  int
+		warnPercent  int
+		wantCritical string
+		wantWarning  string
+	}{
+		{
+			name:         "ThresholdsSpecified",
+			critPercent:  10,
+			warnPercent:  20,
+			wantCritical: "10:",
+			wantWarning:  "20:",
+		},
+		{
+			name: "ThresholdsNotSpecified",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			pd, err := getPerfData(certChain, 15, 30, tt.critPercent, tt.warnPercent)
+			if err != nil {
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			var found bool
+			for _, metric := range pd {
+				if metric.Label != "life_remaining_leaf" {
+					continue
+				}
+				found = true
+
+				if err := metric.Validate(); err != nil {
+					t.Errorf("want valid metric; got %v", err)
+				}
+
+				if metric.Crit != tt.wantCritical {
+					t.Errorf("want critical range %q; got %q", tt.wantCritical, metric.Crit)
+				}
+
+				if metric.Warn != tt.wantWarning {
+					t.Errorf("want warning range %q; got %q", tt.wantWarning, metric.Warn)
+				}
+			}
+
+			if !found {
+				t.Fatal("want life_remaining_leaf metric; got none")
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ package main
 
 import (
 	"bytes"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
@@ -16,14 +17,19 @@ import (
 
 	payload "github.com/atc0005/cert-payload"
 	"github.com/atc0005/cert-payload/input"
+	"github.com/atc0005/check-cert/internal/certpayload"
 	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/validation"
 	"github.com/atc0005/go-nagios"
 	"github.com/rs/zerolog"
 )
 
-// addCertChainPayload appends a given certificate chain payload (as a JSON
-// encoded value) to plugin output.
-func addCertChainPayload(certChain []*x509.Certificate, plugin *nagios.Plugin, cfg *config.Config, ipAddr string) error {
+// encodeCertChainPayload generates a JSON encoded certificate metadata
+// payload for the given certificate chain using the latest plugin state.
+// Metadata for the connection used to retrieve the certificate chain (if
+// any) is included.
+func encodeCertChainPayload(certChain []*x509.Certificate, plugin *nagios.Plugin, cfg *config.Config, ipAddr string, retrievalStats netutils.CertRetrievalStats) ([]byte, error) {
 	log := cfg.Log.With().Logger()
 
 	// We convert the last exit code registered with the plugin to a suitable
@@ -32,27 +38,37 @@ func addCertChainPayload(certChain []*x509.Certificate, plugin *nagios.Plugin, c
 
 	log.Debug().Msgf("%d errors registered with plugin", len(plugin.Errors))
 
+	ageCritical, ageWarning := validation.ExpirationThresholds(cfg, certChain)
+
 	inputData := input.Values{
 		CertChain:                            certChain,
 		Errors:                               plugin.Errors,
 		IncludeFullCertChain:                 cfg.EmitPayloadWithFullChain,
 		OmitSANsEntries:                      cfg.OmitSANsEntries,
-		ExpirationAgeInDaysWarningThreshold:  cfg.AgeWarning,
-		ExpirationAgeInDaysCriticalThreshold: cfg.AgeCritical,
+		ExpirationAgeInDaysWarningThreshold:  ageWarning,
+		ExpirationAgeInDaysCriticalThreshold: ageCritical,
 		Server:                               input.Server{HostValue: cfg.Server, IPAddress: ipAddr},
 		DNSName:                              cfg.DNSName,
 		TCPPort:                              cfg.Port,
 		ServiceState:                         serviceState,
 	}
 
-	stableFormats := payload.AvailableStableFormatVersions()
+	stableFormats := certpayload.AvailableStableFormatVersions()
 
 	if cfg.PayloadFormatVersion == payload.UnstablePayloadVersion {
 		log.Warn().Msg("WARNING: Unstable/development payload format version chosen.")
 		log.Warn().Msgf("It is recommended that you use a stable payload format version (available: %v).", stableFormats)
 	}
 
-	certChainSummary, certSummaryErr := payload.Encode(cfg.PayloadFormatVersion, inputData)
+	return certpayload.Encode(cfg.PayloadFormatVersion, payloadValues(inputData, ipAddr, retrievalStats))
+}
+
+// addCertChainPayload appends a given certificate chain payload (as a JSON
+// encoded value) to plugin output.
+func addCertChainPayload(certChain []*x509.Certificate, plugin *nagios.Plugin, cfg *config.Config, ipAddr string, retrievalStats netutils.CertRetrievalStats) error {
+	log := cfg.Log.With().Logger()
+
+	certChainSummary, certSummaryErr := encodeCertChainPayload(certChain, plugin, cfg, ipAddr, retrievalStats)
 
 	if certSummaryErr != nil {
 		return certSummaryErr
@@ -79,3 +95,30 @@ func addCertChainPayload(certChain []*x509.Certificate, plugin *nagios.Plugin, c
 
 	return nil
 }
+
+// connectionMetadata returns the metadata for the connection used to
+// retrieve a certificate chain from the given IP Address. Empty metadata is
+// returned if a TLS connection was not established (e.g., the certificate
+// chain was read from a file).
+func connectionMetadata(ipAddr string, retrievalStats netutils.CertRetrievalStats) certpayload.Connection {
+	if retrievalStats.TLSVersion == 0 {
+		return certpayload.Connection{}
+	}
+
+	return certpayload.Connection{
+		TLSVersion:  netutils.TLSVersionName(retrievalStats.TLSVersion),
+		CipherSuite: tls.CipherSuiteName(retrievalStats.CipherSuite),
+		ServerName:  retrievalStats.ServerName,
+		IPAddress:   ipAddr,
+	}
+}
+
+// payloadValues returns the input data used to generate a certificate
+// metadata payload extended with the details of the connection used to
+// retrieve the certificate chain from the given IP Address.
+func payloadValues(inputData input.Values, ipAddr string, retrievalStats netutils.CertRetrievalStats) certpayload.Values {
+	return certpayload.Values{
+		Values:     inputData,
+		Connection: connectionMetadata(ipAddr, retrievalStats),
+	}
+}
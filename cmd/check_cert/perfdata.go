@@ -11,16 +11,25 @@ import (
 	"crypto/x509"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/netutils"
 	"github.com/atc0005/go-nagios"
 )
 
 // getPerfData generates performance data metrics from the given certificate
-// chain and certificate age thresholds. An error is returned if any are
-// encountered while gathering metrics or if an empty certificate chain is
-// provided.
-func getPerfData(certChain []*x509.Certificate, ageCritical int, ageWarning int) ([]nagios.PerformanceData, error) {
+// chain and certificate age thresholds. The percentage of lifetime remaining
+// thresholds are applied to the leaf certificate lifetime metric if
+// specified (non-zero). An error is returned if any are encountered while
+// gathering metrics or if an empty certificate chain is provided.
+func getPerfData(
+	certChain []*x509.Certificate,
+	ageCritical int,
+	ageWarning int,
+	lifeCriticalPercent int,
+	lifeWarningPercent int,
+) ([]nagios.PerformanceData, error) {
 	if len(certChain) == 0 {
 		return nil, fmt.Errorf(
 			"func getPerfData: unable to generate metrics: %w",
@@ -57,6 +66,18 @@ func getPerfData(certChain []*x509.Certificate, ageCritical int, ageWarning int)
 		expiresIntermediate = daysToExpiration
 	}
 
+	// Expiration dates are also provided as seconds since the Unix epoch so
+	// that graphing tools do not need to parse the report output.
+	var expiresLeafTimestamp int64
+	if oldestLeaf != nil {
+		expiresLeafTimestamp = oldestLeaf.NotAfter.Unix()
+	}
+
+	var expiresIntermediateTimestamp int64
+	if oldestIntermediate != nil {
+		expiresIntermediateTimestamp = oldestIntermediate.NotAfter.Unix()
+	}
+
 	var oldestIntermediateLifeRemaining int
 	if intermediateLifeRemaining, err := certs.LifeRemainingPercentageTruncated(oldestIntermediate); err == nil {
 		oldestIntermediateLifeRemaining = intermediateLifeRemaining
@@ -67,6 +88,15 @@ func getPerfData(certChain []*x509.Certificate, ageCritical int, ageWarning int)
 	certsPresentRoot := strconv.Itoa(certs.NumRootCerts(certChain))
 	certsPresentUnknown := strconv.Itoa(certs.NumUnknownCerts(certChain))
 
+	// Lower values for the remaining lifetime are worse, so the thresholds
+	// are emitted as "N:" ranges; Nagios alerts when the value falls below
+	// N. A bare N would instead alert when the value is above N.
+	var lifeCritical, lifeWarning string
+	if lifeCriticalPercent > 0 && lifeWarningPercent > 0 {
+		lifeCritical = strconv.Itoa(lifeCriticalPercent) + ":"
+		lifeWarning = strconv.Itoa(lifeWarningPercent) + ":"
+	}
+
 	pd := []nagios.PerformanceData{
 		{
 			Label:             "expires_leaf",
@@ -82,6 +112,14 @@ func getPerfData(certChain []*x509.Certificate, ageCritical int, ageWarning int)
 			Warn:              fmt.Sprintf("%d", ageWarning),
 			Crit:              fmt.Sprintf("%d", ageCritical),
 		},
+		{
+			Label: "expires_leaf_timestamp",
+			Value: strconv.FormatInt(expiresLeafTimestamp, 10),
+		},
+		{
+			Label: "expires_intermediate_timestamp",
+			Value: strconv.FormatInt(expiresIntermediateTimestamp, 10),
+		},
 		{
 			Label: "certs_present_leaf",
 			Value: certsPresentLeaf,
@@ -102,10 +140,8 @@ func getPerfData(certChain []*x509.Certificate, ageCritical int, ageWarning int)
 			Label:             "life_remaining_leaf",
 			Value:             fmt.Sprintf("%d", oldestLeafLifeRemaining),
 			UnitOfMeasurement: "%",
-
-			// TODO: GH-789
-			// Warn:              fmt.Sprintf("%d", ageWarning),
-			// Crit:              fmt.Sprintf("%d", ageCritical),
+			Warn:              lifeWarning,
+			Crit:              lifeCritical,
 		},
 		{
 			Label:             "life_remaining_intermediate",
@@ -121,3 +157,38 @@ func getPerfData(certChain []*x509.Certificate, ageCritical int, ageWarning int)
 	return pd, nil
 
 }
+
+// retrievalPerfData generates performance data metrics for the number of
+// connection attempts made to retrieve a certificate chain and the time
+// spent in each connection phase. The DNS resolution metric is omitted if
+// name resolution was not required.
+func retrievalPerfData(resolveTime time.Duration, stats netutils.CertRetrievalStats) []nagios.PerformanceData {
+	pd := make([]nagios.PerformanceData, 0, 4)
+
+	pd = append(pd, nagios.PerformanceData{
+		Label: "retrieval_attempts",
+		Value: strconv.Itoa(stats.Attempts),
+	})
+
+	if resolveTime > 0 {
+		pd = append(pd, millisecondsPerfData("dns_time", resolveTime))
+	}
+
+	pd = append(
+		pd,
+		millisecondsPerfData("connect_time", stats.ConnectTime),
+		millisecondsPerfData("handshake_time", stats.HandshakeTime),
+	)
+
+	return pd
+}
+
+// millisecondsPerfData generates a performance data metric for the given
+// duration in milliseconds.
+func millisecondsPerfData(label string, d time.Duration) nagios.PerformanceData {
+	return nagios.PerformanceData{
+		Label:             label,
+		Value:             fmt.Sprintf("%.3f", float64(d)/float64(time.Millisecond)),
+		UnitOfMeasurement: "ms",
+	}
+}
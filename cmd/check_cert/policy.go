@@ -0,0 +1,96 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/policy"
+	"github.com/rs/zerolog"
+)
+
+// targetPolicy is a policy along with the configuration produced by
+// applying the policy settings to the user-specified configuration.
+type targetPolicy struct {
+	policy policy.Policy
+	cfg    *config.Config
+}
+
+// targetPolicies is the collection of policies loaded from the
+// user-specified policy file, evaluated in order.
+type targetPolicies []targetPolicy
+
+// loadTargetPolicies loads the policies from the user-specified policy file
+// and precomputes the configuration for each so that invalid policy settings
+// are reported before any target is evaluated. If a policy file was not
+// specified, nil is returned.
+func loadTargetPolicies(cfg *config.Config) (targetPolicies, error) {
+	if cfg.PolicyFile == "" {
+		return nil, nil
+	}
+
+	policies, err := policy.LoadFile(cfg.PolicyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tps := make(targetPolicies, 0, len(policies))
+	for _, p := range policies {
+		policyCfg, err := cfg.WithPolicySettings(p.Settings)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid settings for policy %q: %w",
+				p.Name,
+				err,
+			)
+		}
+
+		tps = append(tps, targetPolicy{policy: p, cfg: policyCfg})
+	}
+
+	return tps, nil
+}
+
+// configFor returns the configuration and name of the first policy applying
+// to a target with the given server, DNS Name and tags. The given
+// configuration and an empty name are returned if no policy applies.
+func (tps targetPolicies) configFor(
+	cfg *config.Config,
+	server string,
+	dnsName string,
+	tags []string,
+	log zerolog.Logger,
+) (*config.Config, string) {
+	for _, tp := range tps {
+		if tp.policy.Matches(server, dnsName, tags) {
+			log.Debug().
+				Str("policy", tp.policy.Name).
+				Msg("Applying policy to target")
+
+			return tp.cfg, tp.policy.Name
+		}
+	}
+
+	if len(tps) > 0 {
+		log.Debug().Msg("No policy applies to target")
+	}
+
+	return cfg, ""
+}
+
+// withPolicyName appends the name of the applied policy (if any) to the
+// given certificate chain source description for display in the detailed
+// report.
+func withPolicyName(certChainSource string, policyName string) string {
+	if policyName == "" {
+		return certChainSource
+	}
+
+	return fmt.Sprintf("%s (policy: %s)", certChainSource, policyName)
+}
@@ -0,0 +1,276 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/go-nagios"
+)
+
+// remediationSource identifies where a certificate chain was retrieved from
+// so that remediation commands can reference it.
+type remediationSource struct {
+	// server is the FQDN or IP Address the certificate chain was retrieved
+	// from. This is empty if the certificate chain was read from a file.
+	server string
+
+	// dnsName is the DNS Name used for SNI support and hostname
+	// verification (if specified).
+	dnsName string
+
+	// port is the TCP port the certificate chain was retrieved from.
+	port int
+
+	// filename is the certificate file the certificate chain was read from
+	// (if any).
+	filename string
+}
+
+// host returns the name the certificate is expected to be valid for.
+func (rs remediationSource) host() string {
+	if rs.dnsName != "" {
+		return rs.dnsName
+	}
+
+	return rs.server
+}
+
+// filePrefix returns the prefix used for the names of files written by
+// remediation commands.
+func (rs remediationSource) filePrefix() string {
+	if host := rs.host(); host != "" {
+		return host
+	}
+
+	return "server"
+}
+
+// cpcertCommand returns a cpcert command saving the certificates of the
+// given type from the certificate chain source to the given file.
+func (rs remediationSource) cpcertCommand(keep string, outputFile string) string {
+	if rs.filename != "" {
+		return fmt.Sprintf(
+			"cpcert --keep %s %s %s",
+			keep,
+			shellQuote(rs.filename),
+			shellQuote(outputFile),
+		)
+	}
+
+	cmd := fmt.Sprintf("cpcert --port %d", rs.port)
+	if rs.dnsName != "" {
+		cmd += " --dns-name " + shellQuote(rs.dnsName)
+	}
+
+	return fmt.Sprintf(
+		"%s --keep %s %s %s",
+		cmd,
+		keep,
+		shellQuote(rs.server),
+		shellQuote(outputFile),
+	)
+}
+
+// sClientCommand returns an openssl s_client command connecting to the
+// certificate chain source. An empty string is returned if the certificate
+// chain was read from a file.
+func (rs remediationSource) sClientCommand() string {
+	if rs.filename != "" || rs.server == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"openssl s_client -connect %s -servername %s -showcerts </dev/null",
+		shellQuote(net.JoinHostPort(rs.server, strconv.Itoa(rs.port))),
+		shellQuote(rs.host()),
+	)
+}
+
+// remediation is a problem found with a certificate chain along with the
+// commands used to resolve it. Commands starting with a # character are
+// comments explaining the commands which follow.
+type remediation struct {
+	problem  string
+	commands []string
+}
+
+// chainRemediations returns remediation commands for the common problems
+// (missing intermediate certificate, misordered certificate chain and
+// expired leaf certificate) found with the given certificate chain.
+func chainRemediations(certChain []*x509.Certificate, source remediationSource) []remediation {
+	var remediations []remediation
+
+	prefix := source.filePrefix()
+	leafFile := prefix + "-leaf.pem"
+	fullChainFile := prefix + "-fullchain.pem"
+
+	if certs.IsIncompleteChain(certChain) {
+		leaf := certs.LeafCerts(certChain)[0]
+
+		commands := []string{
+			"# Save the leaf certificate currently in use",
+			source.cpcertCommand("leaf", leafFile),
+		}
+
+		if len(leaf.IssuingCertificateURL) > 0 {
+			commands = append(
+				commands,
+				"# Download the issuing certificate listed in the leaf certificate",
+				fmt.Sprintf(
+					"curl -fsSL -o intermediate.der %s",
+					shellQuote(leaf.IssuingCertificateURL[0]),
+				),
+				"openssl x509 -inform DER -in intermediate.der -out intermediate.pem",
+			)
+		} else {
+			commands = append(
+				commands,
+				fmt.Sprintf(
+					"# Obtain the certificate for issuer %q from the CA as intermediate.pem",
+					leaf.Issuer.String(),
+				),
+			)
+		}
+
+		commands = append(
+			commands,
+			"# Build the full chain file and configure the service to use it",
+			"# (e.g., fullchain.pem instead of cert.pem for certbot managed certificates)",
+			fmt.Sprintf("cat %s intermediate.pem > %s", shellQuote(leafFile), shellQuote(fullChainFile)),
+			fmt.Sprintf("openssl verify -untrusted intermediate.pem %s", shellQuote(leafFile)),
+		)
+
+		remediations = append(remediations, remediation{
+			problem:  "Missing intermediate certificate",
+			commands: commands,
+		})
+	}
+
+	if certs.IsMisorderedChain(certChain) {
+		intermediatesFile := prefix + "-intermediates.pem"
+
+		remediations = append(remediations, remediation{
+			problem: "Misordered certificate chain",
+			commands: []string{
+				"# Save the leaf and intermediate certificates separately",
+				source.cpcertCommand("leaf", leafFile),
+				source.cpcertCommand("intermediate", intermediatesFile),
+				"# Rebuild the chain file with the leaf certificate first",
+				fmt.Sprintf(
+					"cat %s %s > %s",
+					shellQuote(leafFile),
+					shellQuote(intermediatesFile),
+					shellQuote(fullChainFile),
+				),
+				"# Confirm that each certificate is followed by its issuer",
+				fmt.Sprintf(
+					"openssl crl2pkcs7 -nocrl -certfile %s | openssl pkcs7 -print_certs -noout",
+					shellQuote(fullChainFile),
+				),
+			},
+		})
+	}
+
+	leafCerts := certs.LeafCerts(certChain)
+	if len(leafCerts) > 0 && certs.IsExpiredCert(leafCerts[0]) {
+		host := source.host()
+		if host == "" {
+			host = leafCerts[0].Subject.CommonName
+		}
+
+		commands := []string{
+			"# Renew a certbot managed certificate",
+			fmt.Sprintf("certbot renew --cert-name %s", shellQuote(host)),
+			"# Or generate a new key and CSR to submit to the issuing CA",
+			fmt.Sprintf(
+				"openssl req -new -newkey rsa:2048 -nodes -keyout %s -out %s -subj %s -addext %s",
+				shellQuote(prefix+".key"),
+				shellQuote(prefix+".csr"),
+				shellQuote("/CN="+host),
+				shellQuote("subjectAltName=DNS:"+host),
+			),
+		}
+
+		if sClient := source.sClientCommand(); sClient != "" {
+			commands = append(
+				commands,
+				"# After installing the certificate and reloading the service, confirm the new expiration date",
+				sClient+" 2>/dev/null | openssl x509 -noout -subject -enddate",
+			)
+		}
+
+		remediations = append(remediations, remediation{
+			problem:  "Expired leaf certificate",
+			commands: commands,
+		})
+	}
+
+	return remediations
+}
+
+// remediationReport provides a section of the detailed output listing the
+// given remediation commands. An empty string is returned if there are no
+// remediation commands to list.
+func remediationReport(remediations []remediation) string {
+	if len(remediations) == 0 {
+		return ""
+	}
+
+	var report strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&report,
+		"%s**REMEDIATION**%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	for _, r := range remediations {
+		_, _ = fmt.Fprintf(
+			&report,
+			"%s* %s:%s",
+			nagios.CheckOutputEOL,
+			r.problem,
+			nagios.CheckOutputEOL,
+		)
+
+		for _, cmd := range r.commands {
+			switch {
+			case strings.HasPrefix(cmd, "#"):
+				_, _ = fmt.Fprintf(&report, "  %s%s", cmd, nagios.CheckOutputEOL)
+			default:
+				_, _ = fmt.Fprintf(&report, "  $ %s%s", cmd, nagios.CheckOutputEOL)
+			}
+		}
+	}
+
+	return report.String()
+}
+
+// shellQuote quotes the given value for use as a single shell argument if it
+// contains characters with special meaning to the shell.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return false
+		default:
+			return !strings.ContainsRune("-_.:/@%+=,[]", r)
+		}
+	}) == -1 {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRemediationTestCert generates a certificate with the given Common Name
+// issued by the given parent (and parent key) or self-signed if parent is
+// nil. If provided, modify is applied to the certificate template before the
+// certificate is created.
+func newRemediationTestCert(t *testing.T, serial int64, commonName string, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, modify func(*x509.Certificate)) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(90 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	switch {
+	case isCA:
+		template.KeyUsage = x509.KeyUsageCertSign
+	default:
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.DNSNames = []string{commonName}
+	}
+
+	if modify != nil {
+		modify(template)
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate %q: %v", commonName, err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate %q: %v", commonName, err)
+	}
+
+	return cert, key
+}
+
+// TestChainRemediations asserts that remediation commands are provided for
+// each detected certificate chain problem using the certificate chain
+// source details.
+func TestChainRemediations(t *testing.T) {
+	root, rootKey := newRemediationTestCert(t, 1, "Test Root CA", true, nil, nil, nil)
+	intermediate, intermediateKey := newRemediationTestCert(t, 2, "Test Intermediate CA", true, root, rootKey, nil)
+	leaf, _ := newRemediationTestCert(t, 3, "www.example.com", false, intermediate, intermediateKey, func(c *x509.Certificate) {
+		c.IssuingCertificateURL = []string{"http://ca.example.com/intermediate.der"}
+	})
+	expiredLeaf, _ := newRemediationTestCert(t, 4, "expired.example.com", false, intermediate, intermediateKey, func(c *x509.Certificate) {
+		c.NotBefore = time.Now().Add(-48 * time.Hour)
+		c.NotAfter = time.Now().Add(-24 * time.Hour)
+	})
+
+	serverSource := remediationSource{
+		server:  "192.0.2.10",
+		dnsName: "www.example.com",
+		port:    8443,
+	}
+
+	fileSource := remediationSource{
+		filename: "/tmp/my certs.pem",
+	}
+
+	tests := []struct {
+		name         string
+		certChain    []*x509.Certificate
+		source       remediationSource
+		wantProblems []string
+		wantCommands []string
+		omitCommands []string
+	}{
+		{
+			name:      "ValidChain",
+			certChain: []*x509.Certificate{leaf, intermediate, root},
+			source:    serverSource,
+		},
+		{
+			name:         "MissingIntermediate",
+			certChain:    []*x509.Certificate{leaf},
+			source:       serverSource,
+			wantProblems: []string{"Missing intermediate certificate"},
+			wantCommands: []string{
+				"cpcert --port 8443 --dns-name www.example.com --keep leaf 192.0.2.10 www.example.com-leaf.pem",
+				"curl -fsSL -o intermediate.der http://ca.example.com/intermediate.der",
+				"cat www.example.com-leaf.pem intermediate.pem > www.example.com-fullchain.pem",
+			},
+		},
+		{
+			name:         "MisorderedChain",
+			certChain:    []*x509.Certificate{leaf, root, intermediate},
+			source:       serverSource,
+			wantProblems: []string{"Misordered certificate chain"},
+			wantCommands: []string{
+				"cpcert --port 8443 --dns-name www.example.com --keep intermediate 192.0.2.10 www.example.com-intermediates.pem",
+			},
+		},
+		{
+			name:         "ExpiredLeafFromFile",
+			certChain:    []*x509.Certificate{expiredLeaf, intermediate, root},
+			source:       fileSource,
+			wantProblems: []string{"Expired leaf certificate"},
+			wantCommands: []string{
+				"certbot renew --cert-name expired.example.com",
+				"-keyout server.key -out server.csr -subj /CN=expired.example.com -addext subjectAltName=DNS:expired.example.com",
+			},
+			omitCommands: []string{"openssl s_client"},
+		},
+		{
+			name:         "ExpiredLeafFromServer",
+			certChain:    []*x509.Certificate{expiredLeaf},
+			source:       serverSource,
+			wantProblems: []string{"Missing intermediate certificate", "Expired leaf certificate"},
+			wantCommands: []string{
+				"certbot renew --cert-name www.example.com",
+				"openssl s_client -connect 192.0.2.10:8443 -servername www.example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			remediations := chainRemediations(tt.certChain, tt.source)
+
+			var problems []string
+			for _, r := range remediations {
+				problems = append(problems, r.problem)
+			}
+
+			if strings.Join(problems, ",") != strings.Join(tt.wantProblems, ",") {
+				t.Fatalf("problems = %v, want %v", problems, tt.wantProblems)
+			}
+
+			report := remediationReport(remediations)
+			if len(remediations) == 0 && report != "" {
+				t.Errorf("report = %q, want empty report", report)
+			}
+
+			for _, cmd := range tt.wantCommands {
+				if !strings.Contains(report, cmd) {
+					t.Errorf("report missing %q:\n%s", cmd, report)
+				}
+			}
+
+			for _, cmd := range tt.omitCommands {
+				if strings.Contains(report, cmd) {
+					t.Errorf("report unexpectedly contains %q:\n%s", cmd, report)
+				}
+			}
+		})
+	}
+}
+
+// TestShellQuote asserts that values are quoted only when they contain
+// characters with special meaning to the shell.
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "PlainHostname", input: "www.example.com", want: "www.example.com"},
+		{name: "HostAndPort", input: "[2001:db8::1]:443", want: "[2001:db8::1]:443"},
+		{name: "Empty", input: "", want: "''"},
+		{name: "Space", input: "my certs.pem", want: "'my certs.pem'"},
+		{name: "SingleQuote", input: "it's.pem", want: `'it'\''s.pem'`},
+		{name: "CommandSubstitution", input: "$(id)", want: "'$(id)'"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shellQuote(tt.input); got != tt.want {
+				t.Errorf("shellQuote(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// reportSANsDelta compares the leaf certificate in the given chain against
+// the leaf certificate recorded in the sysadmin-specified state file. If the
+// leaf certificate has changed, SANs entries added or removed by the new
+// certificate are noted in the detailed output. The state file is then
+// updated to record the current leaf certificate.
+//
+// Problems with the state file are logged but do not change the plugin
+// state; the comparison is informational and not part of the check.
+func reportSANsDelta(plugin *nagios.Plugin, cfg *config.Config, certChain []*x509.Certificate, log zerolog.Logger) {
+	if cfg.StateFile == "" {
+		return
+	}
+
+	leafCerts := certs.LeafCerts(certChain)
+	if len(leafCerts) == 0 {
+		log.Debug().Msg("No leaf certificate found, skipping SANs entries comparison")
+
+		return
+	}
+
+	current := certs.NewObservedCert(leafCerts[0], time.Now())
+
+	previous, loadErr := certs.LoadObservedCertFile(cfg.StateFile)
+	switch {
+	case errors.Is(loadErr, fs.ErrNotExist):
+		log.Debug().
+			Str("state_file", cfg.StateFile).
+			Msg("State file not found, recording leaf certificate for the first time")
+
+	case loadErr != nil:
+		log.Error().
+			Err(loadErr).
+			Str("state_file", cfg.StateFile).
+			Msg("Failed to load state file, replacing with current leaf certificate")
+
+	case previous.SameCert(current):
+		log.Debug().
+			Str("state_file", cfg.StateFile).
+			Msg("Leaf certificate unchanged since previous observation")
+
+	default:
+		added, removed := certs.SANsEntriesDelta(previous.SANsEntries, current.SANsEntries)
+
+		log.Debug().
+			Str("previous_serial", previous.SerialNumber).
+			Str("current_serial", current.SerialNumber).
+			Strs("sans_added", added).
+			Strs("sans_removed", removed).
+			Msg("Leaf certificate changed since previous observation")
+
+		plugin.LongServiceOutput += sansDeltaReport(previous, current, added, removed)
+	}
+
+	if err := certs.SaveObservedCertFile(cfg.StateFile, current); err != nil {
+		log.Error().
+			Err(err).
+			Str("state_file", cfg.StateFile).
+			Msg("Failed to update state file")
+	}
+}
+
+// sansDeltaReport generates a summary of the SANs entries added or removed
+// when the leaf certificate changed between observations.
+func sansDeltaReport(previous certs.ObservedCert, current certs.ObservedCert, added []string, removed []string) string {
+	var report strings.Builder
+
+	fmt.Fprintf(
+		&report,
+		"%sNOTE: Leaf certificate changed since previous observation at %s (serial %s replaced by %s).",
+		nagios.CheckOutputEOL+nagios.CheckOutputEOL,
+		previous.ObservedAt.Format(time.RFC3339),
+		previous.SerialNumber,
+		current.SerialNumber,
+	)
+
+	if len(added) == 0 && len(removed) == 0 {
+		fmt.Fprintf(&report, "%s* SANs entries unchanged", nagios.CheckOutputEOL)
+
+		return report.String()
+	}
+
+	if len(added) > 0 {
+		fmt.Fprintf(
+			&report,
+			"%s* SANs entries added (%d): %s",
+			nagios.CheckOutputEOL,
+			len(added),
+			strings.Join(added, ", "),
+		)
+	}
+
+	if len(removed) > 0 {
+		fmt.Fprintf(
+			&report,
+			"%s* SANs entries removed (%d): %s",
+			nagios.CheckOutputEOL,
+			len(removed),
+			strings.Join(removed, ", "),
+		)
+	}
+
+	return report.String()
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/rs/zerolog"
+)
+
+// expandServer expands the given sysadmin-specified server value in order to
+// obtain an IP Address for certificate chain retrieval. If the server value
+// cannot be used, an error is returned along with a brief message suitable
+// for use as the plugin service output. Name resolution is performed using
+// the given resolver and is abandoned if not completed within the given
+// timeout.
+func expandServer(server string, resolver *net.Resolver, dnsTimeout time.Duration, log zerolog.Logger) (netutils.HostPattern, string, error) {
+	log.Debug().Msg("Expanding given host pattern in order to obtain IP Address")
+	expandedHost, expandErr := netutils.ExpandHostWithResolver(server, resolver, dnsTimeout)
+	switch {
+	case expandErr != nil:
+		log.Error().Err(expandErr).Msg(
+			"Error expanding given host pattern")
+
+		return netutils.HostPattern{}, fmt.Sprintf(
+			"Error expanding given host pattern %q to target IP Address",
+			server,
+		), expandErr
+
+	// Fail early for IP Ranges. While we could just grab the first
+	// expanded IP Address, this may be a potential source of confusion
+	// best avoided.
+	case expandedHost.Range:
+		invalidHostPatternErr := errors.New("invalid host pattern")
+		msg := "Given host pattern invalid; " +
+			"host pattern is a CIDR or partial IP range"
+		log.Error().Err(invalidHostPatternErr).Msg(msg)
+
+		return netutils.HostPattern{}, msg, invalidHostPatternErr
+
+	case len(expandedHost.Expanded) == 0:
+		expandHostErr := errors.New("host pattern expansion failed")
+		msg := "Error expanding given host value to IP Address"
+
+		log.Error().Err(expandHostErr).Msg(msg)
+
+		return netutils.HostPattern{}, msg, expandHostErr
+
+	case len(expandedHost.Expanded) > 1:
+
+		ipAddrs := zerolog.Arr()
+		for _, ip := range expandedHost.Expanded {
+			ipAddrs.Str(ip)
+		}
+
+		log.Debug().
+			Int("num_ip_addresses", len(expandedHost.Expanded)).
+			Array("ip_addresses", ipAddrs).
+			Msg("Multiple IP Addresses resolved from given host pattern")
+		log.Debug().Msg("Using first IP Address, ignoring others")
+	}
+
+	return expandedHost, "", nil
+}
+
+// serverHostValue returns the host value used for a SNI-enabled certificate
+// retrieval attempt along with a description of the certificate chain
+// source for the given DNS Name, expanded server value, IP Address and port.
+//
+// Server Name Indication (SNI) support is used to request a specific
+// certificate chain from a remote server.
+//
+// We use the value specified by the `server` flag to open a connection to
+// the remote server. If available, we use the DNS Name value specified by
+// the DNA Name flag as our host value, otherwise we fallback to using the
+// value specified by the server flag as our host value.
+//
+// For a service with only one certificate chain the host value is less
+// important, but for a host with multiple certificate chains having the
+// correct host value is crucial.
+func serverHostValue(dnsName string, expandedHost netutils.HostPattern, ipAddr string, port int) (string, string) {
+	switch {
+
+	// We have a resolved IP Address and a sysadmin-specified DNS Name
+	// value to use for a SNI-enabled certificate retrieval attempt.
+	case expandedHost.Resolved && dnsName != "":
+		return dnsName, fmt.Sprintf(
+			"service running on %s (%s) at port %d using host value %q",
+			expandedHost.Given,
+			ipAddr,
+			port,
+			dnsName,
+		)
+
+	// We have a valid IP Address to use for opening the connection and a
+	// sysadmin-specified DNS Name value to use for a SNI-enabled
+	// certificate retrieval attempt.
+	case dnsName != "":
+		return dnsName, fmt.Sprintf(
+			"service running on %s at port %d using host value %q",
+			ipAddr,
+			port,
+			dnsName,
+		)
+
+	// We have a resolved IP Address, but not a sysadmin-specified DNS
+	// Name value. We'll use the resolvable name/FQDN for a SNI-enabled
+	// certificate retrieval attempt.
+	case expandedHost.Resolved && dnsName == "":
+		return expandedHost.Given, fmt.Sprintf(
+			"service running on %s (%s) at port %d using host value %q",
+			expandedHost.Given,
+			ipAddr,
+			port,
+			expandedHost.Given,
+		)
+
+	default:
+		return "", fmt.Sprintf(
+			"service running on %s at port %d",
+			ipAddr,
+			port,
+		)
+	}
+}
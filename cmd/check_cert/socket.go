@@ -0,0 +1,338 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/textutils"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// maxSocketRequestSize is the maximum size in bytes of a single request
+// read from the Unix domain socket.
+const maxSocketRequestSize int = 64 * 1024
+
+// ErrInvalidSocketRequest indicates that a request received via the Unix
+// domain socket could not be decoded or is missing required values.
+var ErrInvalidSocketRequest = errors.New("invalid socket request")
+
+// socketRequest is a request to evaluate the certificate chain for a
+// certificate-enabled service received via the Unix domain socket.
+type socketRequest struct {
+	Server  string   `json:"server"`
+	Port    int      `json:"port"`
+	DNSName string   `json:"dns_name"`
+	Tags    []string `json:"tags"`
+}
+
+// socketResponse is the service check result for a request received via the
+// Unix domain socket.
+type socketResponse struct {
+	Server              string   `json:"server"`
+	Port                int      `json:"port"`
+	DNSName             string   `json:"dns_name"`
+	IPAddress           string   `json:"ip_address"`
+	State               string   `json:"state"`
+	ExitCode            int      `json:"exit_code"`
+	CertsRetrieved      int      `json:"certs_retrieved"`
+	LeafNotBefore       int64    `json:"leaf_not_before_epoch"`
+	LeafNotAfter        int64    `json:"leaf_not_after_epoch"`
+	LeafNotAfterRFC3339 string   `json:"leaf_not_after"`
+	LeafNotAfterHuman   string   `json:"leaf_not_after_human"`
+	LeafDaysLeft        int      `json:"leaf_days_remaining"`
+	FailedChecks        []string `json:"failed_checks"`
+	Errors              []string `json:"errors"`
+	CertChainSource     string   `json:"cert_chain_source"`
+}
+
+// unknownSocketResponse returns the response for a request which could not
+// be evaluated.
+func unknownSocketResponse(err error) socketResponse {
+	return socketResponse{
+		State:        nagios.StateUNKNOWNLabel,
+		ExitCode:     nagios.StateUNKNOWNExitCode,
+		FailedChecks: []string{},
+		Errors:       []string{err.Error()},
+	}
+}
+
+// newSocketResponse converts the given target check result into its
+// machine-readable representation.
+func newSocketResponse(cfg *config.Config, result targetCheckResult) socketResponse {
+	resp := socketResponse{
+		Server:          result.target.Server,
+		Port:            result.target.Port,
+		DNSName:         result.target.DNSName,
+		IPAddress:       result.ipAddr,
+		State:           result.state.Label,
+		ExitCode:        result.state.ExitCode,
+		CertsRetrieved:  len(result.certChain),
+		CertChainSource: result.certChainSource,
+		FailedChecks:    textutils.DedupeList(result.validationResults.NotOKCheckNames()),
+		Errors:          []string{},
+	}
+	sort.Strings(resp.FailedChecks)
+
+	for _, err := range result.errs(cfg.ListIgnoredValidationCheckResultErrors) {
+		resp.Errors = append(resp.Errors, err.Error())
+	}
+
+	if leaf := certs.OldestLeafCert(result.certChain); leaf != nil {
+		resp.LeafNotBefore = leaf.NotBefore.Unix()
+		resp.LeafNotAfter = leaf.NotAfter.Unix()
+		resp.LeafNotAfterRFC3339 = leaf.NotAfter.UTC().Format(time.RFC3339)
+		resp.LeafNotAfterHuman = leaf.NotAfter.Format(certs.CertValidityDateLayout)
+		resp.LeafDaysLeft, _ = certs.ExpiresInDays(leaf)
+	}
+
+	return resp
+}
+
+// socketTarget validates the given request and converts it to a target.
+func socketTarget(cfg *config.Config, req socketRequest) (netutils.Target, error) {
+	req.Server = strings.TrimSpace(req.Server)
+	if req.Server == "" {
+		return netutils.Target{}, fmt.Errorf(
+			"server value not specified: %w",
+			ErrInvalidSocketRequest,
+		)
+	}
+
+	switch {
+	case req.Port == 0:
+		req.Port = cfg.Port
+	case req.Port < 1 || req.Port > 65535:
+		return netutils.Target{}, fmt.Errorf(
+			"invalid port %d specified: %w",
+			req.Port,
+			ErrInvalidSocketRequest,
+		)
+	}
+
+	return netutils.Target{
+		Server:  req.Server,
+		Port:    req.Port,
+		DNSName: strings.TrimSpace(req.DNSName),
+		Tags:    req.Tags,
+	}, nil
+}
+
+// handleSocketRequest evaluates the certificate chain for the service
+// specified by the given encoded request. Each request is evaluated with a
+// separate network budget and (if a plugin timeout was specified) deadline.
+func handleSocketRequest(
+	cfg *config.Config,
+	line []byte,
+	policies targetPolicies,
+	blocklist certs.CertBlocklist,
+	log zerolog.Logger,
+) socketResponse {
+	var req socketRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return unknownSocketResponse(fmt.Errorf("%w: %v", ErrInvalidSocketRequest, err))
+	}
+
+	target, err := socketTarget(cfg, req)
+	if err != nil {
+		return unknownSocketResponse(err)
+	}
+
+	var deadline time.Time
+	if cfg.PluginTimeout() > 0 {
+		deadline = time.Now().Add(cfg.PluginTimeout())
+	}
+
+	netBudget := budget.New(cfg.MaxExternalRequests, cfg.MaxDownloadBytes)
+	result := evaluateTarget(cfg, target, targetLabel(target), policies, blocklist, netBudget, deadline, log)
+
+	return newSocketResponse(cfg, result)
+}
+
+// serveSocketConn reads newline-delimited JSON requests from the given
+// connection and writes a newline-delimited JSON response for each until
+// the client closes the connection. The given rate limiter caps the number
+// of requests evaluated concurrently across all connections and the number
+// of requests handled is recorded using the given counter.
+func serveSocketConn(
+	conn net.Conn,
+	cfg *config.Config,
+	policies targetPolicies,
+	blocklist certs.CertBlocklist,
+	rateLimiter chan struct{},
+	handled *atomic.Int64,
+	log zerolog.Logger,
+) {
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Debug().Err(err).Msg("Error closing socket connection")
+		}
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxSocketRequestSize)
+	enc := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		rateLimiter <- struct{}{}
+		resp := handleSocketRequest(cfg, line, policies, blocklist, log)
+		<-rateLimiter
+		handled.Add(1)
+
+		log.Debug().
+			Str("server", resp.Server).
+			Int("port", resp.Port).
+			Str("state", resp.State).
+			Msg("Socket request evaluated")
+
+		if err := enc.Encode(resp); err != nil {
+			log.Error().Err(err).Msg("Error writing socket response")
+
+			return
+		}
+	}
+
+	// Connections are closed on shutdown.
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		log.Error().Err(err).Msg("Error reading socket request")
+
+		// Let the client know why the connection is being closed.
+		_ = enc.Encode(unknownSocketResponse(fmt.Errorf("%w: %v", ErrInvalidSocketRequest, err)))
+	}
+}
+
+// runSocketServer accepts requests via the Unix domain socket specified by
+// the socket flag until interrupted. The plugin output notes the number of
+// requests handled or the error which prevented serving requests.
+func runSocketServer(
+	plugin *nagios.Plugin,
+	cfg *config.Config,
+	policies targetPolicies,
+	blocklist certs.CertBlocklist,
+	log zerolog.Logger,
+) {
+	log = log.With().Str("socket", cfg.Socket).Logger()
+
+	// A socket left behind by a previous run is replaced; config validation
+	// asserts that any existing file is a socket.
+	if err := os.Remove(cfg.Socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error().Err(err).Msg("Error removing existing socket")
+	}
+
+	listener, err := net.Listen("unix", cfg.Socket)
+	if err != nil {
+		log.Error().Err(err).Msg("Error listening on socket")
+
+		plugin.AddError(err)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error listening on socket %q",
+			nagios.StateUNKNOWNLabel,
+			cfg.Socket,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		log.Info().Msg("Shutdown requested, closing socket")
+
+		if err := listener.Close(); err != nil {
+			log.Error().Err(err).Msg("Error closing socket")
+		}
+	}()
+
+	log.Info().Msg("Accepting requests via socket")
+
+	var handled atomic.Int64
+	var wg sync.WaitGroup
+	rateLimiter := make(chan struct{}, maxConcurrentTargetChecks)
+
+	for {
+		conn, acceptErr := listener.Accept()
+		if acceptErr != nil {
+			if ctx.Err() == nil {
+				err = acceptErr
+				log.Error().Err(acceptErr).Msg("Error accepting socket connection")
+			}
+
+			break
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// Idle connections are closed on shutdown; requests already
+			// being evaluated are completed, but the response is discarded.
+			connDone := make(chan struct{})
+			defer close(connDone)
+
+			go func() {
+				select {
+				case <-ctx.Done():
+					_ = conn.Close()
+				case <-connDone:
+				}
+			}()
+
+			serveSocketConn(conn, cfg, policies, blocklist, rateLimiter, &handled, log)
+		}()
+	}
+
+	wg.Wait()
+
+	if err != nil {
+		plugin.AddError(err)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error accepting connections on socket %q after %d requests",
+			nagios.StateUNKNOWNLabel,
+			cfg.Socket,
+			handled.Load(),
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return
+	}
+
+	plugin.ServiceOutput = fmt.Sprintf(
+		"%s: Socket %q closed after %d requests",
+		nagios.StateOKLabel,
+		cfg.Socket,
+		handled.Load(),
+	)
+	plugin.ExitStatusCode = nagios.StateOKExitCode
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// ErrSimulatedState indicates that the service check state was simulated
+// via the simulate-state flag instead of reflecting the evaluation results.
+var ErrSimulatedState = errors.New("simulated service check state")
+
+// certFetchFailureState returns the service check state for a failed attempt
+// to retrieve a certificate chain. If requested, connection failures are
+// reported using the DEPENDENT state so that Nagios dependency logic can
+// suppress notifications when a parent dependency is unavailable.
+func certFetchFailureState(cfg *config.Config, err error) nagios.ServiceState {
+	if cfg.DependentOnConnectFailure && netutils.IsConnectionFailure(err) {
+		return nagios.ServiceState{
+			Label:    nagios.StateDEPENDENTLabel,
+			ExitCode: nagios.StateDEPENDENTExitCode,
+		}
+	}
+
+	return nagios.ServiceState{
+		Label:    nagios.StateCRITICALLabel,
+		ExitCode: nagios.StateCRITICALExitCode,
+	}
+}
+
+// applyStateMappings applies any sysadmin-specified service check state
+// overrides to the final plugin state. The original state is noted in the
+// detailed output so that the override is not a source of confusion.
+func applyStateMappings(plugin *nagios.Plugin, cfg *config.Config, log zerolog.Logger) {
+	mappings := cfg.StateMappings()
+	if len(mappings) == 0 {
+		return
+	}
+
+	origState := nagios.ExitCodeToStateLabel(plugin.ExitStatusCode)
+
+	newState, ok := mappings[origState]
+	if !ok || newState == origState {
+		return
+	}
+
+	log.Debug().
+		Str("original_state", origState).
+		Str("new_state", newState).
+		Msg("Applying service check state override")
+
+	plugin.ExitStatusCode = nagios.StateLabelToExitCode(newState)
+
+	// The one-line summary is prefixed with the original state label; swap
+	// it for the new state label so that the summary agrees with the exit
+	// code.
+	summary := strings.TrimPrefix(plugin.ServiceOutput, origState+": ")
+	plugin.ServiceOutput = fmt.Sprintf("%s: %s", newState, summary)
+
+	plugin.LongServiceOutput = fmt.Sprintf(
+		"%s%s%s",
+		plugin.LongServiceOutput,
+		nagios.CheckOutputEOL+nagios.CheckOutputEOL,
+		stateMappingNote(origState, newState),
+	)
+}
+
+// stateMappingNote returns the note added to the detailed output when the
+// original service check state is overridden via the map-state flag.
+func stateMappingNote(origState string, newState string) string {
+	return fmt.Sprintf(
+		"NOTE: %s service check state overridden as %s via the %q flag.",
+		origState,
+		newState,
+		config.MapStateFlag,
+	)
+}
+
+// simulatedStateNote returns the note added to the detailed output when the
+// actual service check state is replaced via the simulate-state flag.
+func simulatedStateNote(origState string, simState string) string {
+	return fmt.Sprintf(
+		"NOTE: %s service check state simulated as %s via the %q flag.",
+		origState,
+		simState,
+		config.SimulateStateFlag,
+	)
+}
+
+// applyBriefWhenOK omits the detailed report output for an OK result if
+// requested so that only the one-line summary and performance data are
+// emitted. Notes describing service check state overrides or simulations are
+// retained so that an OK result for a problematic certificate chain is not a
+// source of confusion. The detailed report output is left as-is for all
+// other results.
+func applyBriefWhenOK(plugin *nagios.Plugin, cfg *config.Config, log zerolog.Logger) {
+	if !cfg.BriefWhenOK || plugin.ExitStatusCode != nagios.StateOKExitCode {
+		return
+	}
+
+	log.Debug().Msg("Omitting detailed report output for OK result")
+
+	var mappingNotes []string
+	var simulatedNotes []string
+	for _, origState := range nagios.SupportedStateLabels() {
+		for _, newState := range nagios.SupportedStateLabels() {
+			if note := stateMappingNote(origState, newState); strings.Contains(plugin.LongServiceOutput, note) {
+				mappingNotes = append(mappingNotes, note)
+			}
+
+			if note := simulatedStateNote(origState, newState); strings.Contains(plugin.LongServiceOutput, note) {
+				simulatedNotes = append(simulatedNotes, note)
+			}
+		}
+	}
+
+	// State overrides are applied before a simulated state.
+	plugin.LongServiceOutput = strings.Join(
+		append(mappingNotes, simulatedNotes...),
+		nagios.CheckOutputEOL+nagios.CheckOutputEOL,
+	)
+}
+
+// applySimulatedState replaces the final plugin state and one-line summary
+// with the sysadmin-specified simulated state and message so that
+// notification pipelines and dashboards can be verified end-to-end. The
+// detailed output and certificate metadata payload (if requested) reflect
+// the actual evaluation; the actual state is noted in the detailed output so
+// that the simulation is not a source of confusion.
+func applySimulatedState(plugin *nagios.Plugin, cfg *config.Config, log zerolog.Logger) {
+	if cfg.SimulateState == "" {
+		return
+	}
+
+	origState := nagios.ExitCodeToStateLabel(plugin.ExitStatusCode)
+	simState := strings.ToUpper(cfg.SimulateState)
+
+	message := cfg.SimulateMessage
+	if message == "" {
+		message = fmt.Sprintf("Simulated %s state for notification pipeline validation", simState)
+	}
+
+	log.Debug().
+		Str("original_state", origState).
+		Str("simulated_state", simState).
+		Str("simulated_message", message).
+		Msg("Applying simulated service check state")
+
+	plugin.AddError(fmt.Errorf("%s: %w", message, ErrSimulatedState))
+
+	plugin.ExitStatusCode = nagios.StateLabelToExitCode(simState)
+	plugin.ServiceOutput = fmt.Sprintf("%s: %s", simState, message)
+
+	plugin.LongServiceOutput = fmt.Sprintf(
+		"%s%s%s",
+		plugin.LongServiceOutput,
+		nagios.CheckOutputEOL+nagios.CheckOutputEOL,
+		simulatedStateNote(origState, simState),
+	)
+}
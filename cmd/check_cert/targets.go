@@ -0,0 +1,645 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atc0005/cert-payload/input"
+	"github.com/atc0005/check-cert/internal/aggregate"
+	"github.com/atc0005/check-cert/internal/budget"
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/validation"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// maxConcurrentTargetChecks is the maximum number of targets evaluated at
+// the same time.
+const maxConcurrentTargetChecks int = 10
+
+// targetCheckResult is the outcome of retrieving and evaluating the
+// certificate chain for a single target.
+type targetCheckResult struct {
+	// target is the certificate-enabled service evaluated.
+	target netutils.Target
+
+	// cfg is the configuration used to evaluate the target. This reflects
+	// the settings of the policy applied to the target (if any).
+	cfg *config.Config
+
+	// policyName is the name of the policy applied to the target (if any).
+	policyName string
+
+	// label identifies the target in errors and the detailed output (e.g.,
+	// "port 443" or "www.example.com:443").
+	label string
+
+	// ipAddr is the IP Address used to retrieve the certificate chain.
+	ipAddr string
+
+	// certChainSource describes where the certificate chain was retrieved
+	// from.
+	certChainSource string
+
+	// certChain is the certificate chain retrieved from the target.
+	certChain []*x509.Certificate
+
+	// resolveTime is the time taken to resolve the target server value to an
+	// IP Address.
+	resolveTime time.Duration
+
+	// retrievalStats records details of the connection attempts made to
+	// retrieve the certificate chain.
+	retrievalStats netutils.CertRetrievalStats
+
+	// validationResults is the collection of validation check results for
+	// the certificate chain.
+	validationResults certs.CertChainValidationResults
+
+	// timings records the time taken to perform each validation check.
+	timings validation.Timings
+
+	// err is the error (if any) encountered retrieving the certificate
+	// chain or which prevented evaluating it.
+	err error
+
+	// state is the service check state for this target.
+	state nagios.ServiceState
+}
+
+// errs returns the errors recorded for this target, each annotated with the
+// target label. If specified, errors for ignored validation check results are
+// also returned.
+func (tcr targetCheckResult) errs(includeIgnored bool) []error {
+	if tcr.err != nil {
+		return []error{fmt.Errorf("%s: %w", tcr.label, tcr.err)}
+	}
+
+	validationErrs := tcr.validationResults.Errs(includeIgnored)
+	errs := make([]error, 0, len(validationErrs))
+	for _, err := range validationErrs {
+		errs = append(errs, fmt.Errorf("%s: %w", tcr.label, err))
+	}
+
+	return errs
+}
+
+// report provides the detailed output section for this target.
+func (tcr targetCheckResult) report(heading string) string {
+	header := fmt.Sprintf(
+		"**%s: %s**%s%s",
+		heading,
+		tcr.state.Label,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	if errors.Is(tcr.err, certs.ErrSystemClockSuspect) {
+		return fmt.Sprintf(
+			"%sSkipped evaluating certificates for %s: %v",
+			header,
+			tcr.certChainSource,
+			tcr.err,
+		)
+	}
+
+	if tcr.err != nil {
+		return fmt.Sprintf(
+			"%sError fetching certificates for %s: %v",
+			header,
+			tcr.certChainSource,
+			tcr.err,
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s%d certs retrieved for %s%s%s",
+		header,
+		len(tcr.certChain),
+		tcr.certChainSource,
+		nagios.CheckOutputEOL,
+		tcr.validationResults.Report(),
+	)
+}
+
+// serviceStateSeverity ranks the given service check state for the purpose
+// of determining the most severe state across multiple results. Higher
+// values indicate a more severe state.
+func serviceStateSeverity(state nagios.ServiceState) int {
+	switch state.ExitCode {
+	case nagios.StateCRITICALExitCode:
+		return 4
+	case nagios.StateWARNINGExitCode:
+		return 3
+	case nagios.StateUNKNOWNExitCode:
+		return 2
+	case nagios.StateDEPENDENTExitCode:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// aggregateState returns the combined service check state for a collection
+// of targets using the chosen aggregate strategy. The most severe target
+// state is used by the worst strategy. The threshold strategies compare the
+// number of targets with problems (as a count or as a percentage of all
+// targets) against the WARNING and CRITICAL thresholds.
+func aggregateState(cfg *config.Config, worstState nagios.ServiceState, numProblems int, numTargets int) nagios.ServiceState {
+	var exceeds func(threshold int) bool
+
+	switch cfg.AggregateStrategy {
+	case config.AggregateStrategyPercentage:
+		exceeds = func(threshold int) bool {
+			return numTargets > 0 && numProblems*100 >= threshold*numTargets
+		}
+
+	case config.AggregateStrategyCount:
+		exceeds = func(threshold int) bool {
+			return numProblems >= threshold
+		}
+
+	default:
+		return worstState
+	}
+
+	switch {
+	case exceeds(cfg.AggregateCritical()):
+		return nagios.ServiceState{
+			Label:    nagios.StateCRITICALLabel,
+			ExitCode: nagios.StateCRITICALExitCode,
+		}
+
+	case exceeds(cfg.AggregateWarning()):
+		return nagios.ServiceState{
+			Label:    nagios.StateWARNINGLabel,
+			ExitCode: nagios.StateWARNINGExitCode,
+		}
+
+	default:
+		return nagios.ServiceState{
+			Label:    nagios.StateOKLabel,
+			ExitCode: nagios.StateOKExitCode,
+		}
+	}
+}
+
+// targetLabel returns the server and port for the given target in host:port
+// format. If specified, the DNS Name for the target is included as a prefix
+// (e.g., www.example.com@192.168.5.3:443) in order to distinguish between
+// multiple certificate chains served from the same port.
+func targetLabel(target netutils.Target) string {
+	address := net.JoinHostPort(target.Server, strconv.Itoa(target.Port))
+
+	if target.DNSName != "" && target.DNSName != target.Server {
+		return target.DNSName + "@" + address
+	}
+
+	return address
+}
+
+// loadTargets returns the collection of certificate-enabled services to
+// evaluate when a list of ports or a targets file is specified. Targets file
+// entries without a port are evaluated using each specified port (or the
+// single port flag value). If neither was specified, nil is returned.
+func loadTargets(cfg *config.Config) ([]netutils.Target, error) {
+	ports := cfg.ServerPorts()
+
+	var fileTargets []netutils.Target
+	switch {
+	case cfg.TargetsFile != "":
+		var err error
+		fileTargets, err = netutils.LoadTargetsFile(cfg.TargetsFile)
+		if err != nil {
+			return nil, err
+		}
+
+	case len(ports) > 0:
+		fileTargets = []netutils.Target{{Server: cfg.Server, DNSName: cfg.DNSName}}
+
+	default:
+		return nil, nil
+	}
+
+	if len(ports) == 0 {
+		ports = []int{cfg.Port}
+	}
+
+	targets := make([]netutils.Target, 0, len(fileTargets))
+	for _, target := range fileTargets {
+		if target.Port != 0 {
+			targets = append(targets, target)
+
+			continue
+		}
+
+		for _, port := range ports {
+			target.Port = port
+			targets = append(targets, target)
+		}
+	}
+
+	return targets, nil
+}
+
+// evaluateTarget retrieves and evaluates the certificate chain for the given
+// target using the settings of the first matching policy (if any).
+func evaluateTarget(
+	cfg *config.Config,
+	target netutils.Target,
+	label string,
+	policies targetPolicies,
+	blocklist certs.CertBlocklist,
+	netBudget *budget.Budget,
+	deadline time.Time,
+	log zerolog.Logger,
+) targetCheckResult {
+	log = log.With().
+		Str("server", target.Server).
+		Str("dns_name", target.DNSName).
+		Int("port", target.Port).
+		Logger()
+
+	cfg, policyName := policies.configFor(cfg, target.Server, target.DNSName, target.Tags, log)
+
+	result := targetCheckResult{
+		target:     target,
+		label:      label,
+		cfg:        cfg,
+		policyName: policyName,
+	}
+
+	expandedHost, expandMsg, expandErr := expandServer(target.Server, cfg.Resolver(), cfg.DNSTimeout(), log)
+	if expandErr != nil {
+		result.certChainSource = fmt.Sprintf(
+			"service running on %s at port %d",
+			target.Server,
+			target.Port,
+		)
+		result.certChainSource = withPolicyName(result.certChainSource, policyName)
+		result.err = fmt.Errorf("%s: %w", expandMsg, expandErr)
+		result.state = nagios.ServiceState{
+			Label:    nagios.StateCRITICALLabel,
+			ExitCode: nagios.StateCRITICALExitCode,
+		}
+
+		return result
+	}
+
+	// Grab first IP Address from the resolved collection. We'll explicitly
+	// use it for cert retrieval and note it in the report output.
+	result.ipAddr = expandedHost.Expanded[0]
+	result.resolveTime = expandedHost.ResolveTime
+
+	hostVal, certChainSource := serverHostValue(target.DNSName, expandedHost, result.ipAddr, target.Port)
+	result.certChainSource = withPolicyName(certChainSource, policyName)
+
+	log.Debug().
+		Str("ip_address", result.ipAddr).
+		Str("host_value", hostVal).
+		Msg("Retrieving certificate chain")
+
+	certChain, retrievalStats, certFetchErr := netutils.GetCertsWithOptions(
+		hostVal,
+		result.ipAddr,
+		target.Port,
+		cfg.Timeout(),
+		cfg.CertRetrievalOptions(),
+		log,
+	)
+	result.retrievalStats = retrievalStats
+
+	switch {
+	case certFetchErr != nil:
+		log.Error().Err(certFetchErr).Msg(
+			"Error fetching certificates chain")
+
+		result.err = certFetchErr
+		result.state = certFetchFailureState(cfg, certFetchErr)
+
+		return result
+
+	case len(certChain) == 0:
+		log.Error().Err(certs.ErrNoCertsFound).Msg("No certificates found")
+
+		result.err = certs.ErrNoCertsFound
+		result.state = nagios.ServiceState{
+			Label:    nagios.StateCRITICALLabel,
+			ExitCode: nagios.StateCRITICALExitCode,
+		}
+
+		return result
+	}
+
+	// Every validation check result is suspect if the system clock is
+	// wrong, so the problem is reported once instead of as a collection of
+	// (likely CRITICAL) validation check failures.
+	if clockErr := certs.CheckSystemClockForCertChain(certChain, time.Now()); clockErr != nil {
+		log.Error().Err(clockErr).Msg("System clock appears to be incorrect")
+
+		result.err = clockErr
+		result.state = nagios.ServiceState{
+			Label:    nagios.StateUNKNOWNLabel,
+			ExitCode: nagios.StateUNKNOWNExitCode,
+		}
+
+		return result
+	}
+
+	result.certChain = certChain
+	result.validationResults, result.timings = validation.Run(
+		cfg,
+		target.Server,
+		target.DNSName,
+		validation.Endpoint{
+			HostVal: hostVal,
+			IPAddr:  result.ipAddr,
+			Port:    target.Port,
+		},
+		certChain,
+		blocklist,
+		netBudget,
+		deadline,
+		log,
+	)
+	result.state = result.validationResults.ServiceState()
+
+	log.Debug().
+		Str("state", result.state.Label).
+		Int("checks_total", result.validationResults.Total()).
+		Int("checks_failed", result.validationResults.NumFailed()).
+		Int("checks_ignored", result.validationResults.NumIgnored()).
+		Int("checks_successful", result.validationResults.NumSucceeded()).
+		Msg("Certificate chain evaluated")
+
+	return result
+}
+
+// runTargetsChecks evaluates the certificate chain for each of the given
+// targets and combines the results into a single service check result using
+// the most severe state. A section for each target is included in the
+// detailed output.
+//
+// If all targets share the same server (i.e., multiple ports were specified
+// for a single server) results are labeled by port, otherwise by server and
+// port.
+//
+// The result for each target is returned in the original target order.
+func runTargetsChecks(
+	plugin *nagios.Plugin,
+	cfg *config.Config,
+	targets []netutils.Target,
+	policies targetPolicies,
+	blocklist certs.CertBlocklist,
+	netBudget *budget.Budget,
+	deadline time.Time,
+	log zerolog.Logger,
+) []targetCheckResult {
+	singleServer := true
+	for _, target := range targets {
+		if target.Server != targets[0].Server || target.DNSName != targets[0].DNSName {
+			singleServer = false
+
+			break
+		}
+	}
+
+	labels := make([]string, len(targets))
+	for i, target := range targets {
+		switch {
+		case singleServer:
+			labels[i] = fmt.Sprintf("port %d", target.Port)
+		default:
+			labels[i] = targetLabel(target)
+		}
+	}
+
+	// Evaluate targets concurrently, retaining the original order for
+	// reporting purposes.
+	results := make([]targetCheckResult, len(targets))
+	rateLimiter := make(chan struct{}, maxConcurrentTargetChecks)
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		rateLimiter <- struct{}{}
+
+		go func(i int, target netutils.Target) {
+			defer func() {
+				<-rateLimiter
+				wg.Done()
+			}()
+
+			results[i] = evaluateTarget(cfg, target, labels[i], policies, blocklist, netBudget, deadline, log)
+		}(i, target)
+	}
+
+	wg.Wait()
+
+	worstState := nagios.ServiceState{
+		Label:    nagios.StateOKLabel,
+		ExitCode: nagios.StateOKExitCode,
+	}
+
+	var numProblems int
+	targetStates := make([]string, 0, len(targets))
+	sections := make([]string, 0, len(targets))
+
+	for _, result := range results {
+		if serviceStateSeverity(result.state) > serviceStateSeverity(worstState) {
+			worstState = result.state
+		}
+
+		if result.state.ExitCode != nagios.StateOKExitCode {
+			numProblems++
+		}
+
+		plugin.AddError(result.errs(cfg.ListIgnoredValidationCheckResultErrors)...)
+
+		var heading string
+		var perfDataPrefix string
+		switch {
+		case singleServer:
+			heading = fmt.Sprintf("PORT %d", result.target.Port)
+			perfDataPrefix = fmt.Sprintf("port_%d_", result.target.Port)
+
+			targetStates = append(
+				targetStates,
+				fmt.Sprintf("%d: %s", result.target.Port, result.state.Label),
+			)
+
+		default:
+			heading = "TARGET " + result.label
+			perfDataPrefix = result.label + "_"
+
+			// Only targets with problems are listed in order to keep the
+			// one-line summary readable for large collections of targets.
+			if result.state.ExitCode != nagios.StateOKExitCode {
+				targetStates = append(
+					targetStates,
+					fmt.Sprintf("%s: %s", result.label, result.state.Label),
+				)
+			}
+		}
+
+		section := result.report(heading)
+		if cfg.ShowCheckTimings && len(result.timings) > 0 {
+			section += checkTimings(result.timings).report()
+		}
+		if cfg.ShowRemediation {
+			section += remediationReport(chainRemediations(
+				result.certChain,
+				remediationSource{
+					server:  result.target.Server,
+					dnsName: result.target.DNSName,
+					port:    result.target.Port,
+				},
+			))
+		}
+		sections = append(sections, section)
+
+		if len(result.certChain) == 0 {
+			continue
+		}
+
+		ageCritical, ageWarning := validation.ExpirationThresholds(result.cfg, result.certChain)
+		lifeCritical, lifeWarning := validation.ExpirationPercentThresholds(result.cfg, result.certChain)
+		pd, perfDataErr := getPerfData(result.certChain, ageCritical, ageWarning, lifeCritical, lifeWarning)
+		if perfDataErr != nil {
+			log.Error().
+				Err(perfDataErr).
+				Str("target", result.label).
+				Msg("failed to generate performance data")
+
+			continue
+		}
+
+		pd = append(pd, retrievalPerfData(result.resolveTime, result.retrievalStats)...)
+
+		for i := range pd {
+			pd[i].Label = perfDataPrefix + pd[i].Label
+		}
+
+		if cfg.ShowCheckTimings {
+			pd = append(pd, checkTimings(result.timings).perfData(perfDataPrefix)...)
+		}
+
+		if err := plugin.AddPerfData(false, pd...); err != nil {
+			log.Error().
+				Err(err).
+				Str("target", result.label).
+				Msg("failed to add performance data")
+		}
+	}
+
+	finalState := aggregateState(cfg, worstState, numProblems, len(targets))
+
+	log.Debug().
+		Str("aggregate_strategy", cfg.AggregateStrategy).
+		Str("worst_state", worstState.Label).
+		Str("final_state", finalState.Label).
+		Int("targets_with_problems", numProblems).
+		Msg("Combined target results")
+
+	switch {
+	case singleServer:
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: %d of %d ports on %s with problems [%s]",
+			finalState.Label,
+			numProblems,
+			len(targets),
+			targets[0].Server,
+			strings.Join(targetStates, ", "),
+		)
+
+	case numProblems > 0:
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: %d of %d targets with problems [%s]",
+			finalState.Label,
+			numProblems,
+			len(targets),
+			strings.Join(targetStates, ", "),
+		)
+
+	default:
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: 0 of %d targets with problems",
+			finalState.Label,
+			len(targets),
+		)
+	}
+
+	plugin.LongServiceOutput = strings.Join(
+		sections,
+		nagios.CheckOutputEOL+nagios.CheckOutputEOL,
+	)
+	plugin.ExitStatusCode = finalState.ExitCode
+
+	return results
+}
+
+// targetsCertChains returns the certificate chain retrieved for each of the
+// given target results (empty if not retrieved).
+func targetsCertChains(results []targetCheckResult) [][]*x509.Certificate {
+	certChains := make([][]*x509.Certificate, 0, len(results))
+	for _, result := range results {
+		certChains = append(certChains, result.certChain)
+	}
+
+	return certChains
+}
+
+// addTargetsPayload appends an aggregate payload bundling the certificate
+// metadata payload for each evaluated target to plugin output. The latest
+// plugin state is recorded as the combined service check state.
+func addTargetsPayload(plugin *nagios.Plugin, cfg *config.Config, results []targetCheckResult) error {
+	bundle := aggregate.New(cfg.PayloadFormatVersion)
+	bundle.ServiceState = nagios.ExitCodeToStateLabel(plugin.ExitStatusCode)
+
+	for _, result := range results {
+		ageCritical, ageWarning := validation.ExpirationThresholds(result.cfg, result.certChain)
+
+		inputData := input.Values{
+			CertChain:                            result.certChain,
+			Errors:                               result.errs(cfg.ListIgnoredValidationCheckResultErrors),
+			IncludeFullCertChain:                 cfg.EmitPayloadWithFullChain,
+			OmitSANsEntries:                      cfg.OmitSANsEntries,
+			ExpirationAgeInDaysWarningThreshold:  ageWarning,
+			ExpirationAgeInDaysCriticalThreshold: ageCritical,
+			Server:                               input.Server{HostValue: result.target.Server, IPAddress: result.ipAddr},
+			DNSName:                              result.target.DNSName,
+			TCPPort:                              result.target.Port,
+			ServiceState:                         result.state.Label,
+		}
+
+		if err := bundle.Add(payloadValues(inputData, result.ipAddr, result.retrievalStats)); err != nil {
+			return err
+		}
+	}
+
+	encoded, err := bundle.Encode()
+	if err != nil {
+		return err
+	}
+
+	if _, err := plugin.AddPayloadBytes(encoded); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+
+	"github.com/atc0005/check-cert/internal/validation"
+)
+
+// checkTimings is a collection of validation check timings in the order the
+// validation checks were performed.
+type checkTimings validation.Timings
+
+// perfData returns a performance data metric for each recorded validation
+// check timing. The given prefix is prepended to each metric label.
+func (ct checkTimings) perfData(prefix string) []nagios.PerformanceData {
+	pd := make([]nagios.PerformanceData, 0, len(ct))
+	for _, timing := range ct {
+		pd = append(pd, nagios.PerformanceData{
+			Label:             prefix + "check_time_" + checkTimingSlug(timing.Name),
+			Value:             fmt.Sprintf("%.3f", float64(timing.Duration)/float64(time.Millisecond)),
+			UnitOfMeasurement: "ms",
+		})
+	}
+
+	return pd
+}
+
+// report returns a formatted summary of the recorded validation check
+// timings, slowest first, suitable for use as a footer in the detailed
+// plugin output.
+func (ct checkTimings) report() string {
+	sorted := make(checkTimings, len(ct))
+	copy(sorted, ct)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Duration > sorted[j].Duration
+	})
+
+	var report strings.Builder
+
+	_, _ = fmt.Fprintf(
+		&report,
+		"%s**CHECK TIMINGS**%s%s",
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+		nagios.CheckOutputEOL,
+	)
+
+	for _, timing := range sorted {
+		_, _ = fmt.Fprintf(
+			&report,
+			"* %s: %s%s",
+			timing.Name,
+			timing.Duration.Round(time.Microsecond),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	_, _ = fmt.Fprintf(
+		&report,
+		"* Total: %s%s",
+		validation.Timings(ct).Total().Round(time.Microsecond),
+		nagios.CheckOutputEOL,
+	)
+
+	return report.String()
+}
+
+// checkTimingSlug converts a validation check name into a form suitable for
+// use in a performance data metric label (e.g., "CT Logs" becomes
+// "ct_logs").
+func checkTimingSlug(name string) string {
+	return strings.Map(
+		func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+				return r
+			case r >= 'A' && r <= 'Z':
+				return r + ('a' - 'A')
+			default:
+				return '_'
+			}
+		},
+		name,
+	)
+}
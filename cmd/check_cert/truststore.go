@@ -0,0 +1,323 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/go-nagios"
+	"github.com/rs/zerolog"
+)
+
+// trustStoreProblem describes a certificate from a trust store certificate
+// bundle which is expired, expiring or weak.
+type trustStoreProblem struct {
+	// cert is the certificate with problems.
+	cert *x509.Certificate
+
+	// state is the service check state for the certificate.
+	state nagios.ServiceState
+
+	// reasons is the list of problems found with the certificate.
+	reasons []string
+
+	// expired indicates whether the certificate has expired.
+	expired bool
+
+	// expiring indicates whether the certificate expires within the
+	// CRITICAL or WARNING threshold.
+	expiring bool
+
+	// weak indicates whether the certificate has a weak key or signature
+	// algorithm.
+	weak bool
+}
+
+// trustStoreCertName returns the Subject CommonName for the given
+// certificate or the full Subject if a CommonName is not set (as is common
+// for root certificates).
+func trustStoreCertName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+
+	return cert.Subject.String()
+}
+
+// resolveTrustStoreFile returns the certificate bundle for the given trust
+// store flag value, locating the system trust store certificate bundle if
+// requested.
+func resolveTrustStoreFile(trustStore string) (string, error) {
+	if strings.EqualFold(trustStore, config.TrustStoreSystemKeyword) {
+		return certs.SystemTrustStoreFile()
+	}
+
+	return trustStore, nil
+}
+
+// evaluateTrustStoreCert evaluates a single certificate from a trust store
+// certificate bundle, returning the problems found (if any). The position of
+// the certificate within the bundle determines which expiration thresholds
+// are applied.
+func evaluateTrustStoreCert(
+	cert *x509.Certificate,
+	bundle []*x509.Certificate,
+	thresholds certs.ExpirationThresholds,
+	now time.Time,
+) trustStoreProblem {
+	problem := trustStoreProblem{
+		cert:  cert,
+		state: nagios.ServiceState{Label: nagios.StateOKLabel, ExitCode: nagios.StateOKExitCode},
+	}
+
+	warningState := nagios.ServiceState{Label: nagios.StateWARNINGLabel, ExitCode: nagios.StateWARNINGExitCode}
+	criticalState := nagios.ServiceState{Label: nagios.StateCRITICALLabel, ExitCode: nagios.StateCRITICALExitCode}
+
+	ageCritical, ageWarning := thresholds.ForCert(now, cert, bundle)
+
+	switch {
+	case cert.NotAfter.Before(now):
+		problem.expired = true
+		problem.state = criticalState
+		problem.reasons = append(problem.reasons, "expired "+certs.FormattedExpiration(cert.NotAfter))
+
+	case cert.NotAfter.Before(ageCritical):
+		problem.expiring = true
+		problem.state = criticalState
+		problem.reasons = append(problem.reasons, "expiring ("+certs.FormattedExpiration(cert.NotAfter)+")")
+
+	case cert.NotAfter.Before(ageWarning):
+		problem.expiring = true
+		problem.state = warningState
+		problem.reasons = append(problem.reasons, "expiring ("+certs.FormattedExpiration(cert.NotAfter)+")")
+	}
+
+	if certs.HasWeakKey(cert) {
+		problem.weak = true
+		if problem.state.ExitCode == nagios.StateOKExitCode {
+			problem.state = warningState
+		}
+		problem.reasons = append(problem.reasons, "weak key "+certs.KeyDescription(cert))
+	}
+
+	// Signatures of root certificates are not evaluated as TLS clients trust
+	// them by their identity.
+	if certs.HasWeakSignatureAlgorithm(cert, bundle, false) {
+		problem.weak = true
+		if problem.state.ExitCode == nagios.StateOKExitCode {
+			problem.state = warningState
+		}
+		problem.reasons = append(problem.reasons, "weak signature algorithm "+cert.SignatureAlgorithm.String())
+	}
+
+	return problem
+}
+
+// runTrustStoreCheck evaluates each certificate in the trust store
+// certificate bundle specified by the sysadmin (or the system trust store)
+// and combines the results into a single service check result. Expired
+// certificates and certificates expiring within the CRITICAL threshold
+// result in a CRITICAL state, other expiring certificates and certificates
+// with a weak key or signature algorithm result in a WARNING state. The
+// evaluated certificates are returned.
+func runTrustStoreCheck(plugin *nagios.Plugin, cfg *config.Config, log zerolog.Logger) []*x509.Certificate {
+	bundleFile, resolveErr := resolveTrustStoreFile(cfg.TrustStore)
+	if resolveErr != nil {
+		log.Error().Err(resolveErr).Msg("Error locating system trust store")
+
+		plugin.AddError(resolveErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error locating system trust store certificate bundle",
+			nagios.StateUNKNOWNLabel,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return nil
+	}
+
+	log = log.With().Str("trust_store", bundleFile).Logger()
+
+	bundle, _, parseErr := certs.GetCertsFromFile(bundleFile)
+	switch {
+	case parseErr != nil:
+		log.Error().Err(parseErr).Msg("Error parsing trust store certificate bundle")
+
+		plugin.AddError(parseErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: Error parsing trust store certificate bundle %q",
+			nagios.StateUNKNOWNLabel,
+			bundleFile,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return nil
+
+	case len(bundle) == 0:
+		noCertsErr := fmt.Errorf(
+			"no certificates found in trust store certificate bundle %q: %w",
+			bundleFile,
+			certs.ErrNoCertsFound,
+		)
+
+		log.Error().Err(noCertsErr).Msg("Error parsing trust store certificate bundle")
+
+		plugin.AddError(noCertsErr)
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: No certificates found in trust store certificate bundle %q",
+			nagios.StateUNKNOWNLabel,
+			bundleFile,
+		)
+		plugin.ExitStatusCode = nagios.StateUNKNOWNExitCode
+
+		return nil
+	}
+
+	log.Debug().
+		Int("certificates", len(bundle)).
+		Msg("Evaluating trust store certificates")
+
+	thresholds := certs.ExpirationThresholds{
+		LeafCritical:         cfg.AgeCritical,
+		LeafWarning:          cfg.AgeWarning,
+		IntermediateCritical: cfg.AgeCriticalIntermediate,
+		IntermediateWarning:  cfg.AgeWarningIntermediate,
+		RootCritical:         cfg.AgeCriticalRoot,
+		RootWarning:          cfg.AgeWarningRoot,
+	}
+
+	now := time.Now()
+
+	var numExpired, numExpiring, numWeak int
+	worstState := nagios.ServiceState{Label: nagios.StateOKLabel, ExitCode: nagios.StateOKExitCode}
+	problems := make([]trustStoreProblem, 0, len(bundle))
+
+	for _, cert := range bundle {
+		problem := evaluateTrustStoreCert(cert, bundle, thresholds, now)
+		if len(problem.reasons) == 0 {
+			continue
+		}
+
+		switch {
+		case problem.expired:
+			numExpired++
+		case problem.expiring:
+			numExpiring++
+		}
+
+		if problem.weak {
+			numWeak++
+		}
+
+		if serviceStateSeverity(problem.state) > serviceStateSeverity(worstState) {
+			worstState = problem.state
+		}
+
+		problems = append(problems, problem)
+	}
+
+	// Most severe problems first, then by soonest expiration.
+	sort.SliceStable(problems, func(i, j int) bool {
+		iSeverity := serviceStateSeverity(problems[i].state)
+		jSeverity := serviceStateSeverity(problems[j].state)
+		if iSeverity != jSeverity {
+			return iSeverity > jSeverity
+		}
+
+		return problems[i].cert.NotAfter.Before(problems[j].cert.NotAfter)
+	})
+
+	log.Debug().
+		Int("expired", numExpired).
+		Int("expiring", numExpiring).
+		Int("weak", numWeak).
+		Str("worst_state", worstState.Label).
+		Msg("Evaluated trust store certificates")
+
+	switch {
+	case len(problems) > 0:
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: %d of %d certificates in trust store %q with problems (%d expired, %d expiring, %d weak)",
+			worstState.Label,
+			len(problems),
+			len(bundle),
+			bundleFile,
+			numExpired,
+			numExpiring,
+			numWeak,
+		)
+
+	default:
+		plugin.ServiceOutput = fmt.Sprintf(
+			"%s: 0 of %d certificates in trust store %q with problems",
+			worstState.Label,
+			len(bundle),
+			bundleFile,
+		)
+	}
+
+	var report strings.Builder
+	for _, problem := range problems {
+		_, _ = fmt.Fprintf(
+			&report,
+			"* [%s] %s cert %q (serial %s): %s%s",
+			problem.state.Label,
+			certs.ChainPosition(problem.cert, bundle),
+			trustStoreCertName(problem.cert),
+			certs.FormatCertSerialNumber(problem.cert.SerialNumber),
+			strings.Join(problem.reasons, ", "),
+			nagios.CheckOutputEOL,
+		)
+	}
+
+	_, _ = fmt.Fprintf(
+		&report,
+		"%sCertificates evaluated: %d (expiration thresholds: %d days CRITICAL, %d days WARNING)%s",
+		nagios.CheckOutputEOL,
+		len(bundle),
+		cfg.AgeCritical,
+		cfg.AgeWarning,
+		nagios.CheckOutputEOL,
+	)
+
+	plugin.LongServiceOutput = report.String()
+	plugin.ExitStatusCode = worstState.ExitCode
+
+	pd := []nagios.PerformanceData{
+		{
+			Label: "trust_store_certs",
+			Value: strconv.Itoa(len(bundle)),
+		},
+		{
+			Label: "trust_store_expired",
+			Value: strconv.Itoa(numExpired),
+		},
+		{
+			Label: "trust_store_expiring",
+			Value: strconv.Itoa(numExpiring),
+		},
+		{
+			Label: "trust_store_weak",
+			Value: strconv.Itoa(numWeak),
+		},
+	}
+
+	if err := plugin.AddPerfData(false, pd...); err != nil {
+		log.Error().
+			Err(err).
+			Msg("failed to add performance data")
+	}
+
+	return bundle
+}
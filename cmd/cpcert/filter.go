@@ -9,6 +9,7 @@ package main
 
 import (
 	"crypto/x509"
+	"fmt"
 
 	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/config"
@@ -52,3 +53,34 @@ func filterCertChain(filterKeywords []string, certChain []*x509.Certificate) []*
 
 	return filteredCertChain
 }
+
+// dedupeCertChain returns the given certificate chain with each unique
+// certificate retained only once (at the position of its first occurrence)
+// along with a description of each redundant certificate removed.
+func dedupeCertChain(certChain []*x509.Certificate) ([]*x509.Certificate, []string) {
+	duplicates := certs.DuplicateCerts(certChain)
+	if len(duplicates) == 0 {
+		return certChain, nil
+	}
+
+	dedupedCertChain := make([]*x509.Certificate, 0, len(certChain)-len(duplicates))
+	removed := make([]string, 0, len(duplicates))
+
+	for idx, cert := range certChain {
+		firstIdx, isDuplicate := duplicates[idx]
+		if !isDuplicate {
+			dedupedCertChain = append(dedupedCertChain, cert)
+
+			continue
+		}
+
+		removed = append(removed, fmt.Sprintf(
+			"cert %d (%s) duplicates cert %d",
+			idx,
+			cert.Subject.String(),
+			firstIdx,
+		))
+	}
+
+	return dedupedCertChain, removed
+}
@@ -13,6 +13,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/rs/zerolog"
@@ -60,6 +61,13 @@ func main() {
 
 	log := cfg.Log.With().Logger()
 
+	// The report is emitted to standard error if the certificate chain is
+	// written to standard output so that the two are not intermingled.
+	var report io.Writer = os.Stdout
+	if cfg.OutputFilename == config.StdioFilename {
+		report = os.Stderr
+	}
+
 	var certChain []*x509.Certificate
 
 	// Anything from the specified file that couldn't be converted to a
@@ -72,6 +80,30 @@ func main() {
 	var certChainSource string
 
 	switch {
+	case cfg.InputFilename == config.StdioFilename:
+
+		log.Debug().Msg("Attempting to retrieve certificates from standard input")
+
+		var err error
+		certChain, parseAttemptLeftovers, err = certs.GetCertsFromReader(os.Stdin, "standard input")
+		if err != nil {
+			log.Error().Err(err).Msg(
+				"Error parsing certificates from standard input")
+			appExitCode = config.ExitCodeCatchall
+			return
+		}
+
+		// Output from other tools (e.g., openssl s_client) commonly surrounds
+		// the certificates with other text.
+		if len(parseAttemptLeftovers) > 0 {
+			log.Debug().
+				Int("bytes", len(parseAttemptLeftovers)).
+				Msg("Ignoring non-certificate data from standard input")
+			parseAttemptLeftovers = nil
+		}
+
+		certChainSource = "standard input"
+
 	case cfg.InputFilename != "":
 
 		log.Debug().Msg("Attempting to retrieve certificates from file")
@@ -90,7 +122,7 @@ func main() {
 	case cfg.Server != "":
 
 		log.Debug().Msg("Expanding given host pattern in order to obtain IP Address")
-		expandedHost, expandErr := netutils.ExpandHost(cfg.Server)
+		expandedHost, expandErr := netutils.ExpandHostWithResolver(cfg.Server, cfg.Resolver(), cfg.DNSTimeout())
 		switch {
 		// Provide useful feedback here to cover the case of the INPUT_PATTERN
 		// not existing as a file or resolving as a server value.
@@ -212,11 +244,12 @@ func main() {
 			Int("port", cfg.Port).
 			Msg("Retrieving certificate chain")
 		var certFetchErr error
-		certChain, certFetchErr = netutils.GetCerts(
+		certChain, _, certFetchErr = netutils.GetCertsWithOptions(
 			hostVal,
 			ipAddr,
 			cfg.Port,
 			cfg.Timeout(),
+			cfg.CertRetrievalOptions(),
 			log,
 		)
 		if certFetchErr != nil {
@@ -238,14 +271,15 @@ func main() {
 	if len(parseAttemptLeftovers) > 0 {
 		textutils.PrintHeader("CERTIFICATES | UNKNOWN data in cert file")
 
-		fmt.Printf(
+		fmt.Fprintf(
+			report,
 			"The following data (converted to text) was found in the %q input"+
 				" file and is provided here in case it is useful for"+
 				" troubleshooting purposes.\n\n",
 			cfg.InputFilename,
 		)
 
-		fmt.Println(string(parseAttemptLeftovers))
+		fmt.Fprintln(report, string(parseAttemptLeftovers))
 
 		appExitCode = config.ExitCodeCatchall
 		return
@@ -261,20 +295,105 @@ func main() {
 		template = "%s: %d certs retrieved for %s:\n"
 	}
 
-	fmt.Printf(
+	fmt.Fprintf(
+		report,
 		template,
 		nagios.StateOKLabel,
 		len(certChain),
 		certChainSource,
 	)
 
-	if err := printCertChain(os.Stdout, certChain); err != nil {
+	if err := printCertChain(report, certChain); err != nil {
 		log.Err(err).Msg("failed to print certificate file")
 		appExitCode = config.ExitCodeCatchall
 
 		return
 	}
 
+	// Duplicates are removed first so that they do not affect completing or
+	// sorting the certificate chain.
+	if cfg.Dedupe {
+		dedupedCertChain, removed := dedupeCertChain(certChain)
+		switch {
+		case len(removed) > 0:
+			fmt.Fprintf(report, "OK: %d duplicate certs removed:\n", len(removed))
+			for _, entry := range removed {
+				fmt.Fprintf(report, "  - %s\n", entry)
+			}
+
+			fmt.Fprintln(report, "\nDeduplicated certificate chain:")
+			if err := printCertChain(report, dedupedCertChain); err != nil {
+				log.Err(err).Msg("failed to print certificate file")
+				appExitCode = config.ExitCodeCatchall
+
+				return
+			}
+
+		default:
+			fmt.Fprintln(report, "OK: No duplicate certs found.")
+		}
+
+		certChain = dedupedCertChain
+	}
+
+	if cfg.CompleteChain {
+		log.Debug().Msg("Attempting to complete certificate chain via AIA fetching")
+
+		completedCertChain, fetchedCerts, err := certs.CompleteCertChain(certChain, cfg.Timeout(), nil)
+		if err != nil {
+			log.Err(err).Msg("failed to complete certificate chain")
+			appExitCode = config.ExitCodeCatchall
+
+			return
+		}
+
+		switch {
+		case len(fetchedCerts) > 0:
+			fmt.Fprintf(
+				report,
+				"OK: %d missing intermediate certs retrieved via AIA fetching.\n",
+				len(fetchedCerts),
+			)
+		case certs.IsMisorderedChain(certChain):
+			fmt.Fprintln(report, "OK: No missing intermediate certs; input certificate chain reordered.")
+		default:
+			fmt.Fprintln(report, "OK: No missing intermediate certs.")
+		}
+
+		if len(fetchedCerts) > 0 || certs.IsMisorderedChain(certChain) {
+			fmt.Fprintln(report, "\nCompleted certificate chain:")
+			if err := printCertChain(report, completedCertChain); err != nil {
+				log.Err(err).Msg("failed to print certificate file")
+				appExitCode = config.ExitCodeCatchall
+
+				return
+			}
+		}
+
+		certChain = completedCertChain
+	}
+
+	// A completed certificate chain is already in canonical order.
+	if cfg.SortChain && !cfg.CompleteChain {
+		switch {
+		case certs.IsMisorderedChain(certChain):
+			certChain = certs.SortCertChain(certChain)
+
+			fmt.Fprintln(report, "OK: Input certificate chain sorted as requested.")
+
+			fmt.Fprintln(report, "\nSorted certificate chain:")
+			if err := printCertChain(report, certChain); err != nil {
+				log.Err(err).Msg("failed to print certificate file")
+				appExitCode = config.ExitCodeCatchall
+
+				return
+			}
+
+		default:
+			fmt.Fprintln(report, "OK: Input certificate chain already in canonical order.")
+		}
+	}
+
 	filteredCertChain := filterCertChain(cfg.CertTypesToKeep(), certChain)
 	switch {
 	case len(filteredCertChain) == 0:
@@ -284,19 +403,19 @@ func main() {
 
 		return
 	case len(filteredCertChain) != len(certChain):
-		fmt.Println("OK: Input certificate chain filtered as requested.")
+		fmt.Fprintln(report, "OK: Input certificate chain filtered as requested.")
 
-		fmt.Println("\nNew certificate chain:")
-		if err := printCertChain(os.Stdout, filteredCertChain); err != nil {
+		fmt.Fprintln(report, "\nNew certificate chain:")
+		if err := printCertChain(report, filteredCertChain); err != nil {
 			log.Err(err).Msg("failed to print certificate file")
 			appExitCode = config.ExitCodeCatchall
 
 			return
 		}
 	default:
-		fmt.Println("OK: Retaining input certificate chain as-is:")
+		fmt.Fprintln(report, "OK: Retaining input certificate chain as-is:")
 
-		if err := printCertChain(os.Stdout, certChain); err != nil {
+		if err := printCertChain(report, certChain); err != nil {
 			log.Err(err).Msg("failed to print certificate file")
 			appExitCode = config.ExitCodeCatchall
 
@@ -304,19 +423,48 @@ func main() {
 		}
 	}
 
-	// Open the file to write the certificate chain
-	outputFile, err := os.Create(cfg.OutputFilename)
-	if err != nil {
-		log.Err(err).Msg("failed to create output certificate file")
-		appExitCode = config.ExitCodeCatchall
+	outputDest := cfg.OutputFilename
+	if cfg.OutputFilename == config.StdioFilename {
+		outputDest = "standard output"
+	}
+
+	if cfg.KeyFile != "" {
+		if err := writePKCS12File(cfg, filteredCertChain); err != nil {
+			log.Err(err).Msg("failed to create output PKCS #12 file")
+			appExitCode = config.ExitCodeCatchall
+
+			return
+		}
+
+		fmt.Fprintf(
+			report,
+			"\n%d of %d certs and private key from %s successfully written to %s\n",
+			len(filteredCertChain),
+			len(certChain),
+			cfg.KeyFile,
+			outputDest,
+		)
+
 		return
 	}
 
-	defer func() {
-		if err := outputFile.Close(); err != nil {
-			log.Err(err).Msg("error occurred closing output file")
+	outputFile := os.Stdout
+	if cfg.OutputFilename != config.StdioFilename {
+		// Open the file to write the certificate chain
+		var err error
+		outputFile, err = os.Create(cfg.OutputFilename)
+		if err != nil {
+			log.Err(err).Msg("failed to create output certificate file")
+			appExitCode = config.ExitCodeCatchall
+			return
 		}
-	}()
+
+		defer func() {
+			if err := outputFile.Close(); err != nil {
+				log.Err(err).Msg("error occurred closing output file")
+			}
+		}()
+	}
 
 	for _, cert := range filteredCertChain {
 		err := certs.WriteCertToPEMFile(outputFile, cert)
@@ -328,10 +476,11 @@ func main() {
 		}
 	}
 
-	fmt.Printf(
+	fmt.Fprintf(
+		report,
 		"\n%d of %d certs successfully written to %s\n",
 		len(filteredCertChain),
 		len(certChain),
-		cfg.OutputFilename,
+		outputDest,
 	)
 }
@@ -0,0 +1,90 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/pkcs12"
+)
+
+// p12Password retrieves the password protecting the PKCS #12 bundle from the
+// user-specified secret provider. An empty string is returned if a password
+// was not specified.
+func p12Password(cfg *config.Config) (string, error) {
+	provider := cfg.P12PasswordProvider()
+	if provider == nil {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout())
+	defer cancel()
+
+	password, err := provider.Secret(ctx)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to retrieve PKCS #12 password from %s: %w",
+			provider.Source(),
+			err,
+		)
+	}
+
+	return password, nil
+}
+
+// writePKCS12File combines the private key from the user-specified key file
+// with the given certificate chain and writes the result to the specified
+// output file (or standard output) as a PKCS #12 bundle.
+func writePKCS12File(cfg *config.Config, certChain []*x509.Certificate) error {
+	privateKey, err := certs.GetPrivateKeyFromFile(cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	password, err := p12Password(cfg)
+	if err != nil {
+		return err
+	}
+
+	pfxData, err := pkcs12.Encode(privateKey, certChain, password)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to create PKCS #12 bundle using key file %s: %w",
+			cfg.KeyFile,
+			err,
+		)
+	}
+
+	if cfg.OutputFilename == config.StdioFilename {
+		if _, err := os.Stdout.Write(pfxData); err != nil {
+			return fmt.Errorf(
+				"failed to write PKCS #12 bundle to standard output: %w",
+				err,
+			)
+		}
+
+		return nil
+	}
+
+	// The bundle contains a private key; restrict access accordingly.
+	if err := os.WriteFile(filepath.Clean(cfg.OutputFilename), pfxData, 0600); err != nil {
+		return fmt.Errorf(
+			"failed to write PKCS #12 bundle to %s: %w",
+			cfg.OutputFilename,
+			err,
+		)
+	}
+
+	return nil
+}
@@ -38,7 +38,7 @@ func printCertChain(out io.Writer, certChain []*x509.Certificate) error {
 	certTypeSeparatorLength := func() int {
 		longest := len(certTypeColTitle)
 		for _, cert := range certChain {
-			certType := certs.ChainPosition(cert, certChain)
+			certType := certs.ChainPosition(cert, certChain).String()
 
 			if len(certType) > longest {
 				longest = len(certType)
@@ -97,7 +97,7 @@ func printCertChain(out io.Writer, certChain []*x509.Certificate) error {
 			w,
 			dataRowTmpl,
 			idx,
-			certs.ChainPosition(cert, certChain),
+			certs.ChainPosition(cert, certChain).String(),
 			cert.Subject.CommonName,
 
 			// Avoid triggering "loop variable X now per-iteration,
@@ -0,0 +1,55 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/atc0005/check-cert/internal/backfill"
+	"github.com/rs/zerolog"
+)
+
+// runBackfill converts the saved check_cert plugin output files within the
+// given directory into normalized JSON records and emits them to the given
+// writer, one record per line. Files which could not be converted are logged
+// and skipped.
+func runBackfill(dir string, w io.Writer, log zerolog.Logger) error {
+	records, errs := backfill.ParseDir(dir)
+	for _, err := range errs {
+		log.Warn().Err(err).Msg("Skipping file")
+	}
+
+	enc := json.NewEncoder(w)
+
+	var numPayload int
+	for _, record := range records {
+		if record.Method == backfill.MethodPayload {
+			numPayload++
+		}
+
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf(
+				"failed to encode record for %s: %w",
+				record.SourceFile,
+				err,
+			)
+		}
+	}
+
+	log.Debug().
+		Str("backfill_dir", dir).
+		Int("records", len(records)).
+		Int("records_from_payload", numPayload).
+		Int("records_from_report", len(records)-numPayload).
+		Int("files_skipped", len(errs)).
+		Msg("Backfill complete")
+
+	return nil
+}
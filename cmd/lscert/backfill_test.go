@@ -0,0 +1,277 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/backfill"
+	"github.com/rs/zerolog"
+)
+
+// TestBackfillRecordsMatchSchema asserts that backfill records conform to
+// the embedded JSON Schema document.
+func TestBackfillRecordsMatchSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(backfillRecordSchema), &schema); err != nil {
+		t.Fatalf("Failed to decode embedded schema: %v", err)
+	}
+
+	notBefore := time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]backfill.Record{
+		"report": {
+			SourceFile:   "report.txt",
+			Method:       backfill.MethodReport,
+			ServiceState: "WARNING",
+			Summary:      "1 of 2 certificates expiring soon",
+			Server:       "www.example.com",
+			IPAddress:    "192.0.2.1",
+			DNSName:      "www.example.com",
+			TCPPort:      443,
+			PerfData:     map[string]string{"expires_leaf": "10d"},
+			Certs: []backfill.Cert{
+				{
+					Subject:           "CN=www.example.com",
+					SANsEntries:       []string{"www.example.com"},
+					Issuer:            "CN=Example Intermediate CA",
+					SerialNumber:      "01:02:03",
+					FingerprintSHA256: "AA:BB:CC",
+					NotBefore:         &notBefore,
+					NotAfter:          &notAfter,
+					ChainPosition:     "leaf",
+					Status:            "[WARNING] 10d 0h remaining",
+				},
+				{
+					Subject:       "CN=Example Intermediate CA",
+					Issuer:        "CN=Example Root CA",
+					SerialNumber:  "04:05",
+					ChainPosition: "intermediate",
+					Status:        "[OK] 3650d 0h remaining",
+				},
+			},
+		},
+		"payload": {
+			SourceFile:   "payload.txt",
+			Method:       backfill.MethodPayload,
+			ServiceState: "OK",
+			Summary:      "0 of 2 certificates expiring soon",
+			Payload:      json.RawMessage(`{"server":"www.example.com"}`),
+		},
+	}
+
+	for name, record := range tests {
+		record := record
+
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(record)
+			if err != nil {
+				t.Fatalf("Failed to encode record: %v", err)
+			}
+
+			var doc interface{}
+			if err := json.Unmarshal(encoded, &doc); err != nil {
+				t.Fatalf("Failed to decode record: %v", err)
+			}
+
+			for _, err := range validateSchema(schema, schema, doc, "") {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestRunBackfill asserts that saved plugin output files are emitted as one
+// JSON record per line and that unrecognized files are skipped.
+func TestRunBackfill(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"ok.txt":      "OK: 0 of 1 certificates expiring soon\n | 'expires_leaf'=60d;30;15;;\n",
+		"unknown.txt": "not plugin output\n",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write plugin output file: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := runBackfill(dir, &buf, zerolog.Nop()); err != nil {
+		t.Fatalf("want no error; got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("want 1 record; got %d: %q", len(lines), buf.String())
+	}
+
+	var record backfill.Record
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Failed to decode record: %v", err)
+	}
+
+	if record.ServiceState != "OK" || record.Method != backfill.MethodReport {
+		t.Errorf("want OK state from report; got %s state from %s", record.ServiceState, record.Method)
+	}
+}
+
+// resolveSchemaRef returns the schema definition for the given local
+// reference (e.g., "#/$defs/cert").
+func resolveSchemaRef(root map[string]interface{}, ref string) map[string]interface{} {
+	def := root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if part == "" {
+			continue
+		}
+
+		next, ok := def[part].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		def = next
+	}
+
+	return def
+}
+
+// validateSchema asserts that the given decoded JSON value conforms to the
+// given schema definition. Only the schema keywords used by the embedded
+// schema document are supported.
+func validateSchema(root map[string]interface{}, def map[string]interface{}, value interface{}, path string) []error {
+	if def == nil {
+		return []error{fmt.Errorf("%s: schema definition not found", path)}
+	}
+
+	if ref, ok := def["$ref"].(string); ok {
+		return validateSchema(root, resolveSchemaRef(root, ref), value, path)
+	}
+
+	var errs []error
+
+	if !schemaTypeMatches(def["type"], value) {
+		return append(errs, fmt.Errorf("%s: value %v does not match type %v", path, value, def["type"]))
+	}
+
+	if enum, ok := def["enum"].([]interface{}); ok {
+		var found bool
+		for _, v := range enum {
+			if v == value {
+				found = true
+			}
+		}
+
+		if !found {
+			errs = append(errs, fmt.Errorf("%s: value %v not in %v", path, value, enum))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := def["properties"].(map[string]interface{})
+
+		if required, ok := def["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[name.(string)]; !ok {
+					errs = append(errs, fmt.Errorf("%s: missing required property %q", path, name))
+				}
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			propPath := path + "/" + key
+
+			if propDef, ok := properties[key].(map[string]interface{}); ok {
+				errs = append(errs, validateSchema(root, propDef, v[key], propPath)...)
+
+				continue
+			}
+
+			switch additional := def["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Errorf("%s: property not described by schema", propPath))
+				}
+			case map[string]interface{}:
+				errs = append(errs, validateSchema(root, additional, v[key], propPath)...)
+			}
+		}
+
+	case []interface{}:
+		if items, ok := def["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				errs = append(errs, validateSchema(root, items, item, fmt.Sprintf("%s/%d", path, i))...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// schemaTypeMatches indicates whether the given decoded JSON value matches
+// the given schema type keyword value (a single type or a list of types).
+func schemaTypeMatches(schemaType interface{}, value interface{}) bool {
+	var types []string
+	switch st := schemaType.(type) {
+	case nil:
+		return true
+	case string:
+		types = []string{st}
+	case []interface{}:
+		for _, t := range st {
+			types = append(types, t.(string))
+		}
+	}
+
+	for _, t := range types {
+		switch value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && value.(float64) == float64(int64(value.(float64)))) {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
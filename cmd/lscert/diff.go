@@ -0,0 +1,389 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// chainSnapshot is a previously recorded snapshot of a certificate chain
+// used for comparison against the current certificate chain.
+type chainSnapshot struct {
+	// source is the file the snapshot was loaded from.
+	source string
+
+	// recordedAt is the time the snapshot was recorded. For certificate
+	// chain files this is the file modification time.
+	recordedAt time.Time
+
+	// leaf is the leaf certificate recorded by the snapshot (if any).
+	leaf *certs.ObservedCert
+
+	// certChain is the full certificate chain recorded by the snapshot.
+	// This is not available for state files as only the leaf certificate
+	// is recorded.
+	certChain []*x509.Certificate
+}
+
+// chainChange describes a non-leaf certificate added to, removed from or
+// unchanged in the current certificate chain.
+type chainChange struct {
+	// marker is a brief prefix indicating the type of change (+, - or =).
+	marker string
+
+	// change is the type of change (added, removed, unchanged).
+	change string
+
+	// cert is the certificate the change applies to.
+	cert *x509.Certificate
+
+	// certChain is the certificate chain the certificate belongs to.
+	certChain []*x509.Certificate
+}
+
+// loadChainSnapshot loads a previously recorded certificate chain snapshot
+// from the given file. Both check_cert state files and PEM or DER formatted
+// certificate chain files are supported.
+func loadChainSnapshot(filename string) (chainSnapshot, error) {
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return chainSnapshot{}, fmt.Errorf(
+			"failed to read snapshot file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	// State files are JSON encoded, certificate chain files are not.
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		observed, loadErr := certs.LoadObservedCertFile(filename)
+		if loadErr != nil {
+			return chainSnapshot{}, loadErr
+		}
+
+		return chainSnapshot{
+			source:     filename,
+			recordedAt: observed.ObservedAt,
+			leaf:       &observed,
+		}, nil
+	}
+
+	certChain, _, parseErr := certs.GetCertsFromFile(filename)
+	switch {
+	case parseErr != nil:
+		return chainSnapshot{}, fmt.Errorf(
+			"failed to parse snapshot file %q: %w",
+			filename,
+			parseErr,
+		)
+
+	case len(certChain) == 0:
+		return chainSnapshot{}, fmt.Errorf(
+			"failed to parse snapshot file %q: %w",
+			filename,
+			certs.ErrNoCertsFound,
+		)
+	}
+
+	fi, statErr := os.Stat(filename)
+	if statErr != nil {
+		return chainSnapshot{}, fmt.Errorf(
+			"failed to read snapshot file %q: %w",
+			filename,
+			statErr,
+		)
+	}
+
+	snapshot := chainSnapshot{
+		source:     filename,
+		recordedAt: fi.ModTime().UTC(),
+		certChain:  certChain,
+	}
+
+	if certs.IsLeafCert(certChain[0], certChain) {
+		observed := certs.NewObservedCert(certChain[0], snapshot.recordedAt)
+		snapshot.leaf = &observed
+	}
+
+	return snapshot, nil
+}
+
+// certFingerprint returns the colon delimited SHA-256 fingerprint for the
+// given certificate.
+func certFingerprint(cert *x509.Certificate) string {
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	return textutils.BytesToDelimitedHexStr(fingerprint[:], ":")
+}
+
+// certDisplayName returns the Subject CommonName for the given certificate
+// or the full Subject if a CommonName is not set.
+func certDisplayName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+
+	return cert.Subject.String()
+}
+
+// nonLeafChainChanges compares the intermediate and root certificates of the
+// previous and current certificate chains, returning the certificates
+// removed, added and unchanged (in that order).
+func nonLeafChainChanges(previous []*x509.Certificate, current []*x509.Certificate) []chainChange {
+	fingerprints := func(certChain []*x509.Certificate) map[string]struct{} {
+		set := make(map[string]struct{}, len(certChain))
+		for _, cert := range certChain {
+			set[certFingerprint(cert)] = struct{}{}
+		}
+
+		return set
+	}
+
+	previousSet := fingerprints(previous)
+	currentSet := fingerprints(current)
+
+	var removed, added, unchanged []chainChange
+
+	for _, cert := range previous {
+		if certs.IsLeafCert(cert, previous) {
+			continue
+		}
+
+		if _, ok := currentSet[certFingerprint(cert)]; !ok {
+			removed = append(removed, chainChange{"-", "removed", cert, previous})
+		}
+	}
+
+	for _, cert := range current {
+		if certs.IsLeafCert(cert, current) {
+			continue
+		}
+
+		switch _, ok := previousSet[certFingerprint(cert)]; {
+		case ok:
+			unchanged = append(unchanged, chainChange{"=", "unchanged", cert, current})
+		default:
+			added = append(added, chainChange{"+", "added", cert, current})
+		}
+	}
+
+	changes := make([]chainChange, 0, len(removed)+len(added)+len(unchanged))
+	changes = append(changes, removed...)
+	changes = append(changes, added...)
+	changes = append(changes, unchanged...)
+
+	return changes
+}
+
+// formatSANsEntries returns the given list of SANs entries as a comma
+// separated list or a placeholder if empty.
+func formatSANsEntries(entries []string) string {
+	if len(entries) == 0 {
+		return "None"
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+// leafDiffRows returns side-by-side rows of field name, previous value and
+// current value for the leaf certificate fields recorded by a snapshot.
+func leafDiffRows(previous *certs.ObservedCert, current *certs.ObservedCert) [][3]string {
+	value := func(oc *certs.ObservedCert, fn func(certs.ObservedCert) string) string {
+		if oc == nil {
+			return "N/A"
+		}
+
+		return fn(*oc)
+	}
+
+	fields := []struct {
+		name string
+		fn   func(certs.ObservedCert) string
+	}{
+		{"Serial", func(oc certs.ObservedCert) string { return oc.SerialNumber }},
+		{"SHA-256 Fingerprint", func(oc certs.ObservedCert) string { return oc.FingerprintSHA256 }},
+		{"Expiration", func(oc certs.ObservedCert) string { return oc.NotAfter.Format(certs.CertValidityDateLayout) }},
+		{"SANs entries", func(oc certs.ObservedCert) string { return strconv.Itoa(len(oc.SANsEntries)) }},
+	}
+
+	rows := make([][3]string, 0, len(fields))
+	for _, field := range fields {
+		rows = append(rows, [3]string{
+			field.name,
+			value(previous, field.fn),
+			value(current, field.fn),
+		})
+	}
+
+	return rows
+}
+
+// currentLeaf returns the leaf certificate for the given certificate chain
+// as an observation recorded at the given time, or nil if the chain does
+// not contain a leaf certificate.
+func currentLeaf(certChain []*x509.Certificate, now time.Time) *certs.ObservedCert {
+	if len(certChain) == 0 || !certs.IsLeafCert(certChain[0], certChain) {
+		return nil
+	}
+
+	observed := certs.NewObservedCert(certChain[0], now)
+
+	return &observed
+}
+
+// leafChangeSummary returns a brief summary of the changes to the leaf
+// certificate since the snapshot was recorded.
+func leafChangeSummary(previous *certs.ObservedCert, current *certs.ObservedCert) string {
+	switch {
+	case previous == nil && current == nil:
+		return "not present in snapshot or current chain"
+	case previous == nil:
+		return "ADDED (not present in snapshot)"
+	case current == nil:
+		return "REMOVED (not present in current chain)"
+	case previous.SameCert(*current):
+		return "UNCHANGED"
+	case previous.SerialNumber != current.SerialNumber:
+		return "CHANGED (new serial number)"
+	default:
+		return "CHANGED"
+	}
+}
+
+// printDiffReport emits the changes to the given certificate chain since the
+// given snapshot was recorded as plain text suitable for change review.
+func printDiffReport(out io.Writer, snapshot chainSnapshot, certChain []*x509.Certificate, certChainSource string) error {
+	now := time.Now().UTC()
+	current := currentLeaf(certChain, now)
+
+	_, _ = fmt.Fprintf(
+		out,
+		"Changes for %s since snapshot %q (recorded %s, %d days ago)\n\n",
+		certChainSource,
+		snapshot.source,
+		snapshot.recordedAt.Format(certs.CertValidityDateLayout),
+		int(now.Sub(snapshot.recordedAt).Hours()/24),
+	)
+
+	_, _ = fmt.Fprintf(out, "Leaf certificate: %s\n\n", leafChangeSummary(snapshot.leaf, current))
+
+	tw := tabwriter.NewWriter(out, 4, 4, 4, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "  Field\tPrevious\tCurrent")
+	_, _ = fmt.Fprintln(tw, "  -----\t--------\t-------")
+	for _, row := range leafDiffRows(snapshot.leaf, current) {
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\n", row[0], row[1], row[2])
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error occurred flushing tabwriter: %w", err)
+	}
+
+	if snapshot.leaf != nil && current != nil {
+		added, removed := certs.SANsEntriesDelta(snapshot.leaf.SANsEntries, current.SANsEntries)
+
+		_, _ = fmt.Fprintf(out, "\n  SANs entries added (%d): %s\n", len(added), formatSANsEntries(added))
+		_, _ = fmt.Fprintf(out, "  SANs entries removed (%d): %s\n", len(removed), formatSANsEntries(removed))
+	}
+
+	_, _ = fmt.Fprintln(out, "\nIntermediate and root certificates:")
+	_, _ = fmt.Fprintln(out)
+
+	if snapshot.certChain == nil {
+		_, _ = fmt.Fprintln(out, "  Not compared; snapshot records the leaf certificate only.")
+
+		return nil
+	}
+
+	changes := nonLeafChainChanges(snapshot.certChain, certChain)
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(out, "  None present in snapshot or current chain.")
+
+		return nil
+	}
+
+	for _, change := range changes {
+		_, _ = fmt.Fprintf(
+			out,
+			"  %s %s %s %q (serial %s, expires %s)\n",
+			change.marker,
+			change.change,
+			certs.ChainPosition(change.cert, change.certChain),
+			certDisplayName(change.cert),
+			certs.FormatCertSerialNumber(change.cert.SerialNumber),
+			change.cert.NotAfter.Format(certs.CertValidityDateLayout),
+		)
+	}
+
+	return nil
+}
+
+// printMarkdownDiffReport emits the changes to the given certificate chain
+// since the given snapshot was recorded as Markdown tables suitable for
+// pasting into change review tickets.
+func printMarkdownDiffReport(snapshot chainSnapshot, certChain []*x509.Certificate, certChainSource string) {
+	now := time.Now().UTC()
+	current := currentLeaf(certChain, now)
+
+	fmt.Println()
+	fmt.Println("## Changes since snapshot")
+	fmt.Println()
+	fmt.Printf(
+		"Changes for %s since snapshot `%s` (recorded %s)\n",
+		certChainSource,
+		snapshot.source,
+		snapshot.recordedAt.Format(certs.CertValidityDateLayout),
+	)
+	fmt.Println()
+	fmt.Printf("Leaf certificate: %s\n", leafChangeSummary(snapshot.leaf, current))
+	fmt.Println()
+
+	fmt.Println(textutils.MarkdownTableHeader("Field", "Previous", "Current"))
+	for _, row := range leafDiffRows(snapshot.leaf, current) {
+		fmt.Println(textutils.MarkdownTableRow(row[0], row[1], row[2]))
+	}
+
+	if snapshot.leaf != nil && current != nil {
+		added, removed := certs.SANsEntriesDelta(snapshot.leaf.SANsEntries, current.SANsEntries)
+		fmt.Println(textutils.MarkdownTableRow("SANs entries added", "", formatSANsEntries(added)))
+		fmt.Println(textutils.MarkdownTableRow("SANs entries removed", formatSANsEntries(removed), ""))
+	}
+
+	if snapshot.certChain == nil {
+		return
+	}
+
+	changes := nonLeafChainChanges(snapshot.certChain, certChain)
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(textutils.MarkdownTableHeader("Change", "Position", "Subject", "Serial", "Expiration"))
+	for _, change := range changes {
+		fmt.Println(textutils.MarkdownTableRow(
+			change.change,
+			certs.ChainPosition(change.cert, change.certChain).String(),
+			certDisplayName(change.cert),
+			certs.FormatCertSerialNumber(change.cert.SerialNumber),
+			change.cert.NotAfter.Format(certs.CertValidityDateLayout),
+		))
+	}
+}
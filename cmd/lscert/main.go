@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/config"
 	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/stats"
 	"github.com/atc0005/check-cert/internal/textutils"
 	"github.com/atc0005/go-nagios"
 )
@@ -36,6 +38,11 @@ func main() {
 
 		return
 
+	case errors.Is(cfgErr, config.ErrSchemaRequested):
+		fmt.Print(backfillRecordSchema)
+
+		return
+
 	case cfgErr != nil:
 
 		// We make some assumptions when setting up our logger as we do not
@@ -50,6 +57,45 @@ func main() {
 
 	log := cfg.Log.With().Logger()
 
+	if cfg.BackfillDir != "" {
+		if err := runBackfill(cfg.BackfillDir, os.Stdout, log); err != nil {
+			log.Error().Err(err).Msg("Error converting saved plugin output")
+			os.Exit(config.ExitCodeCatchall)
+		}
+
+		return
+	}
+
+	if cfg.StatsSummary != "" {
+		records, err := stats.LoadFile(cfg.StatsSummary)
+		if err != nil {
+			log.Error().Err(err).Msg("Error loading run statistics")
+			os.Exit(config.ExitCodeCatchall)
+		}
+
+		textutils.PrintHeader("RUN STATISTICS | SUMMARY")
+
+		if err := stats.WriteSummary(os.Stdout, records); err != nil {
+			log.Error().Err(err).Msg("Error generating run statistics summary")
+			os.Exit(config.ExitCodeCatchall)
+		}
+
+		return
+	}
+
+	// Load the snapshot to compare against before retrieving the current
+	// certificate chain so that problems with the snapshot are reported
+	// early.
+	var snapshot chainSnapshot
+	if cfg.DiffSince != "" {
+		var snapshotErr error
+		snapshot, snapshotErr = loadChainSnapshot(cfg.DiffSince)
+		if snapshotErr != nil {
+			log.Error().Err(snapshotErr).Msg("Error loading certificate chain snapshot")
+			os.Exit(config.ExitCodeCatchall)
+		}
+	}
+
 	var certChain []*x509.Certificate
 
 	// Anything from the specified file that couldn't be converted to a
@@ -61,6 +107,11 @@ func main() {
 
 	var certChainSource string
 
+	// Used to retrieve the certificate chain again when watching for
+	// changes.
+	var fetchCertChain certChainFetcher
+	var retrievedAt time.Time
+
 	// Honor request to parse filename first
 	switch {
 	case cfg.InputFilename != "":
@@ -80,7 +131,7 @@ func main() {
 	case cfg.Server != "":
 
 		log.Debug().Msg("Expanding given host pattern in order to obtain IP Address")
-		expandedHost, expandErr := netutils.ExpandHost(cfg.Server)
+		expandedHost, expandErr := netutils.ExpandHostWithResolver(cfg.Server, cfg.Resolver(), cfg.DNSTimeout())
 		switch {
 		case expandErr != nil:
 			log.Error().Err(expandErr).Msg(
@@ -188,14 +239,22 @@ func main() {
 			Str("host_value", hostVal).
 			Int("port", cfg.Port).
 			Msg("Retrieving certificate chain")
+		fetchCertChain = func() ([]*x509.Certificate, error) {
+			fetched, _, err := netutils.GetCertsWithOptions(
+				hostVal,
+				ipAddr,
+				cfg.Port,
+				cfg.Timeout(),
+				cfg.CertRetrievalOptions(),
+				log,
+			)
+
+			return fetched, err
+		}
+
 		var certFetchErr error
-		certChain, certFetchErr = netutils.GetCerts(
-			hostVal,
-			ipAddr,
-			cfg.Port,
-			cfg.Timeout(),
-			log,
-		)
+		retrievedAt = time.Now().UTC()
+		certChain, certFetchErr = fetchCertChain()
 		if certFetchErr != nil {
 			log.Error().Err(certFetchErr).Msg(
 				"Error fetching certificates chain")
@@ -204,30 +263,9 @@ func main() {
 
 	}
 
-	textutils.PrintHeader("CERTIFICATES | SUMMARY")
-
-	switch {
-	case len(certChain) == 0:
+	if len(certChain) == 0 {
 		log.Err(certs.ErrNoCertsFound).Msg("")
 		os.Exit(config.ExitCodeCatchall)
-
-	default:
-		// If a certificate chain was pulled from a file, we "found" it, if it
-		// was pulled from a server we "retrieved" it.
-		var template string
-		switch {
-		case cfg.InputFilename != "":
-			template = "- %s: %d certs found in %s\n"
-		default:
-			template = "- %s: %d certs retrieved for %s\n"
-		}
-
-		fmt.Printf(
-			template,
-			nagios.StateOKLabel,
-			len(certChain),
-			certChainSource,
-		)
 	}
 
 	hasLeafCert := certs.HasLeafCert(certChain)
@@ -242,6 +280,89 @@ func main() {
 		},
 	)
 
+	sansValidationResult := certs.ValidateSANsList(
+		certChain,
+		cfg.SANsEntries,
+		certs.CertChainValidationOptions{
+			IgnoreValidationResultSANs: !cfg.ApplyCertSANsListValidationResults(),
+		},
+	)
+
+	expirationValidationOptions := certs.CertChainValidationOptions{
+		IgnoreExpiredIntermediateCertificates: cfg.IgnoreExpiredIntermediateCertificates,
+		IgnoreExpiredRootCertificates:         cfg.IgnoreExpiredRootCertificates,
+		IgnoreValidationResultExpiration:      !cfg.ApplyCertExpirationValidationResults(),
+	}
+
+	expirationValidationResult := certs.ValidateExpiration(
+		certChain,
+		cfg.AgeCritical,
+		cfg.AgeWarning,
+		cfg.VerboseOutput,
+		cfg.OmitSANsEntries,
+		expirationValidationOptions,
+	)
+
+	if cfg.TemplateFile != "" {
+		data := newTemplateData(
+			certChain,
+			certChainSource,
+			expirationValidationOptions,
+			expirationValidationResult,
+			hostnameValidationResult,
+			sansValidationResult,
+			expirationValidationResult,
+		)
+
+		if err := printTemplateReport(cfg.TemplateFile, data); err != nil {
+			log.Error().Err(err).Msg("Error generating template output")
+			os.Exit(config.ExitCodeCatchall)
+		}
+
+		return
+	}
+
+	if cfg.OutputFormat == config.OutputFormatMarkdown {
+		printMarkdownReport(
+			certChain,
+			certChainSource,
+			cfg.OmitSANsEntries,
+			expirationValidationOptions,
+			hostnameValidationResult,
+			sansValidationResult,
+			expirationValidationResult,
+		)
+
+		if cfg.DiffSince != "" {
+			printMarkdownDiffReport(snapshot, certChain, certChainSource)
+		}
+
+		if cfg.Watch {
+			os.Exit(runWatch(cfg, certChain, retrievedAt, certChainSource, fetchCertChain, log))
+		}
+
+		return
+	}
+
+	textutils.PrintHeader("CERTIFICATES | SUMMARY")
+
+	// If a certificate chain was pulled from a file, we "found" it, if it
+	// was pulled from a server we "retrieved" it.
+	var template string
+	switch {
+	case cfg.InputFilename != "":
+		template = "- %s: %d certs found in %s\n"
+	default:
+		template = "- %s: %d certs retrieved for %s\n"
+	}
+
+	fmt.Printf(
+		template,
+		nagios.StateOKLabel,
+		len(certChain),
+		certChainSource,
+	)
+
 	switch {
 	case hostnameValidationResult.IsFailed():
 		log.Debug().
@@ -288,13 +409,6 @@ func main() {
 		)
 	}
 
-	sansValidationResult := certs.ValidateSANsList(
-		certChain,
-		cfg.SANsEntries,
-		certs.CertChainValidationOptions{
-			IgnoreValidationResultSANs: !cfg.ApplyCertSANsListValidationResults(),
-		},
-	)
 	switch {
 	case sansValidationResult.IsFailed():
 		log.Debug().
@@ -333,18 +447,6 @@ func main() {
 		)
 	}
 
-	expirationValidationResult := certs.ValidateExpiration(
-		certChain,
-		cfg.AgeCritical,
-		cfg.AgeWarning,
-		cfg.VerboseOutput,
-		cfg.OmitSANsEntries,
-		certs.CertChainValidationOptions{
-			IgnoreExpiredIntermediateCertificates: cfg.IgnoreExpiredIntermediateCertificates,
-			IgnoreExpiredRootCertificates:         cfg.IgnoreExpiredRootCertificates,
-			IgnoreValidationResultExpiration:      !cfg.ApplyCertExpirationValidationResults(),
-		},
-	)
 	switch {
 	case expirationValidationResult.IsFailed():
 		log.Debug().
@@ -400,6 +502,14 @@ func main() {
 	// chain evaluated.
 	fmt.Println(expirationValidationResult.StatusDetail())
 
+	if cfg.DiffSince != "" {
+		textutils.PrintHeader("CERTIFICATES | CHANGES SINCE SNAPSHOT")
+
+		if err := printDiffReport(os.Stdout, snapshot, certChain, certChainSource); err != nil {
+			log.Error().Err(err).Msg("Error generating certificate chain changes report")
+		}
+	}
+
 	// Generate text version of the certificate if requested.
 	if cfg.EmitCertText {
 		textutils.PrintHeader("CERTIFICATES | OpenSSL Text Format")
@@ -432,4 +542,8 @@ func main() {
 		fmt.Println(string(parseAttemptLeftovers))
 	}
 
+	if cfg.Watch {
+		os.Exit(runWatch(cfg, certChain, retrievedAt, certChainSource, fetchCertChain, log))
+	}
+
 }
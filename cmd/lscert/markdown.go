@@ -0,0 +1,102 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// printMarkdownReport emits the certificate chain summary and details to
+// stdout as Markdown tables suitable for pasting into wikis and chat.
+func printMarkdownReport(
+	certChain []*x509.Certificate,
+	certChainSource string,
+	omitSANsEntries bool,
+	expirationValidationOptions certs.CertChainValidationOptions,
+	hostnameValidationResult certs.HostnameValidationResult,
+	sansValidationResult certs.SANsListValidationResult,
+	expirationValidationResult certs.ExpirationValidationResult,
+) {
+
+	fmt.Println("## Certificates summary")
+	fmt.Println()
+	fmt.Printf("%d certs evaluated for %s\n", len(certChain), certChainSource)
+	fmt.Println()
+
+	fmt.Println(textutils.MarkdownTableHeader("State", "Check", "Result"))
+
+	for _, result := range []certs.CertChainValidationResult{
+		hostnameValidationResult,
+		sansValidationResult,
+		expirationValidationResult,
+	} {
+		fmt.Println(textutils.MarkdownTableRow(
+			result.ServiceState().Label,
+			result.CheckName(),
+			result.Status()+" "+result.Overview(),
+		))
+	}
+
+	fmt.Println()
+	fmt.Println("## Certificates chain details")
+	fmt.Println()
+
+	fmt.Println(textutils.MarkdownTableHeader(
+		"#",
+		"Position",
+		"Subject",
+		"SANs entries",
+		"Issuer",
+		"Serial",
+		"Issued On",
+		"Expiration",
+		"Status",
+	))
+
+	ageCritical := expirationValidationResult.AgeCriticalThreshold()
+	ageWarning := expirationValidationResult.AgeWarningThreshold()
+
+	for idx, cert := range certChain {
+		sansEntries := strings.Join(cert.DNSNames, ", ")
+		switch {
+		case omitSANsEntries && len(cert.DNSNames) > 0:
+			sansEntries = fmt.Sprintf("%d (omitted by request)", len(cert.DNSNames))
+		case len(cert.DNSNames) == 0:
+			sansEntries = "None"
+		}
+
+		fmt.Println(textutils.MarkdownTableRow(
+			strconv.Itoa(idx+1),
+			certs.ChainPosition(cert, certChain).String(),
+			cert.Subject.String(),
+			sansEntries,
+			cert.Issuer.String(),
+			certs.FormatCertSerialNumber(cert.SerialNumber),
+			cert.NotBefore.Format(certs.CertValidityDateLayout),
+			cert.NotAfter.Format(certs.CertValidityDateLayout),
+			certs.ExpirationStatus(
+				cert,
+				ageCritical,
+				ageWarning,
+				certs.ShouldCertExpirationBeIgnored(
+					cert,
+					certChain,
+					expirationValidationOptions,
+					ageCritical,
+					ageWarning,
+				),
+			),
+		))
+	}
+}
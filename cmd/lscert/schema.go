@@ -0,0 +1,21 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	_ "embed"
+)
+
+// backfillRecordSchema is the JSON Schema document describing each record
+// emitted by the backfill-dir flag.
+//
+// Changes to the backfill.Record or backfill.Cert types are expected to be
+// reflected here; tests assert that they remain in sync.
+//
+//go:embed schema/backfill-record.schema.json
+var backfillRecordSchema string
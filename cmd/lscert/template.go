@@ -0,0 +1,204 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// templateData is the data structure that a sysadmin-specified template is
+// executed against. Field names and their meaning are documented in the
+// README and should be treated as a stable interface.
+type templateData struct {
+	// Source describes where the certificate chain was obtained from (e.g.,
+	// a filename or a service running on a specific host and port).
+	Source string
+
+	// Generated is the time the template output was generated.
+	Generated time.Time
+
+	// Certs is the evaluated certificate chain in the order provided.
+	Certs []templateCert
+
+	// ValidationResults is the collection of validation check results for
+	// the certificate chain.
+	ValidationResults []templateValidationResult
+}
+
+// templateCert is the template representation of a certificate in the
+// evaluated certificate chain.
+type templateCert struct {
+	// Index is the 1-based position of the certificate in the chain.
+	Index int
+
+	// ChainPosition is the role of the certificate in the chain (e.g.,
+	// leaf, intermediate, root).
+	ChainPosition string
+
+	// Subject is the full Subject distinguished name.
+	Subject string
+
+	// CommonName is the Subject Common Name.
+	CommonName string
+
+	// SANsEntries is the list of DNS Name SANs entries.
+	SANsEntries []string
+
+	// Issuer is the full Issuer distinguished name.
+	Issuer string
+
+	// SerialNumber is the colon delimited hex serial number.
+	SerialNumber string
+
+	// FingerprintSHA256 is the colon delimited SHA-256 fingerprint.
+	FingerprintSHA256 string
+
+	// NotBefore is the start of the certificate validity period.
+	NotBefore time.Time
+
+	// NotAfter is the end of the certificate validity period.
+	NotAfter time.Time
+
+	// DaysRemaining is the number of days until the certificate expires;
+	// negative if already expired.
+	DaysRemaining int
+
+	// ExpirationStatus is the human-readable expiration status of the
+	// certificate.
+	ExpirationStatus string
+
+	// Cert is the underlying certificate for access to fields not otherwise
+	// provided.
+	Cert *x509.Certificate
+}
+
+// templateValidationResult is the template representation of a validation
+// check result for the evaluated certificate chain.
+type templateValidationResult struct {
+	// Name is the human-readable name of the validation check.
+	Name string
+
+	// State is the service check state label (e.g., OK, WARNING).
+	State string
+
+	// Status is a brief status of the validation check result.
+	Status string
+
+	// Overview is a high-level summary of the validation check result.
+	Overview string
+
+	// Ignored indicates whether the validation check result was ignored.
+	Ignored bool
+
+	// Failed indicates whether the validation check failed.
+	Failed bool
+}
+
+// templateFuncs are the helper functions made available to templates in
+// addition to the text/template package builtins.
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// newTemplateData assembles the data structure used to execute a
+// sysadmin-specified template.
+func newTemplateData(
+	certChain []*x509.Certificate,
+	certChainSource string,
+	expirationValidationOptions certs.CertChainValidationOptions,
+	expirationValidationResult certs.ExpirationValidationResult,
+	validationResults ...certs.CertChainValidationResult,
+) templateData {
+
+	ageCritical := expirationValidationResult.AgeCriticalThreshold()
+	ageWarning := expirationValidationResult.AgeWarningThreshold()
+
+	data := templateData{
+		Source:            certChainSource,
+		Generated:         time.Now(),
+		Certs:             make([]templateCert, 0, len(certChain)),
+		ValidationResults: make([]templateValidationResult, 0, len(validationResults)),
+	}
+
+	for idx, cert := range certChain {
+		// An error is only returned for a nil certificate. Expired
+		// certificates report a negative number of days.
+		daysRemaining, _ := certs.ExpiresInDays(cert)
+
+		fingerprint := sha256.Sum256(cert.Raw)
+
+		data.Certs = append(data.Certs, templateCert{
+			Index:             idx + 1,
+			ChainPosition:     certs.ChainPosition(cert, certChain).String(),
+			Subject:           cert.Subject.String(),
+			CommonName:        cert.Subject.CommonName,
+			SANsEntries:       cert.DNSNames,
+			Issuer:            cert.Issuer.String(),
+			SerialNumber:      certs.FormatCertSerialNumber(cert.SerialNumber),
+			FingerprintSHA256: textutils.BytesToDelimitedHexStr(fingerprint[:], ":"),
+			NotBefore:         cert.NotBefore,
+			NotAfter:          cert.NotAfter,
+			DaysRemaining:     daysRemaining,
+			ExpirationStatus: certs.ExpirationStatus(
+				cert,
+				ageCritical,
+				ageWarning,
+				certs.ShouldCertExpirationBeIgnored(
+					cert,
+					certChain,
+					expirationValidationOptions,
+					ageCritical,
+					ageWarning,
+				),
+			),
+			Cert: cert,
+		})
+	}
+
+	for _, result := range validationResults {
+		data.ValidationResults = append(data.ValidationResults, templateValidationResult{
+			Name:     result.CheckName(),
+			State:    result.ServiceState().Label,
+			Status:   result.Status(),
+			Overview: result.Overview(),
+			Ignored:  result.IsIgnored(),
+			Failed:   result.IsFailed(),
+		})
+	}
+
+	return data
+}
+
+// printTemplateReport parses the specified template file and executes it
+// against the given data, writing the output to stdout.
+func printTemplateReport(filename string, data templateData) error {
+	tmpl, err := template.New(filepath.Base(filename)).
+		Funcs(templateFuncs).
+		ParseFiles(filepath.Clean(filename))
+	if err != nil {
+		return fmt.Errorf("failed to parse template file %q: %w", filename, err)
+	}
+
+	if err := tmpl.Execute(os.Stdout, data); err != nil {
+		return fmt.Errorf("failed to execute template file %q: %w", filename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/exechook"
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// watchBaselineSource is the label used in the changes report for the
+// certificate chain retrieved when the application was started.
+const watchBaselineSource string = "initial retrieval"
+
+// certChainFetcher retrieves the current certificate chain.
+type certChainFetcher func() ([]*x509.Certificate, error)
+
+// sameCertChain indicates whether the given certificate chains contain the
+// same certificates in the same order.
+func sameCertChain(a []*x509.Certificate, b []*x509.Certificate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !bytes.Equal(a[i].Raw, b[i].Raw) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// watchCertChain retrieves the certificate chain once per interval until it
+// differs from the given certificate chain, returning the changed
+// certificate chain. Failed retrieval attempts are logged and retried at the
+// next interval. A nil certificate chain is returned if the given context is
+// cancelled before a change is detected.
+func watchCertChain(ctx context.Context, interval time.Duration, certChain []*x509.Certificate, fetch certChainFetcher, log zerolog.Logger) []*x509.Certificate {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := fetch()
+		switch {
+		case err != nil:
+			log.Warn().Err(err).Msg("Error fetching certificates chain, retrying at next interval")
+
+		case len(current) == 0:
+			log.Warn().Err(certs.ErrNoCertsFound).Msg("Retrying at next interval")
+
+		case sameCertChain(certChain, current):
+			log.Info().
+				Str("interval", interval.String()).
+				Msg("Certificate chain unchanged")
+
+		default:
+			return current
+		}
+	}
+}
+
+// leafFingerprint returns the SHA-256 fingerprint of the leaf certificate in
+// the given certificate chain or an empty string if not present.
+func leafFingerprint(certChain []*x509.Certificate) string {
+	leaf := currentLeaf(certChain, time.Now())
+	if leaf == nil {
+		return ""
+	}
+
+	return leaf.FingerprintSHA256
+}
+
+// runWatchHook invokes the sysadmin-specified executable with the given
+// certificate chain changes report provided via stdin. The previous and
+// current leaf certificate fingerprints are provided via environment
+// variables.
+//
+// Output from the executable is logged. The executable is given the same
+// amount of time to complete as is allowed for retrieving a certificate
+// chain.
+func runWatchHook(cfg *config.Config, report []byte, previous []*x509.Certificate, current []*x509.Certificate) error {
+	env := []string{
+		exechook.Var(exechook.EnvServer, cfg.Server),
+		exechook.Var(exechook.EnvPort, strconv.Itoa(cfg.Port)),
+		exechook.Var(exechook.EnvPreviousFingerprint, leafFingerprint(previous)),
+		exechook.Var(exechook.EnvCurrentFingerprint, leafFingerprint(current)),
+	}
+
+	return exechook.Run(cfg.ExecHook, report, env, cfg.Timeout(), cfg.Log)
+}
+
+// runWatch watches for changes to the given certificate chain (retrieved at
+// the given time) until a change is detected or the application is
+// interrupted. Changes are reported using the sysadmin-specified output
+// format and the optional exec hook is invoked. The exit code for the
+// application is returned.
+func runWatch(cfg *config.Config, certChain []*x509.Certificate, retrievedAt time.Time, certChainSource string, fetch certChainFetcher, log zerolog.Logger) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info().
+		Str("interval", cfg.WatchInterval().String()).
+		Msg("Watching for certificate chain changes")
+
+	current := watchCertChain(ctx, cfg.WatchInterval(), certChain, fetch, log)
+	if current == nil {
+		log.Info().Msg("Watch interrupted, no certificate chain changes detected")
+
+		return 0
+	}
+
+	baseline := chainSnapshot{
+		source:     watchBaselineSource,
+		recordedAt: retrievedAt,
+		leaf:       currentLeaf(certChain, retrievedAt),
+		certChain:  certChain,
+	}
+
+	var report bytes.Buffer
+	if err := printDiffReport(&report, baseline, current, certChainSource); err != nil {
+		log.Error().Err(err).Msg("Error generating certificate chain changes report")
+	}
+
+	switch {
+	case cfg.OutputFormat == config.OutputFormatMarkdown:
+		printMarkdownDiffReport(baseline, current, certChainSource)
+
+	default:
+		textutils.PrintHeader("CERTIFICATES | CHANGES DETECTED")
+		fmt.Print(report.String())
+	}
+
+	if cfg.ExecHook != "" {
+		if err := runWatchHook(cfg, report.Bytes(), certChain, current); err != nil {
+			log.Error().Err(err).Msg("Error running exec hook")
+		}
+	}
+
+	return config.ExitCodeCertChainChanged
+}
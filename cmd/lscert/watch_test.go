@@ -0,0 +1,136 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// testWatchInterval is the interval used when watching for certificate chain
+// changes in tests.
+const testWatchInterval time.Duration = 10 * time.Millisecond
+
+// errTestFetch is returned by test certificate chain fetchers to simulate a
+// failed retrieval attempt.
+var errTestFetch = errors.New("test fetch failure")
+
+// fetchResult is the result of a single certificate chain retrieval attempt.
+type fetchResult struct {
+	certChain []*x509.Certificate
+	err       error
+}
+
+// newTestFetcher returns a certificate chain fetcher providing the given
+// results in order. The last result is repeated once the others have been
+// provided.
+func newTestFetcher(results ...fetchResult) certChainFetcher {
+	var calls int
+
+	return func() ([]*x509.Certificate, error) {
+		result := results[len(results)-1]
+		if calls < len(results) {
+			result = results[calls]
+		}
+		calls++
+
+		return result.certChain, result.err
+	}
+}
+
+func TestSameCertChain(t *testing.T) {
+	leaf := &x509.Certificate{Raw: []byte{0x01}}
+	intermediate := &x509.Certificate{Raw: []byte{0x02}}
+	renewedLeaf := &x509.Certificate{Raw: []byte{0x03}}
+
+	tests := []struct {
+		name string
+		a    []*x509.Certificate
+		b    []*x509.Certificate
+		want bool
+	}{
+		{
+			name: "Same",
+			a:    []*x509.Certificate{leaf, intermediate},
+			b:    []*x509.Certificate{leaf, intermediate},
+			want: true,
+		},
+		{
+			name: "DifferentLeaf",
+			a:    []*x509.Certificate{leaf, intermediate},
+			b:    []*x509.Certificate{renewedLeaf, intermediate},
+			want: false,
+		},
+		{
+			name: "DifferentOrder",
+			a:    []*x509.Certificate{leaf, intermediate},
+			b:    []*x509.Certificate{intermediate, leaf},
+			want: false,
+		},
+		{
+			name: "DifferentLength",
+			a:    []*x509.Certificate{leaf, intermediate},
+			b:    []*x509.Certificate{leaf},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameCertChain(tt.a, tt.b); got != tt.want {
+				t.Errorf("want %t; got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWatchCertChain(t *testing.T) {
+	leaf := &x509.Certificate{Raw: []byte{0x01}}
+	intermediate := &x509.Certificate{Raw: []byte{0x02}}
+	renewedLeaf := &x509.Certificate{Raw: []byte{0x03}}
+
+	certChain := []*x509.Certificate{leaf, intermediate}
+	changedCertChain := []*x509.Certificate{renewedLeaf, intermediate}
+
+	t.Run("ChangeDetected", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		fetch := newTestFetcher(
+			fetchResult{err: errTestFetch},
+			fetchResult{},
+			fetchResult{certChain: []*x509.Certificate{leaf, intermediate}},
+			fetchResult{certChain: changedCertChain},
+		)
+
+		got := watchCertChain(ctx, testWatchInterval, certChain, fetch, zerolog.Nop())
+		if !sameCertChain(got, changedCertChain) {
+			t.Errorf("want changed certificate chain; got %v", got)
+		}
+	})
+
+	t.Run("CancelledWithoutChange", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*testWatchInterval)
+		defer cancel()
+
+		fetch := newTestFetcher(
+			fetchResult{certChain: []*x509.Certificate{leaf, intermediate}},
+		)
+
+		if got := watchCertChain(ctx, testWatchInterval, certChain, fetch, zerolog.Nop()); got != nil {
+			t.Errorf("want nil certificate chain; got %v", got)
+		}
+	})
+}
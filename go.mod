@@ -3,10 +3,13 @@ module github.com/atc0005/check-cert
 go 1.20
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/atc0005/cert-payload v0.7.1
 	github.com/atc0005/go-nagios v0.19.0
 	github.com/grantae/certinfo v0.0.0-20170412194111-59d56a35515b
 	github.com/rs/zerolog v1.33.0
+	gopkg.in/yaml.v3 v3.0.1
+	software.sslmate.com/src/go-pkcs12 v0.4.0
 )
 
 // Allow for testing local changes before they're published.
@@ -16,5 +19,6 @@ require (
 require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
+	golang.org/x/crypto v0.11.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 )
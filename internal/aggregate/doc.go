@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package aggregate provides types and helper functions used to bundle the
+// certificate metadata payloads for multiple targets into a single encoded
+// block.
+package aggregate
@@ -0,0 +1,137 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package aggregate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/atc0005/check-cert/internal/certpayload"
+)
+
+// FormatVersion is the version of the aggregate payload structure. This is
+// tracked separately from the format version of the per-target certificate
+// metadata payloads bundled within it.
+const FormatVersion int = 1
+
+// ErrMissingValue indicates that an expected value was missing.
+var ErrMissingValue = errors.New("missing expected value")
+
+// Target is the certificate metadata for a single target evaluated as part
+// of a batch.
+type Target struct {
+	// Server is the host value (FQDN or IP Address) used to retrieve the
+	// certificate chain.
+	Server string `json:"server"`
+
+	// IPAddress is the resolved IP Address used to retrieve the certificate
+	// chain.
+	IPAddress string `json:"ip_address"`
+
+	// DNSName is the (optional) DNS Name value used for SNI support and
+	// hostname verification.
+	DNSName string `json:"dns_name,omitempty"`
+
+	// TCPPort is the port of the remote certificate-enabled service.
+	TCPPort int `json:"tcp_port"`
+
+	// ServiceState is the service check state label for this target.
+	ServiceState string `json:"service_state"`
+
+	// CertPayload is the encoded certificate metadata payload for this
+	// target. The payload is embedded as-is so that collectors are able to
+	// decode each entry using the same logic applied to single target
+	// payloads.
+	CertPayload json.RawMessage `json:"cert_payload"`
+}
+
+// Payload is a collection of per-target certificate metadata payloads.
+type Payload struct {
+	// FormatVersion is the version of this aggregate payload structure.
+	FormatVersion int `json:"aggregate_format_version"`
+
+	// CertPayloadFormatVersion is the format version used for every
+	// per-target certificate metadata payload in the collection.
+	CertPayloadFormatVersion int `json:"cert_payload_format_version"`
+
+	// ServiceState is the service check state label for the combined
+	// result of all targets in the collection.
+	ServiceState string `json:"service_state"`
+
+	// Targets is the collection of per-target certificate metadata.
+	Targets []Target `json:"targets"`
+}
+
+// New creates a new, empty aggregate payload using the specified certificate
+// metadata payload format version for each target added to it.
+func New(certPayloadFormatVersion int) *Payload {
+	return &Payload{
+		FormatVersion:            FormatVersion,
+		CertPayloadFormatVersion: certPayloadFormatVersion,
+		Targets:                  make([]Target, 0, 1),
+	}
+}
+
+// Add generates a certificate metadata payload from the given input values
+// (including connection metadata) and appends it to the collection. An error
+// is returned if the payload cannot be generated.
+func (p *Payload) Add(values certpayload.Values) error {
+	if p == nil {
+		return fmt.Errorf(
+			"aggregate payload not initialized: %w",
+			ErrMissingValue,
+		)
+	}
+
+	inputData := values.Values
+
+	certPayload, err := certpayload.Encode(p.CertPayloadFormatVersion, values)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to encode payload for %s (%s) at port %d: %w",
+			inputData.Server.HostValue,
+			inputData.Server.IPAddress,
+			inputData.TCPPort,
+			err,
+		)
+	}
+
+	p.Targets = append(p.Targets, Target{
+		Server:       inputData.Server.HostValue,
+		IPAddress:    inputData.Server.IPAddress,
+		DNSName:      inputData.DNSName,
+		TCPPort:      inputData.TCPPort,
+		ServiceState: inputData.ServiceState,
+		CertPayload:  certPayload,
+	})
+
+	return nil
+}
+
+// NumTargets returns the number of targets in the collection.
+func (p *Payload) NumTargets() int {
+	if p == nil {
+		return 0
+	}
+
+	return len(p.Targets)
+}
+
+// Encode returns the aggregate payload as a JSON encoded value. An error is
+// returned if the collection is empty or if encoding fails.
+func (p *Payload) Encode() ([]byte, error) {
+	if p.NumTargets() == 0 {
+		return nil, fmt.Errorf(
+			"aggregate payload has no targets: %w",
+			ErrMissingValue,
+		)
+	}
+
+	return json.Marshal(p)
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package aggregate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	payload "github.com/atc0005/cert-payload"
+	format1 "github.com/atc0005/cert-payload/format/v1"
+	"github.com/atc0005/cert-payload/input"
+
+	"github.com/atc0005/check-cert/internal/certpayload"
+)
+
+// newTestCert generates a self-signed leaf certificate for the given DNS
+// Name.
+func newTestCert(t *testing.T, dnsName string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsName},
+		DNSNames:     []string{dnsName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert
+}
+
+// newTestValues returns the input values for a target serving a
+// certificate for the given DNS Name.
+func newTestValues(t *testing.T, dnsName string, ipAddr string, port int) input.Values {
+	t.Helper()
+
+	return input.Values{
+		CertChain:                            []*x509.Certificate{newTestCert(t, dnsName)},
+		ExpirationAgeInDaysWarningThreshold:  30,
+		ExpirationAgeInDaysCriticalThreshold: 15,
+		Server:                               input.Server{HostValue: dnsName, IPAddress: ipAddr},
+		DNSName:                              dnsName,
+		TCPPort:                              port,
+		ServiceState:                         "OK",
+	}
+}
+
+func TestPayloadEncode(t *testing.T) {
+	bundle := New(format1.FormatVersion)
+
+	targets := []input.Values{
+		newTestValues(t, "www.example.com", "192.0.2.10", 443),
+		newTestValues(t, "mail.example.com", "192.0.2.20", 993),
+	}
+
+	for _, target := range targets {
+		values := certpayload.Values{
+			Values:     target,
+			Connection: certpayload.Connection{IPAddress: target.Server.IPAddress, ServerName: target.DNSName},
+		}
+		if err := bundle.Add(values); err != nil {
+			t.Fatalf("failed to add target: %v", err)
+		}
+	}
+
+	if bundle.NumTargets() != len(targets) {
+		t.Fatalf("want %d targets; got %d", len(targets), bundle.NumTargets())
+	}
+
+	encoded, err := bundle.Encode()
+	if err != nil {
+		t.Fatalf("failed to encode aggregate payload: %v", err)
+	}
+
+	var decoded Payload
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode aggregate payload: %v", err)
+	}
+
+	switch {
+	case decoded.FormatVersion != FormatVersion:
+		t.Errorf("want aggregate format version %d; got %d", FormatVersion, decoded.FormatVersion)
+	case decoded.CertPayloadFormatVersion != format1.FormatVersion:
+		t.Errorf("want cert payload format version %d; got %d", format1.FormatVersion, decoded.CertPayloadFormatVersion)
+	case len(decoded.Targets) != len(targets):
+		t.Fatalf("want %d decoded targets; got %d", len(targets), len(decoded.Targets))
+	}
+
+	// Each embedded payload is a certificate metadata payload with the
+	// connection metadata added.
+	for i, target := range decoded.Targets {
+		want := targets[i]
+
+		if target.Server != want.Server.HostValue || target.IPAddress != want.Server.IPAddress || target.TCPPort != want.TCPPort {
+			t.Errorf("want target %s (%s) at port %d; got %+v", want.Server.HostValue, want.Server.IPAddress, want.TCPPort, target)
+		}
+
+		var certPayload struct {
+			format1.CertChainPayload
+			Connection certpayload.Connection `json:"connection"`
+		}
+		if err := json.Unmarshal(target.CertPayload, &certPayload); err != nil {
+			t.Fatalf("failed to decode payload for target %d: %v", i, err)
+		}
+
+		switch {
+		case certPayload.Server.HostValue != want.Server.HostValue || len(certPayload.CertChainSubset) != 1:
+			t.Errorf("unexpected payload for target %d: %+v", i, certPayload)
+		case certPayload.Connection.IPAddress != want.Server.IPAddress:
+			t.Errorf("want connection IP Address %s for target %d; got %+v", want.Server.IPAddress, i, certPayload.Connection)
+		}
+	}
+}
+
+func TestPayloadErrors(t *testing.T) {
+	var uninitialized *Payload
+
+	tests := []struct {
+		name        string
+		bundle      *Payload
+		values      *input.Values
+		errExpected error
+	}{
+		{
+			name:        "EncodeEmpty",
+			bundle:      New(format1.FormatVersion),
+			errExpected: ErrMissingValue,
+		},
+		{
+			name:        "EncodeUninitialized",
+			bundle:      uninitialized,
+			errExpected: ErrMissingValue,
+		},
+		{
+			name:        "AddUninitialized",
+			bundle:      uninitialized,
+			values:      &input.Values{},
+			errExpected: ErrMissingValue,
+		},
+		{
+			name:        "AddUnsupportedFormatVersion",
+			bundle:      New(payload.MaxPayloadVersion + 1),
+			values:      &input.Values{},
+			errExpected: payload.ErrPayloadFormatVersionTooNew,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			switch {
+			case tt.values != nil:
+				err = tt.bundle.Add(certpayload.Values{Values: *tt.values})
+			default:
+				_, err = tt.bundle.Encode()
+			}
+
+			if !errors.Is(err, tt.errExpected) {
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
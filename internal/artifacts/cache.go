@@ -0,0 +1,344 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+)
+
+// maxArtifactSize is the maximum number of bytes read when downloading an
+// artifact. Some CAs publish CRLs which are several megabytes in size.
+const maxArtifactSize int64 = 64 * 1024 * 1024
+
+// File name extensions used for cache entries.
+const (
+	dataFileExt     string = ".bin"
+	metadataFileExt string = ".json"
+)
+
+var (
+	// ErrMissingValue indicates that an expected value was missing.
+	ErrMissingValue = errors.New("missing expected value")
+
+	// ErrInvalidCacheDir indicates that the specified cache directory is not
+	// usable.
+	ErrInvalidCacheDir = errors.New("invalid cache directory")
+
+	// ErrDownloadFailed indicates that an artifact could not be downloaded.
+	ErrDownloadFailed = errors.New("artifact download failed")
+)
+
+// entryMetadata records details of a cached artifact.
+type entryMetadata struct {
+	// URL is the location the artifact was downloaded from.
+	URL string `json:"url"`
+
+	// FetchedAt is the time the artifact was downloaded.
+	FetchedAt time.Time `json:"fetched_at"`
+
+	// ExpiresAt is the time after which the artifact is downloaded again.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Cache is an on-disk cache for downloaded artifacts. Cached CRLs are kept
+// until their nextUpdate time; all other artifacts are kept for the default
+// TTL.
+type Cache struct {
+	// dir is the directory used to store cached artifacts.
+	dir string
+
+	// defaultTTL is how long artifacts without an embedded expiration are
+	// cached.
+	defaultTTL time.Duration
+
+	// timeout is the time allowed for an artifact download to complete.
+	timeout time.Duration
+
+	// client is the HTTP client used to download artifacts.
+	client *http.Client
+
+	// netBudget limits the external requests made and bytes downloaded.
+	// Artifacts served from the cache are not recorded against the budget.
+	netBudget *budget.Budget
+}
+
+// New creates a cache using the specified existing directory. Artifacts
+// without an embedded expiration are cached for the given default TTL and
+// downloads are abandoned after the given timeout. Downloads are recorded
+// against the given network budget; a nil budget imposes no limits.
+func New(dir string, defaultTTL time.Duration, timeout time.Duration, netBudget *budget.Budget) (*Cache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf(
+			"cache directory not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	fi, err := os.Stat(dir)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf(
+			"cache directory %q not usable: %w: %w",
+			dir,
+			ErrInvalidCacheDir,
+			err,
+		)
+
+	case !fi.IsDir():
+		return nil, fmt.Errorf(
+			"cache directory %q is not a directory: %w",
+			dir,
+			ErrInvalidCacheDir,
+		)
+	}
+
+	return &Cache{
+		dir:        dir,
+		defaultTTL: defaultTTL,
+		timeout:    timeout,
+		client:     &http.Client{},
+		netBudget:  netBudget,
+	}, nil
+}
+
+// Fetch returns the artifact at the given URL. A cached copy is returned if
+// it has not yet expired, otherwise the artifact is downloaded and the cache
+// updated. Failure to update the cache does not prevent a downloaded
+// artifact from being returned.
+func (c *Cache) Fetch(url string) ([]byte, error) {
+	if data, ok := c.cached(url, time.Now()); ok {
+		return data, nil
+	}
+
+	data, err := c.download(url)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	meta := entryMetadata{
+		URL:       url,
+		FetchedAt: now.UTC(),
+		ExpiresAt: expiresAt(data, now, c.defaultTTL).UTC(),
+	}
+
+	// The artifact is still usable even if caching it fails; the next
+	// request downloads it again.
+	_ = c.store(url, data, meta)
+
+	return data, nil
+}
+
+// entryPath returns the path (without extension) used to store the cache
+// entry for the given URL.
+func (c *Cache) entryPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// cached returns the cached copy of the artifact at the given URL if present
+// and unexpired as of the given time.
+func (c *Cache) cached(url string, now time.Time) ([]byte, bool) {
+	base := c.entryPath(url)
+
+	metaData, err := os.ReadFile(filepath.Clean(base + metadataFileExt))
+	if err != nil {
+		return nil, false
+	}
+
+	var meta entryMetadata
+	if err := json.Unmarshal(metaData, &meta); err != nil {
+		return nil, false
+	}
+
+	// Guard against (unlikely) hash collisions and stale entries.
+	if meta.URL != url || !now.Before(meta.ExpiresAt) {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Clean(base + dataFileExt))
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// download retrieves the artifact at the given URL.
+func (c *Cache) download(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to prepare request for %q: %w: %w",
+			url,
+			ErrDownloadFailed,
+			err,
+		)
+	}
+
+	if err := c.netBudget.Request(); err != nil {
+		return nil, fmt.Errorf(
+			"skipped download of %q: %w: %w",
+			url,
+			ErrDownloadFailed,
+			err,
+		)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to download %q: %w: %w",
+			url,
+			ErrDownloadFailed,
+			err,
+		)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"unexpected response downloading %q: %s: %w",
+			url,
+			resp.Status,
+			ErrDownloadFailed,
+		)
+	}
+
+	data, err := io.ReadAll(c.netBudget.Reader(io.LimitReader(resp.Body, maxArtifactSize)))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read %q: %w: %w",
+			url,
+			ErrDownloadFailed,
+			err,
+		)
+	}
+
+	return data, nil
+}
+
+// store records the given artifact and metadata in the cache. The data file
+// is written before the metadata file so that an interrupted write does not
+// leave behind metadata for a partial artifact.
+func (c *Cache) store(url string, data []byte, meta entryMetadata) error {
+	base := c.entryPath(url)
+
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache metadata for %q: %w", url, err)
+	}
+
+	if err := writeFileAtomic(base+dataFileExt, data); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(base+metadataFileExt, metaData)
+}
+
+// Purge removes cache entries which expired before the given time. The
+// number of removed entries is returned.
+func (c *Cache) Purge(now time.Time) (int, error) {
+	matches, err := filepath.Glob(filepath.Join(c.dir, "*"+metadataFileExt))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list cache entries: %w", err)
+	}
+
+	var removed int
+	for _, metaFile := range matches {
+		metaData, err := os.ReadFile(filepath.Clean(metaFile))
+		if err != nil {
+			continue
+		}
+
+		var meta entryMetadata
+		if err := json.Unmarshal(metaData, &meta); err == nil && now.Before(meta.ExpiresAt) {
+			continue
+		}
+
+		base := metaFile[:len(metaFile)-len(metadataFileExt)]
+		for _, file := range []string{metaFile, base + dataFileExt} {
+			if err := os.Remove(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return removed, fmt.Errorf("failed to remove cache entry %q: %w", file, err)
+			}
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+// expiresAt returns the time after which the given artifact should be
+// downloaded again. CRLs are cached until their nextUpdate time; all other
+// artifacts (or CRLs with a nextUpdate time already passed) are cached for
+// the given default TTL.
+func expiresAt(data []byte, now time.Time, defaultTTL time.Duration) time.Time {
+	der := data
+	if block, _ := pem.Decode(data); block != nil {
+		der = block.Bytes
+	}
+
+	if crl, err := x509.ParseRevocationList(der); err == nil {
+		if crl.NextUpdate.After(now) {
+			return crl.NextUpdate
+		}
+	}
+
+	return now.Add(defaultTTL)
+}
+
+// writeFileAtomic writes the given data to a temporary file in the same
+// directory as the specified file and then renames it into place.
+func writeFileAtomic(filename string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %q: %w", filename, err)
+	}
+
+	// Remove the temporary file if it is not successfully renamed.
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+
+		return fmt.Errorf("failed to write %q: %w", filename, err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close %q: %w", filename, err)
+	}
+
+	if err := os.Rename(tmpFile.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace %q: %w", filename, err)
+	}
+
+	return nil
+}
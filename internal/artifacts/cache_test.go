@@ -0,0 +1,243 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package artifacts
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestCRL generates a DER encoded CRL with the given nextUpdate time.
+func newTestCRL(t *testing.T, nextUpdate time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create CA certificate: %v", err)
+	}
+
+	issuer, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse CA certificate: %v", err)
+	}
+
+	crl, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: nextUpdate.Add(-24 * time.Hour),
+		NextUpdate: nextUpdate,
+	}, issuer, key)
+	if err != nil {
+		t.Fatalf("failed to create CRL: %v", err)
+	}
+
+	return crl
+}
+
+// newTestServer returns a server responding to all requests with the given
+// status code and body along with a counter of the requests received.
+func newTestServer(t *testing.T, statusCode int, body []byte) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(statusCode)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		err  error
+	}{
+		{name: "ExistingDirectory", dir: t.TempDir()},
+		{name: "EmptyPath", dir: "", err: ErrMissingValue},
+		{name: "MissingDirectory", dir: filepath.Join(t.TempDir(), "missing"), err: ErrInvalidCacheDir},
+		{name: "NotADirectory", dir: file, err: ErrInvalidCacheDir},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(tt.dir, time.Hour, time.Second, nil)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("want error %v; got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestFetchUsesCachedCopy(t *testing.T) {
+	t.Parallel()
+
+	body := []byte("intermediate certificate")
+	server, requests := newTestServer(t, http.StatusOK, body)
+
+	cache, err := New(t.TempDir(), time.Hour, 5*time.Second, nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		data, err := cache.Fetch(server.URL)
+		if err != nil {
+			t.Fatalf("fetch %d failed: %v", i+1, err)
+		}
+
+		if !bytes.Equal(data, body) {
+			t.Fatalf("fetch %d returned %q; want %q", i+1, data, body)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("want 1 download; got %d", got)
+	}
+}
+
+func TestFetchDownloadFailure(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newTestServer(t, http.StatusNotFound, nil)
+
+	cache, err := New(t.TempDir(), time.Hour, 5*time.Second, nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	if _, err := cache.Fetch(server.URL); !errors.Is(err, ErrDownloadFailed) {
+		t.Errorf("want error %v; got %v", ErrDownloadFailed, err)
+	}
+
+	if _, ok := cache.cached(server.URL, time.Now()); ok {
+		t.Error("failed download was cached")
+	}
+}
+
+func TestExpiresAt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	defaultTTL := time.Hour
+	nextUpdate := now.Add(7 * 24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name string
+		data []byte
+		want time.Time
+	}{
+		{
+			name: "CRLWithFutureNextUpdate",
+			data: newTestCRL(t, nextUpdate),
+			want: nextUpdate,
+		},
+		{
+			name: "CRLWithPastNextUpdate",
+			data: newTestCRL(t, now.Add(-time.Minute)),
+			want: now.Add(defaultTTL),
+		},
+		{
+			name: "NotACRL",
+			data: []byte("intermediate certificate"),
+			want: now.Add(defaultTTL),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := expiresAt(tt.data, now, defaultTTL); !got.Equal(tt.want) {
+				t.Errorf("want %v; got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newTestServer(t, http.StatusOK, []byte("artifact"))
+	crlServer, _ := newTestServer(t, http.StatusOK, newTestCRL(t, time.Now().Add(30*24*time.Hour)))
+
+	defaultTTL := time.Hour
+	cache, err := New(t.TempDir(), defaultTTL, 5*time.Second, nil)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+
+	for _, url := range []string{server.URL, crlServer.URL} {
+		if _, err := cache.Fetch(url); err != nil {
+			t.Fatalf("fetch of %q failed: %v", url, err)
+		}
+	}
+
+	// Only the artifact cached for the default TTL has expired.
+	removed, err := cache.Purge(time.Now().Add(2 * defaultTTL))
+	if err != nil {
+		t.Fatalf("purge failed: %v", err)
+	}
+
+	if removed != 1 {
+		t.Errorf("want 1 removed entry; got %d", removed)
+	}
+
+	if _, ok := cache.cached(crlServer.URL, time.Now()); !ok {
+		t.Error("unexpired CRL was removed")
+	}
+
+	if _, err := cache.Fetch(server.URL); err != nil {
+		t.Fatalf("fetch after purge failed: %v", err)
+	}
+
+	if got := requests.Load(); got != 2 {
+		t.Errorf("want 2 downloads; got %d", got)
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package artifacts provides an on-disk cache for downloaded certificate
+// revocation and Authority Information Access (AIA) artifacts such as CRLs
+// and issuer certificates.
+package artifacts
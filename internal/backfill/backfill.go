@@ -0,0 +1,317 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/textutils"
+	"github.com/atc0005/go-nagios"
+)
+
+// Methods used to produce a normalized record from saved plugin output.
+const (
+	// MethodPayload indicates that the record was produced by extracting and
+	// decoding the certificate metadata payload embedded in plugin output.
+	MethodPayload string = "payload"
+
+	// MethodReport indicates that the record was produced by parsing the
+	// human-readable report as the plugin output did not include a usable
+	// certificate metadata payload.
+	MethodReport string = "report"
+)
+
+// ErrUnrecognizedOutput indicates that given content was not recognized as
+// check_cert plugin output.
+var ErrUnrecognizedOutput = errors.New("unrecognized plugin output")
+
+var (
+	// sourceRegex matches the report line describing where the certificate
+	// chain was retrieved from.
+	sourceRegex = regexp.MustCompile(
+		`certs retrieved for service running on (\S+)(?: \(([^)]+)\))? at port (\d+)(?: using host value "([^"]*)")?`,
+	)
+
+	// certHeaderRegex matches the report line which begins the details for
+	// each certificate in the chain.
+	certHeaderRegex = regexp.MustCompile(`^Certificate \d+ of \d+ \(([^)]+)\):`)
+
+	// sansEntriesRegex matches the report line listing SANs entries for a
+	// certificate.
+	sansEntriesRegex = regexp.MustCompile(`^SANs entries \(\d+\): \[(.*)\]$`)
+)
+
+// Cert is the certificate metadata recovered from the human-readable report.
+type Cert struct {
+	Subject           string     `json:"subject"`
+	SANsEntries       []string   `json:"sans_entries"`
+	Issuer            string     `json:"issuer"`
+	SerialNumber      string     `json:"serial"`
+	FingerprintSHA256 string     `json:"fingerprint_sha256,omitempty"`
+	NotBefore         *time.Time `json:"not_before,omitempty"`
+	NotAfter          *time.Time `json:"not_after,omitempty"`
+	ChainPosition     string     `json:"chain_position"`
+	Status            string     `json:"status"`
+}
+
+// Record is the normalized representation of a single saved check_cert
+// plugin output.
+type Record struct {
+	// SourceFile is the path to the file containing the plugin output.
+	SourceFile string `json:"source_file"`
+
+	// Method indicates how the record was produced.
+	Method string `json:"method"`
+
+	// ServiceState is the service check state label for the plugin output.
+	ServiceState string `json:"service_state"`
+
+	// Summary is the one-line summary (service output) without the state
+	// label prefix.
+	Summary string `json:"summary"`
+
+	// Server is the host value (FQDN or IP Address) recovered from the
+	// report.
+	Server string `json:"server,omitempty"`
+
+	// IPAddress is the IP Address recovered from the report.
+	IPAddress string `json:"ip_address,omitempty"`
+
+	// DNSName is the host value used for SNI support and hostname
+	// verification recovered from the report.
+	DNSName string `json:"dns_name,omitempty"`
+
+	// TCPPort is the port of the remote certificate-enabled service
+	// recovered from the report.
+	TCPPort int `json:"tcp_port,omitempty"`
+
+	// PerfData is the collection of performance data metrics indexed by
+	// label. Each value includes the unit of measurement (if any).
+	PerfData map[string]string `json:"perfdata,omitempty"`
+
+	// Certs is the certificate metadata recovered from the report. This is
+	// only populated for records produced from the human-readable report.
+	Certs []Cert `json:"certs,omitempty"`
+
+	// Payload is the decoded certificate metadata payload. This is only
+	// populated for records produced from an embedded payload.
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// ParseOutput converts the given saved check_cert plugin output into a
+// normalized record. The embedded certificate metadata payload is used if
+// present, otherwise the human-readable report is parsed. An error is
+// returned if the content is not recognized as plugin output.
+func ParseOutput(sourceFile string, content string) (Record, error) {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) == "" {
+		return Record{}, fmt.Errorf(
+			"%s: empty input: %w",
+			sourceFile,
+			ErrUnrecognizedOutput,
+		)
+	}
+
+	state, summary, found := strings.Cut(lines[0], ":")
+	state = strings.TrimSpace(state)
+	if !found || !textutils.InList(state, nagios.SupportedStateLabels(), false) {
+		return Record{}, fmt.Errorf(
+			"%s: service check state not found in first line: %w",
+			sourceFile,
+			ErrUnrecognizedOutput,
+		)
+	}
+
+	// Performance data may be included on the first line when there is no
+	// long service output.
+	summary, _, _ = strings.Cut(summary, "|")
+
+	record := Record{
+		SourceFile:   sourceFile,
+		ServiceState: state,
+		Summary:      strings.TrimSpace(summary),
+		PerfData:     parsePerfData(lines),
+	}
+
+	decodedPayload, payloadErr := nagios.ExtractAndDecodePayload(
+		content,
+		"",
+		nagios.DefaultASCII85EncodingDelimiterLeft,
+		nagios.DefaultASCII85EncodingDelimiterRight,
+	)
+	if payloadErr == nil && json.Valid([]byte(decodedPayload)) {
+		record.Method = MethodPayload
+		record.Payload = json.RawMessage(decodedPayload)
+
+		return record, nil
+	}
+
+	record.Method = MethodReport
+	parseReport(&record, lines)
+
+	return record, nil
+}
+
+// ParseDir converts each file within the given directory (and any
+// subdirectories) into a normalized record. Records are returned in file
+// path order along with an error for each file which could not be read or
+// was not recognized as plugin output.
+func ParseDir(dir string) ([]Record, []error) {
+	var paths []string
+
+	walkErr := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if walkErr != nil {
+		return nil, []error{fmt.Errorf("failed to read directory %s: %w", dir, walkErr)}
+	}
+
+	sort.Strings(paths)
+
+	records := make([]Record, 0, len(paths))
+
+	var errs []error
+	for _, path := range paths {
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to read file %s: %w", path, err))
+
+			continue
+		}
+
+		record, err := ParseOutput(path, string(content))
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, errs
+}
+
+// parsePerfData returns the performance data metrics found in the given
+// plugin output lines indexed by label. Performance data is emitted after the
+// last pipe character of the output.
+func parsePerfData(lines []string) map[string]string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		_, rawPerfData, found := strings.Cut(lines[i], "|")
+		if !found {
+			continue
+		}
+
+		perfData, err := nagios.ParsePerfData(strings.TrimSpace(rawPerfData))
+		if err != nil {
+			return nil
+		}
+
+		metrics := make(map[string]string, len(perfData))
+		for _, pd := range perfData {
+			metrics[pd.Label] = pd.Value + pd.UnitOfMeasurement
+		}
+
+		return metrics
+	}
+
+	return nil
+}
+
+// parseReport recovers the certificate chain source and certificate metadata
+// from the human-readable report in the given plugin output lines.
+func parseReport(record *Record, lines []string) {
+	var cert *Cert
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if matches := sourceRegex.FindStringSubmatch(line); matches != nil && record.TCPPort == 0 {
+			record.Server = matches[1]
+			record.IPAddress = matches[2]
+			record.TCPPort, _ = strconv.Atoi(matches[3])
+			record.DNSName = matches[4]
+
+			continue
+		}
+
+		if matches := certHeaderRegex.FindStringSubmatch(line); matches != nil {
+			record.Certs = append(record.Certs, Cert{
+				ChainPosition: matches[1],
+				SANsEntries:   []string{},
+			})
+			cert = &record.Certs[len(record.Certs)-1]
+
+			continue
+		}
+
+		if cert == nil {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ": ")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "Name":
+			cert.Subject = value
+		case "Issuer":
+			cert.Issuer = value
+		case "Serial":
+			cert.SerialNumber = value
+		case "Fingerprint (SHA-256)":
+			cert.FingerprintSHA256 = value
+		case "Issued On":
+			cert.NotBefore = parseValidityDate(value)
+		case "Expiration":
+			cert.NotAfter = parseValidityDate(value)
+		case "Status":
+			cert.Status = value
+		default:
+			if matches := sansEntriesRegex.FindStringSubmatch(line); matches != nil {
+				cert.SANsEntries = strings.Fields(matches[1])
+			}
+		}
+	}
+}
+
+// parseValidityDate parses a certificate validity date as formatted in the
+// human-readable report. nil is returned if the value cannot be parsed.
+func parseValidityDate(value string) *time.Time {
+	t, err := time.Parse(certs.CertValidityDateLayout, value)
+	if err != nil {
+		return nil
+	}
+
+	t = t.UTC()
+
+	return &t
+}
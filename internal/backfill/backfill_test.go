@@ -0,0 +1,230 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// reportOutput is saved check_cert plugin output without an embedded
+// certificate metadata payload.
+const reportOutput = `WARNING: 1 of 2 certificates expiring soon
+**ERRORS**
+
+* None
+
+**THRESHOLDS**
+
+* CRITICAL: Expires before 2024-01-10 (30 days)
+
+**DETAILED INFO**
+
+2 certs retrieved for service running on www.example.com (93.184.216.34) at port 443 using host value "www.example.com"
+
+Certificate 1 of 2 (leaf):
+	Name: CN=www.example.com
+	SANs entries (2): [www.example.com example.com]
+	Issuer: CN=Example Intermediate CA
+	Fingerprint (SHA-256): AA:BB:CC
+	Serial: 01:02:03
+	Issued On: 2023-12-01 00:00:00 +0000 UTC
+	Expiration: 2024-01-20 00:00:00 +0000 UTC
+	Status: [WARNING] 10d 0h remaining
+
+Certificate 2 of 2 (intermediate):
+	Name: CN=Example Intermediate CA
+	SANs entries (0): []
+	Issuer: CN=Example Root CA
+	Serial: 04:05
+	Issued On: not a date
+	Status: [OK] 3650d 0h remaining
+ | 'expires_leaf'=10d;30;15;; 'certs_present_leaf'=1;;;;
+`
+
+func TestParseOutputReport(t *testing.T) {
+	record, err := ParseOutput("report.txt", reportOutput)
+	if err != nil {
+		t.Fatalf("want: no error; got: %v", err)
+	}
+
+	notBefore := time.Date(2023, time.December, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)
+
+	want := Record{
+		SourceFile:   "report.txt",
+		Method:       MethodReport,
+		ServiceState: nagios.StateWARNINGLabel,
+		Summary:      "1 of 2 certificates expiring soon",
+		Server:       "www.example.com",
+		IPAddress:    "93.184.216.34",
+		DNSName:      "www.example.com",
+		TCPPort:      443,
+		PerfData: map[string]string{
+			"expires_leaf":       "10d",
+			"certs_present_leaf": "1",
+		},
+		Certs: []Cert{
+			{
+				Subject:           "CN=www.example.com",
+				SANsEntries:       []string{"www.example.com", "example.com"},
+				Issuer:            "CN=Example Intermediate CA",
+				SerialNumber:      "01:02:03",
+				FingerprintSHA256: "AA:BB:CC",
+				NotBefore:         &notBefore,
+				NotAfter:          &notAfter,
+				ChainPosition:     "leaf",
+				Status:            "[WARNING] 10d 0h remaining",
+			},
+			{
+				Subject:       "CN=Example Intermediate CA",
+				SANsEntries:   []string{},
+				Issuer:        "CN=Example Root CA",
+				SerialNumber:  "04:05",
+				ChainPosition: "intermediate",
+				Status:        "[OK] 3650d 0h remaining",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("want: %+v; got: %+v", want, record)
+	}
+}
+
+func TestParseOutputPayload(t *testing.T) {
+	payload := `{"format_version":1,"service_state":"OK"}`
+
+	content := "OK: No issues found with certificate chain\n\n" +
+		"**DETAILED INFO**\n\n" +
+		nagios.EncodePayload(
+			[]byte(payload),
+			nagios.DefaultASCII85EncodingDelimiterLeft,
+			nagios.DefaultASCII85EncodingDelimiterRight,
+		) +
+		"\n | 'expires_leaf'=90d;30;15;;\n"
+
+	record, err := ParseOutput("payload.txt", content)
+	if err != nil {
+		t.Fatalf("want: no error; got: %v", err)
+	}
+
+	if record.Method != MethodPayload {
+		t.Errorf("want method %q; got %q", MethodPayload, record.Method)
+	}
+
+	if record.ServiceState != nagios.StateOKLabel {
+		t.Errorf("want state %q; got %q", nagios.StateOKLabel, record.ServiceState)
+	}
+
+	if string(record.Payload) != payload {
+		t.Errorf("want payload %s; got %s", payload, record.Payload)
+	}
+
+	if record.Certs != nil {
+		t.Errorf("want no certs parsed from report; got %+v", record.Certs)
+	}
+
+	if got := record.PerfData["expires_leaf"]; got != "90d" {
+		t.Errorf("want expires_leaf perfdata %q; got %q", "90d", got)
+	}
+
+	if _, err := json.Marshal(record); err != nil {
+		t.Errorf("want record to encode as JSON; got: %v", err)
+	}
+}
+
+func TestParseOutputUnrecognized(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "Empty",
+			content: "\n\n",
+		},
+		{
+			name:    "NoStateLabel",
+			content: "Certificate 1 of 1 (leaf):\n",
+		},
+		{
+			name:    "UnknownStateLabel",
+			content: "FINE: everything is fine\n",
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOutput("input.txt", tt.content)
+			if !errors.Is(err, ErrUnrecognizedOutput) {
+				t.Errorf("want: %v; got: %v", ErrUnrecognizedOutput, err)
+			}
+		})
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		filepath.Join("b", "host2.txt"): "CRITICAL: 1 certificate expired\r\n",
+		"a.txt":                         reportOutput,
+		"notes.txt":                     "not plugin output\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	records, errs := ParseDir(dir)
+
+	if len(errs) != 1 || !errors.Is(errs[0], ErrUnrecognizedOutput) {
+		t.Errorf("want one %v error; got: %v", ErrUnrecognizedOutput, errs)
+	}
+
+	wantFiles := []string{
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "b", "host2.txt"),
+	}
+
+	var gotFiles []string
+	for _, record := range records {
+		gotFiles = append(gotFiles, record.SourceFile)
+	}
+
+	if !reflect.DeepEqual(gotFiles, wantFiles) {
+		t.Fatalf("want records for %v; got %v", wantFiles, gotFiles)
+	}
+
+	if records[1].ServiceState != nagios.StateCRITICALLabel {
+		t.Errorf("want state %q; got %q", nagios.StateCRITICALLabel, records[1].ServiceState)
+	}
+
+	if _, errs := ParseDir(filepath.Join(dir, "missing")); len(errs) != 1 {
+		t.Errorf("want one error for missing directory; got: %v", errs)
+	}
+}
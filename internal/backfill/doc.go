@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package backfill provides helper functions used to convert saved
+// check_cert plugin output into normalized JSON records suitable for
+// importing historical results into payload-based collection systems.
+package backfill
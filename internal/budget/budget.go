@@ -0,0 +1,139 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrExceeded indicates that the network budget for the current execution
+// has been exhausted.
+var ErrExceeded = errors.New("network budget exceeded")
+
+// Budget tracks external requests made and bytes downloaded against
+// configured limits. A Budget is safe for concurrent use. A nil Budget
+// imposes no limits.
+type Budget struct {
+	// mu guards the usage counters.
+	mu sync.Mutex
+
+	// maxRequests is the maximum number of external requests permitted. A
+	// zero value indicates no limit.
+	maxRequests int
+
+	// maxBytes is the maximum number of bytes permitted to be downloaded. A
+	// zero value indicates no limit.
+	maxBytes int64
+
+	// requests is the number of external requests made so far.
+	requests int
+
+	// bytes is the number of bytes downloaded so far.
+	bytes int64
+}
+
+// New creates a budget permitting the given maximum number of external
+// requests and bytes downloaded. A zero value for either limit indicates
+// that the limit is not enforced.
+func New(maxRequests int, maxBytes int64) *Budget {
+	return &Budget{
+		maxRequests: maxRequests,
+		maxBytes:    maxBytes,
+	}
+}
+
+// Request records an external request against the budget. If the request
+// limit has been reached or the download limit exhausted, the request is not
+// recorded and ErrExceeded is returned; the caller is expected to skip the
+// request.
+func (b *Budget) Request() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.maxRequests > 0 && b.requests >= b.maxRequests:
+		return fmt.Errorf(
+			"limit of %d external requests reached: %w",
+			b.maxRequests,
+			ErrExceeded,
+		)
+
+	case b.maxBytes > 0 && b.bytes >= b.maxBytes:
+		return fmt.Errorf(
+			"limit of %d downloaded bytes reached: %w",
+			b.maxBytes,
+			ErrExceeded,
+		)
+	}
+
+	b.requests++
+
+	return nil
+}
+
+// Reader wraps the given reader, recording bytes read against the budget.
+// Once the download limit is exceeded further reads return ErrExceeded.
+func (b *Budget) Reader(r io.Reader) io.Reader {
+	if b == nil {
+		return r
+	}
+
+	return &budgetReader{budget: b, r: r}
+}
+
+// Usage returns the number of external requests made and bytes downloaded
+// so far.
+func (b *Budget) Usage() (int, int64) {
+	if b == nil {
+		return 0, 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.requests, b.bytes
+}
+
+// record adds the given number of bytes to the download total and indicates
+// whether the download limit has been exceeded.
+func (b *Budget) record(n int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.bytes += int64(n)
+
+	return b.maxBytes > 0 && b.bytes > b.maxBytes
+}
+
+// budgetReader is an io.Reader which records bytes read against a budget.
+type budgetReader struct {
+	budget *Budget
+	r      io.Reader
+}
+
+// Read implements the io.Reader interface.
+func (br *budgetReader) Read(p []byte) (int, error) {
+	n, err := br.r.Read(p)
+
+	if br.budget.record(n) {
+		return n, fmt.Errorf(
+			"limit of %d downloaded bytes exceeded: %w",
+			br.budget.maxBytes,
+			ErrExceeded,
+		)
+	}
+
+	return n, err
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package budget provides limits on the number of external requests made and
+// bytes downloaded by network-dependent validation checks during a single
+// execution.
+package budget
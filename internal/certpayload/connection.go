@@ -0,0 +1,127 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certpayload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	format0 "github.com/atc0005/cert-payload/format/v0"
+	format1 "github.com/atc0005/cert-payload/format/v1"
+)
+
+// ErrInvalidPayload indicates that a certificate metadata payload could not
+// be decoded.
+var ErrInvalidPayload = errors.New("invalid certificate metadata payload")
+
+// Connection is the metadata for the connection used to retrieve a
+// certificate chain. This is recorded alongside the certificate metadata so
+// that downstream report tooling does not have to probe the server again.
+type Connection struct {
+	// TLSVersion is the negotiated TLS protocol version (e.g., "TLS 1.3").
+	TLSVersion string `json:"tls_version,omitempty"`
+
+	// CipherSuite is the name of the negotiated cipher suite.
+	CipherSuite string `json:"cipher_suite,omitempty"`
+
+	// ServerName is the Server Name Indication (SNI) value sent to the
+	// server. This is empty if SNI was not used.
+	ServerName string `json:"sni,omitempty"`
+
+	// IPAddress is the IP Address actually connected to.
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// IsZero indicates whether connection metadata was not recorded (e.g., the
+// certificate chain was read from a file).
+func (c Connection) IsZero() bool {
+	return c == Connection{}
+}
+
+// format0Payload is a format version 0 certificate metadata payload with
+// connection metadata added.
+type format0Payload struct {
+	format0.CertChainPayload
+
+	// Connection is the metadata for the connection used to retrieve the
+	// certificate chain.
+	Connection *Connection `json:"connection,omitempty"`
+}
+
+// format1Payload is a format version 1 certificate metadata payload with
+// connection metadata added.
+type format1Payload struct {
+	format1.CertChainPayload
+
+	// Connection is the metadata for the connection used to retrieve the
+	// certificate chain.
+	Connection *Connection `json:"connection,omitempty"`
+}
+
+// AddConnection returns the given encoded certificate metadata payload in
+// the specified format version with the given connection metadata added as
+// an additional top-level field. The existing fields are left as-is so that
+// the payload remains decodable by tooling unaware of the additional field.
+// The payload is returned unchanged if connection metadata was not recorded.
+func AddConnection(formatVersion int, certPayload []byte, conn Connection) ([]byte, error) {
+	if conn.IsZero() {
+		return certPayload, nil
+	}
+
+	var updated interface{}
+
+	switch formatVersion {
+	case format0.FormatVersion:
+		p := format0Payload{Connection: &conn}
+		if err := decodeStrict(certPayload, &p.CertChainPayload); err != nil {
+			return nil, err
+		}
+		updated = p
+
+	case format1.FormatVersion:
+		p := format1Payload{Connection: &conn}
+		if err := decodeStrict(certPayload, &p.CertChainPayload); err != nil {
+			return nil, err
+		}
+		updated = p
+
+	default:
+		return nil, fmt.Errorf(
+			"payload version %d specified: %w",
+			formatVersion,
+			ErrUnsupportedFormatVersion,
+		)
+	}
+
+	payloadJSON, err := json.Marshal(updated)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"error marshaling cert chain payload as JSON: %w",
+			err,
+		)
+	}
+
+	return payloadJSON, nil
+}
+
+// decodeStrict decodes the given encoded certificate metadata payload into
+// the given destination. An error is returned if the payload includes fields
+// not supported by the destination (e.g., connection metadata) so that
+// fields are not silently dropped when the payload is encoded again.
+func decodeStrict(certPayload []byte, dest interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(certPayload))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dest); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	return nil
+}
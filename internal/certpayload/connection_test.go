@@ -0,0 +1,138 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certpayload
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	payload "github.com/atc0005/cert-payload"
+	format0 "github.com/atc0005/cert-payload/format/v0"
+	format1 "github.com/atc0005/cert-payload/format/v1"
+	"github.com/atc0005/cert-payload/input"
+)
+
+func TestAddConnection(t *testing.T) {
+	conn := Connection{
+		TLSVersion:  "TLS 1.3",
+		CipherSuite: "TLS_AES_128_GCM_SHA256",
+		ServerName:  "www.example.com",
+		IPAddress:   "192.0.2.10",
+	}
+
+	values := input.Values{
+		CertChain:                            []*x509.Certificate{testCert(t, 100)},
+		ExpirationAgeInDaysWarningThreshold:  30,
+		ExpirationAgeInDaysCriticalThreshold: 15,
+		Server:                               input.Server{HostValue: "www.example.com", IPAddress: "192.0.2.10"},
+		TCPPort:                              443,
+		ServiceState:                         "OK",
+	}
+
+	encode := func(formatVersion int) []byte {
+		encoded, err := payload.Encode(formatVersion, values)
+		if err != nil {
+			t.Fatalf("failed to encode format version %d payload: %v", formatVersion, err)
+		}
+
+		return encoded
+	}
+
+	tests := []struct {
+		name          string
+		formatVersion int
+		payload       []byte
+		conn          Connection
+		wantConn      bool
+		err           error
+	}{
+		{
+			name:          "Format0",
+			formatVersion: format0.FormatVersion,
+			payload:       encode(format0.FormatVersion),
+			conn:          conn,
+			wantConn:      true,
+		},
+		{
+			name:          "Format1",
+			formatVersion: format1.FormatVersion,
+			payload:       encode(format1.FormatVersion),
+			conn:          conn,
+			wantConn:      true,
+		},
+		{
+			name:          "NoConnectionMetadata",
+			formatVersion: format1.FormatVersion,
+			payload:       encode(format1.FormatVersion),
+		},
+		{
+			name:          "NotAnObject",
+			formatVersion: format1.FormatVersion,
+			payload:       []byte(`[1,2]`),
+			conn:          conn,
+			err:           ErrInvalidPayload,
+		},
+		{
+			name:          "FieldAlreadyPresent",
+			formatVersion: format1.FormatVersion,
+			payload:       []byte(`{"format_version":1,"connection":{}}`),
+			conn:          conn,
+			err:           ErrInvalidPayload,
+		},
+		{
+			name:          "UnsupportedFormatVersion",
+			formatVersion: 99,
+			payload:       encode(format1.FormatVersion),
+			conn:          conn,
+			err:           ErrUnsupportedFormatVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AddConnection(tt.formatVersion, tt.payload, tt.conn)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if tt.err != nil {
+				return
+			}
+
+			if !tt.wantConn {
+				if !bytes.Equal(got, tt.payload) {
+					t.Errorf("want payload unchanged:\n%s\ngot:\n%s", tt.payload, got)
+				}
+
+				return
+			}
+
+			// The existing fields are expected as-is and in the same order
+			// ahead of the connection metadata.
+			original := bytes.TrimSuffix(bytes.TrimSpace(tt.payload), []byte("}"))
+			if !bytes.HasPrefix(got, original) {
+				t.Errorf("want payload prefixed by original fields:\n%s\ngot:\n%s", original, got)
+			}
+
+			var decoded struct {
+				Connection Connection `json:"connection"`
+			}
+			if err := json.Unmarshal(got, &decoded); err != nil {
+				t.Fatalf("failed to decode payload: %v", err)
+			}
+
+			if decoded.Connection != tt.conn {
+				t.Errorf("connection = %+v, want %+v", decoded.Connection, tt.conn)
+			}
+		})
+	}
+}
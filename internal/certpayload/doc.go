@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package certpayload provides certificate metadata payload generation for
+// this project. Payloads are generated by the cert-payload module with
+// connection metadata added to payloads of every format version.
+package certpayload
@@ -0,0 +1,49 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certpayload
+
+import (
+	"errors"
+
+	payload "github.com/atc0005/cert-payload"
+	"github.com/atc0005/cert-payload/input"
+)
+
+// ErrUnsupportedFormatVersion indicates that a specified payload format
+// version is unsupported.
+var ErrUnsupportedFormatVersion = errors.New("requested payload format version is unsupported")
+
+// Values is the collection of input data used to generate a certificate
+// metadata payload. This extends the input data accepted by the cert-payload
+// module with details of the connection used to retrieve the certificate
+// chain.
+type Values struct {
+	input.Values
+
+	// Connection is the metadata for the connection used to retrieve the
+	// certificate chain. This is added to payloads of every format version.
+	Connection Connection
+}
+
+// AvailableStableFormatVersions returns the stable payload format versions
+// supported by this package.
+func AvailableStableFormatVersions() []int {
+	return payload.AvailableStableFormatVersions()
+}
+
+// Encode generates a JSON encoded certificate metadata payload in the
+// specified format version from the given input data using the cert-payload
+// module. Connection metadata (if any) is added to the payload.
+func Encode(formatVersion int, values Values) ([]byte, error) {
+	encoded, err := payload.Encode(formatVersion, values.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	return AddConnection(formatVersion, encoded, values.Connection)
+}
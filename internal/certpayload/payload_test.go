@@ -0,0 +1,99 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certpayload
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	payload "github.com/atc0005/cert-payload"
+	format1 "github.com/atc0005/cert-payload/format/v1"
+	"github.com/atc0005/cert-payload/input"
+)
+
+// testCert returns a self-signed ECDSA certificate with the given serial
+// number.
+func testCert(t *testing.T, serial int64) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "www.example.com"},
+		DNSNames:     []string{"www.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert
+}
+
+func TestEncode(t *testing.T) {
+	values := Values{
+		Values: input.Values{
+			CertChain:                            []*x509.Certificate{testCert(t, 100)},
+			ExpirationAgeInDaysWarningThreshold:  30,
+			ExpirationAgeInDaysCriticalThreshold: 15,
+			Server:                               input.Server{HostValue: "www.example.com", IPAddress: "192.0.2.10"},
+			TCPPort:                              443,
+			ServiceState:                         "OK",
+		},
+		Connection: Connection{TLSVersion: "TLS 1.3", IPAddress: "192.0.2.10"},
+	}
+
+	encoded, err := Encode(format1.FormatVersion, values)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var decoded format1Payload
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+
+	if decoded.FormatVersion != format1.FormatVersion {
+		t.Errorf("format version = %d, want %d", decoded.FormatVersion, format1.FormatVersion)
+	}
+
+	if len(decoded.CertChainSubset) != 1 {
+		t.Errorf("got %d certificates, want 1", len(decoded.CertChainSubset))
+	}
+
+	if decoded.Connection == nil || *decoded.Connection != values.Connection {
+		t.Errorf("connection = %+v, want %+v", decoded.Connection, values.Connection)
+	}
+}
+
+func TestEncodeUnsupportedFormatVersion(t *testing.T) {
+	_, err := Encode(payload.MaxPayloadVersion+1, Values{})
+	if !errors.Is(err, payload.ErrPayloadFormatVersionTooNew) {
+		t.Errorf("Encode() error = %v, want %v", err, payload.ErrPayloadFormatVersionTooNew)
+	}
+}
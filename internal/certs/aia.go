@@ -0,0 +1,295 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+)
+
+// aiaMaxResponseSize is the maximum number of bytes read when downloading an
+// issuer certificate from an AIA CA Issuers URL.
+const aiaMaxResponseSize int64 = 1024 * 1024
+
+// aiaMaxChainLength is the maximum number of certificates in a completed
+// certificate chain. This guards against issuer loops and misconfigured AIA
+// URLs.
+const aiaMaxChainLength int = 10
+
+// oidSignedData is the PKCS #7 signed data content type used by "certs-only"
+// bundles published at some AIA CA Issuers URLs.
+var oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+
+// ErrAIAFetchFailed indicates that an issuer certificate could not be
+// retrieved from the AIA CA Issuers URLs listed in a certificate.
+var ErrAIAFetchFailed = errors.New("failed to fetch issuer certificate via AIA")
+
+// pkcs7ContentInfo is the outer PKCS #7 structure.
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// pkcs7SignedData is the PKCS #7 signed data structure. Only the certificates
+// collection is used.
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      asn1.RawValue
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      asn1.RawValue
+}
+
+// ParseIssuerCertificates parses certificates downloaded from an AIA CA
+// Issuers URL. DER encoded certificates, PEM encoded certificates and PKCS #7
+// "certs-only" bundles are supported.
+func ParseIssuerCertificates(data []byte) ([]*x509.Certificate, error) {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		certChain, _, err := ParsePEMCertificates(data)
+
+		return certChain, err
+	}
+
+	if cert, err := x509.ParseCertificate(data); err == nil {
+		return []*x509.Certificate{cert}, nil
+	}
+
+	var contentInfo pkcs7ContentInfo
+	if _, err := asn1.Unmarshal(data, &contentInfo); err != nil {
+		return nil, fmt.Errorf(
+			"data is not a DER, PEM or PKCS #7 encoded certificate: %w",
+			err,
+		)
+	}
+
+	if !contentInfo.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf(
+			"unsupported PKCS #7 content type %s",
+			contentInfo.ContentType,
+		)
+	}
+
+	var signedData pkcs7SignedData
+	if _, err := asn1.Unmarshal(contentInfo.Content.Bytes, &signedData); err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse PKCS #7 signed data: %w",
+			err,
+		)
+	}
+
+	certChain, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse PKCS #7 certificates: %w",
+			err,
+		)
+	}
+
+	if len(certChain) == 0 {
+		return nil, ErrNoCertsFound
+	}
+
+	return certChain, nil
+}
+
+// FetchIssuerCert downloads the issuer of the given certificate using the
+// AIA CA Issuers URLs listed in the certificate. Each URL is tried in turn
+// until a certificate which signed the given certificate is found. Requests
+// are abandoned after the given timeout and recorded against the given
+// network budget; a nil budget imposes no limits.
+func FetchIssuerCert(cert *x509.Certificate, timeout time.Duration, netBudget *budget.Budget) (*x509.Certificate, error) {
+	if len(cert.IssuingCertificateURL) == 0 {
+		return nil, fmt.Errorf(
+			"no AIA CA Issuers URLs listed for %q: %w",
+			cert.Subject.String(),
+			ErrAIAFetchFailed,
+		)
+	}
+
+	var errs []error
+	for _, issuerURL := range cert.IssuingCertificateURL {
+		data, err := fetchAIAURL(issuerURL, timeout, netBudget)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		candidates, err := ParseIssuerCertificates(data)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", issuerURL, err))
+
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if verifySignature(cert, candidate).Matched() {
+				return candidate, nil
+			}
+		}
+
+		errs = append(errs, fmt.Errorf(
+			"%q: no certificate found which signed %q",
+			issuerURL,
+			cert.Subject.String(),
+		))
+	}
+
+	return nil, fmt.Errorf(
+		"%w: %w",
+		ErrAIAFetchFailed,
+		errors.Join(errs...),
+	)
+}
+
+// fetchAIAURL downloads the content at the given AIA CA Issuers URL.
+func fetchAIAURL(issuerURL string, timeout time.Duration, netBudget *budget.Budget) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, issuerURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to prepare request for %q: %w",
+			issuerURL,
+			err,
+		)
+	}
+
+	if err := netBudget.Request(); err != nil {
+		return nil, fmt.Errorf(
+			"skipped request for %q: %w",
+			issuerURL,
+			err,
+		)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to download %q: %w",
+			issuerURL,
+			err,
+		)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"unexpected response for %q: %s",
+			issuerURL,
+			resp.Status,
+		)
+	}
+
+	data, err := io.ReadAll(netBudget.Reader(io.LimitReader(resp.Body, aiaMaxResponseSize)))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read response for %q: %w",
+			issuerURL,
+			err,
+		)
+	}
+
+	return data, nil
+}
+
+// CompleteCertChain returns the given certificate chain ordered from leaf to
+// root with any missing intermediate certificates downloaded using the AIA
+// CA Issuers URLs listed in each certificate. Root certificates are retained
+// if present in the given certificate chain, but are not downloaded. Any
+// certificates from the given chain which are not part of the path from the
+// leaf certificate are kept after the completed chain.
+//
+// The downloaded certificates are returned separately. If an issuer
+// certificate cannot be downloaded the chain assembled up to that point is
+// returned along with the error.
+func CompleteCertChain(
+	certChain []*x509.Certificate,
+	timeout time.Duration,
+	netBudget *budget.Budget,
+) ([]*x509.Certificate, []*x509.Certificate, error) {
+
+	leafCerts := LeafCerts(certChain)
+	if len(leafCerts) == 0 {
+		return certChain, nil, fmt.Errorf(
+			"unable to complete certificate chain: %w",
+			ErrNoCertsFound,
+		)
+	}
+
+	used := make(map[*x509.Certificate]bool, len(certChain))
+	findIssuer := func(cert *x509.Certificate) *x509.Certificate {
+		for _, candidate := range certChain {
+			if !used[candidate] && verifySignature(cert, candidate).Matched() {
+				return candidate
+			}
+		}
+
+		return nil
+	}
+
+	current := leafCerts[0]
+	used[current] = true
+	completed := []*x509.Certificate{current}
+
+	var fetched []*x509.Certificate
+	var fetchErr error
+
+	for !isSelfSigned(current) && len(completed) < aiaMaxChainLength {
+		if issuer := findIssuer(current); issuer != nil {
+			used[issuer] = true
+			completed = append(completed, issuer)
+			current = issuer
+
+			continue
+		}
+
+		// Intermediates issued directly by a root often omit AIA URLs;
+		// this is as complete as the chain can be made.
+		if current != leafCerts[0] && len(current.IssuingCertificateURL) == 0 {
+			break
+		}
+
+		issuer, err := FetchIssuerCert(current, timeout, netBudget)
+		if err != nil {
+			fetchErr = err
+
+			break
+		}
+
+		// Roots are provided by client trust stores.
+		if isSelfSigned(issuer) {
+			break
+		}
+
+		fetched = append(fetched, issuer)
+		completed = append(completed, issuer)
+		current = issuer
+	}
+
+	for _, cert := range certChain {
+		if !used[cert] {
+			completed = append(completed, cert)
+		}
+	}
+
+	return completed, fetched, fetchErr
+}
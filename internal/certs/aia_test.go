@@ -0,0 +1,234 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// pkcs7CertsOnly returns a PKCS #7 "certs-only" bundle holding the given
+// certificates.
+func pkcs7CertsOnly(t *testing.T, certChain ...*x509.Certificate) []byte {
+	t.Helper()
+
+	var certsDER []byte
+	for _, cert := range certChain {
+		certsDER = append(certsDER, cert.Raw...)
+	}
+
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true}
+
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo: asn1.RawValue{
+			Class:      asn1.ClassUniversal,
+			Tag:        asn1.TagSequence,
+			IsCompound: true,
+			Bytes:      []byte{0x06, 0x09, 0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d, 0x01, 0x07, 0x01},
+		},
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certsDER},
+		SignerInfos:  emptySet,
+	})
+	if err != nil {
+		t.Fatalf("failed to encode PKCS #7 signed data: %v", err)
+	}
+
+	bundle, err := asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode PKCS #7 content info: %v", err)
+	}
+
+	return bundle
+}
+
+func TestParseIssuerCertificates(t *testing.T) {
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		want        int
+		errExpected bool
+	}{
+		{
+			name: "DER",
+			data: intermediate.cert.Raw,
+			want: 1,
+		},
+		{
+			name: "PEM",
+			data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate.cert.Raw}),
+			want: 1,
+		},
+		{
+			name: "PKCS7",
+			data: pkcs7CertsOnly(t, intermediate.cert, root.cert),
+			want: 2,
+		},
+		{
+			name:        "PKCS7WithoutCerts",
+			data:        pkcs7CertsOnly(t),
+			errExpected: true,
+		},
+		{
+			name:        "Garbage",
+			data:        []byte("not a certificate"),
+			errExpected: true,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIssuerCertificates(tt.data)
+
+			switch {
+			case tt.errExpected && err == nil:
+				t.Fatal("want: error; got: nil")
+
+			case !tt.errExpected && err != nil:
+				t.Fatalf("want: no error; got: %v", err)
+
+			case tt.errExpected:
+				t.Logf("got expected error: %v", err)
+
+				return
+			}
+
+			if len(got) != tt.want {
+				t.Errorf("want: %d certificates; got: %d", tt.want, len(got))
+			}
+		})
+	}
+}
+
+func TestCompleteCertChain(t *testing.T) {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	withAIA := func(path string) func(*x509.Certificate) {
+		return func(c *x509.Certificate) {
+			c.IssuingCertificateURL = []string{server.URL + path}
+		}
+	}
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, withAIA("/root.der"))
+	otherIntermediate := newTestCert(t, "Other Intermediate CA", true, root, nil)
+	leaf := newTestCert(t, "www.example.com", false, intermediate, withAIA("/intermediate.p7c"))
+	brokenLeaf := newTestCert(t, "broken.example.com", false, intermediate, withAIA("/missing.der"))
+	wrongIssuerLeaf := newTestCert(t, "wrong.example.com", false, intermediate, withAIA("/other.der"))
+
+	mux.HandleFunc("/root.der", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(root.cert.Raw)
+	})
+	mux.HandleFunc("/intermediate.p7c", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(pkcs7CertsOnly(t, intermediate.cert))
+	})
+	mux.HandleFunc("/other.der", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(otherIntermediate.cert.Raw)
+	})
+
+	tests := []struct {
+		name          string
+		certChain     []*x509.Certificate
+		wantCompleted []*x509.Certificate
+		wantFetched   int
+		err           error
+	}{
+		{
+			name:          "MissingIntermediateFetched",
+			certChain:     []*x509.Certificate{leaf.cert},
+			wantCompleted: []*x509.Certificate{leaf.cert, intermediate.cert},
+			wantFetched:   1,
+		},
+		{
+			name:          "MisorderedChainReordered",
+			certChain:     []*x509.Certificate{root.cert, leaf.cert, intermediate.cert},
+			wantCompleted: []*x509.Certificate{leaf.cert, intermediate.cert, root.cert},
+		},
+		{
+			name:          "UnrelatedCertKept",
+			certChain:     []*x509.Certificate{leaf.cert, otherIntermediate.cert, intermediate.cert},
+			wantCompleted: []*x509.Certificate{leaf.cert, intermediate.cert, otherIntermediate.cert},
+		},
+		{
+			name:          "IssuerNotFound",
+			certChain:     []*x509.Certificate{brokenLeaf.cert},
+			wantCompleted: []*x509.Certificate{brokenLeaf.cert},
+			err:           ErrAIAFetchFailed,
+		},
+		{
+			name:          "IssuerMismatch",
+			certChain:     []*x509.Certificate{wrongIssuerLeaf.cert},
+			wantCompleted: []*x509.Certificate{wrongIssuerLeaf.cert},
+			err:           ErrAIAFetchFailed,
+		},
+		{
+			name:          "NoLeaf",
+			certChain:     []*x509.Certificate{intermediate.cert, root.cert},
+			wantCompleted: []*x509.Certificate{intermediate.cert, root.cert},
+			err:           ErrNoCertsFound,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			completed, fetched, err := CompleteCertChain(tt.certChain, 5*time.Second, nil)
+
+			switch {
+			case tt.err == nil && err != nil:
+				t.Fatalf("want: no error; got: %v", err)
+
+			case !errors.Is(err, tt.err):
+				t.Fatalf("want: %v; got: %v", tt.err, err)
+			}
+
+			if len(fetched) != tt.wantFetched {
+				t.Errorf("want: %d fetched certificates; got: %d", tt.wantFetched, len(fetched))
+			}
+
+			if len(completed) != len(tt.wantCompleted) {
+				t.Fatalf("want: %d certificates in chain; got: %d", len(tt.wantCompleted), len(completed))
+			}
+
+			for i := range completed {
+				if !completed[i].Equal(tt.wantCompleted[i]) {
+					t.Errorf(
+						"position %d: want: %q; got: %q",
+						i,
+						tt.wantCompleted[i].Subject.CommonName,
+						completed[i].Subject.CommonName,
+					)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+)
+
+// ariMaxResponseSize is the maximum number of bytes read from an ACME
+// directory or renewal information response. Both are small JSON documents.
+const ariMaxResponseSize int64 = 64 * 1024
+
+// RenewalInfo is the renewal window suggested by a Certificate Authority for
+// a certificate as provided by an ACME Renewal Information (ARI) endpoint
+// (RFC 9773).
+type RenewalInfo struct {
+	// WindowStart is the start of the suggested renewal window.
+	WindowStart time.Time
+
+	// WindowEnd is the end of the suggested renewal window.
+	WindowEnd time.Time
+
+	// ExplanationURL is an optional URL provided by the Certificate
+	// Authority explaining the suggested renewal window (e.g., for a
+	// revocation event).
+	ExplanationURL string
+}
+
+// acmeDirectory is the subset of an ACME directory object used to locate
+// the renewal information endpoint.
+type acmeDirectory struct {
+	RenewalInfo string `json:"renewalInfo"`
+}
+
+// ariResponse is a renewal information response from an ACME server.
+type ariResponse struct {
+	SuggestedWindow struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	} `json:"suggestedWindow"`
+	ExplanationURL string `json:"explanationURL"`
+}
+
+// ARICertID returns the unique identifier for the given certificate used
+// with ACME Renewal Information endpoints. The identifier is constructed
+// from the Authority Key Identifier and serial number of the certificate.
+// An error is returned if the certificate does not have an Authority Key
+// Identifier.
+func ARICertID(cert *x509.Certificate) (string, error) {
+	if cert == nil {
+		return "", fmt.Errorf(
+			"certificate not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", fmt.Errorf(
+			"certificate %q does not have an Authority Key Identifier: %w",
+			cert.Subject.CommonName,
+			ErrMissingValue,
+		)
+	}
+
+	// The serial number is encoded as the content octets of the DER encoded
+	// INTEGER; a leading zero byte is required if the high bit is set.
+	serial := cert.SerialNumber.Bytes()
+	if len(serial) == 0 || serial[0]&0x80 != 0 {
+		serial = append([]byte{0}, serial...)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cert.AuthorityKeyId) +
+		"." +
+		base64.RawURLEncoding.EncodeToString(serial), nil
+}
+
+// FetchRenewalInfo retrieves the renewal window suggested for the given
+// certificate from the ACME Renewal Information endpoint advertised by the
+// ACME directory at the specified URL.
+//
+// The directory and renewal information requests and response sizes are
+// recorded against the given network budget. If the budget is exhausted an
+// error wrapping budget.ErrExceeded is returned.
+func FetchRenewalInfo(directoryURL string, cert *x509.Certificate, timeout time.Duration, netBudget *budget.Budget) (RenewalInfo, error) {
+	certID, err := ARICertID(cert)
+	if err != nil {
+		return RenewalInfo{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var directory acmeDirectory
+	if err := fetchACMEJSON(ctx, directoryURL, &directory, netBudget); err != nil {
+		return RenewalInfo{}, fmt.Errorf(
+			"failed to retrieve ACME directory: %w",
+			err,
+		)
+	}
+
+	if directory.RenewalInfo == "" {
+		return RenewalInfo{}, fmt.Errorf(
+			"ACME directory %q does not provide a renewal information endpoint: %w",
+			directoryURL,
+			ErrMissingValue,
+		)
+	}
+
+	renewalInfoURL := strings.TrimSuffix(directory.RenewalInfo, "/") + "/" + certID
+
+	var response ariResponse
+	if err := fetchACMEJSON(ctx, renewalInfoURL, &response, netBudget); err != nil {
+		return RenewalInfo{}, fmt.Errorf(
+			"failed to retrieve renewal information for certificate %q: %w",
+			cert.Subject.CommonName,
+			err,
+		)
+	}
+
+	windowStart, err := time.Parse(time.RFC3339, response.SuggestedWindow.Start)
+	if err != nil {
+		return RenewalInfo{}, fmt.Errorf(
+			"failed to parse suggested renewal window start value %q: %w",
+			response.SuggestedWindow.Start,
+			err,
+		)
+	}
+
+	windowEnd, err := time.Parse(time.RFC3339, response.SuggestedWindow.End)
+	if err != nil {
+		return RenewalInfo{}, fmt.Errorf(
+			"failed to parse suggested renewal window end value %q: %w",
+			response.SuggestedWindow.End,
+			err,
+		)
+	}
+
+	if !windowEnd.After(windowStart) {
+		return RenewalInfo{}, fmt.Errorf(
+			"invalid suggested renewal window; end %s not after start %s",
+			windowEnd.Format(time.RFC3339),
+			windowStart.Format(time.RFC3339),
+		)
+	}
+
+	return RenewalInfo{
+		WindowStart:    windowStart,
+		WindowEnd:      windowEnd,
+		ExplanationURL: response.ExplanationURL,
+	}, nil
+}
+
+// fetchACMEJSON retrieves the JSON document at the specified URL and decodes
+// it into the given value.
+func fetchACMEJSON(ctx context.Context, u string, v interface{}, netBudget *budget.Budget) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to prepare request for %q: %w", u, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	if err := netBudget.Request(); err != nil {
+		return fmt.Errorf("skipped request for %q: %w", u, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query %q: %w", u, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response from %q: %s", u, resp.Status)
+	}
+
+	body, err := io.ReadAll(netBudget.Reader(io.LimitReader(resp.Body, ariMaxResponseSize)))
+	if err != nil {
+		return fmt.Errorf("failed to read response from %q: %w", u, err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response from %q: %w", u, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Blocklist entry types.
+const (
+	BlocklistEntryFingerprint string = "fingerprint"
+	BlocklistEntrySerial      string = "serial"
+)
+
+// CertBlocklist is a collection of known-compromised (or otherwise
+// unwanted) certificate SHA-256 fingerprints and serial numbers.
+type CertBlocklist struct {
+	fingerprints map[string]struct{}
+	serials      map[string]struct{}
+}
+
+// normalizeFingerprint converts a given SHA-256 fingerprint string into a
+// consistent format suitable for comparison purposes. Delimiters (colons,
+// dashes, spaces) are removed and the result is returned in uppercase.
+func normalizeFingerprint(fingerprint string) string {
+	fingerprint = strings.NewReplacer(
+		":", "",
+		"-", "",
+		" ", "",
+	).Replace(strings.TrimSpace(fingerprint))
+
+	return strings.ToUpper(fingerprint)
+}
+
+// isFingerprint indicates whether the given normalized value is a SHA-256
+// fingerprint. Serial numbers are limited to 20 octets (RFC 5280) and are
+// therefore always shorter.
+func isFingerprint(normalized string) bool {
+	if len(normalized) != sha256.Size*2 {
+		return false
+	}
+
+	_, err := hex.DecodeString(normalized)
+
+	return err == nil
+}
+
+// IsValidFingerprint indicates whether the given string is a SHA-256
+// fingerprint in a supported format (hex digits with optional delimiters).
+func IsValidFingerprint(fingerprint string) bool {
+	return isFingerprint(normalizeFingerprint(fingerprint))
+}
+
+// ParseCertBlocklist parses certificate blocklist entries from the given
+// reader. Each line is expected to contain a single SHA-256 fingerprint or
+// certificate serial number in hex format with optional delimiters. Blank
+// lines and text following a # character are ignored.
+func ParseCertBlocklist(r io.Reader) (CertBlocklist, error) {
+	blocklist := CertBlocklist{
+		fingerprints: make(map[string]struct{}),
+		serials:      make(map[string]struct{}),
+	}
+
+	scanner := bufio.NewScanner(r)
+
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fingerprint := normalizeFingerprint(line)
+
+		switch {
+		case isFingerprint(fingerprint):
+			blocklist.fingerprints[fingerprint] = struct{}{}
+
+		case IsValidSerialNumber(line):
+			blocklist.serials[NormalizeSerialNumber(line)] = struct{}{}
+
+		default:
+			return CertBlocklist{}, fmt.Errorf(
+				"entry %q on line %d: %w",
+				line,
+				lineNum,
+				ErrInvalidBlocklistEntry,
+			)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return CertBlocklist{}, fmt.Errorf(
+			"failed to read blocklist entries: %w",
+			err,
+		)
+	}
+
+	return blocklist, nil
+}
+
+// LoadCertBlocklistFile parses certificate blocklist entries from the
+// specified file. See ParseCertBlocklist for the expected format.
+func LoadCertBlocklistFile(filename string) (CertBlocklist, error) {
+	// Open the blocklist file after first attempting to sanitize the input
+	// file variable contents.
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return CertBlocklist{}, fmt.Errorf(
+			"failed to open blocklist file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	blocklist, err := ParseCertBlocklist(f)
+	if err != nil {
+		return CertBlocklist{}, fmt.Errorf(
+			"failed to parse blocklist file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return blocklist, nil
+}
+
+// Len returns the total number of entries in the blocklist.
+func (cbl CertBlocklist) Len() int {
+	return len(cbl.fingerprints) + len(cbl.serials)
+}
+
+// Match indicates whether the given certificate matches an entry in the
+// blocklist. The type of the matching entry (fingerprint or serial) is
+// returned if a match is found.
+func (cbl CertBlocklist) Match(cert *x509.Certificate) (string, bool) {
+	if cert == nil {
+		return "", false
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	if _, ok := cbl.fingerprints[strings.ToUpper(hex.EncodeToString(fingerprint[:]))]; ok {
+		return BlocklistEntryFingerprint, true
+	}
+
+	serial := NormalizeSerialNumber(FormatCertSerialNumber(cert.SerialNumber))
+	if _, ok := cbl.serials[serial]; ok {
+		return BlocklistEntrySerial, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,256 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testFingerprint returns the SHA-256 fingerprint of the given certificate
+// in the colon-delimited, lowercase format commonly used by other tools.
+func testFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	encoded := hex.EncodeToString(sum[:])
+
+	octets := make([]string, 0, len(sum))
+	for i := 0; i < len(encoded); i += 2 {
+		octets = append(octets, encoded[i:i+2])
+	}
+
+	return strings.Join(octets, ":")
+}
+
+func TestParseCertBlocklist(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := strings.Repeat("ab", sha256.Size)
+
+	tests := []struct {
+		name             string
+		content          string
+		wantFingerprints int
+		wantSerials      int
+		err              error
+	}{
+		{
+			name: "CommentsAndBlankLines",
+			content: "# compromised certificates\n" +
+				"\n" +
+				"   \n" +
+				fingerprint + " # leaked key\n" +
+				"0a:fd:50:2b\n",
+			wantFingerprints: 1,
+			wantSerials:      1,
+		},
+		{
+			name:             "DelimitedMixedCaseFingerprint",
+			content:          strings.ToUpper(strings.Repeat("ab:", sha256.Size-1)) + "ab\n",
+			wantFingerprints: 1,
+		},
+		{
+			name:             "DuplicateEntries",
+			content:          fingerprint + "\n" + strings.ToUpper(fingerprint) + "\n",
+			wantFingerprints: 1,
+		},
+		{
+			name:    "MalformedFingerprint",
+			content: strings.Repeat("zz", sha256.Size) + "\n",
+			err:     ErrInvalidBlocklistEntry,
+		},
+		{
+			name:    "MalformedEntryAfterValidEntries",
+			content: fingerprint + "\n0a:fd\nnot-a-fingerprint\n",
+			err:     ErrInvalidBlocklistEntry,
+		},
+		{
+			name:    "CommentsOnly",
+			content: "# nothing to see here\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			blocklist, err := ParseCertBlocklist(strings.NewReader(tt.content))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if got := len(blocklist.fingerprints); got != tt.wantFingerprints {
+				t.Errorf("want %d fingerprints; got %d", tt.wantFingerprints, got)
+			}
+
+			if got := len(blocklist.serials); got != tt.wantSerials {
+				t.Errorf("want %d serials; got %d", tt.wantSerials, got)
+			}
+		})
+	}
+}
+
+func TestLoadCertBlocklistFile(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "blocklist.txt")
+	if err := os.WriteFile(filename, []byte("# entries\n0a:fd:50:2b\n"), 0o600); err != nil {
+		t.Fatalf("failed to write blocklist file: %v", err)
+	}
+
+	blocklist, err := LoadCertBlocklistFile(filename)
+	if err != nil {
+		t.Fatalf("want no error; got %v", err)
+	}
+
+	if blocklist.Len() != 1 {
+		t.Errorf("want 1 entry; got %d", blocklist.Len())
+	}
+
+	if _, err := LoadCertBlocklistFile(filepath.Join(t.TempDir(), "missing.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want error %v; got %v", os.ErrNotExist, err)
+	}
+}
+
+func TestCertBlocklistMatch(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+	leaf, intermediate := certChain[0], certChain[1]
+
+	tests := []struct {
+		name      string
+		content   string
+		cert      *x509.Certificate
+		wantMatch string
+	}{
+		{
+			name:      "LeafFingerprint",
+			content:   testFingerprint(leaf),
+			cert:      leaf,
+			wantMatch: BlocklistEntryFingerprint,
+		},
+		{
+			name:      "LeafSerial",
+			content:   FormatCertSerialNumber(leaf.SerialNumber),
+			cert:      leaf,
+			wantMatch: BlocklistEntrySerial,
+		},
+		{
+			name:      "IntermediateFingerprint",
+			content:   testFingerprint(intermediate),
+			cert:      intermediate,
+			wantMatch: BlocklistEntryFingerprint,
+		},
+		{
+			name:    "NoMatch",
+			content: testFingerprint(intermediate),
+			cert:    leaf,
+		},
+		{
+			name:    "NilCert",
+			content: testFingerprint(leaf),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			blocklist, err := ParseCertBlocklist(strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			matchType, ok := blocklist.Match(tt.cert)
+			if ok != (tt.wantMatch != "") || matchType != tt.wantMatch {
+				t.Errorf("want match %q; got %q (%t)", tt.wantMatch, matchType, ok)
+			}
+		})
+	}
+}
+
+func TestValidateBlocklist(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+
+	parse := func(t *testing.T, content string) CertBlocklist {
+		t.Helper()
+
+		blocklist, err := ParseCertBlocklist(strings.NewReader(content))
+		if err != nil {
+			t.Fatalf("failed to parse blocklist: %v", err)
+		}
+
+		return blocklist
+	}
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		blocklist func(t *testing.T) CertBlocklist
+		err       error
+		wantMatch string
+	}{
+		{
+			name:      "LeafBlocklisted",
+			certChain: certChain,
+			blocklist: func(t *testing.T) CertBlocklist {
+				return parse(t, testFingerprint(certChain[0]))
+			},
+			err:       ErrCertBlocklisted,
+			wantMatch: BlocklistEntryFingerprint,
+		},
+		{
+			name:      "LeafNotBlocklisted",
+			certChain: certChain,
+			blocklist: func(t *testing.T) CertBlocklist {
+				return parse(t, "0a:fd:50:2b")
+			},
+		},
+		{
+			name:      "EmptyBlocklist",
+			certChain: certChain,
+			blocklist: func(t *testing.T) CertBlocklist { return CertBlocklist{} },
+			err:       ErrMissingValue,
+		},
+		{
+			name:      "NoLeafCert",
+			certChain: certChain[1:],
+			blocklist: func(t *testing.T) CertBlocklist {
+				return parse(t, testFingerprint(certChain[1]))
+			},
+			err: ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateBlocklist(tt.certChain, tt.blocklist(t), CertChainValidationOptions{})
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if result.MatchType() != tt.wantMatch {
+				t.Errorf("want match type %q; got %q", tt.wantMatch, result.MatchType())
+			}
+		})
+	}
+}
@@ -20,9 +20,11 @@ import (
 
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/hex"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"os"
@@ -130,6 +132,91 @@ var (
 	// ErrIncompleteCertificateChain indicates that a certificate chain is
 	// missing one or more certificates (e.g., only leaf cert is present).
 	ErrIncompleteCertificateChain = errors.New("certificate chain incomplete")
+
+	// ErrCertSerialNumberMismatch indicates that the serial number of a
+	// certificate does not match the value specified by the user.
+	ErrCertSerialNumberMismatch = errors.New("serial number does not match expected value")
+
+	// ErrCertMissingRequiredExtKeyUsage indicates that a certificate is
+	// missing one or more required Extended Key Usage values.
+	ErrCertMissingRequiredExtKeyUsage = errors.New("certificate is missing required Extended Key Usage values")
+
+	// ErrCertHasDisallowedExtKeyUsage indicates that a certificate has one or
+	// more Extended Key Usage values flagged by the user as disallowed.
+	ErrCertHasDisallowedExtKeyUsage = errors.New("certificate has disallowed Extended Key Usage values")
+
+	// ErrCertChainConstraintsViolation indicates that one or more
+	// certificates in a chain have Key Usage or Basic Constraints values
+	// inconsistent with their position in the chain.
+	ErrCertChainConstraintsViolation = errors.New("certificate chain has Key Usage or Basic Constraints violations")
+
+	// ErrCertChainKeyUsageMismatch indicates that one or more certificates
+	// in a chain have Key Usage or Extended Key Usage values inappropriate
+	// for their tier in the chain or their key type.
+	ErrCertChainKeyUsageMismatch = errors.New("certificate chain has Key Usage values inappropriate for certificate tier or key type")
+
+	// ErrCertChainHasDuplicateCerts indicates that the same certificate is
+	// present more than once in a certificate chain.
+	ErrCertChainHasDuplicateCerts = errors.New("certificate chain has duplicate certificates")
+
+	// ErrCertIsSelfSigned indicates that a leaf certificate is self-signed.
+	ErrCertIsSelfSigned = errors.New("certificate is self-signed")
+
+	// ErrCertChainHasDistrustedCA indicates that a certificate chain relies
+	// on a certificate authority which has been distrusted by browser and
+	// operating system trust stores.
+	ErrCertChainHasDistrustedCA = errors.New("certificate chain relies on distrusted certificate authority")
+
+	// ErrInvalidDistrustedCAEntry indicates that a distrusted certificate
+	// authorities list entry is not a valid SPKI or certificate SHA-256
+	// fingerprint.
+	ErrInvalidDistrustedCAEntry = errors.New("invalid distrusted CA entry")
+
+	// ErrCertBlocklisted indicates that a certificate matches an entry in a
+	// blocklist of known-compromised certificates.
+	ErrCertBlocklisted = errors.New("certificate is blocklisted")
+
+	// ErrLegacyTLSVersionAccepted indicates that a server negotiated a TLS
+	// protocol version older than the required minimum version.
+	ErrLegacyTLSVersionAccepted = errors.New("server accepts TLS protocol versions older than minimum version")
+
+	// ErrTLSVersionProbeFailed indicates that a server could not be probed
+	// for acceptance of TLS protocol versions.
+	ErrTLSVersionProbeFailed = errors.New("TLS protocol version probe failed")
+
+	// ErrWeakCipherSuitesAccepted indicates that a server accepted
+	// known-weak cipher suites.
+	ErrWeakCipherSuitesAccepted = errors.New("server accepts weak cipher suites")
+
+	// ErrCertChainExpiryCliff indicates that multiple certificates in a
+	// certificate chain expire within the same short window.
+	ErrCertChainExpiryCliff = errors.New("multiple certificates in chain expire together")
+
+	// ErrCipherSuiteProbeFailed indicates that a server could not be probed
+	// for acceptance of known-weak cipher suites.
+	ErrCipherSuiteProbeFailed = errors.New("cipher suite probe failed")
+
+	// ErrInvalidBlocklistEntry indicates that a blocklist entry is not a
+	// valid SHA-256 fingerprint or certificate serial number.
+	ErrInvalidBlocklistEntry = errors.New("invalid blocklist entry")
+
+	// ErrCTLogLookupFailed indicates that certificates logged to Certificate
+	// Transparency logs could not be retrieved for comparison.
+	ErrCTLogLookupFailed = errors.New("certificate transparency log lookup failed")
+
+	// ErrNewerCertInCTLogs indicates that a newer certificate than the one
+	// served is recorded in Certificate Transparency logs; a renewed
+	// certificate was likely issued but not yet installed.
+	ErrNewerCertInCTLogs = errors.New("newer certificate found in certificate transparency logs")
+
+	// ErrRenewalInfoLookupFailed indicates that the renewal window suggested
+	// by the Certificate Authority could not be retrieved via ACME Renewal
+	// Information.
+	ErrRenewalInfoLookupFailed = errors.New("ACME renewal information lookup failed")
+
+	// ErrRenewalWindowStarted indicates that the renewal window suggested by
+	// the Certificate Authority for a certificate has started.
+	ErrRenewalWindowStarted = errors.New("CA-suggested renewal window has started")
 )
 
 // ServiceStater represents a type that is capable of evaluating its overall
@@ -169,6 +256,73 @@ type CertChainValidationOptions struct {
 	// Names (SANs) validation against a leaf certificate in a chain.
 	IgnoreValidationResultSANs bool
 
+	// IgnoreValidationResultSerialNumber tracks whether a request was made
+	// to ignore validation check results from comparing the serial number of
+	// a leaf certificate in a chain against an expected value.
+	IgnoreValidationResultSerialNumber bool
+
+	// IgnoreValidationResultExtKeyUsage tracks whether a request was made to
+	// ignore validation check results from evaluating the Extended Key Usage
+	// values of a leaf certificate in a chain.
+	IgnoreValidationResultExtKeyUsage bool
+
+	// IgnoreValidationResultChainConstraints tracks whether a request was
+	// made to ignore validation check results from evaluating the Key Usage
+	// and Basic Constraints values of certificates in a chain.
+	IgnoreValidationResultChainConstraints bool
+
+	// IgnoreValidationResultKeyUsage tracks whether a request was made to
+	// ignore validation check results from evaluating the Key Usage and
+	// Extended Key Usage values of certificates in a chain for consistency
+	// with their tier in the chain and key type.
+	IgnoreValidationResultKeyUsage bool
+
+	// IgnoreValidationResultDuplicateCerts tracks whether a request was made
+	// to ignore validation check results from evaluating a certificate chain
+	// for redundant certificates.
+	IgnoreValidationResultDuplicateCerts bool
+
+	// IgnoreValidationResultSelfSigned tracks whether a request was made to
+	// ignore validation check results from evaluating whether a leaf
+	// certificate in a chain is self-signed.
+	IgnoreValidationResultSelfSigned bool
+
+	// IgnoreValidationResultDistrustedCAs tracks whether a request was made
+	// to ignore validation check results from evaluating a certificate chain
+	// for reliance on distrusted certificate authorities.
+	IgnoreValidationResultDistrustedCAs bool
+
+	// IgnoreValidationResultBlocklist tracks whether a request was made to
+	// ignore validation check results from evaluating a leaf certificate
+	// against a blocklist of known-compromised certificates.
+	IgnoreValidationResultBlocklist bool
+
+	// IgnoreValidationResultCTLogs tracks whether a request was made to
+	// ignore validation check results from comparing a leaf certificate
+	// against certificates recorded in Certificate Transparency logs.
+	IgnoreValidationResultCTLogs bool
+
+	// IgnoreValidationResultRenewalInfo tracks whether a request was made to
+	// ignore validation check results from comparing the current time
+	// against the renewal window suggested by the Certificate Authority for
+	// a leaf certificate.
+	IgnoreValidationResultRenewalInfo bool
+
+	// IgnoreValidationResultMinTLSVersion tracks whether a request was made
+	// to ignore validation check results from probing a server for
+	// acceptance of TLS protocol versions older than the minimum version.
+	IgnoreValidationResultMinTLSVersion bool
+
+	// IgnoreValidationResultWeakCipherSuites tracks whether a request was
+	// made to ignore validation check results from probing a server for
+	// acceptance of known-weak cipher suites.
+	IgnoreValidationResultWeakCipherSuites bool
+
+	// IgnoreValidationResultExpiryCliff tracks whether a request was made to
+	// ignore validation check results from evaluating a certificate chain
+	// for multiple certificates expiring within the same short window.
+	IgnoreValidationResultExpiryCliff bool
+
 	// IgnoreExpiringIntermediateCertificates tracks whether a request was
 	// made to ignore validation check results for certificate expiration
 	// against intermediate certificates in a certificate chain which are
@@ -189,6 +343,17 @@ type CertChainValidationOptions struct {
 	// ignore validation check results for certificate expiration against root
 	// certificates in a certificate chain which have expired.
 	IgnoreExpiredRootCertificates bool
+
+	// IgnoredSerialNumbers is the collection of serial numbers for
+	// certificates in a certificate chain which should be excluded when
+	// evaluating expiration and signature algorithms (e.g., an expired
+	// cross-signed intermediate certificate which cannot be removed).
+	IgnoredSerialNumbers []string
+
+	// IgnoredFingerprints is the collection of SHA-256 fingerprints for
+	// certificates in a certificate chain which should be excluded when
+	// evaluating expiration and signature algorithms.
+	IgnoredFingerprints []string
 }
 
 // DiscoveredCertChain represents the certificate chain found on a specific
@@ -208,6 +373,102 @@ type DiscoveredCertChain struct {
 
 	// Certs is the certificate chain associated with a host.
 	Certs []*x509.Certificate
+
+	// CorrelationID is an optional identifier used to associate log events
+	// and result records with the scan target (IP Address and port) where
+	// the certificate chain was discovered.
+	CorrelationID string
+
+	// ValidationResults is the collection of validation check results for
+	// the certificate chain. This is populated once when the certificate
+	// chain is discovered so that all summaries and exports share the same
+	// evaluation.
+	//
+	// Hostname verification results are included for each hostname or FQDN
+	// that resolved to the IP Address where the certificate chain was
+	// discovered. No hostname verification results are included if the
+	// certificate chain was discovered by way of an IP Address or range
+	// only.
+	ValidationResults CertChainValidationResults
+}
+
+// ExpirationResult returns the expiration validation check result recorded
+// for the discovered certificate chain. If not previously recorded, the
+// certificate chain is evaluated using the given CRITICAL and WARNING
+// thresholds (specified in number of days from this moment).
+func (dc DiscoveredCertChain) ExpirationResult(ageCritical int, ageWarning int) ExpirationValidationResult {
+	for _, result := range dc.ValidationResults {
+		if expirationResult, ok := result.(ExpirationValidationResult); ok {
+			return expirationResult
+		}
+	}
+
+	return ValidateExpiration(
+		dc.Certs,
+		ageCritical,
+		ageWarning,
+		true,
+		false,
+		CertChainValidationOptions{},
+	)
+}
+
+// IsIgnoredCert indicates whether the given certificate from the discovered
+// certificate chain was excluded from the recorded expiration validation
+// check result at the request of the sysadmin (e.g., by serial number or
+// SHA-256 fingerprint).
+func (dc DiscoveredCertChain) IsIgnoredCert(cert *x509.Certificate) bool {
+	for _, result := range dc.ValidationResults {
+		if expirationResult, ok := result.(ExpirationValidationResult); ok {
+			return IsIgnoredCert(cert, expirationResult.validationOptions)
+		}
+	}
+
+	return false
+}
+
+// HostnameMismatches returns the hostname or FQDN values which failed
+// hostname verification against the leaf certificate of the discovered
+// certificate chain.
+func (dc DiscoveredCertChain) HostnameMismatches() []string {
+	mismatches := make([]string, 0)
+	for _, result := range dc.ValidationResults {
+		if hostnameResult, ok := result.(HostnameValidationResult); ok && hostnameResult.IsFailed() {
+			mismatches = append(mismatches, hostnameResult.Hostname())
+		}
+	}
+
+	return mismatches
+}
+
+// VerifiedNames returns the hostname or FQDN values which were verified
+// against the leaf certificate of the discovered certificate chain
+// regardless of the outcome.
+func (dc DiscoveredCertChain) VerifiedNames() []string {
+	names := make([]string, 0)
+	for _, result := range dc.ValidationResults {
+		if hostnameResult, ok := result.(HostnameValidationResult); ok {
+			names = append(names, hostnameResult.Hostname())
+		}
+	}
+
+	return names
+}
+
+// hasProblems indicates whether any problems were found with the discovered
+// certificate chain. The recorded validation check results are used if
+// available, otherwise the certificate chain is evaluated for expired or
+// expiring soon certificates using the given thresholds.
+func (dc DiscoveredCertChain) hasProblems(
+	certsExpireAgeCritical time.Time,
+	certsExpireAgeWarning time.Time,
+) bool {
+	if len(dc.ValidationResults) > 0 {
+		return dc.ValidationResults.HasFailed()
+	}
+
+	return HasExpiredCert(dc.Certs) ||
+		HasExpiringCert(dc.Certs, certsExpireAgeCritical, certsExpireAgeWarning)
 }
 
 // DiscoveredCertChains is a collection of discovered certificate chains for
@@ -271,14 +532,6 @@ const (
 // validity date/time values across our application.
 const CertValidityDateLayout string = "2006-01-02 15:04:05 -0700 MST"
 
-const (
-	certChainPositionLeaf           string = "leaf"
-	certChainPositionLeafSelfSigned string = "leaf; self-signed"
-	certChainPositionIntermediate   string = "intermediate"
-	certChainPositionRoot           string = "root"
-	certChainPositionUnknown        string = "UNKNOWN cert chain position; please submit a bug report"
-)
-
 // ExpirationValidationOneLineSummaryExpiresNextTmpl is a shared template
 // string used for emitting one-line service check status output for
 // certificate chains whose certificates have not expired yet.
@@ -310,12 +563,39 @@ const (
 	checkNameExpirationValidationResult string = "Expiration"
 	checkNameHostnameValidationResult   string = "Hostname"
 	checkNameSANsListValidationResult   string = "SANs List"
+
+	checkNameSerialNumberValidationResult     string = "Serial Number"
+	checkNameExtKeyUsageValidationResult      string = "Extended Key Usage"
+	checkNameChainConstraintsValidationResult string = "Chain Constraints"
+	checkNameKeyUsageValidationResult         string = "Key Usage"
+	checkNameDuplicateCertsValidationResult   string = "Duplicate Certificates"
+	checkNameSelfSignedValidationResult       string = "Self-Signed Leaf"
+	checkNameDistrustedCAsValidationResult    string = "Distrusted CAs"
+	checkNameBlocklistValidationResult        string = "Blocklist"
+	checkNameCTLogsValidationResult           string = "CT Logs"
+	checkNameRenewalInfoValidationResult      string = "Renewal Info"
+	checkNameMinTLSVersionValidationResult    string = "Minimum TLS Version"
+	checkNameWeakCipherSuitesValidationResult string = "Weak Cipher Suites"
+	checkNameExpiryCliffValidationResult      string = "Expiry Cliff"
 )
 
 // Baseline priority values for validation results. Higher values indicate
 // higher priority.
 const (
-	baselinePrioritySANsListValidationResult int = iota + 1
+	baselinePriorityDuplicateCertsValidationResult int = iota + 1
+	baselinePriorityExpiryCliffValidationResult
+	baselinePrioritySANsListValidationResult
+	baselinePrioritySerialNumberValidationResult
+	baselinePriorityExtKeyUsageValidationResult
+	baselinePriorityChainConstraintsValidationResult
+	baselinePriorityKeyUsageValidationResult
+	baselinePriorityCTLogsValidationResult
+	baselinePriorityRenewalInfoValidationResult
+	baselinePriorityMinTLSVersionValidationResult
+	baselinePriorityWeakCipherSuitesValidationResult
+	baselinePrioritySelfSignedValidationResult
+	baselinePriorityDistrustedCAsValidationResult
+	baselinePriorityBlocklistValidationResult
 	baselinePriorityHostnameValidationResult
 	baselinePriorityExpirationValidationResult
 )
@@ -353,63 +633,63 @@ const (
 // chain position to help determine the purpose of each v1 and v2 certificate.
 // This is because those certificate versions lack the more descriptive
 // "intention" fields (i.e., "extensions") of v3 certificates.
-func chainPositionV1V2Cert(cert *x509.Certificate, certChain []*x509.Certificate) string {
+func chainPositionV1V2Cert(cert *x509.Certificate, certChain []*x509.Certificate) CertChainPosition {
 	switch {
 	case isSelfSigned(cert):
 		if cert == certChain[0] {
-			return certChainPositionLeafSelfSigned
+			return CertChainPositionLeafSelfSigned
 		}
 
-		return certChainPositionRoot
+		return CertChainPositionRoot
 
 	default:
 		if cert == certChain[0] {
-			return certChainPositionLeaf
+			return CertChainPositionLeaf
 		}
 
-		return certChainPositionIntermediate
+		return CertChainPositionIntermediate
 	}
 }
 
 // chainPosV3CertKeyUsage evaluates the KeyUsage field for a certificate to
 // determine the chain position for a certificate; the KeyUsage field
 // identifies the set of actions that are valid for a given key.
-func chainPosV3CertKeyUsage(cert *x509.Certificate) string {
+func chainPosV3CertKeyUsage(cert *x509.Certificate) CertChainPosition {
 	switch {
 	case isSelfSigned(cert):
 		switch cert.KeyUsage {
 		case cert.KeyUsage | x509.KeyUsageCertSign | x509.KeyUsageCRLSign:
-			return certChainPositionRoot
+			return CertChainPositionRoot
 		case cert.KeyUsage | x509.KeyUsageCertSign:
-			return certChainPositionRoot
+			return CertChainPositionRoot
 		default:
-			return certChainPositionLeafSelfSigned
+			return CertChainPositionLeafSelfSigned
 		}
 	default:
 
 		switch cert.KeyUsage {
 		case cert.KeyUsage | x509.KeyUsageCertSign | x509.KeyUsageCRLSign:
-			return certChainPositionIntermediate
+			return CertChainPositionIntermediate
 		case cert.KeyUsage | x509.KeyUsageCertSign:
-			return certChainPositionIntermediate
+			return CertChainPositionIntermediate
 		default:
-			return certChainPositionLeaf
+			return CertChainPositionLeaf
 		}
 	}
 }
 
 // chainPositionV3Cert identifies the certificate chain position for a given
 // v3 cert.
-func chainPositionV3Cert(cert *x509.Certificate) string {
+func chainPositionV3Cert(cert *x509.Certificate) CertChainPosition {
 	selfSigned := isSelfSigned(cert)
 
 	// The CA boolean indicates whether the certified public key may be used
 	// to verify certificate signatures.
 	switch {
 	case selfSigned && cert.IsCA:
-		return certChainPositionRoot
+		return CertChainPositionRoot
 	case cert.IsCA:
-		return certChainPositionIntermediate
+		return CertChainPositionIntermediate
 	}
 
 	// The Extended key usage extension indicates one or more purposes for
@@ -418,9 +698,9 @@ func chainPositionV3Cert(cert *x509.Certificate) string {
 	// this extension will appear only in end entity certificates.
 	switch {
 	case selfSigned && cert.ExtKeyUsage != nil:
-		return certChainPositionLeafSelfSigned
+		return CertChainPositionLeafSelfSigned
 	case cert.ExtKeyUsage != nil:
-		return certChainPositionLeaf
+		return CertChainPositionLeaf
 	}
 
 	return chainPosV3CertKeyUsage(cert)
@@ -458,6 +738,38 @@ func ServiceState(val ServiceStater) nagios.ServiceState {
 // cannot be decoded and parsed. Any trailing non-parsable data is returned
 // for potential further evaluation.
 func GetCertsFromFile(filename string) ([]*x509.Certificate, []byte, error) {
+	// Read in the entire certificate file after first attempting to sanitize
+	// the input file variable contents.
+	certFileData, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parseCertFileData(certFileData, filename)
+}
+
+// GetCertsFromReader is a helper function for retrieving a certificate chain
+// from the given reader (e.g., standard input). The given source is used to
+// describe the reader in error messages. An error is returned if the data
+// format cannot be decoded and parsed. Any trailing non-parsable data is
+// returned for potential further evaluation.
+func GetCertsFromReader(r io.Reader, source string) ([]*x509.Certificate, []byte, error) {
+	certFileData, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf(
+			"failed to read certificates from %s: %w",
+			source,
+			err,
+		)
+	}
+
+	return parseCertFileData(certFileData, source)
+}
+
+// parseCertFileData decodes and parses the given certificate file content as
+// a certificate chain. The given filename is used in error messages. Any
+// trailing non-parsable data is returned for potential further evaluation.
+func parseCertFileData(certFileData []byte, filename string) ([]*x509.Certificate, []byte, error) {
 	var certChain []*x509.Certificate
 
 	// Anything from the specified file that couldn't be converted to a
@@ -465,12 +777,7 @@ func GetCertsFromFile(filename string) ([]*x509.Certificate, []byte, error) {
 	// parse a certificate file indicates a likely source of trouble.
 	var parseAttemptLeftovers []byte
 
-	// Read in the entire certificate file after first attempting to sanitize
-	// the input file variable contents.
-	certFileData, err := os.ReadFile(filepath.Clean(filename))
-	if err != nil {
-		return nil, nil, err
-	}
+	var err error
 
 	// Bail if nothing was found.
 	if len(certFileData) == 0 {
@@ -736,6 +1043,141 @@ func HasRootCert(certChain []*x509.Certificate) bool {
 	return false
 }
 
+// IsIncompleteChain receives a slice of x509 certificates and indicates
+// whether the certificate which issued the leaf certificate is missing from
+// the chain. This is commonly the result of a server configured with the
+// leaf certificate file instead of the "full chain" file. Chains with a
+// self-signed leaf certificate or without a leaf certificate are not
+// considered incomplete.
+func IsIncompleteChain(certChain []*x509.Certificate) bool {
+	leafCerts := LeafCerts(certChain)
+	if len(leafCerts) == 0 || isSelfSigned(leafCerts[0]) {
+		return false
+	}
+
+	for _, cert := range certChain {
+		if bytes.Equal(cert.RawSubject, leafCerts[0].RawIssuer) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsMisorderedChain receives a slice of x509 certificates and indicates
+// whether the certificates are out of order. A correctly ordered chain
+// starts with the leaf certificate and each certificate is followed by the
+// certificate which issued it (if present in the chain).
+func IsMisorderedChain(certChain []*x509.Certificate) bool {
+	if len(certChain) < 2 {
+		return false
+	}
+
+	if HasLeafCert(certChain) && !IsLeafCert(certChain[0], certChain) {
+		return true
+	}
+
+	for i, cert := range certChain {
+		if isSelfSigned(cert) {
+			continue
+		}
+
+		// The issuer immediately follows the certificate as expected.
+		if i+1 < len(certChain) && bytes.Equal(certChain[i+1].RawSubject, cert.RawIssuer) {
+			continue
+		}
+
+		// The issuer is present elsewhere in the chain.
+		for j, issuer := range certChain {
+			if j != i && bytes.Equal(issuer.RawSubject, cert.RawIssuer) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SortCertChain returns the given certificate chain in canonical order: the
+// leaf certificate followed by each issuer in turn up to the root
+// certificate (if present). If the chain does not contain a leaf certificate
+// (e.g., an intermediates bundle) the path starts with the certificate which
+// did not issue any other certificate in the chain. Certificates which are
+// not part of the path are placed after it, grouped into paths in the same
+// way. The given certificate chain is not modified.
+func SortCertChain(certChain []*x509.Certificate) []*x509.Certificate {
+	sorted := make([]*x509.Certificate, 0, len(certChain))
+	used := make(map[*x509.Certificate]bool, len(certChain))
+
+	issues := func(issuer *x509.Certificate, cert *x509.Certificate) bool {
+		return issuer != cert && !isSelfSigned(cert) &&
+			verifySignature(cert, issuer).Matched()
+	}
+
+	// The first remaining leaf certificate, or failing that the first
+	// remaining certificate which did not issue another remaining
+	// certificate, starts the next path.
+	nextStart := func() *x509.Certificate {
+		var start *x509.Certificate
+		for _, cert := range certChain {
+			if used[cert] {
+				continue
+			}
+
+			if ChainPosition(cert, certChain).IsLeaf() {
+				return cert
+			}
+
+			if start != nil {
+				continue
+			}
+
+			isIssuer := false
+			for _, other := range certChain {
+				if !used[other] && issues(cert, other) {
+					isIssuer = true
+
+					break
+				}
+			}
+
+			if !isIssuer {
+				start = cert
+			}
+		}
+
+		if start == nil {
+			for _, cert := range certChain {
+				if !used[cert] {
+					return cert
+				}
+			}
+		}
+
+		return start
+	}
+
+	for len(sorted) < len(certChain) {
+		current := nextStart()
+		for current != nil {
+			used[current] = true
+			sorted = append(sorted, current)
+
+			var issuer *x509.Certificate
+			for _, candidate := range certChain {
+				if !used[candidate] && issues(candidate, current) {
+					issuer = candidate
+
+					break
+				}
+			}
+			current = issuer
+		}
+	}
+
+	return sorted
+}
+
 // HasExpiredCert receives a slice of x509 certificates and indicates whether
 // any of the certificates in the chain have expired.
 func HasExpiredCert(certChain []*x509.Certificate) bool {
@@ -972,12 +1414,20 @@ func verifySignatureECDSAWithSHA1(issuedCert *x509.Certificate, issuerCert *x509
 // but rather for best-effort identification; because evaluated certificate
 // chains are managed by sysadmins and already under their control the outcome
 // of this logic grants no more access than was already present.
-func verifySignature(issuedCert *x509.Certificate, issuerCert *x509.Certificate) error {
+func verifySignature(issuedCert *x509.Certificate, issuerCert *x509.Certificate) SignatureVerification {
+	result := SignatureVerification{
+		Method:    SignatureMethodNone,
+		Algorithm: issuedCert.SignatureAlgorithm,
+	}
+
 	if issuedCert.Issuer.String() != issuerCert.Subject.String() {
-		return fmt.Errorf(
+		result.Status = SignatureVerificationFailed
+		result.Err = fmt.Errorf(
 			"issuer and subject X.509 distinguished name mismatch: %w",
 			ErrSignatureVerificationFailed,
 		)
+
+		return result
 	}
 
 	// Regarding the specific order of issuer/issued certs in signature
@@ -993,25 +1443,35 @@ func verifySignature(issuedCert *x509.Certificate, issuerCert *x509.Certificate)
 	)
 
 	switch {
+	// The legacy signature verification helpers rely on algorithms which are
+	// not permitted in FIPS mode. Fall back to matching key identifiers; the
+	// lack of signature verification is noted in the report output.
+	case errors.Is(sigVerifyErr, x509.InsecureAlgorithmError(issuedCert.SignatureAlgorithm)) &&
+		IsSignatureUnverified(issuedCert):
+		result.Method = SignatureMethodKeyIdentifiers
+		result.Err = verifyKeyIdentifiers(issuedCert, issuerCert)
+
 	// Handle verification of signature algorithms no longer supported by
 	// current Go releases (declared insecure).
 	case errors.Is(sigVerifyErr, x509.InsecureAlgorithmError(issuedCert.SignatureAlgorithm)):
+		result.Method = SignatureMethodLegacy
+
 		switch {
 		case issuedCert.SignatureAlgorithm == x509.MD5WithRSA:
-			return verifySignatureMD5WithRSA(issuedCert, issuerCert)
+			result.Err = verifySignatureMD5WithRSA(issuedCert, issuerCert)
 
 		case issuedCert.SignatureAlgorithm == x509.SHA1WithRSA:
 			// https://github.com/golang/go/issues/41682
-			return verifySignatureSHA1WithRSA(issuedCert, issuerCert)
+			result.Err = verifySignatureSHA1WithRSA(issuedCert, issuerCert)
 
 		case issuedCert.SignatureAlgorithm == x509.ECDSAWithSHA1:
 			// https://github.com/golang/go/issues/41682
-			return verifySignatureECDSAWithSHA1(issuedCert, issuerCert)
+			result.Err = verifySignatureECDSAWithSHA1(issuedCert, issuerCert)
 
 		default:
 			// Go has declared an algorithm as insecure that we're not
 			// aware of.
-			return fmt.Errorf(
+			result.Err = fmt.Errorf(
 				"unsupported signature algorithm %s (please submit bug report): %w: %w",
 				issuedCert.SignatureAlgorithm,
 				sigVerifyErr,
@@ -1022,15 +1482,44 @@ func verifySignature(issuedCert *x509.Certificate, issuerCert *x509.Certificate)
 	case sigVerifyErr != nil:
 		// Some other signature verification error aside from
 		// InsecureAlgorithmError.
-		return fmt.Errorf(
+		result.Method = SignatureMethodStandard
+		result.Err = fmt.Errorf(
 			"%w: %w",
 			sigVerifyErr,
 			ErrSignatureVerificationFailed,
 		)
 
 	default:
-		return nil
+		result.Method = SignatureMethodStandard
+	}
+
+	switch {
+	case result.Err != nil:
+		result.Status = SignatureVerificationFailed
+	case result.Method == SignatureMethodKeyIdentifiers:
+		result.Status = SignatureNotVerified
+	default:
+		result.Status = SignatureVerified
+	}
+
+	return result
+}
+
+// verifyKeyIdentifiers is a helper function used in place of signature
+// verification when FIPS mode is active for certificates using legacy
+// signature algorithms. An error is returned if both the issued certificate
+// Authority Key Identifier and the issuer certificate Subject Key Identifier
+// are present and do not match.
+func verifyKeyIdentifiers(issuedCert *x509.Certificate, issuerCert *x509.Certificate) error {
+	if len(issuedCert.AuthorityKeyId) > 0 && len(issuerCert.SubjectKeyId) > 0 &&
+		!bytes.Equal(issuedCert.AuthorityKeyId, issuerCert.SubjectKeyId) {
+		return fmt.Errorf(
+			"authority and subject key identifier mismatch: %w",
+			ErrSignatureVerificationFailed,
+		)
 	}
+
+	return nil
 }
 
 // IsLeafCert indicates whether a given certificate from a certificate chain
@@ -1038,9 +1527,9 @@ func verifySignature(issuedCert *x509.Certificate, issuerCert *x509.Certificate)
 func IsLeafCert(cert *x509.Certificate, certChain []*x509.Certificate) bool {
 	chainPos := ChainPosition(cert, certChain)
 	switch chainPos {
-	case certChainPositionLeaf:
+	case CertChainPositionLeaf:
 		return true
-	case certChainPositionLeafSelfSigned:
+	case CertChainPositionLeafSelfSigned:
 		return true
 	default:
 		return false
@@ -1052,7 +1541,7 @@ func IsLeafCert(cert *x509.Certificate, certChain []*x509.Certificate) bool {
 func IsIntermediateCert(cert *x509.Certificate, certChain []*x509.Certificate) bool {
 	chainPos := ChainPosition(cert, certChain)
 
-	return chainPos == certChainPositionIntermediate
+	return chainPos == CertChainPositionIntermediate
 }
 
 // IsRootCert indicates whether a given certificate from a certificate chain
@@ -1060,7 +1549,7 @@ func IsIntermediateCert(cert *x509.Certificate, certChain []*x509.Certificate) b
 func IsRootCert(cert *x509.Certificate, certChain []*x509.Certificate) bool {
 	chainPos := ChainPosition(cert, certChain)
 
-	return chainPos == certChainPositionRoot
+	return chainPos == CertChainPositionRoot
 }
 
 // NumLeafCerts receives a slice of x509 certificates and returns a count of
@@ -1070,9 +1559,9 @@ func NumLeafCerts(certChain []*x509.Certificate) int {
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
 		switch chainPos {
-		case certChainPositionLeaf:
+		case CertChainPositionLeaf:
 			num++
-		case certChainPositionLeafSelfSigned:
+		case CertChainPositionLeafSelfSigned:
 			num++
 		}
 	}
@@ -1086,7 +1575,7 @@ func NumIntermediateCerts(certChain []*x509.Certificate) int {
 	var num int
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
-		if chainPos == certChainPositionIntermediate {
+		if chainPos == CertChainPositionIntermediate {
 			num++
 		}
 	}
@@ -1100,7 +1589,7 @@ func NumRootCerts(certChain []*x509.Certificate) int {
 	var num int
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
-		if chainPos == certChainPositionRoot {
+		if chainPos == CertChainPositionRoot {
 			num++
 		}
 	}
@@ -1114,7 +1603,7 @@ func NumUnknownCerts(certChain []*x509.Certificate) int {
 	var num int
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
-		if chainPos == certChainPositionUnknown {
+		if chainPos == CertChainPositionUnknown {
 			num++
 		}
 	}
@@ -1122,6 +1611,34 @@ func NumUnknownCerts(certChain []*x509.Certificate) int {
 	return num
 }
 
+// DuplicateCerts receives a slice of x509 certificates and returns a
+// (potentially empty) collection of redundant certificates present in the
+// chain. Certificates are compared by SHA-256 fingerprint. Each key is the
+// (zero-based) index of a redundant certificate and each value is the index
+// of the first occurrence of that certificate in the chain.
+func DuplicateCerts(certChain []*x509.Certificate) map[int]int {
+	duplicates := make(map[int]int)
+	seen := make(map[[sha256.Size]byte]int, len(certChain))
+
+	for idx, cert := range certChain {
+		fingerprint := sha256.Sum256(cert.Raw)
+		if firstIdx, ok := seen[fingerprint]; ok {
+			duplicates[idx] = firstIdx
+
+			continue
+		}
+		seen[fingerprint] = idx
+	}
+
+	return duplicates
+}
+
+// NumDuplicateCerts receives a slice of x509 certificates and returns a count
+// of redundant certificates present in the chain.
+func NumDuplicateCerts(certChain []*x509.Certificate) int {
+	return len(DuplicateCerts(certChain))
+}
+
 // LeafCerts receives a slice of x509 certificates and returns a (potentially
 // empty) collection of leaf certificates present in the chain.
 func LeafCerts(certChain []*x509.Certificate) []*x509.Certificate {
@@ -1131,9 +1648,9 @@ func LeafCerts(certChain []*x509.Certificate) []*x509.Certificate {
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
 		switch chainPos {
-		case certChainPositionLeaf:
+		case CertChainPositionLeaf:
 			leafCerts = append(leafCerts, cert)
-		case certChainPositionLeafSelfSigned:
+		case CertChainPositionLeafSelfSigned:
 			leafCerts = append(leafCerts, cert)
 		}
 
@@ -1151,7 +1668,7 @@ func IntermediateCerts(certChain []*x509.Certificate) []*x509.Certificate {
 
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
-		if chainPos == certChainPositionIntermediate {
+		if chainPos == CertChainPositionIntermediate {
 			intermediateCerts = append(intermediateCerts, cert)
 		}
 	}
@@ -1167,7 +1684,7 @@ func RootCerts(certChain []*x509.Certificate) []*x509.Certificate {
 
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
-		if chainPos == certChainPositionRoot {
+		if chainPos == CertChainPositionRoot {
 			rootCerts = append(rootCerts, cert)
 		}
 	}
@@ -1183,7 +1700,7 @@ func NonRootCerts(certChain []*x509.Certificate) []*x509.Certificate {
 
 	for _, cert := range certChain {
 		chainPos := ChainPosition(cert, certChain)
-		if chainPos != certChainPositionRoot {
+		if chainPos != CertChainPositionRoot {
 			nonRootCerts = append(nonRootCerts, cert)
 		}
 	}
@@ -1371,6 +1888,54 @@ func LifeRemainingPercentageTruncated(cert *x509.Certificate) (int, error) {
 	return certLifespanRemainingTruncated, nil
 }
 
+// LifetimeThresholdsStatus returns a description of the given CRITICAL and
+// WARNING percentage of lifetime remaining thresholds along with the number
+// of days each represents for the given certificate. An empty string is
+// returned if the thresholds cannot be converted to days.
+func LifetimeThresholdsStatus(cert *x509.Certificate, criticalPercent int, warningPercent int) string {
+	criticalDays, critErr := LifetimePercentageInDays(cert, criticalPercent)
+	warningDays, warnErr := LifetimePercentageInDays(cert, warningPercent)
+	if critErr != nil || warnErr != nil {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"%s at %d%% (%dd), %s at %d%% (%dd) of lifetime remaining",
+		nagios.StateWARNINGLabel,
+		warningPercent,
+		warningDays,
+		nagios.StateCRITICALLabel,
+		criticalPercent,
+		criticalDays,
+	)
+}
+
+// LifetimePercentageInDays returns the number of days represented by the
+// given percentage of the maximum lifespan for a certificate. This value is
+// intentionally rounded up (e.g., 1.5 days becomes 2 days) since the result
+// may be used to determine when a sysadmin is notified of an impending
+// expiration (sooner is better). The minimum value returned is 1 day.
+func LifetimePercentageInDays(cert *x509.Certificate, percentage int) (int, error) {
+	if cert == nil {
+		return 0, fmt.Errorf(
+			"func LifetimePercentageInDays: unable to determine lifespan: %w",
+			ErrMissingValue,
+		)
+	}
+
+	maxCertLifespan, err := MaxLifespan(cert)
+	if err != nil {
+		return 0, err
+	}
+
+	days := int(math.Ceil(maxCertLifespan.Hours() / 24 * float64(percentage) / 100))
+	if days < 1 {
+		days = 1
+	}
+
+	return days, nil
+}
+
 // FormattedExpiration receives a Time value and converts it to a string
 // representing the largest useful whole units of time in days and hours. For
 // example, if a certificate has 1 year, 2 days and 3 hours remaining until
@@ -1483,7 +2048,7 @@ func FormatCertSerialNumber(sn *big.Int) string {
 func HasWeakSignatureAlgorithm(cert *x509.Certificate, certChain []*x509.Certificate, evalRoot bool) bool {
 	chainPos := ChainPosition(cert, certChain)
 
-	if chainPos == certChainPositionRoot && !evalRoot {
+	if chainPos == CertChainPositionRoot && !evalRoot {
 		return false
 	}
 
@@ -1533,18 +2098,26 @@ func HasCertWithWeakSignatureAlgorithm(certChain []*x509.Certificate, evalRoot b
 // Signature algorithms are ignored for root certificates as TLS clients trust
 // them by their identity instead of the signature of their hash.
 func WeakSignatureAlgorithmStatus(cert *x509.Certificate, certChain []*x509.Certificate) string {
+	return weakSignatureAlgorithmStatus(cert, certChain, false)
+}
+
+// weakSignatureAlgorithmStatus returns a human-readable signature algorithm
+// status for the certificate. If requested, the signature algorithm is
+// marked as ignored regardless of the certificate's chain position. See
+// WeakSignatureAlgorithmStatus for further details.
+func weakSignatureAlgorithmStatus(cert *x509.Certificate, certChain []*x509.Certificate, ignore bool) string {
 	chainPos := ChainPosition(cert, certChain)
 
 	switch {
 	case HasWeakSignatureAlgorithm(cert, certChain, true):
-		if chainPos == certChainPositionRoot {
+		if chainPos == CertChainPositionRoot || ignore {
 			return "[WEAK, IGNORED] " + cert.SignatureAlgorithm.String()
 		}
 
 		return "[WEAK] " + cert.SignatureAlgorithm.String()
 
 	default:
-		if chainPos == certChainPositionRoot {
+		if chainPos == CertChainPositionRoot || ignore {
 			return "[IGNORED] " + cert.SignatureAlgorithm.String()
 		}
 
@@ -1632,6 +2205,10 @@ func ShouldCertExpirationBeIgnored(
 		return true
 	}
 
+	if IsIgnoredCert(cert, validationOptions) {
+		return true
+	}
+
 	if IsRootCert(cert, certChain) {
 		if IsExpiredCert(cert) &&
 			validationOptions.IgnoreExpiredRootCertificates {
@@ -1658,6 +2235,55 @@ func ShouldCertExpirationBeIgnored(
 	return false
 }
 
+// IsIgnoredCert indicates whether the serial number or SHA-256 fingerprint
+// of the given certificate matches one of the values specified by the
+// sysadmin for certificates which should be ignored.
+func IsIgnoredCert(cert *x509.Certificate, validationOptions CertChainValidationOptions) bool {
+	if cert == nil {
+		return false
+	}
+
+	if len(validationOptions.IgnoredSerialNumbers) > 0 {
+		certSerial := NormalizeSerialNumber(FormatCertSerialNumber(cert.SerialNumber))
+		for _, serial := range validationOptions.IgnoredSerialNumbers {
+			if NormalizeSerialNumber(serial) == certSerial {
+				return true
+			}
+		}
+	}
+
+	if len(validationOptions.IgnoredFingerprints) > 0 {
+		sum := sha256.Sum256(cert.Raw)
+		certFingerprint := strings.ToUpper(hex.EncodeToString(sum[:]))
+		for _, fingerprint := range validationOptions.IgnoredFingerprints {
+			if normalizeFingerprint(fingerprint) == certFingerprint {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// withoutIgnoredCerts returns the given certificates minus any that the
+// sysadmin has opted to ignore by serial number or SHA-256 fingerprint. The
+// given certificates are returned unmodified if no certificates are ignored.
+func withoutIgnoredCerts(certs []*x509.Certificate, validationOptions CertChainValidationOptions) []*x509.Certificate {
+	if len(validationOptions.IgnoredSerialNumbers) == 0 &&
+		len(validationOptions.IgnoredFingerprints) == 0 {
+		return certs
+	}
+
+	kept := make([]*x509.Certificate, 0, len(certs))
+	for _, cert := range certs {
+		if !IsIgnoredCert(cert, validationOptions) {
+			kept = append(kept, cert)
+		}
+	}
+
+	return kept
+}
+
 // isSelfSigned is a helper function that attempts to validate whether a given
 // certificate is self-signed by asserting that its signature can be validated
 // with its own public key. Any errors encountered during signature validation
@@ -1667,10 +2293,8 @@ func isSelfSigned(cert *x509.Certificate) bool {
 		return false
 	}
 
-	sigVerifyErr := verifySignature(cert, cert)
-
 	switch {
-	case sigVerifyErr != nil:
+	case !verifySignature(cert, cert).Matched():
 		// Some other signature verification error, which we'll interpret as a
 		// failure due to the certificate not being self-signed.
 		return false
@@ -1683,15 +2307,14 @@ func isSelfSigned(cert *x509.Certificate) bool {
 }
 
 // ChainPosition receives a cert and the cert chain that it belongs to and
-// returns a string indicating what position or "role" it occupies in the
-// certificate chain.
+// returns the position or "role" it occupies in the certificate chain.
 //
 // https://en.wikipedia.org/wiki/X.509
 // https://tools.ietf.org/html/rfc5280
-func ChainPosition(cert *x509.Certificate, certChain []*x509.Certificate) string {
+func ChainPosition(cert *x509.Certificate, certChain []*x509.Certificate) CertChainPosition {
 	// We require a valid certificate chain. Fail if not provided.
 	if certChain == nil {
-		return certChainPositionUnknown
+		return CertChainPositionUnknown
 	}
 
 	switch cert.Version {
@@ -1703,7 +2326,7 @@ func ChainPosition(cert *x509.Certificate, certChain []*x509.Certificate) string
 	}
 
 	// no known match, so position unknown
-	return certChainPositionUnknown
+	return CertChainPositionUnknown
 }
 
 // SANsEntriesLine provides a formatted list of SANs entries for a given
@@ -1729,6 +2352,24 @@ func SANsEntriesLine(cert *x509.Certificate, omitSANsEntries bool) string {
 	}
 }
 
+// URLsLine provides a formatted list of the given URLs under the specified
+// label if present or "None" if no URLs are available. This is used to list
+// Authority Information Access (AIA) and CRL Distribution Point (CDP) URLs.
+func URLsLine(label string, urls []string) string {
+	switch {
+	case len(urls) > 0:
+		return fmt.Sprintf(
+			"%s (%d): %s",
+			label,
+			len(urls),
+			urls,
+		)
+
+	default:
+		return fmt.Sprintf("%s: None", label)
+	}
+}
+
 // GenerateCertChainReport receives the current certificate chain status
 // generates a formatted report suitable for display on the console or
 // (potentially) via Microsoft Teams provided suitable conversion is performed
@@ -1742,14 +2383,57 @@ func GenerateCertChainReport(
 	validationOptions CertChainValidationOptions,
 	omitSANsEntries bool,
 ) string {
+	return generateCertChainReport(
+		certChain,
+		uniformExpirationThresholdDates(ageCriticalThreshold, ageWarningThreshold),
+		verboseDetails,
+		validationOptions,
+		omitSANsEntries,
+	)
+}
+
+// generateCertChainReport generates a certificate chain report using the
+// expiration thresholds applicable to each certificate's chain position. See
+// GenerateCertChainReport for further details.
+func generateCertChainReport(
+	certChain []*x509.Certificate,
+	thresholdDates expirationThresholdDates,
+	verboseDetails bool,
+	validationOptions CertChainValidationOptions,
+	omitSANsEntries bool,
+) string {
 
 	var certsReport string
 
 	certsTotal := len(certChain)
 
+	duplicates := DuplicateCerts(certChain)
+
 	for idx, certificate := range certChain {
 
-		certPosition := ChainPosition(certificate, certChain)
+		certPosition := ChainPosition(certificate, certChain).String()
+
+		ageCriticalThreshold, ageWarningThreshold := thresholdDates.forCert(certificate, certChain)
+
+		// Legacy signatures are not verified in FIPS mode; chain position
+		// is determined by names and key identifiers instead.
+		if IssuerSignatureVerification(certificate, certChain).Status == SignatureNotVerified {
+			certPosition = fmt.Sprintf(
+				"%s, %s signature not verified (FIPS mode)",
+				certPosition,
+				certificate.SignatureAlgorithm,
+			)
+		}
+
+		// Note redundant entries alongside the chain position so that they
+		// stand out when reviewing the report.
+		if firstIdx, ok := duplicates[idx]; ok {
+			certPosition = fmt.Sprintf(
+				"%s, duplicate of certificate %d",
+				certPosition,
+				firstIdx+1,
+			)
+		}
 
 		expiresText := ExpirationStatus(
 			certificate,
@@ -1764,6 +2448,10 @@ func GenerateCertChainReport(
 			),
 		)
 
+		if lifetimeStatus := thresholdDates.lifetimeStatus(certificate, certChain); lifetimeStatus != "" {
+			expiresText = fmt.Sprintf("%s [%s]", expiresText, lifetimeStatus)
+		}
+
 		fingerprints := struct {
 			SHA1   string
 			SHA256 string
@@ -1814,6 +2502,9 @@ func GenerateCertChainReport(
 					"%s\tKeyID: %v"+
 					"%s\tIssuer: %s"+
 					"%s\tIssuerKeyID: %v"+
+					"%s\t%s"+
+					"%s\t%s"+
+					"%s\t%s"+
 					"%s\tFingerprint (SHA-1): %v"+
 					"%s\tFingerprint (SHA-256): %v"+
 					"%s\tFingerprint (SHA-512): %v"+
@@ -1836,6 +2527,12 @@ func GenerateCertChainReport(
 				nagios.CheckOutputEOL,
 				textutils.BytesToDelimitedHexStr(certificate.AuthorityKeyId, ":"),
 				nagios.CheckOutputEOL,
+				URLsLine("Issuing Certificate URLs", certificate.IssuingCertificateURL),
+				nagios.CheckOutputEOL,
+				URLsLine("OCSP Server URLs", certificate.OCSPServer),
+				nagios.CheckOutputEOL,
+				URLsLine("CRL Distribution Point URLs", certificate.CRLDistributionPoints),
+				nagios.CheckOutputEOL,
 				textutils.BytesToDelimitedHexStr([]byte(fingerprints.SHA1), ":"),
 				nagios.CheckOutputEOL,
 				textutils.BytesToDelimitedHexStr([]byte(fingerprints.SHA256), ":"),
@@ -1848,7 +2545,7 @@ func GenerateCertChainReport(
 				nagios.CheckOutputEOL,
 				certificate.NotAfter.Format(CertValidityDateLayout),
 				nagios.CheckOutputEOL,
-				WeakSignatureAlgorithmStatus(certificate, certChain),
+				weakSignatureAlgorithmStatus(certificate, certChain, IsIgnoredCert(certificate, validationOptions)),
 				nagios.CheckOutputEOL,
 				expiresText,
 				nagios.CheckOutputEOL,
@@ -1881,7 +2578,7 @@ func GenerateCertChainReport(
 				nagios.CheckOutputEOL,
 				certificate.NotAfter.Format(CertValidityDateLayout),
 				nagios.CheckOutputEOL,
-				WeakSignatureAlgorithmStatus(certificate, certChain),
+				weakSignatureAlgorithmStatus(certificate, certChain, IsIgnoredCert(certificate, validationOptions)),
 				nagios.CheckOutputEOL,
 				expiresText,
 				nagios.CheckOutputEOL,
@@ -1947,54 +2644,35 @@ func NextToExpire(certChain []*x509.Certificate, excludeExpired bool) *x509.Cert
 	return nextToExpire
 }
 
-// HasProblems asserts that no evaluated certificates are expired or expiring
-// soon.
+// HasProblems indicates whether any discovered certificate chains have
+// failed validation checks (e.g., expired or expiring soon certificates,
+// hostname verification failures).
 func (dcc DiscoveredCertChains) HasProblems(
 	certsExpireAgeCritical time.Time,
 	certsExpireAgeWarning time.Time) bool {
 
 	for _, chain := range dcc {
-
-		hasExpiredCerts := HasExpiredCert(chain.Certs)
-		hasExpiringCerts := HasExpiringCert(
-			chain.Certs,
-			certsExpireAgeCritical,
-			certsExpireAgeWarning,
-		)
-
-		if hasExpiredCerts || hasExpiringCerts {
+		if chain.hasProblems(certsExpireAgeCritical, certsExpireAgeWarning) {
 			return true
 		}
-
 	}
 
 	return false
 
 }
 
-// NumProblems indicates how many evaluated certificates are expired or
-// expiring soon.
-//
-// TODO: Need to either rename or expand the scope to also include hostname
-// verification errors, chain validity, etc.
+// NumProblems indicates how many discovered certificate chains have failed
+// validation checks (e.g., expired or expiring soon certificates, hostname
+// verification failures).
 func (dcc DiscoveredCertChains) NumProblems(
 	certsExpireAgeCritical time.Time,
 	certsExpireAgeWarning time.Time) int {
 
 	var problems int
 	for _, chain := range dcc {
-
-		hasExpiredCerts := HasExpiredCert(chain.Certs)
-		hasExpiringCerts := HasExpiringCert(
-			chain.Certs,
-			certsExpireAgeCritical,
-			certsExpireAgeWarning,
-		)
-
-		if hasExpiredCerts || hasExpiringCerts {
+		if chain.hasProblems(certsExpireAgeCritical, certsExpireAgeWarning) {
 			problems++
 		}
-
 	}
 
 	return problems
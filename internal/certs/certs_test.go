@@ -0,0 +1,121 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"testing"
+)
+
+func TestIsIncompleteChain(t *testing.T) {
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+	leaf := newTestCert(t, "www.example.com", false, intermediate, nil)
+	selfSignedLeaf := newTestCert(t, "self-signed.example.com", false, nil, nil)
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		want      bool
+	}{
+		{
+			name:      "CompleteChain",
+			certChain: []*x509.Certificate{leaf.cert, intermediate.cert, root.cert},
+			want:      false,
+		},
+		{
+			name:      "CompleteChainWithoutRoot",
+			certChain: []*x509.Certificate{leaf.cert, intermediate.cert},
+			want:      false,
+		},
+		{
+			name:      "LeafOnly",
+			certChain: []*x509.Certificate{leaf.cert},
+			want:      true,
+		},
+		{
+			name:      "MissingIntermediate",
+			certChain: []*x509.Certificate{leaf.cert, root.cert},
+			want:      true,
+		},
+		{
+			name:      "SelfSignedLeaf",
+			certChain: []*x509.Certificate{selfSignedLeaf.cert},
+			want:      false,
+		},
+		{
+			name:      "NoLeaf",
+			certChain: []*x509.Certificate{intermediate.cert, root.cert},
+			want:      false,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsIncompleteChain(tt.certChain); got != tt.want {
+				t.Errorf("want: %t; got: %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsMisorderedChain(t *testing.T) {
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+	leaf := newTestCert(t, "www.example.com", false, intermediate, nil)
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		want      bool
+	}{
+		{
+			name:      "OrderedChain",
+			certChain: []*x509.Certificate{leaf.cert, intermediate.cert, root.cert},
+			want:      false,
+		},
+		{
+			name:      "LeafOnly",
+			certChain: []*x509.Certificate{leaf.cert},
+			want:      false,
+		},
+		{
+			name:      "MissingIntermediate",
+			certChain: []*x509.Certificate{leaf.cert, root.cert},
+			want:      false,
+		},
+		{
+			name:      "LeafNotFirst",
+			certChain: []*x509.Certificate{intermediate.cert, leaf.cert, root.cert},
+			want:      true,
+		},
+		{
+			name:      "IssuerNotNext",
+			certChain: []*x509.Certificate{leaf.cert, root.cert, intermediate.cert},
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMisorderedChain(tt.certChain); got != tt.want {
+				t.Errorf("want: %t; got: %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import "fmt"
+
+// CertChainPosition is the position or "role" a certificate occupies in a
+// certificate chain.
+type CertChainPosition int
+
+// Known certificate chain positions.
+const (
+	CertChainPositionUnknown CertChainPosition = iota
+	CertChainPositionLeaf
+	CertChainPositionLeafSelfSigned
+	CertChainPositionIntermediate
+	CertChainPositionRoot
+)
+
+// Keywords used to represent certificate chain positions in machine-readable
+// output formats.
+const (
+	CertChainPositionKeywordUnknown        string = "unknown"
+	CertChainPositionKeywordLeaf           string = "leaf"
+	CertChainPositionKeywordLeafSelfSigned string = "self_signed_leaf"
+	CertChainPositionKeywordIntermediate   string = "intermediate"
+	CertChainPositionKeywordRoot           string = "root"
+)
+
+// String provides the human-readable description of a certificate chain
+// position used in report output.
+func (p CertChainPosition) String() string {
+	switch p {
+	case CertChainPositionLeaf:
+		return "leaf"
+	case CertChainPositionLeafSelfSigned:
+		return "leaf; self-signed"
+	case CertChainPositionIntermediate:
+		return "intermediate"
+	case CertChainPositionRoot:
+		return "root"
+	default:
+		return "UNKNOWN cert chain position; please submit a bug report"
+	}
+}
+
+// Keyword provides the keyword used to represent a certificate chain
+// position in machine-readable output formats.
+func (p CertChainPosition) Keyword() string {
+	switch p {
+	case CertChainPositionLeaf:
+		return CertChainPositionKeywordLeaf
+	case CertChainPositionLeafSelfSigned:
+		return CertChainPositionKeywordLeafSelfSigned
+	case CertChainPositionIntermediate:
+		return CertChainPositionKeywordIntermediate
+	case CertChainPositionRoot:
+		return CertChainPositionKeywordRoot
+	default:
+		return CertChainPositionKeywordUnknown
+	}
+}
+
+// IsLeaf indicates whether the chain position is a leaf (or server)
+// certificate, self-signed or otherwise.
+func (p CertChainPosition) IsLeaf() bool {
+	return p == CertChainPositionLeaf || p == CertChainPositionLeafSelfSigned
+}
+
+// MarshalText implements the encoding.TextMarshaler interface so that
+// certificate chain positions are encoded using their keyword.
+func (p CertChainPosition) MarshalText() ([]byte, error) {
+	return []byte(p.Keyword()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface so that
+// certificate chain positions are decoded from their keyword.
+func (p *CertChainPosition) UnmarshalText(text []byte) error {
+	for _, position := range []CertChainPosition{
+		CertChainPositionUnknown,
+		CertChainPositionLeaf,
+		CertChainPositionLeafSelfSigned,
+		CertChainPositionIntermediate,
+		CertChainPositionRoot,
+	} {
+		if position.Keyword() == string(text) {
+			*p = position
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown certificate chain position keyword %q", string(text))
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSystemClockSuspect indicates that the system clock of the host running
+// this application appears to be incorrect. Validation results based on the
+// current time (e.g., expiration) cannot be trusted if this is the case.
+var ErrSystemClockSuspect = errors.New("system clock appears to be incorrect")
+
+// systemClockFutureTolerance is how far in the future the "not before" date
+// of every certificate in a chain must be before the system clock is
+// considered suspect.
+const systemClockFutureTolerance time.Duration = 24 * time.Hour
+
+// CheckSystemClock asserts that the given current time is not before the
+// given build time. A zero build time (e.g., unavailable) skips this check.
+//
+// The application cannot have been built after the time reported by the
+// system clock, so this indicates a system clock which is set to a time in
+// the past (e.g., after an NTP synchronization failure or a dead CMOS
+// battery).
+func CheckSystemClock(now time.Time, buildTime time.Time) error {
+	if buildTime.IsZero() || !now.Before(buildTime) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"current time %s is before application build time %s: %w",
+		now.Format(CertValidityDateLayout),
+		buildTime.Format(CertValidityDateLayout),
+		ErrSystemClockSuspect,
+	)
+}
+
+// CheckSystemClockForCertChain asserts that the given current time is
+// plausible for the given certificate chain. Chains with fewer than two
+// (unique) certificates are not evaluated.
+//
+// A single certificate which is not yet valid is a problem with that
+// certificate, but it is very unlikely for every certificate in a chain
+// (including long-lived intermediate and root certificates) to have been
+// issued in the future. This indicates a system clock which is set to a time
+// in the past.
+func CheckSystemClockForCertChain(certChain []*x509.Certificate, now time.Time) error {
+	threshold := now.Add(systemClockFutureTolerance)
+
+	seen := make(map[[sha256.Size]byte]struct{}, len(certChain))
+	var earliest time.Time
+
+	for _, cert := range certChain {
+		if cert == nil {
+			continue
+		}
+
+		if !cert.NotBefore.After(threshold) {
+			return nil
+		}
+
+		seen[sha256.Sum256(cert.Raw)] = struct{}{}
+
+		if earliest.IsZero() || cert.NotBefore.Before(earliest) {
+			earliest = cert.NotBefore
+		}
+	}
+
+	if len(seen) < 2 {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"current time %s is before the issue date of all %d certificates in chain (earliest %s): %w",
+		now.Format(CertValidityDateLayout),
+		len(seen),
+		earliest.Format(CertValidityDateLayout),
+		ErrSystemClockSuspect,
+	)
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckSystemClock(t *testing.T) {
+	buildTime := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		buildTime time.Time
+		err       error
+	}{
+		{
+			name:      "AfterBuildTime",
+			now:       buildTime.Add(30 * 24 * time.Hour),
+			buildTime: buildTime,
+		},
+		{
+			name:      "EqualToBuildTime",
+			now:       buildTime,
+			buildTime: buildTime,
+		},
+		{
+			name:      "BeforeBuildTime",
+			now:       buildTime.Add(-time.Second),
+			buildTime: buildTime,
+			err:       ErrSystemClockSuspect,
+		},
+		{
+			name: "BuildTimeUnavailable",
+			now:  time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSystemClock(tt.now, tt.buildTime)
+
+			switch {
+			case tt.err == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case !errors.Is(err, tt.err):
+				t.Errorf("want error %v; got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestCheckSystemClockForCertChain(t *testing.T) {
+	certChain := newTestChain(t, "www.example.test")
+	leaf := certChain[0]
+
+	// The test certificates are not valid before one hour ago.
+	now := time.Now()
+	pastClock := now.Add(-365 * 24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		now       time.Time
+		err       error
+	}{
+		{
+			name:      "CorrectClock",
+			certChain: certChain,
+			now:       now,
+		},
+		{
+			name:      "ClockInPast",
+			certChain: certChain,
+			now:       pastClock,
+			err:       ErrSystemClockSuspect,
+		},
+		{
+			name:      "ClockInPastWithinTolerance",
+			certChain: certChain,
+			now:       now.Add(-12 * time.Hour),
+		},
+		{
+			name:      "ClockInPastIgnoresNilCerts",
+			certChain: []*x509.Certificate{nil, certChain[0], nil, certChain[1]},
+			now:       pastClock,
+			err:       ErrSystemClockSuspect,
+		},
+		{
+			name:      "SingleCert",
+			certChain: []*x509.Certificate{leaf},
+			now:       pastClock,
+		},
+		{
+			name:      "DuplicateCert",
+			certChain: []*x509.Certificate{leaf, leaf},
+			now:       pastClock,
+		},
+		{
+			name: "OneCertNotYetValid",
+			certChain: []*x509.Certificate{
+				newTestCert(t, "future.example.test", false, nil, func(c *x509.Certificate) {
+					c.NotBefore = now.Add(400 * 24 * time.Hour)
+					c.NotAfter = now.Add(800 * 24 * time.Hour)
+				}).cert,
+				leaf,
+			},
+			now: now,
+		},
+		{
+			name: "EmptyChain",
+			now:  pastClock,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSystemClockForCertChain(tt.certChain, tt.now)
+
+			switch {
+			case tt.err == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case !errors.Is(err, tt.err):
+				t.Errorf("want error %v; got %v", tt.err, err)
+			}
+		})
+	}
+}
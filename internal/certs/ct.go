@@ -0,0 +1,218 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+)
+
+// ctLogTimestampLayout is the layout used by crt.sh compatible CT search
+// APIs for certificate validity timestamps. Values are provided in UTC
+// without a timezone designator.
+const ctLogTimestampLayout string = "2006-01-02T15:04:05"
+
+// ctLogMaxResponseSize is the maximum number of bytes read from a CT search
+// API response. This guards against unexpectedly large responses for
+// popular domains.
+const ctLogMaxResponseSize int64 = 16 * 1024 * 1024
+
+// CTLogEntry is a certificate recorded in Certificate Transparency logs as
+// reported by a CT search API.
+type CTLogEntry struct {
+	// SerialNumber is the formatted serial number of the logged certificate.
+	SerialNumber string
+
+	// CommonName is the Subject Common Name of the logged certificate.
+	CommonName string
+
+	// IssuerName is the Issuer Distinguished Name of the logged certificate.
+	IssuerName string
+
+	// NotBefore is the start of the validity period for the logged
+	// certificate.
+	NotBefore time.Time
+
+	// NotAfter is the end of the validity period for the logged certificate.
+	NotAfter time.Time
+}
+
+// ctSearchResult is a single result from a crt.sh compatible CT search API.
+type ctSearchResult struct {
+	IssuerName   string `json:"issuer_name"`
+	CommonName   string `json:"common_name"`
+	SerialNumber string `json:"serial_number"`
+	NotBefore    string `json:"not_before"`
+	NotAfter     string `json:"not_after"`
+}
+
+// FetchCTLogEntries retrieves unexpired certificates logged for the given
+// domain from a crt.sh compatible Certificate Transparency search API at the
+// specified URL.
+//
+// Precertificates and final certificates share the same serial number and
+// are reported as a single entry.
+//
+// If specified, the API token is sent as a bearer token with the search
+// request.
+//
+// The search request and response size are recorded against the given
+// network budget. If the budget is exhausted an error wrapping
+// budget.ErrExceeded is returned.
+func FetchCTLogEntries(searchURL string, apiToken string, domain string, timeout time.Duration, netBudget *budget.Budget) ([]CTLogEntry, error) {
+	if domain == "" {
+		return nil, fmt.Errorf(
+			"domain for CT log search not provided: %w",
+			ErrMissingValue,
+		)
+	}
+
+	u, err := url.Parse(searchURL)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse CT search URL %q: %w",
+			searchURL,
+			err,
+		)
+	}
+
+	query := u.Query()
+	query.Set("q", domain)
+	query.Set("output", "json")
+	query.Set("exclude", "expired")
+	u.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to prepare CT search request: %w",
+			err,
+		)
+	}
+	req.Header.Set("Accept", "application/json")
+	if apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+apiToken)
+	}
+
+	if err := netBudget.Request(); err != nil {
+		return nil, fmt.Errorf(
+			"skipped CT search API query for %q: %w",
+			domain,
+			err,
+		)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to query CT search API for %q: %w",
+			domain,
+			err,
+		)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"unexpected response from CT search API for %q: %s",
+			domain,
+			resp.Status,
+		)
+	}
+
+	body, err := io.ReadAll(netBudget.Reader(io.LimitReader(resp.Body, ctLogMaxResponseSize)))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read CT search API response for %q: %w",
+			domain,
+			err,
+		)
+	}
+
+	var results []ctSearchResult
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf(
+			"failed to decode CT search API response for %q: %w",
+			domain,
+			err,
+		)
+	}
+
+	entries := make([]CTLogEntry, 0, len(results))
+	seen := make(map[string]struct{}, len(results))
+
+	for _, result := range results {
+		serial, err := parseCTLogSerial(result.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[serial]; ok {
+			continue
+		}
+		seen[serial] = struct{}{}
+
+		notBefore, err := time.Parse(ctLogTimestampLayout, result.NotBefore)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse CT log entry not_before value %q: %w",
+				result.NotBefore,
+				err,
+			)
+		}
+
+		notAfter, err := time.Parse(ctLogTimestampLayout, result.NotAfter)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to parse CT log entry not_after value %q: %w",
+				result.NotAfter,
+				err,
+			)
+		}
+
+		entries = append(entries, CTLogEntry{
+			SerialNumber: serial,
+			CommonName:   result.CommonName,
+			IssuerName:   result.IssuerName,
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+		})
+	}
+
+	return entries, nil
+}
+
+// parseCTLogSerial converts the plain hex serial number reported by a CT
+// search API to the format used for certificate serial numbers elsewhere in
+// this project.
+func parseCTLogSerial(serial string) (string, error) {
+	n, ok := new(big.Int).SetString(strings.TrimSpace(serial), 16)
+	if !ok {
+		return "", fmt.Errorf(
+			"failed to parse CT log entry serial number %q: %w",
+			serial,
+			ErrCTLogLookupFailed,
+		)
+	}
+
+	return FormatCertSerialNumber(n), nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+)
+
+func TestFetchCTLogEntries(t *testing.T) {
+	t.Parallel()
+
+	// The final certificate and its precertificate share a serial number.
+	const response string = `[
+		{"issuer_name": "CN=Test CA", "common_name": "www.example.com", "serial_number": "0afd502b", "not_before": "2024-01-01T00:00:00", "not_after": "2024-04-01T00:00:00"},
+		{"issuer_name": "CN=Test CA", "common_name": "www.example.com", "serial_number": "0afd502b", "not_before": "2024-01-01T00:00:00", "not_after": "2024-04-01T00:00:00"},
+		{"issuer_name": "CN=Test CA", "common_name": "www.example.com", "serial_number": "1b2c", "not_before": "2024-03-01T00:00:00", "not_after": "2024-06-01T00:00:00"}
+	]`
+
+	var gotQuery, gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("q")
+		gotAuth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("Entries", func(t *testing.T) {
+		entries, err := FetchCTLogEntries(server.URL, "s3cret", "www.example.com", 5*time.Second, nil)
+		if err != nil {
+			t.Fatalf("want no error; got %v", err)
+		}
+
+		if len(entries) != 2 {
+			t.Fatalf("want 2 entries; got %d", len(entries))
+		}
+
+		if want := "0A:FD:50:2B"; entries[0].SerialNumber != want {
+			t.Errorf("want serial %q; got %q", want, entries[0].SerialNumber)
+		}
+
+		if want := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC); !entries[1].NotBefore.Equal(want) {
+			t.Errorf("want not before %v; got %v", want, entries[1].NotBefore)
+		}
+
+		if gotQuery != "www.example.com" {
+			t.Errorf("want query %q; got %q", "www.example.com", gotQuery)
+		}
+
+		if gotAuth != "Bearer s3cret" {
+			t.Errorf("want bearer token; got %q", gotAuth)
+		}
+	})
+
+	t.Run("BudgetExceeded", func(t *testing.T) {
+		netBudget := budget.New(1, 0)
+		if err := netBudget.Request(); err != nil {
+			t.Fatalf("failed to record request: %v", err)
+		}
+
+		_, err := FetchCTLogEntries(server.URL, "", "www.example.com", 5*time.Second, netBudget)
+		if !errors.Is(err, budget.ErrExceeded) {
+			t.Errorf("want error %v; got %v", budget.ErrExceeded, err)
+		}
+	})
+
+	t.Run("MissingDomain", func(t *testing.T) {
+		_, err := FetchCTLogEntries(server.URL, "", "", 5*time.Second, nil)
+		if !errors.Is(err, ErrMissingValue) {
+			t.Errorf("want error %v; got %v", ErrMissingValue, err)
+		}
+	})
+}
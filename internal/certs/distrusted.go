@@ -0,0 +1,309 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Distrusted CA list entry types.
+const (
+	DistrustedCAEntrySPKI        string = "spki"
+	DistrustedCAEntryFingerprint string = "fingerprint"
+)
+
+// DistrustedCA describes a certificate authority that major browser and
+// operating system trust stores have removed or explicitly distrusted.
+// Certificate chains relying on these authorities are rejected by clients
+// regardless of their expiration dates.
+type DistrustedCA struct {
+	// Name is a human-readable description of the distrusted certificate
+	// authority, typically the Subject Common Name and reason for distrust.
+	Name string
+
+	// EntryType indicates how certificates are matched against this entry;
+	// either by the SHA-256 hash of the certificate Subject Public Key Info
+	// (spki) or by the certificate SHA-256 fingerprint (fingerprint).
+	EntryType string
+
+	// Hash is the SHA-256 hash used to match certificates against this
+	// entry. The value is in uppercase hex format without delimiters.
+	Hash string
+}
+
+// DistrustedCAList is a collection of distrusted certificate authorities
+// matched by public key or by certificate fingerprint.
+type DistrustedCAList struct {
+	spki         map[string]DistrustedCA
+	fingerprints map[string]DistrustedCA
+}
+
+// Distrust reasons shared by multiple list entries.
+const (
+	distrustReasonSymantec string = "Symantec legacy PKI distrusted by browsers in 2018"
+	distrustReasonTrustCor string = "TrustCor roots removed from browser trust stores in 2022"
+)
+
+// defaultDistrustedCAs is the built-in list of distrusted certificate
+// authorities. It is used by the distrusted CA validation check unless the
+// sysadmin provides a list.
+//
+// Most entries are matched by the public key of the root certificate so
+// that re-issued copies of the root are also flagged. The VeriSign Class 3
+// G5, VeriSign Universal Root and GeoTrust Global CA keys are still used by
+// valid cross-signed certificates, so only the distrusted self-signed root
+// certificates are matched by fingerprint.
+//
+// This list is intentionally small and should be updated as trust store
+// vendors announce new distrust actions.
+var defaultDistrustedCAs = []DistrustedCA{
+	{
+		Name:      "VeriSign Class 3 Public Primary Certification Authority - G3 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "495A96BA6BAD782407BD521A00BACE657BB355555E4BB7F8146C71BBA57E7ACE",
+	},
+	{
+		Name:      "VeriSign Class 3 Public Primary Certification Authority - G4 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "5192438EC369D7EE0CE71F5C6DB75F941EFBF72E58441715E99EAB04C2C8ACEE",
+	},
+	{
+		Name:      "VeriSign Class 3 Public Primary Certification Authority - G5 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntryFingerprint,
+		Hash:      "9ACFAB7E43C8D880D06B262A94DEEEE4B4659989C3D0CAF19BAF6405E41AB7DF",
+	},
+	{
+		Name:      "VeriSign Universal Root Certification Authority (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntryFingerprint,
+		Hash:      "2399561127A57125DE8CEFEA610DDF2FA078B5C8067F4E828290BFB860E84B3C",
+	},
+	{
+		Name:      "GeoTrust Global CA (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntryFingerprint,
+		Hash:      "FF856A2D251DCD88D36656F450126798CFABAADE40799C722DE4D2B5DB36A73A",
+	},
+	{
+		Name:      "GeoTrust Primary Certification Authority (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "4905466623AB4178BE92AC5CBD6584F7A1E17F27652D5A85AF89504EA239AAAA",
+	},
+	{
+		Name:      "GeoTrust Primary Certification Authority - G2 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "BCFB44AAB9AD021015706B4121EA761C81C9E88967590F6F94AE744DC88B78FB",
+	},
+	{
+		Name:      "GeoTrust Primary Certification Authority - G3 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "AB98495276ADF1ECAFF28F35C53048781E5C1718DAB9C8E67A504F4F6A51328F",
+	},
+	{
+		Name:      "GeoTrust Universal CA (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "9699225C5DE52E56CDD32DF2E96D1CFEA5AA3CA0BB52CD8933C23B5C27443820",
+	},
+	{
+		Name:      "GeoTrust Universal CA 2 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "7CAA03465124590C601E567E52148E952C0CFFE89000530FE0D95B6D50EAAE41",
+	},
+	{
+		Name:      "thawte Primary Root CA (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "1D75D0831B9E0885394D32C7A1BFDB3DBC1C28E2B0E8391FB135981DBC5BA936",
+	},
+	{
+		Name:      "thawte Primary Root CA - G2 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "67DC4F32FA10E7D01A79A073AA0C9E0212EC2FFC3D779E0AA7F9C0F0E1C2C893",
+	},
+	{
+		Name:      "thawte Primary Root CA - G3 (" + distrustReasonSymantec + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "1906C6124DBB438578D00E066D5054C6C37F0FA6028C05545E0994EDDAEC8629",
+	},
+	{
+		Name:      "TrustCor RootCert CA-1 (" + distrustReasonTrustCor + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "EA87F462DEEFFFBD7775AA2A4B7E0FCB91C22EEE6DF69ED90100CCC73B311476",
+	},
+	{
+		Name:      "TrustCor RootCert CA-2 (" + distrustReasonTrustCor + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "C63D68C648A18B77641C427A669D61C9768A55F4FCD0322EAC96C57700299CF1",
+	},
+	{
+		Name:      "TrustCor ECA-1 (" + distrustReasonTrustCor + ")",
+		EntryType: DistrustedCAEntrySPKI,
+		Hash:      "7AFE4B071A2F1F46F8BA944A26D584D5960B92FB48C3BA1B7CAB84905F32AACD",
+	},
+}
+
+// newDistrustedCAList returns an empty distrusted certificate authorities
+// list.
+func newDistrustedCAList() DistrustedCAList {
+	return DistrustedCAList{
+		spki:         make(map[string]DistrustedCA),
+		fingerprints: make(map[string]DistrustedCA),
+	}
+}
+
+// add records the given entry in the list, replacing any existing entry
+// with the same type and hash.
+func (dcl DistrustedCAList) add(ca DistrustedCA) {
+	switch ca.EntryType {
+	case DistrustedCAEntrySPKI:
+		dcl.spki[ca.Hash] = ca
+	case DistrustedCAEntryFingerprint:
+		dcl.fingerprints[ca.Hash] = ca
+	}
+}
+
+// DefaultDistrustedCAList returns the built-in list of distrusted
+// certificate authorities.
+func DefaultDistrustedCAList() DistrustedCAList {
+	list := newDistrustedCAList()
+	for _, ca := range defaultDistrustedCAs {
+		list.add(ca)
+	}
+
+	return list
+}
+
+// ParseDistrustedCAList parses distrusted certificate authority entries from
+// the given reader. Each line is expected to contain an entry type (spki or
+// fingerprint) and a SHA-256 hash in hex format with optional delimiters,
+// separated by a colon, optionally followed by whitespace and a description
+// of the certificate authority. Blank lines and text following a #
+// character are ignored.
+//
+// For example:
+//
+//	# Example Root CA, distrusted in 2024
+//	spki:4905466623AB4178BE92AC5CBD6584F7A1E17F27652D5A85AF89504EA239AAAA Example Root CA
+func ParseDistrustedCAList(r io.Reader) (DistrustedCAList, error) {
+	list := newDistrustedCAList()
+
+	scanner := bufio.NewScanner(r)
+
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		entry, name, _ := strings.Cut(line, " ")
+		entryType, hash, _ := strings.Cut(entry, ":")
+		entryType = strings.ToLower(entryType)
+		hash = normalizeFingerprint(hash)
+
+		switch {
+		case entryType != DistrustedCAEntrySPKI && entryType != DistrustedCAEntryFingerprint,
+			!isFingerprint(hash):
+			return DistrustedCAList{}, fmt.Errorf(
+				"entry %q on line %d: %w",
+				line,
+				lineNum,
+				ErrInvalidDistrustedCAEntry,
+			)
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			name = entryType + ":" + hash
+		}
+
+		list.add(DistrustedCA{
+			Name:      name,
+			EntryType: entryType,
+			Hash:      hash,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return DistrustedCAList{}, fmt.Errorf(
+			"failed to read distrusted CA entries: %w",
+			err,
+		)
+	}
+
+	return list, nil
+}
+
+// LoadDistrustedCAListFile parses distrusted certificate authority entries
+// from the specified file. See ParseDistrustedCAList for the expected
+// format.
+func LoadDistrustedCAListFile(filename string) (DistrustedCAList, error) {
+	// Open the list file after first attempting to sanitize the input file
+	// variable contents.
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return DistrustedCAList{}, fmt.Errorf(
+			"failed to open distrusted CAs file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	list, err := ParseDistrustedCAList(f)
+	if err != nil {
+		return DistrustedCAList{}, fmt.Errorf(
+			"failed to parse distrusted CAs file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return list, nil
+}
+
+// Len returns the total number of entries in the list.
+func (dcl DistrustedCAList) Len() int {
+	return len(dcl.spki) + len(dcl.fingerprints)
+}
+
+// Match indicates whether the public key or fingerprint of the given
+// certificate matches an entry in the list. The matching entry is returned
+// if found.
+func (dcl DistrustedCAList) Match(cert *x509.Certificate) (DistrustedCA, bool) {
+	if cert == nil {
+		return DistrustedCA{}, false
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	if ca, ok := dcl.fingerprints[strings.ToUpper(hex.EncodeToString(fingerprint[:]))]; ok {
+		return ca, true
+	}
+
+	spki := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	if ca, ok := dcl.spki[strings.ToUpper(hex.EncodeToString(spki[:]))]; ok {
+		return ca, true
+	}
+
+	return DistrustedCA{}, false
+}
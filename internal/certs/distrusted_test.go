@@ -0,0 +1,217 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// loadTestCert loads the first certificate from the given file in the
+// testdata directory.
+func loadTestCert(t *testing.T, filename string) *x509.Certificate {
+	t.Helper()
+
+	certChain, _, err := GetCertsFromFile(filepath.Join("testdata", filename))
+	if err != nil {
+		t.Fatalf("failed to load certificate %q: %v", filename, err)
+	}
+
+	return certChain[0]
+}
+
+// spkiHash returns the SHA-256 hash of the Subject Public Key Info for the
+// given certificate in hex format.
+func spkiHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// fingerprintHash returns the SHA-256 fingerprint of the given certificate
+// in hex format.
+func fingerprintHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseDistrustedCAList(t *testing.T) {
+	t.Parallel()
+
+	hash := strings.Repeat("ab", sha256.Size)
+
+	tests := []struct {
+		name    string
+		input   string
+		wantLen int
+		err     error
+	}{
+		{
+			name: "ValidEntries",
+			input: "# distrusted roots\n\n" +
+				"spki:" + hash + " Example Root CA\n" +
+				"FINGERPRINT:" + strings.ToUpper(hash) + "   # no description\n",
+			wantLen: 2,
+		},
+		{
+			name:    "DelimitedHash",
+			input:   "spki:" + strings.Repeat("AB:", sha256.Size-1) + "AB\n",
+			wantLen: 1,
+		},
+		{
+			name:  "UnsupportedEntryType",
+			input: "subject:" + hash + "\n",
+			err:   ErrInvalidDistrustedCAEntry,
+		},
+		{
+			name:  "MissingEntryType",
+			input: hash + "\n",
+			err:   ErrInvalidDistrustedCAEntry,
+		},
+		{
+			name:  "InvalidHash",
+			input: "spki:abcd\n",
+			err:   ErrInvalidDistrustedCAEntry,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			list, err := ParseDistrustedCAList(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if list.Len() != tt.wantLen {
+				t.Errorf("want %d entries; got %d", tt.wantLen, list.Len())
+			}
+		})
+	}
+}
+
+func TestValidateDistrustedCAs(t *testing.T) {
+	t.Parallel()
+
+	geoTrustRoot := loadTestCert(t, "distrusted/geotrust-primary-ca.pem")
+	veriSignG5Root := loadTestCert(t, "distrusted/verisign-class3-g5.pem")
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+	leaf := newTestCert(t, "www.example.com", false, intermediate, nil)
+	chain := []*x509.Certificate{leaf.cert, intermediate.cert, root.cert}
+
+	// A copy of the intermediate certificate issued by a different root
+	// using the same key, as is done when cross-signing.
+	otherRoot := newTestCert(t, "Other Root CA", true, nil, nil)
+	crossSignedTemplate := *intermediate.cert
+	crossSignedTemplate.SerialNumber = big.NewInt(testSerial.Add(1))
+	crossSigned := issueTestCert(t, &crossSignedTemplate, intermediate.key, otherRoot)
+	crossSignedChain := []*x509.Certificate{leaf.cert, crossSigned.cert, otherRoot.cert}
+
+	parseList := func(t *testing.T, entries ...string) DistrustedCAList {
+		t.Helper()
+
+		list, err := ParseDistrustedCAList(strings.NewReader(strings.Join(entries, "\n")))
+		if err != nil {
+			t.Fatalf("failed to parse distrusted CAs list: %v", err)
+		}
+
+		return list
+	}
+
+	tests := []struct {
+		name          string
+		certChain     []*x509.Certificate
+		distrustedCAs DistrustedCAList
+		err           error
+		wantMatches   int
+	}{
+		{
+			name:          "BuiltInListUnlistedChain",
+			certChain:     chain,
+			distrustedCAs: DefaultDistrustedCAList(),
+		},
+		{
+			name:          "BuiltInListSPKIMatch",
+			certChain:     []*x509.Certificate{leaf.cert, geoTrustRoot},
+			distrustedCAs: DefaultDistrustedCAList(),
+			err:           ErrCertChainHasDistrustedCA,
+			wantMatches:   1,
+		},
+		{
+			name:          "BuiltInListFingerprintMatch",
+			certChain:     []*x509.Certificate{leaf.cert, veriSignG5Root},
+			distrustedCAs: DefaultDistrustedCAList(),
+			err:           ErrCertChainHasDistrustedCA,
+			wantMatches:   1,
+		},
+		{
+			name:          "SPKIMatchesCrossSignedCert",
+			certChain:     crossSignedChain,
+			distrustedCAs: parseList(t, "spki:"+spkiHash(intermediate.cert)),
+			err:           ErrCertChainHasDistrustedCA,
+			wantMatches:   1,
+		},
+		{
+			name:          "FingerprintIgnoresCrossSignedCert",
+			certChain:     crossSignedChain,
+			distrustedCAs: parseList(t, "fingerprint:"+fingerprintHash(intermediate.cert)),
+		},
+		{
+			name:          "SameSubjectDifferentKey",
+			certChain:     chain,
+			distrustedCAs: parseList(t, "spki:"+spkiHash(otherRoot.cert)+" Test Root CA"),
+		},
+		{
+			name:      "RepeatedMatchCountedOnce",
+			certChain: []*x509.Certificate{leaf.cert, intermediate.cert, intermediate.cert, root.cert},
+			distrustedCAs: parseList(t,
+				"spki:"+spkiHash(intermediate.cert),
+				"fingerprint:"+fingerprintHash(root.cert),
+			),
+			err:         ErrCertChainHasDistrustedCA,
+			wantMatches: 2,
+		},
+		{
+			name:          "EmptyChain",
+			distrustedCAs: DefaultDistrustedCAList(),
+			err:           ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateDistrustedCAs(tt.certChain, tt.distrustedCAs, CertChainValidationOptions{})
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if result.NumMatches() != tt.wantMatches {
+				t.Errorf("want %d matches; got %d (%s)", tt.wantMatches, result.NumMatches(), result.StatusDetail())
+			}
+
+			if got, want := result.IsCriticalState(), tt.err != nil; got != want {
+				t.Errorf("want CRITICAL state %t; got %t", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// ExpirationThresholds is the collection of CRITICAL and WARNING certificate
+// expiration thresholds (specified in number of days from this moment)
+// applied to certificates based on their position in a certificate chain.
+//
+// Intermediate and root certificate thresholds are optional; if not
+// specified (zero value) the leaf certificate thresholds are used.
+type ExpirationThresholds struct {
+	// LeafCritical is the CRITICAL threshold for leaf certificates. This is
+	// also applied to certificates with an unknown chain position.
+	LeafCritical int
+
+	// LeafWarning is the WARNING threshold for leaf certificates. This is
+	// also applied to certificates with an unknown chain position.
+	LeafWarning int
+
+	// IntermediateCritical is the CRITICAL threshold for intermediate
+	// certificates.
+	IntermediateCritical int
+
+	// IntermediateWarning is the WARNING threshold for intermediate
+	// certificates.
+	IntermediateWarning int
+
+	// RootCritical is the CRITICAL threshold for root certificates.
+	RootCritical int
+
+	// RootWarning is the WARNING threshold for root certificates.
+	RootWarning int
+
+	// LeafCriticalPercent is the percentage of certificate lifetime
+	// remaining that the LeafCritical threshold was calculated from (if
+	// any). This is noted alongside leaf certificate expiration details.
+	LeafCriticalPercent int
+
+	// LeafWarningPercent is the percentage of certificate lifetime
+	// remaining that the LeafWarning threshold was calculated from (if
+	// any). This is noted alongside leaf certificate expiration details.
+	LeafWarningPercent int
+}
+
+// expirationThresholdDates is the collection of CRITICAL and WARNING
+// expiration threshold dates calculated from user specified thresholds for
+// each certificate chain position.
+type expirationThresholdDates struct {
+	leafCritical         time.Time
+	leafWarning          time.Time
+	intermediateCritical time.Time
+	intermediateWarning  time.Time
+	rootCritical         time.Time
+	rootWarning          time.Time
+
+	// leafCriticalPercent and leafWarningPercent are the percentage of
+	// lifetime remaining thresholds (if any) for leaf certificates.
+	leafCriticalPercent int
+	leafWarningPercent  int
+}
+
+// newExpirationThresholdDates calculates the expiration threshold dates for
+// each certificate chain position relative to the given moment. Leaf
+// certificate thresholds are used in place of any unspecified intermediate
+// or root certificate thresholds.
+func newExpirationThresholdDates(now time.Time, thresholds ExpirationThresholds) expirationThresholdDates {
+	daysOrDefault := func(days int, defaultDays int) int {
+		if days == 0 {
+			return defaultDays
+		}
+
+		return days
+	}
+
+	return expirationThresholdDates{
+		leafCritical:         now.AddDate(0, 0, thresholds.LeafCritical),
+		leafWarning:          now.AddDate(0, 0, thresholds.LeafWarning),
+		intermediateCritical: now.AddDate(0, 0, daysOrDefault(thresholds.IntermediateCritical, thresholds.LeafCritical)),
+		intermediateWarning:  now.AddDate(0, 0, daysOrDefault(thresholds.IntermediateWarning, thresholds.LeafWarning)),
+		rootCritical:         now.AddDate(0, 0, daysOrDefault(thresholds.RootCritical, thresholds.LeafCritical)),
+		rootWarning:          now.AddDate(0, 0, daysOrDefault(thresholds.RootWarning, thresholds.LeafWarning)),
+		leafCriticalPercent:  thresholds.LeafCriticalPercent,
+		leafWarningPercent:   thresholds.LeafWarningPercent,
+	}
+}
+
+// uniformExpirationThresholdDates uses the given CRITICAL and WARNING
+// threshold dates for all certificate chain positions.
+func uniformExpirationThresholdDates(ageCritical time.Time, ageWarning time.Time) expirationThresholdDates {
+	return expirationThresholdDates{
+		leafCritical:         ageCritical,
+		leafWarning:          ageWarning,
+		intermediateCritical: ageCritical,
+		intermediateWarning:  ageWarning,
+		rootCritical:         ageCritical,
+		rootWarning:          ageWarning,
+	}
+}
+
+// forCert returns the CRITICAL and WARNING threshold dates applicable to the
+// given certificate based on its position in the given certificate chain.
+func (etd expirationThresholdDates) forCert(cert *x509.Certificate, certChain []*x509.Certificate) (time.Time, time.Time) {
+	switch {
+	case IsIntermediateCert(cert, certChain):
+		return etd.intermediateCritical, etd.intermediateWarning
+	case IsRootCert(cert, certChain):
+		return etd.rootCritical, etd.rootWarning
+	default:
+		return etd.leafCritical, etd.leafWarning
+	}
+}
+
+// numExpiringCerts indicates the number of certificates in the given
+// certificate chain that are expiring soon using the thresholds applicable
+// to each certificate's chain position. Any already expired certificates are
+// ignored.
+func (etd expirationThresholdDates) numExpiringCerts(certs []*x509.Certificate, certChain []*x509.Certificate) int {
+	var num int
+	for _, cert := range certs {
+		ageCritical, ageWarning := etd.forCert(cert, certChain)
+		if IsExpiringCert(cert, ageCritical, ageWarning) {
+			num++
+		}
+	}
+
+	return num
+}
+
+// lifetimeStatus returns a description of the percentage of lifetime
+// remaining thresholds (along with the number of days each represents) if
+// the given certificate is a leaf certificate and percentage thresholds were
+// specified. An empty string is returned otherwise.
+func (etd expirationThresholdDates) lifetimeStatus(cert *x509.Certificate, certChain []*x509.Certificate) string {
+	if etd.leafCriticalPercent == 0 || etd.leafWarningPercent == 0 {
+		return ""
+	}
+
+	if !ChainPosition(cert, certChain).IsLeaf() {
+		return ""
+	}
+
+	return LifetimeThresholdsStatus(cert, etd.leafCriticalPercent, etd.leafWarningPercent)
+}
+
+// ForCert returns the CRITICAL and WARNING threshold dates (relative to the
+// given moment) applicable to the given certificate based on its position in
+// the given certificate chain. Leaf certificate thresholds are used in place
+// of any unspecified intermediate or root certificate thresholds.
+func (et ExpirationThresholds) ForCert(now time.Time, cert *x509.Certificate, certChain []*x509.Certificate) (time.Time, time.Time) {
+	return newExpirationThresholdDates(now, et).forCert(cert, certChain)
+}
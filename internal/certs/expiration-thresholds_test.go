@@ -0,0 +1,142 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLifetimePercentageInDays(t *testing.T) {
+	t.Parallel()
+
+	// certWithLifetime returns a certificate valid for the given duration.
+	certWithLifetime := func(lifetime time.Duration) *x509.Certificate {
+		notBefore := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+		return &x509.Certificate{
+			NotBefore: notBefore,
+			NotAfter:  notBefore.Add(lifetime),
+		}
+	}
+
+	const day = 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		cert       *x509.Certificate
+		percentage int
+		want       int
+		err        error
+	}{
+		{
+			name:       "WholeDays",
+			cert:       certWithLifetime(90 * day),
+			percentage: 20,
+			want:       18,
+		},
+		{
+			name:       "PartialDayRoundedUp",
+			cert:       certWithLifetime(398 * day),
+			percentage: 33,
+			want:       132,
+		},
+		{
+			name:       "FullLifetime",
+			cert:       certWithLifetime(47 * day),
+			percentage: 100,
+			want:       47,
+		},
+		{
+			name:       "MinimumOneDay",
+			cert:       certWithLifetime(6 * time.Hour),
+			percentage: 10,
+			want:       1,
+		},
+		{
+			name:       "MissingCert",
+			percentage: 20,
+			err:        ErrMissingValue,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LifetimePercentageInDays(tt.cert, tt.percentage)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("want %d days; got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpirationThresholdsForCert(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+	now := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	thresholds := ExpirationThresholds{
+		LeafCritical:         15,
+		LeafWarning:          30,
+		IntermediateCritical: 60,
+		IntermediateWarning:  90,
+	}
+
+	tests := []struct {
+		name         string
+		cert         *x509.Certificate
+		wantCritical int
+		wantWarning  int
+	}{
+		{
+			name:         "Leaf",
+			cert:         certChain[0],
+			wantCritical: 15,
+			wantWarning:  30,
+		},
+		{
+			name:         "Intermediate",
+			cert:         certChain[1],
+			wantCritical: 60,
+			wantWarning:  90,
+		},
+		{
+			name:         "RootUsesLeafThresholds",
+			cert:         certChain[2],
+			wantCritical: 15,
+			wantWarning:  30,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			critical, warning := thresholds.ForCert(now, tt.cert, certChain)
+
+			if want := now.AddDate(0, 0, tt.wantCritical); !critical.Equal(want) {
+				t.Errorf("want CRITICAL threshold %v; got %v", want, critical)
+			}
+
+			if want := now.AddDate(0, 0, tt.wantWarning); !warning.Equal(want) {
+				t.Errorf("want WARNING threshold %v; got %v", want, warning)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ExpiryCliff is a group of two or more certificates which expire within the
+// same short window. Renewing many certificates at once requires more lead
+// time than renewing a single certificate.
+type ExpiryCliff struct {
+	// Certs is the collection of certificates which expire within the
+	// window, ordered by expiration date.
+	Certs []*x509.Certificate
+}
+
+// Start returns the earliest expiration date of the certificates in the
+// expiry cliff.
+func (ec ExpiryCliff) Start() time.Time {
+	if len(ec.Certs) == 0 {
+		return time.Time{}
+	}
+
+	return ec.Certs[0].NotAfter
+}
+
+// End returns the latest expiration date of the certificates in the expiry
+// cliff.
+func (ec ExpiryCliff) End() time.Time {
+	if len(ec.Certs) == 0 {
+		return time.Time{}
+	}
+
+	return ec.Certs[len(ec.Certs)-1].NotAfter
+}
+
+// String provides a brief human-readable description of the expiry cliff.
+func (ec ExpiryCliff) String() string {
+	return fmt.Sprintf(
+		"%d certificates expire between %s and %s (%s)",
+		len(ec.Certs),
+		ec.Start().Format(CertValidityDateLayout),
+		ec.End().Format(CertValidityDateLayout),
+		FormattedExpiration(ec.Start()),
+	)
+}
+
+// ExpiryCliffs evaluates the given certificates and returns a (potentially
+// empty) collection of expiry cliffs; groups of two or more certificates
+// whose expiration dates fall within the given window of the earliest
+// expiration in the group. Only expiry cliffs starting within the given lead
+// time are returned. Certificates which have already expired are excluded
+// and certificates present more than once (compared by fingerprint) are
+// evaluated once.
+//
+// A window of zero disables expiry cliff detection.
+func ExpiryCliffs(certChain []*x509.Certificate, window time.Duration, leadTime time.Duration) []ExpiryCliff {
+	if window <= 0 || len(certChain) < 2 {
+		return nil
+	}
+
+	now := time.Now()
+	horizon := now.Add(leadTime)
+
+	seen := make(map[[sha256.Size]byte]struct{}, len(certChain))
+	candidates := make([]*x509.Certificate, 0, len(certChain))
+
+	for _, cert := range certChain {
+		if cert == nil || cert.NotAfter.Before(now) {
+			continue
+		}
+
+		fingerprint := sha256.Sum256(cert.Raw)
+		if _, ok := seen[fingerprint]; ok {
+			continue
+		}
+		seen[fingerprint] = struct{}{}
+
+		candidates = append(candidates, cert)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].NotAfter.Before(candidates[j].NotAfter)
+	})
+
+	var cliffs []ExpiryCliff
+
+	for start := 0; start < len(candidates); {
+		// Expiry cliffs are ordered by expiration date, so none of the
+		// remaining certificates start an expiry cliff within the lead time.
+		if candidates[start].NotAfter.After(horizon) {
+			break
+		}
+
+		end := start + 1
+		windowEnd := candidates[start].NotAfter.Add(window)
+		for end < len(candidates) && !candidates[end].NotAfter.After(windowEnd) {
+			end++
+		}
+
+		if end-start < 2 {
+			start++
+
+			continue
+		}
+
+		cliffs = append(cliffs, ExpiryCliff{Certs: candidates[start:end]})
+		start = end
+	}
+
+	return cliffs
+}
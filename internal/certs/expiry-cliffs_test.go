@@ -0,0 +1,133 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+)
+
+// newTestCertExpiringAt generates a CA certificate expiring at the given
+// time.
+func newTestCertExpiringAt(t *testing.T, commonName string, notAfter time.Time) *x509.Certificate {
+	t.Helper()
+
+	return newTestCert(t, commonName, true, nil, func(c *x509.Certificate) {
+		c.NotAfter = notAfter
+	}).cert
+}
+
+func TestExpiryCliffs(t *testing.T) {
+	t.Parallel()
+
+	const (
+		day      = 24 * time.Hour
+		window   = 7 * day
+		leadTime = 60 * day
+	)
+
+	// Certificate validity dates are encoded with second precision.
+	start := time.Now().Add(30 * day).Truncate(time.Second)
+
+	first := newTestCertExpiringAt(t, "first", start)
+	atBoundary := newTestCertExpiringAt(t, "at boundary", start.Add(window))
+	pastBoundary := newTestCertExpiringAt(t, "past boundary", start.Add(window+time.Second))
+	insideWindow := newTestCertExpiringAt(t, "inside window", start.Add(window-time.Second))
+	beyondLeadTime := newTestCertExpiringAt(t, "beyond lead time", time.Now().Add(leadTime+day))
+	beyondLeadTimeToo := newTestCertExpiringAt(t, "beyond lead time too", time.Now().Add(leadTime+2*day))
+	expired := newTestCertExpiringAt(t, "expired", time.Now().Add(-time.Hour))
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		window    time.Duration
+		want      [][]*x509.Certificate
+	}{
+		{
+			name:      "AtWindowBoundary",
+			certChain: []*x509.Certificate{atBoundary, first},
+			window:    window,
+			want:      [][]*x509.Certificate{{first, atBoundary}},
+		},
+		{
+			name:      "PastWindowBoundary",
+			certChain: []*x509.Certificate{first, pastBoundary},
+			window:    window,
+		},
+		{
+			name:      "InsideWindowWithBoundary",
+			certChain: []*x509.Certificate{first, insideWindow, atBoundary, pastBoundary},
+			window:    window,
+			want:      [][]*x509.Certificate{{first, insideWindow, atBoundary}},
+		},
+		{
+			name:      "BeyondLeadTime",
+			certChain: []*x509.Certificate{beyondLeadTime, beyondLeadTimeToo},
+			window:    window,
+		},
+		{
+			name:      "ExpiredExcluded",
+			certChain: []*x509.Certificate{expired, first},
+			window:    window,
+		},
+		{
+			name:      "DuplicateCertEvaluatedOnce",
+			certChain: []*x509.Certificate{first, first},
+			window:    window,
+		},
+		{
+			name:      "WindowDisabled",
+			certChain: []*x509.Certificate{first, atBoundary},
+			window:    0,
+		},
+		{
+			name:      "SingleCert",
+			certChain: []*x509.Certificate{first},
+			window:    window,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cliffs := ExpiryCliffs(tt.certChain, tt.window, leadTime)
+
+			if len(cliffs) != len(tt.want) {
+				t.Fatalf("want %d expiry cliffs; got %d: %v", len(tt.want), len(cliffs), cliffs)
+			}
+
+			for i, cliff := range cliffs {
+				if len(cliff.Certs) != len(tt.want[i]) {
+					t.Fatalf("want %d certs in expiry cliff; got %d", len(tt.want[i]), len(cliff.Certs))
+				}
+
+				for j, cert := range cliff.Certs {
+					if cert != tt.want[i][j] {
+						t.Errorf(
+							"want cert %q at position %d; got %q",
+							tt.want[i][j].Subject.CommonName,
+							j,
+							cert.Subject.CommonName,
+						)
+					}
+				}
+
+				if !cliff.Start().Equal(tt.want[i][0].NotAfter) {
+					t.Errorf("want start %v; got %v", tt.want[i][0].NotAfter, cliff.Start())
+				}
+
+				if !cliff.End().Equal(tt.want[i][len(tt.want[i])-1].NotAfter) {
+					t.Errorf("want end %v; got %v", tt.want[i][len(tt.want[i])-1].NotAfter, cliff.End())
+				}
+			}
+		})
+	}
+}
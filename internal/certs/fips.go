@@ -0,0 +1,111 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// fipsModeDetectOnce guards detection of a FIPS-restricted build or
+	// environment.
+	fipsModeDetectOnce sync.Once
+
+	// fipsModeDetected describes the detected FIPS-restricted build or
+	// environment. An empty value indicates that none was detected.
+	fipsModeDetected string
+
+	// fipsModeForced indicates whether FIPS mode was explicitly enabled.
+	fipsModeForced atomic.Bool
+)
+
+// detectFIPSMode returns a description of the FIPS-restricted build or
+// environment the application is running in. An empty string is returned if
+// no FIPS restrictions were detected.
+func detectFIPSMode() string {
+	switch {
+	case fipsBuild:
+		return "built with FIPS validated cryptography module"
+
+	case os.Getenv("GOLANG_FIPS") == "1":
+		return "GOLANG_FIPS environment variable set"
+	}
+
+	for _, setting := range strings.Split(os.Getenv("GODEBUG"), ",") {
+		switch strings.TrimSpace(setting) {
+		case "fips140=on", "fips140=only":
+			return "GODEBUG fips140 setting enabled"
+		}
+	}
+
+	if systemFIPSMode() {
+		return "operating system FIPS mode enabled"
+	}
+
+	return ""
+}
+
+// EnableFIPSMode forces FIPS mode regardless of whether a FIPS-restricted
+// build or environment was detected. This is intended to be called once
+// during application startup.
+func EnableFIPSMode() {
+	fipsModeForced.Store(true)
+}
+
+// FIPSModeReason returns a description of why FIPS mode is active. An empty
+// string is returned if FIPS mode is not active.
+func FIPSModeReason() string {
+	fipsModeDetectOnce.Do(func() {
+		fipsModeDetected = detectFIPSMode()
+	})
+
+	switch {
+	case fipsModeDetected != "":
+		return fipsModeDetected
+	case fipsModeForced.Load():
+		return "explicitly requested"
+	default:
+		return ""
+	}
+}
+
+// FIPSMode indicates whether FIPS mode is active, either because a
+// FIPS-restricted build or environment was detected or because it was
+// explicitly enabled.
+//
+// When active, signatures using legacy algorithms (MD5 or SHA1) which are
+// no longer supported by the Go standard library are not verified. Instead,
+// issuer and subject names (and key identifiers if present) are used to
+// determine chain position and the lack of verification is noted as
+// informational output.
+func FIPSMode() bool {
+	return FIPSModeReason() != ""
+}
+
+// isLegacySignatureAlgorithm indicates whether the given signature algorithm
+// is only verified using the legacy MD5 or SHA1 signature verification
+// helpers.
+func isLegacySignatureAlgorithm(algo x509.SignatureAlgorithm) bool {
+	switch algo {
+	case x509.MD5WithRSA, x509.SHA1WithRSA, x509.ECDSAWithSHA1:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSignatureUnverified indicates whether the signature for the given
+// certificate was not verified because FIPS mode is active and the
+// certificate uses a legacy signature algorithm.
+func IsSignatureUnverified(cert *x509.Certificate) bool {
+	return FIPSMode() && isLegacySignatureAlgorithm(cert.SignatureAlgorithm)
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"bytes"
+	"os"
+)
+
+// fipsEnabledFile is the file used by the Linux kernel to indicate whether
+// the system is running in FIPS mode.
+const fipsEnabledFile string = "/proc/sys/crypto/fips_enabled"
+
+// systemFIPSMode indicates whether the operating system is running in FIPS
+// mode.
+func systemFIPSMode() bool {
+	data, err := os.ReadFile(fipsEnabledFile)
+	if err != nil {
+		return false
+	}
+
+	return string(bytes.TrimSpace(data)) == "1"
+}
@@ -0,0 +1,14 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !goexperiment.boringcrypto
+
+package certs
+
+// fipsBuild indicates whether the application was built using a FIPS
+// validated cryptography module.
+const fipsBuild bool = false
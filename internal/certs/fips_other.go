@@ -0,0 +1,16 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+//go:build !linux
+
+package certs
+
+// systemFIPSMode indicates whether the operating system is running in FIPS
+// mode. Detection is not supported on this platform.
+func systemFIPSMode() bool {
+	return false
+}
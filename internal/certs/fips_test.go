@@ -0,0 +1,341 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto"
+	"crypto/md5" //nolint:gosec // required to create legacy test certificates
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+// oidSignatureMD5WithRSA is the signature algorithm identifier for
+// MD5WithRSA signatures.
+var oidSignatureMD5WithRSA = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 4}
+
+// resetFIPSMode clears the detected and forced FIPS mode state so that
+// detection is repeated using the current environment. The state is cleared
+// again once the test completes.
+func resetFIPSMode(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		fipsModeDetectOnce = sync.Once{}
+		fipsModeDetected = ""
+		fipsModeForced.Store(false)
+	}
+
+	reset()
+	t.Cleanup(reset)
+}
+
+// skipIfFIPSEnvironment skips the test if the build or operating system
+// enables FIPS mode regardless of environment variables.
+func skipIfFIPSEnvironment(t *testing.T) {
+	t.Helper()
+
+	if fipsBuild || systemFIPSMode() {
+		t.Skip("FIPS mode enabled by build or operating system")
+	}
+}
+
+func TestDetectFIPSMode(t *testing.T) {
+	skipIfFIPSEnvironment(t)
+
+	tests := []struct {
+		name       string
+		golangFIPS string
+		godebug    string
+		want       bool
+	}{
+		{
+			name: "NotDetected",
+			want: false,
+		},
+		{
+			name:       "GolangFIPSEnvVar",
+			golangFIPS: "1",
+			want:       true,
+		},
+		{
+			name:    "GodebugFIPS140On",
+			godebug: "x509sha1=1, fips140=on",
+			want:    true,
+		},
+		{
+			name:    "GodebugFIPS140Only",
+			godebug: "fips140=only",
+			want:    true,
+		},
+		{
+			name:    "GodebugFIPS140Off",
+			godebug: "fips140=off",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOLANG_FIPS", tt.golangFIPS)
+			t.Setenv("GODEBUG", tt.godebug)
+
+			got := detectFIPSMode()
+			if (got != "") != tt.want {
+				t.Errorf("want detected: %t; got: %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnableFIPSMode(t *testing.T) {
+	skipIfFIPSEnvironment(t)
+
+	t.Setenv("GOLANG_FIPS", "")
+	t.Setenv("GODEBUG", "")
+	resetFIPSMode(t)
+
+	if FIPSMode() {
+		t.Fatalf("want: FIPS mode inactive; got reason %q", FIPSModeReason())
+	}
+
+	EnableFIPSMode()
+
+	if !FIPSMode() {
+		t.Fatal("want: FIPS mode active once enabled; got: inactive")
+	}
+
+	if got := FIPSModeReason(); got != "explicitly requested" {
+		t.Errorf("want reason %q; got %q", "explicitly requested", got)
+	}
+}
+
+// newRSATestCert creates a RSA certificate for the given common name issued
+// by the given parent or self-signed if parent is nil. The certificate key
+// and the generated certificate are returned.
+func newRSATestCert(t *testing.T, commonName string, parent *x509.Certificate, parentKey *rsa.PrivateKey) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(testSerial.Add(1)),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(90 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert, key
+}
+
+// resignMD5WithRSA returns a copy of the given certificate signed by the
+// given issuer key using the MD5WithRSA signature algorithm. Go no longer
+// creates certificates using this algorithm, so the certificate is
+// re-encoded by hand.
+func resignMD5WithRSA(t *testing.T, cert *x509.Certificate, issuerKey *rsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+
+	sigAlg := pkix.AlgorithmIdentifier{
+		Algorithm:  oidSignatureMD5WithRSA,
+		Parameters: asn1.NullRawValue,
+	}
+
+	sigAlgDER, err := asn1.Marshal(sigAlg)
+	if err != nil {
+		t.Fatalf("failed to encode signature algorithm: %v", err)
+	}
+
+	var tbs asn1.RawValue
+	if _, err := asn1.Unmarshal(cert.RawTBSCertificate, &tbs); err != nil {
+		t.Fatalf("failed to decode certificate: %v", err)
+	}
+
+	// The signature algorithm is the third field of the TBSCertificate
+	// structure, following the explicitly tagged version and serial number.
+	var fields []byte
+	rest := tbs.Bytes
+	for i := 0; len(rest) > 0; i++ {
+		var field asn1.RawValue
+		rest, err = asn1.Unmarshal(rest, &field)
+		if err != nil {
+			t.Fatalf("failed to decode certificate field: %v", err)
+		}
+
+		switch {
+		case i == 2:
+			fields = append(fields, sigAlgDER...)
+		default:
+			fields = append(fields, field.FullBytes...)
+		}
+	}
+
+	tbs = asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: fields}
+	tbsDER, err := asn1.Marshal(tbs)
+	if err != nil {
+		t.Fatalf("failed to encode certificate: %v", err)
+	}
+
+	hashed := md5.Sum(tbsDER) //nolint:gosec // required to create legacy test certificates
+	signature, err := rsa.SignPKCS1v15(rand.Reader, issuerKey, crypto.MD5, hashed[:])
+	if err != nil {
+		t.Fatalf("failed to sign certificate: %v", err)
+	}
+
+	der, err := asn1.Marshal(struct {
+		TBSCertificate     asn1.RawValue
+		SignatureAlgorithm pkix.AlgorithmIdentifier
+		SignatureValue     asn1.BitString
+	}{
+		TBSCertificate:     asn1.RawValue{FullBytes: tbsDER},
+		SignatureAlgorithm: sigAlg,
+		SignatureValue:     asn1.BitString{Bytes: signature, BitLength: len(signature) * 8},
+	})
+	if err != nil {
+		t.Fatalf("failed to encode certificate: %v", err)
+	}
+
+	legacyCert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return legacyCert
+}
+
+func TestVerifySignatureFIPSMode(t *testing.T) {
+	skipIfFIPSEnvironment(t)
+
+	t.Setenv("GOLANG_FIPS", "")
+	t.Setenv("GODEBUG", "")
+
+	root, rootKey := newRSATestCert(t, "Test Root CA", nil, nil)
+	impostorRoot, _ := newRSATestCert(t, "Test Root CA", nil, nil)
+	intermediate, _ := newRSATestCert(t, "Test Intermediate CA", root, rootKey)
+	legacyIntermediate := resignMD5WithRSA(t, intermediate, rootKey)
+
+	if legacyIntermediate.SignatureAlgorithm != x509.MD5WithRSA {
+		t.Fatalf(
+			"want signature algorithm %s; got %s",
+			x509.MD5WithRSA,
+			legacyIntermediate.SignatureAlgorithm,
+		)
+	}
+
+	tests := []struct {
+		name           string
+		fips           bool
+		issued         *x509.Certificate
+		issuer         *x509.Certificate
+		wantStatus     SignatureVerificationStatus
+		wantMethod     SignatureVerificationMethod
+		wantUnverified bool
+	}{
+		{
+			name:       "LegacyAlgorithmVerified",
+			issued:     legacyIntermediate,
+			issuer:     root,
+			wantStatus: SignatureVerified,
+			wantMethod: SignatureMethodLegacy,
+		},
+		{
+			name:       "LegacyAlgorithmWrongIssuer",
+			issued:     legacyIntermediate,
+			issuer:     impostorRoot,
+			wantStatus: SignatureVerificationFailed,
+			wantMethod: SignatureMethodLegacy,
+		},
+		{
+			name:           "LegacyAlgorithmNotVerifiedInFIPSMode",
+			fips:           true,
+			issued:         legacyIntermediate,
+			issuer:         root,
+			wantStatus:     SignatureNotVerified,
+			wantMethod:     SignatureMethodKeyIdentifiers,
+			wantUnverified: true,
+		},
+		{
+			name:           "KeyIdentifierMismatchInFIPSMode",
+			fips:           true,
+			issued:         legacyIntermediate,
+			issuer:         impostorRoot,
+			wantStatus:     SignatureVerificationFailed,
+			wantMethod:     SignatureMethodKeyIdentifiers,
+			wantUnverified: true,
+		},
+		{
+			name:       "CurrentAlgorithmVerifiedInFIPSMode",
+			fips:       true,
+			issued:     intermediate,
+			issuer:     root,
+			wantStatus: SignatureVerified,
+			wantMethod: SignatureMethodStandard,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			resetFIPSMode(t)
+			if tt.fips {
+				EnableFIPSMode()
+			}
+
+			got := verifySignature(tt.issued, tt.issuer)
+
+			if got.Status != tt.wantStatus || got.Method != tt.wantMethod {
+				t.Errorf(
+					"want: %s/%s; got: %s/%s (%v)",
+					tt.wantStatus,
+					tt.wantMethod,
+					got.Status,
+					got.Method,
+					got.Err,
+				)
+			}
+
+			if unverified := IsSignatureUnverified(tt.issued); unverified != tt.wantUnverified {
+				t.Errorf("want signature unverified: %t; got: %t", tt.wantUnverified, unverified)
+			}
+		})
+	}
+}
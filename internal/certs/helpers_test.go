@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testSerial provides unique serial numbers for generated test certificates.
+var testSerial atomic.Int64
+
+// testCert is a generated certificate and the private key used to sign
+// certificates issued by it.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newTestCert generates a certificate with the given Common Name issued by
+// the given parent or self-signed if parent is nil. CA certificates are
+// permitted to sign certificates; leaf certificates are server
+// authentication certificates with the Common Name as the sole SANs entry.
+// If provided, modify is applied to the certificate template before the
+// certificate is created.
+func newTestCert(t *testing.T, commonName string, isCA bool, parent *testCert, modify func(*x509.Certificate)) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(testSerial.Add(1)),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(90 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	switch {
+	case isCA:
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	default:
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.DNSNames = []string{commonName}
+	}
+
+	if modify != nil {
+		modify(template)
+	}
+
+	return issueTestCert(t, template, key, parent)
+}
+
+// issueTestCert creates a certificate from the given template for the given
+// key issued by the given parent or self-signed if parent is nil.
+func issueTestCert(t *testing.T, template *x509.Certificate, key *ecdsa.PrivateKey, parent *testCert) *testCert {
+	t.Helper()
+
+	issuerCert, issuerKey := template, key
+	if parent != nil {
+		issuerCert, issuerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuerCert, &key.PublicKey, issuerKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate %q: %v", template.Subject.CommonName, err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate %q: %v", template.Subject.CommonName, err)
+	}
+
+	return &testCert{cert: cert, key: key}
+}
+
+// newTestChain generates a leaf certificate issued by an intermediate
+// certificate issued by a self-signed root certificate and returns the chain
+// in the order served by a server: leaf, intermediate, root.
+func newTestChain(t *testing.T, leafName string) []*x509.Certificate {
+	t.Helper()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+	leaf := newTestCert(t, leafName, false, intermediate, nil)
+
+	return []*x509.Certificate{leaf.cert, intermediate.cert, root.cert}
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// ObservedCert records details of a certificate as observed at a specific
+// point in time. This is used to compare a certificate against the one
+// observed during a previous evaluation (e.g., to detect renewals).
+type ObservedCert struct {
+	// SerialNumber is the formatted serial number of the certificate.
+	SerialNumber string `json:"serial"`
+
+	// FingerprintSHA256 is the colon delimited SHA-256 fingerprint of the
+	// certificate.
+	FingerprintSHA256 string `json:"fingerprint_sha256"`
+
+	// SANsEntries is the list of DNS Name SANs entries for the certificate.
+	SANsEntries []string `json:"sans_entries"`
+
+	// NotAfter is the expiration time of the certificate.
+	NotAfter time.Time `json:"not_after"`
+
+	// ObservedAt is the time the certificate was observed.
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// NewObservedCert records details of the given certificate as observed at
+// the specified time.
+func NewObservedCert(cert *x509.Certificate, observedAt time.Time) ObservedCert {
+	fingerprint := sha256.Sum256(cert.Raw)
+
+	sansEntries := make([]string, len(cert.DNSNames))
+	copy(sansEntries, cert.DNSNames)
+
+	return ObservedCert{
+		SerialNumber:      FormatCertSerialNumber(cert.SerialNumber),
+		FingerprintSHA256: textutils.BytesToDelimitedHexStr(fingerprint[:], ":"),
+		SANsEntries:       sansEntries,
+		NotAfter:          cert.NotAfter.UTC(),
+		ObservedAt:        observedAt.UTC(),
+	}
+}
+
+// SameCert indicates whether the given observed certificate records the same
+// certificate as this one.
+func (oc ObservedCert) SameCert(other ObservedCert) bool {
+	return strings.EqualFold(oc.FingerprintSHA256, other.FingerprintSHA256)
+}
+
+// LoadObservedCertFile reads a previously recorded certificate observation
+// from the specified file. The returned error wraps fs.ErrNotExist if the
+// file has not yet been created.
+func LoadObservedCertFile(filename string) (ObservedCert, error) {
+	// Read the state file after first attempting to sanitize the input file
+	// variable contents.
+	data, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return ObservedCert{}, fmt.Errorf(
+			"failed to read state file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	var observed ObservedCert
+	if err := json.Unmarshal(data, &observed); err != nil {
+		return ObservedCert{}, fmt.Errorf(
+			"failed to decode state file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return observed, nil
+}
+
+// SaveObservedCertFile records the given certificate observation to the
+// specified file. The file is replaced as a whole so that an interrupted
+// write does not leave a partially written file behind.
+func SaveObservedCertFile(filename string, observed ObservedCert) error {
+	data, err := json.MarshalIndent(observed, "", "  ")
+	if err != nil {
+		return fmt.Errorf(
+			"failed to encode state for file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf(
+			"failed to create temporary file for state file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	// Remove the temporary file if it is not successfully renamed.
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+
+		return fmt.Errorf(
+			"failed to write state file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf(
+			"failed to close state file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	if err := os.Rename(tmpFile.Name(), filename); err != nil {
+		return fmt.Errorf(
+			"failed to replace state file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// SANsEntriesDelta compares the given previous and current SANs entries
+// lists and returns the (sorted) entries added to and removed from the
+// current list. Entries are compared case-insensitively.
+func SANsEntriesDelta(previous []string, current []string) ([]string, []string) {
+	toSet := func(entries []string) map[string]struct{} {
+		set := make(map[string]struct{}, len(entries))
+		for _, entry := range entries {
+			set[strings.ToLower(entry)] = struct{}{}
+		}
+
+		return set
+	}
+
+	previousSet := toSet(previous)
+	currentSet := toSet(current)
+
+	added := make([]string, 0)
+	for entry := range currentSet {
+		if _, ok := previousSet[entry]; !ok {
+			added = append(added, entry)
+		}
+	}
+
+	removed := make([]string, 0)
+	for entry := range previousSet {
+		if _, ok := currentSet[entry]; !ok {
+			removed = append(removed, entry)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
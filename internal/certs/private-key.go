@@ -0,0 +1,84 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// ErrPrivateKeyParseFailure indicates that a private key could not be
+// decoded and parsed.
+var ErrPrivateKeyParseFailure = errors.New("failed to parse private key")
+
+// GetPrivateKeyFromFile retrieves an unencrypted private key from the
+// specified PEM formatted file. PKCS #1 RSA, SEC 1 EC and PKCS #8 private
+// keys are supported. The first private key found in the file is used; any
+// certificates also present in the file are skipped.
+func GetPrivateKeyFromFile(filename string) (crypto.PrivateKey, error) {
+	keyFileData, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, err
+	}
+
+	pemData := textutils.NormalizeNewlines(keyFileData)
+
+	for {
+		var block *pem.Block
+		block, pemData = pem.Decode(pemData)
+		if block == nil {
+			return nil, fmt.Errorf(
+				"no PEM formatted private key found in %s: %w",
+				filename,
+				ErrPrivateKeyParseFailure,
+			)
+		}
+
+		// Legacy OpenSSL encryption is noted via PEM headers.
+		if _, ok := block.Headers["Proc-Type"]; ok {
+			block.Type = "ENCRYPTED PRIVATE KEY"
+		}
+
+		var key crypto.PrivateKey
+		switch block.Type {
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = x509.ParseECPrivateKey(block.Bytes)
+		case "PRIVATE KEY":
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, fmt.Errorf(
+				"encrypted private keys are not supported (%s): %w",
+				filename,
+				ErrPrivateKeyParseFailure,
+			)
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: %s block in %s: %w",
+				ErrPrivateKeyParseFailure,
+				block.Type,
+				filename,
+				err,
+			)
+		}
+
+		return key, nil
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SignatureVerificationStatus is the outcome of verifying that a certificate
+// was issued by another certificate.
+type SignatureVerificationStatus string
+
+// Known signature verification outcomes.
+const (
+	// SignatureVerified indicates that the signature was verified using the
+	// issuer certificate public key.
+	SignatureVerified SignatureVerificationStatus = "verified"
+
+	// SignatureNotVerified indicates that the signature uses a legacy
+	// algorithm which is not verified in FIPS mode. The issuer was instead
+	// matched using names and key identifiers.
+	SignatureNotVerified SignatureVerificationStatus = "not_verified"
+
+	// SignatureVerificationFailed indicates that the certificate was not
+	// issued by the given issuer certificate (or that the signature could
+	// not be verified).
+	SignatureVerificationFailed SignatureVerificationStatus = "failed"
+
+	// SignatureIssuerNotPresent indicates that the issuer certificate was
+	// not found in the certificate chain.
+	SignatureIssuerNotPresent SignatureVerificationStatus = "issuer_not_present"
+)
+
+// SignatureVerificationMethod is the approach used to verify that a
+// certificate was issued by another certificate.
+type SignatureVerificationMethod string
+
+// Known signature verification methods.
+const (
+	// SignatureMethodNone indicates that no verification was attempted.
+	SignatureMethodNone SignatureVerificationMethod = "none"
+
+	// SignatureMethodStandard indicates that the Go standard library was
+	// used to verify the signature.
+	SignatureMethodStandard SignatureVerificationMethod = "standard"
+
+	// SignatureMethodLegacy indicates that the signature uses a legacy MD5
+	// or SHA1 algorithm rejected by the Go standard library and was
+	// verified using fallback logic.
+	SignatureMethodLegacy SignatureVerificationMethod = "legacy"
+
+	// SignatureMethodKeyIdentifiers indicates that issuer and subject names
+	// and key identifiers were matched in place of signature verification.
+	SignatureMethodKeyIdentifiers SignatureVerificationMethod = "key_identifiers"
+)
+
+// SignatureVerification is the result of verifying that a certificate was
+// issued by another certificate.
+type SignatureVerification struct {
+	// Status is the outcome of the verification attempt.
+	Status SignatureVerificationStatus
+
+	// Method is the approach used to verify the signature.
+	Method SignatureVerificationMethod
+
+	// Algorithm is the signature algorithm used by the issued certificate.
+	Algorithm x509.SignatureAlgorithm
+
+	// Err records why verification failed. This is nil unless Status is
+	// SignatureVerificationFailed or SignatureIssuerNotPresent.
+	Err error
+}
+
+// Matched indicates whether the issuer certificate was determined to have
+// issued the certificate, either by verifying the signature or (in FIPS
+// mode) by matching names and key identifiers.
+func (sv SignatureVerification) Matched() bool {
+	return sv.Status == SignatureVerified || sv.Status == SignatureNotVerified
+}
+
+// signatureVerificationJSON is the encoded form of a SignatureVerification.
+type signatureVerificationJSON struct {
+	Status    SignatureVerificationStatus `json:"status"`
+	Method    SignatureVerificationMethod `json:"method"`
+	Algorithm string                      `json:"algorithm"`
+	Error     string                      `json:"error"`
+}
+
+// MarshalJSON implements the json.Marshaler interface so that the signature
+// algorithm and verification error are encoded as strings.
+func (sv SignatureVerification) MarshalJSON() ([]byte, error) {
+	var errMsg string
+	if sv.Err != nil {
+		errMsg = sv.Err.Error()
+	}
+
+	return json.Marshal(signatureVerificationJSON{
+		Status:    sv.Status,
+		Method:    sv.Method,
+		Algorithm: sv.Algorithm.String(),
+		Error:     errMsg,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Unrecognized
+// signature algorithm names are decoded as x509.UnknownSignatureAlgorithm.
+func (sv *SignatureVerification) UnmarshalJSON(data []byte) error {
+	var decoded signatureVerificationJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*sv = SignatureVerification{
+		Status:    decoded.Status,
+		Method:    decoded.Method,
+		Algorithm: x509.UnknownSignatureAlgorithm,
+	}
+
+	for algo := x509.MD2WithRSA; algo <= x509.PureEd25519; algo++ {
+		if algo.String() == decoded.Algorithm {
+			sv.Algorithm = algo
+
+			break
+		}
+	}
+
+	if decoded.Error != "" {
+		sv.Err = errors.New(decoded.Error)
+	}
+
+	return nil
+}
+
+// IssuerSignatureVerification verifies that the given certificate was issued
+// by a certificate from the given certificate chain (or by itself if
+// self-signed) and returns the outcome.
+func IssuerSignatureVerification(cert *x509.Certificate, certChain []*x509.Certificate) SignatureVerification {
+	if isSelfSigned(cert) {
+		return verifySignature(cert, cert)
+	}
+
+	result := SignatureVerification{
+		Status:    SignatureIssuerNotPresent,
+		Method:    SignatureMethodNone,
+		Algorithm: cert.SignatureAlgorithm,
+		Err: fmt.Errorf(
+			"issuer %q not found in certificate chain: %w",
+			cert.Issuer.String(),
+			ErrSignatureVerificationFailed,
+		),
+	}
+
+	for _, candidate := range certChain {
+		if candidate == cert || candidate.Subject.String() != cert.Issuer.String() {
+			continue
+		}
+
+		result = verifySignature(cert, candidate)
+		if result.Matched() {
+			return result
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/dsa" // nolint:staticcheck
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrSystemTrustStoreNotFound indicates that a certificate bundle for the
+// system trust store could not be located.
+var ErrSystemTrustStoreNotFound = errors.New("system trust store certificate bundle not found")
+
+// Minimum public key sizes (in bits) below which a certificate key is
+// considered weak.
+const (
+	minRSAKeySize   int = 2048
+	minECDSAKeySize int = 256
+)
+
+// systemTrustStoreEnvVar is the environment variable used (as with OpenSSL
+// and the Go standard library) to override the location of the system trust
+// store certificate bundle.
+const systemTrustStoreEnvVar string = "SSL_CERT_FILE"
+
+// systemTrustStoreFiles is the list of well-known locations for the system
+// trust store certificate bundle. The first file found is used.
+var systemTrustStoreFiles = []string{
+	"/etc/ssl/certs/ca-certificates.crt",                // Debian/Ubuntu/Gentoo etc.
+	"/etc/pki/tls/certs/ca-bundle.crt",                  // Fedora/RHEL 6
+	"/etc/ssl/ca-bundle.pem",                            // OpenSUSE
+	"/etc/pki/tls/cacert.pem",                           // OpenELEC
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem", // CentOS/RHEL 7
+	"/etc/ssl/cert.pem",                                 // Alpine Linux, macOS, FreeBSD
+}
+
+// SystemTrustStoreFile returns the path to the certificate bundle for the
+// system trust store. The location specified by the SSL_CERT_FILE
+// environment variable is used if set, otherwise the first bundle found in
+// a list of well-known locations is used. An error is returned if a bundle
+// could not be located.
+func SystemTrustStoreFile() (string, error) {
+	if envFile := os.Getenv(systemTrustStoreEnvVar); envFile != "" {
+		return envFile, nil
+	}
+
+	for _, file := range systemTrustStoreFiles {
+		if fi, err := os.Stat(file); err == nil && !fi.IsDir() {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"checked %s environment variable and %d known locations: %w",
+		systemTrustStoreEnvVar,
+		len(systemTrustStoreFiles),
+		ErrSystemTrustStoreNotFound,
+	)
+}
+
+// HasWeakKey indicates whether the public key for the given certificate is
+// known to be cryptographically weak; RSA keys smaller than 2048 bits, ECDSA
+// keys smaller than 256 bits or DSA keys.
+func HasWeakKey(cert *x509.Certificate) bool {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return pub.N.BitLen() < minRSAKeySize
+
+	case *ecdsa.PublicKey:
+		return pub.Curve.Params().BitSize < minECDSAKeySize
+
+	case *dsa.PublicKey:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// KeyDescription returns a brief human-readable description of the public
+// key algorithm and key size (where known) for the given certificate.
+func KeyDescription(cert *x509.Certificate) string {
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("%s %d bits", cert.PublicKeyAlgorithm, pub.N.BitLen())
+
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("%s %d bits", cert.PublicKeyAlgorithm, pub.Curve.Params().BitSize)
+
+	default:
+		return cert.PublicKeyAlgorithm.String()
+	}
+}
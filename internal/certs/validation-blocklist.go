@@ -0,0 +1,326 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*BlocklistValidationResult)(nil)
+
+// BlocklistValidationResult is the validation result from evaluating the
+// leaf certificate in a chain against a blocklist of known-compromised
+// certificate fingerprints and serial numbers.
+type BlocklistValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// leafCert is the leaf certificate from the chain that we evaluated to
+	// produce this validation check result.
+	leafCert *x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// blocklistEntries is the number of entries in the evaluated blocklist.
+	blocklistEntries int
+
+	// matchType is the type of blocklist entry (fingerprint or serial) that
+	// matched the leaf certificate, if any.
+	matchType string
+}
+
+// ValidateBlocklist asserts that the leaf certificate for a given certificate
+// chain does not match an entry in the given blocklist of known-compromised
+// certificate fingerprints and serial numbers. If specified, this validation
+// check result is ignored.
+func ValidateBlocklist(
+	certChain []*x509.Certificate,
+	blocklist CertBlocklist,
+	validationOptions CertChainValidationOptions,
+) BlocklistValidationResult {
+
+	leafCerts := LeafCerts(certChain)
+
+	// Early exit logic.
+	switch {
+	case len(leafCerts) == 0:
+		return BlocklistValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			blocklistEntries:  blocklist.Len(),
+			err: fmt.Errorf(
+				"leaf certificate not found in certificate chain: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultBlocklist,
+			priorityModifier: priorityModifierMaximum,
+		}
+
+	// If no blocklist entries were provided we are unable to perform
+	// validation.
+	//
+	// NOTE: Config validation is expected to prevent explicitly applying
+	// this validation check without a blocklist file.
+	case blocklist.Len() == 0:
+		return BlocklistValidationResult{
+			certChain:         certChain,
+			leafCert:          leafCerts[0],
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"blocklist entries not provided: %w",
+				ErrMissingValue,
+			),
+			ignored:          validationOptions.IgnoreValidationResultBlocklist,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	leafCert := leafCerts[0]
+
+	result := BlocklistValidationResult{
+		certChain:         certChain,
+		leafCert:          leafCert,
+		validationOptions: validationOptions,
+		blocklistEntries:  blocklist.Len(),
+		ignored:           validationOptions.IgnoreValidationResultBlocklist,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if matchType, ok := blocklist.Match(leafCert); ok {
+		result.matchType = matchType
+		result.err = ErrCertBlocklisted
+		result.priorityModifier = priorityModifierMaximum
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (blvr BlocklistValidationResult) CheckName() string {
+	return checkNameBlocklistValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (blvr BlocklistValidationResult) CertChain() []*x509.Certificate {
+	return blvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (blvr BlocklistValidationResult) TotalCerts() int {
+	return len(blvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (blvr BlocklistValidationResult) IsWarningState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (blvr BlocklistValidationResult) IsCriticalState() bool {
+	return blvr.err != nil && !blvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (blvr BlocklistValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (blvr BlocklistValidationResult) IsOKState() bool {
+	return blvr.err == nil || blvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (blvr BlocklistValidationResult) IsIgnored() bool {
+	return blvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (blvr BlocklistValidationResult) IsSucceeded() bool {
+	return blvr.IsOKState() && !blvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (blvr BlocklistValidationResult) IsFailed() bool {
+	return blvr.err != nil && !blvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (blvr BlocklistValidationResult) Err() error {
+	return blvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (blvr BlocklistValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(blvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (blvr BlocklistValidationResult) Priority() int {
+	switch {
+	case blvr.ignored:
+		return baselinePriorityBlocklistValidationResult
+	default:
+		return baselinePriorityBlocklistValidationResult + blvr.priorityModifier
+	}
+}
+
+// NumEntries returns the number of entries in the evaluated blocklist.
+func (blvr BlocklistValidationResult) NumEntries() int {
+	return blvr.blocklistEntries
+}
+
+// MatchType returns the type of blocklist entry (fingerprint or serial) that
+// matched the evaluated leaf certificate. An empty string is returned if the
+// leaf certificate did not match a blocklist entry.
+func (blvr BlocklistValidationResult) MatchType() string {
+	return blvr.matchType
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (blvr BlocklistValidationResult) Overview() string {
+	matched := "NONE"
+	if blvr.matchType != "" {
+		matched = strings.ToUpper(blvr.matchType)
+	}
+
+	return fmt.Sprintf(
+		"[ENTRIES: %d, MATCHED: %s]",
+		blvr.blocklistEntries,
+		matched,
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (blvr BlocklistValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case blvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			blvr.CheckName(),
+		)
+
+	case errors.Is(blvr.err, ErrCertBlocklisted):
+		status = fmt.Sprintf(
+			"%s validation failed: %s cert %q %s by %s",
+			blvr.CheckName(),
+			ChainPosition(blvr.leafCert, blvr.certChain),
+			blvr.leafCert.Subject.CommonName,
+			blvr.Err(),
+			blvr.matchType,
+		)
+
+	case blvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered checking leaf certificate against blocklist: %v",
+			blvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: %s cert not found in blocklist",
+			blvr.CheckName(),
+			ChainPosition(blvr.leafCert, blvr.certChain),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (blvr BlocklistValidationResult) StatusDetail() string {
+	// The overview already provides the blocklist size and match type; there
+	// is nothing further to add.
+	return ""
+}
+
+// String provides the validation check result in human-readable format.
+func (blvr BlocklistValidationResult) String() string {
+	return fmt.Sprintf(
+		"%s %s",
+		blvr.Status(),
+		blvr.Overview(),
+	)
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (blvr BlocklistValidationResult) Report() string {
+	return blvr.String()
+}
+
+// ValidationStatus provides a one word status value for blocklist
+// validation check results.
+func (blvr BlocklistValidationResult) ValidationStatus() string {
+	switch {
+	case blvr.IsFailed():
+		return ValidationStatusFailed
+	case blvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*WeakCipherSuitesValidationResult)(nil)
+
+// WeakCipherSuitesValidationResult is the validation result from probing the
+// server providing a certificate chain for acceptance of known-weak cipher
+// suites (e.g., RC4, 3DES, export or CBC cipher suites with TLS 1.0).
+type WeakCipherSuitesValidationResult struct {
+	// certChain is the collection of certificates retrieved from the server
+	// that we probed to produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// weakSuites is the collection of known-weak cipher suites accepted by
+	// the server.
+	weakSuites []netutils.WeakCipherSuite
+}
+
+// ValidateWeakCipherSuites asserts that the server providing the given
+// certificate chain did not accept any of the given known-weak cipher
+// suites. The given probe error, if any, is recorded as the reason that
+// validation could not be performed. If specified, this validation check
+// result is ignored.
+func ValidateWeakCipherSuites(
+	certChain []*x509.Certificate,
+	acceptedSuites []netutils.WeakCipherSuite,
+	probeErr error,
+	validationOptions CertChainValidationOptions,
+) WeakCipherSuitesValidationResult {
+
+	result := WeakCipherSuitesValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		weakSuites:        acceptedSuites,
+		ignored:           validationOptions.IgnoreValidationResultWeakCipherSuites,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	switch {
+	case probeErr != nil:
+		result.err = fmt.Errorf("%w: %w", ErrCipherSuiteProbeFailed, probeErr)
+
+		// An unreachable server says nothing about the accepted cipher
+		// suites, so this is not given precedence over other validation
+		// check results.
+		result.priorityModifier = priorityModifierMinimum
+
+	case len(acceptedSuites) > 0:
+		result.err = ErrWeakCipherSuitesAccepted
+		result.priorityModifier = priorityModifierMedium
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (wcsvr WeakCipherSuitesValidationResult) CheckName() string {
+	return checkNameWeakCipherSuitesValidationResult
+}
+
+// CertChain returns the certificate chain retrieved from the probed server.
+func (wcsvr WeakCipherSuitesValidationResult) CertChain() []*x509.Certificate {
+	return wcsvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the certificate chain
+// retrieved from the probed server.
+func (wcsvr WeakCipherSuitesValidationResult) TotalCerts() int {
+	return len(wcsvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or UNKNOWN state, or is flagged as ignored. True is returned otherwise.
+func (wcsvr WeakCipherSuitesValidationResult) IsWarningState() bool {
+	return errors.Is(wcsvr.err, ErrWeakCipherSuitesAccepted) && !wcsvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (wcsvr WeakCipherSuitesValidationResult) IsCriticalState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state. This is the case if the server could not be probed.
+func (wcsvr WeakCipherSuitesValidationResult) IsUnknownState() bool {
+	return errors.Is(wcsvr.err, ErrCipherSuiteProbeFailed) && !wcsvr.IsIgnored()
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (wcsvr WeakCipherSuitesValidationResult) IsOKState() bool {
+	return wcsvr.err == nil || wcsvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (wcsvr WeakCipherSuitesValidationResult) IsIgnored() bool {
+	return wcsvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the server were identified.
+func (wcsvr WeakCipherSuitesValidationResult) IsSucceeded() bool {
+	return wcsvr.IsOKState() && !wcsvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (wcsvr WeakCipherSuitesValidationResult) IsFailed() bool {
+	return wcsvr.err != nil && !wcsvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (wcsvr WeakCipherSuitesValidationResult) Err() error {
+	return wcsvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (wcsvr WeakCipherSuitesValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(wcsvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (wcsvr WeakCipherSuitesValidationResult) Priority() int {
+	switch {
+	case wcsvr.ignored:
+		return baselinePriorityWeakCipherSuitesValidationResult
+	default:
+		return baselinePriorityWeakCipherSuitesValidationResult + wcsvr.priorityModifier
+	}
+}
+
+// NumWeakSuites returns the number of known-weak cipher suites accepted by
+// the server.
+func (wcsvr WeakCipherSuitesValidationResult) NumWeakSuites() int {
+	return len(wcsvr.weakSuites)
+}
+
+// WeakSuites returns the names of the known-weak cipher suites accepted by
+// the server along with the reason each is considered weak.
+func (wcsvr WeakCipherSuitesValidationResult) WeakSuites() []string {
+	names := make([]string, 0, len(wcsvr.weakSuites))
+	for _, suite := range wcsvr.weakSuites {
+		names = append(names, suite.String())
+	}
+
+	return names
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (wcsvr WeakCipherSuitesValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[WEAK SUITES ACCEPTED: %d]",
+		len(wcsvr.weakSuites),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (wcsvr WeakCipherSuitesValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case wcsvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			wcsvr.CheckName(),
+		)
+
+	case errors.Is(wcsvr.err, ErrWeakCipherSuitesAccepted):
+		status = fmt.Sprintf(
+			"%s validation failed: server accepted %d weak cipher suites",
+			wcsvr.CheckName(),
+			len(wcsvr.weakSuites),
+		)
+
+	case wcsvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered probing server for weak cipher suites: %v",
+			wcsvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: no weak cipher suites accepted",
+			wcsvr.CheckName(),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (wcsvr WeakCipherSuitesValidationResult) StatusDetail() string {
+	return strings.Join(wcsvr.WeakSuites(), "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (wcsvr WeakCipherSuitesValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		wcsvr.Status(),
+		wcsvr.Overview(),
+	)
+
+	if wcsvr.StatusDetail() != "" {
+		output += ": " + wcsvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (wcsvr WeakCipherSuitesValidationResult) Report() string {
+	return wcsvr.String()
+}
+
+// ValidationStatus provides a one word status value for weak cipher suites
+// validation check results.
+func (wcsvr WeakCipherSuitesValidationResult) ValidationStatus() string {
+	switch {
+	case wcsvr.IsFailed():
+		return ValidationStatusFailed
+	case wcsvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
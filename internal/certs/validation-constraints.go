@@ -0,0 +1,405 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*ChainConstraintsValidationResult)(nil)
+
+// ChainConstraintsValidationResult is the validation result from evaluating
+// the Key Usage and Basic Constraints extensions of each certificate in a
+// chain for consistency with the position of the certificate in the chain.
+type ChainConstraintsValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// violations is the collection of human-readable descriptions for each
+	// identified constraint violation.
+	violations []string
+}
+
+// ValidateChainConstraints asserts that the Key Usage and Basic Constraints
+// extensions for each certificate in the given certificate chain are
+// consistent with its position in the chain. If specified, this validation
+// check result is ignored.
+//
+// The first certificate in the chain is evaluated as the leaf certificate
+// and is not permitted to be a CA certificate. Each certificate after the
+// first is evaluated as an issuer of the certificate before it and is
+// required to be a CA certificate permitted to sign certificates. Path
+// length constraints are evaluated for each issuer against the number of
+// intermediate certificates that follow it in the direction of the leaf and
+// against the path length constraint of its own issuer. A path length
+// constraint on a non-CA certificate is also flagged.
+func ValidateChainConstraints(
+	certChain []*x509.Certificate,
+	validationOptions CertChainValidationOptions,
+) ChainConstraintsValidationResult {
+
+	if len(certChain) == 0 {
+		return ChainConstraintsValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"required certificate chain is empty: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultChainConstraints,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	var violations []string
+
+	leafCert := certChain[0]
+	if leafCert.BasicConstraintsValid && leafCert.IsCA {
+		violations = append(violations, fmt.Sprintf(
+			"leaf cert %q has Basic Constraints CA:TRUE",
+			leafCert.Subject.CommonName,
+		))
+	}
+
+	// RFC 5280 does not permit a path length constraint unless the
+	// certificate is a CA certificate. Some strict validators reject the
+	// certificate outright.
+	if leafCert.BasicConstraintsValid && !leafCert.IsCA && hasPathLenConstraint(leafCert) {
+		violations = append(violations, fmt.Sprintf(
+			"leaf cert %q has Basic Constraints pathlen:%d without CA:TRUE",
+			leafCert.Subject.CommonName,
+			leafCert.MaxPathLen,
+		))
+	}
+
+	for i := 1; i < len(certChain); i++ {
+		issuer := certChain[i]
+
+		// Self-signed roots are trust anchors. Clients evaluate them based on
+		// their trust store entry and not the certificate extensions, so we
+		// skip them to avoid flagging legacy roots.
+		if i == len(certChain)-1 && isSelfSigned(issuer) {
+			continue
+		}
+
+		switch {
+		case !issuer.BasicConstraintsValid:
+			violations = append(violations, fmt.Sprintf(
+				"issuer cert %q (position %d) is missing Basic Constraints extension",
+				issuer.Subject.CommonName,
+				i+1,
+			))
+
+			continue
+
+		case !issuer.IsCA:
+			violations = append(violations, fmt.Sprintf(
+				"issuer cert %q (position %d) has Basic Constraints CA:FALSE",
+				issuer.Subject.CommonName,
+				i+1,
+			))
+
+			continue
+		}
+
+		// An absent Key Usage extension places no restrictions on the key.
+		if issuer.KeyUsage != 0 && issuer.KeyUsage&x509.KeyUsageCertSign == 0 {
+			violations = append(violations, fmt.Sprintf(
+				"issuer cert %q (position %d) is missing keyCertSign Key Usage",
+				issuer.Subject.CommonName,
+				i+1,
+			))
+		}
+
+		if !hasPathLenConstraint(issuer) {
+			continue
+		}
+
+		// The number of intermediate certificates between this issuer and
+		// the leaf certificate.
+		intermediatesBelow := i - 1
+		if intermediatesBelow > issuer.MaxPathLen {
+			violations = append(violations, fmt.Sprintf(
+				"issuer cert %q (position %d) has pathlen:%d but is followed by %d intermediate certs",
+				issuer.Subject.CommonName,
+				i+1,
+				issuer.MaxPathLen,
+				intermediatesBelow,
+			))
+		}
+
+		// An intermediate with a path length constraint equal to or greater
+		// than that of its own issuer can never make use of it. Each CA
+		// certificate in the path consumes one level of the constraint set
+		// above it. As with the earlier checks, a self-signed root is
+		// evaluated based on its trust store entry and is skipped.
+		if i+1 >= len(certChain) {
+			continue
+		}
+
+		parent := certChain[i+1]
+		if i+1 == len(certChain)-1 && isSelfSigned(parent) {
+			continue
+		}
+
+		if parent.BasicConstraintsValid && parent.IsCA &&
+			hasPathLenConstraint(parent) && issuer.MaxPathLen >= parent.MaxPathLen {
+			violations = append(violations, fmt.Sprintf(
+				"issuer cert %q (position %d) has pathlen:%d which is not less than pathlen:%d of its issuer %q (position %d)",
+				issuer.Subject.CommonName,
+				i+1,
+				issuer.MaxPathLen,
+				parent.MaxPathLen,
+				parent.Subject.CommonName,
+				i+2,
+			))
+		}
+	}
+
+	result := ChainConstraintsValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		violations:        violations,
+		ignored:           validationOptions.IgnoreValidationResultChainConstraints,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if len(violations) > 0 {
+		result.err = ErrCertChainConstraintsViolation
+		result.priorityModifier = priorityModifierMaximum
+	}
+
+	return result
+}
+
+// hasPathLenConstraint indicates whether the given certificate specifies a
+// Basic Constraints path length constraint. A MaxPathLen of -1 indicates an
+// unset value; a value of 0 is only meaningful if MaxPathLenZero is set.
+func hasPathLenConstraint(cert *x509.Certificate) bool {
+	return cert.MaxPathLen > 0 || (cert.MaxPathLen == 0 && cert.MaxPathLenZero)
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (ccvr ChainConstraintsValidationResult) CheckName() string {
+	return checkNameChainConstraintsValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (ccvr ChainConstraintsValidationResult) CertChain() []*x509.Certificate {
+	return ccvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (ccvr ChainConstraintsValidationResult) TotalCerts() int {
+	return len(ccvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (ccvr ChainConstraintsValidationResult) IsWarningState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (ccvr ChainConstraintsValidationResult) IsCriticalState() bool {
+	return ccvr.err != nil && !ccvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (ccvr ChainConstraintsValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (ccvr ChainConstraintsValidationResult) IsOKState() bool {
+	return ccvr.err == nil || ccvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (ccvr ChainConstraintsValidationResult) IsIgnored() bool {
+	return ccvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (ccvr ChainConstraintsValidationResult) IsSucceeded() bool {
+	return ccvr.IsOKState() && !ccvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (ccvr ChainConstraintsValidationResult) IsFailed() bool {
+	return ccvr.err != nil && !ccvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (ccvr ChainConstraintsValidationResult) Err() error {
+	return ccvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (ccvr ChainConstraintsValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(ccvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (ccvr ChainConstraintsValidationResult) Priority() int {
+	switch {
+	case ccvr.ignored:
+		return baselinePriorityChainConstraintsValidationResult
+	default:
+		return baselinePriorityChainConstraintsValidationResult + ccvr.priorityModifier
+	}
+}
+
+// NumViolations returns the number of constraint violations identified for
+// the evaluated certificate chain.
+func (ccvr ChainConstraintsValidationResult) NumViolations() int {
+	return len(ccvr.violations)
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (ccvr ChainConstraintsValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[%d CERTS, %d VIOLATIONS]",
+		len(ccvr.certChain),
+		len(ccvr.violations),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (ccvr ChainConstraintsValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case ccvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			ccvr.CheckName(),
+		)
+
+	case errors.Is(ccvr.err, ErrCertChainConstraintsViolation):
+		status = fmt.Sprintf(
+			"%s validation failed: %s",
+			ccvr.CheckName(),
+			ccvr.Err(),
+		)
+
+	case ccvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered validating certificate chain constraints: %v",
+			ccvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: Key Usage and Basic Constraints consistent with chain",
+			ccvr.CheckName(),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (ccvr ChainConstraintsValidationResult) StatusDetail() string {
+	if len(ccvr.violations) == 0 {
+		return ""
+	}
+
+	return strings.Join(ccvr.violations, "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (ccvr ChainConstraintsValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		ccvr.Status(),
+		ccvr.Overview(),
+	)
+
+	if ccvr.StatusDetail() != "" {
+		output += ": " + ccvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (ccvr ChainConstraintsValidationResult) Report() string {
+	return ccvr.String()
+}
+
+// ValidationStatus provides a one word status value for chain constraints
+// validation check results.
+func (ccvr ChainConstraintsValidationResult) ValidationStatus() string {
+	switch {
+	case ccvr.IsFailed():
+		return ValidationStatusFailed
+	case ccvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+func TestValidateChainConstraints(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+
+	tests := []struct {
+		name      string
+		certChain func(t *testing.T) []*x509.Certificate
+		err       error
+	}{
+		{
+			name: "ValidChain",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return newTestChain(t, "www.example.com")
+			},
+		},
+		{
+			name: "LeafIsCA",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				leaf := newTestCert(t, "www.example.com", false, intermediate, func(c *x509.Certificate) {
+					c.IsCA = true
+				})
+
+				return []*x509.Certificate{leaf.cert, intermediate.cert, root.cert}
+			},
+			err: ErrCertChainConstraintsViolation,
+		},
+		{
+			name: "IssuerNotCA",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				notCA := newTestCert(t, "Not A CA", false, root, func(c *x509.Certificate) {
+					c.KeyUsage |= x509.KeyUsageCertSign
+				})
+				leaf := newTestCert(t, "www.example.com", false, notCA, nil)
+
+				return []*x509.Certificate{leaf.cert, notCA.cert, root.cert}
+			},
+			err: ErrCertChainConstraintsViolation,
+		},
+		{
+			name: "IssuerMissingCertSignKeyUsage",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				issuer := newTestCert(t, "Test Intermediate CA", true, root, func(c *x509.Certificate) {
+					c.KeyUsage = x509.KeyUsageDigitalSignature
+				})
+				leaf := newTestCert(t, "www.example.com", false, issuer, nil)
+
+				return []*x509.Certificate{leaf.cert, issuer.cert, root.cert}
+			},
+			err: ErrCertChainConstraintsViolation,
+		},
+		{
+			name: "PathLenExceeded",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				upper := newTestCert(t, "Test Upper Intermediate CA", true, root, func(c *x509.Certificate) {
+					c.MaxPathLen = 0
+					c.MaxPathLenZero = true
+				})
+				lower := newTestCert(t, "Test Lower Intermediate CA", true, upper, nil)
+				leaf := newTestCert(t, "www.example.com", false, lower, nil)
+
+				return []*x509.Certificate{leaf.cert, lower.cert, upper.cert, root.cert}
+			},
+			err: ErrCertChainConstraintsViolation,
+		},
+		{
+			name:      "EmptyChain",
+			certChain: func(t *testing.T) []*x509.Certificate { return nil },
+			err:       ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateChainConstraints(tt.certChain(t), CertChainValidationOptions{})
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got, want := result.IsOKState(), tt.err == nil; got != want {
+				t.Errorf("want OK state %t; got %t", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*CTLogsValidationResult)(nil)
+
+// CTLogsValidationResult is the validation result from comparing the leaf
+// certificate for a certificate chain against certificates recorded in
+// Certificate Transparency logs for the same domain.
+type CTLogsValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// leafCert is the leaf certificate from the chain that we evaluated.
+	leafCert *x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// domain is the name used to search Certificate Transparency logs.
+	domain string
+
+	// ctLogEntries is the number of unexpired certificates recorded in
+	// Certificate Transparency logs for the domain.
+	ctLogEntries int
+
+	// servedCertLogged indicates whether the leaf certificate was found in
+	// the Certificate Transparency log search results.
+	servedCertLogged bool
+
+	// newerCerts is the collection of logged certificates issued after the
+	// leaf certificate.
+	newerCerts []CTLogEntry
+}
+
+// ValidateCTLogs asserts that no currently valid certificate recorded in
+// Certificate Transparency logs for the given domain was issued after the
+// leaf certificate for a given certificate chain. A newer logged certificate
+// indicates that a renewed certificate was issued but not installed (e.g., a
+// stale deployment). If specified, this validation check result is ignored.
+//
+// Certificates are compared by serial number; precertificates recorded in CT
+// logs share the serial number of the final certificate but not the
+// fingerprint. The given lookup error, if any, is recorded as the reason that
+// validation could not be performed.
+func ValidateCTLogs(
+	certChain []*x509.Certificate,
+	domain string,
+	ctLogEntries []CTLogEntry,
+	lookupErr error,
+	validationOptions CertChainValidationOptions,
+) CTLogsValidationResult {
+
+	leafCerts := LeafCerts(certChain)
+
+	// Early exit logic.
+	switch {
+	case len(leafCerts) == 0:
+		return CTLogsValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			domain:            domain,
+			err: fmt.Errorf(
+				"leaf certificate not found in certificate chain: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultCTLogs,
+			priorityModifier: priorityModifierMaximum,
+		}
+
+	case lookupErr != nil:
+		return CTLogsValidationResult{
+			certChain:         certChain,
+			leafCert:          leafCerts[0],
+			validationOptions: validationOptions,
+			domain:            domain,
+			err:               fmt.Errorf("%w: %w", ErrCTLogLookupFailed, lookupErr),
+			ignored:           validationOptions.IgnoreValidationResultCTLogs,
+
+			// An unavailable external service says nothing about the
+			// certificate chain, so this is not given precedence over
+			// other validation check results.
+			priorityModifier: priorityModifierMinimum,
+		}
+	}
+
+	leafCert := leafCerts[0]
+	servedSerial := FormatCertSerialNumber(leafCert.SerialNumber)
+
+	result := CTLogsValidationResult{
+		certChain:         certChain,
+		leafCert:          leafCert,
+		validationOptions: validationOptions,
+		domain:            domain,
+		ctLogEntries:      len(ctLogEntries),
+		ignored:           validationOptions.IgnoreValidationResultCTLogs,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	now := time.Now()
+
+	for _, entry := range ctLogEntries {
+		switch {
+		case strings.EqualFold(entry.SerialNumber, servedSerial):
+			result.servedCertLogged = true
+
+		// Certificates not yet valid cannot be installed and expired
+		// certificates are of no interest.
+		case entry.NotBefore.After(now) || entry.NotAfter.Before(now):
+			continue
+
+		case entry.NotBefore.After(leafCert.NotBefore):
+			result.newerCerts = append(result.newerCerts, entry)
+		}
+	}
+
+	if len(result.newerCerts) > 0 {
+		result.err = ErrNewerCertInCTLogs
+		result.priorityModifier = priorityModifierMedium
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (ctvr CTLogsValidationResult) CheckName() string {
+	return checkNameCTLogsValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (ctvr CTLogsValidationResult) CertChain() []*x509.Certificate {
+	return ctvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (ctvr CTLogsValidationResult) TotalCerts() int {
+	return len(ctvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (ctvr CTLogsValidationResult) IsWarningState() bool {
+	return errors.Is(ctvr.err, ErrNewerCertInCTLogs) && !ctvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (ctvr CTLogsValidationResult) IsCriticalState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state. This is the case if the Certificate Transparency log search
+// could not be performed.
+func (ctvr CTLogsValidationResult) IsUnknownState() bool {
+	return ctvr.err != nil &&
+		!errors.Is(ctvr.err, ErrNewerCertInCTLogs) &&
+		!ctvr.IsIgnored()
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (ctvr CTLogsValidationResult) IsOKState() bool {
+	return ctvr.err == nil || ctvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (ctvr CTLogsValidationResult) IsIgnored() bool {
+	return ctvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (ctvr CTLogsValidationResult) IsSucceeded() bool {
+	return ctvr.IsOKState() && !ctvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (ctvr CTLogsValidationResult) IsFailed() bool {
+	return ctvr.err != nil && !ctvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (ctvr CTLogsValidationResult) Err() error {
+	return ctvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (ctvr CTLogsValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(ctvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (ctvr CTLogsValidationResult) Priority() int {
+	switch {
+	case ctvr.ignored:
+		return baselinePriorityCTLogsValidationResult
+	default:
+		return baselinePriorityCTLogsValidationResult + ctvr.priorityModifier
+	}
+}
+
+// NumEntries returns the number of unexpired certificates recorded in
+// Certificate Transparency logs for the evaluated domain.
+func (ctvr CTLogsValidationResult) NumEntries() int {
+	return ctvr.ctLogEntries
+}
+
+// NumNewerCerts returns the number of logged certificates issued after the
+// evaluated leaf certificate.
+func (ctvr CTLogsValidationResult) NumNewerCerts() int {
+	return len(ctvr.newerCerts)
+}
+
+// ServedCertLogged indicates whether the evaluated leaf certificate was
+// found in the Certificate Transparency log search results.
+func (ctvr CTLogsValidationResult) ServedCertLogged() bool {
+	return ctvr.servedCertLogged
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (ctvr CTLogsValidationResult) Overview() string {
+	logged := "NO"
+	if ctvr.servedCertLogged {
+		logged = "YES"
+	}
+
+	return fmt.Sprintf(
+		"[LOGGED: %d, SERVED CERT LOGGED: %s, NEWER: %d]",
+		ctvr.ctLogEntries,
+		logged,
+		len(ctvr.newerCerts),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (ctvr CTLogsValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// The CT search API was not queried in order to remain within the
+	// network budget.
+	case ctvr.IsIgnored() && errors.Is(ctvr.err, budget.ErrExceeded):
+		status = fmt.Sprintf(
+			"%s validation ignored: %v",
+			ctvr.CheckName(),
+			ctvr.err,
+		)
+
+	// User opted to ignore validation check results.
+	case ctvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			ctvr.CheckName(),
+		)
+
+	case errors.Is(ctvr.err, ErrNewerCertInCTLogs):
+		status = fmt.Sprintf(
+			"%s validation failed: %s for %q; served %s cert %q may be a stale deployment",
+			ctvr.CheckName(),
+			ctvr.Err(),
+			ctvr.domain,
+			ChainPosition(ctvr.leafCert, ctvr.certChain),
+			ctvr.leafCert.Subject.CommonName,
+		)
+
+	case ctvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered comparing leaf certificate against CT logs: %v",
+			ctvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: no newer certificate found in CT logs for %q",
+			ctvr.CheckName(),
+			ctvr.domain,
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (ctvr CTLogsValidationResult) StatusDetail() string {
+	if len(ctvr.newerCerts) == 0 {
+		return ""
+	}
+
+	details := make([]string, 0, len(ctvr.newerCerts))
+	for _, entry := range ctvr.newerCerts {
+		details = append(details, fmt.Sprintf(
+			"serial %s issued %s by %q",
+			entry.SerialNumber,
+			entry.NotBefore.Format(time.RFC3339),
+			entry.IssuerName,
+		))
+	}
+
+	return strings.Join(details, "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (ctvr CTLogsValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		ctvr.Status(),
+		ctvr.Overview(),
+	)
+
+	if ctvr.StatusDetail() != "" {
+		output += ": " + ctvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (ctvr CTLogsValidationResult) Report() string {
+	return ctvr.String()
+}
+
+// ValidationStatus provides a one word status value for CT logs validation
+// check results.
+func (ctvr CTLogsValidationResult) ValidationStatus() string {
+	switch {
+	case ctvr.IsFailed():
+		return ValidationStatusFailed
+	case ctvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
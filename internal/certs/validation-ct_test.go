@@ -0,0 +1,173 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+	"github.com/atc0005/go-nagios"
+)
+
+func TestValidateCTLogs(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+	leaf := certChain[0]
+	servedSerial := FormatCertSerialNumber(leaf.SerialNumber)
+
+	// logEntry returns a CT log entry with the given serial number valid
+	// from the given offset relative to the leaf certificate.
+	logEntry := func(serial string, issuedAfterLeaf time.Duration, lifetime time.Duration) CTLogEntry {
+		notBefore := leaf.NotBefore.Add(issuedAfterLeaf)
+
+		return CTLogEntry{
+			SerialNumber: serial,
+			CommonName:   "www.example.com",
+			IssuerName:   "CN=Test Intermediate CA",
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.Add(lifetime),
+		}
+	}
+
+	lookupErr := fmt.Errorf("CT search API unavailable: %w", budget.ErrExceeded)
+
+	tests := []struct {
+		name             string
+		certChain        []*x509.Certificate
+		entries          []CTLogEntry
+		lookupErr        error
+		options          CertChainValidationOptions
+		err              error
+		wantState        string
+		wantServedLogged bool
+		wantNewer        int
+	}{
+		{
+			name:      "ServedCertLogged",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry(servedSerial, 0, 90*24*time.Hour),
+			},
+			wantState:        nagios.StateOKLabel,
+			wantServedLogged: true,
+		},
+		{
+			name:      "ServedCertLoggedInDifferentCase",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry(strings.ToLower(servedSerial), 0, 90*24*time.Hour),
+			},
+			wantState:        nagios.StateOKLabel,
+			wantServedLogged: true,
+		},
+		{
+			name:      "ServedCertNotLogged",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry("0A:FD:50:2B", -time.Hour, 90*24*time.Hour),
+			},
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "NoLogEntries",
+			certChain: certChain,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "NewerCertLogged",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry(servedSerial, 0, 90*24*time.Hour),
+				logEntry("0A:FD:50:2B", 30*time.Minute, 90*24*time.Hour),
+			},
+			err:              ErrNewerCertInCTLogs,
+			wantState:        nagios.StateWARNINGLabel,
+			wantServedLogged: true,
+			wantNewer:        1,
+		},
+		{
+			name:      "NewerCertLoggedIgnored",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry("0A:FD:50:2B", 30*time.Minute, 90*24*time.Hour),
+			},
+			options:   CertChainValidationOptions{IgnoreValidationResultCTLogs: true},
+			err:       ErrNewerCertInCTLogs,
+			wantState: nagios.StateOKLabel,
+			wantNewer: 1,
+		},
+		{
+			name:      "NewerCertNotYetValid",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry("0A:FD:50:2B", 24*time.Hour, 90*24*time.Hour),
+			},
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "NewerCertExpired",
+			certChain: certChain,
+			entries: []CTLogEntry{
+				logEntry("0A:FD:50:2B", 30*time.Minute, time.Minute),
+			},
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "LookupFailed",
+			certChain: certChain,
+			lookupErr: lookupErr,
+			err:       ErrCTLogLookupFailed,
+			wantState: nagios.StateUNKNOWNLabel,
+		},
+		{
+			name:      "LookupSkipped",
+			certChain: certChain,
+			lookupErr: lookupErr,
+			options:   CertChainValidationOptions{IgnoreValidationResultCTLogs: true},
+			err:       budget.ErrExceeded,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "NoLeafCert",
+			certChain: certChain[1:],
+			err:       ErrIncompleteCertificateChain,
+			wantState: nagios.StateUNKNOWNLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateCTLogs(tt.certChain, "www.example.com", tt.entries, tt.lookupErr, tt.options)
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got := result.ServiceState().Label; got != tt.wantState {
+				t.Errorf("want state %s; got %s: %s", tt.wantState, got, result)
+			}
+
+			if got := result.ServedCertLogged(); got != tt.wantServedLogged {
+				t.Errorf("want served cert logged %t; got %t", tt.wantServedLogged, got)
+			}
+
+			if got := result.NumNewerCerts(); got != tt.wantNewer {
+				t.Errorf("want %d newer certs; got %d", tt.wantNewer, got)
+			}
+		})
+	}
+}
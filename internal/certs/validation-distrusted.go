@@ -0,0 +1,314 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*DistrustedCAsValidationResult)(nil)
+
+// DistrustedCAsValidationResult is the validation result from evaluating a
+// certificate chain for certificates issued by or belonging to a distrusted
+// certificate authority.
+type DistrustedCAsValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// matches is the collection of distrusted certificate authorities that
+	// the certificate chain relies on.
+	matches []DistrustedCA
+}
+
+// ValidateDistrustedCAs asserts that no certificate in the given certificate
+// chain matches the public key or fingerprint of a certificate authority
+// present in the given distrusted certificate authorities list. Certificates
+// are flagged even if they are not yet expired since clients reject them
+// regardless. If specified, this validation check result is ignored.
+//
+// Only certificates served as part of the chain are evaluated; a chain which
+// omits a distrusted root certificate is only flagged if an intermediate
+// certificate is also listed.
+func ValidateDistrustedCAs(
+	certChain []*x509.Certificate,
+	distrustedCAs DistrustedCAList,
+	validationOptions CertChainValidationOptions,
+) DistrustedCAsValidationResult {
+
+	if len(certChain) == 0 {
+		return DistrustedCAsValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"required certificate chain is empty: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultDistrustedCAs,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	var matches []DistrustedCA
+	seen := make(map[string]struct{})
+
+	for _, cert := range certChain {
+		ca, ok := distrustedCAs.Match(cert)
+		if !ok {
+			continue
+		}
+		if _, found := seen[ca.Hash]; found {
+			continue
+		}
+		seen[ca.Hash] = struct{}{}
+		matches = append(matches, ca)
+	}
+
+	result := DistrustedCAsValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		matches:           matches,
+		ignored:           validationOptions.IgnoreValidationResultDistrustedCAs,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if len(matches) > 0 {
+		result.err = ErrCertChainHasDistrustedCA
+		result.priorityModifier = priorityModifierMaximum
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (dcvr DistrustedCAsValidationResult) CheckName() string {
+	return checkNameDistrustedCAsValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (dcvr DistrustedCAsValidationResult) CertChain() []*x509.Certificate {
+	return dcvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (dcvr DistrustedCAsValidationResult) TotalCerts() int {
+	return len(dcvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (dcvr DistrustedCAsValidationResult) IsWarningState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (dcvr DistrustedCAsValidationResult) IsCriticalState() bool {
+	return dcvr.err != nil && !dcvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (dcvr DistrustedCAsValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (dcvr DistrustedCAsValidationResult) IsOKState() bool {
+	return dcvr.err == nil || dcvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (dcvr DistrustedCAsValidationResult) IsIgnored() bool {
+	return dcvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (dcvr DistrustedCAsValidationResult) IsSucceeded() bool {
+	return dcvr.IsOKState() && !dcvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (dcvr DistrustedCAsValidationResult) IsFailed() bool {
+	return dcvr.err != nil && !dcvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (dcvr DistrustedCAsValidationResult) Err() error {
+	return dcvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (dcvr DistrustedCAsValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(dcvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (dcvr DistrustedCAsValidationResult) Priority() int {
+	switch {
+	case dcvr.ignored:
+		return baselinePriorityDistrustedCAsValidationResult
+	default:
+		return baselinePriorityDistrustedCAsValidationResult + dcvr.priorityModifier
+	}
+}
+
+// NumMatches returns the number of distrusted certificate authorities that
+// the evaluated certificate chain relies on.
+func (dcvr DistrustedCAsValidationResult) NumMatches() int {
+	return len(dcvr.matches)
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (dcvr DistrustedCAsValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[%d CERTS, %d DISTRUSTED CAs]",
+		len(dcvr.certChain),
+		len(dcvr.matches),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (dcvr DistrustedCAsValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case dcvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			dcvr.CheckName(),
+		)
+
+	case errors.Is(dcvr.err, ErrCertChainHasDistrustedCA):
+		status = fmt.Sprintf(
+			"%s validation failed: %s",
+			dcvr.CheckName(),
+			dcvr.Err(),
+		)
+
+	case dcvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered checking certificate chain for distrusted CAs: %v",
+			dcvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: no distrusted CAs found in chain",
+			dcvr.CheckName(),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (dcvr DistrustedCAsValidationResult) StatusDetail() string {
+	if len(dcvr.matches) == 0 {
+		return ""
+	}
+
+	details := make([]string, 0, len(dcvr.matches))
+	for _, ca := range dcvr.matches {
+		details = append(details, fmt.Sprintf(
+			"%s [%s match]",
+			ca.Name,
+			ca.EntryType,
+		))
+	}
+
+	return strings.Join(details, "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (dcvr DistrustedCAsValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		dcvr.Status(),
+		dcvr.Overview(),
+	)
+
+	if dcvr.StatusDetail() != "" {
+		output += ": " + dcvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (dcvr DistrustedCAsValidationResult) Report() string {
+	return dcvr.String()
+}
+
+// ValidationStatus provides a one word status value for distrusted CA
+// validation check results.
+func (dcvr DistrustedCAsValidationResult) ValidationStatus() string {
+	switch {
+	case dcvr.IsFailed():
+		return ValidationStatusFailed
+	case dcvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
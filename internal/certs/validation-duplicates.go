@@ -0,0 +1,309 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*DuplicateCertsValidationResult)(nil)
+
+// DuplicateCertsValidationResult is the validation result from evaluating a
+// certificate chain for redundant copies of the same certificate.
+type DuplicateCertsValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// duplicates maps the index of each redundant certificate in the chain
+	// to the index of the first occurrence of that certificate.
+	duplicates map[int]int
+}
+
+// ValidateDuplicateCerts asserts that each certificate in the given
+// certificate chain is present only once. Certificates are compared by
+// fingerprint. If specified, this validation check result is ignored.
+//
+// Redundant certificates are commonly the result of a misconfigured load
+// balancer or a certificate bundle concatenated more than once. While
+// clients generally tolerate them, they waste bandwidth during every
+// handshake and indicate a configuration problem.
+func ValidateDuplicateCerts(
+	certChain []*x509.Certificate,
+	validationOptions CertChainValidationOptions,
+) DuplicateCertsValidationResult {
+
+	if len(certChain) == 0 {
+		return DuplicateCertsValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"required certificate chain is empty: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultDuplicateCerts,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	duplicates := DuplicateCerts(certChain)
+
+	result := DuplicateCertsValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		duplicates:        duplicates,
+		ignored:           validationOptions.IgnoreValidationResultDuplicateCerts,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if len(duplicates) > 0 {
+		result.err = ErrCertChainHasDuplicateCerts
+		result.priorityModifier = priorityModifierMinimum
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (dcvr DuplicateCertsValidationResult) CheckName() string {
+	return checkNameDuplicateCertsValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (dcvr DuplicateCertsValidationResult) CertChain() []*x509.Certificate {
+	return dcvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (dcvr DuplicateCertsValidationResult) TotalCerts() int {
+	return len(dcvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. Redundant certificates in the chain are treated as a
+// WARNING state. This returns false if the validation check result is
+// flagged as ignored.
+func (dcvr DuplicateCertsValidationResult) IsWarningState() bool {
+	return errors.Is(dcvr.err, ErrCertChainHasDuplicateCerts) && !dcvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. Errors other than redundant certificates (e.g., an empty
+// chain) are treated as a CRITICAL state. This returns false if the
+// validation check result is flagged as ignored.
+func (dcvr DuplicateCertsValidationResult) IsCriticalState() bool {
+	return dcvr.err != nil &&
+		!errors.Is(dcvr.err, ErrCertChainHasDuplicateCerts) &&
+		!dcvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (dcvr DuplicateCertsValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (dcvr DuplicateCertsValidationResult) IsOKState() bool {
+	return dcvr.err == nil || dcvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (dcvr DuplicateCertsValidationResult) IsIgnored() bool {
+	return dcvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (dcvr DuplicateCertsValidationResult) IsSucceeded() bool {
+	return dcvr.IsOKState() && !dcvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (dcvr DuplicateCertsValidationResult) IsFailed() bool {
+	return dcvr.err != nil && !dcvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (dcvr DuplicateCertsValidationResult) Err() error {
+	return dcvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (dcvr DuplicateCertsValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(dcvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (dcvr DuplicateCertsValidationResult) Priority() int {
+	switch {
+	case dcvr.ignored:
+		return baselinePriorityDuplicateCertsValidationResult
+	default:
+		return baselinePriorityDuplicateCertsValidationResult + dcvr.priorityModifier
+	}
+}
+
+// NumDuplicates returns the number of redundant certificates identified in
+// the evaluated certificate chain.
+func (dcvr DuplicateCertsValidationResult) NumDuplicates() int {
+	return len(dcvr.duplicates)
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (dcvr DuplicateCertsValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[%d CERTS, %d DUPLICATES]",
+		len(dcvr.certChain),
+		len(dcvr.duplicates),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (dcvr DuplicateCertsValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case dcvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			dcvr.CheckName(),
+		)
+
+	case errors.Is(dcvr.err, ErrCertChainHasDuplicateCerts):
+		status = fmt.Sprintf(
+			"%s validation failed: %s",
+			dcvr.CheckName(),
+			dcvr.Err(),
+		)
+
+	case dcvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered checking certificate chain for duplicates: %v",
+			dcvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: no redundant certificates found",
+			dcvr.CheckName(),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (dcvr DuplicateCertsValidationResult) StatusDetail() string {
+	if len(dcvr.duplicates) == 0 {
+		return ""
+	}
+
+	indexes := make([]int, 0, len(dcvr.duplicates))
+	for idx := range dcvr.duplicates {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	entries := make([]string, 0, len(indexes))
+	for _, idx := range indexes {
+		entries = append(entries, fmt.Sprintf(
+			"certificate %d (%q) duplicates certificate %d",
+			idx+1,
+			dcvr.certChain[idx].Subject.CommonName,
+			dcvr.duplicates[idx]+1,
+		))
+	}
+
+	return strings.Join(entries, "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (dcvr DuplicateCertsValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		dcvr.Status(),
+		dcvr.Overview(),
+	)
+
+	if dcvr.StatusDetail() != "" {
+		output += ": " + dcvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (dcvr DuplicateCertsValidationResult) Report() string {
+	return dcvr.String()
+}
+
+// ValidationStatus provides a one word status value for duplicate
+// certificates validation check results.
+func (dcvr DuplicateCertsValidationResult) ValidationStatus() string {
+	switch {
+	case dcvr.IsFailed():
+		return ValidationStatusFailed
+	case dcvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+func TestValidateDuplicateCerts(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+	leaf, intermediate, root := certChain[0], certChain[1], certChain[2]
+
+	tests := []struct {
+		name           string
+		certChain      []*x509.Certificate
+		ignored        bool
+		err            error
+		wantDuplicates int
+		wantWarning    bool
+		wantCritical   bool
+	}{
+		{
+			name:      "UniqueCerts",
+			certChain: certChain,
+		},
+		{
+			name:           "RepeatedIntermediate",
+			certChain:      []*x509.Certificate{leaf, intermediate, intermediate, root},
+			err:            ErrCertChainHasDuplicateCerts,
+			wantDuplicates: 1,
+			wantWarning:    true,
+		},
+		{
+			name:           "RepeatedIntermediateIgnored",
+			certChain:      []*x509.Certificate{leaf, intermediate, intermediate, root},
+			ignored:        true,
+			err:            ErrCertChainHasDuplicateCerts,
+			wantDuplicates: 1,
+		},
+		{
+			name:         "EmptyChain",
+			err:          ErrIncompleteCertificateChain,
+			wantCritical: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateDuplicateCerts(
+				tt.certChain,
+				CertChainValidationOptions{IgnoreValidationResultDuplicateCerts: tt.ignored},
+			)
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got := result.NumDuplicates(); got != tt.wantDuplicates {
+				t.Errorf("want %d duplicates; got %d", tt.wantDuplicates, got)
+			}
+
+			if got := result.IsWarningState(); got != tt.wantWarning {
+				t.Errorf("want WARNING state %t; got %t", tt.wantWarning, got)
+			}
+
+			if got := result.IsCriticalState(); got != tt.wantCritical {
+				t.Errorf("want CRITICAL state %t; got %t", tt.wantCritical, got)
+			}
+		})
+	}
+}
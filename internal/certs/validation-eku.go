@@ -0,0 +1,485 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*ExtKeyUsageValidationResult)(nil)
+
+// Extended Key Usage keywords. These mirror the short names used by OpenSSL
+// and are matched case-insensitively.
+const (
+	ExtKeyUsageKeywordAny                        string = "any"
+	ExtKeyUsageKeywordServerAuth                 string = "serverAuth"
+	ExtKeyUsageKeywordClientAuth                 string = "clientAuth"
+	ExtKeyUsageKeywordCodeSigning                string = "codeSigning"
+	ExtKeyUsageKeywordEmailProtection            string = "emailProtection"
+	ExtKeyUsageKeywordIPSECEndSystem             string = "ipsecEndSystem"
+	ExtKeyUsageKeywordIPSECTunnel                string = "ipsecTunnel"
+	ExtKeyUsageKeywordIPSECUser                  string = "ipsecUser"
+	ExtKeyUsageKeywordTimeStamping               string = "timeStamping"
+	ExtKeyUsageKeywordOCSPSigning                string = "OCSPSigning"
+	ExtKeyUsageKeywordMicrosoftServerGatedCrypto string = "msSGC"
+	ExtKeyUsageKeywordNetscapeServerGatedCrypto  string = "nsSGC"
+)
+
+// extKeyUsageKeywords maps Extended Key Usage values to their keyword.
+var extKeyUsageKeywords = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                        ExtKeyUsageKeywordAny,
+	x509.ExtKeyUsageServerAuth:                 ExtKeyUsageKeywordServerAuth,
+	x509.ExtKeyUsageClientAuth:                 ExtKeyUsageKeywordClientAuth,
+	x509.ExtKeyUsageCodeSigning:                ExtKeyUsageKeywordCodeSigning,
+	x509.ExtKeyUsageEmailProtection:            ExtKeyUsageKeywordEmailProtection,
+	x509.ExtKeyUsageIPSECEndSystem:             ExtKeyUsageKeywordIPSECEndSystem,
+	x509.ExtKeyUsageIPSECTunnel:                ExtKeyUsageKeywordIPSECTunnel,
+	x509.ExtKeyUsageIPSECUser:                  ExtKeyUsageKeywordIPSECUser,
+	x509.ExtKeyUsageTimeStamping:               ExtKeyUsageKeywordTimeStamping,
+	x509.ExtKeyUsageOCSPSigning:                ExtKeyUsageKeywordOCSPSigning,
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto: ExtKeyUsageKeywordMicrosoftServerGatedCrypto,
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:  ExtKeyUsageKeywordNetscapeServerGatedCrypto,
+}
+
+// SupportedExtKeyUsageKeywords returns the list of Extended Key Usage
+// keywords supported by the Extended Key Usage validation check.
+func SupportedExtKeyUsageKeywords() []string {
+	return []string{
+		ExtKeyUsageKeywordAny,
+		ExtKeyUsageKeywordServerAuth,
+		ExtKeyUsageKeywordClientAuth,
+		ExtKeyUsageKeywordCodeSigning,
+		ExtKeyUsageKeywordEmailProtection,
+		ExtKeyUsageKeywordIPSECEndSystem,
+		ExtKeyUsageKeywordIPSECTunnel,
+		ExtKeyUsageKeywordIPSECUser,
+		ExtKeyUsageKeywordTimeStamping,
+		ExtKeyUsageKeywordOCSPSigning,
+		ExtKeyUsageKeywordMicrosoftServerGatedCrypto,
+		ExtKeyUsageKeywordNetscapeServerGatedCrypto,
+	}
+}
+
+// ExtKeyUsageKeyword returns the keyword for a given Extended Key Usage
+// value. A placeholder value is returned for unrecognized values.
+func ExtKeyUsageKeyword(eku x509.ExtKeyUsage) string {
+	if keyword, ok := extKeyUsageKeywords[eku]; ok {
+		return keyword
+	}
+
+	return fmt.Sprintf("unknown(%d)", eku)
+}
+
+// ParseExtKeyUsageKeyword returns the Extended Key Usage value for a given
+// (case-insensitive) keyword. False is returned if the keyword is not
+// recognized.
+func ParseExtKeyUsageKeyword(keyword string) (x509.ExtKeyUsage, bool) {
+	keyword = strings.TrimSpace(keyword)
+	for eku, name := range extKeyUsageKeywords {
+		if strings.EqualFold(name, keyword) {
+			return eku, true
+		}
+	}
+
+	return 0, false
+}
+
+// ExtKeyUsageValidationResult is the validation result from evaluating the
+// Extended Key Usage values of a leaf certificate in a chain against lists
+// of required and disallowed values.
+type ExtKeyUsageValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// leafCert is the leaf certificate from the chain that we evaluated to
+	// produce this validation check result.
+	leafCert *x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// requiredEKUs is the list of Extended Key Usage keywords that the
+	// sysadmin has stated are required to be present for the leaf
+	// certificate.
+	requiredEKUs []string
+
+	// disallowedEKUs is the list of Extended Key Usage keywords that the
+	// sysadmin has stated should not be present for the leaf certificate.
+	disallowedEKUs []string
+
+	// missingEKUs is the subset of required Extended Key Usage keywords not
+	// found on the leaf certificate.
+	missingEKUs []string
+
+	// unexpectedEKUs is the subset of disallowed Extended Key Usage keywords
+	// found on the leaf certificate.
+	unexpectedEKUs []string
+}
+
+// ValidateExtKeyUsage asserts that the leaf certificate for a given
+// certificate chain includes all required Extended Key Usage values and none
+// of the disallowed values. If specified, this validation check result is
+// ignored.
+//
+// A leaf certificate without the Extended Key Usage extension (or which
+// explicitly permits any usage) is considered to satisfy the list of required
+// values as its key is not restricted to specific purposes.
+func ValidateExtKeyUsage(
+	certChain []*x509.Certificate,
+	requiredEKUs []string,
+	disallowedEKUs []string,
+	validationOptions CertChainValidationOptions,
+) ExtKeyUsageValidationResult {
+
+	leafCerts := LeafCerts(certChain)
+	if len(leafCerts) == 0 {
+		return ExtKeyUsageValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			requiredEKUs:      requiredEKUs,
+			disallowedEKUs:    disallowedEKUs,
+			err: fmt.Errorf(
+				"leaf certificate not found in certificate chain: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultExtKeyUsage,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	leafCert := leafCerts[0]
+
+	present := make(map[x509.ExtKeyUsage]bool, len(leafCert.ExtKeyUsage))
+	for _, eku := range leafCert.ExtKeyUsage {
+		present[eku] = true
+	}
+
+	unrestricted := present[x509.ExtKeyUsageAny] ||
+		(len(leafCert.ExtKeyUsage) == 0 && len(leafCert.UnknownExtKeyUsage) == 0)
+
+	var missingEKUs []string
+	for _, keyword := range requiredEKUs {
+		eku, ok := ParseExtKeyUsageKeyword(keyword)
+		if !ok {
+			return ExtKeyUsageValidationResult{
+				certChain:         certChain,
+				leafCert:          leafCert,
+				validationOptions: validationOptions,
+				requiredEKUs:      requiredEKUs,
+				disallowedEKUs:    disallowedEKUs,
+				err: fmt.Errorf(
+					"unrecognized Extended Key Usage keyword %q: %w",
+					keyword,
+					ErrMissingValue,
+				),
+				ignored:          validationOptions.IgnoreValidationResultExtKeyUsage,
+				priorityModifier: priorityModifierMaximum,
+			}
+		}
+
+		if !present[eku] && !unrestricted {
+			missingEKUs = append(missingEKUs, ExtKeyUsageKeyword(eku))
+		}
+	}
+
+	var unexpectedEKUs []string
+	for _, keyword := range disallowedEKUs {
+		eku, ok := ParseExtKeyUsageKeyword(keyword)
+		if !ok {
+			continue
+		}
+
+		if present[eku] {
+			unexpectedEKUs = append(unexpectedEKUs, ExtKeyUsageKeyword(eku))
+		}
+	}
+
+	result := ExtKeyUsageValidationResult{
+		certChain:         certChain,
+		leafCert:          leafCert,
+		validationOptions: validationOptions,
+		requiredEKUs:      requiredEKUs,
+		disallowedEKUs:    disallowedEKUs,
+		missingEKUs:       missingEKUs,
+		unexpectedEKUs:    unexpectedEKUs,
+		ignored:           validationOptions.IgnoreValidationResultExtKeyUsage,
+	}
+
+	switch {
+	case len(missingEKUs) > 0:
+		result.err = ErrCertMissingRequiredExtKeyUsage
+		result.priorityModifier = priorityModifierMaximum
+
+	case len(unexpectedEKUs) > 0:
+		result.err = ErrCertHasDisallowedExtKeyUsage
+		result.priorityModifier = priorityModifierMinimum
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (ekuvr ExtKeyUsageValidationResult) CheckName() string {
+	return checkNameExtKeyUsageValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (ekuvr ExtKeyUsageValidationResult) CertChain() []*x509.Certificate {
+	return ekuvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (ekuvr ExtKeyUsageValidationResult) TotalCerts() int {
+	return len(ekuvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. Disallowed Extended Key Usage values present on the leaf
+// certificate are treated as a WARNING state. This returns false if the
+// validation check result is flagged as ignored.
+func (ekuvr ExtKeyUsageValidationResult) IsWarningState() bool {
+	return errors.Is(ekuvr.err, ErrCertHasDisallowedExtKeyUsage) && !ekuvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. Missing required Extended Key Usage values (or any other
+// error) are treated as a CRITICAL state. This returns false if the
+// validation check result is flagged as ignored.
+func (ekuvr ExtKeyUsageValidationResult) IsCriticalState() bool {
+	return ekuvr.err != nil &&
+		!errors.Is(ekuvr.err, ErrCertHasDisallowedExtKeyUsage) &&
+		!ekuvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (ekuvr ExtKeyUsageValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (ekuvr ExtKeyUsageValidationResult) IsOKState() bool {
+	return ekuvr.err == nil || ekuvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (ekuvr ExtKeyUsageValidationResult) IsIgnored() bool {
+	return ekuvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (ekuvr ExtKeyUsageValidationResult) IsSucceeded() bool {
+	return ekuvr.IsOKState() && !ekuvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (ekuvr ExtKeyUsageValidationResult) IsFailed() bool {
+	return ekuvr.err != nil && !ekuvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (ekuvr ExtKeyUsageValidationResult) Err() error {
+	return ekuvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (ekuvr ExtKeyUsageValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(ekuvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (ekuvr ExtKeyUsageValidationResult) Priority() int {
+	switch {
+	case ekuvr.ignored:
+		return baselinePriorityExtKeyUsageValidationResult
+	default:
+		return baselinePriorityExtKeyUsageValidationResult + ekuvr.priorityModifier
+	}
+}
+
+// PresentEKUs returns the list of Extended Key Usage keywords for the
+// evaluated leaf certificate.
+func (ekuvr ExtKeyUsageValidationResult) PresentEKUs() []string {
+	if ekuvr.leafCert == nil {
+		return nil
+	}
+
+	ekus := make([]string, 0, len(ekuvr.leafCert.ExtKeyUsage))
+	for _, eku := range ekuvr.leafCert.ExtKeyUsage {
+		ekus = append(ekus, ExtKeyUsageKeyword(eku))
+	}
+
+	return ekus
+}
+
+// NumMissing returns the number of required Extended Key Usage values not
+// present on the evaluated leaf certificate.
+func (ekuvr ExtKeyUsageValidationResult) NumMissing() int {
+	return len(ekuvr.missingEKUs)
+}
+
+// NumUnexpected returns the number of disallowed Extended Key Usage values
+// present on the evaluated leaf certificate.
+func (ekuvr ExtKeyUsageValidationResult) NumUnexpected() int {
+	return len(ekuvr.unexpectedEKUs)
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (ekuvr ExtKeyUsageValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[%d REQUIRED, %d MISSING, %d DISALLOWED PRESENT]",
+		len(ekuvr.requiredEKUs),
+		len(ekuvr.missingEKUs),
+		len(ekuvr.unexpectedEKUs),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (ekuvr ExtKeyUsageValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case ekuvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored: %d EKU values present on leaf cert",
+			ekuvr.CheckName(),
+			len(ekuvr.PresentEKUs()),
+		)
+
+	case errors.Is(ekuvr.err, ErrCertMissingRequiredExtKeyUsage) ||
+		errors.Is(ekuvr.err, ErrCertHasDisallowedExtKeyUsage):
+
+		status = fmt.Sprintf(
+			"%s validation failed for %s cert: %s",
+			ekuvr.CheckName(),
+			ChainPosition(ekuvr.leafCert, ekuvr.certChain),
+			ekuvr.Err(),
+		)
+
+	case ekuvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered validating Extended Key Usage values: %v",
+			ekuvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: required EKU values present for %s cert",
+			ekuvr.CheckName(),
+			ChainPosition(ekuvr.leafCert, ekuvr.certChain),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (ekuvr ExtKeyUsageValidationResult) StatusDetail() string {
+	if len(ekuvr.missingEKUs) == 0 && len(ekuvr.unexpectedEKUs) == 0 {
+		return ""
+	}
+
+	missing := "N/A"
+	if len(ekuvr.missingEKUs) > 0 {
+		missing = strings.Join(ekuvr.missingEKUs, ", ")
+	}
+
+	unexpected := "N/A"
+	if len(ekuvr.unexpectedEKUs) > 0 {
+		unexpected = strings.Join(ekuvr.unexpectedEKUs, ", ")
+	}
+
+	return fmt.Sprintf(
+		"missing: [%s], disallowed: [%s]",
+		missing,
+		unexpected,
+	)
+}
+
+// String provides the validation check result in human-readable format.
+func (ekuvr ExtKeyUsageValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		ekuvr.Status(),
+		ekuvr.Overview(),
+	)
+
+	if ekuvr.StatusDetail() != "" {
+		output += "; " + ekuvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (ekuvr ExtKeyUsageValidationResult) Report() string {
+	return ekuvr.String()
+}
+
+// ValidationStatus provides a one word status value for Extended Key Usage
+// validation check results.
+func (ekuvr ExtKeyUsageValidationResult) ValidationStatus() string {
+	switch {
+	case ekuvr.IsFailed():
+		return ValidationStatusFailed
+	case ekuvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
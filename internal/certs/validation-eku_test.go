@@ -0,0 +1,133 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestValidateExtKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+
+	// chainWith returns a chain with a leaf certificate using the given
+	// Extended Key Usage values.
+	chainWith := func(t *testing.T, ekus ...x509.ExtKeyUsage) []*x509.Certificate {
+		leaf := newTestCert(t, "www.example.com", false, root, func(c *x509.Certificate) {
+			c.ExtKeyUsage = ekus
+		})
+
+		return []*x509.Certificate{leaf.cert, root.cert}
+	}
+
+	required := []string{ExtKeyUsageKeywordServerAuth}
+
+	tests := []struct {
+		name       string
+		certChain  func(t *testing.T) []*x509.Certificate
+		required   []string
+		disallowed []string
+		options    CertChainValidationOptions
+		err        error
+		wantState  string
+	}{
+		{
+			name: "ServerAuthPresent",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth)
+			},
+			required:  required,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name: "ServerAuthMissing",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, x509.ExtKeyUsageClientAuth)
+			},
+			required:  required,
+			err:       ErrCertMissingRequiredExtKeyUsage,
+			wantState: nagios.StateCRITICALLabel,
+		},
+		{
+			name: "ServerAuthMissingIgnored",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, x509.ExtKeyUsageClientAuth)
+			},
+			required:  required,
+			options:   CertChainValidationOptions{IgnoreValidationResultExtKeyUsage: true},
+			err:       ErrCertMissingRequiredExtKeyUsage,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name: "AnyExtendedKeyUsage",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, x509.ExtKeyUsageAny)
+			},
+			required:  required,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name: "NoExtKeyUsageExtension",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t)
+			},
+			required:  required,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name: "DisallowedPresent",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageCodeSigning)
+			},
+			required:   required,
+			disallowed: []string{ExtKeyUsageKeywordCodeSigning},
+			err:        ErrCertHasDisallowedExtKeyUsage,
+			wantState:  nagios.StateWARNINGLabel,
+		},
+		{
+			name: "UnrecognizedKeyword",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, x509.ExtKeyUsageServerAuth)
+			},
+			required:  []string{"serverAuthentication"},
+			err:       ErrMissingValue,
+			wantState: nagios.StateCRITICALLabel,
+		},
+		{
+			name: "NoLeafCert",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return []*x509.Certificate{root.cert}
+			},
+			required:  required,
+			err:       ErrIncompleteCertificateChain,
+			wantState: nagios.StateCRITICALLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateExtKeyUsage(tt.certChain(t), tt.required, tt.disallowed, tt.options)
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got := result.ServiceState().Label; got != tt.wantState {
+				t.Errorf("want state %s; got %s: %s", tt.wantState, got, result)
+			}
+		})
+	}
+}
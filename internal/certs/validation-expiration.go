@@ -105,6 +105,12 @@ type ExpirationValidationResult struct {
 	// considered to be in a CRITICAL state. This value is calculated based on
 	// user specified threshold in days.
 	ageCriticalThreshold time.Time
+
+	// thresholdDates is the collection of CRITICAL and WARNING age threshold
+	// dates applied to certificates based on their position in the chain.
+	// The leaf certificate thresholds match ageCriticalThreshold and
+	// ageWarningThreshold.
+	thresholdDates expirationThresholdDates
 }
 
 // ValidateExpiration evaluates a given certificate chain using provided
@@ -125,6 +131,30 @@ func ValidateExpiration(
 	omitSANsEntries bool,
 	validationOptions CertChainValidationOptions,
 ) ExpirationValidationResult {
+	return ValidateExpirationByPosition(
+		certChain,
+		ExpirationThresholds{
+			LeafCritical: expireDaysCritical,
+			LeafWarning:  expireDaysWarning,
+		},
+		verboseOutput,
+		omitSANsEntries,
+		validationOptions,
+	)
+}
+
+// ValidateExpirationByPosition evaluates a given certificate chain using
+// provided CRITICAL and WARNING thresholds for each certificate chain
+// position (e.g., much earlier warnings for rarely rotated intermediate
+// certificates). Leaf certificate thresholds are used for any chain position
+// without explicit thresholds. See ValidateExpiration for further details.
+func ValidateExpirationByPosition(
+	certChain []*x509.Certificate,
+	thresholds ExpirationThresholds,
+	verboseOutput bool,
+	omitSANsEntries bool,
+	validationOptions CertChainValidationOptions,
+) ExpirationValidationResult {
 
 	// Perform basic validation of given values.
 	switch {
@@ -141,7 +171,7 @@ func ValidateExpiration(
 			priorityModifier: priorityModifierMaximum,
 		}
 
-	case expireDaysCritical == 0:
+	case thresholds.LeafCritical == 0:
 		return ExpirationValidationResult{
 			certChain:         certChain,
 			validationOptions: validationOptions,
@@ -154,7 +184,7 @@ func ValidateExpiration(
 			priorityModifier: priorityModifierMaximum,
 		}
 
-	case expireDaysWarning == 0:
+	case thresholds.LeafWarning == 0:
 		return ExpirationValidationResult{
 			certChain:         certChain,
 			validationOptions: validationOptions,
@@ -170,54 +200,52 @@ func ValidateExpiration(
 	}
 
 	now := time.Now().UTC()
-	certsExpireAgeWarning := now.AddDate(0, 0, expireDaysWarning)
-	certsExpireAgeCritical := now.AddDate(0, 0, expireDaysCritical)
+	thresholdDates := newExpirationThresholdDates(now, thresholds)
+	certsExpireAgeWarning := thresholdDates.leafWarning
+	certsExpireAgeCritical := thresholdDates.leafCritical
 
-	hasExpiredCerts := HasExpiredCert(certChain)
-	numExpiredCerts := NumExpiredCerts(certChain)
+	// Certificates with serial numbers that the sysadmin has opted to ignore
+	// are excluded from evaluation. Chain position is still determined using
+	// the full certificate chain.
+	evaluatedCerts := withoutIgnoredCerts(certChain, validationOptions)
 
-	hasExpiringCerts := HasExpiringCert(
-		certChain,
-		certsExpireAgeCritical,
-		certsExpireAgeWarning,
-	)
-	numExpiringCerts := NumExpiringCerts(
-		certChain,
-		certsExpireAgeCritical,
-		certsExpireAgeWarning,
-	)
+	hasExpiredCerts := HasExpiredCert(evaluatedCerts)
+	numExpiredCerts := NumExpiredCerts(evaluatedCerts)
+
+	numExpiringCerts := thresholdDates.numExpiringCerts(evaluatedCerts, certChain)
+	hasExpiringCerts := numExpiringCerts > 0
 
 	hasExpiringLeafCerts := HasExpiringCert(
-		LeafCerts(certChain),
+		withoutIgnoredCerts(LeafCerts(certChain), validationOptions),
 		certsExpireAgeCritical,
 		certsExpireAgeWarning,
 	)
 
 	hasExpiringIntermediateCerts := HasExpiringCert(
-		IntermediateCerts(certChain),
-		certsExpireAgeCritical,
-		certsExpireAgeWarning,
+		withoutIgnoredCerts(IntermediateCerts(certChain), validationOptions),
+		thresholdDates.intermediateCritical,
+		thresholdDates.intermediateWarning,
 	)
 
 	hasExpiringRootCerts := HasExpiringCert(
-		RootCerts(certChain),
-		certsExpireAgeCritical,
-		certsExpireAgeWarning,
+		withoutIgnoredCerts(RootCerts(certChain), validationOptions),
+		thresholdDates.rootCritical,
+		thresholdDates.rootWarning,
 	)
 
 	hasExpiredLeafCerts := HasExpiredCert(
-		LeafCerts(certChain),
+		withoutIgnoredCerts(LeafCerts(certChain), validationOptions),
 	)
 
 	hasExpiredIntermediateCerts := HasExpiredCert(
-		IntermediateCerts(certChain),
+		withoutIgnoredCerts(IntermediateCerts(certChain), validationOptions),
 	)
 
 	hasExpiredRootCerts := HasExpiredCert(
-		RootCerts(certChain),
+		withoutIgnoredCerts(RootCerts(certChain), validationOptions),
 	)
 
-	filteredCerts := filterCertificateChain(certChain, validationOptions, certsExpireAgeCritical, certsExpireAgeWarning)
+	filteredCerts := filterCertificateChain(certChain, validationOptions, thresholdDates)
 
 	// Process certificates expiration status checks.
 	switch {
@@ -235,6 +263,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -260,6 +289,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -287,6 +317,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -314,6 +345,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -341,6 +373,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -368,6 +401,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -407,6 +441,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -446,6 +481,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -485,6 +521,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -524,6 +561,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -547,6 +585,7 @@ func ValidateExpiration(
 			omitSANsEntries:              omitSANsEntries,
 			ageWarningThreshold:          certsExpireAgeWarning,
 			ageCriticalThreshold:         certsExpireAgeCritical,
+			thresholdDates:               thresholdDates,
 			hasExpiredCerts:              hasExpiredCerts,
 			hasExpiringCerts:             hasExpiringCerts,
 			hasExpiredIntermediateCerts:  hasExpiredIntermediateCerts,
@@ -588,7 +627,8 @@ func (evr ExpirationValidationResult) IsWarningState() bool {
 
 	// for _, cert := range evr.certChain {
 	for _, cert := range evr.FilteredCertificateChain() {
-		if IsExpiringCert(cert, evr.ageCriticalThreshold, evr.ageWarningThreshold) {
+		ageCritical, ageWarning := evr.thresholdDates.forCert(cert, evr.certChain)
+		if IsExpiringCert(cert, ageCritical, ageWarning) {
 			return true
 		}
 	}
@@ -607,7 +647,8 @@ func (evr ExpirationValidationResult) IsCriticalState() bool {
 
 	// for _, cert := range evr.certChain {
 	for _, cert := range evr.FilteredCertificateChain() {
-		if IsExpiredCert(cert) || cert.NotAfter.Before(evr.ageCriticalThreshold) {
+		ageCritical, _ := evr.thresholdDates.forCert(cert, evr.certChain)
+		if IsExpiredCert(cert) || cert.NotAfter.Before(ageCritical) {
 			return true
 		}
 	}
@@ -699,7 +740,13 @@ func (evr ExpirationValidationResult) Status() string {
 	// based on expiring or expired status.
 	certChainFiltered := evr.FilteredCertificateChain()
 
+	// Fall back to the original certificate chain when reporting the next
+	// certificate to expire if the sysadmin opted to ignore every
+	// certificate by serial number.
 	nextCertToExpire := NextToExpire(certChainFiltered, false)
+	if nextCertToExpire == nil {
+		nextCertToExpire = NextToExpire(evr.certChain, false)
+	}
 
 	// Start by assuming that the CommonName is *not* blank
 	nextCertToExpireServerName := nextCertToExpire.Subject.CommonName
@@ -716,7 +763,7 @@ func (evr ExpirationValidationResult) Status() string {
 	switch {
 	case HasExpiredCert(certChainFiltered):
 		summaryTemplate = ExpirationValidationOneLineSummaryExpiredTmpl
-	case HasExpiringCert(certChainFiltered, evr.ageCriticalThreshold, evr.ageWarningThreshold):
+	case evr.thresholdDates.numExpiringCerts(certChainFiltered, evr.certChain) > 0:
 		summaryTemplate = ExpirationValidationOneLineSummaryExpiresNextTmpl
 	default:
 		summaryTemplate = ExpirationValidationOneLineSummaryExpiresNextTmpl
@@ -740,10 +787,9 @@ func (evr ExpirationValidationResult) Status() string {
 // status text with information suitable as explanation for the overall state
 // of the validation check result. This text may span multiple lines.
 func (evr ExpirationValidationResult) StatusDetail() string {
-	return GenerateCertChainReport(
+	return generateCertChainReport(
 		evr.certChain,
-		evr.ageCriticalThreshold,
-		evr.ageWarningThreshold,
+		evr.thresholdDates,
 		evr.verboseOutput,
 		evr.validationOptions,
 		evr.omitSANsEntries,
@@ -858,10 +904,12 @@ func (evr ExpirationValidationResult) CriticalDateThreshold() string {
 // in order to give it the highest precedence.
 //
 // If the sysadmin did not opt to ignore any certificates then the returned
-// certificate chain is unchanged from the original.
+// certificate chain is unchanged from the original. If the sysadmin opted to
+// ignore every certificate by serial number then the returned certificate
+// chain is empty.
 func (evr ExpirationValidationResult) FilteredCertificateChain() []*x509.Certificate {
 	switch {
-	case len(evr.filteredCertChain) > 0:
+	case evr.filteredCertChain != nil:
 		return evr.filteredCertChain
 	default:
 		return evr.certChain
@@ -899,7 +947,8 @@ func (evr ExpirationValidationResult) AgeCriticalThreshold() time.Time {
 }
 
 // filterCertificateChain filters a given certificate chain excluding any
-// certificates that the sysadmin has opted to ignore. The first leaf
+// certificates that the sysadmin has opted to ignore using the expiration
+// thresholds applicable to each certificate's chain position. The first leaf
 // certificate encountered that is expired or expiring is returned by itself
 // in order to give it the highest precedence.
 //
@@ -908,12 +957,16 @@ func (evr ExpirationValidationResult) AgeCriticalThreshold() time.Time {
 func filterCertificateChain(
 	certChain []*x509.Certificate,
 	validationOptions CertChainValidationOptions,
-	ageCriticalThreshold time.Time,
-	ageWarningThreshold time.Time,
+	thresholdDates expirationThresholdDates,
 ) []*x509.Certificate {
 
 	certChainFiltered := make([]*x509.Certificate, 0, len(certChain))
 	for _, cert := range certChain {
+		if IsIgnoredCert(cert, validationOptions) {
+			continue
+		}
+
+		ageCriticalThreshold, ageWarningThreshold := thresholdDates.forCert(cert, certChain)
 
 		// Leaf certs with issues get the highest priority. Add the first leaf
 		// cert in the chain with issues to our list and skip processing any
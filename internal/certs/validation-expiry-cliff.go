@@ -0,0 +1,345 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*ExpiryCliffValidationResult)(nil)
+
+// ExpiryCliffValidationResult is the validation result from evaluating a
+// certificate chain for multiple certificates expiring within the same short
+// window.
+type ExpiryCliffValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// window is the period within which the expiration of multiple
+	// certificates is treated as a single expiry cliff.
+	window time.Duration
+
+	// leadTime is the period before the earliest expiration of an expiry
+	// cliff when the expiry cliff is reported.
+	leadTime time.Duration
+
+	// cliffs is the collection of expiry cliffs found within the lead time.
+	cliffs []ExpiryCliff
+}
+
+// ValidateExpiryCliff asserts that multiple certificates in the given
+// certificate chain do not expire within the given window of each other
+// during the given lead time. If specified, this validation check result is
+// ignored.
+//
+// Certificates which expire together (e.g., a leaf and intermediate
+// certificate issued by an internal CA on the same day) each require
+// renewal at the same time. Bulk renewals need more lead time than the
+// renewal of a single certificate, so these expiry cliffs are reported well
+// ahead of the usual expiration thresholds.
+func ValidateExpiryCliff(
+	certChain []*x509.Certificate,
+	window time.Duration,
+	leadTime time.Duration,
+	validationOptions CertChainValidationOptions,
+) ExpiryCliffValidationResult {
+
+	if len(certChain) == 0 {
+		return ExpiryCliffValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			window:            window,
+			leadTime:          leadTime,
+			err: fmt.Errorf(
+				"required certificate chain is empty: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultExpiryCliff,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	cliffs := ExpiryCliffs(certChain, window, leadTime)
+
+	result := ExpiryCliffValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		window:            window,
+		leadTime:          leadTime,
+		cliffs:            cliffs,
+		ignored:           validationOptions.IgnoreValidationResultExpiryCliff,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if len(cliffs) > 0 {
+		result.err = ErrCertChainExpiryCliff
+		result.priorityModifier = priorityModifierMinimum
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (ecvr ExpiryCliffValidationResult) CheckName() string {
+	return checkNameExpiryCliffValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (ecvr ExpiryCliffValidationResult) CertChain() []*x509.Certificate {
+	return ecvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (ecvr ExpiryCliffValidationResult) TotalCerts() int {
+	return len(ecvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. Multiple certificates expiring together are treated as a
+// WARNING state. This returns false if the validation check result is
+// flagged as ignored.
+func (ecvr ExpiryCliffValidationResult) IsWarningState() bool {
+	return errors.Is(ecvr.err, ErrCertChainExpiryCliff) && !ecvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. Errors other than an expiry cliff (e.g., an empty chain)
+// are treated as a CRITICAL state. This returns false if the validation
+// check result is flagged as ignored.
+func (ecvr ExpiryCliffValidationResult) IsCriticalState() bool {
+	return ecvr.err != nil &&
+		!errors.Is(ecvr.err, ErrCertChainExpiryCliff) &&
+		!ecvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (ecvr ExpiryCliffValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (ecvr ExpiryCliffValidationResult) IsOKState() bool {
+	return ecvr.err == nil || ecvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (ecvr ExpiryCliffValidationResult) IsIgnored() bool {
+	return ecvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (ecvr ExpiryCliffValidationResult) IsSucceeded() bool {
+	return ecvr.IsOKState() && !ecvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (ecvr ExpiryCliffValidationResult) IsFailed() bool {
+	return ecvr.err != nil && !ecvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (ecvr ExpiryCliffValidationResult) Err() error {
+	return ecvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (ecvr ExpiryCliffValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(ecvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (ecvr ExpiryCliffValidationResult) Priority() int {
+	switch {
+	case ecvr.ignored:
+		return baselinePriorityExpiryCliffValidationResult
+	default:
+		return baselinePriorityExpiryCliffValidationResult + ecvr.priorityModifier
+	}
+}
+
+// Cliffs returns the expiry cliffs identified in the evaluated certificate
+// chain.
+func (ecvr ExpiryCliffValidationResult) Cliffs() []ExpiryCliff {
+	return ecvr.cliffs
+}
+
+// NumCliffCerts returns the number of certificates which are part of an
+// expiry cliff.
+func (ecvr ExpiryCliffValidationResult) NumCliffCerts() int {
+	var num int
+	for _, cliff := range ecvr.cliffs {
+		num += len(cliff.Certs)
+	}
+
+	return num
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (ecvr ExpiryCliffValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[%d CERTS, %d EXPIRING TOGETHER]",
+		len(ecvr.certChain),
+		ecvr.NumCliffCerts(),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (ecvr ExpiryCliffValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case ecvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			ecvr.CheckName(),
+		)
+
+	case errors.Is(ecvr.err, ErrCertChainExpiryCliff):
+		status = fmt.Sprintf(
+			"%s validation failed: %s",
+			ecvr.CheckName(),
+			ecvr.Err(),
+		)
+
+	case ecvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered checking certificate chain for expiry cliffs: %v",
+			ecvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: no certificates expire within %s of each other in the next %s",
+			ecvr.CheckName(),
+			formatDays(ecvr.window),
+			formatDays(ecvr.leadTime),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (ecvr ExpiryCliffValidationResult) StatusDetail() string {
+	if len(ecvr.cliffs) == 0 {
+		return ""
+	}
+
+	entries := make([]string, 0, len(ecvr.cliffs))
+	for _, cliff := range ecvr.cliffs {
+		names := make([]string, 0, len(cliff.Certs))
+		for _, cert := range cliff.Certs {
+			names = append(names, fmt.Sprintf(
+				"%q (%s)",
+				cert.Subject.CommonName,
+				ChainPosition(cert, ecvr.certChain),
+			))
+		}
+
+		entries = append(entries, fmt.Sprintf(
+			"%s: %s",
+			cliff,
+			strings.Join(names, ", "),
+		))
+	}
+
+	return strings.Join(entries, "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (ecvr ExpiryCliffValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		ecvr.Status(),
+		ecvr.Overview(),
+	)
+
+	if ecvr.StatusDetail() != "" {
+		output += ": " + ecvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (ecvr ExpiryCliffValidationResult) Report() string {
+	return ecvr.String()
+}
+
+// ValidationStatus provides a one word status value for expiry cliff
+// validation check results.
+func (ecvr ExpiryCliffValidationResult) ValidationStatus() string {
+	switch {
+	case ecvr.IsFailed():
+		return ValidationStatusFailed
+	case ecvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
+
+// formatDays formats the given duration as a whole number of days.
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
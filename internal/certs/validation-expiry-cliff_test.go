@@ -0,0 +1,89 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestValidateExpiryCliff(t *testing.T) {
+	t.Parallel()
+
+	const (
+		day      = 24 * time.Hour
+		window   = 7 * day
+		leadTime = 60 * day
+	)
+
+	start := time.Now().Add(30 * day).Truncate(time.Second)
+
+	first := newTestCertExpiringAt(t, "first", start)
+	atBoundary := newTestCertExpiringAt(t, "at boundary", start.Add(window))
+	pastBoundary := newTestCertExpiringAt(t, "past boundary", start.Add(window+time.Second))
+
+	tests := []struct {
+		name          string
+		certChain     []*x509.Certificate
+		options       CertChainValidationOptions
+		err           error
+		wantState     string
+		wantCliffCert int
+	}{
+		{
+			name:          "ExpiringTogether",
+			certChain:     []*x509.Certificate{first, atBoundary},
+			err:           ErrCertChainExpiryCliff,
+			wantState:     nagios.StateWARNINGLabel,
+			wantCliffCert: 2,
+		},
+		{
+			name:          "ExpiringTogetherIgnored",
+			certChain:     []*x509.Certificate{first, atBoundary},
+			options:       CertChainValidationOptions{IgnoreValidationResultExpiryCliff: true},
+			err:           ErrCertChainExpiryCliff,
+			wantState:     nagios.StateOKLabel,
+			wantCliffCert: 2,
+		},
+		{
+			name:      "ExpiringApart",
+			certChain: []*x509.Certificate{first, pastBoundary},
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "EmptyChain",
+			err:       ErrIncompleteCertificateChain,
+			wantState: nagios.StateCRITICALLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateExpiryCliff(tt.certChain, window, leadTime, tt.options)
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got := result.ServiceState().Label; got != tt.wantState {
+				t.Errorf("want state %s; got %s: %s", tt.wantState, got, result)
+			}
+
+			if got := result.NumCliffCerts(); got != tt.wantCliffCert {
+				t.Errorf("want %d certs expiring together; got %d", tt.wantCliffCert, got)
+			}
+		})
+	}
+}
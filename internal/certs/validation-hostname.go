@@ -243,6 +243,11 @@ func (hnvr HostnameValidationResult) CheckName() string {
 	return checkNameHostnameValidationResult
 }
 
+// Hostname returns the hostname value used during hostname verification.
+func (hnvr HostnameValidationResult) Hostname() string {
+	return hnvr.hostnameValue
+}
+
 // CertChain returns the evaluated certificate chain.
 func (hnvr HostnameValidationResult) CertChain() []*x509.Certificate {
 	return hnvr.certChain
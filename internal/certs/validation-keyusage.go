@@ -0,0 +1,443 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*KeyUsageValidationResult)(nil)
+
+// KeyUsageValidationResult is the validation result from evaluating the Key
+// Usage and Extended Key Usage extensions of each certificate in a chain for
+// consistency with the tier of the certificate in the chain and the key type
+// of the certificate.
+type KeyUsageValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// violations is the collection of human-readable descriptions for each
+	// identified Key Usage mismatch, including remediation advice.
+	violations []string
+}
+
+// Remediation advice included alongside identified Key Usage mismatches.
+const (
+	keyUsageAdviceIssuer    string = "reissue the CA certificate with keyUsage set to keyCertSign and cRLSign"
+	keyUsageAdviceLeafRSA   string = "reissue the certificate with keyUsage set to digitalSignature and (optionally) keyEncipherment"
+	keyUsageAdviceLeafEC    string = "reissue the certificate with keyUsage set to digitalSignature and (optionally) keyAgreement"
+	keyUsageAdviceLeafEdDSA string = "reissue the certificate with keyUsage set to digitalSignature only"
+	keyUsageAdviceLeafCA    string = "reissue the certificate without the keyCertSign and cRLSign Key Usage values"
+	keyUsageAdviceEKU       string = "reissue the certificate with Extended Key Usage values permitted by its issuer or reissue the issuer without an Extended Key Usage restriction"
+)
+
+// ValidateKeyUsage asserts that the Key Usage and Extended Key Usage
+// extensions for each certificate in the given certificate chain are
+// consistent with its tier in the chain. If specified, this validation check
+// result is ignored.
+//
+// Each issuer CA certificate is required to have the cRLSign Key Usage
+// value; a missing keyCertSign value is flagged by the chain constraints
+// validation check and is not evaluated here. The first certificate in the chain is evaluated as the
+// leaf certificate and is required to have Key Usage values appropriate for
+// its key type: digitalSignature or keyEncipherment for RSA keys,
+// digitalSignature or keyAgreement for ECDSA keys and digitalSignature for
+// Ed25519 keys. If an issuer restricts Extended Key Usage values, the
+// Extended Key Usage values of the certificate it issued are required to be
+// permitted by the issuer.
+//
+// Certificates without a Key Usage extension are not restricted and are not
+// evaluated for Key Usage values. Self-signed roots are evaluated based on
+// their trust store entry and are skipped.
+func ValidateKeyUsage(
+	certChain []*x509.Certificate,
+	validationOptions CertChainValidationOptions,
+) KeyUsageValidationResult {
+
+	if len(certChain) == 0 {
+		return KeyUsageValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"required certificate chain is empty: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultKeyUsage,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	var violations []string
+
+	leafCert := certChain[0]
+	if !leafCert.IsCA {
+		violations = append(violations, leafKeyUsageMismatches(leafCert)...)
+	}
+
+	for i := 1; i < len(certChain); i++ {
+		issuer := certChain[i]
+		issued := certChain[i-1]
+
+		if i == len(certChain)-1 && isSelfSigned(issuer) {
+			continue
+		}
+
+		// Issuers which are not CA certificates are flagged by the chain
+		// constraints validation check.
+		if !issuer.IsCA {
+			continue
+		}
+
+		if issuer.KeyUsage != 0 && issuer.KeyUsage&x509.KeyUsageCRLSign == 0 {
+			violations = append(violations, fmt.Sprintf(
+				"issuer cert %q (position %d) is missing cRLSign Key Usage; %s",
+				issuer.Subject.CommonName,
+				i+1,
+				keyUsageAdviceIssuer,
+			))
+		}
+
+		if unpermitted := unpermittedExtKeyUsages(issued, issuer); len(unpermitted) > 0 {
+			violations = append(violations, fmt.Sprintf(
+				"cert %q (position %d) has Extended Key Usage %v not permitted by issuer %q (position %d); %s",
+				issued.Subject.CommonName,
+				i,
+				unpermitted,
+				issuer.Subject.CommonName,
+				i+1,
+				keyUsageAdviceEKU,
+			))
+		}
+	}
+
+	result := KeyUsageValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		violations:        violations,
+		ignored:           validationOptions.IgnoreValidationResultKeyUsage,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if len(violations) > 0 {
+		result.err = ErrCertChainKeyUsageMismatch
+		result.priorityModifier = priorityModifierMinimum
+	}
+
+	return result
+}
+
+// leafKeyUsageMismatches evaluates the Key Usage values of the given leaf
+// certificate against its key type and returns a description of each
+// identified mismatch.
+func leafKeyUsageMismatches(leafCert *x509.Certificate) []string {
+	// An absent Key Usage extension places no restrictions on the key.
+	if leafCert.KeyUsage == 0 {
+		return nil
+	}
+
+	var mismatches []string
+
+	describe := func(problem string, advice string) {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"leaf cert %q with %s key %s; %s",
+			leafCert.Subject.CommonName,
+			leafCert.PublicKeyAlgorithm,
+			problem,
+			advice,
+		))
+	}
+
+	ku := leafCert.KeyUsage
+	hasDigitalSignature := ku&x509.KeyUsageDigitalSignature != 0
+	hasKeyEncipherment := ku&x509.KeyUsageKeyEncipherment != 0
+	hasKeyAgreement := ku&x509.KeyUsageKeyAgreement != 0
+
+	switch leafCert.PublicKeyAlgorithm {
+	case x509.RSA:
+		switch {
+		case !hasDigitalSignature && !hasKeyEncipherment:
+			describe("is missing digitalSignature and keyEncipherment Key Usage", keyUsageAdviceLeafRSA)
+		case hasKeyAgreement:
+			describe("has keyAgreement Key Usage which is not supported for this key type", keyUsageAdviceLeafRSA)
+		}
+
+	case x509.ECDSA:
+		switch {
+		case !hasDigitalSignature && !hasKeyAgreement:
+			describe("is missing digitalSignature and keyAgreement Key Usage", keyUsageAdviceLeafEC)
+		case hasKeyEncipherment:
+			describe("has keyEncipherment Key Usage which is not supported for this key type", keyUsageAdviceLeafEC)
+		}
+
+	case x509.Ed25519:
+		switch {
+		case !hasDigitalSignature:
+			describe("is missing digitalSignature Key Usage", keyUsageAdviceLeafEdDSA)
+		case hasKeyEncipherment || hasKeyAgreement:
+			describe("has keyEncipherment or keyAgreement Key Usage which is not supported for this key type", keyUsageAdviceLeafEdDSA)
+		}
+	}
+
+	if ku&(x509.KeyUsageCertSign|x509.KeyUsageCRLSign) != 0 {
+		describe("has keyCertSign or cRLSign Key Usage but is not a CA certificate", keyUsageAdviceLeafCA)
+	}
+
+	return mismatches
+}
+
+// unpermittedExtKeyUsages returns the Extended Key Usage values of the given
+// issued certificate which are not permitted by the Extended Key Usage values
+// of its issuer. An issuer without the Extended Key Usage extension or with
+// the anyExtendedKeyUsage value places no restrictions on issued
+// certificates.
+func unpermittedExtKeyUsages(issued *x509.Certificate, issuer *x509.Certificate) []string {
+	if len(issuer.ExtKeyUsage) == 0 && len(issuer.UnknownExtKeyUsage) == 0 {
+		return nil
+	}
+
+	permitted := make(map[x509.ExtKeyUsage]struct{}, len(issuer.ExtKeyUsage))
+	for _, eku := range issuer.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageAny {
+			return nil
+		}
+		permitted[eku] = struct{}{}
+	}
+
+	var unpermitted []string
+	for _, eku := range issued.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageAny {
+			continue
+		}
+
+		if _, ok := permitted[eku]; !ok {
+			unpermitted = append(unpermitted, ExtKeyUsageKeyword(eku))
+		}
+	}
+
+	return unpermitted
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (kuvr KeyUsageValidationResult) CheckName() string {
+	return checkNameKeyUsageValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (kuvr KeyUsageValidationResult) CertChain() []*x509.Certificate {
+	return kuvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (kuvr KeyUsageValidationResult) TotalCerts() int {
+	return len(kuvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (kuvr KeyUsageValidationResult) IsWarningState() bool {
+	return kuvr.err != nil && !kuvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (kuvr KeyUsageValidationResult) IsCriticalState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (kuvr KeyUsageValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (kuvr KeyUsageValidationResult) IsOKState() bool {
+	return kuvr.err == nil || kuvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (kuvr KeyUsageValidationResult) IsIgnored() bool {
+	return kuvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (kuvr KeyUsageValidationResult) IsSucceeded() bool {
+	return kuvr.IsOKState() && !kuvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (kuvr KeyUsageValidationResult) IsFailed() bool {
+	return kuvr.err != nil && !kuvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (kuvr KeyUsageValidationResult) Err() error {
+	return kuvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (kuvr KeyUsageValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(kuvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (kuvr KeyUsageValidationResult) Priority() int {
+	switch {
+	case kuvr.ignored:
+		return baselinePriorityKeyUsageValidationResult
+	default:
+		return baselinePriorityKeyUsageValidationResult + kuvr.priorityModifier
+	}
+}
+
+// NumViolations returns the number of Key Usage mismatches identified for
+// the evaluated certificate chain.
+func (kuvr KeyUsageValidationResult) NumViolations() int {
+	return len(kuvr.violations)
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (kuvr KeyUsageValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[%d CERTS, %d VIOLATIONS]",
+		len(kuvr.certChain),
+		len(kuvr.violations),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (kuvr KeyUsageValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case kuvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			kuvr.CheckName(),
+		)
+
+	case errors.Is(kuvr.err, ErrCertChainKeyUsageMismatch):
+		status = fmt.Sprintf(
+			"%s validation failed: %s",
+			kuvr.CheckName(),
+			kuvr.Err(),
+		)
+
+	case kuvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered validating certificate chain Key Usage values: %v",
+			kuvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: Key Usage values consistent with chain tiers and key types",
+			kuvr.CheckName(),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (kuvr KeyUsageValidationResult) StatusDetail() string {
+	if len(kuvr.violations) == 0 {
+		return ""
+	}
+
+	return strings.Join(kuvr.violations, "; ")
+}
+
+// String provides the validation check result in human-readable format.
+func (kuvr KeyUsageValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		kuvr.Status(),
+		kuvr.Overview(),
+	)
+
+	if kuvr.StatusDetail() != "" {
+		output += ": " + kuvr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (kuvr KeyUsageValidationResult) Report() string {
+	return kuvr.String()
+}
+
+// ValidationStatus provides a one word status value for Key Usage
+// validation check results.
+func (kuvr KeyUsageValidationResult) ValidationStatus() string {
+	switch {
+	case kuvr.IsFailed():
+		return ValidationStatusFailed
+	case kuvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
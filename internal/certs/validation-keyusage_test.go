@@ -0,0 +1,164 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+func TestValidateKeyUsage(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, nil)
+
+	// chainWith returns a chain with a leaf certificate modified as
+	// specified and issued by the given intermediate certificate.
+	chainWith := func(t *testing.T, issuer *testCert, modify func(*x509.Certificate)) []*x509.Certificate {
+		leaf := newTestCert(t, "www.example.com", false, issuer, modify)
+
+		return []*x509.Certificate{leaf.cert, issuer.cert, root.cert}
+	}
+
+	// issuerWith returns an intermediate certificate modified as specified.
+	issuerWith := func(t *testing.T, modify func(*x509.Certificate)) *testCert {
+		return newTestCert(t, "Test Intermediate CA", true, root, modify)
+	}
+
+	tests := []struct {
+		name           string
+		certChain      func(t *testing.T) []*x509.Certificate
+		err            error
+		wantViolations int
+	}{
+		{
+			name: "ValidChain",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, intermediate, nil)
+			},
+		},
+		{
+			name: "LeafWithoutKeyUsage",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, intermediate, func(c *x509.Certificate) {
+					c.KeyUsage = 0
+				})
+			},
+		},
+		{
+			name: "ECDSALeafWithKeyEncipherment",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, intermediate, func(c *x509.Certificate) {
+					c.KeyUsage |= x509.KeyUsageKeyEncipherment
+				})
+			},
+			err:            ErrCertChainKeyUsageMismatch,
+			wantViolations: 1,
+		},
+		{
+			name: "LeafWithCertSign",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, intermediate, func(c *x509.Certificate) {
+					c.KeyUsage |= x509.KeyUsageCertSign
+				})
+			},
+			err:            ErrCertChainKeyUsageMismatch,
+			wantViolations: 1,
+		},
+		{
+			name: "IssuerMissingCRLSign",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, issuerWith(t, func(c *x509.Certificate) {
+					c.KeyUsage = x509.KeyUsageCertSign
+				}), nil)
+			},
+			err:            ErrCertChainKeyUsageMismatch,
+			wantViolations: 1,
+		},
+		{
+			name: "IssuerMissingCertSign",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, issuerWith(t, func(c *x509.Certificate) {
+					c.KeyUsage = x509.KeyUsageCRLSign
+				}), nil)
+			},
+		},
+		{
+			name: "ExtKeyUsageNotPermittedByIssuer",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, issuerWith(t, func(c *x509.Certificate) {
+					c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+				}), nil)
+			},
+			err:            ErrCertChainKeyUsageMismatch,
+			wantViolations: 1,
+		},
+		{
+			name: "ExtKeyUsagePermittedByIssuer",
+			certChain: func(t *testing.T) []*x509.Certificate {
+				return chainWith(t, issuerWith(t, func(c *x509.Certificate) {
+					c.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+				}), nil)
+			},
+		},
+		{
+			name:      "EmptyChain",
+			certChain: func(t *testing.T) []*x509.Certificate { return nil },
+			err:       ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateKeyUsage(tt.certChain(t), CertChainValidationOptions{})
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if result.NumViolations() != tt.wantViolations {
+				t.Errorf("want %d violations; got %d: %s", tt.wantViolations, result.NumViolations(), result)
+			}
+
+			if got, want := result.IsOKState(), tt.err == nil; got != want {
+				t.Errorf("want OK state %t; got %t", want, got)
+			}
+		})
+	}
+}
+
+// TestIssuerMissingCertSignSingleFinding asserts that an issuer missing the
+// keyCertSign Key Usage value is reported by the chain constraints
+// validation check only.
+func TestIssuerMissingCertSignSingleFinding(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	intermediate := newTestCert(t, "Test Intermediate CA", true, root, func(c *x509.Certificate) {
+		c.KeyUsage = x509.KeyUsageCRLSign
+	})
+	leaf := newTestCert(t, "www.example.com", false, intermediate, nil)
+
+	certChain := []*x509.Certificate{leaf.cert, intermediate.cert, root.cert}
+
+	keyUsage := ValidateKeyUsage(certChain, CertChainValidationOptions{})
+	constraints := ValidateChainConstraints(certChain, CertChainValidationOptions{})
+
+	if got := keyUsage.NumViolations() + constraints.NumViolations(); got != 1 {
+		t.Errorf("want 1 finding; got %d: %s; %s", got, keyUsage, constraints)
+	}
+
+	if !errors.Is(constraints.Err(), ErrCertChainConstraintsViolation) {
+		t.Errorf("want chain constraints error %v; got %v", ErrCertChainConstraintsViolation, constraints.Err())
+	}
+}
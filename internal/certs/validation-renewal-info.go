@@ -0,0 +1,351 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*RenewalInfoValidationResult)(nil)
+
+// RenewalInfoValidationResult is the validation result from comparing the
+// current time against the renewal window suggested by the Certificate
+// Authority for the leaf certificate of a certificate chain.
+type RenewalInfoValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// leafCert is the leaf certificate from the chain that we evaluated.
+	leafCert *x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// renewalInfo is the renewal window suggested by the Certificate
+	// Authority for the leaf certificate. This is the zero value if renewal
+	// information was not retrieved.
+	renewalInfo RenewalInfo
+}
+
+// ValidateRenewalInfo asserts that the renewal window suggested by the
+// Certificate Authority (via ACME Renewal Information) for the leaf
+// certificate of a given certificate chain has not started. A renewal window
+// which has started indicates that the certificate should be renewed now
+// even if expiration thresholds have not been crossed (e.g., due to a
+// pending revocation). If specified, this validation check result is
+// ignored.
+//
+// The given lookup error, if any, is recorded as the reason that validation
+// could not be performed.
+func ValidateRenewalInfo(
+	certChain []*x509.Certificate,
+	renewalInfo RenewalInfo,
+	lookupErr error,
+	validationOptions CertChainValidationOptions,
+) RenewalInfoValidationResult {
+
+	leafCerts := LeafCerts(certChain)
+
+	// Early exit logic.
+	switch {
+	case len(leafCerts) == 0:
+		return RenewalInfoValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"leaf certificate not found in certificate chain: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultRenewalInfo,
+			priorityModifier: priorityModifierMaximum,
+		}
+
+	case lookupErr != nil:
+		return RenewalInfoValidationResult{
+			certChain:         certChain,
+			leafCert:          leafCerts[0],
+			validationOptions: validationOptions,
+			err:               fmt.Errorf("%w: %w", ErrRenewalInfoLookupFailed, lookupErr),
+			ignored:           validationOptions.IgnoreValidationResultRenewalInfo,
+
+			// An unavailable external service says nothing about the
+			// certificate chain, so this is not given precedence over
+			// other validation check results.
+			priorityModifier: priorityModifierMinimum,
+		}
+	}
+
+	result := RenewalInfoValidationResult{
+		certChain:         certChain,
+		leafCert:          leafCerts[0],
+		validationOptions: validationOptions,
+		renewalInfo:       renewalInfo,
+		ignored:           validationOptions.IgnoreValidationResultRenewalInfo,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if !renewalInfo.WindowStart.IsZero() && !time.Now().Before(renewalInfo.WindowStart) {
+		result.err = ErrRenewalWindowStarted
+		result.priorityModifier = priorityModifierMedium
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (rivr RenewalInfoValidationResult) CheckName() string {
+	return checkNameRenewalInfoValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (rivr RenewalInfoValidationResult) CertChain() []*x509.Certificate {
+	return rivr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (rivr RenewalInfoValidationResult) TotalCerts() int {
+	return len(rivr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or UNKNOWN state, or is flagged as ignored. True is returned otherwise.
+func (rivr RenewalInfoValidationResult) IsWarningState() bool {
+	return errors.Is(rivr.err, ErrRenewalWindowStarted) && !rivr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (rivr RenewalInfoValidationResult) IsCriticalState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state. This is the case if renewal information could not be
+// retrieved.
+func (rivr RenewalInfoValidationResult) IsUnknownState() bool {
+	return rivr.err != nil &&
+		!errors.Is(rivr.err, ErrRenewalWindowStarted) &&
+		!rivr.IsIgnored()
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (rivr RenewalInfoValidationResult) IsOKState() bool {
+	return rivr.err == nil || rivr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (rivr RenewalInfoValidationResult) IsIgnored() bool {
+	return rivr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (rivr RenewalInfoValidationResult) IsSucceeded() bool {
+	return rivr.IsOKState() && !rivr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (rivr RenewalInfoValidationResult) IsFailed() bool {
+	return rivr.err != nil && !rivr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (rivr RenewalInfoValidationResult) Err() error {
+	return rivr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (rivr RenewalInfoValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(rivr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (rivr RenewalInfoValidationResult) Priority() int {
+	switch {
+	case rivr.ignored:
+		return baselinePriorityRenewalInfoValidationResult
+	default:
+		return baselinePriorityRenewalInfoValidationResult + rivr.priorityModifier
+	}
+}
+
+// RenewalInfo returns the renewal window suggested by the Certificate
+// Authority for the evaluated leaf certificate. This is the zero value if
+// renewal information was not retrieved.
+func (rivr RenewalInfoValidationResult) RenewalInfo() RenewalInfo {
+	return rivr.renewalInfo
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (rivr RenewalInfoValidationResult) Overview() string {
+	if rivr.renewalInfo.WindowStart.IsZero() {
+		return "[RENEWAL WINDOW: UNKNOWN]"
+	}
+
+	return fmt.Sprintf(
+		"[RENEWAL WINDOW: %s to %s]",
+		rivr.renewalInfo.WindowStart.Format(CertValidityDateLayout),
+		rivr.renewalInfo.WindowEnd.Format(CertValidityDateLayout),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (rivr RenewalInfoValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// Renewal information could not be retrieved (e.g., the ACME server is
+	// unreachable) or was not requested in order to remain within the
+	// network budget.
+	case rivr.IsIgnored() && rivr.err != nil:
+		status = fmt.Sprintf(
+			"%s validation ignored: %v",
+			rivr.CheckName(),
+			rivr.err,
+		)
+
+	// User opted to ignore validation check results.
+	case rivr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			rivr.CheckName(),
+		)
+
+	case errors.Is(rivr.err, ErrRenewalWindowStarted) &&
+		!time.Now().Before(rivr.renewalInfo.WindowEnd):
+		status = fmt.Sprintf(
+			"%s validation failed: CA-suggested renewal window for %s cert %q ended %s; renewal is overdue",
+			rivr.CheckName(),
+			ChainPosition(rivr.leafCert, rivr.certChain),
+			rivr.leafCert.Subject.CommonName,
+			FormattedExpiration(rivr.renewalInfo.WindowEnd),
+		)
+
+	case errors.Is(rivr.err, ErrRenewalWindowStarted):
+		status = fmt.Sprintf(
+			"%s validation failed: CA-suggested renewal window for %s cert %q started %s; renewal pending",
+			rivr.CheckName(),
+			ChainPosition(rivr.leafCert, rivr.certChain),
+			rivr.leafCert.Subject.CommonName,
+			FormattedExpiration(rivr.renewalInfo.WindowStart),
+		)
+
+	case rivr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered retrieving renewal information for leaf certificate: %v",
+			rivr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: CA-suggested renewal window for %s cert %q starts %s (%s)",
+			rivr.CheckName(),
+			ChainPosition(rivr.leafCert, rivr.certChain),
+			rivr.leafCert.Subject.CommonName,
+			rivr.renewalInfo.WindowStart.Format(CertValidityDateLayout),
+			FormattedExpiration(rivr.renewalInfo.WindowStart),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (rivr RenewalInfoValidationResult) StatusDetail() string {
+	if rivr.renewalInfo.ExplanationURL == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"explanation provided by CA: %s",
+		rivr.renewalInfo.ExplanationURL,
+	)
+}
+
+// String provides the validation check result in human-readable format.
+func (rivr RenewalInfoValidationResult) String() string {
+	output := fmt.Sprintf(
+		"%s %s",
+		rivr.Status(),
+		rivr.Overview(),
+	)
+
+	if rivr.StatusDetail() != "" {
+		output += ": " + rivr.StatusDetail()
+	}
+
+	return output
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (rivr RenewalInfoValidationResult) Report() string {
+	return rivr.String()
+}
+
+// ValidationStatus provides a one word status value for renewal information
+// validation check results.
+func (rivr RenewalInfoValidationResult) ValidationStatus() string {
+	switch {
+	case rivr.IsFailed():
+		return ValidationStatusFailed
+	case rivr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
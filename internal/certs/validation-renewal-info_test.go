@@ -0,0 +1,99 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateRenewalInfo(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+	leaf, intermediate, root := certChain[0], certChain[1], certChain[2]
+
+	windowPending := RenewalInfo{
+		WindowStart: time.Now().Add(30 * 24 * time.Hour),
+		WindowEnd:   time.Now().Add(32 * 24 * time.Hour),
+	}
+
+	windowStarted := RenewalInfo{
+		WindowStart: time.Now().Add(-time.Hour),
+		WindowEnd:   time.Now().Add(24 * time.Hour),
+	}
+
+	tests := []struct {
+		name        string
+		certChain   []*x509.Certificate
+		renewalInfo RenewalInfo
+		lookupErr   error
+		wantLeaf    *x509.Certificate
+		err         error
+	}{
+		{
+			name:        "WindowPending",
+			certChain:   certChain,
+			renewalInfo: windowPending,
+			wantLeaf:    leaf,
+		},
+		{
+			name:        "WindowStarted",
+			certChain:   certChain,
+			renewalInfo: windowStarted,
+			wantLeaf:    leaf,
+			err:         ErrRenewalWindowStarted,
+		},
+		{
+			name:        "LeafNotFirstInChain",
+			certChain:   []*x509.Certificate{intermediate, leaf, root},
+			renewalInfo: windowStarted,
+			wantLeaf:    leaf,
+			err:         ErrRenewalWindowStarted,
+		},
+		{
+			name:      "LookupFailed",
+			certChain: certChain,
+			lookupErr: errors.New("connection refused"),
+			wantLeaf:  leaf,
+			err:       ErrRenewalInfoLookupFailed,
+		},
+		{
+			name:        "LeafMissing",
+			certChain:   []*x509.Certificate{intermediate, root},
+			renewalInfo: windowStarted,
+			err:         ErrIncompleteCertificateChain,
+		},
+		{
+			name: "EmptyChain",
+			err:  ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateRenewalInfo(tt.certChain, tt.renewalInfo, tt.lookupErr, CertChainValidationOptions{})
+
+			switch {
+			case tt.err == nil && result.Err() != nil:
+				t.Errorf("want no error; got %v", result.Err())
+			case !errors.Is(result.Err(), tt.err):
+				t.Errorf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if result.leafCert != tt.wantLeaf {
+				t.Error("evaluated certificate is not the expected leaf certificate")
+			}
+		})
+	}
+}
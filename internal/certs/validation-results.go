@@ -22,6 +22,7 @@ const (
 	ValidationStatusFailed     string = "failed"
 	ValidationStatusIgnored    string = "ignored"
 	ValidationStatusSuccessful string = "successful"
+	ValidationStatusSkipped    string = "skipped"
 )
 
 // CertChainValidationResult represents the result for a validation check
@@ -0,0 +1,276 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*SelfSignedValidationResult)(nil)
+
+// SelfSignedValidationResult is the validation result from evaluating
+// whether the leaf certificate in a chain is self-signed.
+type SelfSignedValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// leafCert is the leaf certificate from the chain that we evaluated to
+	// produce this validation check result.
+	leafCert *x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+}
+
+// ValidateSelfSigned asserts that the leaf certificate for a given
+// certificate chain is not self-signed (e.g., a production endpoint serving
+// a placeholder certificate instead of a CA-issued one). If specified, this
+// validation check result is ignored.
+func ValidateSelfSigned(
+	certChain []*x509.Certificate,
+	validationOptions CertChainValidationOptions,
+) SelfSignedValidationResult {
+
+	leafCerts := LeafCerts(certChain)
+	if len(leafCerts) == 0 {
+		return SelfSignedValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			err: fmt.Errorf(
+				"leaf certificate not found in certificate chain: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultSelfSigned,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	leafCert := leafCerts[0]
+
+	result := SelfSignedValidationResult{
+		certChain:         certChain,
+		leafCert:          leafCert,
+		validationOptions: validationOptions,
+		ignored:           validationOptions.IgnoreValidationResultSelfSigned,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if isSelfSigned(leafCert) {
+		result.err = ErrCertIsSelfSigned
+		result.priorityModifier = priorityModifierMaximum
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (ssvr SelfSignedValidationResult) CheckName() string {
+	return checkNameSelfSignedValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (ssvr SelfSignedValidationResult) CertChain() []*x509.Certificate {
+	return ssvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (ssvr SelfSignedValidationResult) TotalCerts() int {
+	return len(ssvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (ssvr SelfSignedValidationResult) IsWarningState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (ssvr SelfSignedValidationResult) IsCriticalState() bool {
+	return ssvr.err != nil && !ssvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (ssvr SelfSignedValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (ssvr SelfSignedValidationResult) IsOKState() bool {
+	return ssvr.err == nil || ssvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (ssvr SelfSignedValidationResult) IsIgnored() bool {
+	return ssvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (ssvr SelfSignedValidationResult) IsSucceeded() bool {
+	return ssvr.IsOKState() && !ssvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (ssvr SelfSignedValidationResult) IsFailed() bool {
+	return ssvr.err != nil && !ssvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (ssvr SelfSignedValidationResult) Err() error {
+	return ssvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (ssvr SelfSignedValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(ssvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (ssvr SelfSignedValidationResult) Priority() int {
+	switch {
+	case ssvr.ignored:
+		return baselinePrioritySelfSignedValidationResult
+	default:
+		return baselinePrioritySelfSignedValidationResult + ssvr.priorityModifier
+	}
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (ssvr SelfSignedValidationResult) Overview() string {
+	if ssvr.leafCert == nil {
+		return "[ISSUER: N/A]"
+	}
+
+	return fmt.Sprintf(
+		"[ISSUER: %s]",
+		ssvr.leafCert.Issuer,
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (ssvr SelfSignedValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case ssvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			ssvr.CheckName(),
+		)
+
+	case errors.Is(ssvr.err, ErrCertIsSelfSigned):
+		status = fmt.Sprintf(
+			"%s validation failed: %s cert %q is self-signed",
+			ssvr.CheckName(),
+			ChainPosition(ssvr.leafCert, ssvr.certChain),
+			ssvr.leafCert.Subject.CommonName,
+		)
+
+	case ssvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered checking for self-signed leaf certificate: %v",
+			ssvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: %s cert is not self-signed",
+			ssvr.CheckName(),
+			ChainPosition(ssvr.leafCert, ssvr.certChain),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (ssvr SelfSignedValidationResult) StatusDetail() string {
+	// The overview already provides the issuer; there is nothing further to
+	// add.
+	return ""
+}
+
+// String provides the validation check result in human-readable format.
+func (ssvr SelfSignedValidationResult) String() string {
+	return fmt.Sprintf(
+		"%s %s",
+		ssvr.Status(),
+		ssvr.Overview(),
+	)
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (ssvr SelfSignedValidationResult) Report() string {
+	return ssvr.String()
+}
+
+// ValidationStatus provides a one word status value for self-signed leaf
+// certificate validation check results.
+func (ssvr SelfSignedValidationResult) ValidationStatus() string {
+	switch {
+	case ssvr.IsFailed():
+		return ValidationStatusFailed
+	case ssvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
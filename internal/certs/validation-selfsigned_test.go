@@ -0,0 +1,58 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"testing"
+)
+
+func TestValidateSelfSigned(t *testing.T) {
+	t.Parallel()
+
+	selfSigned := newTestCert(t, "www.example.com", false, nil, nil)
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		err       error
+	}{
+		{
+			name:      "CAIssuedLeaf",
+			certChain: newTestChain(t, "www.example.com"),
+		},
+		{
+			name:      "SelfSignedLeaf",
+			certChain: []*x509.Certificate{selfSigned.cert},
+			err:       ErrCertIsSelfSigned,
+		},
+		{
+			name:      "EmptyChain",
+			certChain: nil,
+			err:       ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateSelfSigned(tt.certChain, CertChainValidationOptions{})
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got, want := result.IsCriticalState(), tt.err != nil; got != want {
+				t.Errorf("want CRITICAL state %t; got %t", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*SerialNumberValidationResult)(nil)
+
+// SerialNumberValidationResult is the validation result from comparing the
+// serial number of a leaf certificate in a chain against a sysadmin-provided
+// expected value.
+type SerialNumberValidationResult struct {
+	// certChain is the collection of certificates that we evaluated to
+	// produce this validation check result.
+	certChain []*x509.Certificate
+
+	// leafCert is the leaf certificate from the chain that we evaluated to
+	// produce this validation check result.
+	leafCert *x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// expectedSerial is the serial number value that the sysadmin has stated
+	// the leaf certificate is required to have. This value is recorded as
+	// given.
+	expectedSerial string
+}
+
+// NormalizeSerialNumber converts a given serial number string into a
+// consistent format suitable for comparison purposes. Delimiters (colons,
+// dashes, spaces), an optional hex prefix and leading zeros are removed and
+// the result is returned in uppercase.
+//
+// Example: "0a:fd:50:2b" becomes "AFD502B"
+func NormalizeSerialNumber(serial string) string {
+	serial = strings.TrimSpace(serial)
+	serial = strings.TrimPrefix(strings.ToLower(serial), "0x")
+
+	serial = strings.NewReplacer(
+		":", "",
+		"-", "",
+		" ", "",
+	).Replace(serial)
+
+	serial = strings.TrimLeft(serial, "0")
+
+	return strings.ToUpper(serial)
+}
+
+// IsValidSerialNumber indicates whether the given serial number string is in
+// a supported format (hex digits with optional delimiters).
+func IsValidSerialNumber(serial string) bool {
+	normalized := NormalizeSerialNumber(serial)
+	if normalized == "" {
+		// A serial number consisting solely of zeros is technically valid
+		// but an empty value is not.
+		return strings.Contains(serial, "0")
+	}
+
+	_, ok := new(big.Int).SetString(normalized, 16)
+
+	return ok
+}
+
+// ValidateSerialNumber asserts that the leaf certificate for a given
+// certificate chain has the specified serial number. If specified, this
+// validation check result is ignored.
+func ValidateSerialNumber(
+	certChain []*x509.Certificate,
+	expectedSerial string,
+	validationOptions CertChainValidationOptions,
+) SerialNumberValidationResult {
+
+	leafCerts := LeafCerts(certChain)
+
+	// Early exit logic.
+	switch {
+	case len(leafCerts) == 0:
+		return SerialNumberValidationResult{
+			certChain:         certChain,
+			validationOptions: validationOptions,
+			expectedSerial:    expectedSerial,
+			err: fmt.Errorf(
+				"leaf certificate not found in certificate chain: %w",
+				ErrIncompleteCertificateChain,
+			),
+			ignored:          validationOptions.IgnoreValidationResultSerialNumber,
+			priorityModifier: priorityModifierMaximum,
+		}
+
+	// If no serial number was provided we are unable to perform validation.
+	//
+	// NOTE: Config validation is expected to prevent explicitly applying
+	// this validation check without an expected value.
+	case strings.TrimSpace(expectedSerial) == "":
+		return SerialNumberValidationResult{
+			certChain:         certChain,
+			leafCert:          leafCerts[0],
+			validationOptions: validationOptions,
+			expectedSerial:    expectedSerial,
+			err: fmt.Errorf(
+				"expected serial number not provided: %w",
+				ErrMissingValue,
+			),
+			ignored:          validationOptions.IgnoreValidationResultSerialNumber,
+			priorityModifier: priorityModifierMaximum,
+		}
+	}
+
+	leafCert := leafCerts[0]
+
+	if NormalizeSerialNumber(expectedSerial) != NormalizeSerialNumber(FormatCertSerialNumber(leafCert.SerialNumber)) {
+		return SerialNumberValidationResult{
+			certChain:         certChain,
+			leafCert:          leafCert,
+			validationOptions: validationOptions,
+			expectedSerial:    expectedSerial,
+			err:               ErrCertSerialNumberMismatch,
+			ignored:           validationOptions.IgnoreValidationResultSerialNumber,
+			priorityModifier:  priorityModifierMaximum,
+		}
+	}
+
+	return SerialNumberValidationResult{
+		certChain:         certChain,
+		leafCert:          leafCert,
+		validationOptions: validationOptions,
+		expectedSerial:    expectedSerial,
+		ignored:           validationOptions.IgnoreValidationResultSerialNumber,
+		priorityModifier:  priorityModifierBaseline,
+	}
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (snvr SerialNumberValidationResult) CheckName() string {
+	return checkNameSerialNumberValidationResult
+}
+
+// CertChain returns the evaluated certificate chain.
+func (snvr SerialNumberValidationResult) CertChain() []*x509.Certificate {
+	return snvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the evaluated certificate
+// chain.
+func (snvr SerialNumberValidationResult) TotalCerts() int {
+	return len(snvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or CRITICAL state, or is flagged as ignored. True is returned otherwise.
+func (snvr SerialNumberValidationResult) IsWarningState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (snvr SerialNumberValidationResult) IsCriticalState() bool {
+	return snvr.err != nil && !snvr.IsIgnored()
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state.
+func (snvr SerialNumberValidationResult) IsUnknownState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (snvr SerialNumberValidationResult) IsOKState() bool {
+	return snvr.err == nil || snvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (snvr SerialNumberValidationResult) IsIgnored() bool {
+	return snvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+func (snvr SerialNumberValidationResult) IsSucceeded() bool {
+	return snvr.IsOKState() && !snvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (snvr SerialNumberValidationResult) IsFailed() bool {
+	return snvr.err != nil && !snvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (snvr SerialNumberValidationResult) Err() error {
+	return snvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (snvr SerialNumberValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(snvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (snvr SerialNumberValidationResult) Priority() int {
+	switch {
+	case snvr.ignored:
+		return baselinePrioritySerialNumberValidationResult
+	default:
+		return baselinePrioritySerialNumberValidationResult + snvr.priorityModifier
+	}
+}
+
+// ExpectedSerial returns the user-specified serial number value in the
+// OpenSSL text format used throughout this project.
+func (snvr SerialNumberValidationResult) ExpectedSerial() string {
+	normalized := NormalizeSerialNumber(snvr.expectedSerial)
+	if normalized == "" {
+		return "N/A"
+	}
+
+	sn, ok := new(big.Int).SetString(normalized, 16)
+	if !ok {
+		return snvr.expectedSerial
+	}
+
+	return FormatCertSerialNumber(sn)
+}
+
+// ActualSerial returns the serial number of the evaluated leaf certificate.
+func (snvr SerialNumberValidationResult) ActualSerial() string {
+	if snvr.leafCert == nil {
+		return "N/A"
+	}
+
+	return FormatCertSerialNumber(snvr.leafCert.SerialNumber)
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (snvr SerialNumberValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[EXPECTED: %s, FOUND: %s]",
+		snvr.ExpectedSerial(),
+		snvr.ActualSerial(),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (snvr SerialNumberValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case snvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			snvr.CheckName(),
+		)
+
+	case errors.Is(snvr.err, ErrCertSerialNumberMismatch):
+		status = fmt.Sprintf(
+			"%s validation failed: %s cert %s",
+			snvr.CheckName(),
+			ChainPosition(snvr.leafCert, snvr.certChain),
+			snvr.Err(),
+		)
+
+	case snvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered validating expected serial number: %v",
+			snvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: %s cert has expected serial number",
+			snvr.CheckName(),
+			ChainPosition(snvr.leafCert, snvr.certChain),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (snvr SerialNumberValidationResult) StatusDetail() string {
+	// The overview already provides the expected and actual values; there is
+	// nothing further to add.
+	return ""
+}
+
+// String provides the validation check result in human-readable format.
+func (snvr SerialNumberValidationResult) String() string {
+	return fmt.Sprintf(
+		"%s %s",
+		snvr.Status(),
+		snvr.Overview(),
+	)
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (snvr SerialNumberValidationResult) Report() string {
+	return snvr.String()
+}
+
+// ValidationStatus provides a one word status value for serial number
+// validation check results.
+func (snvr SerialNumberValidationResult) ValidationStatus() string {
+	switch {
+	case snvr.IsFailed():
+		return ValidationStatusFailed
+	case snvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestNormalizeSerialNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		serial string
+		want   string
+	}{
+		{
+			name:   "ColonSeparated",
+			serial: "0a:fd:50:2b",
+			want:   "AFD502B",
+		},
+		{
+			name:   "SpaceSeparated",
+			serial: "0a fd 50 2b",
+			want:   "AFD502B",
+		},
+		{
+			name:   "DashSeparated",
+			serial: "0a-fd-50-2b",
+			want:   "AFD502B",
+		},
+		{
+			name:   "MixedCase",
+			serial: "0A:fD:50:2b",
+			want:   "AFD502B",
+		},
+		{
+			name:   "LeadingZeros",
+			serial: "00:00:0a:fd",
+			want:   "AFD",
+		},
+		{
+			name:   "HexPrefix",
+			serial: "0xAFD502B",
+			want:   "AFD502B",
+		},
+		{
+			name:   "SurroundingWhitespace",
+			serial: "  afd502b\n",
+			want:   "AFD502B",
+		},
+		{
+			name:   "AllZeros",
+			serial: "00:00",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NormalizeSerialNumber(tt.serial); got != tt.want {
+				t.Errorf("want %q; got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateSerialNumber(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+	leaf := newTestCert(t, "www.example.com", false, root, func(c *x509.Certificate) {
+		c.SerialNumber = big.NewInt(0x0AFD502B)
+	})
+	certChain := []*x509.Certificate{leaf.cert, root.cert}
+
+	tests := []struct {
+		name      string
+		certChain []*x509.Certificate
+		expected  string
+		options   CertChainValidationOptions
+		err       error
+		wantOK    bool
+	}{
+		{
+			name:      "MatchColonSeparated",
+			certChain: certChain,
+			expected:  "0A:FD:50:2B",
+			wantOK:    true,
+		},
+		{
+			name:      "MatchSpaceSeparatedMixedCase",
+			certChain: certChain,
+			expected:  "0a Fd 50 2B",
+			wantOK:    true,
+		},
+		{
+			name:      "MatchWithoutLeadingZeros",
+			certChain: certChain,
+			expected:  "afd502b",
+			wantOK:    true,
+		},
+		{
+			name:      "Mismatch",
+			certChain: certChain,
+			expected:  "0A:FD:50:2C",
+			err:       ErrCertSerialNumberMismatch,
+		},
+		{
+			name:      "MismatchIgnored",
+			certChain: certChain,
+			expected:  "0A:FD:50:2C",
+			options:   CertChainValidationOptions{IgnoreValidationResultSerialNumber: true},
+			err:       ErrCertSerialNumberMismatch,
+			wantOK:    true,
+		},
+		{
+			name:      "MissingExpectedValue",
+			certChain: certChain,
+			expected:  " ",
+			err:       ErrMissingValue,
+		},
+		{
+			name:      "NoLeafCert",
+			certChain: []*x509.Certificate{root.cert},
+			expected:  "0A:FD:50:2B",
+			err:       ErrIncompleteCertificateChain,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateSerialNumber(tt.certChain, tt.expected, tt.options)
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got := result.IsOKState(); got != tt.wantOK {
+				t.Errorf("want OK state %t; got %t: %s", tt.wantOK, got, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*SeverityLimitedValidationResult)(nil)
+
+// SeverityLimitedValidationResult wraps a validation check result in order
+// to limit the most severe state that the validation check result may
+// contribute when determining final validation state (e.g., a failing
+// hostname validation check contributes a WARNING state instead of
+// CRITICAL). All other behavior is provided by the wrapped validation check
+// result.
+type SeverityLimitedValidationResult struct {
+	CertChainValidationResult
+
+	// maxState is the most severe service check state label that this
+	// validation check result may report.
+	maxState string
+}
+
+// NewSeverityLimitedValidationResult limits the given validation check
+// result to the specified maximum service check state. Only the WARNING
+// state is currently supported as a limit; the given validation check result
+// is returned unmodified for any other value.
+func NewSeverityLimitedValidationResult(
+	result CertChainValidationResult,
+	maxState string,
+) CertChainValidationResult {
+	maxState = strings.ToUpper(maxState)
+
+	if maxState != nagios.StateWARNINGLabel {
+		return result
+	}
+
+	return SeverityLimitedValidationResult{
+		CertChainValidationResult: result,
+		maxState:                  maxState,
+	}
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. A CRITICAL state for the wrapped validation check result is
+// reported as WARNING.
+func (slvr SeverityLimitedValidationResult) IsWarningState() bool {
+	return slvr.CertChainValidationResult.IsWarningState() ||
+		slvr.CertChainValidationResult.IsCriticalState()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This is always false as the state is limited to WARNING.
+func (slvr SeverityLimitedValidationResult) IsCriticalState() bool {
+	return false
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (slvr SeverityLimitedValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(slvr)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text. A note is included if the state of
+// the wrapped validation check result was limited.
+func (slvr SeverityLimitedValidationResult) Status() string {
+	if !slvr.CertChainValidationResult.IsCriticalState() {
+		return slvr.CertChainValidationResult.Status()
+	}
+
+	return fmt.Sprintf(
+		"%s [SEVERITY LIMITED TO %s]",
+		slvr.CertChainValidationResult.Status(),
+		slvr.maxState,
+	)
+}
+
+// Report provides the validation check result in verbose human-readable
+// format. A note is included if the state of the wrapped validation check
+// result was limited.
+func (slvr SeverityLimitedValidationResult) Report() string {
+	if !slvr.CertChainValidationResult.IsCriticalState() {
+		return slvr.CertChainValidationResult.Report()
+	}
+
+	return fmt.Sprintf(
+		"%s%sNOTE: %s state limited to %s by request.",
+		slvr.CertChainValidationResult.Report(),
+		nagios.CheckOutputEOL,
+		nagios.StateCRITICALLabel,
+		slvr.maxState,
+	)
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestNewSeverityLimitedValidationResult(t *testing.T) {
+	t.Parallel()
+
+	root := newTestCert(t, "Test Root CA", true, nil, nil)
+
+	// ekuResult returns an Extended Key Usage validation check result for a
+	// leaf certificate using the given Extended Key Usage values. The result
+	// is in a CRITICAL state if serverAuth is missing and in a WARNING state
+	// if codeSigning is present.
+	ekuResult := func(t *testing.T, ekus ...x509.ExtKeyUsage) CertChainValidationResult {
+		leaf := newTestCert(t, "www.example.com", false, root, func(c *x509.Certificate) {
+			c.ExtKeyUsage = ekus
+		})
+
+		return ValidateExtKeyUsage(
+			[]*x509.Certificate{leaf.cert, root.cert},
+			[]string{ExtKeyUsageKeywordServerAuth},
+			[]string{ExtKeyUsageKeywordCodeSigning},
+			CertChainValidationOptions{},
+		)
+	}
+
+	critical := ekuResult(t, x509.ExtKeyUsageClientAuth)
+	warning := ekuResult(t, x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageCodeSigning)
+	ok := ekuResult(t, x509.ExtKeyUsageServerAuth)
+
+	tests := []struct {
+		name        string
+		result      CertChainValidationResult
+		maxState    string
+		wantState   string
+		wantLimited bool
+	}{
+		{
+			name:        "CriticalLimitedToWarning",
+			result:      critical,
+			maxState:    nagios.StateWARNINGLabel,
+			wantState:   nagios.StateWARNINGLabel,
+			wantLimited: true,
+		},
+		{
+			name:        "CriticalLimitedToLowercaseWarning",
+			result:      critical,
+			maxState:    "warning",
+			wantState:   nagios.StateWARNINGLabel,
+			wantLimited: true,
+		},
+		{
+			name:      "WarningUnchanged",
+			result:    warning,
+			maxState:  nagios.StateWARNINGLabel,
+			wantState: nagios.StateWARNINGLabel,
+		},
+		{
+			name:      "OKUnchanged",
+			result:    ok,
+			maxState:  nagios.StateWARNINGLabel,
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:      "UnsupportedCriticalLimit",
+			result:    critical,
+			maxState:  nagios.StateCRITICALLabel,
+			wantState: nagios.StateCRITICALLabel,
+		},
+		{
+			name:      "UnsupportedOKLimit",
+			result:    critical,
+			maxState:  nagios.StateOKLabel,
+			wantState: nagios.StateCRITICALLabel,
+		},
+		{
+			name:      "UnsupportedUnknownLimit",
+			result:    critical,
+			maxState:  nagios.StateUNKNOWNLabel,
+			wantState: nagios.StateCRITICALLabel,
+		},
+		{
+			name:      "EmptyLimit",
+			result:    critical,
+			wantState: nagios.StateCRITICALLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := NewSeverityLimitedValidationResult(tt.result, tt.maxState)
+
+			if got := result.ServiceState().Label; got != tt.wantState {
+				t.Errorf("want state %s; got %s", tt.wantState, got)
+			}
+
+			if result.IsCriticalState() && tt.wantState != nagios.StateCRITICALLabel {
+				t.Errorf("want no CRITICAL state; got CRITICAL: %s", result.Status())
+			}
+
+			limited := strings.Contains(result.Status(), "SEVERITY LIMITED")
+			if limited != tt.wantLimited {
+				t.Errorf("want severity limited note %t; got %t: %s", tt.wantLimited, limited, result.Status())
+			}
+
+			if result.CheckName() != tt.result.CheckName() {
+				t.Errorf("want check name %q; got %q", tt.result.CheckName(), result.CheckName())
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*SkippedValidationResult)(nil)
+
+// ErrValidationSkipped indicates that a validation check was not performed.
+var ErrValidationSkipped = errors.New("validation check skipped")
+
+// SkippedValidationResult is the validation result recorded in place of a
+// validation check which was not performed (e.g., because the time allowed
+// for the plugin to complete is nearly exhausted). Skipped validation check
+// results are treated as ignored for the purposes of determining final
+// validation state.
+type SkippedValidationResult struct {
+	// certChain is the collection of certificates that would have been
+	// evaluated by the skipped validation check.
+	certChain []*x509.Certificate
+
+	// checkName is the human-readable name of the skipped validation check.
+	checkName string
+
+	// reason is a brief explanation of why the validation check was
+	// skipped.
+	reason string
+
+	// err describes the skipped validation attempt.
+	err error
+}
+
+// NewSkippedValidationResult creates a validation result for the named
+// validation check which was not performed for the given reason.
+func NewSkippedValidationResult(
+	certChain []*x509.Certificate,
+	checkName string,
+	reason string,
+) SkippedValidationResult {
+	return SkippedValidationResult{
+		certChain: certChain,
+		checkName: checkName,
+		reason:    reason,
+		err: fmt.Errorf(
+			"%s validation skipped (%s): %w",
+			checkName,
+			reason,
+			ErrValidationSkipped,
+		),
+	}
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (svr SkippedValidationResult) CheckName() string {
+	return svr.checkName
+}
+
+// CertChain returns the certificate chain which would have been evaluated.
+func (svr SkippedValidationResult) CertChain() []*x509.Certificate {
+	return svr.certChain
+}
+
+// TotalCerts returns the number of certificates in the certificate chain
+// which would have been evaluated.
+func (svr SkippedValidationResult) TotalCerts() int {
+	return len(svr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. Skipped validation checks are never in a WARNING state.
+func (svr SkippedValidationResult) IsWarningState() bool {
+	return false
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. Skipped validation checks are never in a CRITICAL state.
+func (svr SkippedValidationResult) IsCriticalState() bool {
+	return false
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state. Skipped validation checks are never in an UNKNOWN state.
+func (svr SkippedValidationResult) IsUnknownState() bool {
+	return false
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. Skipped validation checks are treated as ignored and are
+// therefore considered to be a subset of OK status.
+func (svr SkippedValidationResult) IsOKState() bool {
+	return true
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state. This is
+// always true for skipped validation checks.
+func (svr SkippedValidationResult) IsIgnored() bool {
+	return true
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the certificate chain were identified.
+// This is always false for skipped validation checks.
+func (svr SkippedValidationResult) IsSucceeded() bool {
+	return false
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified. This is always false for skipped
+// validation checks.
+func (svr SkippedValidationResult) IsFailed() bool {
+	return false
+}
+
+// Err returns the error describing why the validation check was skipped.
+func (svr SkippedValidationResult) Err() error {
+	return svr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (svr SkippedValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(svr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result. Skipped validation checks are given the lowest priority.
+func (svr SkippedValidationResult) Priority() int {
+	return priorityModifierBaseline
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (svr SkippedValidationResult) Overview() string {
+	return fmt.Sprintf("[REASON: %s]", svr.reason)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (svr SkippedValidationResult) Status() string {
+	return fmt.Sprintf(
+		"%s validation %s (%s)",
+		svr.CheckName(),
+		svr.ValidationStatus(),
+		svr.reason,
+	)
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. There are no further details for skipped
+// validation checks.
+func (svr SkippedValidationResult) StatusDetail() string {
+	return ""
+}
+
+// String provides the validation check result in human-readable format.
+func (svr SkippedValidationResult) String() string {
+	return svr.Status()
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (svr SkippedValidationResult) Report() string {
+	return svr.String()
+}
+
+// ValidationStatus provides a one word status value for skipped validation
+// check results.
+func (svr SkippedValidationResult) ValidationStatus() string {
+	return ValidationStatusSkipped
+}
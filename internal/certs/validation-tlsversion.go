@@ -0,0 +1,300 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/go-nagios"
+)
+
+// Add an "implements assertion" to fail the build if the interface
+// implementation isn't correct.
+var _ CertChainValidationResult = (*MinTLSVersionValidationResult)(nil)
+
+// MinTLSVersionValidationResult is the validation result from probing the
+// server providing a certificate chain for acceptance of TLS protocol
+// versions older than a required minimum version.
+type MinTLSVersionValidationResult struct {
+	// certChain is the collection of certificates retrieved from the server
+	// that we probed to produce this validation check result.
+	certChain []*x509.Certificate
+
+	// err is the "final" error describing the validation attempt.
+	err error
+
+	// priorityModifier is applied when calculating the priority for a
+	// validation check result. If a validation check result has an associated
+	// error but is flagged as ignored then the base priority value is used
+	// and this modifier is ignored.
+	//
+	// If the validation check is not flagged as ignored than this modifier is
+	// used to calculate the final priority level.
+	priorityModifier int
+
+	// ignored indicates whether validation check results are ignored for the
+	// certificate chain.
+	ignored bool
+
+	// validationOptions tracks what validation options were chosen by the
+	// sysadmin.
+	validationOptions CertChainValidationOptions
+
+	// minVersion is the oldest TLS protocol version the server is permitted
+	// to negotiate.
+	minVersion uint16
+
+	// legacyVersions is the collection of TLS protocol versions older than
+	// the minimum version which the server negotiated.
+	legacyVersions []uint16
+}
+
+// ValidateMinTLSVersion asserts that the server providing the given
+// certificate chain did not negotiate any of the given TLS protocol versions
+// older than the specified minimum version. The given probe error, if any,
+// is recorded as the reason that validation could not be performed. If
+// specified, this validation check result is ignored.
+func ValidateMinTLSVersion(
+	certChain []*x509.Certificate,
+	minVersion uint16,
+	acceptedVersions []uint16,
+	probeErr error,
+	validationOptions CertChainValidationOptions,
+) MinTLSVersionValidationResult {
+
+	result := MinTLSVersionValidationResult{
+		certChain:         certChain,
+		validationOptions: validationOptions,
+		minVersion:        minVersion,
+		ignored:           validationOptions.IgnoreValidationResultMinTLSVersion,
+		priorityModifier:  priorityModifierBaseline,
+	}
+
+	if probeErr != nil {
+		result.err = fmt.Errorf("%w: %w", ErrTLSVersionProbeFailed, probeErr)
+
+		// An unreachable server says nothing about the accepted protocol
+		// versions, so this is not given precedence over other validation
+		// check results.
+		result.priorityModifier = priorityModifierMinimum
+
+		return result
+	}
+
+	for _, version := range acceptedVersions {
+		if version < minVersion {
+			result.legacyVersions = append(result.legacyVersions, version)
+		}
+	}
+
+	if len(result.legacyVersions) > 0 {
+		result.err = ErrLegacyTLSVersionAccepted
+		result.priorityModifier = priorityModifierMedium
+	}
+
+	return result
+}
+
+// CheckName emits the human-readable name of this validation check result.
+func (mtvr MinTLSVersionValidationResult) CheckName() string {
+	return checkNameMinTLSVersionValidationResult
+}
+
+// CertChain returns the certificate chain retrieved from the probed server.
+func (mtvr MinTLSVersionValidationResult) CertChain() []*x509.Certificate {
+	return mtvr.certChain
+}
+
+// TotalCerts returns the number of certificates in the certificate chain
+// retrieved from the probed server.
+func (mtvr MinTLSVersionValidationResult) TotalCerts() int {
+	return len(mtvr.certChain)
+}
+
+// IsWarningState indicates whether this validation check result is in a
+// WARNING state. This returns false if the validation check resulted in an OK
+// or UNKNOWN state, or is flagged as ignored. True is returned otherwise.
+func (mtvr MinTLSVersionValidationResult) IsWarningState() bool {
+	return errors.Is(mtvr.err, ErrLegacyTLSVersionAccepted) && !mtvr.IsIgnored()
+}
+
+// IsCriticalState indicates whether this validation check result is in a
+// CRITICAL state. This returns false if the validation check resulted in an
+// OK or WARNING state, or is flagged as ignored. True is returned otherwise.
+func (mtvr MinTLSVersionValidationResult) IsCriticalState() bool {
+	// This state is not used for this certificate validation check.
+	return false
+}
+
+// IsUnknownState indicates whether this validation check result is in an
+// UNKNOWN state. This is the case if the server could not be probed.
+func (mtvr MinTLSVersionValidationResult) IsUnknownState() bool {
+	return errors.Is(mtvr.err, ErrTLSVersionProbeFailed) && !mtvr.IsIgnored()
+}
+
+// IsOKState indicates whether this validation check result is in an OK or
+// passing state. For the purposes of validation check evaluation, ignored
+// validation checks are considered to be a subset of OK status.
+func (mtvr MinTLSVersionValidationResult) IsOKState() bool {
+	return mtvr.err == nil || mtvr.IsIgnored()
+}
+
+// IsIgnored indicates whether this validation check result was flagged as
+// ignored for the purposes of determining final validation state.
+func (mtvr MinTLSVersionValidationResult) IsIgnored() bool {
+	return mtvr.ignored
+}
+
+// IsSucceeded indicates whether this validation check result is not flagged
+// as ignored and no problems with the server were identified.
+func (mtvr MinTLSVersionValidationResult) IsSucceeded() bool {
+	return mtvr.IsOKState() && !mtvr.IsIgnored()
+}
+
+// IsFailed indicates whether this validation check result is not flagged as
+// ignored and problems were identified.
+func (mtvr MinTLSVersionValidationResult) IsFailed() bool {
+	return mtvr.err != nil && !mtvr.IsIgnored()
+}
+
+// Err returns the underlying error (if any) regardless of whether this
+// validation check result is flagged as ignored.
+func (mtvr MinTLSVersionValidationResult) Err() error {
+	return mtvr.err
+}
+
+// ServiceState returns the appropriate Service Check Status label and exit
+// code for this validation check result.
+func (mtvr MinTLSVersionValidationResult) ServiceState() nagios.ServiceState {
+	return ServiceState(mtvr)
+}
+
+// Priority indicates the level of importance for this validation check
+// result.
+//
+// This value is calculated by applying a priority modifier for specific
+// failure conditions (recorded when the validation check result is
+// initially obtained) to a baseline value specific to the validation
+// check performed.
+//
+// If the validation check result is flagged as ignored the priority
+// modifier is also ignored.
+func (mtvr MinTLSVersionValidationResult) Priority() int {
+	switch {
+	case mtvr.ignored:
+		return baselinePriorityMinTLSVersionValidationResult
+	default:
+		return baselinePriorityMinTLSVersionValidationResult + mtvr.priorityModifier
+	}
+}
+
+// NumLegacyVersions returns the number of TLS protocol versions older than
+// the minimum version which the server negotiated.
+func (mtvr MinTLSVersionValidationResult) NumLegacyVersions() int {
+	return len(mtvr.legacyVersions)
+}
+
+// LegacyVersions returns the names of the TLS protocol versions older than
+// the minimum version which the server negotiated.
+func (mtvr MinTLSVersionValidationResult) LegacyVersions() []string {
+	names := make([]string, 0, len(mtvr.legacyVersions))
+	for _, version := range mtvr.legacyVersions {
+		names = append(names, netutils.TLSVersionName(version))
+	}
+
+	return names
+}
+
+// Overview provides a high-level summary of this validation check result.
+func (mtvr MinTLSVersionValidationResult) Overview() string {
+	return fmt.Sprintf(
+		"[MINIMUM: %s, LEGACY ACCEPTED: %d]",
+		netutils.TLSVersionName(mtvr.minVersion),
+		len(mtvr.legacyVersions),
+	)
+}
+
+// Status is intended as a brief status of the validation check result. This
+// can be used as initial lead-in text.
+func (mtvr MinTLSVersionValidationResult) Status() string {
+	var status string
+
+	switch {
+
+	// User opted to ignore validation check results.
+	case mtvr.IsIgnored():
+		status = fmt.Sprintf(
+			"%s validation ignored",
+			mtvr.CheckName(),
+		)
+
+	case errors.Is(mtvr.err, ErrLegacyTLSVersionAccepted):
+		status = fmt.Sprintf(
+			"%s validation failed: server negotiated %s",
+			mtvr.CheckName(),
+			strings.Join(mtvr.LegacyVersions(), ", "),
+		)
+
+	case mtvr.err != nil:
+		status = fmt.Sprintf(
+			"Error encountered probing server for legacy TLS protocol versions: %v",
+			mtvr.err,
+		)
+
+	// No validation errors occurred.
+	default:
+		status = fmt.Sprintf(
+			"%s validation successful: no TLS protocol version older than %s negotiated",
+			mtvr.CheckName(),
+			netutils.TLSVersionName(mtvr.minVersion),
+		)
+	}
+
+	return status
+}
+
+// StatusDetail provides additional details intended to extend the shorter
+// status text with information suitable as explanation for the overall state
+// of the validation check result. This text may span multiple lines.
+func (mtvr MinTLSVersionValidationResult) StatusDetail() string {
+	// The status already lists the accepted legacy protocol versions; there
+	// is nothing further to add.
+	return ""
+}
+
+// String provides the validation check result in human-readable format.
+func (mtvr MinTLSVersionValidationResult) String() string {
+	return fmt.Sprintf(
+		"%s %s",
+		mtvr.Status(),
+		mtvr.Overview(),
+	)
+}
+
+// Report provides the validation check result in verbose human-readable
+// format.
+func (mtvr MinTLSVersionValidationResult) Report() string {
+	return mtvr.String()
+}
+
+// ValidationStatus provides a one word status value for minimum TLS version
+// validation check results.
+func (mtvr MinTLSVersionValidationResult) ValidationStatus() string {
+	switch {
+	case mtvr.IsFailed():
+		return ValidationStatusFailed
+	case mtvr.IsIgnored():
+		return ValidationStatusIgnored
+	default:
+		return ValidationStatusSuccessful
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package certs
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/atc0005/go-nagios"
+)
+
+func TestValidateMinTLSVersion(t *testing.T) {
+	t.Parallel()
+
+	certChain := newTestChain(t, "www.example.com")
+
+	tests := []struct {
+		name        string
+		accepted    []uint16
+		probeErr    error
+		options     CertChainValidationOptions
+		err         error
+		wantState   string
+		wantLegacy  int
+		wantVersion []string
+	}{
+		{
+			name:      "ServerPinnedToTLS12",
+			accepted:  []uint16{tls.VersionTLS12},
+			wantState: nagios.StateOKLabel,
+		},
+		{
+			name:        "ServerPinnedToTLS11",
+			accepted:    []uint16{tls.VersionTLS10, tls.VersionTLS11},
+			err:         ErrLegacyTLSVersionAccepted,
+			wantState:   nagios.StateWARNINGLabel,
+			wantLegacy:  2,
+			wantVersion: []string{"TLS 1.0", "TLS 1.1"},
+		},
+		{
+			name:       "LegacyVersionIgnored",
+			accepted:   []uint16{tls.VersionTLS11, tls.VersionTLS12},
+			options:    CertChainValidationOptions{IgnoreValidationResultMinTLSVersion: true},
+			err:        ErrLegacyTLSVersionAccepted,
+			wantState:  nagios.StateOKLabel,
+			wantLegacy: 1,
+		},
+		{
+			name:      "ProbeFailed",
+			probeErr:  fmt.Errorf("connection refused"),
+			err:       ErrTLSVersionProbeFailed,
+			wantState: nagios.StateUNKNOWNLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := ValidateMinTLSVersion(certChain, tls.VersionTLS12, tt.accepted, tt.probeErr, tt.options)
+
+			if !errors.Is(result.Err(), tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, result.Err())
+			}
+
+			if got := result.ServiceState().Label; got != tt.wantState {
+				t.Errorf("want state %s; got %s: %s", tt.wantState, got, result)
+			}
+
+			if got := result.NumLegacyVersions(); got != tt.wantLegacy {
+				t.Errorf("want %d legacy versions; got %d", tt.wantLegacy, got)
+			}
+
+			if tt.wantVersion != nil {
+				if got := fmt.Sprint(result.LegacyVersions()); got != fmt.Sprint(tt.wantVersion) {
+					t.Errorf("want legacy versions %s; got %s", fmt.Sprint(tt.wantVersion), got)
+				}
+			}
+		})
+	}
+}
@@ -124,7 +124,9 @@ func (c *Config) handlePositionalArgs(appType AppType) error {
 
 			cleanPath := filepath.Clean(flag.Arg(0))
 
-			if _, err := os.Stat(cleanPath); err == nil {
+			if flag.Arg(0) == StdioFilename {
+				c.InputFilename = StdioFilename
+			} else if _, err := os.Stat(cleanPath); err == nil {
 				c.InputFilename = cleanPath
 			} else if errors.Is(err, os.ErrNotExist) {
 				err := c.parseServerValue(flag.Arg(0))
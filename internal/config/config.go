@@ -10,10 +10,14 @@ package config
 import (
 	"errors"
 	"fmt"
-	"strconv"
+	"net"
+	"runtime/debug"
 	"strings"
+	"time"
 
+	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/secrets"
 	"github.com/atc0005/check-cert/internal/textutils"
 	"github.com/rs/zerolog"
 )
@@ -28,6 +32,10 @@ var (
 	// version information.
 	ErrVersionRequested = errors.New("version information requested")
 
+	// ErrSchemaRequested indicates that the user requested the JSON Schema
+	// document for machine-readable output.
+	ErrSchemaRequested = errors.New("output schema requested")
+
 	// ErrInvalidPosArgPattern indicates that the user provided an invalid
 	// pattern for a positional argument.
 	ErrInvalidPosArgPattern = errors.New("invalid positional argument pattern")
@@ -60,6 +68,15 @@ type AppType struct {
 	// Copier represents an application used for copying or manipulating
 	// certificates.
 	Copier bool
+
+	// Exporter represents a long-running application used to expose
+	// certificate metrics for collection by a monitoring system.
+	Exporter bool
+
+	// Monitor represents a long-running application which periodically
+	// evaluates a collection of targets and exposes the results via an HTTP
+	// API.
+	Monitor bool
 }
 
 // multiValueStringFlag is a custom type that satisfies the flag.Value
@@ -75,10 +92,10 @@ type multiValueIntFlag []int
 // interface in order to accept multiple IP Addresses, hostnames or FQDNs for
 // some of our flags.
 type multiValueHostsFlag struct {
-	// hostValues represents an original specified host pattern provided by
-	// the caller and the collection of IP Addresses expanded from the
-	// pattern.
-	hostValues []netutils.HostPattern
+	// givenValues records the host patterns provided by the caller. These
+	// are expanded when needed so that the sysadmin-specified DNS server and
+	// timeout are used for name resolution regardless of flag order.
+	givenValues []string
 }
 
 // String returns a comma separated string consisting of all slice elements.
@@ -121,23 +138,18 @@ func (mvh *multiValueHostsFlag) String() string {
 	case mvh == nil:
 		return ""
 
-	case len(mvh.hostValues) > mvhPrintLimit:
+	case len(mvh.givenValues) > mvhPrintLimit:
 		return fmt.Sprintf(
 			"Provided host list has %d entries (skipping printing of large list)",
-			len(mvh.hostValues),
+			len(mvh.givenValues),
 		)
 
 	default:
 
-		givenHostPatterns := make([]string, len(mvh.hostValues))
-		for i := range mvh.hostValues {
-			givenHostPatterns[i] = mvh.hostValues[i].Given
-		}
-
 		return fmt.Sprintf(
 			"Provided hosts list (%d entries): %v",
-			len(mvh.hostValues),
-			strings.Join(givenHostPatterns, ", "),
+			len(mvh.givenValues),
+			strings.Join(mvh.givenValues, ", "),
 		)
 
 	}
@@ -153,40 +165,52 @@ func (mvh *multiValueHostsFlag) Set(value string) error {
 		items[index] = strings.TrimSpace(item)
 	}
 
-	// convert given host patterns
+	// record given host patterns for later expansion
 	for _, givenPattern := range items {
-		host, err := netutils.ExpandHost(givenPattern)
-		if err != nil {
-			return err
+		if givenPattern == "" {
+			return fmt.Errorf(
+				"error processing flag; empty host pattern in %q: %w",
+				value,
+				netutils.ErrUnrecognizedHostOrIPValue,
+			)
 		}
-		mvh.hostValues = append(mvh.hostValues, host)
+		mvh.givenValues = append(mvh.givenValues, givenPattern)
 	}
 
 	return nil
 }
 
+// expand converts the given host patterns to the collection of IP Addresses
+// represented by each, using the given resolver and timeout for name
+// resolution of hostnames, FQDNs or DNS SRV records.
+func (mvh multiValueHostsFlag) expand(resolver *net.Resolver, timeout time.Duration) ([]netutils.HostPattern, error) {
+	hosts := make([]netutils.HostPattern, 0, len(mvh.givenValues))
+
+	for _, givenPattern := range mvh.givenValues {
+		expanded, err := netutils.ExpandHostsWithResolver(givenPattern, resolver, timeout)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, expanded...)
+	}
+
+	return hosts, nil
+}
+
 // Set is called once by the flag package, in command line order, for each
 // flag present.
 func (mvi *multiValueIntFlag) Set(value string) error {
 
 	// split comma-separated string into multiple values, toss whitespace,
-	// then convert port in string format to integer for later use
-	items := strings.Split(value, ",")
-	for i, v := range items {
-		items[i] = strings.TrimSpace(v)
-
-		port, strConvErr := strconv.Atoi(strings.TrimSpace(v))
-		if strConvErr != nil {
-			return fmt.Errorf(
-				"error processing flag; failed to convert string %q to int: %w",
-				v,
-				strConvErr,
-			)
-		}
-
-		*mvi = append(*mvi, port)
+	// expand port ranges (e.g., 8000-8100) then convert ports in string
+	// format to integers for later use
+	ports, err := netutils.ParsePortsList(value)
+	if err != nil {
+		return fmt.Errorf("error processing flag; %w", err)
 	}
 
+	*mvi = append(*mvi, ports...)
+
 	return nil
 }
 
@@ -241,10 +265,40 @@ type Config struct {
 	// FQDNs to scan for certs.
 	hosts multiValueHostsFlag
 
+	// HostsFile is the fully-qualified path to a file listing IP Addresses
+	// (single and ranges), hostnames or FQDNs to scan for certs with
+	// optional per-entry port overrides.
+	HostsFile string
+
+	// excludeHosts is the list of IP Addresses (single and ranges), hostnames
+	// or FQDNs to remove from the expanded set of hosts to scan.
+	excludeHosts multiValueHostsFlag
+
 	// certTypesToKeep is the list of certificate types to keep from a given
 	// input certificate chain.
 	certTypesToKeep multiValueStringFlag
 
+	// CompleteChain indicates whether missing intermediate certificates
+	// should be downloaded using the AIA CA Issuers URLs listed in the input
+	// certificate chain.
+	CompleteChain bool
+
+	// SortChain indicates whether the input certificate chain should be
+	// sorted into canonical order before writing the output file.
+	SortChain bool
+
+	// Dedupe indicates whether repeated certificates in the input
+	// certificate chain should be removed before writing the output file.
+	Dedupe bool
+
+	// KeyFile is the fully-qualified path to a PEM encoded private key file.
+	// If specified, the output file is written as a PKCS #12 bundle.
+	KeyFile string
+
+	// P12Password is the secret provider specification (e.g., env:NAME) for
+	// the password protecting the PKCS #12 bundle.
+	P12Password string
+
 	// ScanRateLimit is the maximum number of concurrent port scan attempts.
 	ScanRateLimit int
 
@@ -257,6 +311,143 @@ type Config struct {
 	// Port is the TCP port used by the certificate-enabled service.
 	Port int
 
+	// ExpectedSerial is the serial number that the leaf certificate is
+	// required to have. This is used to confirm that a replacement
+	// certificate has been deployed (e.g., after a coordinated renewal).
+	ExpectedSerial string
+
+	// DependentOnConnectFailure indicates whether the DEPENDENT service
+	// check state should be used instead of CRITICAL when a connection to
+	// the remote service cannot be established (e.g., due to an unavailable
+	// proxy or bastion host).
+	DependentOnConnectFailure bool
+
+	// ExecHook is the fully-qualified path to an executable invoked with the
+	// JSON encoded results of the check provided via stdin.
+	ExecHook string
+
+	// BlocklistFile is the fully-qualified path to a file containing
+	// known-compromised certificate SHA-256 fingerprints or serial numbers
+	// that the leaf certificate is required to not match.
+	BlocklistFile string
+
+	// DistrustedCAsFile is the fully-qualified path to a file containing
+	// distrusted certificate authority entries used in place of the
+	// built-in list.
+	DistrustedCAsFile string
+
+	// CTSearchURL is the URL of a crt.sh compatible Certificate
+	// Transparency search API used to retrieve certificates logged for the
+	// server name.
+	CTSearchURL string
+
+	// CTSearchToken is the secret provider specification (e.g.,
+	// env:CT_TOKEN) for the API token sent with CT search API requests.
+	CTSearchToken string
+
+	// CTSearchTokenCmd is the command whose output is used as the API token
+	// sent with CT search API requests.
+	CTSearchTokenCmd string
+
+	// ACMEDirectoryURL is the URL of the ACME directory used to locate the
+	// ACME Renewal Information endpoint.
+	ACMEDirectoryURL string
+
+	// ctSearchTokenProvider retrieves the API token sent with CT search API
+	// requests. This is nil if an API token was not specified.
+	ctSearchTokenProvider secrets.Provider
+
+	// distrustedCAs is the list of distrusted certificate authorities
+	// loaded from the sysadmin-specified file. This is nil if a file was not
+	// specified.
+	distrustedCAs *certs.DistrustedCAList
+
+	// minTLSVersion is the oldest TLS protocol version (e.g., "1.2") the
+	// server is permitted to negotiate.
+	minTLSVersion string
+
+	// MaxExternalRequests is the maximum number of requests to external
+	// services made by network-dependent validation checks during a single
+	// execution. A zero value indicates no limit.
+	MaxExternalRequests int
+
+	// MaxDownloadBytes is the maximum number of bytes downloaded from
+	// external services by network-dependent validation checks during a
+	// single execution. A zero value indicates no limit.
+	MaxDownloadBytes int64
+
+	// StateFile is the fully-qualified path to a file used to record details
+	// of the leaf certificate between plugin executions so that changes to
+	// the SANs list can be reported. For the scanner, this file records scan
+	// progress so that an interrupted scan can be resumed.
+	StateFile string
+
+	// StatsFile is the fully-qualified path to a file used to record
+	// local-only, aggregate run statistics for each execution.
+	StatsFile string
+
+	// TargetsFile is the fully-qualified path to a file listing
+	// certificate-enabled services to evaluate in place of the single
+	// service specified by the server flag.
+	TargetsFile string
+
+	// Socket is the fully-qualified path to a Unix domain socket where
+	// certificate-enabled services are evaluated on request.
+	Socket string
+
+	// TrustStore is the certificate bundle (or system trust store keyword)
+	// whose certificates are each evaluated in place of a certificate chain.
+	TrustStore string
+
+	// CompareWith is the server value (with optional port) of a second
+	// certificate-enabled service evaluated and compared against the
+	// service specified by the server flag.
+	CompareWith string
+
+	// AggregateStrategy is the strategy used to combine the results for
+	// multiple targets into a single service check result.
+	AggregateStrategy string
+
+	// aggregateWarning is the percentage or number of targets with problems
+	// at which the combined service check result is WARNING.
+	aggregateWarning int
+
+	// aggregateCritical is the percentage or number of targets with
+	// problems at which the combined service check result is CRITICAL.
+	aggregateCritical int
+
+	// PolicyFile is the fully-qualified path to a file defining the
+	// validation checks and thresholds applied to matching targets.
+	PolicyFile string
+
+	// stateMappings is the list of FROM=TO service check state overrides
+	// applied to the final plugin state (e.g., WARNING=OK).
+	stateMappings multiValueStringFlag
+
+	// maxSeverity is the list of KEYWORD=STATE overrides limiting the most
+	// severe state that a failing validation check contributes to the final
+	// plugin state (e.g., hostname=warning).
+	maxSeverity multiValueStringFlag
+
+	// requiredEKUs is the list of Extended Key Usage keywords that the leaf
+	// certificate is required to have.
+	requiredEKUs multiValueStringFlag
+
+	// disallowedEKUs is the list of Extended Key Usage keywords that the leaf
+	// certificate is not permitted to have (e.g., codeSigning on a TLS
+	// endpoint).
+	disallowedEKUs multiValueStringFlag
+
+	// ignoredSerials is the list of serial numbers for certificates in the
+	// chain which should be ignored when evaluating expiration and signature
+	// algorithms.
+	ignoredSerials multiValueStringFlag
+
+	// ignoredFingerprints is the list of SHA-256 fingerprints for
+	// certificates in the chain which should be ignored when evaluating
+	// expiration and signature algorithms.
+	ignoredFingerprints multiValueStringFlag
+
 	// PortsList is the list of ports to be checked for certificates.
 	portsList multiValueIntFlag
 
@@ -273,26 +464,131 @@ type Config struct {
 	// field as a CRITICAL state.
 	AgeCritical int
 
+	// AgeWarningPercent is the percentage of certificate lifetime remaining
+	// when a certificate is considered to be in a WARNING state. If
+	// specified, this value overrides AgeWarning.
+	AgeWarningPercent int
+
+	// AgeCriticalPercent is the percentage of certificate lifetime remaining
+	// when a certificate is considered to be in a CRITICAL state. If
+	// specified, this value overrides AgeCritical.
+	AgeCriticalPercent int
+
+	// AgeWarningIntermediate is the number of days remaining before
+	// intermediate certificate expiration when this application will flag
+	// the NotAfter certificate field as a WARNING state. If not specified,
+	// AgeWarning is used.
+	AgeWarningIntermediate int
+
+	// AgeCriticalIntermediate is the number of days remaining before
+	// intermediate certificate expiration when this application will flag
+	// the NotAfter certificate field as a CRITICAL state. If not specified,
+	// AgeCritical is used.
+	AgeCriticalIntermediate int
+
+	// AgeWarningRoot is the number of days remaining before root certificate
+	// expiration when this application will flag the NotAfter certificate
+	// field as a WARNING state. If not specified, AgeWarning is used.
+	AgeWarningRoot int
+
+	// AgeCriticalRoot is the number of days remaining before root
+	// certificate expiration when this application will flag the NotAfter
+	// certificate field as a CRITICAL state. If not specified, AgeCritical
+	// is used.
+	AgeCriticalRoot int
+
 	// PayloadFormatVersion indicates the chosen format version to use when
 	// creating a certificate metadata payload.
 	PayloadFormatVersion int
 
+	// expiryCliffWindow is the number of days within which the expiration
+	// of multiple certificates is treated as a single expiry cliff. Expiry
+	// cliff detection is disabled if zero.
+	expiryCliffWindow int
+
+	// expiryCliffLeadTime is the number of days before the earliest
+	// expiration date of an expiry cliff when the expiry cliff is reported.
+	expiryCliffLeadTime int
+
 	// timeout is the number of seconds allowed before the connection attempt
 	// to a remote certificate-enabled service is abandoned and an error
 	// returned.
 	timeout int
 
+	// dnsTimeout is the number of seconds allowed for resolving a hostname
+	// or FQDN to an IP Address. The general connection timeout is used if
+	// not specified.
+	dnsTimeout int
+
+	// DNSServer is the DNS server (IP Address or hostname with an optional
+	// port) used for resolving hostnames or FQDNs to IP Addresses instead of
+	// the DNS servers used by the system resolver.
+	DNSServer string
+
+	// ResolverDNSSECMode determines how a DNS answer for the server value
+	// which the DNS server does not report as authenticated using DNSSEC
+	// (via the AD bit) is handled (ignored, WARNING or CRITICAL state). The
+	// DNS server is trusted to perform DNSSEC validation.
+	ResolverDNSSECMode string
+
+	// SimulateState is the optional service check state emitted in place of
+	// the actual state in order to verify notification pipelines.
+	SimulateState string
+
+	// SimulateMessage is the optional one-line summary emitted along with
+	// the simulated service check state.
+	SimulateMessage string
+
+	// dialTimeout is the number of seconds allowed for establishing the TCP
+	// connection to a remote certificate-enabled service. The general
+	// connection timeout is used if not specified.
+	dialTimeout int
+
+	// handshakeTimeout is the number of seconds allowed for completing the
+	// TLS handshake with a remote certificate-enabled service. The general
+	// connection timeout is used if not specified.
+	handshakeTimeout int
+
+	// retries is the number of additional attempts made to retrieve a
+	// certificate chain after a transient connection failure.
+	retries int
+
+	// retryDelay is the number of milliseconds to wait before the first retry
+	// attempt when retrieving a certificate chain.
+	retryDelay int
+
+	// FIPSMode indicates whether FIPS mode should be used regardless of
+	// whether a FIPS-restricted build or environment is detected.
+	FIPSMode bool
+
 	// timeoutPortScan is the number of milliseconds allowed before the port
 	// connection attempt is abandoned and an error returned. This timeout is
 	// used specifically to quickly determine port state as part of bulk
 	// operations where speed is crucial.
 	timeoutPortScan int
 
+	// hostDelay is the minimum number of milliseconds between connection
+	// attempts to the same IP Address during a scan.
+	hostDelay int
+
+	// scanJitter is the maximum number of milliseconds of random delay added
+	// before each connection attempt during a scan.
+	scanJitter int
+
+	// progressInterval is the number of seconds between progress reports
+	// emitted during a scan.
+	progressInterval int
+
 	// timeoutAppInactivity is the timeout in seconds that occurs when the
 	// scanning process gets "stuck" for one reason or another (e.g., older
 	// devices or systems with non-compliant TCP stacks).
 	timeoutAppInactivity int
 
+	// pluginTimeout is the number of seconds the plugin is allowed to run
+	// before the service check is considered timed out. Optional validation
+	// checks are skipped as this deadline approaches.
+	pluginTimeout int
+
 	// EmitBranding controls whether "generated by" text is included at the
 	// bottom of application output. This output is included in the Nagios
 	// dashboard and notifications. This output may not mix well with branding
@@ -323,10 +619,46 @@ type Config struct {
 	// output text, so this setting defaults to false.
 	EmitCertText bool
 
+	// TemplateFile is the fully-qualified path to a Go text/template file
+	// used to generate custom output for an evaluated certificate chain.
+	TemplateFile string
+
+	// DiffSince is the fully-qualified path to a previously recorded
+	// snapshot of a certificate chain (a PEM formatted certificate chain
+	// file or a plugin state file) to compare an evaluated certificate chain
+	// against.
+	DiffSince string
+
+	// Watch indicates whether the certificate chain is retrieved again on
+	// an interval until it differs from the initial certificate chain.
+	Watch bool
+
+	// watchInterval is the time between certificate chain retrieval
+	// attempts when watching for changes.
+	watchInterval time.Duration
+
+	// BackfillDir is the fully-qualified path to a directory of saved
+	// check_cert plugin output files to convert into normalized JSON
+	// records.
+	BackfillDir string
+
+	// StatsSummary is the fully-qualified path to a run statistics file to
+	// summarize.
+	StatsSummary string
+
 	// ShowVersion is a flag indicating whether the user opted to display only
 	// the version string and then immediately exit the application.
 	ShowVersion bool
 
+	// PrintSchema is a flag indicating whether the user opted to display only
+	// the JSON Schema document for machine-readable output and then
+	// immediately exit the application.
+	PrintSchema bool
+
+	// ConfigFile is the fully-qualified path to a configuration file
+	// providing default flag values.
+	ConfigFile string
+
 	// ShowHostsWithClosedPorts indicates whether hosts without any open ports
 	// are included in the port scan results summary output.
 	ShowHostsWithClosedPorts bool
@@ -345,6 +677,57 @@ type Config struct {
 	// is shown at the end of scanning specified hosts.
 	ShowOverview bool
 
+	// OutputFormat is the format used to emit scan results or certificate
+	// chain details (e.g., summary tables, JSON or Markdown).
+	OutputFormat string
+
+	// ListenAddress is the network address (host:port) used by exporter
+	// type applications to serve metrics and by monitor type applications
+	// to serve the HTTP API.
+	ListenAddress string
+
+	// checkInterval is the number of minutes between checks of all targets
+	// by monitor type applications.
+	checkInterval int
+
+	// ProfileCPU is the optional path to a file where a CPU profile in pprof
+	// format is written by long-running applications.
+	ProfileCPU string
+
+	// ProfileMem is the optional path to a file where a memory profile in
+	// pprof format is written by long-running applications on exit.
+	ProfileMem string
+
+	// OutputFile is the optional path to a file where flattened scan results
+	// are written in CSV format.
+	OutputFile string
+
+	// ExportBundle is the optional path to a bundle file where scan results
+	// and discovered certificate chains are written for transfer out of an
+	// isolated network.
+	ExportBundle string
+
+	// ImportBundle is the optional path to a bundle file whose recorded
+	// certificate chains are evaluated in place of performing a scan.
+	ImportBundle string
+
+	// CompareTo is the optional path to the results of a previous scan (in
+	// JSON or NDJSON format) used to report changes since that scan.
+	CompareTo string
+
+	// WebhookURL is the optional URL of an endpoint where the scan results
+	// (in JSON format) are sent using a POST request.
+	WebhookURL string
+
+	// WebhookAuthHeader is the secret provider specification (e.g.,
+	// env:NAME) for the value of the authentication header sent with the
+	// webhook request.
+	WebhookAuthHeader string
+
+	// WebhookAuthHeaderName is the name of the authentication header sent
+	// with the webhook request.
+	WebhookAuthHeaderName string
+
 	// ShowResultsDuringScan indicates whether host scan results should be
 	// shown during a port scan. See also ShowHostsWithClosedPorts. Enabling
 	// either of these options results in live scan result details being
@@ -389,6 +772,29 @@ type Config struct {
 	// confusing (e.g., when all results are either successful or ignored).
 	ListIgnoredValidationCheckResultErrors bool
 
+	// ShowCheckTimings indicates whether the time taken to perform each
+	// validation check should be included in performance data metrics and
+	// the final plugin report output.
+	ShowCheckTimings bool
+
+	// ShowRemediation indicates whether remediation commands for common
+	// failures should be included in the final plugin report output.
+	ShowRemediation bool
+
+	// BriefWhenOK indicates whether the detailed report output should be
+	// omitted for OK results, leaving only the one-line summary and
+	// performance data.
+	BriefWhenOK bool
+
+	// DaysRemainingOnly indicates whether plugin output should be limited
+	// to the number of days until the soonest certificate expiration (or -1
+	// if this could not be determined).
+	DaysRemainingOnly bool
+
+	// Profile is the name of a check profile presetting a group of
+	// validation flags and expiration thresholds.
+	Profile string
+
 	// ignoreValidationResults is a list of validation check results that
 	// should be explicitly ignored and not used when determining overall
 	// validation state of a certificate chain.
@@ -408,6 +814,32 @@ func Version() string {
 	return fmt.Sprintf("%s %s (%s)", myAppName, version, myAppURL)
 }
 
+// BuildTime returns the time of the version control commit that this
+// application was built from as recorded by the Go toolchain. The zero value
+// is returned if this information is unavailable (e.g., builds made outside
+// of a Git working tree or via go test).
+func BuildTime() time.Time {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return time.Time{}
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key != "vcs.time" {
+			continue
+		}
+
+		buildTime, err := time.Parse(time.RFC3339, setting.Value)
+		if err != nil {
+			return time.Time{}
+		}
+
+		return buildTime
+	}
+
+	return time.Time{}
+}
+
 // Branding accepts a message and returns a function that concatenates that
 // message with version information. This function is intended to be called as
 // a final step before application exit after any other output has already
@@ -431,6 +863,18 @@ func New(appType AppType) (*Config, error) {
 		return nil, ErrVersionRequested
 	}
 
+	if config.PrintSchema {
+		return nil, ErrSchemaRequested
+	}
+
+	if err := config.handleConfigFile(appType); err != nil {
+		return nil, fmt.Errorf("failed to process configuration file: %w", err)
+	}
+
+	if err := config.handleProfile(); err != nil {
+		return nil, fmt.Errorf("failed to process check profile: %w", err)
+	}
+
 	if err := config.handlePositionalArgs(appType); err != nil {
 		return nil, fmt.Errorf("failed to process positional arguments: %w", err)
 	}
@@ -439,6 +883,24 @@ func New(appType AppType) (*Config, error) {
 		return nil, fmt.Errorf("configuration validation failed: %w", err)
 	}
 
+	// The API token is retrieved at most once, when first needed.
+	if provider := config.ctSearchTokenSecretProvider(); provider != nil {
+		config.ctSearchTokenProvider = secrets.Cached(provider)
+	}
+
+	if config.DistrustedCAsFile != "" {
+		distrustedCAs, err := certs.LoadDistrustedCAListFile(config.DistrustedCAsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load distrusted CAs list: %w", err)
+		}
+		config.distrustedCAs = &distrustedCAs
+	}
+
+	// Legacy signature verification is disabled for the entire application.
+	if config.FIPSMode {
+		certs.EnableFIPSMode()
+	}
+
 	// initialize logging just as soon as validation is complete
 	if err := config.setupLogging(appType); err != nil {
 		return nil, fmt.Errorf(
@@ -11,8 +11,14 @@ import (
 	"errors"
 	"flag"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/secrets"
 )
 
 func TestExpirationAgeThresholds(t *testing.T) {
@@ -259,157 +265,2294 @@ func TestConfigValidationForCheckResultsFlags(t *testing.T) {
 			},
 			errExpected: true,
 		},
-	}
-
-	for _, tt := range tests {
-
-		// Make scopelint linter happy
-		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
-		tt := tt
-
-		t.Run(tt.name, func(t *testing.T) {
-
-			// Perform configuration validation.
-			cfgErr := tt.cfg.validate(AppType{Plugin: true})
-			switch {
-			case !tt.errExpected && cfgErr != nil:
-				t.Errorf("want error: %v; got %v", tt.errExpected, cfgErr)
-			case tt.errExpected && cfgErr == nil:
-				t.Errorf("want error: %v; got %v", tt.errExpected, cfgErr)
-			default:
-				t.Log("configuration validation successful")
-			}
-		})
-	}
-
-}
-
-// TestApplyIgnoreDecision asserts that given a specific configuration (based
-// on expected flag values) the "should validation check result be applied"
-// question is answered as expected. Configuration validation is not
-// performed.
-func TestApplyIgnoreDecision(t *testing.T) {
-
-	tests := []struct {
-		name         string
-		cfg          Config
-		validateFunc func(Config) bool
-		applyResults bool
-	}{
 		{
-			name:         "DefaultValidateExpirationResults",
-			cfg:          Config{},
-			validateFunc: Config.ApplyCertExpirationValidationResults,
-			applyResults: defaultApplyCertExpirationValidationResults,
+			name: "ApplyValidateSerialNumberResultsWithExpectedSerial",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				applyValidationResults: []string{ValidationKeywordSerial},
+				ExpectedSerial:         "0A:FD:50:2B",
+			},
+			errExpected: false,
 		},
 		{
-			name: "IgnoreValidateExpirationResults",
+			name: "ApplyValidateSerialNumberResultsWithoutExpectedSerial",
 			cfg: Config{
-				ignoreValidationResults: []string{ValidationKeywordExpiration},
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				applyValidationResults: []string{ValidationKeywordSerial},
 			},
-			validateFunc: Config.ApplyCertExpirationValidationResults,
-			applyResults: false,
+			errExpected: true,
 		},
 		{
-			name: "ApplyValidateExpirationResults",
+			name: "InvalidExpectedSerial",
 			cfg: Config{
-				applyValidationResults: []string{ValidationKeywordExpiration},
+				Port:           443,
+				LoggingLevel:   defaultLogLevel,
+				Server:         "www.example.com",
+				AgeWarning:     defaultCertExpireAgeWarning,
+				AgeCritical:    defaultCertExpireAgeCritical,
+				ExpectedSerial: "tacos",
 			},
-			validateFunc: Config.ApplyCertExpirationValidationResults,
-			applyResults: true,
+			errExpected: true,
 		},
 		{
-			name:         "DefaultValidateHostnameResults",
-			cfg:          Config{},
-			validateFunc: Config.ApplyCertHostnameValidationResults,
-			applyResults: defaultApplyCertHostnameValidationResults,
+			name: "ApplyValidateBlocklistResultsWithoutBlocklistFile",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				applyValidationResults: []string{ValidationKeywordBlocklist},
+			},
+			errExpected: true,
 		},
 		{
-			name: "IgnoreValidateHostnameResults",
+			name: "MissingBlocklistFile",
 			cfg: Config{
-				ignoreValidationResults: []string{ValidationKeywordHostname},
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				BlocklistFile: "/tmp/does-not-exist/blocklist.txt",
 			},
-			validateFunc: Config.ApplyCertHostnameValidationResults,
-			applyResults: false,
+			errExpected: true,
 		},
 		{
-			name: "ApplyValidateHostnameResults",
+			name: "MissingStateFileDirectory",
 			cfg: Config{
-				applyValidationResults: []string{ValidationKeywordHostname},
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				StateFile:    "/tmp/does-not-exist/state.json",
 			},
-			validateFunc: Config.ApplyCertHostnameValidationResults,
-			applyResults: true,
+			errExpected: true,
 		},
 		{
-			name:         "DefaultValidateSANsListResultsWithoutSANsEntries",
-			cfg:          Config{},
-			validateFunc: Config.ApplyCertSANsListValidationResults,
-			applyResults: false,
+			name: "ValidServerPorts",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				portsList:    []int{443, 8443, 9443},
+			},
+			errExpected: false,
 		},
 		{
-			name: "DefaultValidateSANsListResultsWithSANsEntries",
+			name: "InvalidServerPortsValue",
 			cfg: Config{
-				SANsEntries: []string{"tacos.example.com"},
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				portsList:    []int{443, 70000},
 			},
-			validateFunc: Config.ApplyCertSANsListValidationResults,
-			applyResults: defaultApplyCertSANsListValidationResults,
+			errExpected: true,
 		},
 		{
-			name: "IgnoreValidateSANsListResults",
+			name: "ServerPortsWithStateFile",
 			cfg: Config{
-				ignoreValidationResults: []string{ValidationKeywordSANsList},
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				portsList:    []int{443, 8443},
+				StateFile:    "/tmp/state.json",
 			},
-			validateFunc: Config.ApplyCertSANsListValidationResults,
-			applyResults: false,
+			errExpected: true,
 		},
 		{
-			name: "ApplyValidateSANsListResults",
+			name: "MissingTargetsFile",
 			cfg: Config{
-				applyValidationResults: []string{ValidationKeywordSANsList},
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				TargetsFile:  "/tmp/does-not-exist/targets.txt",
 			},
-			validateFunc: Config.ApplyCertSANsListValidationResults,
-			applyResults: true,
+			errExpected: true,
 		},
-	}
-
-	for _, tt := range tests {
-
-		// Make scopelint linter happy
-		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
-		tt := tt
-
-		t.Run(tt.name, func(t *testing.T) {
-
-			switch {
-
-			// If the test case indicates that validation results should be
-			// applied but the validation function indicates it is not.
-			case tt.applyResults && !tt.validateFunc(tt.cfg):
-				t.Errorf(
-					"want: validation results applied (%v); got: validation results applied (%v)",
-					tt.applyResults,
-					tt.validateFunc(tt.cfg),
-				)
-
-			// If the test case indicates that validation results should not
-			// be applied but the validation function indicates it is.
-			case !tt.applyResults && tt.validateFunc(tt.cfg):
-				t.Errorf(
-					"want: validation results applied (%v); got: validation results applied (%v)",
-					tt.applyResults,
-					tt.validateFunc(tt.cfg),
-				)
-
-			// Test case and validation function agree.
-			default:
-				t.Logf(
-					"want: validation results applied (%v); got: validation results applied (%v)",
-					tt.applyResults,
-					tt.validateFunc(tt.cfg),
-				)
-			}
-
-		})
-	}
-
+		{
+			name: "TargetsFileWithServer",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				TargetsFile:  "config_test.go",
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidCTSearchURLScheme",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				CTSearchURL:  "ftp://crt.sh/",
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidACMEDirectoryURLScheme",
+			cfg: Config{
+				Port:             443,
+				LoggingLevel:     defaultLogLevel,
+				Server:           "www.example.com",
+				AgeWarning:       defaultCertExpireAgeWarning,
+				AgeCritical:      defaultCertExpireAgeCritical,
+				ACMEDirectoryURL: "ftp://acme.example.com/directory",
+			},
+			errExpected: true,
+		},
+		{
+			name: "PlaintextCTSearchToken",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				CTSearchToken: "s3cr3t",
+			},
+			errExpected: true,
+		},
+		{
+			name: "ConflictingCTSearchTokenFlags",
+			cfg: Config{
+				Port:             443,
+				LoggingLevel:     defaultLogLevel,
+				Server:           "www.example.com",
+				AgeWarning:       defaultCertExpireAgeWarning,
+				AgeCritical:      defaultCertExpireAgeCritical,
+				CTSearchToken:    "env:CT_TOKEN",
+				CTSearchTokenCmd: "pass show ct-token",
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidCTSearchToken",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				CTSearchToken: "file:/etc/nagios/ct-token",
+			},
+			errExpected: false,
+		},
+		{
+			name: "UnsupportedPayloadFormatVersion",
+			cfg: Config{
+				Port:                 443,
+				LoggingLevel:         defaultLogLevel,
+				Server:               "www.example.com",
+				AgeWarning:           defaultCertExpireAgeWarning,
+				AgeCritical:          defaultCertExpireAgeCritical,
+				PayloadFormatVersion: 3,
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidPayloadFormatVersion1",
+			cfg: Config{
+				Port:                 443,
+				LoggingLevel:         defaultLogLevel,
+				Server:               "www.example.com",
+				AgeWarning:           defaultCertExpireAgeWarning,
+				AgeCritical:          defaultCertExpireAgeCritical,
+				PayloadFormatVersion: 1,
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidAggregateStrategy",
+			cfg: Config{
+				Port:              443,
+				LoggingLevel:      defaultLogLevel,
+				Server:            "www.example.com",
+				AgeWarning:        defaultCertExpireAgeWarning,
+				AgeCritical:       defaultCertExpireAgeCritical,
+				AggregateStrategy: "best",
+			},
+			errExpected: true,
+		},
+		{
+			name: "AggregateThresholdsWithWorstStrategy",
+			cfg: Config{
+				Port:             443,
+				LoggingLevel:     defaultLogLevel,
+				Server:           "www.example.com",
+				AgeWarning:       defaultCertExpireAgeWarning,
+				AgeCritical:      defaultCertExpireAgeCritical,
+				aggregateWarning: 1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidAggregatePercentageThreshold",
+			cfg: Config{
+				Port:              443,
+				LoggingLevel:      defaultLogLevel,
+				Server:            "www.example.com",
+				AgeWarning:        defaultCertExpireAgeWarning,
+				AgeCritical:       defaultCertExpireAgeCritical,
+				AggregateStrategy: AggregateStrategyPercentage,
+				aggregateCritical: 150,
+			},
+			errExpected: true,
+		},
+		{
+			name: "AggregateWarningHigherThanCritical",
+			cfg: Config{
+				Port:              443,
+				LoggingLevel:      defaultLogLevel,
+				Server:            "www.example.com",
+				AgeWarning:        defaultCertExpireAgeWarning,
+				AgeCritical:       defaultCertExpireAgeCritical,
+				AggregateStrategy: AggregateStrategyCount,
+				aggregateWarning:  10,
+				aggregateCritical: 2,
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidAggregateCountThresholds",
+			cfg: Config{
+				Port:              443,
+				LoggingLevel:      defaultLogLevel,
+				Server:            "www.example.com",
+				AgeWarning:        defaultCertExpireAgeWarning,
+				AgeCritical:       defaultCertExpireAgeCritical,
+				AggregateStrategy: AggregateStrategyCount,
+				aggregateWarning:  2,
+				aggregateCritical: 20,
+			},
+			errExpected: false,
+		},
+		{
+			name: "ValidAggregatePercentageDefaultThresholds",
+			cfg: Config{
+				Port:              443,
+				LoggingLevel:      defaultLogLevel,
+				Server:            "www.example.com",
+				AgeWarning:        defaultCertExpireAgeWarning,
+				AgeCritical:       defaultCertExpireAgeCritical,
+				AggregateStrategy: AggregateStrategyPercentage,
+			},
+			errExpected: false,
+		},
+		{
+			name: "CompareWithPortsList",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				portsList:    []int{443, 8443},
+				CompareWith:  "staging.example.com:8443",
+			},
+			errExpected: true,
+		},
+		{
+			name: "CompareWithInvalidPort",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				CompareWith:  "staging.example.com:99999",
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidCompareWith",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				CompareWith:  "staging.example.com:8443",
+			},
+			errExpected: false,
+		},
+		{
+			name: "SocketWithServer",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				Socket:       filepath.Join(os.TempDir(), "check_cert.sock"),
+			},
+			errExpected: true,
+		},
+		{
+			name: "SocketMissingDirectory",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Socket:       filepath.Join(os.TempDir(), "check-cert-missing-dir", "check_cert.sock"),
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidSocket",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Socket:       filepath.Join(os.TempDir(), "check_cert.sock"),
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidDNSServer",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				DNSServer:    "10.0.0.53:99999",
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidDNSServer",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				DNSServer:    "10.0.0.53",
+			},
+			errExpected: false,
+		},
+		{
+			name: "TrustStoreWithServer",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				Server:       "www.example.com",
+				TrustStore:   TrustStoreSystemKeyword,
+			},
+			errExpected: true,
+		},
+		{
+			name: "TrustStoreWithPayload",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				TrustStore:   TrustStoreSystemKeyword,
+				EmitPayload:  true,
+			},
+			errExpected: true,
+		},
+		{
+			name: "TrustStoreWithExecHook",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				TrustStore:   TrustStoreSystemKeyword,
+				ExecHook:     os.Args[0],
+			},
+			errExpected: true,
+		},
+		{
+			name: "TrustStoreMissingBundle",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				TrustStore:   filepath.Join(os.TempDir(), "check-cert-missing-dir", "ca-bundle.pem"),
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidTrustStoreSystem",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				TrustStore:   TrustStoreSystemKeyword,
+			},
+			errExpected: false,
+		},
+		{
+			name: "ValidAgePercentThresholds",
+			cfg: Config{
+				Port:               443,
+				LoggingLevel:       defaultLogLevel,
+				Server:             "www.example.com",
+				AgeWarning:         defaultCertExpireAgeWarning,
+				AgeCritical:        defaultCertExpireAgeCritical,
+				AgeWarningPercent:  20,
+				AgeCriticalPercent: 10,
+			},
+			errExpected: false,
+		},
+		{
+			name: "AgeCriticalPercentWithoutWarningPercent",
+			cfg: Config{
+				Port:               443,
+				LoggingLevel:       defaultLogLevel,
+				Server:             "www.example.com",
+				AgeWarning:         defaultCertExpireAgeWarning,
+				AgeCritical:        defaultCertExpireAgeCritical,
+				AgeCriticalPercent: 10,
+			},
+			errExpected: true,
+		},
+		{
+			name: "IncorrectCriticalPercentThreshold",
+			cfg: Config{
+				Port:               443,
+				LoggingLevel:       defaultLogLevel,
+				Server:             "www.example.com",
+				AgeWarning:         defaultCertExpireAgeWarning,
+				AgeCritical:        defaultCertExpireAgeCritical,
+				AgeWarningPercent:  10,
+				AgeCriticalPercent: 20,
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidChainPositionAgeThresholds",
+			cfg: Config{
+				Port:                    443,
+				LoggingLevel:            defaultLogLevel,
+				Server:                  "www.example.com",
+				AgeWarning:              defaultCertExpireAgeWarning,
+				AgeCritical:             defaultCertExpireAgeCritical,
+				AgeWarningIntermediate:  180,
+				AgeCriticalIntermediate: 90,
+				AgeWarningRoot:          365,
+				AgeCriticalRoot:         180,
+			},
+			errExpected: false,
+		},
+		{
+			name: "AgeWarningRootWithoutCriticalRoot",
+			cfg: Config{
+				Port:           443,
+				LoggingLevel:   defaultLogLevel,
+				Server:         "www.example.com",
+				AgeWarning:     defaultCertExpireAgeWarning,
+				AgeCritical:    defaultCertExpireAgeCritical,
+				AgeWarningRoot: 365,
+			},
+			errExpected: true,
+		},
+		{
+			name: "IncorrectCriticalIntermediateThreshold",
+			cfg: Config{
+				Port:                    443,
+				LoggingLevel:            defaultLogLevel,
+				Server:                  "www.example.com",
+				AgeWarning:              defaultCertExpireAgeWarning,
+				AgeCritical:             defaultCertExpireAgeCritical,
+				AgeWarningIntermediate:  90,
+				AgeCriticalIntermediate: 180,
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidPluginTimeout",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				timeout:       defaultConnectTimeout,
+				pluginTimeout: 30,
+			},
+			errExpected: false,
+		},
+		{
+			name: "PluginTimeoutNotGreaterThanTimeout",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				timeout:       defaultConnectTimeout,
+				pluginTimeout: defaultConnectTimeout,
+			},
+			errExpected: true,
+		},
+		{
+			name: "NegativeMaxExternalRequests",
+			cfg: Config{
+				Port:                443,
+				LoggingLevel:        defaultLogLevel,
+				Server:              "www.example.com",
+				AgeWarning:          defaultCertExpireAgeWarning,
+				AgeCritical:         defaultCertExpireAgeCritical,
+				MaxExternalRequests: -1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "MissingCTSearchURLWithCTLogsValidation",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				applyValidationResults: []string{ValidationKeywordCTLogs},
+			},
+			errExpected: true,
+		},
+		{
+			name: "MissingACMEDirectoryURLWithRenewalInfoValidation",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				applyValidationResults: []string{ValidationKeywordRenewalInfo},
+			},
+			errExpected: true,
+		},
+		{
+			name: "ApplyValidateRenewalInfoResults",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				ACMEDirectoryURL:       defaultACMEDirectoryURL,
+				applyValidationResults: []string{ValidationKeywordRenewalInfo},
+			},
+			errExpected: false,
+		},
+		{
+			name: "ValidMinTLSVersion",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				applyValidationResults: []string{ValidationKeywordTLSVersion},
+				minTLSVersion:          "1.3",
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidMinTLSVersion",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				minTLSVersion: "1.4",
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidStateMappings",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				stateMappings: []string{"WARNING=OK", "unknown=critical"},
+			},
+			errExpected: false,
+		},
+		{
+			name: "ValidMaxSeverity",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				maxSeverity:  []string{"hostname=warning", "EXPIRATION=WARNING"},
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidMaxSeverityKeyword",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				maxSeverity:  []string{"tacos=warning"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidMaxSeverityState",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				maxSeverity:  []string{"hostname=unknown"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidIgnoredSerials",
+			cfg: Config{
+				Port:           443,
+				LoggingLevel:   defaultLogLevel,
+				Server:         "www.example.com",
+				AgeWarning:     defaultCertExpireAgeWarning,
+				AgeCritical:    defaultCertExpireAgeCritical,
+				ignoredSerials: []string{"0A:FD:50:2B", "0afd502c"},
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidIgnoredSerial",
+			cfg: Config{
+				Port:           443,
+				LoggingLevel:   defaultLogLevel,
+				Server:         "www.example.com",
+				AgeWarning:     defaultCertExpireAgeWarning,
+				AgeCritical:    defaultCertExpireAgeCritical,
+				ignoredSerials: []string{"0A:FD:50:2B", "tacos"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidIgnoredFingerprints",
+			cfg: Config{
+				Port:                443,
+				LoggingLevel:        defaultLogLevel,
+				Server:              "www.example.com",
+				AgeWarning:          defaultCertExpireAgeWarning,
+				AgeCritical:         defaultCertExpireAgeCritical,
+				ignoredFingerprints: []string{"14:17:97:88:69:84:53:51:93:E7:73:80:1A:88:EA:EA:D7:39:AA:B4:CC:E4:86:DD:48:C7:83:60:F8:EA:92:EB"},
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidIgnoredFingerprint",
+			cfg: Config{
+				Port:                443,
+				LoggingLevel:        defaultLogLevel,
+				Server:              "www.example.com",
+				AgeWarning:          defaultCertExpireAgeWarning,
+				AgeCritical:         defaultCertExpireAgeCritical,
+				ignoredFingerprints: []string{"0A:FD:50:2B"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidPhaseTimeouts",
+			cfg: Config{
+				Port:             443,
+				LoggingLevel:     defaultLogLevel,
+				Server:           "www.example.com",
+				AgeWarning:       defaultCertExpireAgeWarning,
+				AgeCritical:      defaultCertExpireAgeCritical,
+				dnsTimeout:       2,
+				dialTimeout:      3,
+				handshakeTimeout: 5,
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidDNSTimeout",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				dnsTimeout:   -1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidHandshakeTimeout",
+			cfg: Config{
+				Port:             443,
+				LoggingLevel:     defaultLogLevel,
+				Server:           "www.example.com",
+				AgeWarning:       defaultCertExpireAgeWarning,
+				AgeCritical:      defaultCertExpireAgeCritical,
+				handshakeTimeout: -1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidRetries",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				retries:      3,
+				retryDelay:   250,
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidRetriesNegative",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				retries:      -1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidRetriesTooMany",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				retries:      maxRetries + 1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidRetryDelay",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				retries:      3,
+				retryDelay:   -1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidStateMappingFormat",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				stateMappings: []string{"WARNING:OK"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidStateMappingLabel",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				stateMappings: []string{"WARNING=TACOS"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "ConflictingStateMappings",
+			cfg: Config{
+				Port:          443,
+				LoggingLevel:  defaultLogLevel,
+				Server:        "www.example.com",
+				AgeWarning:    defaultCertExpireAgeWarning,
+				AgeCritical:   defaultCertExpireAgeCritical,
+				stateMappings: []string{"WARNING=OK", "WARNING=CRITICAL"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "MissingExecHook",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				ExecHook:     "/path/to/nonexistent/hook.sh",
+			},
+			errExpected: true,
+		},
+		{
+			name: "ValidRequiredAndDisallowedEKUs",
+			cfg: Config{
+				Port:           443,
+				LoggingLevel:   defaultLogLevel,
+				Server:         "www.example.com",
+				AgeWarning:     defaultCertExpireAgeWarning,
+				AgeCritical:    defaultCertExpireAgeCritical,
+				requiredEKUs:   []string{"serverAuth", "clientauth"},
+				disallowedEKUs: []string{"codeSigning"},
+			},
+			errExpected: false,
+		},
+		{
+			name: "InvalidRequiredEKU",
+			cfg: Config{
+				Port:         443,
+				LoggingLevel: defaultLogLevel,
+				Server:       "www.example.com",
+				AgeWarning:   defaultCertExpireAgeWarning,
+				AgeCritical:  defaultCertExpireAgeCritical,
+				requiredEKUs: []string{"tacos"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "EKURequiredAndDisallowed",
+			cfg: Config{
+				Port:           443,
+				LoggingLevel:   defaultLogLevel,
+				Server:         "www.example.com",
+				AgeWarning:     defaultCertExpireAgeWarning,
+				AgeCritical:    defaultCertExpireAgeCritical,
+				requiredEKUs:   []string{"serverAuth"},
+				disallowedEKUs: []string{"serverAuth"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "NegativeExpiryCliffWindow",
+			cfg: Config{
+				Port:              443,
+				LoggingLevel:      defaultLogLevel,
+				Server:            "www.example.com",
+				AgeWarning:        defaultCertExpireAgeWarning,
+				AgeCritical:       defaultCertExpireAgeCritical,
+				expiryCliffWindow: -1,
+			},
+			errExpected: true,
+		},
+		{
+			name: "ApplyValidateExpiryCliffResults",
+			cfg: Config{
+				Port:                   443,
+				LoggingLevel:           defaultLogLevel,
+				Server:                 "www.example.com",
+				AgeWarning:             defaultCertExpireAgeWarning,
+				AgeCritical:            defaultCertExpireAgeCritical,
+				expiryCliffWindow:      defaultExpiryCliffWindow,
+				expiryCliffLeadTime:    defaultExpiryCliffLeadTime,
+				applyValidationResults: []string{ValidationKeywordExpiryCliff},
+			},
+			errExpected: false,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			// Perform configuration validation.
+			cfgErr := tt.cfg.validate(AppType{Plugin: true})
+			switch {
+			case !tt.errExpected && cfgErr != nil:
+				t.Errorf("want error: %v; got %v", tt.errExpected, cfgErr)
+			case tt.errExpected && cfgErr == nil:
+				t.Errorf("want error: %v; got %v", tt.errExpected, cfgErr)
+			default:
+				t.Log("configuration validation successful")
+			}
+		})
+	}
+
+}
+
+// TestApplyIgnoreDecision asserts that given a specific configuration (based
+// on expected flag values) the "should validation check result be applied"
+// question is answered as expected. Configuration validation is not
+// performed.
+func TestApplyIgnoreDecision(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		cfg          Config
+		validateFunc func(Config) bool
+		applyResults bool
+	}{
+		{
+			name:         "DefaultValidateExpirationResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertExpirationValidationResults,
+			applyResults: defaultApplyCertExpirationValidationResults,
+		},
+		{
+			name: "IgnoreValidateExpirationResults",
+			cfg: Config{
+				ignoreValidationResults: []string{ValidationKeywordExpiration},
+			},
+			validateFunc: Config.ApplyCertExpirationValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "ApplyValidateExpirationResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordExpiration},
+			},
+			validateFunc: Config.ApplyCertExpirationValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateHostnameResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertHostnameValidationResults,
+			applyResults: defaultApplyCertHostnameValidationResults,
+		},
+		{
+			name: "IgnoreValidateHostnameResults",
+			cfg: Config{
+				ignoreValidationResults: []string{ValidationKeywordHostname},
+			},
+			validateFunc: Config.ApplyCertHostnameValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "ApplyValidateHostnameResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordHostname},
+			},
+			validateFunc: Config.ApplyCertHostnameValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateSANsListResultsWithoutSANsEntries",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertSANsListValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "DefaultValidateSANsListResultsWithSANsEntries",
+			cfg: Config{
+				SANsEntries: []string{"tacos.example.com"},
+			},
+			validateFunc: Config.ApplyCertSANsListValidationResults,
+			applyResults: defaultApplyCertSANsListValidationResults,
+		},
+		{
+			name: "IgnoreValidateSANsListResults",
+			cfg: Config{
+				ignoreValidationResults: []string{ValidationKeywordSANsList},
+			},
+			validateFunc: Config.ApplyCertSANsListValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "ApplyValidateSANsListResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordSANsList},
+			},
+			validateFunc: Config.ApplyCertSANsListValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateSerialNumberResultsWithoutExpectedSerial",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertSerialNumberValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "DefaultValidateSerialNumberResultsWithExpectedSerial",
+			cfg: Config{
+				ExpectedSerial: "0A:FD:50:2B",
+			},
+			validateFunc: Config.ApplyCertSerialNumberValidationResults,
+			applyResults: defaultApplyCertSerialNumberValidationResults,
+		},
+		{
+			name: "IgnoreValidateSerialNumberResults",
+			cfg: Config{
+				ExpectedSerial:          "0A:FD:50:2B",
+				ignoreValidationResults: []string{ValidationKeywordSerial},
+			},
+			validateFunc: Config.ApplyCertSerialNumberValidationResults,
+			applyResults: false,
+		},
+		{
+			name:         "DefaultValidateSelfSignedResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertSelfSignedValidationResults,
+			applyResults: defaultApplyCertSelfSignedValidationResults,
+		},
+		{
+			name: "ApplyValidateSelfSignedResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordSelfSigned},
+			},
+			validateFunc: Config.ApplyCertSelfSignedValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateBlocklistResultsWithoutBlocklistFile",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertBlocklistValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "DefaultValidateBlocklistResultsWithBlocklistFile",
+			cfg: Config{
+				BlocklistFile: "/etc/check-cert/blocklist.txt",
+			},
+			validateFunc: Config.ApplyCertBlocklistValidationResults,
+			applyResults: defaultApplyCertBlocklistValidationResults,
+		},
+		{
+			name:         "DefaultValidateDistrustedCAsResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertDistrustedCAsValidationResults,
+			applyResults: defaultApplyCertDistrustedCAsValidationResults,
+		},
+		{
+			name: "IgnoreValidateDistrustedCAsResults",
+			cfg: Config{
+				ignoreValidationResults: []string{ValidationKeywordDistrusted},
+			},
+			validateFunc: Config.ApplyCertDistrustedCAsValidationResults,
+			applyResults: false,
+		},
+		{
+			name:         "DefaultValidateDuplicateCertsResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertDuplicateCertsValidationResults,
+			applyResults: defaultApplyCertDuplicateCertsValidationResults,
+		},
+		{
+			name: "IgnoreValidateDuplicateCertsResults",
+			cfg: Config{
+				ignoreValidationResults: []string{ValidationKeywordDuplicates},
+			},
+			validateFunc: Config.ApplyCertDuplicateCertsValidationResults,
+			applyResults: false,
+		},
+		{
+			name:         "DefaultValidateChainConstraintsResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertChainConstraintsValidationResults,
+			applyResults: defaultApplyCertChainConstraintsValidationResults,
+		},
+		{
+			name: "ApplyValidateChainConstraintsResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordConstraints},
+			},
+			validateFunc: Config.ApplyCertChainConstraintsValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateKeyUsageResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertKeyUsageValidationResults,
+			applyResults: defaultApplyCertKeyUsageValidationResults,
+		},
+		{
+			name: "ApplyValidateKeyUsageResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordKeyUsage},
+			},
+			validateFunc: Config.ApplyCertKeyUsageValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateCTLogsResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertCTLogsValidationResults,
+			applyResults: defaultApplyCertCTLogsValidationResults,
+		},
+		{
+			name: "ApplyValidateCTLogsResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordCTLogs},
+			},
+			validateFunc: Config.ApplyCertCTLogsValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateRenewalInfoResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyRenewalInfoValidationResults,
+			applyResults: defaultApplyRenewalInfoValidationResults,
+		},
+		{
+			name: "ApplyValidateRenewalInfoResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordRenewalInfo},
+			},
+			validateFunc: Config.ApplyRenewalInfoValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateMinTLSVersionResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyMinTLSVersionValidationResults,
+			applyResults: defaultApplyMinTLSVersionValidationResults,
+		},
+		{
+			name: "ApplyValidateMinTLSVersionResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordTLSVersion},
+			},
+			validateFunc: Config.ApplyMinTLSVersionValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateWeakCipherSuitesResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyWeakCipherSuitesValidationResults,
+			applyResults: defaultApplyWeakCipherSuitesValidationResults,
+		},
+		{
+			name: "ApplyValidateWeakCipherSuitesResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordWeakCiphers},
+			},
+			validateFunc: Config.ApplyWeakCipherSuitesValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateExpiryCliffResults",
+			cfg:          Config{},
+			validateFunc: Config.ApplyExpiryCliffValidationResults,
+			applyResults: defaultApplyExpiryCliffValidationResults,
+		},
+		{
+			name: "ApplyValidateExpiryCliffResults",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordExpiryCliff},
+			},
+			validateFunc: Config.ApplyExpiryCliffValidationResults,
+			applyResults: true,
+		},
+		{
+			name:         "DefaultValidateExtKeyUsageResultsWithoutEKUs",
+			cfg:          Config{},
+			validateFunc: Config.ApplyCertExtKeyUsageValidationResults,
+			applyResults: false,
+		},
+		{
+			name: "DefaultValidateExtKeyUsageResultsWithDisallowedEKUs",
+			cfg: Config{
+				disallowedEKUs: []string{"codeSigning"},
+			},
+			validateFunc: Config.ApplyCertExtKeyUsageValidationResults,
+			applyResults: defaultApplyCertExtKeyUsageValidationResults,
+		},
+		{
+			name: "ApplyValidateExtKeyUsageResultsWithoutEKUs",
+			cfg: Config{
+				applyValidationResults: []string{ValidationKeywordEKU},
+			},
+			validateFunc: Config.ApplyCertExtKeyUsageValidationResults,
+			applyResults: true,
+		},
+		{
+			name: "IgnoreValidateExtKeyUsageResults",
+			cfg: Config{
+				requiredEKUs:            []string{"serverAuth"},
+				ignoreValidationResults: []string{ValidationKeywordEKU},
+			},
+			validateFunc: Config.ApplyCertExtKeyUsageValidationResults,
+			applyResults: false,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+
+			switch {
+
+			// If the test case indicates that validation results should be
+			// applied but the validation function indicates it is not.
+			case tt.applyResults && !tt.validateFunc(tt.cfg):
+				t.Errorf(
+					"want: validation results applied (%v); got: validation results applied (%v)",
+					tt.applyResults,
+					tt.validateFunc(tt.cfg),
+				)
+
+			// If the test case indicates that validation results should not
+			// be applied but the validation function indicates it is.
+			case !tt.applyResults && tt.validateFunc(tt.cfg):
+				t.Errorf(
+					"want: validation results applied (%v); got: validation results applied (%v)",
+					tt.applyResults,
+					tt.validateFunc(tt.cfg),
+				)
+
+			// Test case and validation function agree.
+			default:
+				t.Logf(
+					"want: validation results applied (%v); got: validation results applied (%v)",
+					tt.applyResults,
+					tt.validateFunc(tt.cfg),
+				)
+			}
+
+		})
+	}
+
+}
+
+func TestParseConfigFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		settings    []configFileSetting
+		errExpected bool
+	}{
+		{
+			name: "SharedAndSectionSettings",
+			input: `# shared settings
+age-warning = 45
+log-level = "debug" # trailing comment
+
+[check_cert]
+apply-validation-result = ["expiration", 'sans']
+`,
+			settings: []configFileSetting{
+				{name: "age-warning", values: []string{"45"}},
+				{name: "log-level", values: []string{"debug"}},
+				{section: "check_cert", name: "apply-validation-result", values: []string{"expiration", "sans"}},
+			},
+			errExpected: false,
+		},
+		{
+			name:        "UnknownSection",
+			input:       "[check-cert]\nage-warning = 45\n",
+			errExpected: true,
+		},
+		{
+			name:        "MissingValue",
+			input:       "age-warning =\n",
+			errExpected: true,
+		},
+		{
+			name:        "UnterminatedArray",
+			input:       "ports = [443, 8443\n",
+			errExpected: true,
+		},
+		{
+			name:        "UnterminatedString",
+			input:       "server = \"www.example.com\n",
+			errExpected: true,
+		},
+		{
+			name:  "MultilineArray",
+			input: "[certsum]\nports = [\n  443,\n  8443,\n]\ntimeout = 2.5\n",
+			settings: []configFileSetting{
+				{section: "certsum", name: "ports", values: []string{"443", "8443"}},
+				{section: "certsum", name: "timeout", values: []string{"2.5"}},
+			},
+			errExpected: false,
+		},
+		{
+			name:        "UnsupportedDateTime",
+			input:       "age-warning = 2024-01-01T00:00:00Z\n",
+			errExpected: true,
+		},
+		{
+			name:        "UnsupportedInlineTable",
+			input:       "[check_cert]\nthresholds = { warning = 45 }\n",
+			errExpected: true,
+		},
+		{
+			name:        "UnsupportedNestedTable",
+			input:       "[check_cert.thresholds]\nwarning = 45\n",
+			errExpected: true,
+		},
+		{
+			name:        "UnsupportedArrayOfTables",
+			input:       "[[check_cert]]\nage-warning = 45\n",
+			errExpected: true,
+		},
+		{
+			name:        "UnsupportedNestedArray",
+			input:       "ports = [[443], [8443]]\n",
+			errExpected: true,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := parseConfigFile(strings.NewReader(tt.input))
+
+			switch {
+			case tt.errExpected && err == nil:
+				t.Fatal("want: error; got: nil")
+
+			case !tt.errExpected && err != nil:
+				t.Fatalf("want: no error; got: %v", err)
+
+			case tt.errExpected:
+				t.Logf("got expected error: %v", err)
+
+				return
+			}
+
+			if !reflect.DeepEqual(settings, tt.settings) {
+				t.Errorf("want: %+v; got: %+v", tt.settings, settings)
+			}
+		})
+	}
+}
+
+func TestCheckProfiles(t *testing.T) {
+
+	const appName string = "check_cert"
+
+	tests := []struct {
+		name                    string
+		args                    []string
+		configFile              string
+		ageWarning              int
+		ageCritical             int
+		applyValidationResults  []string
+		ignoreValidationResults []string
+		errExpected             bool
+	}{
+		{
+			name:                   "StrictProfile",
+			args:                   []string{"--profile", "strict"},
+			ageWarning:             45,
+			ageCritical:            30,
+			applyValidationResults: []string{"constraints", "keyusage", "self-signed"},
+		},
+		{
+			name:        "CommandLineOverridesProfile",
+			args:        []string{"--profile", "internal-pki", "--age-warning", "90"},
+			ageWarning:  90,
+			ageCritical: 30,
+			applyValidationResults: []string{
+				"constraints",
+				"keyusage",
+			},
+		},
+		{
+			name:                    "ConflictingKeywordSkipped",
+			args:                    []string{"--profile", "public-web", "--ignore-validation-result", "self-signed"},
+			ageWarning:              20,
+			ageCritical:             10,
+			applyValidationResults:  []string{"keyusage"},
+			ignoreValidationResults: []string{"self-signed"},
+		},
+		{
+			name:        "ConfigFileOverridesProfile",
+			args:        []string{"--profile", "lenient"},
+			configFile:  "[check_cert]\nage-critical = 10\n",
+			ageWarning:  14,
+			ageCritical: 10,
+		},
+		{
+			name:        "UnsupportedProfile",
+			args:        []string{"--profile", "paranoid"},
+			errExpected: true,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			oldArgs := os.Args
+
+			defer func() {
+				os.Args = oldArgs
+			}()
+
+			os.Args = append(
+				[]string{appName, "--server", "www.example.com"},
+				tt.args...,
+			)
+
+			if tt.configFile != "" {
+				configFile := filepath.Join(t.TempDir(), "config.toml")
+				if err := os.WriteFile(configFile, []byte(tt.configFile), 0600); err != nil {
+					t.Fatalf("failed to write configuration file: %v", err)
+				}
+
+				os.Args = append(os.Args, "--config-file", configFile)
+			}
+
+			// Reset parsed flags by discarding the previous default flagset
+			// and creating a new one from scratch.
+			flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+
+			cfg, err := New(AppType{Plugin: true})
+			switch {
+			case tt.errExpected && err == nil:
+				t.Fatal("want: error; got: nil")
+
+			case !tt.errExpected && err != nil:
+				t.Fatalf("want: no error; got: %v", err)
+
+			case tt.errExpected:
+				t.Logf("got expected error: %v", err)
+
+				return
+			}
+
+			if cfg.AgeWarning != tt.ageWarning || cfg.AgeCritical != tt.ageCritical {
+				t.Errorf(
+					"want: thresholds %d/%d; got: thresholds %d/%d",
+					tt.ageWarning,
+					tt.ageCritical,
+					cfg.AgeWarning,
+					cfg.AgeCritical,
+				)
+			}
+
+			if !reflect.DeepEqual([]string(cfg.applyValidationResults), tt.applyValidationResults) {
+				t.Errorf("want: applied %v; got: applied %v", tt.applyValidationResults, cfg.applyValidationResults)
+			}
+
+			if !reflect.DeepEqual([]string(cfg.ignoreValidationResults), tt.ignoreValidationResults) {
+				t.Errorf("want: ignored %v; got: ignored %v", tt.ignoreValidationResults, cfg.ignoreValidationResults)
+			}
+		})
+	}
+}
+
+func TestWithPolicySettings(t *testing.T) {
+
+	baseCfg := Config{
+		Port:                    443,
+		LoggingLevel:            defaultLogLevel,
+		Server:                  "www.example.com",
+		AgeWarning:              defaultCertExpireAgeWarning,
+		AgeCritical:             defaultCertExpireAgeCritical,
+		ignoreValidationResults: []string{ValidationKeywordSelfSigned},
+	}
+
+	tests := []struct {
+		name                    string
+		settings                map[string][]string
+		ageWarning              int
+		ageCritical             int
+		applyValidationResults  []string
+		ignoreValidationResults []string
+		errExpected             bool
+	}{
+		{
+			name: "ThresholdsAndKeywords",
+			settings: map[string][]string{
+				AgeWarningFlagLong:        {"60"},
+				AgeCriticalFlagLong:       {"30"},
+				ApplyValidationResultFlag: {ValidationKeywordSelfSigned, ValidationKeywordKeyUsage},
+			},
+			ageWarning:              60,
+			ageCritical:             30,
+			applyValidationResults:  []string{ValidationKeywordSelfSigned, ValidationKeywordKeyUsage},
+			ignoreValidationResults: []string{},
+		},
+		{
+			name:                    "NoSettings",
+			settings:                map[string][]string{},
+			ageWarning:              defaultCertExpireAgeWarning,
+			ageCritical:             defaultCertExpireAgeCritical,
+			ignoreValidationResults: []string{ValidationKeywordSelfSigned},
+		},
+		{
+			name: "KeywordAppliedAndIgnored",
+			settings: map[string][]string{
+				ApplyValidationResultFlag:  {ValidationKeywordKeyUsage},
+				IgnoreValidationResultFlag: {ValidationKeywordKeyUsage},
+			},
+			errExpected: true,
+		},
+		{
+			name: "InvalidThresholds",
+			settings: map[string][]string{
+				AgeCriticalFlagLong: {"90"},
+			},
+			errExpected: true,
+		},
+		{
+			name: "UnsupportedSetting",
+			settings: map[string][]string{
+				ServerFlagLong: {"www.example.org"},
+			},
+			errExpected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := baseCfg.WithPolicySettings(tt.settings)
+
+			switch {
+			case tt.errExpected && err == nil:
+				t.Fatal("want: error; got: nil")
+
+			case tt.errExpected:
+				t.Logf("got expected error: %v", err)
+
+				return
+
+			case err != nil:
+				t.Fatalf("want: no error; got: %v", err)
+			}
+
+			if cfg.AgeWarning != tt.ageWarning || cfg.AgeCritical != tt.ageCritical {
+				t.Errorf(
+					"want: %d/%d thresholds; got: %d/%d",
+					tt.ageWarning,
+					tt.ageCritical,
+					cfg.AgeWarning,
+					cfg.AgeCritical,
+				)
+			}
+
+			if len(cfg.applyValidationResults) != len(tt.applyValidationResults) ||
+				len(cfg.ignoreValidationResults) != len(tt.ignoreValidationResults) {
+				t.Errorf(
+					"want: apply %v, ignore %v; got: apply %v, ignore %v",
+					tt.applyValidationResults,
+					tt.ignoreValidationResults,
+					cfg.applyValidationResults,
+					cfg.ignoreValidationResults,
+				)
+			}
+
+			// The original configuration is not modified.
+			if len(baseCfg.ignoreValidationResults) != 1 {
+				t.Errorf("original configuration modified: %v", baseCfg.ignoreValidationResults)
+			}
+		})
+	}
+}
+
+func TestScanRateLimitFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		numTargets int
+		want       int
+	}{
+		{
+			name:       "UserSpecifiedLimit",
+			cfg:        Config{ScanRateLimit: 100},
+			numTargets: 5,
+			want:       100,
+		},
+		{
+			name:       "CalculatedLimitCappedToTargets",
+			cfg:        Config{},
+			numTargets: 5,
+			want:       5,
+		},
+		{
+			name:       "CalculatedLimitWithoutTargets",
+			cfg:        Config{},
+			numTargets: 0,
+			want:       1,
+		},
+		{
+			name:       "CalculatedLimitUpperBound",
+			cfg:        Config{},
+			numTargets: 1000000,
+			want:       -1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.cfg.ScanRateLimitFor(tt.numTargets)
+
+			switch {
+			// The calculated limit depends on the number of available CPUs
+			// so only the bounds are asserted.
+			case tt.want == -1:
+				if got < scanRateLimitAutoMin || got > scanRateLimitAutoMax {
+					t.Errorf(
+						"want: value between %d and %d; got: %d",
+						scanRateLimitAutoMin,
+						scanRateLimitAutoMax,
+						got,
+					)
+				}
+
+			case got != tt.want:
+				t.Errorf("want: %d; got: %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateDiffSince(t *testing.T) {
+	snapshotFile := filepath.Join(t.TempDir(), "snapshot.pem")
+	if err := os.WriteFile(snapshotFile, []byte("placeholder"), 0600); err != nil {
+		t.Fatalf("failed to create snapshot file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{},
+		},
+		{
+			name: "ExistingFile",
+			cfg:  Config{DiffSince: snapshotFile},
+		},
+		{
+			name:        "MissingFile",
+			cfg:         Config{DiffSince: filepath.Join(t.TempDir(), "missing.pem")},
+			errExpected: os.ErrNotExist,
+		},
+		{
+			name:        "Directory",
+			cfg:         Config{DiffSince: t.TempDir()},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "Timestamp",
+			cfg:         Config{DiffSince: "2024-06-01T00:00:00Z"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "WithTemplateFile",
+			cfg:         Config{DiffSince: snapshotFile, TemplateFile: snapshotFile},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDiffSince(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateWatch(t *testing.T) {
+	hookFile := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(hookFile, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatalf("failed to create hook file: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{},
+		},
+		{
+			name: "Server",
+			cfg:  Config{Watch: true, Server: "www.example.com", watchInterval: 5 * time.Minute},
+		},
+		{
+			name: "ServerWithExecHook",
+			cfg:  Config{Watch: true, Server: "www.example.com", watchInterval: 5 * time.Minute, ExecHook: hookFile},
+		},
+		{
+			name:        "ExecHookWithoutWatch",
+			cfg:         Config{Server: "www.example.com", ExecHook: hookFile},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "Filename",
+			cfg:         Config{Watch: true, InputFilename: hookFile, watchInterval: 5 * time.Minute},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "WithTemplateFile",
+			cfg:         Config{Watch: true, Server: "www.example.com", watchInterval: 5 * time.Minute, TemplateFile: hookFile},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "ZeroInterval",
+			cfg:         Config{Watch: true, Server: "www.example.com"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "SubSecondInterval",
+			cfg:         Config{Watch: true, Server: "www.example.com", watchInterval: 500 * time.Millisecond},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "MissingExecHook",
+			cfg:         Config{Watch: true, Server: "www.example.com", watchInterval: 5 * time.Minute, ExecHook: filepath.Join(t.TempDir(), "missing.sh")},
+			errExpected: os.ErrNotExist,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWatch(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateStatsFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{},
+		},
+		{
+			name: "NewFileInExistingDirectory",
+			cfg:  Config{StatsFile: filepath.Join(tempDir, "stats.jsonl")},
+		},
+		{
+			name:        "MissingDirectory",
+			cfg:         Config{StatsFile: filepath.Join(tempDir, "missing", "stats.jsonl")},
+			errExpected: os.ErrNotExist,
+		},
+		{
+			name:        "Directory",
+			cfg:         Config{StatsFile: tempDir},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateStatsFile(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateHostThrottle(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{timeoutAppInactivity: defaultAppTimeout},
+		},
+		{
+			name: "DelayAndJitter",
+			cfg:  Config{hostDelay: 250, scanJitter: 100, timeoutAppInactivity: defaultAppTimeout},
+		},
+		{
+			name:        "NegativeDelay",
+			cfg:         Config{hostDelay: -1, timeoutAppInactivity: defaultAppTimeout},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "NegativeJitter",
+			cfg:         Config{scanJitter: -1, timeoutAppInactivity: defaultAppTimeout},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "ExceedsAppTimeout",
+			cfg:         Config{hostDelay: 20000, scanJitter: 10000, timeoutAppInactivity: defaultAppTimeout},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHostThrottle(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestExcludedHosts(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		errExpected error
+	}{
+		{
+			name:     "IPv4Range",
+			patterns: []string{"192.168.2.10-20", "192.168.3.0/24"},
+		},
+		{
+			name:     "LargeIPv6CIDR",
+			patterns: []string{"2001:db8::/64"},
+		},
+		{
+			name:     "SmallIPv6Range",
+			patterns: []string{"2001:db8::10-1f"},
+		},
+		{
+			name:        "LargeIPv6Range",
+			patterns:    []string{"2001:db8::10-1f", "2001:db8::-2001:db8::1:0"},
+			errExpected: netutils.ErrIPv6RangeTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{excludeHosts: multiValueHostsFlag{givenValues: tt.patterns}}
+
+			_, err := cfg.ExcludedHosts()
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateCompareTo(t *testing.T) {
+	previousScan := filepath.Join(t.TempDir(), "previous.json")
+	if err := os.WriteFile(previousScan, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("Failed to write previous scan results: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{OutputFormat: OutputFormatJSON},
+		},
+		{
+			name: "TextOutput",
+			cfg:  Config{CompareTo: previousScan, OutputFormat: OutputFormatText},
+		},
+		{
+			name:        "JSONOutput",
+			cfg:         Config{CompareTo: previousScan, OutputFormat: OutputFormatJSON},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "MissingFile",
+			cfg:         Config{CompareTo: previousScan + ".missing", OutputFormat: OutputFormatText},
+			errExpected: os.ErrNotExist,
+		},
+		{
+			name:        "Directory",
+			cfg:         Config{CompareTo: filepath.Dir(previousScan), OutputFormat: OutputFormatText},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateCompareTo(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{},
+		},
+		{
+			name: "URL",
+			cfg:  Config{WebhookURL: "https://soar.example.com/api/events"},
+		},
+		{
+			name: "URLWithAuthHeader",
+			cfg: Config{
+				WebhookURL:            "https://soar.example.com/api/events",
+				WebhookAuthHeader:     "env:SOAR_AUTH",
+				WebhookAuthHeaderName: "ph-auth-token",
+			},
+		},
+		{
+			name:        "InvalidScheme",
+			cfg:         Config{WebhookURL: "ftp://soar.example.com/events"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "MissingHost",
+			cfg:         Config{WebhookURL: "soar.example.com/events"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "AuthHeaderWithoutURL",
+			cfg:         Config{WebhookAuthHeader: "env:SOAR_AUTH"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name: "PlaintextAuthHeader",
+			cfg: Config{
+				WebhookURL:            "https://soar.example.com/api/events",
+				WebhookAuthHeader:     "Bearer abc123",
+				WebhookAuthHeaderName: "Authorization",
+			},
+			errExpected: secrets.ErrPlaintextSecret,
+		},
+		{
+			name: "InvalidAuthHeaderName",
+			cfg: Config{
+				WebhookURL:            "https://soar.example.com/api/events",
+				WebhookAuthHeader:     "env:SOAR_AUTH",
+				WebhookAuthHeaderName: "Auth Token",
+			},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateWebhook(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateSimulateState(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{},
+		},
+		{
+			name: "Critical",
+			cfg:  Config{SimulateState: SimulateStateCritical},
+		},
+		{
+			name: "WarningWithMessage",
+			cfg:  Config{SimulateState: SimulateStateWarning, SimulateMessage: "pipeline test"},
+		},
+		{
+			name:        "InvalidState",
+			cfg:         Config{SimulateState: "ok"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "MessageWithoutState",
+			cfg:         Config{SimulateMessage: "pipeline test"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "Socket",
+			cfg:         Config{SimulateState: SimulateStateCritical, Socket: "/run/check_cert.sock"},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSimulateState(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateDNSSECMode(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{Server: "www.example.com"},
+		},
+		{
+			name: "Off",
+			cfg:  Config{Server: "www.example.com", ResolverDNSSECMode: ResolverDNSSECModeOff},
+		},
+		{
+			name: "Warn",
+			cfg:  Config{Server: "www.example.com", ResolverDNSSECMode: ResolverDNSSECModeWarn},
+		},
+		{
+			name: "Fail",
+			cfg:  Config{Server: "www.example.com", ResolverDNSSECMode: ResolverDNSSECModeFail},
+		},
+		{
+			name:        "InvalidMode",
+			cfg:         Config{Server: "www.example.com", ResolverDNSSECMode: "strict"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "MissingServer",
+			cfg:         Config{ResolverDNSSECMode: ResolverDNSSECModeFail},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "TargetsFile",
+			cfg:         Config{Server: "www.example.com", TargetsFile: "targets.txt", ResolverDNSSECMode: ResolverDNSSECModeWarn},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateResolverDNSSECMode(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestValidateServiceValidationResultKeywords(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         Config
+		errExpected error
+	}{
+		{
+			name: "NotSpecified",
+			cfg:  Config{},
+		},
+		{
+			name: "ApplySupportedKeywords",
+			cfg:  Config{applyValidationResults: []string{ValidationKeywordDuplicates, ValidationKeywordDistrusted}},
+		},
+		{
+			name: "IgnoreUnsupportedKeyword",
+			cfg:  Config{ignoreValidationResults: []string{ValidationKeywordBlocklist}},
+		},
+		{
+			name:        "ApplyBlocklist",
+			cfg:         Config{applyValidationResults: []string{ValidationKeywordBlocklist}},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "ApplyCTLogs",
+			cfg:         Config{applyValidationResults: []string{ValidationKeywordCTLogs}},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "ApplyRenewalInfo",
+			cfg:         Config{applyValidationResults: []string{ValidationKeywordRenewalInfo}},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateServiceValidationResultKeywords(tt.cfg)
+
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+		})
+	}
+}
+
+func TestMaxSeverity(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxSeverity []string
+		want        map[string]string
+		errExpected error
+	}{
+		{
+			name:        "WarningLimits",
+			maxSeverity: []string{"hostname=warning", " EXPIRATION = WARNING "},
+			want: map[string]string{
+				ValidationKeywordHostname:   "WARNING",
+				ValidationKeywordExpiration: "WARNING",
+			},
+		},
+		{
+			name: "NoLimits",
+			want: map[string]string{},
+		},
+		{
+			name:        "UnknownCheckName",
+			maxSeverity: []string{"tacos=warning"},
+			want:        map[string]string{"tacos": "WARNING"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "UnsupportedState",
+			maxSeverity: []string{"hostname=critical"},
+			want:        map[string]string{ValidationKeywordHostname: "CRITICAL"},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "MissingState",
+			maxSeverity: []string{"hostname"},
+			want:        map[string]string{},
+			errExpected: ErrUnsupportedOption,
+		},
+		{
+			name:        "ConflictingLimits",
+			maxSeverity: []string{"hostname=warning", "hostname=ok"},
+			want:        map[string]string{ValidationKeywordHostname: "OK"},
+			errExpected: ErrUnsupportedOption,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{maxSeverity: tt.maxSeverity}
+
+			err := validateMaxSeverity(cfg)
+			switch {
+			case tt.errExpected == nil && err != nil:
+				t.Errorf("want no error; got %v", err)
+			case tt.errExpected != nil && !errors.Is(err, tt.errExpected):
+				t.Errorf("want error %v; got %v", tt.errExpected, err)
+			}
+
+			if got := cfg.MaxSeverity(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want limits %v; got %v", tt.want, got)
+			}
+		})
+	}
 }
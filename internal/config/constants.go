@@ -7,6 +7,12 @@
 
 package config
 
+import (
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+)
+
 const myAppName string = "check-cert"
 const myAppURL string = "https://github.com/atc0005/check-cert"
 
@@ -23,50 +29,152 @@ const SkipSANSCheckKeyword string = "SKIPSANSCHECKS"
 // See https://tldp.org/LDP/abs/html/exitcodes.html for additional details.
 const ExitCodeCatchall int = 1
 
+// ExitCodeCertChainChanged indicates that a change to the certificate chain
+// was detected while watching for changes.
+const ExitCodeCertChainChanged int = 2
+
+// StdioFilename is used in place of an input or output filename to indicate
+// that standard input or standard output should be used instead of a file.
+const StdioFilename string = "-"
+
+// TrustStoreSystemKeyword is used in place of a certificate bundle filename
+// to indicate that the system trust store should be evaluated.
+const TrustStoreSystemKeyword string = "system"
+
 // Flag help text.
 const (
+	configFileFlagHelp                                       string = "Fully-qualified path to a configuration file providing default flag values (e.g., /etc/check-cert/config.toml). Settings use a subset of TOML syntax with flag names as keys; settings listed within a section named after an application (e.g., [check_cert]) apply to that application only. Flag values specified on the command-line take precedence."
+	printSchemaFlagHelp                                      string = "Whether to display the JSON Schema document describing the json and ndjson output formats and then immediately exit application."
+	printBackfillSchemaFlagHelp                              string = "Whether to display the JSON Schema document describing the records emitted by the backfill-dir flag and then immediately exit application."
 	versionFlagHelp                                          string = "Whether to display application version and then immediately exit application."
 	sansEntriesFlagHelp                                      string = "One or many names required to be in the Subject Alternate Names (SANs) list for a leaf certificate. If provided, this list of comma-separated values is required for the certificate to pass validation. If the case-insensitive " + SkipSANSCheckKeyword + " keyword is provided the results from this validation check will be flagged as ignored."
 	dnsNameFlagHelp                                          string = "A fully-qualified domain name or IP Address in the Subject Alternate Names (SANs) list for the leaf certificate. If specified, this value will be used when retrieving the certificate chain (SNI support) and for hostname verification. Required when evaluating certificate files."
 	logLevelFlagHelp                                         string = "Sets log level."
 	serverFlagHelp                                           string = "The fully-qualified domain name or IP Address used for certificate chain retrieval. This value should appear in the Subject Alternate Names (SANs) list for the leaf certificate unless also using the " + DNSNameFlagLong + " flag."
-	hostsFlagHelp                                            string = "List of comma-separated individual IP Addresses, CIDR IP ranges, partial (dash-separated) ranges (e.g., 192.168.2.10-15), hostnames or FQDNs to scan for certificates."
+	hostsFlagHelp                                            string = "List of comma-separated individual IP Addresses, CIDR IP ranges, partial (dash-separated) ranges (e.g., 192.168.2.10-15), hostnames, FQDNs or DNS SRV record names (e.g., _ldaps._tcp.example.com) to scan for certificates. Each target advertised by a SRV record is scanned using the advertised port."
 	portFlagHelp                                             string = "TCP port of the remote certificate-enabled service. This is usually 443 (HTTPS) or 636 (LDAPS)."
-	portsListFlagHelp                                        string = "List of comma-separated TCP ports to check for certificates. If not specified, the list defaults to 443 only."
+	portsListFlagHelp                                        string = "List of comma-separated TCP ports or port ranges (e.g., 443,8000-8100,9443) to check for certificates. If not specified, the list defaults to 443 only."
+	serverPortsListFlagHelp                                  string = "List of comma-separated TCP ports or port ranges (e.g., 443,8000-8100,9443) of certificate-enabled services on the specified server. If specified, the certificate chain for each port is evaluated separately and the results are combined using the most severe service check state. Overrides the " + PortFlagLong + " flag."
 	timeoutConnectFlagHelp                                   string = "Timeout value in seconds allowed before a connection attempt to a remote certificate-enabled service (in order to retrieve the certificate) is abandoned and an error returned."
+	dnsTimeoutFlagHelp                                       string = "Timeout value in seconds allowed for resolving a hostname or FQDN to an IP Address. If not specified, the `timeout` value is used."
+	dnsServerFlagHelp                                        string = "DNS server (IP Address or hostname with an optional port, e.g., 10.0.0.53 or 10.0.0.53:5353) used to resolve hostnames or FQDNs to IP Addresses instead of the DNS servers used by the system resolver (e.g., an internal split-horizon DNS server). The dns-timeout flag value applies to resolution attempts. If not specified, the system resolver is used."
+	resolverDNSSECFlagHelp                                   string = "Whether the DNS server (the dns-server flag value or the first DNS server used by the system resolver) is required to report the DNS answer for the hostname or FQDN specified via the server flag as authenticated using DNSSEC. DNSSEC signatures are NOT validated by this application; only the Authenticated Data (AD) bit set by the DNS server is checked, so the DNS server and the network path to it must be trusted (e.g., a DNSSEC validating resolver on localhost). If warn, an answer without the AD bit set results in (at least) a WARNING state. If fail, an answer without the AD bit set results in a CRITICAL state and the certificate chain is not retrieved. The AD bit status is noted in verbose output."
+	simulateStateFlagHelp                                    string = "Service check state to simulate in order to verify notification pipelines and dashboards end-to-end. If specified, the certificate chain is evaluated as usual (including the certificate metadata payload, if requested) but the final plugin state is replaced by the given state and the one-line summary by the simulate-message flag value. The detailed output notes that the state was simulated."
+	simulateMessageFlagHelp                                  string = "One-line summary emitted along with the service check state specified via the simulate-state flag. If not specified, a message noting the simulated state is used. Requires the simulate-state flag."
+	connectTimeoutFlagHelp                                   string = "Timeout value in seconds allowed for establishing the TCP connection to a remote certificate-enabled service. If not specified, the `timeout` value is used."
+	handshakeTimeoutFlagHelp                                 string = "Timeout value in seconds allowed for completing the TLS handshake with a remote certificate-enabled service once connected. If not specified, the `timeout` value is used."
+	retriesFlagHelp                                          string = "The number of additional attempts made to retrieve a certificate chain after a transient connection failure (e.g., connection timeout or reset). Delays between attempts increase exponentially (with jitter) starting from the `retry-delay` value. A value of 0 disables retries."
+	fipsModeFlagHelp                                         string = "Whether FIPS mode should be used regardless of whether a FIPS-restricted build or environment is detected. In FIPS mode, certificate signatures using legacy MD5 or SHA1 algorithms are not verified; chain positions are determined using issuer and subject names (and key identifiers if present) and the lack of verification is noted in the report output."
+	retryDelayFlagHelp                                       string = "The number of milliseconds to wait before the first retry attempt when retrieving a certificate chain. The delay is doubled for each subsequent retry attempt."
 	timeoutPortScanFlagHelp                                  string = "The number of milliseconds before a connection attempt during a port scan is abandoned and an error returned. This timeout value is separate from the general `timeout` value used when retrieving certificates. This setting is used specifically to quickly determine port state as part of bulk operations where speed is crucial."
+	pluginTimeoutFlagHelp                                    string = "The number of seconds the plugin is allowed to run before the service check is considered timed out (e.g., the NRPE or Nagios service check timeout). As this deadline approaches, optional validation checks which have not yet started are skipped and flagged as such in the report output so that results for completed validation checks are still emitted. A value of 0 disables this behavior."
 	timeoutAppInactivityFlagHelp                             string = "The number of seconds the application is allowed to remain inactive (i.e., \"hung\") before it is automatically terminated."
 	scanRateLimitFlagHelp                                    string = "Maximum concurrent port and certificate scans. Remaining scans are queued until an existing scan completes."
+	scannerScanRateLimitFlagHelp                             string = "Maximum concurrent port and certificate scans. Remaining scans are queued until an existing scan completes. If not specified (or 0), a limit is calculated from the number of available CPUs and the number of hosts and ports to scan."
+	hostDelayFlagHelp                                        string = "The minimum number of milliseconds between connection attempts (port checks and certificate retrieval) to the same IP Address. Connections to other IP Addresses are not delayed. Use this setting to avoid triggering connection rate protection or intrusion detection signatures when scanning many ports on a single host. A value of 0 disables this behavior."
+	scanJitterFlagHelp                                       string = "The maximum number of milliseconds of random delay added before each connection attempt (port checks and certificate retrieval) to an IP Address. Use this setting (optionally alongside the `host-delay` flag) to avoid a predictable connection pattern. A value of 0 disables this behavior."
+	backfillDirFlagHelp                                      string = "Fully-qualified path to a directory of saved check_cert plugin output files (e.g., archived service check results). Each file is converted into a normalized JSON record (one per line) using the embedded certificate metadata payload if present or the human-readable report if not. If specified, no certificate chain is retrieved or evaluated."
+	templateFileFlagHelp                                     string = "Fully-qualified path to a Go text/template file used to generate custom output for the evaluated certificate chain. If specified, the template output replaces the standard summary and details output."
+	statsSummaryFlagHelp                                     string = "Fully-qualified path to a run statistics file recorded via the stats-file flag of the check_cert or certsum applications. A daily summary of runs, targets checked, failures and runtime (along with trends) is emitted followed by the total failures by category. If specified, no certificate chain is retrieved or evaluated."
+	diffSinceFlagHelp                                        string = "Fully-qualified path to a previously recorded snapshot of the certificate chain (a PEM formatted certificate chain file, e.g., saved using cpcert, or a check_cert state file) to compare against the evaluated certificate chain. Changes since the snapshot (e.g., new serial number, different intermediate certificates, SANs entries changes) are listed side-by-side for use in change review."
+	watchFlagHelp                                            string = "Whether the certificate chain is retrieved again on the interval given by the " + WatchIntervalFlag + " flag after the initial report until it differs from the initial certificate chain. Changes to the certificate chain are listed side-by-side and the application exits with exit code 2. The IP Address resolved at startup is used for each retrieval attempt and failed retrieval attempts are logged and retried at the next interval. Useful when waiting for a certificate deployment (e.g., a load balancer rollout) to complete. Requires the " + ServerFlagLong + " flag or a URL pattern."
+	watchIntervalFlagHelp                                    string = "Time between certificate chain retrieval attempts when watching for changes (e.g., 30s, 5m or 1h). Requires the " + WatchFlag + " flag."
+	watchExecHookFlagHelp                                    string = "Fully-qualified path to an executable (e.g., a script) invoked when a change to the certificate chain is detected while watching for changes. The certificate chain changes report is provided to the executable via stdin and the previous and current leaf certificate fingerprints via environment variables. Failure of the executable is logged but does not affect the exit code. Requires the " + WatchFlag + " flag."
 	emitCertTextFlagHelp                                     string = "Toggles emission of x509 TLS certificates in an OpenSSL-inspired text format. This output is disabled by default."
 	inputFilenameFlagHelp                                    string = "Fully-qualified path to a PEM (text) or binary DER formatted input file containing one or more certificates."
 	certExpireAgeWarningFlagHelp                             string = "The number of days remaining before certificate expiration when this application will will flag the NotAfter certificate field as a WARNING state."
 	certExpireAgeCriticalFlagHelp                            string = "The number of days remaining before certificate expiration when this application will will flag the NotAfter certificate field as a CRITICAL state."
+	certExpireAgeWarningPercentFlagHelp                      string = "The percentage of certificate lifetime remaining when this application will flag the NotAfter certificate field as a WARNING state. The lifetime of the leaf certificate is used to convert this value to a number of days. If specified, overrides the age-warning flag. Requires the age-critical-percent flag."
+	certExpireAgeCriticalPercentFlagHelp                     string = "The percentage of certificate lifetime remaining when this application will flag the NotAfter certificate field as a CRITICAL state. The lifetime of the leaf certificate is used to convert this value to a number of days. If specified, overrides the age-critical flag. Requires the age-warning-percent flag."
+	certExpireAgeWarningIntermediateFlagHelp                 string = "The number of days remaining before expiration when this application will flag the NotAfter field of an intermediate certificate as a WARNING state. If not specified, the age-warning flag value is used. Requires the age-critical-intermediate flag."
+	certExpireAgeCriticalIntermediateFlagHelp                string = "The number of days remaining before expiration when this application will flag the NotAfter field of an intermediate certificate as a CRITICAL state. If not specified, the age-critical flag value is used. Requires the age-warning-intermediate flag."
+	certExpireAgeWarningRootFlagHelp                         string = "The number of days remaining before expiration when this application will flag the NotAfter field of a root certificate as a WARNING state. If not specified, the age-warning flag value is used. Requires the age-critical-root flag."
+	certExpireAgeCriticalRootFlagHelp                        string = "The number of days remaining before expiration when this application will flag the NotAfter field of a root certificate as a CRITICAL state. If not specified, the age-critical flag value is used. Requires the age-warning-root flag."
 	brandingFlagHelp                                         string = "Toggles emission of branding details with plugin status details. This output is disabled by default."
 	payloadFormatVersionFlagHelp                             string = "Specifies the format version to use when generating the (optional) certificate metadata payload. Version 0 is unstable."
 	payloadFlagHelp                                          string = "Toggles emission of encoded certificate chain payload. This output is disabled by default."
-	payloadWithFullChainFlagHelp                             string = "Toggles emission of encoded certificate chain payload with the full certificate chain included. This option is disabled by default due to the significant increase in payload size."
+	payloadWithFullChainFlagHelp                             string = "Toggles emission of encoded certificate chain payload with the full certificate chain included. Each certificate is included in PEM format (in the order served) in the cert_chain_original field, allowing central tooling to archive the certificate chain as served at check time without connecting to the server. This option is disabled by default due to the significant increase in payload size."
 	verboseOutputFlagHelp                                    string = "Toggles emission of detailed certificate metadata. This level of output is disabled by default."
 	omitSANsListFlagHelp                                     string = "Toggles listing of SANs entries list items in certificate metadata output. This list is included by default."
 	omitSANsEntriesFlagHelp                                  string = "Alias for \"" + OmitSANsListFlagLong + "\" flag"
+	hostsFileFlagHelp                                        string = "Fully-qualified path to a file listing IP Addresses, CIDR IP ranges, partial (dash-separated) ranges, hostnames, FQDNs or DNS SRV record names to scan for certificates, one per line. An entry may be followed by a colon and a comma-separated list of ports (e.g., 192.168.2.0/24:443,8443) to override the ports flag for that entry. Blank lines and text following a # character are ignored. May be combined with the hosts flag."
+	excludeHostsFlagHelp                                     string = "List of comma-separated individual IP Addresses, CIDR IP ranges, partial (dash-separated) ranges, hostnames or FQDNs to remove from the expanded set of hosts to scan (e.g., printers or out-of-band management controllers). Hostnames and FQDNs exclude the IP Addresses they resolve to. Excluded IP Addresses are applied after deduping and reported in the summary."
 	showHostsWithClosedPortsFlagHelp                         string = "Toggles listing all host port scan results, even for hosts without any specified ports in an open state."
 	showHostsWithValidCertsFlagHelp                          string = "Toggles listing all cert check results in overview output, even for hosts with valid certificates."
 	showValidCertsFlagHelp                                   string = "Toggles listing all certificates in output summary, even certificates which have passed all validity checks."
+	outputFormatFlagHelp                                     string = "Format used to emit scan results. The json format emits a single document for all discovered certificate chains and the ndjson format emits one document per line for each discovered certificate chain. The influx format emits InfluxDB line protocol records suitable for collection by the Telegraf exec input plugin. The markdown format emits tables suitable for pasting into wikis and chat. Progress output is suppressed for machine-readable formats."
+	inspectorOutputFormatFlagHelp                            string = "Format used to emit the certificate chain summary and details. The markdown format emits tables suitable for pasting into wikis and chat."
+	listenAddressFlagHelp                                    string = "The network address (host:port) where metrics are served. An empty host value listens on all interfaces."
+	monitorListenAddressFlagHelp                             string = "The network address (host:port) where the HTTP API is served. An empty host value listens on all interfaces."
+	checkIntervalFlagHelp                                    string = "Number of minutes between checks of all targets. Targets are first checked at startup."
+	profileCPUFlagHelp                                       string = "Fully-qualified path to a file where a CPU profile in pprof format is written. Profiling starts after flags are parsed and stops when the application exits. Intended for diagnosing performance issues."
+	profileMemFlagHelp                                       string = "Fully-qualified path to a file where a memory (heap) profile in pprof format is written when the application exits. Intended for diagnosing memory usage and allocation issues."
+	outputFileFlagHelp                                       string = "Fully-qualified path to a file where flattened scan results are written in CSV format, one row per discovered certificate. This file is written in addition to the summary output and is overwritten if it already exists."
+	exportBundleFlagHelp                                     string = "Fully-qualified path to a bundle file (gzip compressed tar archive) where scan results are written for transfer out of an isolated network. The bundle contains the scan results in JSON format, the discovered certificate chains in PEM format and scan metadata. The file is overwritten if it already exists."
+	importBundleFlagHelp                                     string = "Fully-qualified path to a bundle file created via the export-bundle flag. The certificate chains recorded in the bundle are evaluated and reported using the specified output format and thresholds in place of performing a scan. Host and port flags are not used when importing a bundle."
+	compareToFlagHelp                                        string = "Fully-qualified path to the results of a previous scan (emitted using the json or ndjson output formats) to compare against. If specified, endpoints (IP Address and port) discovered since the previous scan, endpoints no longer found and endpoints where the leaf certificate has changed (different fingerprint, issuer or expiration date) are reported. Only supported with the text output format."
+	webhookURLFlagHelp                                       string = "The http or https URL of an endpoint (e.g., a SOAR or automation platform) where the scan results are sent as a JSON document (the json output format) using a POST request once the scan completes. Results from a scan aborted due to the application timeout are not sent."
+	webhookAuthHeaderFlagHelp                                string = "Secret provider for the value of the authentication header (e.g., Bearer TOKEN) sent with the webhook request. Supported providers are env:NAME (environment variable), file:PATH (first line of a file) and cmd:COMMAND (first line of the output of a command). Plaintext values are not accepted so that secrets are not exposed on the command line. Requires the " + WebhookURLFlag + " flag."
+	webhookAuthHeaderNameFlagHelp                            string = "Name of the authentication header sent with the webhook request."
 	showOverviewFlagHelp                                     string = "Toggles summary output view from detailed to overview."
 	showPortScanResultsFlagHelp                              string = "Toggles listing host port scan results."
 	ignoreHostnameVerificationFailureIfEmptySANsListFlagHelp string = "Whether a hostname verification failure should be ignored if Subject Alternate Names (SANs) list is empty."
 	ignoreValidationResultsFlagHelp                          string = "List of keywords for certificate chain validation check result that should be explicitly ignored and not used to determine final validation state."
 	applyValidationResultsFlagHelp                           string = "List of keywords for certificate chain validation check results that should be explicitly applied and used to determine final validation state."
 	listIgnoredErrorsFlagHelp                                string = "Toggles emission of ignored validation check result errors. Disabled by default to reduce confusion."
+	profileFlagHelp                                          string = "Name of a check profile presetting a group of validation flags and expiration thresholds. Flag values specified on the command-line or via a configuration file take precedence over values preset by the profile."
+	daysRemainingOnlyFlagHelp                                string = "Toggles emitting only the number of days until the soonest certificate expiration (0 if already expired) or -1 if this could not be determined, with no other output. The exit code reflects the service check state as usual. Useful for event handlers and custom macros requiring a bare numeric value."
+	briefWhenOKFlagHelp                                      string = "Toggles omission of the detailed report output for OK results so that only the one-line summary and performance data are emitted. The detailed report output is included as usual for all other results. Useful for reducing Nagios log and notification volume for large numbers of monitored services."
+	showRemediationFlagHelp                                  string = "Toggles emission of a remediation section in the detailed report output listing copy-pasteable commands (openssl, certbot, cpcert) for common failures such as a missing intermediate certificate, misordered certificate chain or expired leaf certificate."
+	showCheckTimingsFlagHelp                                 string = "Toggles emission of the time taken to perform each validation check as performance data metrics and as a footer in the detailed report output. Useful for identifying validation checks to disable when plugin runtime approaches the service check timeout."
 	ignoreExpiredIntermediateCertificatesFlagHelp            string = "Whether expired intermediate certificates should be ignored."
 	ignoreExpiredRootCertificatesFlagHelp                    string = "Whether expired root certificates should be ignored."
+	ignoreFingerprintFlagHelp                                string = "List of SHA-256 fingerprints for certificates in the chain which should be ignored when evaluating expiration and signature algorithms. Colon delimited and plain hex values are accepted. This flag may be repeated or specified as a comma-separated list."
+	ignoreSerialFlagHelp                                     string = "List of serial numbers for certificates in the chain which should be ignored when evaluating expiration and signature algorithms (e.g., an expired cross-signed intermediate certificate which cannot be removed). Colon or dash delimited hex (e.g., as emitted by OpenSSL) and plain hex values are accepted. This flag may be repeated or specified as a comma-separated list."
 	ignoreExpiringIntermediateCertificatesFlagHelp           string = "Whether expiring intermediate certificates should be ignored."
 	ignoreExpiringRootCertificatesFlagHelp                   string = "Whether expiring root certificates should be ignored."
+	dependentOnConnectFailureFlagHelp                        string = "Whether the DEPENDENT service check state should be used instead of CRITICAL when a connection to the remote service cannot be established (e.g., connection refused, timeout). This allows Nagios dependency logic to suppress notifications when a parent dependency (e.g., a proxy or bastion host) fails."
+	execHookFlagHelp                                         string = "Fully-qualified path to an executable (e.g., a script) invoked after the check completes. The JSON encoded certificate metadata payload is provided to the executable via stdin and the final service check state via environment variables. Failure of the executable does not affect plugin state."
+	mapStateFlagHelp                                         string = "List of FROM=TO service check state overrides applied to the final plugin state (e.g., WARNING=OK or UNKNOWN=CRITICAL). This flag may be repeated or specified as a comma-separated list."
+	maxSeverityFlagHelp                                      string = "List of KEYWORD=STATE overrides limiting the most severe state that a failing validation check contributes to the final plugin state (e.g., hostname=warning). This flag may be repeated or specified as a comma-separated list. Supported states: [WARNING]."
+	requiredEKUsFlagHelp                                     string = "List of Extended Key Usage keywords that the leaf certificate is required to have. If not specified, serverAuth is required when Extended Key Usage validation is applied."
+	disallowedEKUsFlagHelp                                   string = "List of Extended Key Usage keywords that the leaf certificate is not permitted to have (e.g., codeSigning on a TLS endpoint)."
+	blocklistFileFlagHelp                                    string = "Fully-qualified path to a file containing known-compromised certificate SHA-256 fingerprints or serial numbers, one per line. Blank lines and text following a # character are ignored. If provided, the leaf certificate is required to not match any entry for the certificate to pass validation."
+	distrustedCAsFileFlagHelp                                string = "Fully-qualified path to a file containing distrusted certificate authority entries, one per line, replacing the built-in list. Each entry is spki: or fingerprint: followed by a SHA-256 hash in hex format and an optional description. Blank lines and text following a # character are ignored. If not specified, the built-in list is used when distrusted CA validation is applied."
+	ctSearchURLFlagHelp                                      string = "URL of a crt.sh compatible Certificate Transparency search API used when CT logs validation is applied. Unexpired certificates logged for the server name are compared against the leaf certificate to detect renewed certificates which have not been installed."
+	minTLSVersionFlagHelp                                    string = "Oldest TLS protocol version the server is permitted to negotiate. When minimum TLS version validation is applied, additional handshakes are made with each older protocol version (and legacy cipher suites) enabled and a WARNING state is reported if the server accepts any of them."
+	compareWithFlagHelp                                      string = "Server value with an optional port (e.g., staging.example.com:8443) of a second certificate-enabled service to evaluate and compare against the service specified by the server flag. Differences in the certificate chains served or validation check results are noted in the detailed output and result in (at least) a WARNING state. The DNS Name (or server) value is used for SNI support and hostname verification for both services. If a port is not specified the value of the port flag is used. Useful for verifying that a staging service serves the same renewed certificate chain before a blue/green cutover."
+	aggregateStrategyFlagHelp                                string = "Strategy used to combine the results for multiple targets (i.e., when the ports or targets-file flags are specified) into a single service check result. The worst strategy uses the most severe target state. The percentage-thresholds and count-thresholds strategies compare the percentage or number of targets with problems against the aggregate-warning and aggregate-critical thresholds."
+	aggregateWarningFlagHelp                                 string = "Percentage or number (depending on the aggregate-strategy flag) of targets with problems at which the combined service check result is WARNING. Defaults to 10 (percent) or 1 (target)."
+	aggregateCriticalFlagHelp                                string = "Percentage or number (depending on the aggregate-strategy flag) of targets with problems at which the combined service check result is CRITICAL. Defaults to 25 (percent) or 5 (targets)."
+	socketFlagHelp                                           string = "Fully-qualified path to a Unix domain socket where certificate-enabled services are evaluated on request. If specified, the plugin runs until interrupted and accepts newline-delimited JSON requests (e.g., {\"server\": \"www.example.com\", \"port\": 443}), writing a newline-delimited JSON response with the service check result for each. Avoids process startup overhead for local agents which query certificate status repeatedly. Not supported with the server, filename, ports, targets-file, compare-with, state-file or exec-hook flags."
+	targetsFileFlagHelp                                      string = "Fully-qualified path to a file listing certificate-enabled services to evaluate, one per line. Each line specifies a server value with an optional port (e.g., www.example.com:8443) followed by an optional DNS Name value and an optional tags=tag1,tag2 field used to select a policy. Blank lines and text following a # character are ignored. If a port is not specified for an entry, the value of the port flag (or each value of the ports flag) is used. Results for all entries are combined into a single service check result using the most severe state (see the aggregate-strategy flag)."
+	trustStoreFlagHelp                                       string = "Certificate bundle (e.g., a CA bundle embedded in an appliance image or container base layer) to evaluate, or the keyword system to evaluate the system trust store. Each certificate in the bundle is evaluated separately and a single service check result reports expired, expiring (per the age-warning and age-critical flags or chain position specific thresholds) and weak (weak key or non-root weak signature algorithm) certificates. Not supported with the server, filename, ports, targets-file, socket, compare-with or state-file flags."
+	acmeDirectoryURLFlagHelp                                 string = "URL of the ACME directory used to locate the ACME Renewal Information (ARI) endpoint when renewal info validation is applied. The renewal window suggested by the Certificate Authority for the leaf certificate is included in the output and a WARNING state is reported once the suggested renewal window has started."
+	ctSearchTokenFlagHelp                                    string = "Secret provider for the API token sent (as a bearer token) with CT search API requests. Supported providers are env:NAME (environment variable), file:PATH (first line of a file) and cmd:COMMAND (first line of the output of a command). Plaintext tokens are not accepted so that secrets are not exposed on the command line."
+	ctSearchTokenCmdFlagHelp                                 string = "Command (e.g., a password manager CLI) whose first line of output is used as the API token sent with CT search API requests. This is equivalent to specifying cmd:COMMAND via the ct-search-token flag."
+	policyFileFlagHelp                                       string = "Fully-qualified path to a policy file (subset of YAML syntax) mapping targets to required validation checks and thresholds. Each policy lists server name patterns (e.g., *.example.com) and/or tags (assigned via the targets file) along with the settings applied to matching targets (age-warning, age-critical, apply-validation-result, ignore-validation-result, min-tls-version). The first matching policy is applied to each target; settings not provided by the policy use flag values."
+	maxExternalRequestsFlagHelp                              string = "Maximum number of requests to external services (e.g., CT search API) made by network-dependent validation checks during a single execution. Validation checks which would exceed this limit are skipped and their results ignored. The default value of 0 disables this limit."
+	maxDownloadBytesFlagHelp                                 string = "Maximum number of bytes downloaded from external services (e.g., CT search API) by network-dependent validation checks during a single execution. Validation checks which would exceed this limit are skipped and their results ignored. The default value of 0 disables this limit."
+	stateFileFlagHelp                                        string = "Fully-qualified path to a file used to record details of the leaf certificate between plugin executions. If the leaf certificate has changed since the previous execution, SANs entries added or removed by the new certificate are noted in the report. The file is created if it does not already exist."
+	scannerStateFileFlagHelp                                 string = "Fully-qualified path to a file used to record the progress of a scan (completed host and port pairs along with the certificate chains discovered). If a scan is interrupted (e.g., via Ctrl+C or the application timeout), a later scan using the same file resumes where the earlier scan left off instead of starting over. The file is created if it does not already exist and is removed once a scan completes."
+	progressIntervalFlagHelp                                 string = "The number of seconds between progress reports (IP Addresses completed, ports scanned, certificate chains found and the estimated time remaining) emitted to stderr during a scan. Progress is also reported when the application receives the SIGUSR1 signal (where supported) regardless of this setting. A value of 0 disables interval progress reports."
+	statsFileFlagHelp                                        string = "Fully-qualified path to a file used to record local-only, aggregate run statistics (targets checked, failures by category, runtime) for each execution. One JSON record is appended per execution; no statistics are sent anywhere. The file is created if it does not already exist. See the lscert stats-summary flag for a summary of recorded statistics."
+	expiryCliffWindowFlagHelp                                string = "Number of days within which the expiration of multiple certificates is treated as a single expiry cliff requiring a bulk renewal. A value of 0 disables expiry cliff detection."
+	expiryCliffLeadTimeFlagHelp                              string = "Number of days before the earliest expiration date of an expiry cliff when the expiry cliff is reported. Bulk renewals generally require more lead time than renewing a single certificate, so this is usually larger than the age-warning flag value."
+	expectedSerialFlagHelp                                   string = "The serial number that the leaf certificate is required to have. Colon or dash delimited hex (e.g., as emitted by OpenSSL) and plain hex values are accepted. If provided, the leaf certificate serial number is required to match this value for the certificate to pass validation."
 )
 
 // Flag help text specific to the Copier app type.
 const (
-	outputFilenameFlagHelp  string = "Fully-qualified path to an output file to write one or more PEM (text) encoded certificates."
+	copierInputFlagHelp     string = "Fully-qualified path to a PEM (text) or binary DER formatted input file containing one or more certificates. Use " + StdioFilename + " to read from standard input."
+	outputFilenameFlagHelp  string = "Fully-qualified path to an output file to write one or more PEM (text) encoded certificates (or a PKCS #12 bundle if the " + KeyFileFlag + " flag is specified). Use " + StdioFilename + " to write to standard output; the report is then emitted to standard error."
 	certTypesToKeepFlagHelp string = "List of keywords for certificate types that should be kept from the input certificate chain when saving the output file."
+	dedupeFlagHelp          string = "Whether repeated certificates in the input certificate chain should be removed so that each unique certificate is written to the output file only once. A summary of the removed duplicate certificates is emitted."
+	sortChainFlagHelp       string = "Whether the input certificate chain should be sorted into canonical order (leaf certificate followed by each issuer in turn up to the root certificate) before writing the output file. By default the order of the input certificate chain is retained."
+	keyFileFlagHelp         string = "Fully-qualified path to a PEM encoded (unencrypted) private key file. If specified, the output file is written as a PKCS #12 (PFX) bundle combining the private key with the (filtered) certificate chain instead of as PEM encoded certificates. The certificate chain is required to include the certificate matching the private key."
+	p12PasswordFlagHelp     string = "Secret provider for the password protecting the PKCS #12 bundle written when the " + KeyFileFlag + " flag is specified. Supported providers are env:NAME (environment variable), file:PATH (first line of a file) and cmd:COMMAND (first line of the output of a command). Plaintext passwords are not accepted so that secrets are not exposed on the command line. If not specified, an empty password is used."
+	completeChainFlagHelp   string = "Whether missing intermediate certificates should be downloaded using the Authority Information Access (AIA) CA Issuers URLs listed in the input certificates. The completed certificate chain is written in order from leaf to root; root certificates are only written if present in the input certificate chain."
 )
 
 // shorthandFlagSuffix is appended to short flag help text to emphasize that
@@ -89,22 +197,54 @@ const (
 	IgnoreExpiredRootCertificatesFlag          string = "ignore-expired-root-certs"
 	IgnoreExpiringIntermediateCertificatesFlag string = "ignore-expiring-intermediate-certs"
 	IgnoreExpiringRootCertificatesFlag         string = "ignore-expiring-root-certs"
-
-	VersionFlagLong          string = "version"
-	OmitSANsListFlagLong     string = "omit-sans-list"
-	OmitSANsEntriesFlagLong  string = "omit-sans-entries"
-	VerboseFlagLong          string = "verbose"
-	VerboseFlagShort         string = "v"
-	BrandingFlag             string = "branding"
-	PayloadFlag              string = "payload"
-	PayloadWithFullChainFlag string = "payload-with-full-chain"
-	PayloadFormatVersionFlag string = "payload-format"
-	ServerFlagLong           string = "server"
-	ServerFlagShort          string = "s"
-	PortFlagLong             string = "port"
-	PortFlagShort            string = "p"
-	DNSNameFlagLong          string = "dns-name"
-	DNSNameFlagShort         string = "dn"
+	IgnoreSerialFlag                           string = "ignore-serial"
+	IgnoreFingerprintFlag                      string = "ignore-fingerprint"
+
+	VersionFlagLong               string = "version"
+	PrintSchemaFlag               string = "print-schema"
+	ConfigFileFlag                string = "config-file"
+	OmitSANsListFlagLong          string = "omit-sans-list"
+	OmitSANsEntriesFlagLong       string = "omit-sans-entries"
+	VerboseFlagLong               string = "verbose"
+	VerboseFlagShort              string = "v"
+	BrandingFlag                  string = "branding"
+	PayloadFlag                   string = "payload"
+	PayloadWithFullChainFlag      string = "payload-with-full-chain"
+	PayloadFormatVersionFlag      string = "payload-format"
+	ServerFlagLong                string = "server"
+	ServerFlagShort               string = "s"
+	PortFlagLong                  string = "port"
+	PortFlagShort                 string = "p"
+	DNSNameFlagLong               string = "dns-name"
+	DNSNameFlagShort              string = "dn"
+	ExpectedSerialFlag            string = "expected-serial"
+	DependentOnConnectFailureFlag string = "dependent-on-connect-failure"
+	ExecHookFlag                  string = "exec-hook"
+	BlocklistFileFlag             string = "blocklist-file"
+	DistrustedCAsFileFlag         string = "distrusted-cas-file"
+	StateFileFlag                 string = "state-file"
+	StatsFileFlag                 string = "stats-file"
+	TargetsFileFlag               string = "targets-file"
+	SocketFlag                    string = "socket"
+	TrustStoreFlag                string = "trust-store"
+	AggregateStrategyFlag         string = "aggregate-strategy"
+	CompareWithFlag               string = "compare-with"
+	AggregateWarningFlag          string = "aggregate-warning"
+	AggregateCriticalFlag         string = "aggregate-critical"
+	PolicyFileFlag                string = "policy-file"
+	CTSearchURLFlag               string = "ct-search-url"
+	CTSearchTokenFlag             string = "ct-search-token"
+	CTSearchTokenCmdFlag          string = "ct-search-token-cmd"
+	ACMEDirectoryURLFlag          string = "acme-directory-url"
+	MinTLSVersionFlag             string = "min-tls-version"
+	MaxExternalRequestsFlag       string = "max-external-requests"
+	MaxDownloadBytesFlag          string = "max-download-bytes"
+	MapStateFlag                  string = "map-state"
+	MaxSeverityFlag               string = "max-severity"
+	ExpiryCliffWindowFlag         string = "expiry-cliff-window"
+	ExpiryCliffLeadTimeFlag       string = "expiry-cliff-lead-time"
+	RequiredEKUsFlag              string = "required-eku"
+	DisallowedEKUsFlag            string = "disallowed-eku"
 
 	// Flags used for specifying a list of keywords used to explicitly ignore
 	// or apply validation check results when determining final plugin state.
@@ -112,23 +252,55 @@ const (
 	ApplyValidationResultFlag  string = "apply-validation-result"
 
 	ListIgnoredErrorsFlag             string = "list-ignored-errors"
+	ProfileFlag                       string = "profile"
+	BriefWhenOKFlag                   string = "brief-when-ok"
+	DaysRemainingOnlyFlag             string = "days-remaining-only"
+	ShowCheckTimingsFlag              string = "show-check-timings"
+	ShowRemediationFlag               string = "show-remediation"
+	PluginTimeoutFlag                 string = "plugin-timeout"
 	FilenameFlagLong                  string = "filename"        // inspector, plugin; potentially deprecated
 	InputFilenameFlagLong             string = "input-filename"  // copier
 	InputFilenameFlagShort            string = "if"              // copier
 	OutputFilenameFlagShort           string = "of"              // copier
 	OutputFilenameFlagLong            string = "output-filename" // copier
 	CertTypesToKeepFlagLong           string = "keep"            // copier
+	CompleteChainFlag                 string = "complete-chain"  // copier
+	SortChainFlag                     string = "sort-chain"      // copier
+	DedupeFlag                        string = "dedupe"          // copier
+	KeyFileFlag                       string = "key-file"        // copier
+	P12PasswordFlag                   string = "p12-password"    // copier
 	EmitCertTextFlagLong              string = "text"
+	BackfillDirFlag                   string = "backfill-dir"
+	StatsSummaryFlag                  string = "stats-summary"
+	TemplateFileFlag                  string = "template"
+	DiffSinceFlag                     string = "diff-since"
+	WatchFlag                         string = "watch"
+	WatchIntervalFlag                 string = "interval"
 	TimeoutFlagLong                   string = "timeout"
 	TimeoutFlagShort                  string = "t"
+	DNSTimeoutFlag                    string = "dns-timeout"
+	DNSServerFlag                     string = "dns-server"
+	ResolverDNSSECFlag                string = "resolver-dnssec"
+	SimulateStateFlag                 string = "simulate-state"
+	SimulateMessageFlag               string = "simulate-message"
+	ConnectTimeoutFlag                string = "connect-timeout"
+	HandshakeTimeoutFlag              string = "handshake-timeout"
+	RetriesFlag                       string = "retries"
+	RetryDelayFlag                    string = "retry-delay"
+	FIPSModeFlag                      string = "fips"
 	LogLevelFlagLong                  string = "log-level"
 	LogLevelFlagShort                 string = "ll"
 	TimeoutPortScanFlagLong           string = "scan-timeout"
 	TimeoutPortScanFlagShort          string = "st"
 	HostsFlagLong                     string = "hosts"
 	HostsFlagAlt                      string = "ips"
+	HostsFileFlag                     string = "hosts-file"
+	ExcludeHostsFlag                  string = "exclude-hosts"
 	ScanRateLimitFlagLong             string = "scan-rate-limit"
 	ScanRateLimitFlagShort            string = "srl"
+	HostDelayFlag                     string = "host-delay"
+	ScanJitterFlag                    string = "scan-jitter"
+	ProgressIntervalFlag              string = "progress-interval"
 	AppTimeoutFlagLong                string = "app-timeout"
 	AppTimeoutFlagShort               string = "at"
 	PortsFlagLong                     string = "ports"
@@ -142,6 +314,18 @@ const (
 	ShowValidCertsFlagLong            string = "show-valid-certs"
 	ShowValidCertsFlagShort           string = "svc"
 	ShowOverviewFlagLong              string = "show-overview"
+	OutputFormatFlag                  string = "output-format"
+	ListenAddressFlag                 string = "listen-address"
+	CheckIntervalFlag                 string = "check-interval"
+	ProfileCPUFlag                    string = "profile-cpu"
+	ProfileMemFlag                    string = "profile-mem"
+	OutputFileFlag                    string = "output-file"
+	ExportBundleFlag                  string = "export-bundle"
+	ImportBundleFlag                  string = "import-bundle"
+	CompareToFlag                     string = "compare-to"
+	WebhookURLFlag                    string = "webhook-url"
+	WebhookAuthHeaderFlag             string = "webhook-auth-header"
+	WebhookAuthHeaderNameFlag         string = "webhook-auth-header-name"
 	ShowOverviewFlagShort             string = "so"
 	SANsEntriesFlagLong               string = "sans-entries"
 	SANsEntriesFlagShort              string = "se"
@@ -149,6 +333,12 @@ const (
 	AgeWarningFlagShort               string = "w"
 	AgeCriticalFlagLong               string = "age-critical"
 	AgeCriticalFlagShort              string = "c"
+	AgeWarningPercentFlag             string = "age-warning-percent"
+	AgeCriticalPercentFlag            string = "age-critical-percent"
+	AgeWarningIntermediateFlag        string = "age-warning-intermediate"
+	AgeCriticalIntermediateFlag       string = "age-critical-intermediate"
+	AgeWarningRootFlag                string = "age-warning-root"
+	AgeCriticalRootFlag               string = "age-critical-root"
 )
 
 // Validation keywords used when explicitly ignoring or applying validation
@@ -156,9 +346,54 @@ const (
 //
 // NOTE: These need to be manually kept in sync with Flag Help Text.
 const (
-	ValidationKeywordExpiration string = "expiration"
-	ValidationKeywordHostname   string = "hostname"
-	ValidationKeywordSANsList   string = "sans"
+	ValidationKeywordExpiration  string = "expiration"
+	ValidationKeywordHostname    string = "hostname"
+	ValidationKeywordSANsList    string = "sans"
+	ValidationKeywordSerial      string = "serial"
+	ValidationKeywordEKU         string = "eku"
+	ValidationKeywordConstraints string = "constraints"
+	ValidationKeywordKeyUsage    string = "keyusage"
+	ValidationKeywordDuplicates  string = "duplicates"
+	ValidationKeywordSelfSigned  string = "self-signed"
+	ValidationKeywordDistrusted  string = "distrusted"
+	ValidationKeywordBlocklist   string = "blocklist"
+	ValidationKeywordCTLogs      string = "ct"
+	ValidationKeywordTLSVersion  string = "tls-version"
+	ValidationKeywordWeakCiphers string = "weak-ciphers"
+	ValidationKeywordExpiryCliff string = "expiry-cliff"
+	ValidationKeywordRenewalInfo string = "ari"
+)
+
+// Strategies used to combine the results for multiple targets into a single
+// service check result.
+const (
+	AggregateStrategyWorst      string = "worst"
+	AggregateStrategyPercentage string = "percentage-thresholds"
+	AggregateStrategyCount      string = "count-thresholds"
+)
+
+// Service check states which may be simulated to verify notification
+// pipelines.
+const (
+	SimulateStateCritical string = "critical"
+	SimulateStateWarning  string = "warning"
+)
+
+// Modes used to determine how DNS answers which the DNS server does not
+// report as authenticated using DNSSEC are handled.
+const (
+	ResolverDNSSECModeOff  string = "off"
+	ResolverDNSSECModeWarn string = "warn"
+	ResolverDNSSECModeFail string = "fail"
+)
+
+// Output format keywords used when selecting the format of scan results.
+const (
+	OutputFormatText     string = "text"
+	OutputFormatJSON     string = "json"
+	OutputFormatNDJSON   string = "ndjson"
+	OutputFormatInflux   string = "influx"
+	OutputFormatMarkdown string = "markdown"
 )
 
 // Certificate type keywords used when filtering specific certificate types
@@ -177,6 +412,11 @@ const (
 	defaultDNSName               string = ""
 	defaultPort                  int    = 443
 	defaultEmitCertText          bool   = false
+	defaultBackfillDir           string = ""
+	defaultStatsSummary          string = ""
+	defaultTemplateFile          string = ""
+	defaultDiffSince             string = ""
+	defaultWatch                 bool   = false
 	defaultFilename              string = "" // inspector, plugin; potentially deprecated
 	defaultBranding              bool   = false
 	defaultPayload               bool   = false
@@ -185,6 +425,8 @@ const (
 	defaultVerboseOutput         bool   = false
 	defaultOmitSANsEntriesList   bool   = false
 	defaultDisplayVersionAndExit bool   = false
+	defaultPrintSchemaAndExit    bool   = false
+	defaultConfigFile            string = ""
 
 	// Default WARNING threshold is 30 days
 	defaultCertExpireAgeWarning int = 30
@@ -192,10 +434,45 @@ const (
 	// Default CRITICAL threshold is 15 days
 	defaultCertExpireAgeCritical int = 15
 
+	// Expiration thresholds specified as a percentage of certificate
+	// lifetime remaining are disabled by default.
+	defaultCertExpireAgeWarningPercent  int = 0
+	defaultCertExpireAgeCriticalPercent int = 0
+
+	// Intermediate and root certificates use the leaf certificate expiration
+	// thresholds by default.
+	defaultCertExpireAgeWarningIntermediate  int = 0
+	defaultCertExpireAgeCriticalIntermediate int = 0
+	defaultCertExpireAgeWarningRoot          int = 0
+	defaultCertExpireAgeCriticalRoot         int = 0
+
 	// Default timeout (in seconds) used when retrieving a certificate from a
 	// specified TCP port.
 	defaultConnectTimeout int = 10
 
+	// Default per-phase timeouts (in seconds) used when resolving a hostname
+	// and retrieving a certificate. A value of 0 indicates that the general
+	// connection timeout is used.
+	defaultDNSTimeout       int = 0
+	defaultDialTimeout      int = 0
+	defaultHandshakeTimeout int = 0
+
+	// Default number of retry attempts made after a transient failure to
+	// retrieve a certificate chain.
+	defaultRetries int = 0
+
+	// Default delay (in milliseconds) before the first retry attempt when
+	// retrieving a certificate chain.
+	defaultRetryDelay int = 500
+
+	// FIPS mode is only used by default if a FIPS-restricted build or
+	// environment is detected.
+	defaultFIPSMode bool = false
+
+	// Maximum number of retry attempts permitted when retrieving a
+	// certificate chain.
+	maxRetries int = 10
+
 	// Default choice of whether Go 1.17+ behavior of failing hostname
 	// verification for empty SANs list should be ignored (NOTE: only applies
 	// when the SANs list for a certificate is completely empty).
@@ -221,6 +498,29 @@ const (
 	// or ignored).
 	defaultListIgnoredValidationCheckResultErrors bool = false
 
+	// Whether the time taken to perform each validation check is included in
+	// performance data metrics and the final plugin report output.
+	defaultShowCheckTimings bool = false
+
+	// Remediation commands are not included in the report output by
+	// default.
+	defaultShowRemediation bool = false
+
+	// Default choice of whether the detailed report output is omitted for OK
+	// results.
+	defaultBriefWhenOK bool = false
+
+	// Plugin output is not limited to the number of days remaining by
+	// default.
+	defaultDaysRemainingOnly bool = false
+
+	// No check profile is applied by default.
+	defaultProfile string = ""
+
+	// Default plugin timeout (in seconds). A value of 0 disables skipping
+	// optional validation checks as the plugin timeout approaches.
+	defaultPluginTimeout int = 0
+
 	// Whether expiration date validation check results should be applied when
 	// determining overall validation state of a certificate chain by default.
 	//
@@ -239,6 +539,181 @@ const (
 	//
 	// This is set based on existing behavior in prior stable releases.
 	defaultApplyCertSANsListValidationResults bool = true
+
+	// Whether serial number validation check results should be applied when
+	// determining overall validation state of a certificate chain by
+	// default. Requires that an expected serial number also be specified.
+	defaultApplyCertSerialNumberValidationResults bool = true
+
+	// No expected serial number is specified by default.
+	defaultExpectedSerial string = ""
+
+	// Whether Key Usage and Basic Constraints validation check results
+	// should be applied when determining overall validation state of a
+	// certificate chain by default.
+	//
+	// Prior stable releases did not evaluate these values, so this
+	// validation check is opt-in.
+	defaultApplyCertChainConstraintsValidationResults bool = false
+
+	// Whether Key Usage consistency validation check results should be
+	// applied when determining overall validation state of a certificate
+	// chain by default. Internal CAs commonly use templates that do not
+	// match modern expectations, so this validation check is opt-in.
+	defaultApplyCertKeyUsageValidationResults bool = false
+
+	// Whether duplicate certificate validation check results should be
+	// applied when determining overall validation state of a certificate
+	// chain by default. Redundant certificates are reported as a WARNING.
+	//
+	// Prior stable releases did not flag redundant certificates, so this
+	// validation check is opt-in.
+	defaultApplyCertDuplicateCertsValidationResults bool = false
+
+	// Connection failures are treated as a CRITICAL state by default.
+	defaultDependentOnConnectFailure bool = false
+
+	// Whether self-signed leaf certificate validation check results should
+	// be applied when determining overall validation state of a certificate
+	// chain by default.
+	//
+	// Self-signed certificates are common for internal services and were not
+	// flagged by prior stable releases, so this validation check is opt-in.
+	defaultApplyCertSelfSignedValidationResults bool = false
+
+	// Whether distrusted CA validation check results should be applied when
+	// determining overall validation state of a certificate chain by
+	// default. Clients reject chains relying on these CAs regardless of
+	// expiration dates.
+	//
+	// Prior stable releases did not evaluate chains against a list of
+	// distrusted CAs, so this validation check is opt-in.
+	defaultApplyCertDistrustedCAsValidationResults bool = false
+
+	// The built-in list of distrusted CAs is used by default.
+	defaultDistrustedCAsFile string = ""
+
+	// No exec hook is invoked by default.
+	defaultExecHook string = ""
+
+	// Whether blocklist validation check results should be applied when
+	// determining overall validation state of a certificate chain by
+	// default. Requires that a blocklist file also be specified.
+	defaultApplyCertBlocklistValidationResults bool = true
+
+	// No blocklist file is used by default.
+	defaultBlocklistFile string = ""
+
+	// Whether CT logs validation check results should be applied when
+	// determining overall validation state of a certificate chain by
+	// default. This validation check queries an external service, so it is
+	// opt-in.
+	defaultApplyCertCTLogsValidationResults bool = false
+
+	// The public crt.sh service is used for CT log searches by default.
+	defaultCTSearchURL string = "https://crt.sh/"
+
+	// An API token is not sent with CT search API requests by default.
+	defaultCTSearchToken    string = ""
+	defaultCTSearchTokenCmd string = ""
+
+	// Whether renewal info validation check results should be applied when
+	// determining overall validation state of a certificate chain by
+	// default. This validation check queries an external service, so it is
+	// opt-in.
+	defaultApplyRenewalInfoValidationResults bool = false
+
+	// The Let's Encrypt production ACME directory is used to locate the
+	// renewal information endpoint by default.
+	defaultACMEDirectoryURL string = "https://acme-v02.api.letsencrypt.org/directory"
+
+	// Whether minimum TLS version validation check results should be
+	// applied when determining overall validation state by default. This
+	// validation check makes additional connections to the server, so it is
+	// opt-in.
+	defaultApplyMinTLSVersionValidationResults bool = false
+
+	// Whether weak cipher suites validation check results should be applied
+	// when determining overall validation state by default. This validation
+	// check makes additional connections to the server, so it is opt-in.
+	defaultApplyWeakCipherSuitesValidationResults bool = false
+
+	// Whether expiry cliff validation check results should be applied when
+	// determining overall validation state by default. Expiring certificates
+	// are already reported by the expiration validation check, so this
+	// validation check is opt-in.
+	defaultApplyExpiryCliffValidationResults bool = false
+
+	// Certificates expiring within 14 days of each other are treated as a
+	// single expiry cliff.
+	defaultExpiryCliffWindow int = 14
+
+	// Expiry cliffs are reported 90 days ahead of the earliest expiration.
+	defaultExpiryCliffLeadTime int = 90
+
+	// TLS 1.2 is the oldest TLS protocol version the server is permitted to
+	// negotiate by default.
+	defaultMinTLSVersion string = "1.2"
+
+	// Requests made and bytes downloaded by network-dependent validation
+	// checks are not limited by default.
+	defaultMaxExternalRequests int   = 0
+	defaultMaxDownloadBytes    int64 = 0
+
+	// No state file is used by default.
+	defaultStateFile string = ""
+
+	// No run statistics are recorded by default.
+	defaultStatsFile string = ""
+
+	// No targets file is used by default.
+	defaultTargetsFile string = ""
+
+	// Requests are not accepted via a Unix domain socket by default.
+	defaultSocket string = ""
+
+	// No trust store certificate bundle is evaluated by default.
+	defaultTrustStore string = ""
+
+	// The system resolver is used by default.
+	defaultDNSServer string = ""
+
+	// DNS answers are not required to be reported as authenticated using
+	// DNSSEC by default.
+	defaultResolverDNSSECMode string = ResolverDNSSECModeOff
+
+	// The actual service check state is emitted by default.
+	defaultSimulateState   string = ""
+	defaultSimulateMessage string = ""
+
+	// No second service is compared against by default.
+	defaultCompareWith string = ""
+
+	// Certificate chains are retrieved using a TLS handshake over TCP by
+	// default.
+
+	// The most severe target state is used for the combined service check
+	// result by default. The aggregate thresholds are unset by default; the
+	// default value used depends on the chosen strategy.
+	defaultAggregateStrategy           string = AggregateStrategyWorst
+	defaultAggregateThreshold          int    = 0
+	defaultAggregateWarningPercentage  int    = 10
+	defaultAggregateCriticalPercentage int    = 25
+	defaultAggregateWarningCount       int    = 1
+	defaultAggregateCriticalCount      int    = 5
+
+	// No policy file is used by default.
+	defaultPolicyFile string = ""
+
+	// Whether Extended Key Usage validation check results should be applied
+	// when determining overall validation state of a certificate chain by
+	// default. Requires that required or disallowed EKUs also be specified
+	// (or that the validation check is explicitly applied).
+	defaultApplyCertExtKeyUsageValidationResults bool = true
+
+	// The Extended Key Usage required of a leaf certificate if the sysadmin
+	// did not specify a list.
+	defaultRequiredEKU string = certs.ExtKeyUsageKeywordServerAuth
 )
 
 // Constants specific to the copier app.
@@ -246,6 +721,20 @@ const (
 	defaultCertTypesToKeep string = "all"
 	defaultInputFilename   string = "" // future: shared by all apps reading an input file
 	defaultOutputFilename  string = ""
+
+	// Missing intermediate certificates are not downloaded by default.
+	defaultCompleteChain bool = false
+
+	// The order of the input certificate chain is retained by default.
+	defaultSortChain bool = false
+
+	// Duplicate certificates are retained by default.
+	defaultDedupe bool = false
+
+	// The output file is written as PEM encoded certificates unless a
+	// private key file is specified.
+	defaultKeyFile     string = ""
+	defaultP12Password string = ""
 )
 
 // Constants specific to certsum.
@@ -266,6 +755,35 @@ const (
 	// they work from.
 	defaultScanRateLimit int = 100
 
+	// the scanner calculates the scan rate limit from the number of
+	// available CPUs and scan targets unless a limit is specified.
+	defaultScannerScanRateLimit int = 0
+
+	// When automatically calculating the scan rate limit, this number of
+	// concurrent scans is allowed per available CPU. Scans spend most of
+	// their time waiting on the network, so this value is intentionally
+	// high.
+	scanRateLimitPerCPU int = 64
+
+	// Bounds applied to the automatically calculated scan rate limit before
+	// limiting it to the number of scan targets. The upper bound is kept
+	// well below common open file limits.
+	scanRateLimitAutoMin int = 16
+	scanRateLimitAutoMax int = 512
+
+	// connection attempts to the same IP Address are not delayed
+	defaultHostDelay int = 0
+
+	// connection attempts are not randomly delayed
+	defaultScanJitter int = 0
+
+	// progress is only reported on request (e.g., via SIGUSR1)
+	defaultProgressInterval int = 0
+
+	// retrieve the certificate chain every 5 minutes when watching for
+	// changes
+	defaultWatchInterval time.Duration = 5 * time.Minute
+
 	// For the "scanner", this flag value is required.
 	// defaultCIDRRange string = ""
 	// FIXME
@@ -275,6 +793,9 @@ const (
 	// the sole entry in the list of ports to be checked by the scanner
 	defaultPortsListEntry int = 443
 
+	// no hosts file is used by default
+	defaultHostsFile string = ""
+
 	// list port open/close scan results (false == exclude)
 	defaultShowPortScanResults bool = false
 
@@ -289,6 +810,38 @@ const (
 
 	// show overview instead of detailed view (false == show detailed view)
 	defaultShowOverview bool = false
+
+	// emit human-readable summary tables
+	defaultOutputFormat string = OutputFormatText
+
+	// do not write scan results to a file
+	defaultOutputFile string = ""
+
+	// do not export or import scan results bundles
+	defaultExportBundle string = ""
+	defaultImportBundle string = ""
+
+	// do not compare scan results against a previous scan
+	defaultCompareTo string = ""
+
+	// do not send scan results to a webhook; the Authorization header is
+	// used for authentication if a header value is provided
+	defaultWebhookURL            string = ""
+	defaultWebhookAuthHeader     string = ""
+	defaultWebhookAuthHeaderName string = "Authorization"
+
+	// serve exporter metrics on all interfaces
+	defaultListenAddress string = ":9810"
+
+	// serve the monitor HTTP API on all interfaces
+	defaultMonitorListenAddress string = ":9811"
+
+	// check monitored targets every hour
+	defaultCheckInterval int = 60
+
+	// profiling is disabled by default
+	defaultProfileCPU string = ""
+	defaultProfileMem string = ""
 )
 
 const (
@@ -296,6 +849,8 @@ const (
 	appTypeInspector string = "inspector"
 	appTypeCopier    string = "copier"
 	appTypeScanner   string = "scanner"
+	appTypeExporter  string = "exporter"
+	appTypeMonitor   string = "monitor"
 )
 
 // limit number of IP Addresses "printed" by the Stringer interface to a
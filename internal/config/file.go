@@ -0,0 +1,261 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ErrInvalidConfigFile indicates that a configuration file could not be
+// parsed.
+var ErrInvalidConfigFile = errors.New("invalid configuration file")
+
+// configFileSections maps configuration file section names to the
+// application type that the settings within the section apply to.
+var configFileSections = map[string]func(AppType) bool{
+	"check_cert":    func(a AppType) bool { return a.Plugin },
+	"lscert":        func(a AppType) bool { return a.Inspector },
+	"cpcert":        func(a AppType) bool { return a.Copier },
+	"certsum":       func(a AppType) bool { return a.Scanner },
+	"cert_exporter": func(a AppType) bool { return a.Exporter },
+	"cert_monitor":  func(a AppType) bool { return a.Monitor },
+}
+
+// configFileSetting is a single flag value recorded in a configuration file.
+type configFileSetting struct {
+	// section is the name of the section the setting was found in. This is
+	// empty for settings applying to all applications.
+	section string
+
+	// name is the flag name.
+	name string
+
+	// values is the collection of values for the flag. Multiple values are
+	// provided for array settings.
+	values []string
+}
+
+// parseConfigFile parses flag settings from the given reader.
+//
+// The file is parsed as TOML. Each setting is specified as flag-name = value
+// where the value is a string, integer, float, boolean or an array of these
+// values. Settings listed before any table apply to all applications;
+// settings within a table named after an application (e.g., [check_cert])
+// apply to that application only. Other TOML types (e.g., date-times, inline
+// tables, nested tables or arrays of tables) are rejected. Settings are
+// returned in the order they are listed in the file.
+func parseConfigFile(r io.Reader) ([]configFileSetting, error) {
+	var content map[string]interface{}
+
+	md, err := toml.NewDecoder(r).Decode(&content)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"%v: %w",
+			err,
+			ErrInvalidConfigFile,
+		)
+	}
+
+	var settings []configFileSetting
+
+	for _, key := range md.Keys() {
+		var section string
+		var name string
+		var value interface{}
+
+		switch len(key) {
+		case 1:
+			name = key[0]
+			value = content[name]
+
+			if _, ok := value.(map[string]interface{}); ok {
+				if _, known := configFileSections[name]; !known {
+					return nil, fmt.Errorf(
+						"unknown section %q: %w",
+						name,
+						ErrInvalidConfigFile,
+					)
+				}
+
+				continue
+			}
+
+		case 2:
+			section, name = key[0], key[1]
+
+			table, ok := content[section].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf(
+					"unsupported value for %q: %w",
+					key.String(),
+					ErrInvalidConfigFile,
+				)
+			}
+			value = table[name]
+
+		default:
+			return nil, fmt.Errorf(
+				"unsupported nested setting %q: %w",
+				key.String(),
+				ErrInvalidConfigFile,
+			)
+		}
+
+		values, err := configFileValues(value)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid value for %q: %w",
+				key.String(),
+				err,
+			)
+		}
+
+		settings = append(settings, configFileSetting{
+			section: section,
+			name:    name,
+			values:  values,
+		})
+	}
+
+	return settings, nil
+}
+
+// configFileValues converts a decoded TOML value or array of values to the
+// string form accepted by the flag package.
+func configFileValues(value interface{}) ([]string, error) {
+	array, ok := value.([]interface{})
+	if !ok {
+		v, err := configFileScalar(value)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{v}, nil
+	}
+
+	values := make([]string, 0, len(array))
+	for _, item := range array {
+		v, err := configFileScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// configFileScalar converts a single decoded TOML value to the string form
+// accepted by the flag package.
+func configFileScalar(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported value type %T: %w",
+			value,
+			ErrInvalidConfigFile,
+		)
+	}
+}
+
+// handleConfigFile applies flag values from the user-specified configuration
+// file. Flag values specified on the command-line take precedence over
+// values from the configuration file.
+func (c *Config) handleConfigFile(appType AppType) error {
+	if c.ConfigFile == "" {
+		return nil
+	}
+
+	f, err := os.Open(filepath.Clean(c.ConfigFile))
+	if err != nil {
+		return fmt.Errorf(
+			"failed to open configuration file %q: %w",
+			c.ConfigFile,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	settings, err := parseConfigFile(f)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to parse configuration file %q: %w",
+			c.ConfigFile,
+			err,
+		)
+	}
+
+	// Short and long flag names share the same underlying value, so the
+	// values of flags explicitly set on the command-line are tracked instead
+	// of the flag names.
+	setOnCommandLine := make(map[flag.Value]struct{})
+	flag.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Value] = struct{}{}
+	})
+
+	for _, setting := range settings {
+		if setting.section != "" && !configFileSections[setting.section](appType) {
+			continue
+		}
+
+		f := flag.Lookup(setting.name)
+		switch {
+		// Settings shared by all applications may not apply to this one.
+		case f == nil && setting.section == "":
+			continue
+
+		case f == nil, setting.name == ConfigFileFlag:
+			return fmt.Errorf(
+				"unsupported setting %q in configuration file %q: %w",
+				setting.name,
+				c.ConfigFile,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if _, ok := setOnCommandLine[f.Value]; ok {
+			continue
+		}
+
+		// Flags are set via the flag package so that values from the
+		// configuration file are treated as explicitly set when applying
+		// check profile presets.
+		for _, value := range setting.values {
+			if err := flag.Set(f.Name, value); err != nil {
+				return fmt.Errorf(
+					"invalid value %q for setting %q in configuration file %q: %w",
+					value,
+					setting.name,
+					c.ConfigFile,
+					err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
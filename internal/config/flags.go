@@ -11,6 +11,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/go-nagios"
 )
 
 // supportedValuesFlagHelpText is a flag package helper function that combines
@@ -98,6 +102,9 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 			ignoreExpiringRootCertificatesFlagHelp,
 		)
 
+		flag.Var(&c.ignoredSerials, IgnoreSerialFlag, ignoreSerialFlagHelp)
+		flag.Var(&c.ignoredFingerprints, IgnoreFingerprintFlag, ignoreFingerprintFlagHelp)
+
 		flag.BoolVar(&c.OmitSANsEntries, OmitSANsEntriesFlagLong, defaultOmitSANsEntriesList, omitSANsEntriesFlagHelp)
 		flag.BoolVar(&c.OmitSANsEntries, OmitSANsListFlagLong, defaultOmitSANsEntriesList, omitSANsListFlagHelp)
 
@@ -106,6 +113,20 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 
 		flag.BoolVar(&c.ListIgnoredValidationCheckResultErrors, ListIgnoredErrorsFlag, defaultListIgnoredValidationCheckResultErrors, listIgnoredErrorsFlagHelp)
 
+		flag.BoolVar(&c.ShowCheckTimings, ShowCheckTimingsFlag, defaultShowCheckTimings, showCheckTimingsFlagHelp)
+		flag.BoolVar(&c.ShowRemediation, ShowRemediationFlag, defaultShowRemediation, showRemediationFlagHelp)
+		flag.BoolVar(&c.BriefWhenOK, BriefWhenOKFlag, defaultBriefWhenOK, briefWhenOKFlagHelp)
+		flag.BoolVar(&c.DaysRemainingOnly, DaysRemainingOnlyFlag, defaultDaysRemainingOnly, daysRemainingOnlyFlagHelp)
+
+		flag.StringVar(
+			&c.Profile,
+			ProfileFlag,
+			defaultProfile,
+			supportedValuesFlagHelpText(profileFlagHelp, supportedProfiles()),
+		)
+
+		flag.IntVar(&c.pluginTimeout, PluginTimeoutFlag, defaultPluginTimeout, pluginTimeoutFlagHelp)
+
 		flag.StringVar(&c.InputFilename, FilenameFlagLong, defaultFilename, inputFilenameFlagHelp)
 
 		flag.StringVar(&c.Server, ServerFlagShort, defaultServer, serverFlagHelp+shorthandFlagSuffix)
@@ -117,6 +138,99 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 		flag.IntVar(&c.Port, PortFlagShort, defaultPort, portFlagHelp+shorthandFlagSuffix)
 		flag.IntVar(&c.Port, PortFlagLong, defaultPort, portFlagHelp)
 
+		flag.Var(&c.portsList, PortsFlagLong, serverPortsListFlagHelp)
+
+		flag.StringVar(&c.ExpectedSerial, ExpectedSerialFlag, defaultExpectedSerial, expectedSerialFlagHelp)
+
+		flag.BoolVar(&c.DependentOnConnectFailure, DependentOnConnectFailureFlag, defaultDependentOnConnectFailure, dependentOnConnectFailureFlagHelp)
+
+		flag.StringVar(&c.ExecHook, ExecHookFlag, defaultExecHook, execHookFlagHelp)
+
+		flag.StringVar(&c.BlocklistFile, BlocklistFileFlag, defaultBlocklistFile, blocklistFileFlagHelp)
+
+		flag.StringVar(&c.DistrustedCAsFile, DistrustedCAsFileFlag, defaultDistrustedCAsFile, distrustedCAsFileFlagHelp)
+
+		flag.StringVar(&c.StateFile, StateFileFlag, defaultStateFile, stateFileFlagHelp)
+
+		flag.StringVar(
+			&c.ResolverDNSSECMode,
+			ResolverDNSSECFlag,
+			defaultResolverDNSSECMode,
+			supportedValuesFlagHelpText(resolverDNSSECFlagHelp, supportedResolverDNSSECModes()),
+		)
+
+		flag.StringVar(
+			&c.SimulateState,
+			SimulateStateFlag,
+			defaultSimulateState,
+			supportedValuesFlagHelpText(simulateStateFlagHelp, supportedSimulateStates()),
+		)
+
+		flag.StringVar(&c.SimulateMessage, SimulateMessageFlag, defaultSimulateMessage, simulateMessageFlagHelp)
+
+		flag.StringVar(&c.StatsFile, StatsFileFlag, defaultStatsFile, statsFileFlagHelp)
+
+		flag.StringVar(&c.TargetsFile, TargetsFileFlag, defaultTargetsFile, targetsFileFlagHelp)
+
+		flag.StringVar(&c.Socket, SocketFlag, defaultSocket, socketFlagHelp)
+
+		flag.StringVar(&c.TrustStore, TrustStoreFlag, defaultTrustStore, trustStoreFlagHelp)
+
+		flag.StringVar(&c.CompareWith, CompareWithFlag, defaultCompareWith, compareWithFlagHelp)
+
+		flag.StringVar(
+			&c.AggregateStrategy,
+			AggregateStrategyFlag,
+			defaultAggregateStrategy,
+			supportedValuesFlagHelpText(aggregateStrategyFlagHelp, supportedAggregateStrategies()),
+		)
+		flag.IntVar(&c.aggregateWarning, AggregateWarningFlag, defaultAggregateThreshold, aggregateWarningFlagHelp)
+		flag.IntVar(&c.aggregateCritical, AggregateCriticalFlag, defaultAggregateThreshold, aggregateCriticalFlagHelp)
+
+		flag.StringVar(&c.PolicyFile, PolicyFileFlag, defaultPolicyFile, policyFileFlagHelp)
+
+		flag.StringVar(&c.CTSearchURL, CTSearchURLFlag, defaultCTSearchURL, ctSearchURLFlagHelp)
+
+		flag.StringVar(&c.CTSearchToken, CTSearchTokenFlag, defaultCTSearchToken, ctSearchTokenFlagHelp)
+
+		flag.StringVar(&c.CTSearchTokenCmd, CTSearchTokenCmdFlag, defaultCTSearchTokenCmd, ctSearchTokenCmdFlagHelp)
+
+		flag.StringVar(&c.ACMEDirectoryURL, ACMEDirectoryURLFlag, defaultACMEDirectoryURL, acmeDirectoryURLFlagHelp)
+
+		flag.StringVar(
+			&c.minTLSVersion,
+			MinTLSVersionFlag,
+			defaultMinTLSVersion,
+			supportedValuesFlagHelpText(minTLSVersionFlagHelp, netutils.SupportedTLSVersionKeywords()),
+		)
+
+		flag.IntVar(&c.MaxExternalRequests, MaxExternalRequestsFlag, defaultMaxExternalRequests, maxExternalRequestsFlagHelp)
+		flag.Int64Var(&c.MaxDownloadBytes, MaxDownloadBytesFlag, defaultMaxDownloadBytes, maxDownloadBytesFlagHelp)
+
+		flag.Var(
+			&c.stateMappings,
+			MapStateFlag,
+			supportedValuesFlagHelpText(mapStateFlagHelp, nagios.SupportedStateLabels()),
+		)
+
+		flag.Var(
+			&c.maxSeverity,
+			MaxSeverityFlag,
+			supportedValuesFlagHelpText(maxSeverityFlagHelp, supportedValidationCheckResultKeywords()),
+		)
+
+		flag.Var(
+			&c.requiredEKUs,
+			RequiredEKUsFlag,
+			supportedValuesFlagHelpText(requiredEKUsFlagHelp, certs.SupportedExtKeyUsageKeywords()),
+		)
+
+		flag.Var(
+			&c.disallowedEKUs,
+			DisallowedEKUsFlag,
+			supportedValuesFlagHelpText(disallowedEKUsFlagHelp, certs.SupportedExtKeyUsageKeywords()),
+		)
+
 		flag.Var(
 			&c.ignoreValidationResults,
 			IgnoreValidationResultFlag,
@@ -135,6 +249,17 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 		flag.IntVar(&c.AgeCritical, AgeCriticalFlagShort, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp+shorthandFlagSuffix)
 		flag.IntVar(&c.AgeCritical, AgeCriticalFlagLong, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp)
 
+		flag.IntVar(&c.AgeWarningPercent, AgeWarningPercentFlag, defaultCertExpireAgeWarningPercent, certExpireAgeWarningPercentFlagHelp)
+		flag.IntVar(&c.AgeCriticalPercent, AgeCriticalPercentFlag, defaultCertExpireAgeCriticalPercent, certExpireAgeCriticalPercentFlagHelp)
+
+		flag.IntVar(&c.AgeWarningIntermediate, AgeWarningIntermediateFlag, defaultCertExpireAgeWarningIntermediate, certExpireAgeWarningIntermediateFlagHelp)
+		flag.IntVar(&c.AgeCriticalIntermediate, AgeCriticalIntermediateFlag, defaultCertExpireAgeCriticalIntermediate, certExpireAgeCriticalIntermediateFlagHelp)
+		flag.IntVar(&c.AgeWarningRoot, AgeWarningRootFlag, defaultCertExpireAgeWarningRoot, certExpireAgeWarningRootFlagHelp)
+		flag.IntVar(&c.AgeCriticalRoot, AgeCriticalRootFlag, defaultCertExpireAgeCriticalRoot, certExpireAgeCriticalRootFlagHelp)
+
+		flag.IntVar(&c.expiryCliffWindow, ExpiryCliffWindowFlag, defaultExpiryCliffWindow, expiryCliffWindowFlagHelp)
+		flag.IntVar(&c.expiryCliffLeadTime, ExpiryCliffLeadTimeFlag, defaultExpiryCliffLeadTime, expiryCliffLeadTimeFlagHelp)
+
 	case appType.Inspector:
 
 		// Override the default Help output with a brief lead-in summary of
@@ -171,6 +296,26 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 		flag.StringVar(&c.InputFilename, FilenameFlagLong, defaultInputFilename, inputFilenameFlagHelp)
 		flag.BoolVar(&c.EmitCertText, EmitCertTextFlagLong, defaultEmitCertText, emitCertTextFlagHelp)
 
+		flag.StringVar(
+			&c.OutputFormat,
+			OutputFormatFlag,
+			defaultOutputFormat,
+			supportedValuesFlagHelpText(inspectorOutputFormatFlagHelp, supportedInspectorOutputFormats()),
+		)
+
+		flag.StringVar(&c.TemplateFile, TemplateFileFlag, defaultTemplateFile, templateFileFlagHelp)
+
+		flag.StringVar(&c.DiffSince, DiffSinceFlag, defaultDiffSince, diffSinceFlagHelp)
+
+		flag.BoolVar(&c.Watch, WatchFlag, defaultWatch, watchFlagHelp)
+		flag.DurationVar(&c.watchInterval, WatchIntervalFlag, defaultWatchInterval, watchIntervalFlagHelp)
+		flag.StringVar(&c.ExecHook, ExecHookFlag, defaultExecHook, watchExecHookFlagHelp)
+
+		flag.StringVar(&c.BackfillDir, BackfillDirFlag, defaultBackfillDir, backfillDirFlagHelp)
+		flag.BoolVar(&c.PrintSchema, PrintSchemaFlag, defaultPrintSchemaAndExit, printBackfillSchemaFlagHelp)
+
+		flag.StringVar(&c.StatsSummary, StatsSummaryFlag, defaultStatsSummary, statsSummaryFlagHelp)
+
 		flag.StringVar(&c.Server, ServerFlagShort, defaultServer, serverFlagHelp+shorthandFlagSuffix)
 		flag.StringVar(&c.Server, ServerFlagLong, defaultServer, serverFlagHelp)
 
@@ -217,8 +362,8 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 		flag.BoolVar(&c.VerboseOutput, VerboseFlagShort, defaultVerboseOutput, verboseOutputFlagHelp+shorthandFlagSuffix)
 		flag.BoolVar(&c.VerboseOutput, VerboseFlagLong, defaultVerboseOutput, verboseOutputFlagHelp)
 
-		flag.StringVar(&c.InputFilename, InputFilenameFlagShort, defaultInputFilename, inputFilenameFlagHelp+shorthandFlagSuffix)
-		flag.StringVar(&c.InputFilename, InputFilenameFlagLong, defaultInputFilename, inputFilenameFlagHelp)
+		flag.StringVar(&c.InputFilename, InputFilenameFlagShort, defaultInputFilename, copierInputFlagHelp+shorthandFlagSuffix)
+		flag.StringVar(&c.InputFilename, InputFilenameFlagLong, defaultInputFilename, copierInputFlagHelp)
 
 		flag.StringVar(&c.OutputFilename, OutputFilenameFlagShort, defaultOutputFilename, outputFilenameFlagHelp+shorthandFlagSuffix)
 		flag.StringVar(&c.OutputFilename, OutputFilenameFlagLong, defaultOutputFilename, outputFilenameFlagHelp)
@@ -229,6 +374,13 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 			supportedValuesFlagHelpText(certTypesToKeepFlagHelp, supportedCertTypeFilterKeywords()),
 		)
 
+		flag.BoolVar(&c.CompleteChain, CompleteChainFlag, defaultCompleteChain, completeChainFlagHelp)
+		flag.BoolVar(&c.SortChain, SortChainFlag, defaultSortChain, sortChainFlagHelp)
+		flag.BoolVar(&c.Dedupe, DedupeFlag, defaultDedupe, dedupeFlagHelp)
+
+		flag.StringVar(&c.KeyFile, KeyFileFlag, defaultKeyFile, keyFileFlagHelp)
+		flag.StringVar(&c.P12Password, P12PasswordFlag, defaultP12Password, p12PasswordFlagHelp)
+
 		flag.StringVar(&c.Server, ServerFlagShort, defaultServer, serverFlagHelp+shorthandFlagSuffix)
 		flag.StringVar(&c.Server, ServerFlagLong, defaultServer, serverFlagHelp)
 
@@ -257,8 +409,16 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 		flag.Var(&c.hosts, HostsFlagLong, hostsFlagHelp)
 		flag.Var(&c.hosts, HostsFlagAlt, hostsFlagHelp+" (alt name)")
 
-		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagLong, defaultScanRateLimit, scanRateLimitFlagHelp)
-		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagShort, defaultScanRateLimit, scanRateLimitFlagHelp+shorthandFlagSuffix)
+		flag.StringVar(&c.HostsFile, HostsFileFlag, defaultHostsFile, hostsFileFlagHelp)
+
+		flag.Var(&c.excludeHosts, ExcludeHostsFlag, excludeHostsFlagHelp)
+
+		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagLong, defaultScannerScanRateLimit, scannerScanRateLimitFlagHelp)
+		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagShort, defaultScannerScanRateLimit, scannerScanRateLimitFlagHelp+shorthandFlagSuffix)
+
+		flag.IntVar(&c.hostDelay, HostDelayFlag, defaultHostDelay, hostDelayFlagHelp)
+		flag.IntVar(&c.scanJitter, ScanJitterFlag, defaultScanJitter, scanJitterFlagHelp)
+		flag.IntVar(&c.progressInterval, ProgressIntervalFlag, defaultProgressInterval, progressIntervalFlagHelp)
 
 		flag.IntVar(&c.timeoutAppInactivity, AppTimeoutFlagLong, defaultAppTimeout, timeoutAppInactivityFlagHelp)
 		flag.IntVar(&c.timeoutAppInactivity, AppTimeoutFlagShort, defaultAppTimeout, timeoutAppInactivityFlagHelp+shorthandFlagSuffix)
@@ -281,12 +441,131 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 		flag.BoolVar(&c.ShowOverview, ShowOverviewFlagLong, defaultShowOverview, showOverviewFlagHelp)
 		flag.BoolVar(&c.ShowOverview, ShowOverviewFlagShort, defaultShowOverview, showOverviewFlagHelp+shorthandFlagSuffix)
 
+		flag.StringVar(
+			&c.OutputFormat,
+			OutputFormatFlag,
+			defaultOutputFormat,
+			supportedValuesFlagHelpText(outputFormatFlagHelp, supportedOutputFormats()),
+		)
+
+		flag.StringVar(&c.OutputFile, OutputFileFlag, defaultOutputFile, outputFileFlagHelp)
+
+		flag.StringVar(&c.ExportBundle, ExportBundleFlag, defaultExportBundle, exportBundleFlagHelp)
+
+		flag.StringVar(&c.StateFile, StateFileFlag, defaultStateFile, scannerStateFileFlagHelp)
+
+		flag.StringVar(&c.StatsFile, StatsFileFlag, defaultStatsFile, statsFileFlagHelp)
+		flag.StringVar(&c.ImportBundle, ImportBundleFlag, defaultImportBundle, importBundleFlagHelp)
+		flag.StringVar(&c.CompareTo, CompareToFlag, defaultCompareTo, compareToFlagHelp)
+
+		flag.StringVar(&c.WebhookURL, WebhookURLFlag, defaultWebhookURL, webhookURLFlagHelp)
+		flag.StringVar(&c.WebhookAuthHeader, WebhookAuthHeaderFlag, defaultWebhookAuthHeader, webhookAuthHeaderFlagHelp)
+		flag.StringVar(&c.WebhookAuthHeaderName, WebhookAuthHeaderNameFlag, defaultWebhookAuthHeaderName, webhookAuthHeaderNameFlagHelp)
+
+		flag.BoolVar(&c.PrintSchema, PrintSchemaFlag, defaultPrintSchemaAndExit, printSchemaFlagHelp)
+
+		flag.StringVar(&c.ProfileCPU, ProfileCPUFlag, defaultProfileCPU, profileCPUFlagHelp)
+		flag.StringVar(&c.ProfileMem, ProfileMemFlag, defaultProfileMem, profileMemFlagHelp)
+
+		flag.IntVar(&c.AgeWarning, AgeWarningFlagShort, defaultCertExpireAgeWarning, certExpireAgeWarningFlagHelp+shorthandFlagSuffix)
+		flag.IntVar(&c.AgeWarning, AgeWarningFlagLong, defaultCertExpireAgeWarning, certExpireAgeWarningFlagHelp)
+
+		flag.IntVar(&c.AgeCritical, AgeCriticalFlagShort, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp+shorthandFlagSuffix)
+		flag.IntVar(&c.AgeCritical, AgeCriticalFlagLong, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp)
+
+		flag.IntVar(&c.expiryCliffWindow, ExpiryCliffWindowFlag, defaultExpiryCliffWindow, expiryCliffWindowFlagHelp)
+		flag.IntVar(&c.expiryCliffLeadTime, ExpiryCliffLeadTimeFlag, defaultExpiryCliffLeadTime, expiryCliffLeadTimeFlagHelp)
+
+		flag.Var(&c.ignoredFingerprints, IgnoreFingerprintFlag, ignoreFingerprintFlagHelp)
+
+	case appType.Exporter:
+
+		// Override the default Help output with a brief lead-in summary of
+		// the expected syntax and project version.
+		//
+		// https://stackoverflow.com/a/36787811/903870
+		// https://pubs.opengroup.org/onlinepubs/9699919799/basedefs/V1_chap12.html
+		usageTextHeaderTmpl = "%s\n\nUsage:  %s <flags>\n\n%s\n\nFlags:\n"
+
+		appDescription = "Prometheus exporter used to expose certificate chain metrics for a collection of hosts."
+
+		flag.Var(&c.hosts, HostsFlagLong, hostsFlagHelp)
+		flag.Var(&c.hosts, HostsFlagAlt, hostsFlagHelp+" (alt name)")
+
+		flag.Var(&c.portsList, PortsFlagLong, portsListFlagHelp)
+		flag.Var(&c.portsList, PortsFlagShort, portsListFlagHelp+shorthandFlagSuffix)
+
+		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagLong, defaultScanRateLimit, scanRateLimitFlagHelp)
+		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagShort, defaultScanRateLimit, scanRateLimitFlagHelp+shorthandFlagSuffix)
+
+		flag.StringVar(&c.ListenAddress, ListenAddressFlag, defaultListenAddress, listenAddressFlagHelp)
+
+		flag.StringVar(&c.ProfileCPU, ProfileCPUFlag, defaultProfileCPU, profileCPUFlagHelp)
+		flag.StringVar(&c.ProfileMem, ProfileMemFlag, defaultProfileMem, profileMemFlagHelp)
+
 		flag.IntVar(&c.AgeWarning, AgeWarningFlagShort, defaultCertExpireAgeWarning, certExpireAgeWarningFlagHelp+shorthandFlagSuffix)
 		flag.IntVar(&c.AgeWarning, AgeWarningFlagLong, defaultCertExpireAgeWarning, certExpireAgeWarningFlagHelp)
 
 		flag.IntVar(&c.AgeCritical, AgeCriticalFlagShort, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp+shorthandFlagSuffix)
 		flag.IntVar(&c.AgeCritical, AgeCriticalFlagLong, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp)
 
+		flag.Var(
+			&c.ignoreValidationResults,
+			IgnoreValidationResultFlag,
+			supportedValuesFlagHelpText(ignoreValidationResultsFlagHelp, supportedValidationCheckResultKeywords()),
+		)
+
+		flag.Var(
+			&c.applyValidationResults,
+			ApplyValidationResultFlag,
+			supportedValuesFlagHelpText(applyValidationResultsFlagHelp, supportedValidationCheckResultKeywords()),
+		)
+
+	case appType.Monitor:
+
+		// Override the default Help output with a brief lead-in summary of
+		// the expected syntax and project version.
+		//
+		// https://stackoverflow.com/a/36787811/903870
+		// https://pubs.opengroup.org/onlinepubs/9699919799/basedefs/V1_chap12.html
+		usageTextHeaderTmpl = "%s\n\nUsage:  %s <flags>\n\n%s\n\nFlags:\n"
+
+		appDescription = "Service which periodically evaluates certificate chains for a collection of hosts and exposes the results via an HTTP API."
+
+		flag.Var(&c.hosts, HostsFlagLong, hostsFlagHelp)
+		flag.Var(&c.hosts, HostsFlagAlt, hostsFlagHelp+" (alt name)")
+
+		flag.Var(&c.portsList, PortsFlagLong, portsListFlagHelp)
+		flag.Var(&c.portsList, PortsFlagShort, portsListFlagHelp+shorthandFlagSuffix)
+
+		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagLong, defaultScanRateLimit, scanRateLimitFlagHelp)
+		flag.IntVar(&c.ScanRateLimit, ScanRateLimitFlagShort, defaultScanRateLimit, scanRateLimitFlagHelp+shorthandFlagSuffix)
+
+		flag.StringVar(&c.ListenAddress, ListenAddressFlag, defaultMonitorListenAddress, monitorListenAddressFlagHelp)
+
+		flag.IntVar(&c.checkInterval, CheckIntervalFlag, defaultCheckInterval, checkIntervalFlagHelp)
+
+		flag.StringVar(&c.ProfileCPU, ProfileCPUFlag, defaultProfileCPU, profileCPUFlagHelp)
+		flag.StringVar(&c.ProfileMem, ProfileMemFlag, defaultProfileMem, profileMemFlagHelp)
+
+		flag.IntVar(&c.AgeWarning, AgeWarningFlagShort, defaultCertExpireAgeWarning, certExpireAgeWarningFlagHelp+shorthandFlagSuffix)
+		flag.IntVar(&c.AgeWarning, AgeWarningFlagLong, defaultCertExpireAgeWarning, certExpireAgeWarningFlagHelp)
+
+		flag.IntVar(&c.AgeCritical, AgeCriticalFlagShort, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp+shorthandFlagSuffix)
+		flag.IntVar(&c.AgeCritical, AgeCriticalFlagLong, defaultCertExpireAgeCritical, certExpireAgeCriticalFlagHelp)
+
+		flag.Var(
+			&c.ignoreValidationResults,
+			IgnoreValidationResultFlag,
+			supportedValuesFlagHelpText(ignoreValidationResultsFlagHelp, supportedValidationCheckResultKeywords()),
+		)
+
+		flag.Var(
+			&c.applyValidationResults,
+			ApplyValidationResultFlag,
+			supportedValuesFlagHelpText(applyValidationResultsFlagHelp, supportedValidationCheckResultKeywords()),
+		)
+
 	}
 
 	// Shared flags for all application type
@@ -297,6 +576,16 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 	flag.IntVar(&c.timeout, TimeoutFlagShort, defaultConnectTimeout, timeoutConnectFlagHelp+shorthandFlagSuffix)
 	flag.IntVar(&c.timeout, TimeoutFlagLong, defaultConnectTimeout, timeoutConnectFlagHelp)
 
+	flag.IntVar(&c.dnsTimeout, DNSTimeoutFlag, defaultDNSTimeout, dnsTimeoutFlagHelp)
+	flag.StringVar(&c.DNSServer, DNSServerFlag, defaultDNSServer, dnsServerFlagHelp)
+	flag.IntVar(&c.dialTimeout, ConnectTimeoutFlag, defaultDialTimeout, connectTimeoutFlagHelp)
+	flag.IntVar(&c.handshakeTimeout, HandshakeTimeoutFlag, defaultHandshakeTimeout, handshakeTimeoutFlagHelp)
+
+	flag.IntVar(&c.retries, RetriesFlag, defaultRetries, retriesFlagHelp)
+	flag.IntVar(&c.retryDelay, RetryDelayFlag, defaultRetryDelay, retryDelayFlagHelp)
+
+	flag.BoolVar(&c.FIPSMode, FIPSModeFlag, defaultFIPSMode, fipsModeFlagHelp)
+
 	flag.StringVar(
 		&c.LoggingLevel,
 		LogLevelFlagShort,
@@ -312,6 +601,8 @@ func (c *Config) handleFlagsConfig(appType AppType) {
 
 	flag.BoolVar(&c.ShowVersion, VersionFlagLong, defaultDisplayVersionAndExit, versionFlagHelp)
 
+	flag.StringVar(&c.ConfigFile, ConfigFileFlag, defaultConfigFile, configFileFlagHelp)
+
 	// Prepend a brief lead-in summary of the expected syntax and project
 	// version before emitting the default Help output.
 	//
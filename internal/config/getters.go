@@ -8,11 +8,18 @@
 package config
 
 import (
+	"fmt"
+	"net"
+	"net/netip"
+	"runtime"
 	"strings"
 	"time"
 
+	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/secrets"
 	"github.com/atc0005/check-cert/internal/textutils"
+	"github.com/atc0005/go-nagios"
 )
 
 // Timeout converts the user-specified connection timeout value in
@@ -22,6 +29,83 @@ func (c Config) Timeout() time.Duration {
 	return time.Duration(c.timeout) * time.Second
 }
 
+// DNSTimeout converts the user-specified DNS resolution timeout value in
+// seconds to an appropriate time duration value. The general connection
+// timeout is returned if not specified.
+func (c Config) DNSTimeout() time.Duration {
+	if c.dnsTimeout == 0 {
+		return c.Timeout()
+	}
+
+	return time.Duration(c.dnsTimeout) * time.Second
+}
+
+// Resolver returns the resolver used for resolving hostnames or FQDNs to IP
+// Addresses. The system resolver is returned if a DNS server was not
+// specified.
+func (c Config) Resolver() *net.Resolver {
+	resolver, err := netutils.NewResolver(c.DNSServer, c.DNSTimeout())
+	if err != nil {
+		return net.DefaultResolver
+	}
+
+	return resolver
+}
+
+// AnswerValidator returns the validator used to determine whether the DNS
+// server reports the DNS answer for a hostname or FQDN as authenticated using
+// DNSSEC. The DNS server specified by the sysadmin is queried, falling back
+// to the first DNS server used by the system resolver. DNSSEC signatures are
+// not validated locally; the DNS server is trusted to do so.
+func (c Config) AnswerValidator() (netutils.AnswerValidator, error) {
+	return netutils.NewADBitValidator(c.DNSServer, c.DNSTimeout())
+}
+
+// DialTimeout converts the user-specified TCP connection timeout value in
+// seconds to an appropriate time duration value. The general connection
+// timeout is returned if not specified.
+func (c Config) DialTimeout() time.Duration {
+	if c.dialTimeout == 0 {
+		return c.Timeout()
+	}
+
+	return time.Duration(c.dialTimeout) * time.Second
+}
+
+// HandshakeTimeout converts the user-specified TLS handshake timeout value in
+// seconds to an appropriate time duration value. The general connection
+// timeout is returned if not specified.
+func (c Config) HandshakeTimeout() time.Duration {
+	if c.handshakeTimeout == 0 {
+		return c.Timeout()
+	}
+
+	return time.Duration(c.handshakeTimeout) * time.Second
+}
+
+// Retries returns the user-specified number of additional attempts made to
+// retrieve a certificate chain after a transient connection failure.
+func (c Config) Retries() int {
+	return c.retries
+}
+
+// RetryDelay converts the user-specified retry delay value in milliseconds to
+// an appropriate time duration value.
+func (c Config) RetryDelay() time.Duration {
+	return time.Duration(c.retryDelay) * time.Millisecond
+}
+
+// CertRetrievalOptions returns the user-specified settings used to customize
+// retrieval of certificate chains from remote certificate-enabled services.
+func (c Config) CertRetrievalOptions() netutils.CertRetrievalOptions {
+	return netutils.CertRetrievalOptions{
+		DialTimeout:      c.DialTimeout(),
+		HandshakeTimeout: c.HandshakeTimeout(),
+		Retries:          c.Retries(),
+		RetryDelay:       c.RetryDelay(),
+	}
+}
+
 // TimeoutPortScan converts the user-specified port scan timeout value in
 // milliseconds to an appropriate time duration value for use with setting
 // net.Dial timeout.
@@ -29,6 +113,36 @@ func (c Config) TimeoutPortScan() time.Duration {
 	return time.Duration(c.timeoutPortScan) * time.Millisecond
 }
 
+// HostDelay converts the user-specified minimum delay between connection
+// attempts to the same IP Address in milliseconds to a time duration value.
+func (c Config) HostDelay() time.Duration {
+	return time.Duration(c.hostDelay) * time.Millisecond
+}
+
+// ScanJitter converts the user-specified maximum random delay before each
+// connection attempt in milliseconds to a time duration value.
+func (c Config) ScanJitter() time.Duration {
+	return time.Duration(c.scanJitter) * time.Millisecond
+}
+
+// ProgressInterval converts the user-specified number of seconds between
+// scan progress reports to a time duration value.
+func (c Config) ProgressInterval() time.Duration {
+	return time.Duration(c.progressInterval) * time.Second
+}
+
+// CheckInterval converts the user-specified number of minutes between checks
+// of all targets to a time duration value.
+func (c Config) CheckInterval() time.Duration {
+	return time.Duration(c.checkInterval) * time.Minute
+}
+
+// WatchInterval returns the user-specified time between certificate chain
+// retrieval attempts when watching for changes.
+func (c Config) WatchInterval() time.Duration {
+	return c.watchInterval
+}
+
 // TimeoutAppInactivity converts the user-specified application inactivity
 // timeout value in seconds to an appropriate time duration value for use with
 // setting automatic context cancellation.
@@ -36,6 +150,70 @@ func (c Config) TimeoutAppInactivity() time.Duration {
 	return time.Duration(c.timeoutAppInactivity) * time.Second
 }
 
+// CompareWithTarget returns the user-specified second certificate-enabled
+// service to evaluate and compare against the service specified by the
+// server flag. The port flag value is used if a port was not specified and
+// the DNS Name (or server) value is used for SNI support and hostname
+// verification.
+func (c Config) CompareWithTarget() (netutils.Target, error) {
+	target, err := netutils.ParseTargetServer(c.CompareWith)
+	if err != nil {
+		return netutils.Target{}, err
+	}
+
+	if target.Port == 0 {
+		target.Port = c.Port
+	}
+
+	target.DNSName = c.DNSName
+	if target.DNSName == "" {
+		target.DNSName = c.Server
+	}
+
+	return target, nil
+}
+
+// AggregateWarning returns the user-specified percentage or number of
+// targets with problems at which the combined service check result is
+// WARNING or the default value for the chosen aggregate strategy if not
+// specified. Zero is returned for the worst strategy.
+func (c Config) AggregateWarning() int {
+	switch {
+	case c.aggregateWarning != defaultAggregateThreshold:
+		return c.aggregateWarning
+	case c.AggregateStrategy == AggregateStrategyPercentage:
+		return defaultAggregateWarningPercentage
+	case c.AggregateStrategy == AggregateStrategyCount:
+		return defaultAggregateWarningCount
+	default:
+		return defaultAggregateThreshold
+	}
+}
+
+// AggregateCritical returns the user-specified percentage or number of
+// targets with problems at which the combined service check result is
+// CRITICAL or the default value for the chosen aggregate strategy if not
+// specified. Zero is returned for the worst strategy.
+func (c Config) AggregateCritical() int {
+	switch {
+	case c.aggregateCritical != defaultAggregateThreshold:
+		return c.aggregateCritical
+	case c.AggregateStrategy == AggregateStrategyPercentage:
+		return defaultAggregateCriticalPercentage
+	case c.AggregateStrategy == AggregateStrategyCount:
+		return defaultAggregateCriticalCount
+	default:
+		return defaultAggregateThreshold
+	}
+}
+
+// PluginTimeout converts the user-specified plugin timeout value in seconds
+// to an appropriate time duration value. A zero value indicates that no
+// plugin timeout was specified.
+func (c Config) PluginTimeout() time.Duration {
+	return time.Duration(c.pluginTimeout) * time.Second
+}
+
 // CertPorts returns the user-specified list of ports to check for
 // certificates or the default value if not specified.
 func (c Config) CertPorts() []int {
@@ -46,15 +224,105 @@ func (c Config) CertPorts() []int {
 	return []int{defaultPortsListEntry}
 }
 
+// ServerPorts returns the user-specified list of ports to evaluate on the
+// specified server with duplicate entries removed. Nil is returned if a list
+// of ports was not specified, in which case the single port value is used.
+func (c Config) ServerPorts() []int {
+	if len(c.portsList) == 0 {
+		return nil
+	}
+
+	ports := make([]int, 0, len(c.portsList))
+	seen := make(map[int]struct{}, len(c.portsList))
+	for _, port := range c.portsList {
+		if _, ok := seen[port]; ok {
+			continue
+		}
+		seen[port] = struct{}{}
+		ports = append(ports, port)
+	}
+
+	return ports
+}
+
+// ScanRateLimitFor returns the maximum number of concurrent scans for the
+// given number of scan targets (e.g., IP Address and port combinations). The
+// user-specified limit is returned if provided, otherwise a limit is
+// calculated from the number of available CPUs and capped to the number of
+// scan targets so that small scans do not reserve unneeded resources.
+func (c Config) ScanRateLimitFor(numTargets int) int {
+	if c.ScanRateLimit > 0 {
+		return c.ScanRateLimit
+	}
+
+	limit := runtime.GOMAXPROCS(0) * scanRateLimitPerCPU
+
+	switch {
+	case limit < scanRateLimitAutoMin:
+		limit = scanRateLimitAutoMin
+	case limit > scanRateLimitAutoMax:
+		limit = scanRateLimitAutoMax
+	}
+
+	if numTargets < limit {
+		limit = numTargets
+	}
+
+	if limit < 1 {
+		limit = 1
+	}
+
+	return limit
+}
+
 // Hosts returns a list of individual IP Addresses expanded from any
-// user-specified IP Addresses (single or ranges) and hostnames or FQDNs that
-// passed name resolution checks.
-func (c Config) Hosts() []netutils.HostPattern {
-	if c.hosts.hostValues != nil {
-		return c.hosts.hostValues
+// user-specified IP Addresses (single or ranges) and hostnames or FQDNs.
+// Hostnames and FQDNs are resolved using the sysadmin-specified DNS server
+// (if any) and DNS resolution timeout. An error is returned if a host
+// pattern is invalid or fails name resolution.
+func (c Config) Hosts() ([]netutils.HostPattern, error) {
+	hosts, err := c.hosts.expand(c.Resolver(), c.DNSTimeout())
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q flag: %w", HostsFlagLong, err)
 	}
 
-	return []netutils.HostPattern{}
+	return hosts, nil
+}
+
+// ExcludedHosts returns a list of host patterns removed from the expanded
+// set of hosts to scan. Hostnames and FQDNs are resolved in the same manner
+// as for Hosts. An error is returned if a host pattern is invalid or fails
+// name resolution.
+//
+// IPv6 CIDR exclusion patterns are matched by range regardless of size, but
+// other IPv6 ranges are matched by their expanded IP Addresses. An error is
+// returned for an IPv6 partial range too large to expand in full as
+// addresses beyond the expanded limit would not be excluded.
+func (c Config) ExcludedHosts() ([]netutils.HostPattern, error) {
+	hosts, err := c.excludeHosts.expand(c.Resolver(), c.DNSTimeout())
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q flag: %w", ExcludeHostsFlag, err)
+	}
+
+	for _, host := range hosts {
+		if _, prefixErr := netip.ParsePrefix(host.Given); host.Truncated && prefixErr != nil {
+			return nil, fmt.Errorf(
+				"invalid value for %q flag: %q has more than %d addresses: %w",
+				ExcludeHostsFlag,
+				host.Given,
+				netutils.MaxIPv6RangeHosts,
+				netutils.ErrIPv6RangeTooLarge,
+			)
+		}
+	}
+
+	return hosts, nil
+}
+
+// ExcludedHostPatterns returns the user-specified host patterns removed
+// from the expanded set of hosts to scan.
+func (c Config) ExcludedHostPatterns() []string {
+	return c.excludeHosts.givenValues
 }
 
 // CertTypesToKeep returns the user-specified list of certificate types to
@@ -68,6 +336,86 @@ func (c Config) CertTypesToKeep() []string {
 	return []string{defaultCertTypesToKeep}
 }
 
+// RequiredEKUs returns the user-specified list of Extended Key Usage keywords
+// required for the leaf certificate or the default value if not specified.
+func (c Config) RequiredEKUs() []string {
+	if c.requiredEKUs != nil {
+		return c.requiredEKUs
+	}
+
+	return []string{defaultRequiredEKU}
+}
+
+// DisallowedEKUs returns the user-specified list of Extended Key Usage
+// keywords not permitted for the leaf certificate.
+func (c Config) DisallowedEKUs() []string {
+	if c.disallowedEKUs != nil {
+		return c.disallowedEKUs
+	}
+
+	return []string{}
+}
+
+// IgnoredSerials returns the user-specified list of serial numbers for
+// certificates in the chain which should be ignored when evaluating
+// expiration and signature algorithms.
+func (c Config) IgnoredSerials() []string {
+	if c.ignoredSerials != nil {
+		return c.ignoredSerials
+	}
+
+	return []string{}
+}
+
+// IgnoredFingerprints returns the user-specified list of SHA-256
+// fingerprints for certificates in the chain which should be ignored when
+// evaluating expiration and signature algorithms.
+func (c Config) IgnoredFingerprints() []string {
+	if c.ignoredFingerprints != nil {
+		return c.ignoredFingerprints
+	}
+
+	return []string{}
+}
+
+// StateMappings returns the user-specified service check state overrides as
+// a collection of uppercase FROM state labels to uppercase TO state labels.
+// An empty collection is returned if no overrides were specified.
+//
+// NOTE: Config validation is expected to reject malformed values.
+func (c Config) StateMappings() map[string]string {
+	mappings := make(map[string]string, len(c.stateMappings))
+	for _, mapping := range c.stateMappings {
+		from, to, found := strings.Cut(mapping, "=")
+		if !found {
+			continue
+		}
+
+		mappings[strings.ToUpper(strings.TrimSpace(from))] = strings.ToUpper(strings.TrimSpace(to))
+	}
+
+	return mappings
+}
+
+// MaxSeverity returns the user-specified validation check state limits as a
+// collection of lowercase validation check keywords to uppercase state
+// labels. An empty collection is returned if no limits were specified.
+//
+// NOTE: Config validation is expected to reject malformed values.
+func (c Config) MaxSeverity() map[string]string {
+	limits := make(map[string]string, len(c.maxSeverity))
+	for _, limit := range c.maxSeverity {
+		keyword, state, found := strings.Cut(limit, "=")
+		if !found {
+			continue
+		}
+
+		limits[strings.ToLower(strings.TrimSpace(keyword))] = strings.ToUpper(strings.TrimSpace(state))
+	}
+
+	return limits
+}
+
 // ApplyCertHostnameValidationResults indicates whether certificate hostname
 // validation check results should be applied when performing final plugin
 // state evaluation. Precedence is given for explicit request to ignore this
@@ -178,6 +526,472 @@ func (c Config) ApplyCertSANsListValidationResults() bool {
 
 }
 
+// ApplyCertSerialNumberValidationResults indicates whether certificate
+// serial number validation check results should be applied when performing
+// final plugin state evaluation. Precedence is given for explicit request to
+// ignore this validation result.
+func (c Config) ApplyCertSerialNumberValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordSerial, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordSerial, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	// NOTE: Config validation is expected to fail attempts to explicitly
+	// apply serial number validation if the sysadmin did not supply an
+	// expected serial number.
+	case applyRequested:
+		return true
+
+	// Without an expected serial number to compare against the validation
+	// check result is of limited value, so we ignore it.
+	case strings.TrimSpace(c.ExpectedSerial) == "":
+		return false
+
+	default:
+		return defaultApplyCertSerialNumberValidationResults
+	}
+}
+
+// ApplyCertExtKeyUsageValidationResults indicates whether certificate
+// Extended Key Usage validation check results should be applied when
+// performing final plugin state evaluation. Precedence is given for explicit
+// request to ignore this validation result.
+func (c Config) ApplyCertExtKeyUsageValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordEKU, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordEKU, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	// Explicitly applying this validation check without specifying required
+	// EKUs uses the default list.
+	case applyRequested:
+		return true
+
+	// Prior stable releases did not evaluate EKUs, so we only apply this
+	// validation check if the sysadmin opted in by specifying EKU values.
+	case len(c.requiredEKUs) == 0 && len(c.disallowedEKUs) == 0:
+		return false
+
+	default:
+		return defaultApplyCertExtKeyUsageValidationResults
+	}
+}
+
+// ApplyCertChainConstraintsValidationResults indicates whether Key Usage and
+// Basic Constraints validation check results should be applied when
+// performing final plugin state evaluation. Precedence is given for explicit
+// request to ignore this validation result.
+func (c Config) ApplyCertChainConstraintsValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordConstraints, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordConstraints, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyCertChainConstraintsValidationResults
+	}
+}
+
+// ApplyCertKeyUsageValidationResults indicates whether Key Usage and Extended
+// Key Usage consistency validation check results should be applied when
+// performing final plugin state evaluation. Precedence is given for explicit
+// request to ignore this validation result.
+func (c Config) ApplyCertKeyUsageValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordKeyUsage, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordKeyUsage, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyCertKeyUsageValidationResults
+	}
+}
+
+// ApplyCertDuplicateCertsValidationResults indicates whether duplicate
+// certificate validation check results should be applied when performing
+// final plugin state evaluation. Precedence is given for explicit request to
+// ignore this validation result.
+func (c Config) ApplyCertDuplicateCertsValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordDuplicates, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordDuplicates, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyCertDuplicateCertsValidationResults
+	}
+}
+
+// ApplyCertSelfSignedValidationResults indicates whether self-signed leaf
+// certificate validation check results should be applied when performing
+// final plugin state evaluation. Precedence is given for explicit request to
+// ignore this validation result.
+func (c Config) ApplyCertSelfSignedValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordSelfSigned, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordSelfSigned, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyCertSelfSignedValidationResults
+	}
+}
+
+// ApplyCertDistrustedCAsValidationResults indicates whether distrusted CA
+// validation check results should be applied when performing final plugin
+// state evaluation. Precedence is given for explicit request to ignore this
+// validation result.
+func (c Config) ApplyCertDistrustedCAsValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordDistrusted, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordDistrusted, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyCertDistrustedCAsValidationResults
+	}
+}
+
+// DistrustedCAs returns the list of distrusted certificate authorities
+// loaded from the sysadmin-specified file or the built-in list if a file was
+// not specified.
+func (c Config) DistrustedCAs() certs.DistrustedCAList {
+	if c.distrustedCAs == nil {
+		return certs.DefaultDistrustedCAList()
+	}
+
+	return *c.distrustedCAs
+}
+
+// ApplyCertBlocklistValidationResults indicates whether blocklist validation
+// check results should be applied when performing final plugin state
+// evaluation. Precedence is given for explicit request to ignore this
+// validation result.
+func (c Config) ApplyCertBlocklistValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordBlocklist, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordBlocklist, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	// NOTE: Config validation is expected to fail attempts to explicitly
+	// apply blocklist validation if the sysadmin did not supply a blocklist
+	// file.
+	case applyRequested:
+		return true
+
+	// Without a blocklist to compare against the validation check result is
+	// of no value, so we ignore it.
+	case c.BlocklistFile == "":
+		return false
+
+	default:
+		return defaultApplyCertBlocklistValidationResults
+	}
+}
+
+// ApplyCertCTLogsValidationResults indicates whether validation check
+// results from comparing the leaf certificate against certificates recorded
+// in Certificate Transparency logs should be applied when performing final
+// plugin state evaluation. Precedence is given for explicit request to ignore
+// this validation result.
+func (c Config) ApplyCertCTLogsValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordCTLogs, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordCTLogs, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyCertCTLogsValidationResults
+	}
+}
+
+// ApplyRenewalInfoValidationResults indicates whether validation check
+// results from comparing the current time against the renewal window
+// suggested by the Certificate Authority for the leaf certificate should be
+// applied when performing final plugin state evaluation. Precedence is given
+// for explicit request to ignore this validation result.
+func (c Config) ApplyRenewalInfoValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordRenewalInfo, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordRenewalInfo, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyRenewalInfoValidationResults
+	}
+}
+
+// ApplyMinTLSVersionValidationResults indicates whether validation check
+// results from probing the server for acceptance of TLS protocol versions
+// older than the minimum version should be applied when performing final
+// plugin state evaluation. Precedence is given for explicit request to ignore
+// this validation result.
+func (c Config) ApplyMinTLSVersionValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordTLSVersion, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordTLSVersion, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyMinTLSVersionValidationResults
+	}
+}
+
+// ApplyWeakCipherSuitesValidationResults indicates whether validation check
+// results from probing the server for acceptance of known-weak cipher suites
+// should be applied when performing final plugin state evaluation. Precedence
+// is given for explicit request to ignore this validation result.
+func (c Config) ApplyWeakCipherSuitesValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordWeakCiphers, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordWeakCiphers, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyWeakCipherSuitesValidationResults
+	}
+}
+
+// ApplyExpiryCliffValidationResults indicates whether validation check
+// results from evaluating a certificate chain for multiple certificates
+// expiring together should be applied when performing final plugin state
+// evaluation. Precedence is given for explicit request to ignore this
+// validation result.
+func (c Config) ApplyExpiryCliffValidationResults() bool {
+
+	ignoreRequested := textutils.InList(
+		ValidationKeywordExpiryCliff, c.ignoreValidationResults, true,
+	)
+
+	applyRequested := textutils.InList(
+		ValidationKeywordExpiryCliff, c.applyValidationResults, true,
+	)
+
+	switch {
+	case ignoreRequested:
+		return false
+
+	case applyRequested:
+		return true
+
+	default:
+		return defaultApplyExpiryCliffValidationResults
+	}
+}
+
+// ExpiryCliffWindow returns the period within which the expiration of
+// multiple certificates is treated as a single expiry cliff. A zero value
+// indicates that expiry cliff detection is disabled.
+func (c Config) ExpiryCliffWindow() time.Duration {
+	return time.Duration(c.expiryCliffWindow) * 24 * time.Hour
+}
+
+// ExpiryCliffLeadTime returns the period before the earliest expiration date
+// of an expiry cliff when the expiry cliff is reported.
+func (c Config) ExpiryCliffLeadTime() time.Duration {
+	return time.Duration(c.expiryCliffLeadTime) * 24 * time.Hour
+}
+
+// MinTLSVersion returns the oldest TLS protocol version the server is
+// permitted to negotiate. The default minimum version is returned if a
+// version was not specified.
+func (c Config) MinTLSVersion() uint16 {
+	version, err := netutils.ParseTLSVersion(c.minTLSVersion)
+	if err != nil {
+		version, _ = netutils.ParseTLSVersion(defaultMinTLSVersion)
+	}
+
+	return version
+}
+
+// CTSearchTokenProvider returns the secret provider for the API token sent
+// with CT search API requests. Nil is returned if an API token was not
+// specified.
+func (c Config) CTSearchTokenProvider() secrets.Provider {
+	if c.ctSearchTokenProvider != nil {
+		return c.ctSearchTokenProvider
+	}
+
+	return c.ctSearchTokenSecretProvider()
+}
+
+// ctSearchTokenSecretProvider parses the user-specified secret provider for
+// the CT search API token. Nil is returned if an API token was not specified
+// or the provider specification is invalid.
+func (c Config) ctSearchTokenSecretProvider() secrets.Provider {
+	switch {
+	case c.CTSearchTokenCmd != "":
+		return secrets.CommandProvider{Command: c.CTSearchTokenCmd}
+
+	case c.CTSearchToken != "":
+		provider, err := secrets.ParseProvider(c.CTSearchToken)
+		if err != nil {
+			return nil
+		}
+
+		return provider
+
+	default:
+		return nil
+	}
+}
+
+// P12PasswordProvider returns the secret provider for the password
+// protecting the PKCS #12 bundle written by the copier app. Nil is returned
+// if a password was not specified or the provider specification is invalid.
+func (c Config) P12PasswordProvider() secrets.Provider {
+	if c.P12Password == "" {
+		return nil
+	}
+
+	provider, err := secrets.ParseProvider(c.P12Password)
+	if err != nil {
+		return nil
+	}
+
+	return provider
+}
+
+// WebhookAuthHeaderProvider returns the secret provider for the value of
+// the authentication header sent with the webhook request. Nil is returned
+// if a header value was not specified or the provider specification is
+// invalid.
+func (c Config) WebhookAuthHeaderProvider() secrets.Provider {
+	if c.WebhookAuthHeader == "" {
+		return nil
+	}
+
+	provider, err := secrets.ParseProvider(c.WebhookAuthHeader)
+	if err != nil {
+		return nil
+	}
+
+	return provider
+}
+
 // supportedValidationCheckResultKeywords returns a list of valid validation
 // check keywords used by plugin type applications in this project.
 func supportedValidationCheckResultKeywords() []string {
@@ -185,6 +999,27 @@ func supportedValidationCheckResultKeywords() []string {
 		ValidationKeywordHostname,
 		ValidationKeywordExpiration,
 		ValidationKeywordSANsList,
+		ValidationKeywordSerial,
+		ValidationKeywordEKU,
+		ValidationKeywordConstraints,
+		ValidationKeywordKeyUsage,
+		ValidationKeywordDuplicates,
+		ValidationKeywordSelfSigned,
+		ValidationKeywordDistrusted,
+		ValidationKeywordBlocklist,
+		ValidationKeywordCTLogs,
+		ValidationKeywordTLSVersion,
+		ValidationKeywordWeakCiphers,
+		ValidationKeywordExpiryCliff,
+		ValidationKeywordRenewalInfo,
+	}
+}
+
+// supportedMaxSeverityStates returns a list of valid service check state
+// labels used to limit the state contributed by a validation check.
+func supportedMaxSeverityStates() []string {
+	return []string{
+		nagios.StateWARNINGLabel,
 	}
 }
 
@@ -199,6 +1034,57 @@ func supportedCertTypeFilterKeywords() []string {
 	}
 }
 
+// supportedResolverDNSSECModes returns a list of valid modes used to determine how
+// DNS answers which are not authenticated using DNSSEC are handled.
+func supportedResolverDNSSECModes() []string {
+	return []string{
+		ResolverDNSSECModeOff,
+		ResolverDNSSECModeWarn,
+		ResolverDNSSECModeFail,
+	}
+}
+
+// supportedSimulateStates returns a list of valid service check states
+// which may be simulated.
+func supportedSimulateStates() []string {
+	return []string{
+		SimulateStateCritical,
+		SimulateStateWarning,
+	}
+}
+
+// supportedAggregateStrategies returns a list of valid strategies used to
+// combine the results for multiple targets into a single service check
+// result.
+func supportedAggregateStrategies() []string {
+	return []string{
+		AggregateStrategyWorst,
+		AggregateStrategyPercentage,
+		AggregateStrategyCount,
+	}
+}
+
+// supportedOutputFormats returns a list of valid output formats used by
+// scanner type applications in this project.
+func supportedOutputFormats() []string {
+	return []string{
+		OutputFormatText,
+		OutputFormatJSON,
+		OutputFormatNDJSON,
+		OutputFormatInflux,
+		OutputFormatMarkdown,
+	}
+}
+
+// supportedInspectorOutputFormats returns a list of valid output formats
+// used by inspector type applications in this project.
+func supportedInspectorOutputFormats() []string {
+	return []string{
+		OutputFormatText,
+		OutputFormatMarkdown,
+	}
+}
+
 // supportedLogLevels returns a list of valid log levels supported by tools in
 // this project.
 func supportedLogLevels() []string {
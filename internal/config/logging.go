@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/atc0005/check-cert/internal/certs"
 	"github.com/rs/zerolog"
 )
 
@@ -83,8 +84,13 @@ func (c *Config) setupLogging(appType AppType) error {
 	switch {
 	case appType.Inspector:
 		// CLI app logging uses ConsoleWriter to generate human-friendly,
-		// colorized output to stdout.
+		// colorized output to stdout. Log messages are sent to stderr
+		// instead when converting saved plugin output so that the emitted
+		// JSON records are not interleaved with log messages.
 		consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout}
+		if c.BackfillDir != "" {
+			consoleWriter.Out = os.Stderr
+		}
 		c.Log = zerolog.New(consoleWriter).With().Timestamp().Caller().
 			Str("version", Version()).
 			Str("logging_level", c.LoggingLevel).
@@ -123,6 +129,11 @@ func (c *Config) setupLogging(appType AppType) error {
 		// Plugin logging uses ConsoleWriter to generate human-friendly,
 		// colorized output to stderr. Log output is sent to stderr to prevent
 		// mixing in with stdout output intended for the Nagios console.
+		ports := zerolog.Arr()
+		for _, port := range c.ServerPorts() {
+			ports.Int(port)
+		}
+
 		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr}
 		c.Log = zerolog.New(consoleWriter).With().Timestamp().Caller().
 			Str("version", Version()).
@@ -130,7 +141,10 @@ func (c *Config) setupLogging(appType AppType) error {
 			Str("app_type", appTypePlugin).
 			Str("filename", c.InputFilename).
 			Str("server", c.Server).
+			Str("targets_file", c.TargetsFile).
+			Str("socket", c.Socket).
 			Int("port", c.Port).
+			Array("ports", ports).
 			Str("cert_check_timeout", c.Timeout().String()).
 			Int("age_warning", c.AgeWarning).
 			Int("age_critical", c.AgeCritical).
@@ -159,6 +173,53 @@ func (c *Config) setupLogging(appType AppType) error {
 			Int("age_warning", c.AgeWarning).
 			Int("age_critical", c.AgeCritical).
 			Logger()
+
+	case appType.Exporter:
+		// Exporter logging uses ConsoleWriter to generate human-friendly,
+		// colorized output to stderr.
+
+		ports := zerolog.Arr()
+		for _, port := range c.CertPorts() {
+			ports.Int(port)
+		}
+
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr}
+		c.Log = zerolog.New(consoleWriter).With().Timestamp().Caller().
+			Str("version", Version()).
+			Str("logging_level", c.LoggingLevel).
+			Str("app_type", appTypeExporter).
+			Str("listen_address", c.ListenAddress).
+			Array("ports", ports).
+			Str("cert_check_timeout", c.Timeout().String()).
+			Int("age_warning", c.AgeWarning).
+			Int("age_critical", c.AgeCritical).
+			Logger()
+
+	case appType.Monitor:
+		// Monitor logging uses ConsoleWriter to generate human-friendly,
+		// colorized output to stderr.
+
+		ports := zerolog.Arr()
+		for _, port := range c.CertPorts() {
+			ports.Int(port)
+		}
+
+		consoleWriter := zerolog.ConsoleWriter{Out: os.Stderr}
+		c.Log = zerolog.New(consoleWriter).With().Timestamp().Caller().
+			Str("version", Version()).
+			Str("logging_level", c.LoggingLevel).
+			Str("app_type", appTypeMonitor).
+			Str("listen_address", c.ListenAddress).
+			Array("ports", ports).
+			Str("check_interval", c.CheckInterval().String()).
+			Str("cert_check_timeout", c.Timeout().String()).
+			Int("age_warning", c.AgeWarning).
+			Int("age_critical", c.AgeCritical).
+			Logger()
+	}
+
+	if reason := certs.FIPSModeReason(); reason != "" {
+		c.Log = c.Log.With().Str("fips_mode", reason).Logger()
 	}
 
 	return setLoggingLevel(c.LoggingLevel)
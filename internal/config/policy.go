@@ -0,0 +1,157 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// supportedPolicySettings returns the flag names which may be overridden by
+// a policy.
+func supportedPolicySettings() []string {
+	return []string{
+		AgeWarningFlagLong,
+		AgeCriticalFlagLong,
+		ApplyValidationResultFlag,
+		IgnoreValidationResultFlag,
+		MinTLSVersionFlag,
+	}
+}
+
+// WithPolicySettings returns a copy of the configuration with the given
+// policy settings (indexed by flag name) applied. Validation check result
+// keywords listed by a policy are added to those specified via flags,
+// replacing any opposite treatment (i.e., ignored instead of applied or
+// applied instead of ignored) requested via flags. An error is returned if a
+// setting is not supported or the resulting configuration is invalid.
+func (c Config) WithPolicySettings(settings map[string][]string) (*Config, error) {
+	policyCfg := c
+
+	// Copy the keyword lists so that changes are not reflected in the
+	// original configuration.
+	policyCfg.applyValidationResults = append(multiValueStringFlag(nil), c.applyValidationResults...)
+	policyCfg.ignoreValidationResults = append(multiValueStringFlag(nil), c.ignoreValidationResults...)
+
+	// Apply settings in a consistent order.
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		values := settings[name]
+
+		switch name {
+		case AgeWarningFlagLong, AgeCriticalFlagLong:
+			days, err := singlePolicyIntValue(name, values)
+			if err != nil {
+				return nil, err
+			}
+
+			if name == AgeWarningFlagLong {
+				policyCfg.AgeWarning = days
+			} else {
+				policyCfg.AgeCritical = days
+			}
+
+		case MinTLSVersionFlag:
+			if len(values) != 1 {
+				return nil, fmt.Errorf(
+					"policy setting %q requires a single value: %w",
+					name,
+					ErrUnsupportedOption,
+				)
+			}
+
+			policyCfg.minTLSVersion = values[0]
+
+		case ApplyValidationResultFlag:
+			for _, keyword := range values {
+				if textutils.InList(keyword, settings[IgnoreValidationResultFlag], true) {
+					return nil, fmt.Errorf(
+						"validation keyword %q listed by both %q and %q policy settings: %w",
+						keyword,
+						ApplyValidationResultFlag,
+						IgnoreValidationResultFlag,
+						ErrUnsupportedOption,
+					)
+				}
+
+				policyCfg.ignoreValidationResults = removeKeyword(policyCfg.ignoreValidationResults, keyword)
+				if !textutils.InList(keyword, policyCfg.applyValidationResults, true) {
+					policyCfg.applyValidationResults = append(policyCfg.applyValidationResults, keyword)
+				}
+			}
+
+		case IgnoreValidationResultFlag:
+			for _, keyword := range values {
+				policyCfg.applyValidationResults = removeKeyword(policyCfg.applyValidationResults, keyword)
+				if !textutils.InList(keyword, policyCfg.ignoreValidationResults, true) {
+					policyCfg.ignoreValidationResults = append(policyCfg.ignoreValidationResults, keyword)
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf(
+				"unsupported policy setting %q; supported settings: %v: %w",
+				name,
+				supportedPolicySettings(),
+				ErrUnsupportedOption,
+			)
+		}
+	}
+
+	if err := policyCfg.validate(AppType{Plugin: true}); err != nil {
+		return nil, err
+	}
+
+	return &policyCfg, nil
+}
+
+// singlePolicyIntValue converts the single value for the named policy
+// setting to an integer.
+func singlePolicyIntValue(name string, values []string) (int, error) {
+	if len(values) != 1 {
+		return 0, fmt.Errorf(
+			"policy setting %q requires a single value: %w",
+			name,
+			ErrUnsupportedOption,
+		)
+	}
+
+	num, err := strconv.Atoi(strings.TrimSpace(values[0]))
+	if err != nil {
+		return 0, fmt.Errorf(
+			"invalid value %q for policy setting %q: %w",
+			values[0],
+			name,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return num, nil
+}
+
+// removeKeyword returns the given list of keywords without the specified
+// keyword (case-insensitive).
+func removeKeyword(keywords multiValueStringFlag, keyword string) multiValueStringFlag {
+	kept := make(multiValueStringFlag, 0, len(keywords))
+	for _, k := range keywords {
+		if !strings.EqualFold(k, keyword) {
+			kept = append(kept, k)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package config
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/textutils"
+)
+
+// Names of the supported check profiles.
+const (
+	ProfileStrict      string = "strict"
+	ProfileLenient     string = "lenient"
+	ProfileInternalPKI string = "internal-pki"
+	ProfilePublicWeb   string = "public-web"
+)
+
+// profileSetting is a single flag value preset by a check profile.
+type profileSetting struct {
+	// name is the flag name.
+	name string
+
+	// values is the collection of values for the flag. Multiple values are
+	// provided for multi-value flags.
+	values []string
+}
+
+// checkProfiles is the collection of supported check profiles. Each profile
+// presets a group of validation flags and expiration thresholds in order to
+// simplify applying consistent policy across many service definitions.
+var checkProfiles = map[string][]profileSetting{
+
+	// Tighter expiration thresholds and opt-in validation checks applied.
+	// Validation checks which make additional connections to the server
+	// (minimum TLS version and weak cipher suites) are left to the sysadmin.
+	ProfileStrict: {
+		{name: AgeWarningFlagLong, values: []string{"45"}},
+		{name: AgeCriticalFlagLong, values: []string{"30"}},
+		{
+			name: ApplyValidationResultFlag,
+			values: []string{
+				ValidationKeywordConstraints,
+				ValidationKeywordKeyUsage,
+				ValidationKeywordSelfSigned,
+			},
+		},
+	},
+
+	// Shorter expiration thresholds with expired or expiring intermediate and
+	// root certificates ignored and hostname verification failures limited to
+	// a WARNING state.
+	ProfileLenient: {
+		{name: AgeWarningFlagLong, values: []string{"14"}},
+		{name: AgeCriticalFlagLong, values: []string{"7"}},
+		{name: IgnoreHostnameVerificationFailureIfEmptySANsListFlag, values: []string{"true"}},
+		{name: IgnoreExpiredIntermediateCertificatesFlag, values: []string{"true"}},
+		{name: IgnoreExpiredRootCertificatesFlag, values: []string{"true"}},
+		{name: IgnoreExpiringIntermediateCertificatesFlag, values: []string{"true"}},
+		{name: IgnoreExpiringRootCertificatesFlag, values: []string{"true"}},
+		{name: MaxSeverityFlag, values: []string{ValidationKeywordHostname + "=warning"}},
+	},
+
+	// Longer expiration thresholds to allow for manual renewal of
+	// certificates issued by a private CA. Long-lived private roots and
+	// legacy certificates without SANs entries are tolerated.
+	ProfileInternalPKI: {
+		{name: AgeWarningFlagLong, values: []string{"60"}},
+		{name: AgeCriticalFlagLong, values: []string{"30"}},
+		{name: IgnoreHostnameVerificationFailureIfEmptySANsListFlag, values: []string{"true"}},
+		{name: IgnoreExpiringRootCertificatesFlag, values: []string{"true"}},
+		{
+			name: ApplyValidationResultFlag,
+			values: []string{
+				ValidationKeywordConstraints,
+				ValidationKeywordKeyUsage,
+			},
+		},
+	},
+
+	// Expiration thresholds suited to short-lived certificates issued by
+	// public CAs (e.g., renewed 30 days before expiration) with self-signed
+	// certificates and key usage problems flagged.
+	ProfilePublicWeb: {
+		{name: AgeWarningFlagLong, values: []string{"20"}},
+		{name: AgeCriticalFlagLong, values: []string{"10"}},
+		{
+			name: ApplyValidationResultFlag,
+			values: []string{
+				ValidationKeywordKeyUsage,
+				ValidationKeywordSelfSigned,
+			},
+		},
+	},
+}
+
+// supportedProfiles returns a sorted list of valid check profile names.
+func supportedProfiles() []string {
+	profiles := make([]string, 0, len(checkProfiles))
+	for name := range checkProfiles {
+		profiles = append(profiles, name)
+	}
+
+	sort.Strings(profiles)
+
+	return profiles
+}
+
+// handleProfile applies flag values preset by the user-specified check
+// profile. Flag values specified on the command-line or via a configuration
+// file take precedence over values from the profile.
+func (c *Config) handleProfile() error {
+	if c.Profile == "" {
+		return nil
+	}
+
+	settings, ok := checkProfiles[strings.ToLower(strings.TrimSpace(c.Profile))]
+	if !ok {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; supported profiles: %v: %w",
+			c.Profile,
+			ProfileFlag,
+			supportedProfiles(),
+			ErrUnsupportedOption,
+		)
+	}
+
+	// Short and long flag names share the same underlying value, so the
+	// values of flags explicitly set are tracked instead of the flag names.
+	explicitlySet := make(map[flag.Value]struct{})
+	flag.Visit(func(f *flag.Flag) {
+		explicitlySet[f.Value] = struct{}{}
+	})
+
+	for _, setting := range settings {
+		f := flag.Lookup(setting.name)
+		if f == nil {
+			continue
+		}
+
+		if _, ok := explicitlySet[f.Value]; ok {
+			continue
+		}
+
+		for _, value := range setting.values {
+			if c.conflictsWithValidationResultKeywords(setting.name, value) {
+				continue
+			}
+
+			if err := f.Value.Set(value); err != nil {
+				return fmt.Errorf(
+					"invalid value %q for setting %q of profile %q: %w",
+					value,
+					setting.name,
+					c.Profile,
+					err,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// conflictsWithValidationResultKeywords indicates whether the given profile
+// value for the named flag is a validation check result keyword explicitly
+// requested for the opposite treatment (i.e., ignored instead of applied or
+// applied instead of ignored).
+func (c Config) conflictsWithValidationResultKeywords(name string, value string) bool {
+	switch name {
+	case ApplyValidationResultFlag:
+		return textutils.InList(value, c.ignoreValidationResults, true)
+	case IgnoreValidationResultFlag:
+		return textutils.InList(value, c.applyValidationResults, true)
+	default:
+		return false
+	}
+}
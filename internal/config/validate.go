@@ -9,11 +9,137 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	payload "github.com/atc0005/cert-payload"
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/atc0005/check-cert/internal/secrets"
 	"github.com/atc0005/check-cert/internal/textutils"
+	"github.com/atc0005/go-nagios"
 )
 
+// validateValidationResultKeywords asserts that the keywords specified via
+// the flags used to explicitly ignore or apply validation check results are
+// supported, are not specified for both flags and that values required to
+// apply the specified validation checks are provided.
+func validateValidationResultKeywords(c Config) error {
+	supportedValidationKeywords := supportedValidationCheckResultKeywords()
+
+	// Validate the specified explicit "ignore" validation check results
+	// keywords
+	for _, specifiedKeyword := range c.ignoreValidationResults {
+		if !textutils.InList(specifiedKeyword, supportedValidationKeywords, true) {
+			return fmt.Errorf(
+				"invalid ignore validation results keyword specified; got %v, expected one of %v",
+				specifiedKeyword,
+				supportedValidationKeywords,
+			)
+		}
+	}
+
+	// Validate the specified explicit "apply" validation check results
+	// keywords
+	for _, specifiedKeyword := range c.applyValidationResults {
+		if !textutils.InList(specifiedKeyword, supportedValidationKeywords, true) {
+			return fmt.Errorf(
+				"invalid apply validation results keyword specified; got %v, expected one of %v",
+				specifiedKeyword,
+				supportedValidationKeywords,
+			)
+		}
+	}
+
+	// If we have explicit apply AND explicit ignore keywords ...
+	if len(c.applyValidationResults) > 0 && len(c.ignoreValidationResults) > 0 {
+
+		// Assert that the same keyword is not present in both explicit
+		// apply and explicit ignore flag values.
+		for _, keyword := range supportedValidationKeywords {
+			if textutils.InList(keyword, c.applyValidationResults, true) &&
+				textutils.InList(keyword, c.ignoreValidationResults, true) {
+				return fmt.Errorf(
+					"specified validation keyword %q was specified as"+
+						" value for multiple flags;"+
+						" keyword may be used with only one of %q or %q"+
+						" flags",
+					keyword,
+					IgnoreValidationResultFlag,
+					ApplyValidationResultFlag,
+				)
+			}
+		}
+	}
+
+	// If the sysadmin explicitly requested that SANs list validation
+	// check results be applied, but did not provide a SANs entries list
+	// to use for validation we can't perform SANs list validation.
+	//
+	// The default behavior is to perform SANs list validation *if* a list
+	// of SANs entries to validate is provided.
+	if textutils.InList(ValidationKeywordSANsList, c.applyValidationResults, true) {
+		if len(c.SANsEntries) == 0 {
+			return fmt.Errorf(
+				"unsupported setting for certificate SANs list validation;"+
+					" providing SANs entries via the %q flag is required"+
+					" when specifying the %q keyword via the %q flag",
+				SANsEntriesFlagLong,
+				ValidationKeywordSANsList,
+				ApplyValidationResultFlag,
+			)
+		}
+	}
+
+	// As with SANs list validation, explicitly applying serial number
+	// validation check results requires a value to validate against.
+	if textutils.InList(ValidationKeywordSerial, c.applyValidationResults, true) {
+		if strings.TrimSpace(c.ExpectedSerial) == "" {
+			return fmt.Errorf(
+				"unsupported setting for certificate serial number validation;"+
+					" providing an expected serial number via the %q flag is required"+
+					" when specifying the %q keyword via the %q flag",
+				ExpectedSerialFlag,
+				ValidationKeywordSerial,
+				ApplyValidationResultFlag,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateServiceValidationResultKeywords asserts that validation check
+// results which depend on settings specific to the check_cert plugin (e.g.,
+// a blocklist file or CT search API) are not explicitly applied for the
+// applications which evaluate a collection of hosts on request or on a
+// schedule.
+func validateServiceValidationResultKeywords(c Config) error {
+	unsupportedKeywords := []string{
+		ValidationKeywordBlocklist,
+		ValidationKeywordCTLogs,
+		ValidationKeywordExpiryCliff,
+		ValidationKeywordRenewalInfo,
+	}
+
+	for _, keyword := range unsupportedKeywords {
+		if textutils.InList(keyword, c.applyValidationResults, true) {
+			return fmt.Errorf(
+				"%q keyword for %q flag is not supported by this application: %w",
+				keyword,
+				ApplyValidationResultFlag,
+				ErrUnsupportedOption,
+			)
+		}
+	}
+
+	return nil
+}
+
 func validateAgeThresholds(c Config) error {
 	switch {
 	case c.AgeWarning < 1:
@@ -43,31 +169,1689 @@ func validateAgeThresholds(c Config) error {
 	}
 }
 
+func validateAgePercentThresholds(c Config) error {
+	switch {
+	case c.AgeWarningPercent == 0 && c.AgeCriticalPercent == 0:
+		return nil
+
+	case c.AgeWarningPercent == 0 || c.AgeCriticalPercent == 0:
+		return fmt.Errorf(
+			"%q and %q flags must be specified together: %w",
+			AgeWarningPercentFlag,
+			AgeCriticalPercentFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.AgeWarningPercent < 1 || c.AgeWarningPercent > 99:
+		return fmt.Errorf(
+			"invalid cert expiration WARNING threshold percentage: %d",
+			c.AgeWarningPercent,
+		)
+
+	case c.AgeCriticalPercent < 1 || c.AgeCriticalPercent > 99:
+		return fmt.Errorf(
+			"invalid cert expiration CRITICAL threshold percentage: %d",
+			c.AgeCriticalPercent,
+		)
+
+	case c.AgeCriticalPercent > c.AgeWarningPercent:
+		return fmt.Errorf(
+			"critical threshold percentage set higher than warning threshold percentage",
+		)
+
+	case c.AgeCriticalPercent == c.AgeWarningPercent:
+		return fmt.Errorf(
+			"critical threshold percentage set equal to warning threshold percentage",
+		)
+
+	default:
+		return nil
+	}
+}
+
+// validateChainPositionAgeThresholds asserts that the optional intermediate
+// and root certificate expiration thresholds are specified as valid pairs.
+func validateChainPositionAgeThresholds(c Config) error {
+	thresholds := []struct {
+		position     string
+		warning      int
+		critical     int
+		warningFlag  string
+		criticalFlag string
+	}{
+		{
+			position:     "intermediate",
+			warning:      c.AgeWarningIntermediate,
+			critical:     c.AgeCriticalIntermediate,
+			warningFlag:  AgeWarningIntermediateFlag,
+			criticalFlag: AgeCriticalIntermediateFlag,
+		},
+		{
+			position:     "root",
+			warning:      c.AgeWarningRoot,
+			critical:     c.AgeCriticalRoot,
+			warningFlag:  AgeWarningRootFlag,
+			criticalFlag: AgeCriticalRootFlag,
+		},
+	}
+
+	for _, t := range thresholds {
+		switch {
+		case t.warning == 0 && t.critical == 0:
+			continue
+
+		case t.warning == 0 || t.critical == 0:
+			return fmt.Errorf(
+				"%q and %q flags must be specified together: %w",
+				t.warningFlag,
+				t.criticalFlag,
+				ErrUnsupportedOption,
+			)
+
+		case t.warning < 1:
+			return fmt.Errorf(
+				"invalid %s cert expiration WARNING threshold number: %d",
+				t.position,
+				t.warning,
+			)
+
+		case t.critical < 1:
+			return fmt.Errorf(
+				"invalid %s cert expiration CRITICAL threshold number: %d",
+				t.position,
+				t.critical,
+			)
+
+		case t.critical > t.warning:
+			return fmt.Errorf(
+				"%s critical threshold set higher than warning threshold",
+				t.position,
+			)
+
+		case t.critical == t.warning:
+			return fmt.Errorf(
+				"%s critical threshold set equal to warning threshold",
+				t.position,
+			)
+		}
+	}
+
+	return nil
+}
+
+// validatePhaseTimeouts asserts that the optional per-phase timeouts are not
+// negative.
+func validatePhaseTimeouts(c Config) error {
+	phaseTimeouts := []struct {
+		flag  string
+		value int
+	}{
+		{flag: DNSTimeoutFlag, value: c.dnsTimeout},
+		{flag: ConnectTimeoutFlag, value: c.dialTimeout},
+		{flag: HandshakeTimeoutFlag, value: c.handshakeTimeout},
+	}
+
+	for _, t := range phaseTimeouts {
+		if t.value < 0 {
+			return fmt.Errorf(
+				"invalid %s value %d provided; expected value of 0 or greater",
+				t.flag,
+				t.value,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateDNSServer(c Config) error {
+	if c.DNSServer == "" {
+		return nil
+	}
+
+	if _, err := netutils.DNSServerAddress(c.DNSServer); err != nil {
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.DNSServer,
+			DNSServerFlag,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func validateResolverDNSSECMode(c Config) error {
+	switch c.ResolverDNSSECMode {
+	case "", ResolverDNSSECModeOff:
+		return nil
+
+	case ResolverDNSSECModeWarn, ResolverDNSSECModeFail:
+
+	default:
+		return fmt.Errorf(
+			"invalid value %q for %q flag; expected one of %v: %w",
+			c.ResolverDNSSECMode,
+			ResolverDNSSECFlag,
+			supportedResolverDNSSECModes(),
+			ErrUnsupportedOption,
+		)
+	}
+
+	// The DNS answer is validated for the single server value only.
+	var conflictingFlag string
+	switch {
+	case c.Server == "":
+		return fmt.Errorf(
+			"the %q flag requires the %q flag: %w",
+			ResolverDNSSECFlag,
+			ServerFlagLong,
+			ErrUnsupportedOption,
+		)
+	case c.TargetsFile != "":
+		conflictingFlag = TargetsFileFlag
+	case len(c.ServerPorts()) > 0:
+		conflictingFlag = PortsFlagLong
+	case c.Socket != "":
+		conflictingFlag = SocketFlag
+	}
+
+	if conflictingFlag != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported with the %q flag: %w",
+			ResolverDNSSECFlag,
+			conflictingFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateSimulateState(c Config) error {
+	switch {
+	case c.SimulateState == "" && c.SimulateMessage != "":
+		return fmt.Errorf(
+			"the %q flag requires the %q flag: %w",
+			SimulateMessageFlag,
+			SimulateStateFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.SimulateState == "":
+		return nil
+
+	case !textutils.InList(c.SimulateState, supportedSimulateStates(), false):
+		return fmt.Errorf(
+			"invalid value %q for %q flag; expected one of %v: %w",
+			c.SimulateState,
+			SimulateStateFlag,
+			supportedSimulateStates(),
+			ErrUnsupportedOption,
+		)
+
+	// Each request received via the socket is answered with the actual
+	// service check state.
+	case c.Socket != "":
+		return fmt.Errorf(
+			"the %q flag is not supported with the %q flag: %w",
+			SimulateStateFlag,
+			SocketFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateRetries(c Config) error {
+	switch {
+	case c.retries < 0 || c.retries > maxRetries:
+		return fmt.Errorf(
+			"invalid %s value %d provided; expected value between 0 and %d",
+			RetriesFlag,
+			c.retries,
+			maxRetries,
+		)
+
+	case c.retryDelay < 0:
+		return fmt.Errorf(
+			"invalid %s value %d provided; expected value of 0 or greater",
+			RetryDelayFlag,
+			c.retryDelay,
+		)
+	}
+
+	return nil
+}
+
+func validatePluginTimeout(c Config) error {
+	switch {
+	case c.pluginTimeout == 0:
+		return nil
+
+	case c.pluginTimeout < 0:
+		return fmt.Errorf(
+			"invalid plugin timeout value provided: %d",
+			c.pluginTimeout,
+		)
+
+	// A plugin timeout no longer than the connection timeout leaves no time
+	// for validation checks after the certificate chain is retrieved.
+	case c.PluginTimeout() <= c.Timeout():
+		return fmt.Errorf(
+			"%q value (%d) must be greater than %q value (%d): %w",
+			PluginTimeoutFlag,
+			c.pluginTimeout,
+			TimeoutFlagLong,
+			c.timeout,
+			ErrUnsupportedOption,
+		)
+
+	default:
+		return nil
+	}
+}
+
 func validatePort(c Config) error {
 	// TCP Port 0 is used by server applications to indicate that they
 	// should bind to an available port. Specifying port 0 for a client
 	// application is not useful.
 	if c.Port <= 0 {
 		return fmt.Errorf(
-			"invalid TCP port number; got %d,"+
-				" expected value between %d and %d (e.g., 443, 636)",
-			c.Port,
-			tcpSystemPortStart,
-			tcpDynamicPrivatePortEnd,
+			"invalid TCP port number; got %d,"+
+				" expected value between %d and %d (e.g., 443, 636)",
+			c.Port,
+			tcpSystemPortStart,
+			tcpDynamicPrivatePortEnd,
+		)
+	}
+
+	return nil
+}
+
+func validateServerPorts(c Config) error {
+	if len(c.portsList) == 0 {
+		return nil
+	}
+
+	if c.InputFilename != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported when evaluating certificate files: %w",
+			PortsFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	for _, port := range c.portsList {
+		if port < tcpSystemPortStart || port > tcpDynamicPrivatePortEnd {
+			return fmt.Errorf(
+				"invalid TCP port number for %q flag; got %d,"+
+					" expected value between %d and %d (e.g., 443, 636)",
+				PortsFlagLong,
+				port,
+				tcpSystemPortStart,
+				tcpDynamicPrivatePortEnd,
+			)
+		}
+	}
+
+	// These features record or report on a single certificate chain.
+	var singleChainFlag string
+	switch {
+	case c.ExecHook != "":
+		singleChainFlag = ExecHookFlag
+	case c.StateFile != "":
+		singleChainFlag = StateFileFlag
+	}
+
+	if singleChainFlag != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported when specifying ports via the %q flag: %w",
+			singleChainFlag,
+			PortsFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateSocket(c Config) error {
+	if c.Socket == "" {
+		return nil
+	}
+
+	// Targets are provided by each request.
+	var conflictingFlag string
+	switch {
+	case c.InputFilename != "":
+		conflictingFlag = FilenameFlagLong
+	case c.Server != "":
+		conflictingFlag = ServerFlagLong
+	case c.TargetsFile != "":
+		conflictingFlag = TargetsFileFlag
+	case len(c.ServerPorts()) > 0:
+		conflictingFlag = PortsFlagLong
+	case c.CompareWith != "":
+		conflictingFlag = CompareWithFlag
+	case c.ExecHook != "":
+		conflictingFlag = ExecHookFlag
+	case c.StateFile != "":
+		conflictingFlag = StateFileFlag
+	case c.StatsFile != "":
+		conflictingFlag = StatsFileFlag
+	}
+
+	if conflictingFlag != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported with the %q flag: %w",
+			conflictingFlag,
+			SocketFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	dir := filepath.Dir(c.Socket)
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; directory %q not found: %w",
+			c.Socket,
+			SocketFlag,
+			dir,
+			ErrUnsupportedOption,
+		)
+	}
+
+	// An existing socket (e.g., left behind by a previous run) is replaced,
+	// but other files are not.
+	if fi, err := os.Lstat(c.Socket); err == nil && fi.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; file exists and is not a socket: %w",
+			c.Socket,
+			SocketFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateTrustStore(c Config) error {
+	if c.TrustStore == "" {
+		return nil
+	}
+
+	// Certificates are provided by the bundle.
+	var conflictingFlag string
+	switch {
+	case c.InputFilename != "":
+		conflictingFlag = FilenameFlagLong
+	case c.Server != "":
+		conflictingFlag = ServerFlagLong
+	case c.TargetsFile != "":
+		conflictingFlag = TargetsFileFlag
+	case c.Socket != "":
+		conflictingFlag = SocketFlag
+	case len(c.ServerPorts()) > 0:
+		conflictingFlag = PortsFlagLong
+	case c.CompareWith != "":
+		conflictingFlag = CompareWithFlag
+	case c.StateFile != "":
+		conflictingFlag = StateFileFlag
+
+	// These features report on a single certificate chain and are not
+	// applied to the certificates evaluated from a bundle.
+	case c.ExecHook != "":
+		conflictingFlag = ExecHookFlag
+	case c.EmitPayload:
+		conflictingFlag = PayloadFlag
+	case c.EmitPayloadWithFullChain:
+		conflictingFlag = PayloadWithFullChainFlag
+	}
+
+	if conflictingFlag != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported with the %q flag: %w",
+			conflictingFlag,
+			TrustStoreFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	if strings.EqualFold(c.TrustStore, TrustStoreSystemKeyword) {
+		return nil
+	}
+
+	if fi, err := os.Stat(c.TrustStore); err != nil || fi.IsDir() {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; expected %q keyword or certificate bundle file: %w",
+			c.TrustStore,
+			TrustStoreFlag,
+			TrustStoreSystemKeyword,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateCompareWith(c Config) error {
+	if c.CompareWith == "" {
+		return nil
+	}
+
+	// Comparison is supported for a single service specified by the server
+	// flag.
+	var conflictingFlag string
+	switch {
+	case c.Server == "":
+		return fmt.Errorf(
+			"%q flag requires the %q flag: %w",
+			CompareWithFlag,
+			ServerFlagLong,
+			ErrUnsupportedOption,
+		)
+	case c.TargetsFile != "":
+		conflictingFlag = TargetsFileFlag
+	case len(c.ServerPorts()) > 0:
+		conflictingFlag = PortsFlagLong
+	}
+
+	if conflictingFlag != "" {
+		return fmt.Errorf(
+			"%q flag is not supported with the %q flag: %w",
+			CompareWithFlag,
+			conflictingFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	if _, err := c.CompareWithTarget(); err != nil {
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.CompareWith,
+			CompareWithFlag,
+			err,
+		)
+	}
+
+	return nil
+}
+
+func validateAggregateStrategy(c Config) error {
+	switch c.AggregateStrategy {
+	case "", AggregateStrategyWorst:
+		if c.aggregateWarning != defaultAggregateThreshold || c.aggregateCritical != defaultAggregateThreshold {
+			return fmt.Errorf(
+				"%q and %q flags require the %q or %q value for the %q flag: %w",
+				AggregateWarningFlag,
+				AggregateCriticalFlag,
+				AggregateStrategyPercentage,
+				AggregateStrategyCount,
+				AggregateStrategyFlag,
+				ErrUnsupportedOption,
+			)
+		}
+
+		return nil
+
+	case AggregateStrategyPercentage:
+		for _, threshold := range []int{c.AggregateWarning(), c.AggregateCritical()} {
+			if threshold < 1 || threshold > 100 {
+				return fmt.Errorf(
+					"invalid aggregate threshold percentage %d; expected value between 1 and 100: %w",
+					threshold,
+					ErrUnsupportedOption,
+				)
+			}
+		}
+
+	case AggregateStrategyCount:
+		for _, threshold := range []int{c.AggregateWarning(), c.AggregateCritical()} {
+			if threshold < 1 {
+				return fmt.Errorf(
+					"invalid aggregate threshold count %d; expected value of 1 or greater: %w",
+					threshold,
+					ErrUnsupportedOption,
+				)
+			}
+		}
+
+	default:
+		return fmt.Errorf(
+			"invalid aggregate strategy;"+
+				" got %v, expected one of %v",
+			c.AggregateStrategy,
+			supportedAggregateStrategies(),
+		)
+	}
+
+	if c.AggregateWarning() > c.AggregateCritical() {
+		return fmt.Errorf(
+			"aggregate WARNING threshold %d set higher than CRITICAL threshold %d: %w",
+			c.AggregateWarning(),
+			c.AggregateCritical(),
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateTargetsFile(c Config) error {
+	if c.TargetsFile == "" {
+		return nil
+	}
+
+	// The targets file replaces these flags.
+	var conflictingFlag string
+	switch {
+	case c.InputFilename != "":
+		conflictingFlag = FilenameFlagLong
+	case c.Server != "":
+		conflictingFlag = ServerFlagLong
+	case c.DNSName != "":
+		conflictingFlag = DNSNameFlagLong
+	}
+
+	if conflictingFlag != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported when specifying targets via the %q flag: %w",
+			conflictingFlag,
+			TargetsFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	// These features record or report on a single certificate chain.
+	var singleChainFlag string
+	switch {
+	case c.ExecHook != "":
+		singleChainFlag = ExecHookFlag
+	case c.StateFile != "":
+		singleChainFlag = StateFileFlag
+	}
+
+	if singleChainFlag != "" {
+		return fmt.Errorf(
+			"the %q flag is not supported when specifying targets via the %q flag: %w",
+			singleChainFlag,
+			TargetsFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	fi, err := os.Stat(c.TargetsFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.TargetsFile,
+			TargetsFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.TargetsFile,
+			TargetsFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validatePolicyFile(c Config) error {
+	if c.PolicyFile == "" {
+		return nil
+	}
+
+	fi, err := os.Stat(c.PolicyFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.PolicyFile,
+			PolicyFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.PolicyFile,
+			PolicyFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validatePayloadFormatVersion(c Config) error {
+	// Format version 0 is valid, but anything less than that is not; in order
+	// to have the value set to less than zero someone has to explicitly
+	// choose that value (0 is the default).
+	if c.PayloadFormatVersion < 0 {
+		return fmt.Errorf(
+			"invalid certificate metadata payload format version %d",
+			c.PayloadFormatVersion,
+		)
+	}
+
+	if c.PayloadFormatVersion > payload.MaxPayloadVersion {
+		return fmt.Errorf(
+			"invalid certificate metadata payload format version %d (max is %d): %w",
+			c.PayloadFormatVersion,
+			payload.MaxPayloadVersion,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateIgnoredFingerprints(c Config) error {
+	for _, fingerprint := range c.IgnoredFingerprints() {
+		if !certs.IsValidFingerprint(fingerprint) {
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected SHA-256 fingerprint"+
+					" as hex value with optional colon delimiters: %w",
+				fingerprint,
+				IgnoreFingerprintFlag,
+				ErrUnsupportedOption,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateMaxSeverity(c Config) error {
+	supportedKeywords := supportedValidationCheckResultKeywords()
+	supportedStates := supportedMaxSeverityStates()
+	seen := make(map[string]string, len(c.maxSeverity))
+
+	for _, limit := range c.maxSeverity {
+		keyword, state, found := strings.Cut(limit, "=")
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		state = strings.ToUpper(strings.TrimSpace(state))
+
+		switch {
+		case !found:
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected KEYWORD=STATE format"+
+					" (e.g., hostname=warning): %w",
+				limit,
+				MaxSeverityFlag,
+				ErrUnsupportedOption,
+			)
+
+		case !textutils.InList(keyword, supportedKeywords, true):
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected keyword from %v: %w",
+				limit,
+				MaxSeverityFlag,
+				supportedKeywords,
+				ErrUnsupportedOption,
+			)
+
+		case !textutils.InList(state, supportedStates, true):
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected state from %v: %w",
+				limit,
+				MaxSeverityFlag,
+				supportedStates,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if prev, ok := seen[keyword]; ok && prev != state {
+			return fmt.Errorf(
+				"conflicting values for keyword %s specified via %q flag;"+
+					" got %s and %s: %w",
+				keyword,
+				MaxSeverityFlag,
+				prev,
+				state,
+				ErrUnsupportedOption,
+			)
+		}
+		seen[keyword] = state
+	}
+
+	return nil
+}
+
+func validateStateMappings(c Config) error {
+	supportedStates := nagios.SupportedStateLabels()
+	seen := make(map[string]string, len(c.stateMappings))
+
+	for _, mapping := range c.stateMappings {
+		from, to, found := strings.Cut(mapping, "=")
+		from = strings.ToUpper(strings.TrimSpace(from))
+		to = strings.ToUpper(strings.TrimSpace(to))
+
+		switch {
+		case !found:
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected FROM=TO format"+
+					" (e.g., WARNING=OK): %w",
+				mapping,
+				MapStateFlag,
+				ErrUnsupportedOption,
+			)
+
+		case !textutils.InList(from, supportedStates, true),
+			!textutils.InList(to, supportedStates, true):
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected states from %v: %w",
+				mapping,
+				MapStateFlag,
+				supportedStates,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if prev, ok := seen[from]; ok && prev != to {
+			return fmt.Errorf(
+				"conflicting values for state %s specified via %q flag;"+
+					" got %s and %s: %w",
+				from,
+				MapStateFlag,
+				prev,
+				to,
+				ErrUnsupportedOption,
+			)
+		}
+		seen[from] = to
+	}
+
+	return nil
+}
+
+func validateExecHook(c Config) error {
+	if c.ExecHook == "" {
+		return nil
+	}
+
+	fi, err := os.Stat(c.ExecHook)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.ExecHook,
+			ExecHookFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.ExecHook,
+			ExecHookFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+// validateBackfillDir asserts that the directory of saved plugin output files
+// exists and that no certificate chain source is also specified.
+func validateBackfillDir(c Config) error {
+	if c.InputFilename != "" || c.Server != "" {
+		return fmt.Errorf(
+			"%q flag may not be specified along with %q or %q flags: %w",
+			BackfillDirFlag,
+			ServerFlagLong,
+			FilenameFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	fi, err := os.Stat(c.BackfillDir)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.BackfillDir,
+			BackfillDirFlag,
+			err,
+		)
+
+	case !fi.IsDir():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a directory: %w",
+			c.BackfillDir,
+			BackfillDirFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateTemplateFile(c Config) error {
+	if c.TemplateFile == "" {
+		return nil
+	}
+
+	if c.OutputFormat != OutputFormatText {
+		return fmt.Errorf(
+			"only one of %q flag or %q flag with a value other than %q may be specified: %w",
+			TemplateFileFlag,
+			OutputFormatFlag,
+			OutputFormatText,
+			ErrUnsupportedOption,
+		)
+	}
+
+	fi, err := os.Stat(c.TemplateFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.TemplateFile,
+			TemplateFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.TemplateFile,
+			TemplateFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+// validateDiffSince asserts that the certificate chain snapshot file given
+// for comparison exists. Timestamps are rejected with a specific error as
+// certificate chains are not archived.
+func validateDiffSince(c Config) error {
+	if c.DiffSince == "" {
+		return nil
+	}
+
+	if c.TemplateFile != "" {
+		return fmt.Errorf(
+			"only one of %q or %q flags may be specified: %w",
+			DiffSinceFlag,
+			TemplateFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	fi, err := os.Stat(c.DiffSince)
+	switch {
+	case err != nil && isTimestamp(c.DiffSince):
+		return fmt.Errorf(
+			"invalid value %q for %q flag; timestamps are not supported"+
+				" as certificate chains are not archived, specify a"+
+				" previously recorded snapshot file instead: %w",
+			c.DiffSince,
+			DiffSinceFlag,
+			ErrUnsupportedOption,
+		)
+
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.DiffSince,
+			DiffSinceFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.DiffSince,
+			DiffSinceFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+// validateWatch asserts that watching for certificate chain changes is
+// requested for a certificate chain retrieved from a server and that the
+// optional exec hook invoked when a change is detected exists.
+func validateWatch(c Config) error {
+	if !c.Watch {
+		if c.ExecHook != "" {
+			return fmt.Errorf(
+				"%q flag requires the %q flag: %w",
+				ExecHookFlag,
+				WatchFlag,
+				ErrUnsupportedOption,
+			)
+		}
+
+		return nil
+	}
+
+	switch {
+	case c.Server == "":
+		return fmt.Errorf(
+			"%q flag requires the %q flag or a URL pattern: %w",
+			WatchFlag,
+			ServerFlagLong,
+			ErrUnsupportedOption,
+		)
+
+	case c.TemplateFile != "":
+		return fmt.Errorf(
+			"only one of %q or %q flags may be specified: %w",
+			WatchFlag,
+			TemplateFileFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.watchInterval < time.Second:
+		return fmt.Errorf(
+			"invalid value %s for %q flag; at least 1s is required: %w",
+			c.watchInterval,
+			WatchIntervalFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return validateExecHook(c)
+}
+
+// isTimestamp indicates whether the given value is a date or timestamp in
+// a commonly used layout.
+func isTimestamp(value string) bool {
+	for _, layout := range []string{time.RFC3339, time.DateTime, time.DateOnly} {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateStateFile(c Config) error {
+	if c.StateFile == "" {
+		return nil
+	}
+
+	// The state file itself is created on first use, but the directory
+	// where it is stored is required to already exist.
+	stateDir := filepath.Dir(c.StateFile)
+	fi, err := os.Stat(stateDir)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.StateFile,
+			StateFileFlag,
+			err,
+		)
+
+	case !fi.IsDir():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; %q is not a directory: %w",
+			c.StateFile,
+			StateFileFlag,
+			stateDir,
+			ErrUnsupportedOption,
+		)
+	}
+
+	if fi, err := os.Stat(c.StateFile); err == nil && !fi.Mode().IsRegular() {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.StateFile,
+			StateFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateStatsFile(c Config) error {
+	if c.StatsFile == "" {
+		return nil
+	}
+
+	// The statistics file itself is created on first use, but the directory
+	// where it is stored is required to already exist.
+	statsDir := filepath.Dir(c.StatsFile)
+	fi, err := os.Stat(statsDir)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.StatsFile,
+			StatsFileFlag,
+			err,
+		)
+
+	case !fi.IsDir():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; %q is not a directory: %w",
+			c.StatsFile,
+			StatsFileFlag,
+			statsDir,
+			ErrUnsupportedOption,
+		)
+	}
+
+	if fi, err := os.Stat(c.StatsFile); err == nil && !fi.Mode().IsRegular() {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.StatsFile,
+			StatsFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateHostThrottle(c Config) error {
+	switch {
+	case c.hostDelay < 0:
+		return fmt.Errorf(
+			"invalid value %d for %q flag: %w",
+			c.hostDelay,
+			HostDelayFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.scanJitter < 0:
+		return fmt.Errorf(
+			"invalid value %d for %q flag: %w",
+			c.scanJitter,
+			ScanJitterFlag,
+			ErrUnsupportedOption,
+		)
+
+	// Connection attempts delayed longer than the application inactivity
+	// timeout would result in the scan being terminated.
+	case c.HostDelay()+c.ScanJitter() >= c.TimeoutAppInactivity():
+		return fmt.Errorf(
+			"combined %q flag and %q flag values (%v) must be less than %q flag value (%v): %w",
+			HostDelayFlag,
+			ScanJitterFlag,
+			c.HostDelay()+c.ScanJitter(),
+			AppTimeoutFlagLong,
+			c.TimeoutAppInactivity(),
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateCompareTo(c Config) error {
+	if c.CompareTo == "" {
+		return nil
+	}
+
+	if c.OutputFormat != OutputFormatText {
+		return fmt.Errorf(
+			"only one of %q flag or %q flag with a value other than %q may be specified: %w",
+			CompareToFlag,
+			OutputFormatFlag,
+			OutputFormatText,
+			ErrUnsupportedOption,
+		)
+	}
+
+	fi, err := os.Stat(c.CompareTo)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.CompareTo,
+			CompareToFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.CompareTo,
+			CompareToFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateWebhook(c Config) error {
+	if c.WebhookURL == "" {
+		if c.WebhookAuthHeader != "" {
+			return fmt.Errorf(
+				"%q flag requires the %q flag: %w",
+				WebhookAuthHeaderFlag,
+				WebhookURLFlag,
+				ErrUnsupportedOption,
+			)
+		}
+
+		return nil
+	}
+
+	u, err := url.Parse(c.WebhookURL)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.WebhookURL,
+			WebhookURLFlag,
+			err,
+		)
+
+	case (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		return fmt.Errorf(
+			"invalid value %q for %q flag; an http or https URL is required: %w",
+			c.WebhookURL,
+			WebhookURLFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	if c.WebhookAuthHeader == "" {
+		return nil
+	}
+
+	if _, err := secrets.ParseProvider(c.WebhookAuthHeader); err != nil {
+		return fmt.Errorf(
+			"invalid value for %q flag: %w",
+			WebhookAuthHeaderFlag,
+			err,
+		)
+	}
+
+	if c.WebhookAuthHeaderName == "" || strings.ContainsAny(c.WebhookAuthHeaderName, " \t\r\n:") {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; a valid header name is required: %w",
+			c.WebhookAuthHeaderName,
+			WebhookAuthHeaderNameFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+// validateStatsSummary asserts that the run statistics file to summarize
+// exists and that flags for retrieving a certificate chain were not also
+// specified.
+func validateStatsSummary(c Config) error {
+	if c.InputFilename != "" || c.Server != "" {
+		return fmt.Errorf(
+			"%q flag may not be specified along with %q or %q flags: %w",
+			StatsSummaryFlag,
+			ServerFlagLong,
+			FilenameFlagLong,
+			ErrUnsupportedOption,
+		)
+	}
+
+	fi, err := os.Stat(c.StatsSummary)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.StatsSummary,
+			StatsSummaryFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.StatsSummary,
+			StatsSummaryFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateBlocklistFile(c Config) error {
+	if textutils.InList(ValidationKeywordBlocklist, c.applyValidationResults, true) &&
+		c.BlocklistFile == "" {
+		return fmt.Errorf(
+			"unsupported setting for certificate blocklist validation;"+
+				" providing a blocklist file via the %q flag is required"+
+				" when specifying the %q keyword via the %q flag",
+			BlocklistFileFlag,
+			ValidationKeywordBlocklist,
+			ApplyValidationResultFlag,
+		)
+	}
+
+	if c.BlocklistFile == "" {
+		return nil
+	}
+
+	fi, err := os.Stat(c.BlocklistFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.BlocklistFile,
+			BlocklistFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.BlocklistFile,
+			BlocklistFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateDistrustedCAsFile(c Config) error {
+	if c.DistrustedCAsFile == "" {
+		return nil
+	}
+
+	fi, err := os.Stat(c.DistrustedCAsFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.DistrustedCAsFile,
+			DistrustedCAsFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.DistrustedCAsFile,
+			DistrustedCAsFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateMinTLSVersion(c Config) error {
+	// The default minimum version is used if not specified.
+	if c.minTLSVersion == "" {
+		return nil
+	}
+
+	if _, err := netutils.ParseTLSVersion(c.minTLSVersion); err != nil {
+		return fmt.Errorf(
+			"invalid value %q for %q flag; supported values: %v: %w",
+			c.minTLSVersion,
+			MinTLSVersionFlag,
+			netutils.SupportedTLSVersionKeywords(),
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateBundleFiles(c Config) error {
+	switch {
+	case c.ExportBundle != "" && c.ImportBundle != "":
+		return fmt.Errorf(
+			"unsupported setting for scan results bundle;"+
+				" only one of %q or %q flags may be specified: %w",
+			ExportBundleFlag,
+			ImportBundleFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.ImportBundle != "" && c.StateFile != "":
+		return fmt.Errorf(
+			"unsupported setting for scan results bundle;"+
+				" a scan is not performed when importing a bundle via the %q flag"+
+				" so the %q flag is not used: %w",
+			ImportBundleFlag,
+			StateFileFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.ImportBundle != "" && (len(c.hosts.givenValues) > 0 || c.HostsFile != ""):
+		return fmt.Errorf(
+			"unsupported setting for scan results bundle;"+
+				" host values are not used when importing a bundle via the %q flag: %w",
+			ImportBundleFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.ImportBundle != "":
+		fi, err := os.Stat(c.ImportBundle)
+		switch {
+		case err != nil:
+			return fmt.Errorf(
+				"invalid value %q for %q flag: %w",
+				c.ImportBundle,
+				ImportBundleFlag,
+				err,
+			)
+
+		case !fi.Mode().IsRegular():
+			return fmt.Errorf(
+				"invalid value %q for %q flag; not a regular file: %w",
+				c.ImportBundle,
+				ImportBundleFlag,
+				ErrUnsupportedOption,
+			)
+		}
+
+	case c.ExportBundle != "":
+		outputDir := filepath.Dir(c.ExportBundle)
+		info, err := os.Stat(outputDir)
+		switch {
+		case err != nil:
+			return fmt.Errorf(
+				"invalid value %q for %q flag: %w",
+				c.ExportBundle,
+				ExportBundleFlag,
+				err,
+			)
+
+		case !info.IsDir():
+			return fmt.Errorf(
+				"invalid value %q for %q flag; %q is not a directory: %w",
+				c.ExportBundle,
+				ExportBundleFlag,
+				outputDir,
+				ErrUnsupportedOption,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateCTSearchURL(c Config) error {
+	if c.CTSearchURL == "" {
+		if textutils.InList(ValidationKeywordCTLogs, c.applyValidationResults, true) {
+			return fmt.Errorf(
+				"unsupported setting for CT logs validation;"+
+					" providing a CT search URL via the %q flag is required"+
+					" when specifying the %q keyword via the %q flag",
+				CTSearchURLFlag,
+				ValidationKeywordCTLogs,
+				ApplyValidationResultFlag,
+			)
+		}
+
+		return nil
+	}
+
+	u, err := url.Parse(c.CTSearchURL)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.CTSearchURL,
+			CTSearchURLFlag,
+			err,
+		)
+
+	case (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		return fmt.Errorf(
+			"invalid value %q for %q flag; an http or https URL is required: %w",
+			c.CTSearchURL,
+			CTSearchURLFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateACMEDirectoryURL(c Config) error {
+	if c.ACMEDirectoryURL == "" {
+		if textutils.InList(ValidationKeywordRenewalInfo, c.applyValidationResults, true) {
+			return fmt.Errorf(
+				"unsupported setting for renewal info validation;"+
+					" providing an ACME directory URL via the %q flag is required"+
+					" when specifying the %q keyword via the %q flag",
+				ACMEDirectoryURLFlag,
+				ValidationKeywordRenewalInfo,
+				ApplyValidationResultFlag,
+			)
+		}
+
+		return nil
+	}
+
+	u, err := url.Parse(c.ACMEDirectoryURL)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.ACMEDirectoryURL,
+			ACMEDirectoryURLFlag,
+			err,
+		)
+
+	case (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		return fmt.Errorf(
+			"invalid value %q for %q flag; an http or https URL is required: %w",
+			c.ACMEDirectoryURL,
+			ACMEDirectoryURLFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateCTSearchToken(c Config) error {
+	switch {
+	case c.CTSearchToken != "" && c.CTSearchTokenCmd != "":
+		return fmt.Errorf(
+			"unsupported setting for CT search API token;"+
+				" only one of %q or %q flags may be specified: %w",
+			CTSearchTokenFlag,
+			CTSearchTokenCmdFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.CTSearchTokenCmd != "" && strings.TrimSpace(c.CTSearchTokenCmd) == "":
+		return fmt.Errorf(
+			"invalid value for %q flag; a command is required: %w",
+			CTSearchTokenCmdFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.CTSearchToken != "":
+		if _, err := secrets.ParseProvider(c.CTSearchToken); err != nil {
+			return fmt.Errorf(
+				"invalid value for %q flag: %w",
+				CTSearchTokenFlag,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateKeyFile(c Config) error {
+	switch {
+	case c.P12Password != "" && c.KeyFile == "":
+		return fmt.Errorf(
+			"%q flag requires the %q flag: %w",
+			P12PasswordFlag,
+			KeyFileFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.KeyFile == "":
+		return nil
+	}
+
+	fi, err := os.Stat(c.KeyFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.KeyFile,
+			KeyFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; not a regular file: %w",
+			c.KeyFile,
+			KeyFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	if c.P12Password != "" {
+		if _, err := secrets.ParseProvider(c.P12Password); err != nil {
+			return fmt.Errorf(
+				"invalid value for %q flag: %w",
+				P12PasswordFlag,
+				err,
+			)
+		}
+	}
+
+	return nil
+}
+
+func validateHostsFile(c Config) error {
+	if c.HostsFile == "" {
+		return nil
+	}
+
+	fi, err := os.Stat(c.HostsFile)
+	switch {
+	case err != nil:
+		return fmt.Errorf(
+			"invalid value %q for %q flag: %w",
+			c.HostsFile,
+			HostsFileFlag,
+			err,
+		)
+
+	case !fi.Mode().IsRegular():
+		return fmt.Errorf(
+			"invalid value %q for %q flag; path is not a regular file: %w",
+			c.HostsFile,
+			HostsFileFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateProfileFiles(c Config) error {
+	profiles := []struct {
+		filename string
+		flagName string
+	}{
+		{filename: c.ProfileCPU, flagName: ProfileCPUFlag},
+		{filename: c.ProfileMem, flagName: ProfileMemFlag},
+	}
+
+	for _, profile := range profiles {
+		if profile.filename == "" {
+			continue
+		}
+
+		// The profile is created when written, but the directory where it
+		// is stored is required to already exist.
+		profileDir := filepath.Dir(profile.filename)
+		fi, err := os.Stat(profileDir)
+		switch {
+		case err != nil:
+			return fmt.Errorf(
+				"invalid value %q for %q flag: %w",
+				profile.filename,
+				profile.flagName,
+				err,
+			)
+
+		case !fi.IsDir():
+			return fmt.Errorf(
+				"invalid value %q for %q flag; %q is not a directory: %w",
+				profile.filename,
+				profile.flagName,
+				profileDir,
+				ErrUnsupportedOption,
+			)
+		}
+	}
+
+	if c.ProfileCPU != "" && filepath.Clean(c.ProfileCPU) == filepath.Clean(c.ProfileMem) {
+		return fmt.Errorf(
+			"%q and %q flags cannot specify the same file: %w",
+			ProfileCPUFlag,
+			ProfileMemFlag,
+			ErrUnsupportedOption,
+		)
+	}
+
+	return nil
+}
+
+func validateNetworkBudget(c Config) error {
+	switch {
+	case c.MaxExternalRequests < 0:
+		return fmt.Errorf(
+			"invalid value %d for %q flag; a non-negative value is required: %w",
+			c.MaxExternalRequests,
+			MaxExternalRequestsFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.MaxDownloadBytes < 0:
+		return fmt.Errorf(
+			"invalid value %d for %q flag; a non-negative value is required: %w",
+			c.MaxDownloadBytes,
+			MaxDownloadBytesFlag,
+			ErrUnsupportedOption,
 		)
 	}
 
 	return nil
 }
 
-func validatePayloadFormatVersion(c Config) error {
-	// Format version 0 is valid, but anything less than that is not; in order
-	// to have the value set to less than zero someone has to explicitly
-	// choose that value (0 is the default).
-	if c.PayloadFormatVersion < 0 {
+func validateExpiryCliff(c Config) error {
+	switch {
+	case c.expiryCliffWindow < 0:
 		return fmt.Errorf(
-			"invalid certificate metadata payload format version %d",
-			c.PayloadFormatVersion,
+			"invalid value %d for %q flag; a non-negative value is required: %w",
+			c.expiryCliffWindow,
+			ExpiryCliffWindowFlag,
+			ErrUnsupportedOption,
+		)
+
+	case c.expiryCliffLeadTime < 0:
+		return fmt.Errorf(
+			"invalid value %d for %q flag; a non-negative value is required: %w",
+			c.expiryCliffLeadTime,
+			ExpiryCliffLeadTimeFlag,
+			ErrUnsupportedOption,
 		)
 	}
 
@@ -81,6 +1865,14 @@ func (c Config) validate(appType AppType) error {
 
 	switch {
 	case appType.Inspector:
+		if c.BackfillDir != "" {
+			return validateBackfillDir(c)
+		}
+
+		if c.StatsSummary != "" {
+			return validateStatsSummary(c)
+		}
+
 		switch {
 		case c.InputFilename == "" && c.Server == "":
 			return fmt.Errorf(
@@ -106,6 +1898,28 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		supportedOutputFormats := supportedInspectorOutputFormats()
+		if !textutils.InList(c.OutputFormat, supportedOutputFormats, false) {
+			return fmt.Errorf(
+				"invalid output format;"+
+					" got %v, expected one of %v",
+				c.OutputFormat,
+				supportedOutputFormats,
+			)
+		}
+
+		if err := validateTemplateFile(c); err != nil {
+			return err
+		}
+
+		if err := validateDiffSince(c); err != nil {
+			return err
+		}
+
+		if err := validateWatch(c); err != nil {
+			return err
+		}
+
 	case appType.Copier:
 
 		// User can specify one of input filename or server, but not both.
@@ -128,6 +1942,10 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateKeyFile(c); err != nil {
+			return err
+		}
+
 		// Assert that only supported keywords are specified.
 		supportedCertTypeFilterKeywords := supportedCertTypeFilterKeywords()
 		for _, specifiedKeyword := range c.certTypesToKeep {
@@ -170,11 +1988,14 @@ func (c Config) validate(appType AppType) error {
 
 	case appType.Plugin:
 		switch {
-		case c.InputFilename == "" && c.Server == "":
+		case c.InputFilename == "" && c.Server == "" && c.TargetsFile == "" && c.Socket == "" && c.TrustStore == "":
 			return fmt.Errorf(
-				"one of %q or %q flags must be specified",
+				"one of %q, %q, %q, %q or %q flags must be specified",
 				ServerFlagLong,
 				FilenameFlagLong,
+				TargetsFileFlag,
+				SocketFlag,
+				TrustStoreFlag,
 			)
 		case c.InputFilename != "" && c.Server != "":
 			return fmt.Errorf(
@@ -195,80 +2016,168 @@ func (c Config) validate(appType AppType) error {
 			return err
 		}
 
+		if err := validateServerPorts(c); err != nil {
+			return err
+		}
+
+		if err := validateTargetsFile(c); err != nil {
+			return err
+		}
+
+		if err := validateSocket(c); err != nil {
+			return err
+		}
+
+		if err := validateTrustStore(c); err != nil {
+			return err
+		}
+
+		if err := validateAggregateStrategy(c); err != nil {
+			return err
+		}
+
+		if err := validateResolverDNSSECMode(c); err != nil {
+			return err
+		}
+
+		if err := validateSimulateState(c); err != nil {
+			return err
+		}
+
+		if err := validateCompareWith(c); err != nil {
+			return err
+		}
+
+		if err := validatePolicyFile(c); err != nil {
+			return err
+		}
+
 		if err := validatePayloadFormatVersion(c); err != nil {
 			return err
 		}
 
-		supportedValidationKeywords := supportedValidationCheckResultKeywords()
+		if err := validateValidationResultKeywords(c); err != nil {
+			return err
+		}
 
-		// Validate the specified explicit "ignore" validation check results
-		// keywords
-		for _, specifiedKeyword := range c.ignoreValidationResults {
-			if !textutils.InList(specifiedKeyword, supportedValidationKeywords, true) {
-				return fmt.Errorf(
-					"invalid ignore validation results keyword specified; got %v, expected one of %v",
-					specifiedKeyword,
-					supportedValidationKeywords,
-				)
-			}
+		if c.ExpectedSerial != "" && !certs.IsValidSerialNumber(c.ExpectedSerial) {
+			return fmt.Errorf(
+				"invalid value %q for %q flag; expected hex value"+
+					" (e.g., 0A:FD:50:2B or 0afd502b): %w",
+				c.ExpectedSerial,
+				ExpectedSerialFlag,
+				ErrUnsupportedOption,
+			)
 		}
 
-		// Validate the specified explicit "apply" validation check results
-		// keywords
-		for _, specifiedKeyword := range c.applyValidationResults {
-			if !textutils.InList(specifiedKeyword, supportedValidationKeywords, true) {
+		for _, serial := range c.IgnoredSerials() {
+			if !certs.IsValidSerialNumber(serial) {
 				return fmt.Errorf(
-					"invalid apply validation results keyword specified; got %v, expected one of %v",
-					specifiedKeyword,
-					supportedValidationKeywords,
+					"invalid value %q for %q flag; expected hex value"+
+						" (e.g., 0A:FD:50:2B or 0afd502b): %w",
+					serial,
+					IgnoreSerialFlag,
+					ErrUnsupportedOption,
 				)
 			}
 		}
 
-		// If we have explicit apply AND explicit ignore keywords ...
-		if len(c.applyValidationResults) > 0 && len(c.ignoreValidationResults) > 0 {
-
-			// Assert that the same keyword is not present in both explicit
-			// apply and explicit ignore flag values.
-			for _, keyword := range supportedValidationKeywords {
-				if textutils.InList(keyword, c.applyValidationResults, true) &&
-					textutils.InList(keyword, c.ignoreValidationResults, true) {
-					return fmt.Errorf(
-						"specified validation keyword %q was specified as"+
-							" value for multiple flags;"+
-							" keyword may be used with only one of %q or %q"+
-							" flags",
-						keyword,
-						IgnoreValidationResultFlag,
-						ApplyValidationResultFlag,
-					)
-				}
+		supportedEKUs := certs.SupportedExtKeyUsageKeywords()
+		for _, eku := range append(c.RequiredEKUs(), c.DisallowedEKUs()...) {
+			if _, ok := certs.ParseExtKeyUsageKeyword(eku); !ok {
+				return fmt.Errorf(
+					"invalid Extended Key Usage keyword specified; got %q, expected one of %v: %w",
+					eku,
+					supportedEKUs,
+					ErrUnsupportedOption,
+				)
 			}
 		}
 
-		// If the sysadmin explicitly requested that SANs list validation
-		// check results be applied, but did not provide a SANs entries list
-		// to use for validation we can't perform SANs list validation.
-		//
-		// The default behavior is to perform SANs list validation *if* a list
-		// of SANs entries to validate is provided.
-		if textutils.InList(ValidationKeywordSANsList, c.applyValidationResults, true) {
-			if len(c.SANsEntries) == 0 {
+		for _, eku := range c.RequiredEKUs() {
+			if textutils.InList(eku, c.DisallowedEKUs(), true) {
 				return fmt.Errorf(
-					"unsupported setting for certificate SANs list validation;"+
-						" providing SANs entries via the %q flag is required"+
-						" when specifying the %q keyword via the %q flag",
-					SANsEntriesFlagLong,
-					ValidationKeywordSANsList,
-					ApplyValidationResultFlag,
+					"specified Extended Key Usage keyword %q was provided for both %q and %q flags: %w",
+					eku,
+					RequiredEKUsFlag,
+					DisallowedEKUsFlag,
+					ErrUnsupportedOption,
 				)
 			}
 		}
 
+		if err := validateStateMappings(c); err != nil {
+			return err
+		}
+
+		if err := validateMaxSeverity(c); err != nil {
+			return err
+		}
+
+		if err := validateIgnoredFingerprints(c); err != nil {
+			return err
+		}
+
+		if err := validateExecHook(c); err != nil {
+			return err
+		}
+
+		if err := validateBlocklistFile(c); err != nil {
+			return err
+		}
+
+		if err := validateDistrustedCAsFile(c); err != nil {
+			return err
+		}
+
+		if err := validateStateFile(c); err != nil {
+			return err
+		}
+
+		if err := validateStatsFile(c); err != nil {
+			return err
+		}
+
+		if err := validateCTSearchURL(c); err != nil {
+			return err
+		}
+
+		if err := validateCTSearchToken(c); err != nil {
+			return err
+		}
+
+		if err := validateACMEDirectoryURL(c); err != nil {
+			return err
+		}
+
+		if err := validateMinTLSVersion(c); err != nil {
+			return err
+		}
+
+		if err := validateNetworkBudget(c); err != nil {
+			return err
+		}
+
 		if err := validateAgeThresholds(c); err != nil {
 			return err
 		}
 
+		if err := validateAgePercentThresholds(c); err != nil {
+			return err
+		}
+
+		if err := validateChainPositionAgeThresholds(c); err != nil {
+			return err
+		}
+
+		if err := validateExpiryCliff(c); err != nil {
+			return err
+		}
+
+		if err := validatePluginTimeout(c); err != nil {
+			return err
+		}
+
 	case appType.Scanner:
 
 		// Use getter method in order to validate final ports list. Because we
@@ -298,8 +2207,10 @@ func (c Config) validate(appType AppType) error {
 			)
 		}
 
+		// A zero value indicates that the scan rate limit is calculated
+		// automatically.
 		switch {
-		case c.ScanRateLimit < 1:
+		case c.ScanRateLimit < 0:
 			return fmt.Errorf(
 				"invalid scan rate limit value provided: %d",
 				c.ScanRateLimit,
@@ -314,29 +2225,209 @@ func (c Config) validate(appType AppType) error {
 			)
 		}
 
-		if c.ScanRateLimit < 1 {
+		if err := validateHostThrottle(c); err != nil {
+			return err
+		}
+
+		if c.progressInterval < 0 {
 			return fmt.Errorf(
-				"invalid port scan rate limit value provided: %d",
-				c.ScanRateLimit,
+				"invalid value %d for %q flag: %w",
+				c.progressInterval,
+				ProgressIntervalFlag,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if err := validateBundleFiles(c); err != nil {
+			return err
+		}
+
+		if err := validateHostsFile(c); err != nil {
+			return err
+		}
+
+		if err := validateStateFile(c); err != nil {
+			return err
+		}
+
+		if err := validateStatsFile(c); err != nil {
+			return err
+		}
+
+		if err := validateCompareTo(c); err != nil {
+			return err
+		}
+
+		if err := validateWebhook(c); err != nil {
+			return err
+		}
+
+		supportedOutputFormats := supportedOutputFormats()
+		if !textutils.InList(c.OutputFormat, supportedOutputFormats, false) {
+			return fmt.Errorf(
+				"invalid output format;"+
+					" got %v, expected one of %v",
+				c.OutputFormat,
+				supportedOutputFormats,
 			)
 		}
 
-		if c.Hosts() == nil {
-			return fmt.Errorf("host values (one or many, single or IP Address ranges) not provided")
+		if c.OutputFile != "" {
+			outputDir := filepath.Dir(c.OutputFile)
+			info, err := os.Stat(outputDir)
+			switch {
+			case err != nil:
+				return fmt.Errorf(
+					"invalid output file %q: %w",
+					c.OutputFile,
+					err,
+				)
+			case !info.IsDir():
+				return fmt.Errorf(
+					"invalid output file %q; %q is not a directory",
+					c.OutputFile,
+					outputDir,
+				)
+			}
 		}
 
 		if err := validateAgeThresholds(c); err != nil {
 			return err
 		}
 
+		if err := validateExpiryCliff(c); err != nil {
+			return err
+		}
+
+		if err := validateIgnoredFingerprints(c); err != nil {
+			return err
+		}
+
+		if err := validateProfileFiles(c); err != nil {
+			return err
+		}
+
 		// TODO: Figure out how to (or if we need to) validate mix of boolean
 		// value "show" flags
+
+	case appType.Exporter:
+
+		if len(c.hosts.givenValues) == 0 {
+			return fmt.Errorf(
+				"host values (one or many, single or IP Address ranges) not provided via %q flag",
+				HostsFlagLong,
+			)
+		}
+
+		switch {
+		case c.ScanRateLimit < 1:
+			return fmt.Errorf(
+				"invalid scan rate limit value provided: %d",
+				c.ScanRateLimit,
+			)
+
+		case c.ScanRateLimit >= 10000:
+			return fmt.Errorf(
+				"unreliable value provided; too high values result in 'too many open files' OS errors: %d",
+				c.ScanRateLimit,
+			)
+		}
+
+		if _, _, err := net.SplitHostPort(c.ListenAddress); err != nil {
+			return fmt.Errorf(
+				"invalid value %q for %q flag: %w",
+				c.ListenAddress,
+				ListenAddressFlag,
+				err,
+			)
+		}
+
+		if err := validateAgeThresholds(c); err != nil {
+			return err
+		}
+
+		if err := validateValidationResultKeywords(c); err != nil {
+			return err
+		}
+
+		if err := validateServiceValidationResultKeywords(c); err != nil {
+			return err
+		}
+
+		if err := validateProfileFiles(c); err != nil {
+			return err
+		}
+
+	case appType.Monitor:
+
+		if len(c.hosts.givenValues) == 0 {
+			return fmt.Errorf(
+				"host values (one or many, single or IP Address ranges) not provided via %q flag",
+				HostsFlagLong,
+			)
+		}
+
+		switch {
+		case c.ScanRateLimit < 1:
+			return fmt.Errorf(
+				"invalid scan rate limit value provided: %d",
+				c.ScanRateLimit,
+			)
+
+		case c.ScanRateLimit >= 10000:
+			return fmt.Errorf(
+				"unreliable value provided; too high values result in 'too many open files' OS errors: %d",
+				c.ScanRateLimit,
+			)
+		}
+
+		if _, _, err := net.SplitHostPort(c.ListenAddress); err != nil {
+			return fmt.Errorf(
+				"invalid value %q for %q flag: %w",
+				c.ListenAddress,
+				ListenAddressFlag,
+				err,
+			)
+		}
+
+		if c.checkInterval < 1 {
+			return fmt.Errorf(
+				"invalid value %d for %q flag; at least 1 minute is required: %w",
+				c.checkInterval,
+				CheckIntervalFlag,
+				ErrUnsupportedOption,
+			)
+		}
+
+		if err := validateAgeThresholds(c); err != nil {
+			return err
+		}
+
+		if err := validateValidationResultKeywords(c); err != nil {
+			return err
+		}
+
+		if err := validateServiceValidationResultKeywords(c); err != nil {
+			return err
+		}
 	}
 
 	if c.Timeout() < 0 {
 		return fmt.Errorf("invalid timeout value %d provided", c.Timeout())
 	}
 
+	if err := validatePhaseTimeouts(c); err != nil {
+		return err
+	}
+
+	if err := validateDNSServer(c); err != nil {
+		return err
+	}
+
+	if err := validateRetries(c); err != nil {
+		return err
+	}
+
 	// Validate the specified logging level
 	supportedLogLevels := supportedLogLevels()
 	if !textutils.InList(c.LoggingLevel, supportedLogLevels, true) {
@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package exechook runs sysadmin-specified executables (e.g., scripts) in
+// response to application events, providing event details via stdin and
+// environment variables.
+package exechook
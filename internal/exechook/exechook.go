@@ -0,0 +1,93 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package exechook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Environment variables provided to the exec hook in addition to those
+// inherited from the application. Not all variables are provided by every
+// application.
+const (
+	// EnvServer is the server value (FQDN or IP Address) used to retrieve
+	// the certificate chain.
+	EnvServer string = "CHECK_CERT_SERVER"
+
+	// EnvPort is the TCP port used to retrieve the certificate chain.
+	EnvPort string = "CHECK_CERT_PORT"
+
+	// EnvFilename is the certificate file evaluated instead of retrieving a
+	// certificate chain.
+	EnvFilename string = "CHECK_CERT_FILENAME"
+
+	// EnvServiceState is the final service check state label.
+	EnvServiceState string = "CHECK_CERT_SERVICE_STATE"
+
+	// EnvExitCode is the final service check exit code.
+	EnvExitCode string = "CHECK_CERT_EXIT_CODE"
+
+	// EnvPreviousFingerprint is the SHA-256 fingerprint of the leaf
+	// certificate before a certificate chain change was detected.
+	EnvPreviousFingerprint string = "CHECK_CERT_PREVIOUS_FINGERPRINT"
+
+	// EnvCurrentFingerprint is the SHA-256 fingerprint of the leaf
+	// certificate after a certificate chain change was detected.
+	EnvCurrentFingerprint string = "CHECK_CERT_CURRENT_FINGERPRINT"
+)
+
+// ErrExecHookFailed indicates that the exec hook could not be run or did not
+// complete successfully.
+var ErrExecHookFailed = errors.New("exec hook failed")
+
+// Var returns an environment variable entry in the "key=value" format.
+func Var(key string, value string) string {
+	return key + "=" + value
+}
+
+// Run invokes the executable at the given path with the given input provided
+// via stdin and the given environment variables (see Var) provided in
+// addition to those inherited from the application. The executable is
+// stopped if it does not complete within the given timeout.
+//
+// Output from the executable is logged and not returned.
+func Run(path string, input []byte, env []string, timeout time.Duration, log zerolog.Logger) error {
+	log = log.With().Str("exec_hook", path).Logger()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// The path is provided by the sysadmin and validated as a regular file
+	// during config initialization.
+	cmd := exec.CommandContext(ctx, path) // nolint:gosec
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), env...)
+
+	log.Debug().Msg("Running exec hook")
+
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		log.Debug().Str("output", string(output)).Msg("Exec hook output")
+	}
+
+	if err != nil {
+		return fmt.Errorf("%q: %w: %w", path, ErrExecHookFailed, err)
+	}
+
+	log.Debug().Msg("Exec hook completed")
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package exechook
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newTestScript writes a shell script with the given body to a temporary
+// directory and returns the path to the script.
+func newTestScript(t *testing.T, body string) string {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on Windows")
+	}
+
+	script := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"+body+"\n"), 0o700); err != nil { // nolint:gosec
+		t.Fatalf("failed to write exec hook script: %v", err)
+	}
+
+	return script
+}
+
+func TestRun(t *testing.T) {
+	t.Run("InputAndEnvironment", func(t *testing.T) {
+		outputFile := filepath.Join(t.TempDir(), "output.txt")
+		script := newTestScript(t,
+			`cat > "`+outputFile+`"; echo "$`+EnvServer+`:$`+EnvPort+`" >> "`+outputFile+`"`,
+		)
+
+		env := []string{
+			Var(EnvServer, "www.example.com"),
+			Var(EnvPort, "443"),
+		}
+
+		if err := Run(script, []byte("payload\n"), env, 5*time.Second, zerolog.Nop()); err != nil {
+			t.Fatalf("want no error; got %v", err)
+		}
+
+		got, err := os.ReadFile(outputFile) // nolint:gosec
+		if err != nil {
+			t.Fatalf("failed to read exec hook output: %v", err)
+		}
+
+		want := "payload\nwww.example.com:443\n"
+		if string(got) != want {
+			t.Errorf("want %q; got %q", want, string(got))
+		}
+	})
+
+	t.Run("NonZeroExitStatus", func(t *testing.T) {
+		script := newTestScript(t, "echo failure; exit 3")
+
+		err := Run(script, nil, nil, 5*time.Second, zerolog.Nop())
+		if !errors.Is(err, ErrExecHookFailed) || !strings.Contains(err.Error(), "exit status 3") {
+			t.Errorf("want error %v with exit status 3; got %v", ErrExecHookFailed, err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		script := newTestScript(t, "exec sleep 5")
+
+		start := time.Now()
+		err := Run(script, nil, nil, 100*time.Millisecond, zerolog.Nop())
+		if !errors.Is(err, ErrExecHookFailed) {
+			t.Errorf("want error %v; got %v", ErrExecHookFailed, err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 4*time.Second {
+			t.Errorf("want exec hook stopped after timeout; ran for %v", elapsed)
+		}
+	})
+
+	t.Run("MissingExecutable", func(t *testing.T) {
+		err := Run(filepath.Join(t.TempDir(), "missing.sh"), nil, nil, time.Second, zerolog.Nop())
+		if !errors.Is(err, ErrExecHookFailed) {
+			t.Errorf("want error %v; got %v", ErrExecHookFailed, err)
+		}
+	})
+}
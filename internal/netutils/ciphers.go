@@ -0,0 +1,407 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrUnexpectedServerHello indicates that a server responded to a probe
+// with a message which could not be parsed as a TLS ServerHello message.
+var ErrUnexpectedServerHello = errors.New("unexpected response to TLS ClientHello")
+
+// Reasons that a cipher suite is considered weak.
+const (
+	WeakCipherReasonRC4          string = "RC4"
+	WeakCipherReason3DES         string = "3DES"
+	WeakCipherReasonExport       string = "export"
+	WeakCipherReasonCBCWithTLS10 string = "CBC with TLS 1.0"
+)
+
+// WeakCipherSuite is a known-weak cipher suite along with the reason it is
+// considered weak.
+type WeakCipherSuite struct {
+	// ID is the IANA assigned cipher suite ID.
+	ID uint16
+
+	// Name is the IANA assigned cipher suite name.
+	Name string
+
+	// Reason is the reason the cipher suite is considered weak (e.g., RC4).
+	Reason string
+
+	// version is the TLS protocol version offered when probing for
+	// acceptance of the cipher suite.
+	version uint16
+}
+
+// String provides the cipher suite name along with the reason it is
+// considered weak.
+func (wcs WeakCipherSuite) String() string {
+	return fmt.Sprintf("%s (%s)", wcs.Name, wcs.Reason)
+}
+
+// weakCipherSuites is the collection of known-weak cipher suites probed for.
+// RC4 and 3DES cipher suites are offered with TLS 1.2 so that servers
+// negotiate their preferred protocol version. Export cipher suites are
+// prohibited by TLS 1.1 and newer and CBC cipher suites are only considered
+// weak when used with TLS 1.0, so both are offered with TLS 1.0.
+//
+// Most of these cipher suites are not implemented by the crypto/tls package,
+// so probes use a minimal ClientHello message and only evaluate the cipher
+// suite chosen by the server in the ServerHello response.
+var weakCipherSuites = []WeakCipherSuite{
+	{ID: 0x0004, Name: "TLS_RSA_WITH_RC4_128_MD5", Reason: WeakCipherReasonRC4, version: tls.VersionTLS12},
+	{ID: 0x0005, Name: "TLS_RSA_WITH_RC4_128_SHA", Reason: WeakCipherReasonRC4, version: tls.VersionTLS12},
+	{ID: 0xC007, Name: "TLS_ECDHE_ECDSA_WITH_RC4_128_SHA", Reason: WeakCipherReasonRC4, version: tls.VersionTLS12},
+	{ID: 0xC011, Name: "TLS_ECDHE_RSA_WITH_RC4_128_SHA", Reason: WeakCipherReasonRC4, version: tls.VersionTLS12},
+	{ID: 0x000A, Name: "TLS_RSA_WITH_3DES_EDE_CBC_SHA", Reason: WeakCipherReason3DES, version: tls.VersionTLS12},
+	{ID: 0x0016, Name: "TLS_DHE_RSA_WITH_3DES_EDE_CBC_SHA", Reason: WeakCipherReason3DES, version: tls.VersionTLS12},
+	{ID: 0xC008, Name: "TLS_ECDHE_ECDSA_WITH_3DES_EDE_CBC_SHA", Reason: WeakCipherReason3DES, version: tls.VersionTLS12},
+	{ID: 0xC012, Name: "TLS_ECDHE_RSA_WITH_3DES_EDE_CBC_SHA", Reason: WeakCipherReason3DES, version: tls.VersionTLS12},
+	{ID: 0x0003, Name: "TLS_RSA_EXPORT_WITH_RC4_40_MD5", Reason: WeakCipherReasonExport, version: tls.VersionTLS10},
+	{ID: 0x0006, Name: "TLS_RSA_EXPORT_WITH_RC2_CBC_40_MD5", Reason: WeakCipherReasonExport, version: tls.VersionTLS10},
+	{ID: 0x0008, Name: "TLS_RSA_EXPORT_WITH_DES40_CBC_SHA", Reason: WeakCipherReasonExport, version: tls.VersionTLS10},
+	{ID: 0x0011, Name: "TLS_DHE_DSS_EXPORT_WITH_DES40_CBC_SHA", Reason: WeakCipherReasonExport, version: tls.VersionTLS10},
+	{ID: 0x0014, Name: "TLS_DHE_RSA_EXPORT_WITH_DES40_CBC_SHA", Reason: WeakCipherReasonExport, version: tls.VersionTLS10},
+	{ID: 0x002F, Name: "TLS_RSA_WITH_AES_128_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0x0035, Name: "TLS_RSA_WITH_AES_256_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0x0033, Name: "TLS_DHE_RSA_WITH_AES_128_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0x0039, Name: "TLS_DHE_RSA_WITH_AES_256_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0xC009, Name: "TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0xC00A, Name: "TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0xC013, Name: "TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+	{ID: 0xC014, Name: "TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA", Reason: WeakCipherReasonCBCWithTLS10, version: tls.VersionTLS10},
+}
+
+// WeakCipherSuites returns the collection of known-weak cipher suites
+// evaluated by ProbeWeakCipherSuites.
+func WeakCipherSuites() []WeakCipherSuite {
+	suites := make([]WeakCipherSuite, len(weakCipherSuites))
+	copy(suites, weakCipherSuites)
+
+	return suites
+}
+
+// ProbeWeakCipherSuites identifies the known-weak cipher suites accepted by
+// the server at the specified IP Address & port.
+//
+// The weak cipher suites offered with the same TLS protocol version are
+// offered together; each cipher suite chosen by the server is recorded and
+// removed from the next offer until the server rejects the remaining cipher
+// suites. A rejected offer is not treated as an error. An error is returned
+// if a connection to the server cannot be established or the server response
+// cannot be parsed.
+func ProbeWeakCipherSuites(
+	host string,
+	ipAddr string,
+	port int,
+	timeout time.Duration,
+	opts CertRetrievalOptions,
+	logger zerolog.Logger,
+) ([]WeakCipherSuite, error) {
+
+	if strings.TrimSpace(ipAddr) == "" {
+		return nil, fmt.Errorf(
+			"target IP Address not specified: %w",
+			ErrMissingValue,
+		)
+	}
+
+	host = strings.TrimSpace(host)
+
+	var accepted []WeakCipherSuite
+
+	for _, version := range []uint16{tls.VersionTLS12, tls.VersionTLS10} {
+		var remaining []WeakCipherSuite
+		for _, suite := range weakCipherSuites {
+			if suite.version == version {
+				remaining = append(remaining, suite)
+			}
+		}
+
+		for len(remaining) > 0 {
+			ids := make([]uint16, 0, len(remaining))
+			for _, suite := range remaining {
+				ids = append(ids, suite.ID)
+			}
+
+			chosen, ok, err := probeCipherSuites(host, ipAddr, port, version, ids, timeout, opts)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				logger.Debug().
+					Str("tls_version", TLSVersionName(version)).
+					Int("cipher_suites", len(ids)).
+					Msg("Remaining weak cipher suites rejected")
+
+				break
+			}
+
+			next := remaining[:0]
+			for _, suite := range remaining {
+				if suite.ID != chosen {
+					next = append(next, suite)
+
+					continue
+				}
+
+				logger.Debug().
+					Str("tls_version", TLSVersionName(version)).
+					Str("cipher_suite", suite.Name).
+					Msg("Weak cipher suite accepted")
+
+				accepted = append(accepted, suite)
+			}
+
+			// A server choosing a cipher suite which was not offered is
+			// misbehaving; stop probing to avoid looping indefinitely.
+			if len(next) == len(remaining) {
+				return nil, fmt.Errorf(
+					"server chose cipher suite 0x%04X which was not offered: %w",
+					chosen,
+					ErrUnexpectedServerHello,
+				)
+			}
+
+			remaining = next
+		}
+	}
+
+	return accepted, nil
+}
+
+// probeCipherSuites sends a ClientHello message offering the given TLS
+// protocol version and cipher suites and returns the cipher suite chosen by
+// the server. False is returned if the server rejected the offer.
+func probeCipherSuites(
+	host string,
+	ipAddr string,
+	port int,
+	version uint16,
+	cipherSuites []uint16,
+	timeout time.Duration,
+	opts CertRetrievalOptions,
+) (uint16, bool, error) {
+
+	dialTimeout := opts.dialTimeout(timeout)
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer dialCancel()
+
+	serverConnStr := net.JoinHostPort(ipAddr, strconv.Itoa(port))
+	conn, connErr := opts.dialer(dialTimeout).DialContext(dialCtx, "tcp", serverConnStr)
+	if connErr != nil {
+		return 0, false, fmt.Errorf(
+			"error connecting to server (host: %s, IP: %s): %w",
+			host,
+			ipAddr,
+			phaseErr(PhaseTCPConnect, dialTimeout, connErr),
+		)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	handshakeTimeout := opts.handshakeTimeout(timeout)
+	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+		return 0, false, fmt.Errorf("failed to set probe deadline: %w", err)
+	}
+
+	hello, err := clientHelloRecord(host, version, cipherSuites)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if _, err := conn.Write(hello); err != nil {
+		// A server closing the connection upon receiving the offer is
+		// treated as a rejection.
+		return 0, false, nil
+	}
+
+	return readServerHelloCipherSuite(conn)
+}
+
+// TLS record and handshake message values used by cipher suite probes.
+const (
+	recordTypeAlert          byte = 21
+	recordTypeHandshake      byte = 22
+	handshakeTypeClientHello byte = 1
+	handshakeTypeServerHello byte = 2
+
+	extensionServerName          uint16 = 0x0000
+	extensionSupportedGroups     uint16 = 0x000A
+	extensionECPointFormats      uint16 = 0x000B
+	extensionSignatureAlgorithms uint16 = 0x000D
+	extensionRenegotiationInfo   uint16 = 0xFF01
+)
+
+// clientHelloRecord builds a TLS record containing a minimal ClientHello
+// message offering the given TLS protocol version and cipher suites. The
+// extensions commonly required by servers to negotiate ECDHE cipher suites
+// are included along with SNI if the given host is not an IP Address.
+func clientHelloRecord(host string, version uint16, cipherSuites []uint16) ([]byte, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate ClientHello random value: %w", err)
+	}
+
+	var extensions []byte
+
+	if host != "" && net.ParseIP(host) == nil {
+		name := []byte(host)
+		entry := appendUint16(nil, uint16(len(name)+3))
+		entry = append(entry, 0) // host_name
+		entry = appendUint16(entry, uint16(len(name)))
+		entry = append(entry, name...)
+		extensions = appendExtension(extensions, extensionServerName, entry)
+	}
+
+	// x25519, secp256r1, secp384r1 and secp521r1
+	groups := []uint16{0x001D, 0x0017, 0x0018, 0x0019}
+	groupList := appendUint16(nil, uint16(len(groups)*2))
+	for _, group := range groups {
+		groupList = appendUint16(groupList, group)
+	}
+	extensions = appendExtension(extensions, extensionSupportedGroups, groupList)
+
+	// uncompressed
+	extensions = appendExtension(extensions, extensionECPointFormats, []byte{1, 0})
+
+	// RSA and ECDSA signatures with SHA-256, SHA-384, SHA-512 and SHA-1
+	sigAlgs := []uint16{0x0401, 0x0501, 0x0601, 0x0403, 0x0503, 0x0603, 0x0201, 0x0203}
+	sigAlgList := appendUint16(nil, uint16(len(sigAlgs)*2))
+	for _, sigAlg := range sigAlgs {
+		sigAlgList = appendUint16(sigAlgList, sigAlg)
+	}
+	extensions = appendExtension(extensions, extensionSignatureAlgorithms, sigAlgList)
+
+	// Empty renegotiation_info for servers requiring secure renegotiation.
+	extensions = appendExtension(extensions, extensionRenegotiationInfo, []byte{0})
+
+	body := appendUint16(nil, version)
+	body = append(body, random...)
+	body = append(body, 0) // empty session ID
+	body = appendUint16(body, uint16(len(cipherSuites)*2))
+	for _, id := range cipherSuites {
+		body = appendUint16(body, id)
+	}
+	body = append(body, 1, 0) // null compression only
+	body = appendUint16(body, uint16(len(extensions)))
+	body = append(body, extensions...)
+
+	msg := []byte{
+		handshakeTypeClientHello,
+		byte(len(body) >> 16),
+		byte(len(body) >> 8),
+		byte(len(body)),
+	}
+	msg = append(msg, body...)
+
+	// The record layer version is TLS 1.0 for compatibility with servers
+	// which reject newer record versions in the initial ClientHello.
+	record := []byte{recordTypeHandshake}
+	record = appendUint16(record, tls.VersionTLS10)
+	record = appendUint16(record, uint16(len(msg)))
+	record = append(record, msg...)
+
+	return record, nil
+}
+
+// readServerHelloCipherSuite reads the server response to a ClientHello
+// message and returns the cipher suite chosen by the server. False is
+// returned if the server rejected the offer with an alert or by closing the
+// connection.
+func readServerHelloCipherSuite(r io.Reader) (uint16, bool, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return 0, false, fmt.Errorf(
+				"timeout waiting for ServerHello: %w",
+				err,
+			)
+		}
+
+		return 0, false, nil
+	}
+
+	switch header[0] {
+	case recordTypeAlert:
+		return 0, false, nil
+
+	case recordTypeHandshake:
+
+	default:
+		return 0, false, fmt.Errorf(
+			"record type %d: %w",
+			header[0],
+			ErrUnexpectedServerHello,
+		)
+	}
+
+	// The ServerHello message is small enough to be the first message of
+	// the first record.
+	length := int(binary.BigEndian.Uint16(header[3:5]))
+	msg := make([]byte, length)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return 0, false, fmt.Errorf(
+			"truncated ServerHello record: %w",
+			ErrUnexpectedServerHello,
+		)
+	}
+
+	// handshake type (1), length (3), version (2), random (32) and session
+	// ID length (1)
+	const fixedLen int = 1 + 3 + 2 + 32 + 1
+	if len(msg) < fixedLen || msg[0] != handshakeTypeServerHello {
+		return 0, false, fmt.Errorf(
+			"handshake message is not a ServerHello: %w",
+			ErrUnexpectedServerHello,
+		)
+	}
+
+	sessionIDLen := int(msg[fixedLen-1])
+	offset := fixedLen + sessionIDLen
+	if len(msg) < offset+2 {
+		return 0, false, fmt.Errorf(
+			"truncated ServerHello message: %w",
+			ErrUnexpectedServerHello,
+		)
+	}
+
+	return binary.BigEndian.Uint16(msg[offset : offset+2]), true, nil
+}
+
+// appendUint16 appends the given value in network byte order.
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+// appendExtension appends a ClientHello extension with the given type and
+// data.
+func appendExtension(b []byte, extType uint16, data []byte) []byte {
+	b = appendUint16(b, extType)
+	b = appendUint16(b, uint16(len(data)))
+
+	return append(b, data...)
+}
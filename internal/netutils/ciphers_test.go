@@ -0,0 +1,389 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// serverHelloRecord builds a TLS record containing a minimal ServerHello
+// message choosing the given cipher suite.
+func serverHelloRecord(cipherSuite uint16) []byte {
+	body := appendUint16(nil, tls.VersionTLS12)
+	body = append(body, make([]byte, 32)...) // random
+	body = append(body, 0)                   // empty session ID
+	body = appendUint16(body, cipherSuite)
+	body = append(body, 0) // null compression
+
+	msg := []byte{handshakeTypeServerHello, 0, byte(len(body) >> 8), byte(len(body))}
+	msg = append(msg, body...)
+
+	record := []byte{recordTypeHandshake}
+	record = appendUint16(record, tls.VersionTLS12)
+	record = appendUint16(record, uint16(len(msg)))
+
+	return append(record, msg...)
+}
+
+// alertRecord builds a TLS record containing a fatal handshake_failure
+// alert.
+func alertRecord() []byte {
+	return []byte{recordTypeAlert, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28}
+}
+
+// parsedClientHello is the subset of ClientHello message values evaluated by
+// tests.
+type parsedClientHello struct {
+	version      uint16
+	cipherSuites []uint16
+	serverName   string
+}
+
+// errTestClientHello indicates that a record could not be parsed as a
+// ClientHello message produced by clientHelloRecord.
+var errTestClientHello = errors.New("unexpected ClientHello record")
+
+// parseClientHelloRecord parses a TLS record produced by clientHelloRecord.
+func parseClientHelloRecord(record []byte) (parsedClientHello, error) {
+	switch {
+	case len(record) < 9 || record[0] != recordTypeHandshake:
+		return parsedClientHello{}, errTestClientHello
+	case int(binary.BigEndian.Uint16(record[3:5])) != len(record)-5:
+		return parsedClientHello{}, errTestClientHello
+	case record[5] != handshakeTypeClientHello:
+		return parsedClientHello{}, errTestClientHello
+	}
+
+	msg := record[5:]
+
+	var hello parsedClientHello
+
+	body := msg[4:]
+	hello.version = binary.BigEndian.Uint16(body[0:2])
+	body = body[2+32:]
+	body = body[1+int(body[0]):]
+
+	suitesLen := int(binary.BigEndian.Uint16(body[0:2]))
+	for i := 2; i < 2+suitesLen; i += 2 {
+		hello.cipherSuites = append(hello.cipherSuites, binary.BigEndian.Uint16(body[i:i+2]))
+	}
+	body = body[2+suitesLen:]
+	body = body[1+int(body[0]):]
+
+	extensions := body[2:]
+	for len(extensions) >= 4 {
+		extType := binary.BigEndian.Uint16(extensions[0:2])
+		extLen := int(binary.BigEndian.Uint16(extensions[2:4]))
+		data := extensions[4 : 4+extLen]
+
+		if extType == extensionServerName {
+			hello.serverName = string(data[5:])
+		}
+
+		extensions = extensions[4+extLen:]
+	}
+
+	return hello, nil
+}
+
+// readClientHelloRecord reads a single TLS record from the given reader.
+func readClientHelloRecord(r io.Reader) ([]byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	body := make([]byte, binary.BigEndian.Uint16(header[3:5]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	return append(header, body...), nil
+}
+
+// newFakeTLSServer starts a listener which responds to each ClientHello
+// message with the response returned by the given function. The IP Address
+// and port of the listener are returned.
+func newFakeTLSServer(t *testing.T, respond func(hello parsedClientHello) []byte) (string, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start listener: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			record, err := readClientHelloRecord(conn)
+			if err == nil {
+				hello, parseErr := parseClientHelloRecord(record)
+				if parseErr == nil {
+					_, _ = conn.Write(respond(hello))
+				}
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	host, portStr, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse listener address: %v", err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("failed to parse listener port: %v", err)
+	}
+
+	return host, port
+}
+
+func TestClientHelloRecord(t *testing.T) {
+	cipherSuites := []uint16{0x0004, 0x0005}
+
+	tests := []struct {
+		name       string
+		host       string
+		version    uint16
+		serverName string
+	}{
+		{
+			name:       "HostnameWithTLS12",
+			host:       "www.example.com",
+			version:    tls.VersionTLS12,
+			serverName: "www.example.com",
+		},
+		{
+			name:    "IPAddressWithTLS10",
+			host:    "192.0.2.10",
+			version: tls.VersionTLS10,
+		},
+		{
+			name:    "NoHost",
+			version: tls.VersionTLS12,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			record, err := clientHelloRecord(tt.host, tt.version, cipherSuites)
+			if err != nil {
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			hello, err := parseClientHelloRecord(record)
+			if err != nil {
+				t.Fatalf("want no error parsing ClientHello; got %v", err)
+			}
+
+			if hello.version != tt.version {
+				t.Errorf("want version 0x%04X; got 0x%04X", tt.version, hello.version)
+			}
+
+			if !reflect.DeepEqual(hello.cipherSuites, cipherSuites) {
+				t.Errorf("want cipher suites %v; got %v", cipherSuites, hello.cipherSuites)
+			}
+
+			if hello.serverName != tt.serverName {
+				t.Errorf("want server name %q; got %q", tt.serverName, hello.serverName)
+			}
+		})
+	}
+}
+
+func TestReadServerHelloCipherSuite(t *testing.T) {
+	serverHello := serverHelloRecord(0xC011)
+
+	notServerHello := serverHelloRecord(0xC011)
+	notServerHello[5] = handshakeTypeClientHello
+
+	tests := []struct {
+		name       string
+		response   []byte
+		wantSuite  uint16
+		wantChosen bool
+		err        error
+	}{
+		{
+			name:       "SelectedSuite",
+			response:   serverHello,
+			wantSuite:  0xC011,
+			wantChosen: true,
+		},
+		{
+			name:     "Alert",
+			response: alertRecord(),
+		},
+		{
+			name:     "ConnectionClosed",
+			response: nil,
+		},
+		{
+			name:     "TruncatedRecord",
+			response: serverHello[:len(serverHello)-10],
+			err:      ErrUnexpectedServerHello,
+		},
+		{
+			name:     "TruncatedMessage",
+			response: append([]byte{recordTypeHandshake, 0x03, 0x03, 0x00, 0x27}, serverHello[5:5+0x27]...),
+			err:      ErrUnexpectedServerHello,
+		},
+		{
+			name:     "UnexpectedHandshakeType",
+			response: notServerHello,
+			err:      ErrUnexpectedServerHello,
+		},
+		{
+			name:     "UnexpectedRecordType",
+			response: []byte{23, 0x03, 0x03, 0x00, 0x00},
+			err:      ErrUnexpectedServerHello,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			suite, chosen, err := readServerHelloCipherSuite(bytes.NewReader(tt.response))
+
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("want error %v; got %v", tt.err, err)
+				}
+
+				return
+
+			case err != nil:
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			if chosen != tt.wantChosen {
+				t.Errorf("want chosen %t; got %t", tt.wantChosen, chosen)
+			}
+
+			if suite != tt.wantSuite {
+				t.Errorf("want cipher suite 0x%04X; got 0x%04X", tt.wantSuite, suite)
+			}
+		})
+	}
+}
+
+func TestProbeWeakCipherSuites(t *testing.T) {
+	// acceptFirst returns a function responding with a ServerHello message
+	// choosing the first offered cipher suite in the given collection or an
+	// alert if none were offered.
+	acceptFirst := func(accepted ...uint16) func(parsedClientHello) []byte {
+		return func(hello parsedClientHello) []byte {
+			for _, offered := range hello.cipherSuites {
+				for _, id := range accepted {
+					if offered == id {
+						return serverHelloRecord(id)
+					}
+				}
+			}
+
+			return alertRecord()
+		}
+	}
+
+	tests := []struct {
+		name    string
+		respond func(parsedClientHello) []byte
+		want    []string
+		err     error
+	}{
+		{
+			name:    "WeakSuitesAccepted",
+			respond: acceptFirst(0x0005, 0x002F),
+			want: []string{
+				"TLS_RSA_WITH_RC4_128_SHA",
+				"TLS_RSA_WITH_AES_128_CBC_SHA",
+			},
+		},
+		{
+			name:    "AllRejected",
+			respond: acceptFirst(),
+		},
+		{
+			name: "SuiteNotOffered",
+			respond: func(parsedClientHello) []byte {
+				return serverHelloRecord(0x1301)
+			},
+			err: ErrUnexpectedServerHello,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ipAddr, port := newFakeTLSServer(t, tt.respond)
+
+			accepted, err := ProbeWeakCipherSuites(
+				"www.example.com",
+				ipAddr,
+				port,
+				5*time.Second,
+				CertRetrievalOptions{},
+				zerolog.Nop(),
+			)
+
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("want error %v; got %v", tt.err, err)
+				}
+
+				return
+
+			case err != nil:
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			var got []string
+			for _, suite := range accepted {
+				got = append(got, suite.Name)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %v; got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("MissingIPAddress", func(t *testing.T) {
+		_, err := ProbeWeakCipherSuites("www.example.com", "", 443, time.Second, CertRetrievalOptions{}, zerolog.Nop())
+		if !errors.Is(err, ErrMissingValue) {
+			t.Errorf("want error %v; got %v", ErrMissingValue, err)
+		}
+	})
+}
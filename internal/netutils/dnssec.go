@@ -0,0 +1,434 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrInsecureResolution indicates that the DNS server did not report the DNS
+// answer for a hostname or FQDN as authenticated using DNSSEC.
+var ErrInsecureResolution = errors.New("hostname resolution not reported as DNSSEC validated by DNS server")
+
+// ErrInvalidDNSResponse indicates that a DNS server returned a response
+// which could not be used.
+var ErrInvalidDNSResponse = errors.New("invalid DNS response")
+
+// DNSSECStatus indicates whether the DNS server reported the DNS answer for a
+// hostname or FQDN as authenticated using DNSSEC. This status is only as
+// trustworthy as the DNS server and the network path to it; DNSSEC
+// signatures are not validated by this package.
+type DNSSECStatus string
+
+const (
+	// DNSSECSecure indicates that the DNS server reported the DNS answer as
+	// authenticated (the AD bit was set).
+	DNSSECSecure DNSSECStatus = "secure"
+
+	// DNSSECInsecure indicates that the DNS server did not report the DNS
+	// answer as authenticated (e.g., the zone is not signed or the DNS
+	// server does not perform DNSSEC validation).
+	DNSSECInsecure DNSSECStatus = "insecure"
+
+	// DNSSECIndeterminate indicates that the DNS answer could not be
+	// obtained in order to determine whether it was authenticated.
+	DNSSECIndeterminate DNSSECStatus = "indeterminate"
+)
+
+// DNSSECResult is the DNSSEC status reported by a DNS server for the DNS
+// answer for a hostname or FQDN.
+type DNSSECResult struct {
+	// Name is the hostname or FQDN queried.
+	Name string
+
+	// Server is the DNS server (host:port) queried.
+	Server string
+
+	// Status indicates whether the DNS server reported the DNS answer as
+	// authenticated.
+	Status DNSSECStatus
+
+	// AuthenticatedData indicates whether the Authenticated Data (AD) bit
+	// was set in the DNS response.
+	AuthenticatedData bool
+
+	// Err records any error encountered obtaining the DNS answer.
+	Err error
+}
+
+// Secure indicates whether the DNS server reported the DNS answer as
+// authenticated.
+func (r DNSSECResult) Secure() bool {
+	return r.Status == DNSSECSecure
+}
+
+// String provides a brief summary of the result suitable for report output.
+func (r DNSSECResult) String() string {
+	switch r.Status {
+	case DNSSECIndeterminate:
+		return fmt.Sprintf(
+			"DNSSEC status for %q indeterminate (DNS server %s): %v",
+			r.Name,
+			r.Server,
+			r.Err,
+		)
+	default:
+		adBit := "not set"
+		if r.AuthenticatedData {
+			adBit = "set"
+		}
+
+		return fmt.Sprintf(
+			"DNSSEC status for %q: %s (AD bit %s by DNS server %s)",
+			r.Name,
+			r.Status,
+			adBit,
+			r.Server,
+		)
+	}
+}
+
+// AnswerValidator determines the DNSSEC status of the DNS answer for a
+// hostname or FQDN.
+type AnswerValidator interface {
+	ValidateAnswer(name string) DNSSECResult
+}
+
+// ADBitValidator is an AnswerValidator which relies on a trusted, DNSSEC
+// validating DNS server (e.g., a local recursive resolver) to authenticate
+// DNS answers. The answer is considered authenticated if the Authenticated
+// Data (AD) bit is set in the response.
+//
+// DNSSEC signatures (RRSIG records) are not requested or validated. The AD
+// bit is not protected in transit, so anything able to forge a DNS response
+// (e.g., on the network path to a remote DNS server) can also set it. Use a
+// DNS server reached over a trusted path (e.g., on localhost).
+type ADBitValidator struct {
+	// Server is the DNS server (host:port) queried.
+	Server string
+
+	// Timeout is applied to each query; a timeout of zero applies no limit.
+	Timeout time.Duration
+}
+
+// Constants used to build and parse DNS messages (RFC 1035, RFC 6891).
+const (
+	dnsHeaderLen       int    = 12
+	dnsFlagQR          uint16 = 1 << 15
+	dnsFlagTC          uint16 = 1 << 9
+	dnsFlagRD          uint16 = 1 << 8
+	dnsFlagAD          uint16 = 1 << 5
+	dnsRCodeMask       uint16 = 0x000F
+	dnsTypeA           uint16 = 1
+	dnsTypeOPT         uint16 = 41
+	dnsClassIN         uint16 = 1
+	dnsEDNSPayloadSize uint16 = 1232
+	dnsEDNSFlagDO      uint32 = 1 << 15
+	dnsMaxLabelLen     int    = 63
+)
+
+// resolvConfPath is the file listing the DNS servers used by the system
+// resolver on Unix-like systems.
+const resolvConfPath string = "/etc/resolv.conf"
+
+// systemDNSServer returns the first DNS server (host:port) listed in the
+// system resolver configuration.
+func systemDNSServer() (string, error) {
+	f, err := os.Open(resolvConfPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"unable to determine system DNS server: %w",
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return DNSServerAddress(fields[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf(
+			"unable to determine system DNS server: %w",
+			err,
+		)
+	}
+
+	return "", fmt.Errorf(
+		"unable to determine system DNS server; no nameserver entries in %s: %w",
+		resolvConfPath,
+		ErrInvalidDNSServer,
+	)
+}
+
+// NewADBitValidator returns an ADBitValidator which queries the given DNS
+// server (IP Address or hostname with an optional port) using the given
+// timeout. The first DNS server used by the system resolver is queried if a
+// DNS server is not specified.
+func NewADBitValidator(server string, timeout time.Duration) (*ADBitValidator, error) {
+	var address string
+	var err error
+
+	switch {
+	case server == "":
+		address, err = systemDNSServer()
+	default:
+		address, err = DNSServerAddress(server)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ADBitValidator{
+		Server:  address,
+		Timeout: timeout,
+	}, nil
+}
+
+// ValidateAnswer queries the DNS server for the A record of the given
+// hostname or FQDN with DNSSEC records requested and reports whether the
+// DNS server set the AD bit in its response.
+func (v ADBitValidator) ValidateAnswer(name string) DNSSECResult {
+	result := DNSSECResult{
+		Name:   name,
+		Server: v.Server,
+		Status: DNSSECIndeterminate,
+	}
+
+	flags, err := v.exchange(name, dnsTypeA)
+	if err != nil {
+		result.Err = err
+
+		return result
+	}
+
+	result.AuthenticatedData = flags&dnsFlagAD != 0
+	switch {
+	case result.AuthenticatedData:
+		result.Status = DNSSECSecure
+	default:
+		result.Status = DNSSECInsecure
+	}
+
+	return result
+}
+
+// exchange sends a query for the given name and record type to the DNS
+// server, returning the header flags of the response. The query is retried
+// over TCP if the UDP response is truncated.
+func (v ADBitValidator) exchange(name string, qtype uint16) (uint16, error) {
+	query, id, err := newDNSQuery(name, qtype)
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := v.roundTrip("udp", query)
+	if err != nil {
+		return 0, err
+	}
+
+	flags, err := parseDNSResponseFlags(response, id)
+	if err != nil {
+		return 0, err
+	}
+
+	if flags&dnsFlagTC != 0 {
+		response, err = v.roundTrip("tcp", query)
+		if err != nil {
+			return 0, err
+		}
+
+		if flags, err = parseDNSResponseFlags(response, id); err != nil {
+			return 0, err
+		}
+	}
+
+	if rcode := flags & dnsRCodeMask; rcode != 0 {
+		return 0, fmt.Errorf(
+			"query for %q returned response code %d: %w",
+			name,
+			rcode,
+			ErrInvalidDNSResponse,
+		)
+	}
+
+	return flags, nil
+}
+
+// roundTrip sends the given DNS message to the DNS server using the given
+// network (udp or tcp) and returns the response.
+func (v ADBitValidator) roundTrip(network string, query []byte) ([]byte, error) {
+	dialer := net.Dialer{Timeout: v.Timeout}
+
+	conn, err := dialer.Dial(network, v.Server)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to connect to DNS server %s: %w",
+			v.Server,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	if v.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(v.Timeout)); err != nil {
+			return nil, fmt.Errorf("failed to set deadline for DNS query: %w", err)
+		}
+	}
+
+	// Messages sent over TCP are prefixed with a two byte length field.
+	if network == "tcp" {
+		msg := make([]byte, 2, 2+len(query))
+		binary.BigEndian.PutUint16(msg, uint16(len(query)))
+		query = append(msg, query...)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, fmt.Errorf(
+			"failed to send query to DNS server %s: %w",
+			v.Server,
+			err,
+		)
+	}
+
+	if network == "tcp" {
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, fmt.Errorf(
+				"failed to read response from DNS server %s: %w",
+				v.Server,
+				err,
+			)
+		}
+
+		response := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, response); err != nil {
+			return nil, fmt.Errorf(
+				"failed to read response from DNS server %s: %w",
+				v.Server,
+				err,
+			)
+		}
+
+		return response, nil
+	}
+
+	response := make([]byte, 65535)
+	n, err := conn.Read(response)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read response from DNS server %s: %w",
+			v.Server,
+			err,
+		)
+	}
+
+	return response[:n], nil
+}
+
+// newDNSQuery builds a recursive DNS query for the given name and record
+// type with the AD bit set (RFC 6840) and an EDNS0 OPT record with the
+// DNSSEC OK (DO) bit set (RFC 3225). The query ID is returned along with the
+// query.
+func newDNSQuery(name string, qtype uint16) ([]byte, uint16, error) {
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, 0, fmt.Errorf("failed to generate DNS query ID: %w", err)
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+
+	msg := make([]byte, dnsHeaderLen, 512)
+	binary.BigEndian.PutUint16(msg[0:], id)
+	binary.BigEndian.PutUint16(msg[2:], dnsFlagRD|dnsFlagAD)
+	binary.BigEndian.PutUint16(msg[4:], 1)  // QDCOUNT
+	binary.BigEndian.PutUint16(msg[10:], 1) // ARCOUNT
+
+	fqdn := strings.TrimSuffix(name, ".")
+	if fqdn == "" {
+		return nil, 0, fmt.Errorf("empty name: %w", ErrHostnameFailsNameResolution)
+	}
+
+	for _, label := range strings.Split(fqdn, ".") {
+		if label == "" || len(label) > dnsMaxLabelLen {
+			return nil, 0, fmt.Errorf(
+				"name %q contains an invalid label %q: %w",
+				name,
+				label,
+				ErrHostnameFailsNameResolution,
+			)
+		}
+
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+
+	// EDNS0 OPT pseudo-record: root name, type, UDP payload size (class),
+	// extended RCODE/version/flags (TTL) and empty RDATA.
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, dnsTypeOPT)
+	msg = binary.BigEndian.AppendUint16(msg, dnsEDNSPayloadSize)
+	msg = binary.BigEndian.AppendUint32(msg, dnsEDNSFlagDO)
+	msg = binary.BigEndian.AppendUint16(msg, 0)
+
+	return msg, id, nil
+}
+
+// parseDNSResponseFlags returns the header flags of the given DNS response
+// after confirming that it is a response to the query with the given ID.
+func parseDNSResponseFlags(response []byte, id uint16) (uint16, error) {
+	if len(response) < dnsHeaderLen {
+		return 0, fmt.Errorf(
+			"response too short (%d bytes): %w",
+			len(response),
+			ErrInvalidDNSResponse,
+		)
+	}
+
+	if got := binary.BigEndian.Uint16(response[0:]); got != id {
+		return 0, fmt.Errorf(
+			"response ID %d does not match query ID %d: %w",
+			got,
+			id,
+			ErrInvalidDNSResponse,
+		)
+	}
+
+	flags := binary.BigEndian.Uint16(response[2:])
+	if flags&dnsFlagQR == 0 {
+		return 0, fmt.Errorf(
+			"message is not a response: %w",
+			ErrInvalidDNSResponse,
+		)
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// serveDNSFlags answers each query received on the given connection with a
+// response (header only) echoing the query ID and using the given flags.
+func serveDNSFlags(t *testing.T, conn net.PacketConn, flags uint16) {
+	t.Helper()
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			if n < dnsHeaderLen {
+				continue
+			}
+
+			response := make([]byte, dnsHeaderLen)
+			copy(response, buf[:2])
+			binary.BigEndian.PutUint16(response[2:], flags)
+
+			_, _ = conn.WriteTo(response, addr)
+		}
+	}()
+}
+
+func TestNewDNSQuery(t *testing.T) {
+	query, id, err := newDNSQuery("www.example.com.", dnsTypeA)
+	if err != nil {
+		t.Fatalf("want no error; got %v", err)
+	}
+
+	if got := binary.BigEndian.Uint16(query[0:]); got != id {
+		t.Errorf("want query ID %d; got %d", id, got)
+	}
+
+	if flags := binary.BigEndian.Uint16(query[2:]); flags != dnsFlagRD|dnsFlagAD {
+		t.Errorf("want RD and AD flags set; got %#04x", flags)
+	}
+
+	// header + encoded name + QTYPE/QCLASS + OPT record
+	wantLen := dnsHeaderLen + len("\x03www\x07example\x03com\x00") + 4 + 11
+	if len(query) != wantLen {
+		t.Errorf("want query length %d; got %d", wantLen, len(query))
+	}
+
+	if ttl := binary.BigEndian.Uint32(query[len(query)-6:]); ttl&dnsEDNSFlagDO == 0 {
+		t.Error("want DO bit set in OPT record")
+	}
+
+	for _, name := range []string{"", "www..example.com"} {
+		if _, _, err := newDNSQuery(name, dnsTypeA); !errors.Is(err, ErrHostnameFailsNameResolution) {
+			t.Errorf("want error %v for %q; got %v", ErrHostnameFailsNameResolution, name, err)
+		}
+	}
+}
+
+func TestADBitValidator(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    uint16
+		expected DNSSECStatus
+	}{
+		{
+			name:     "Authenticated",
+			flags:    dnsFlagQR | dnsFlagRD | dnsFlagAD,
+			expected: DNSSECSecure,
+		},
+		{
+			name:     "NotAuthenticated",
+			flags:    dnsFlagQR | dnsFlagRD,
+			expected: DNSSECInsecure,
+		},
+		{
+			name:     "ServerFailure",
+			flags:    dnsFlagQR | dnsFlagRD | 2,
+			expected: DNSSECIndeterminate,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("failed to start DNS server: %v", err)
+			}
+			defer func() {
+				_ = conn.Close()
+			}()
+
+			serveDNSFlags(t, conn, tt.flags)
+
+			validator, err := NewADBitValidator(conn.LocalAddr().String(), 2*time.Second)
+			if err != nil {
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			result := validator.ValidateAnswer("www.example.com")
+			if result.Status != tt.expected {
+				t.Errorf("want status %q; got %q (%v)", tt.expected, result.Status, result.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidHostsFileEntry indicates that a hosts file entry could not
+	// be parsed.
+	ErrInvalidHostsFileEntry = errors.New("invalid hosts file entry")
+
+	// ErrNoHostsFound indicates that no host entries were found.
+	ErrNoHostsFound = errors.New("no host entries found")
+)
+
+// ParseHostsFile parses host patterns from the given reader. Each line is
+// expected to contain a single IP Address, CIDR IP range, partial
+// (dash-separated) IP range, hostname, FQDN or DNS SRV record name (e.g.,
+// _ldaps._tcp.example.com) optionally followed by a colon and a
+// comma-separated list of ports which override the ports specified for all
+// host patterns (e.g., 192.168.5.0/24:443,8443 or [2001:db8::1]:636). Blank
+// lines and text following a # character are ignored.
+//
+// Hostnames and FQDNs are resolved using the given resolver (the system
+// resolver if not provided) within the given timeout (if non-zero).
+func ParseHostsFile(r io.Reader, resolver *net.Resolver, timeout time.Duration) ([]HostPattern, error) {
+	var hosts []HostPattern
+
+	scanner := bufio.NewScanner(r)
+
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		hostPattern, ports, err := splitHostsFileEntry(line)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"entry %q on line %d: %w",
+				line,
+				lineNum,
+				err,
+			)
+		}
+
+		expanded, err := ExpandHostsWithResolver(hostPattern, resolver, timeout)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"entry %q on line %d: %w",
+				line,
+				lineNum,
+				err,
+			)
+		}
+
+		// Ports specified for the entry override those advertised by a DNS
+		// SRV record.
+		if len(ports) > 0 {
+			for i := range expanded {
+				expanded[i].Ports = ports
+			}
+		}
+
+		hosts = append(hosts, expanded...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to read host entries: %w",
+			err,
+		)
+	}
+
+	if len(hosts) == 0 {
+		return nil, ErrNoHostsFound
+	}
+
+	return hosts, nil
+}
+
+// splitHostsFileEntry splits a hosts file entry into the host pattern and
+// optional list of ports.
+func splitHostsFileEntry(entry string) (string, []int, error) {
+	host, portsList, err := net.SplitHostPort(entry)
+	if err != nil {
+		// No ports specified; bare IPv6 addresses are accepted as-is.
+		return strings.Trim(entry, "[]"), nil, nil
+	}
+
+	if host == "" {
+		return "", nil, fmt.Errorf(
+			"missing host pattern: %w",
+			ErrInvalidHostsFileEntry,
+		)
+	}
+
+	ports, err := ParsePortsList(portsList)
+	if err != nil {
+		return "", nil, fmt.Errorf(
+			"invalid ports %q: %v: %w",
+			portsList,
+			err,
+			ErrInvalidHostsFileEntry,
+		)
+	}
+
+	for _, port := range ports {
+		if port < 1 || port > 65535 {
+			return "", nil, fmt.Errorf(
+				"invalid port %d: %w",
+				port,
+				ErrInvalidHostsFileEntry,
+			)
+		}
+	}
+
+	return host, ports, nil
+}
+
+// LoadHostsFile parses host patterns from the specified file using the given
+// resolver and timeout. See ParseHostsFile for the expected format.
+func LoadHostsFile(filename string, resolver *net.Resolver, timeout time.Duration) ([]HostPattern, error) {
+	// Open the hosts file after first attempting to sanitize the input file
+	// variable contents.
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to open hosts file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hosts, err := ParseHostsFile(f, resolver, timeout)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse hosts file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return hosts, nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHostsFile(t *testing.T) {
+	t.Parallel()
+
+	type host struct {
+		given    string
+		expanded []string
+		ports    []int
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []host
+		err      error
+	}{
+		{
+			name: "EntriesWithCommentsAndBlankLines",
+			input: strings.Join([]string{
+				"# Web servers",
+				"",
+				"192.0.2.1",
+				"192.0.2.10-11:443,8443   # load balanced pair",
+				"[2001:db8::1]:636",
+				"2001:db8::2",
+				"  198.51.100.0/30  ",
+			}, "\n"),
+			expected: []host{
+				{given: "192.0.2.1", expanded: []string{"192.0.2.1"}},
+				{given: "192.0.2.10-11", expanded: []string{"192.0.2.10", "192.0.2.11"}, ports: []int{443, 8443}},
+				{given: "2001:db8::1", expanded: []string{"2001:db8::1"}, ports: []int{636}},
+				{given: "2001:db8::2", expanded: []string{"2001:db8::2"}},
+				{given: "198.51.100.0/30", expanded: []string{"198.51.100.1", "198.51.100.2"}},
+			},
+		},
+		{
+			name:  "PortRange",
+			input: "192.0.2.1:8443-8445\n",
+			expected: []host{
+				{given: "192.0.2.1", expanded: []string{"192.0.2.1"}, ports: []int{8443, 8444, 8445}},
+			},
+		},
+		{
+			name:  "OnlyComments",
+			input: "# nothing to see here\n\n",
+			err:   ErrNoHostsFound,
+		},
+		{
+			name:  "MissingHostPattern",
+			input: ":443\n",
+			err:   ErrInvalidHostsFileEntry,
+		},
+		{
+			name:  "PortOutOfRange",
+			input: "192.0.2.1:70000\n",
+			err:   ErrInvalidHostsFileEntry,
+		},
+		{
+			name:  "NonNumericPort",
+			input: "192.0.2.1:https\n",
+			err:   ErrInvalidHostsFileEntry,
+		},
+		{
+			name:  "InvalidRange",
+			input: "192.0.2.300-301\n",
+			err:   ErrUnrecognizedIPRange,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			hosts, err := ParseHostsFile(strings.NewReader(tt.input), nil, 0)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			got := make([]host, 0, len(hosts))
+			for _, h := range hosts {
+				got = append(got, host{given: h.Given, expanded: h.Expanded, ports: h.Ports})
+			}
+
+			if tt.err == nil && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("want %+v; got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoadHostsFile(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "hosts.txt")
+	if err := os.WriteFile(filename, []byte("192.0.2.1:443\n"), 0o600); err != nil {
+		t.Fatalf("failed to write hosts file: %v", err)
+	}
+
+	hosts, err := LoadHostsFile(filename, nil, 0)
+	if err != nil {
+		t.Fatalf("failed to load hosts file: %v", err)
+	}
+
+	if len(hosts) != 1 || hosts[0].Given != "192.0.2.1" {
+		t.Errorf("unexpected hosts: %+v", hosts)
+	}
+
+	if _, err := LoadHostsFile(filepath.Join(t.TempDir(), "missing.txt"), nil, 0); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want error %v; got %v", os.ErrNotExist, err)
+	}
+}
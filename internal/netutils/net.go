@@ -8,13 +8,18 @@
 package netutils
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/netip"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -28,6 +33,10 @@ var ErrUnrecognizedHostOrIPValue = errors.New("unrecognized hostname or IP Addre
 // as a valid IP Address range (partial or CIDR).
 var ErrUnrecognizedIPRange = errors.New("unrecognized IP Address range")
 
+// ErrIPv6RangeTooLarge indicates that a given IPv6 range contains more than
+// MaxIPv6RangeHosts addresses and cannot be expanded in full.
+var ErrIPv6RangeTooLarge = errors.New("IPv6 Address range too large to expand in full")
+
 // ErrUnrecognizedIPAddress indicates that a given string value is
 // unrecognized as a valid IP Address.
 var ErrUnrecognizedIPAddress = errors.New("unrecognized IP Address")
@@ -43,6 +52,21 @@ var ErrIPAddrOctectIdxValidityFailure = errors.New("invalid index of IP Address
 // ErrMissingValue indicates that an expected value was missing.
 var ErrMissingValue = errors.New("missing expected value")
 
+// MaxIPv6RangeHosts is the maximum number of IP Addresses expanded from an
+// IPv6 CIDR or partial range. IPv6 networks are far too large to scan in
+// full (a single /64 network has 2^64 addresses), so only the first
+// addresses of larger ranges are expanded. This covers the low host
+// addresses (e.g., ::1 through ::ffff) commonly assigned to servers.
+const MaxIPv6RangeHosts int = 65536
+
+// Connection phases noted in errors returned when retrieving a certificate
+// chain or resolving a host pattern.
+const (
+	PhaseDNSResolution string = "DNS resolution"
+	PhaseTCPConnect    string = "TCP connect"
+	PhaseTLSHandshake  string = "TLS handshake"
+)
+
 // IndexSize returns the number of entries in the index.
 func (idx IPv4AddressOctetsIndex) IndexSize() int {
 	var mapEntriesSize int
@@ -127,7 +151,7 @@ func CheckPort(host PortCheckTarget, port int, timeout time.Duration) PortCheckR
 		// fmt.Printf("connErr: %v\n", connErr)
 		return PortCheckResult{
 			Host:      host.Name,
-			IPAddress: net.IPAddr{IP: net.ParseIP(host.IPAddress)},
+			IPAddress: parseIPAddr(host.IPAddress),
 			Port:      port,
 			Open:      false,
 			Err:       connErr,
@@ -140,7 +164,7 @@ func CheckPort(host PortCheckTarget, port int, timeout time.Duration) PortCheckR
 	if disableKeepAliveErr != nil {
 		return PortCheckResult{
 			Host:      host.Name,
-			IPAddress: net.IPAddr{IP: net.ParseIP(host.IPAddress)},
+			IPAddress: parseIPAddr(host.IPAddress),
 			Port:      port,
 			Open:      true,
 			Err:       disableKeepAliveErr,
@@ -152,7 +176,7 @@ func CheckPort(host PortCheckTarget, port int, timeout time.Duration) PortCheckR
 		// fmt.Println("connection close error")
 		return PortCheckResult{
 			Host:      host.Name,
-			IPAddress: net.IPAddr{IP: net.ParseIP(host.IPAddress)},
+			IPAddress: parseIPAddr(host.IPAddress),
 			Port:      port,
 			Open:      true,
 			Err:       closeErr,
@@ -161,7 +185,7 @@ func CheckPort(host PortCheckTarget, port int, timeout time.Duration) PortCheckR
 
 	result := PortCheckResult{
 		Host:      host.Name,
-		IPAddress: net.IPAddr{IP: net.ParseIP(host.IPAddress)},
+		IPAddress: parseIPAddr(host.IPAddress),
 		Port:      port,
 		Open:      true,
 		Err:       nil,
@@ -173,16 +197,54 @@ func CheckPort(host PortCheckTarget, port int, timeout time.Duration) PortCheckR
 
 }
 
+// parseIPAddr converts the given IP Address, optionally including an IPv6
+// zone (e.g., fe80::1%eth0), to a net.IPAddr value.
+func parseIPAddr(s string) net.IPAddr {
+	ip, zone, _ := strings.Cut(s, "%")
+
+	return net.IPAddr{IP: net.ParseIP(ip), Zone: zone}
+}
+
 // CIDRHosts converts a CIDR network pattern into a slice of hosts within that
 // network, the total count of hosts and an error if any occurred.
 //
+// The network address (and broadcast address for IPv4 networks) is
+// excluded. An error is returned for IPv6 networks with more than
+// MaxIPv6RangeHosts hosts; use ExpandHost to retrieve the first
+// MaxIPv6RangeHosts hosts of such networks instead.
+//
 // https://stackoverflow.com/questions/60540465/go-how-to-list-all-ips-in-a-network
 // https://play.golang.org/p/fe-F2k6prlA
 // https://gist.github.com/kotakanbe/d3059af990252ba89a82
 func CIDRHosts(cidr string) ([]string, int, error) {
+	ips, truncated, err := cidrHosts(cidr)
+	switch {
+	case err != nil:
+		return nil, 0, err
+
+	case truncated:
+		return nil, 0, fmt.Errorf(
+			"%q has more than %d hosts: %w",
+			cidr,
+			MaxIPv6RangeHosts,
+			ErrIPv6RangeTooLarge,
+		)
+	}
+
+	return ips, len(ips), nil
+}
+
+// cidrHosts converts a CIDR network pattern into a slice of hosts within
+// that network and indicates whether the hosts were limited to the first
+// MaxIPv6RangeHosts hosts of an IPv6 network.
+func cidrHosts(cidr string) ([]string, bool, error) {
 	ip, ipnet, err := net.ParseCIDR(cidr)
 	if err != nil {
-		return nil, 0, err
+		return nil, false, err
+	}
+
+	if ip.To4() == nil {
+		return ipv6CIDRHosts(ipnet)
 	}
 
 	var ips []string
@@ -194,13 +256,101 @@ func CIDRHosts(cidr string) ([]string, int, error) {
 	lenIPs := len(ips)
 	switch {
 	case lenIPs < 2:
-		return ips, lenIPs, nil
+		return ips, false, nil
 
 	default:
-		return ips[1 : len(ips)-1], lenIPs - 2, nil
+		return ips[1 : len(ips)-1], false, nil
 	}
 }
 
+// ipv6CIDRHosts returns the hosts within the given IPv6 network and
+// indicates whether the hosts were limited to the first MaxIPv6RangeHosts
+// hosts. The network address (the Subnet-Router anycast address) is
+// excluded unless the network consists of a single address.
+func ipv6CIDRHosts(ipnet *net.IPNet) ([]string, bool, error) {
+	ones, bits := ipnet.Mask.Size()
+	if ones == bits {
+		return []string{ipnet.IP.String()}, false, nil
+	}
+
+	start, ok := netip.AddrFromSlice(ipnet.IP)
+	if !ok {
+		return nil, false, fmt.Errorf(
+			"%q invalid: %w",
+			ipnet.String(),
+			ErrUnrecognizedIPAddress,
+		)
+	}
+	prefix := netip.PrefixFrom(start, ones)
+
+	return ipv6Hosts(start.Next(), func(addr netip.Addr) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+// ipv6Hosts returns consecutive IPv6 Addresses beginning with the given
+// start address for as long as the given function returns true for an
+// address. The result is limited to the first MaxIPv6RangeHosts addresses
+// and whether this limit was applied is indicated.
+func ipv6Hosts(start netip.Addr, inRange func(netip.Addr) bool) ([]string, bool, error) {
+	var ips []string
+	for addr := start; addr.IsValid() && inRange(addr); addr = addr.Next() {
+		if len(ips) == MaxIPv6RangeHosts {
+			return ips, true, nil
+		}
+
+		ips = append(ips, addr.String())
+	}
+
+	return ips, false, nil
+}
+
+// ipv6RangeHosts converts a partial IPv6 Address range (e.g.,
+// 2001:db8::10-1f or 2001:db8::ff00-2001:db8::1:ff) into a slice of hosts
+// within that range and indicates whether the hosts were limited to the
+// first MaxIPv6RangeHosts hosts. The end of the range is either a complete
+// IPv6 Address or a replacement for the last group of the start address. A
+// zone given for the start address (e.g., fe80::10%eth0-1f) also applies to
+// a replacement last group.
+func ipv6RangeHosts(hostPattern string) ([]string, bool, error) {
+	startValue, endValue, _ := strings.Cut(hostPattern, "-")
+
+	if !strings.Contains(endValue, ":") {
+		startAddr, zone, zoned := strings.Cut(startValue, "%")
+		endValue = startAddr[:strings.LastIndex(startAddr, ":")+1] + endValue
+
+		if zoned && !strings.Contains(endValue, "%") {
+			endValue += "%" + zone
+		}
+	}
+
+	start, startErr := netip.ParseAddr(startValue)
+	end, endErr := netip.ParseAddr(endValue)
+
+	switch {
+	case startErr != nil || endErr != nil || !start.Is6() || !end.Is6() || start.Zone() != end.Zone():
+		return nil, false, fmt.Errorf(
+			"%q is invalid IPv6 Address range: %w",
+			hostPattern,
+			ErrUnrecognizedIPRange,
+		)
+
+	case start.Compare(end) >= 0:
+		return nil, false, fmt.Errorf(
+			"%q is invalid IPv6 Address range; "+
+				"given start value %s not less than end value %s: %w",
+			hostPattern,
+			start,
+			end,
+			ErrUnrecognizedIPRange,
+		)
+	}
+
+	return ipv6Hosts(start, func(addr netip.Addr) bool {
+		return addr.Compare(end) <= 0
+	})
+}
+
 // inc is a helper function used to increment a given IP Address (presumably
 // to the next available IP Address).
 //
@@ -225,9 +375,35 @@ func inc(ip net.IP) {
 // successfully retrieve and examine all certificates in the certificate
 // chain.
 func GetCerts(host string, ipAddr string, port int, timeout time.Duration, logger zerolog.Logger) ([]*x509.Certificate, error) {
+	certChain, _, err := GetCertsWithOptions(host, ipAddr, port, timeout, CertRetrievalOptions{}, logger)
+
+	return certChain, err
+}
+
+// GetCertsWithOptions retrieves and returns the certificate chain from the
+// specified IP Address & port along with details of the connection attempts
+// made or an error if one occurs. The given options allow the caller to
+// supply a custom TLS client configuration and dialer (e.g., to integrate
+// custom root certificates, client certificates or a proxy) and to retry
+// after transient connection failures. The default TLS client configuration
+// and dialer used by GetCerts are used for any options not specified.
+//
+// The given timeout applies separately to establishing the connection and to
+// completing the TLS handshake for each attempt unless overridden by the
+// per-phase timeouts specified in the given options.
+func GetCertsWithOptions(
+	host string,
+	ipAddr string,
+	port int,
+	timeout time.Duration,
+	opts CertRetrievalOptions,
+	logger zerolog.Logger,
+) ([]*x509.Certificate, CertRetrievalStats, error) {
+
+	var stats CertRetrievalStats
 
 	if strings.TrimSpace(ipAddr) == "" {
-		return nil, fmt.Errorf(
+		return nil, stats, fmt.Errorf(
 			"target IP Address not specified: %w",
 			ErrMissingValue,
 		)
@@ -237,57 +413,114 @@ func GetCerts(host string, ipAddr string, port int, timeout time.Duration, logge
 	// breaking SNI support when setting TLS client configuration.
 	host = strings.TrimSpace(host)
 
-	var certChain []*x509.Certificate
-
 	logger = logger.With().
 		Str("host", host).
 		Str("ip_address", ipAddr).
 		Int("port", port).
 		Str("timeout", timeout.String()).
+		Str("dial_timeout", opts.dialTimeout(timeout).String()).
+		Str("handshake_timeout", opts.handshakeTimeout(timeout).String()).
+		Bool("custom_tls_config", opts.TLSConfig != nil).
+		Bool("custom_dialer", opts.Dialer != nil).
+		Int("max_attempts", opts.maxAttempts()).
 		Logger()
 
-	logger.Debug().Msg("Connecting to remote server")
-	tlsConfig := tls.Config{
-		// Permit insecure connection.
-		//
-		// This is needed so that we can examine not only valid certificates,
-		// but certs that are expired, self-signed or having other properties
-		// which make them invalid. This is also needed so that we can examine
-		// not only the initial certificate, but others in the chain also.
-		// This allows us to flag any intermediate or root certs which may
-		// also be expired.
-		//
-		// Ignore security (gosec) linting warnings re this choice.
-		// nolint:gosec
-		InsecureSkipVerify: true,
-
-		// ServerName is included in the client's handshake to support virtual
-		// hosting. Specifying the value here allows us to connect to a
-		// specific IP Address while also retrieving a certificate chain for a
-		// specific host value.
-		ServerName: host,
-	}
-
-	// Create custom dialer with user-specified timeout value
-	dialer := &net.Dialer{
-		Timeout: timeout,
+	for {
+		stats.Attempts++
+
+		certChain, err := getCerts(host, ipAddr, port, timeout, opts, &stats, logger.With().Int("attempt", stats.Attempts).Logger())
+		switch {
+		case err == nil:
+			return certChain, stats, nil
+
+		case stats.Attempts >= opts.maxAttempts() || !isTransientFailure(err):
+			return nil, stats, err
+		}
+
+		delay := opts.retryDelay(stats.Attempts)
+		logger.Debug().
+			Err(err).
+			Int("attempt", stats.Attempts).
+			Str("retry_delay", delay.String()).
+			Msg("Transient failure retrieving certificate chain, retrying")
+
+		time.Sleep(delay)
 	}
+}
+
+// getCerts makes a single attempt to retrieve and return the certificate
+// chain from the specified IP Address & port. The time taken to connect and
+// complete the TLS handshake is recorded in the given stats.
+func getCerts(
+	host string,
+	ipAddr string,
+	port int,
+	timeout time.Duration,
+	opts CertRetrievalOptions,
+	stats *CertRetrievalStats,
+	logger zerolog.Logger,
+) ([]*x509.Certificate, error) {
+
+	var certChain []*x509.Certificate
+
+	logger.Debug().Msg("Connecting to remote server")
+
+	dialTimeout := opts.dialTimeout(timeout)
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer dialCancel()
 
 	serverConnStr := net.JoinHostPort(ipAddr, strconv.Itoa(port))
-	conn, connErr := tls.DialWithDialer(dialer, "tcp", serverConnStr, &tlsConfig)
+	connectStart := time.Now()
+	rawConn, connErr := opts.dialer(dialTimeout).DialContext(dialCtx, "tcp", serverConnStr)
+	stats.ConnectTime = time.Since(connectStart)
+	stats.HandshakeTime = 0
+	stats.TLSVersion = 0
+	stats.CipherSuite = 0
+	stats.ServerName = ""
 	if connErr != nil {
-		// logger.Error().Err(connErr).Msgf("error connecting to server")
 		return nil, fmt.Errorf(
 			"error connecting to server (host: %s, IP: %s): %w",
 			host,
 			ipAddr,
-			connErr,
+			phaseErr(PhaseTCPConnect, dialTimeout, connErr),
+		)
+	}
+
+	handshakeTimeout := opts.handshakeTimeout(timeout)
+	handshakeCtx, handshakeCancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer handshakeCancel()
+
+	conn := tls.Client(rawConn, opts.tlsConfig(host))
+	handshakeStart := time.Now()
+	handshakeErr := conn.HandshakeContext(handshakeCtx)
+	stats.HandshakeTime = time.Since(handshakeStart)
+	if handshakeErr != nil {
+		_ = rawConn.Close()
+
+		return nil, fmt.Errorf(
+			"error connecting to server (host: %s, IP: %s): %w",
+			host,
+			ipAddr,
+			phaseErr(PhaseTLSHandshake, handshakeTimeout, handshakeErr),
 		)
 	}
-	logger.Debug().Msg("Connected")
+	logger.Debug().
+		Str("connect_time", stats.ConnectTime.String()).
+		Str("handshake_time", stats.HandshakeTime.String()).
+		Msg("Connected")
+
+	// grab certificate chain as presented by remote peer along with the
+	// negotiated connection parameters
+	connState := conn.ConnectionState()
+	certChain = connState.PeerCertificates
+	stats.TLSVersion = connState.Version
+	stats.CipherSuite = connState.CipherSuite
+
+	// IP Address values are not sent via SNI.
+	if net.ParseIP(connState.ServerName) == nil {
+		stats.ServerName = connState.ServerName
+	}
 
-	// grab certificate chain as presented by remote peer
-	certChain = conn.ConnectionState().PeerCertificates
 	logger.Debug().
 		Int("certs", len(certChain)).
 		Msg("Retrieved certificate chain")
@@ -304,6 +537,55 @@ func GetCerts(host string, ipAddr string, port int, timeout time.Duration, logge
 	return certChain, nil
 }
 
+// phaseErr annotates the given error with the connection phase in which it
+// occurred, noting the applied timeout if the phase timed out.
+func phaseErr(phase string, timeout time.Duration, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%s timed out after %v: %w", phase, timeout, err)
+	}
+
+	return fmt.Errorf("%s failed: %w", phase, err)
+}
+
+// isTransientFailure indicates whether the given error is the result of a
+// (potentially) transient network failure such as a connection timeout,
+// dropped packet or reset connection for which a retry attempt may succeed.
+func isTransientFailure(err error) bool {
+	switch {
+	case IsConnectionFailure(err):
+		return true
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.Is(err, syscall.ECONNRESET):
+		return true
+	default:
+		return false
+	}
+}
+
+// IsConnectionFailure indicates whether the given error is the result of a
+// failure to establish or maintain a network connection (e.g., connection
+// refused, no route to host, timeout) as opposed to a problem with the
+// remote service itself (e.g., a TLS handshake failure).
+func IsConnectionFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
 // IsCIDR indicates whether a specified string is a CIDR notation IP address
 // and prefix length, like "192.0.2.0/24" or "2001:db8::/32", as defined in
 // RFC 4632 and RFC 4291.
@@ -332,242 +614,318 @@ func isIPv4AddrCandidate(s string) bool {
 	return err == nil
 }
 
-// ExpandHost accepts a host pattern as a string value that represents either
-// an individual IP Address, a CIDR IP range or a partial (dash-separated)
-// range (e.g., 192.168.2.10-15) and returns a HostPattern value. The
-// HostPattern value represents the original host pattern and a collection of
-// IP Addresses expanded from the original pattern.
+// ExpandOctetRange expands the given IPv4 Address pattern using a partial
+// implementation of nmap's octet range addressing (e.g., 192.168.2.10-15 or
+// 192.168.1-15.10-12) and returns the IP Addresses within the range. Any
+// octet may specify a dash-separated range; a pattern without a range is
+// rejected.
 //
-// An error is returned if an invalid host pattern is provided (e.g., invalid
-// IP Address range) or if it fails name resolution (e.g., invalid hostname or
-// FQDN).
-func ExpandHost(hostPattern string) (HostPattern, error) {
+// An error is returned if the pattern does not contain four octets, if an
+// octet is non-numeric or outside of the 0-255 bounds or if the start of an
+// octet range is not less than the end.
+func ExpandOctetRange(pattern string) ([]string, error) {
+	octets := strings.Split(pattern, ".")
 
-	switch {
+	if len(octets) != 4 {
+		return nil, fmt.Errorf(
+			"%q (%d octets) not IPv4 Address; does not contain 4 octets: %w",
+			pattern,
+			len(octets),
+			ErrUnrecognizedIPAddress,
+		)
+	}
 
-	// assume that user specified a CIDR mask
-	case strings.Contains(hostPattern, "/"):
+	// fmt.Printf("%q is a potential IP Address range\n", s)
 
-		if IsCIDR(hostPattern) {
-			ipAddrs, _, err := CIDRHosts(hostPattern)
-			if err != nil {
-				return HostPattern{}, fmt.Errorf("error parsing CIDR range: %w", err)
-			}
-			// fmt.Printf("%q is a CIDR rangeof %d IPs\n", s, count)
+	// A single IP Address is not a range; we are either dealing with a
+	// partial range or invalid value.
 
-			return HostPattern{
-				Given:    hostPattern,
-				Expanded: ipAddrs,
-				Range:    true,
-			}, nil
-		}
+	// check for dash character used to specify partial IP range
+	if !strings.Contains(pattern, "-") {
+		return nil, fmt.Errorf(
+			"%q not IP Address range; does not contain dash character: %w",
+			pattern,
+			ErrUnrecognizedIPRange,
+		)
+	}
 
-		return HostPattern{}, fmt.Errorf("%q contains slash, but fails CIDR parsing", hostPattern)
+	ipAddrOctIdx := make(IPv4AddressOctetsIndex)
 
-	// valid (presumably single) IPv4 or IPv6 address
-	case net.ParseIP(hostPattern) != nil:
+	// Validate each octet of IP Address pattern.
+	for octIdx := range octets {
 
-		// fmt.Printf("%q is an IP Address\n", s)
-		return HostPattern{
-			Given:    hostPattern,
-			Expanded: []string{hostPattern},
-		}, nil
-
-	// no CIDR mask, not a single IP Address (earlier check would have
-	// triggered), and so potentially a partial range of IPv4 Addresses
-	case isIPv4AddrCandidate(hostPattern) && strings.Contains(hostPattern, "."):
+		// split on dashes, loop over that
+		halves := strings.Split(octets[octIdx], "-")
 
-		octets := strings.Split(hostPattern, ".")
+		switch {
 
-		if len(octets) != 4 {
-			return HostPattern{}, fmt.Errorf(
-				"%q (%d octets) not IPv4 Address; does not contain 4 octets: %w",
-				hostPattern,
-				len(octets),
-				ErrUnrecognizedIPAddress,
-			)
-		}
+		// dash is not present
+		case len(halves) == 1:
 
-		// fmt.Printf("%q is a potential IP Address range\n", s)
+			// fmt.Printf("DEBUG: octet %d does not have a dash\n", octIdx)
 
-		// reminder: at this point single IP Address was handled by earlier
-		// switch case. We are either dealing with a partial range or invalid
-		// value.
+			num, err := strconv.Atoi(halves[0])
+			if err != nil {
+				return nil, fmt.Errorf(
+					"octet %q of IP pattern %q invalid; non-numeric values present: %w",
+					octets[octIdx],
+					pattern,
+					ErrUnrecognizedIPRange,
+				)
+			}
 
-		// check for dash character used to specify partial IP range
-		if !strings.Contains(hostPattern, "-") {
-			return HostPattern{}, fmt.Errorf(
-				"%q not IP Address range; does not contain dash character: %w",
-				hostPattern,
-				ErrUnrecognizedIPRange,
-			)
-		}
+			if !octetWithinBounds(num) {
+				return nil, fmt.Errorf(
+					"octet %q of IP pattern %q outside lower (0), upper (255) bounds: %w",
+					octets[octIdx],
+					pattern,
+					ErrUnrecognizedIPRange,
+				)
+			}
 
-		ipAddrOctIdx := make(IPv4AddressOctetsIndex)
+			// extend values for this octet
+			ipAddrOctIdx[octIdx] = append(ipAddrOctIdx[octIdx], num)
 
-		// Validate each octet of IP Address pattern.
-		for octIdx := range octets {
+			// fmt.Printf("DEBUG: %+v\n", ipAddrOctIdx)
 
-			// split on dashes, loop over that
-			halves := strings.Split(octets[octIdx], "-")
+		// one dash present, this is a range separator
+		case len(halves) == 2:
 
-			switch {
+			rangeStart, err := strconv.Atoi(halves[0])
+			if err != nil {
+				return nil, fmt.Errorf(
+					"octet %q of IP pattern %q invalid; non-numeric values present: %w",
+					octets[octIdx],
+					pattern,
+					ErrUnrecognizedIPRange,
+				)
+			}
 
-			// dash is not present
-			case len(halves) == 1:
+			rangeEnd, err := strconv.Atoi(halves[1])
+			if err != nil {
+				return nil, fmt.Errorf(
+					"octet %q of IP pattern %q invalid; non-numeric values present: %w",
+					octets[octIdx],
+					pattern,
+					ErrUnrecognizedIPRange,
+				)
+			}
 
-				// fmt.Printf("DEBUG: octet %d does not have a dash\n", octIdx)
+			switch {
+			case rangeStart > rangeEnd:
+				return nil, fmt.Errorf(
+					"%q is invalid octet range; "+
+						"given start value %d greater than end value %d: %w",
+					octets[octIdx],
+					rangeStart,
+					rangeEnd,
+					ErrUnrecognizedIPRange,
+				)
 
-				num, err := strconv.Atoi(halves[0])
-				if err != nil {
-					return HostPattern{}, fmt.Errorf(
-						"octet %q of IP pattern %q invalid; non-numeric values present: %w",
-						octets[octIdx],
-						hostPattern,
-						ErrUnrecognizedIPRange,
-					)
-				}
+			case rangeStart == rangeEnd:
+				return nil, fmt.Errorf(
+					"%q is invalid octet range; "+
+						"given start value %d equal to end value %d: %w",
+					octets[octIdx],
+					rangeStart,
+					rangeEnd,
+					ErrUnrecognizedIPRange,
+				)
+			}
 
-				if !octetWithinBounds(num) {
-					return HostPattern{}, fmt.Errorf(
+			for i := rangeStart; i <= rangeEnd; i++ {
+				if !octetWithinBounds(i) {
+					return nil, fmt.Errorf(
 						"octet %q of IP pattern %q outside lower (0), upper (255) bounds: %w",
 						octets[octIdx],
-						hostPattern,
+						pattern,
 						ErrUnrecognizedIPRange,
 					)
 				}
 
 				// extend values for this octet
-				ipAddrOctIdx[octIdx] = append(ipAddrOctIdx[octIdx], num)
-
+				ipAddrOctIdx[octIdx] = append(ipAddrOctIdx[octIdx], i)
 				// fmt.Printf("DEBUG: %+v\n", ipAddrOctIdx)
+			}
 
-			// one dash present, this is a range separator
-			case len(halves) == 2:
+		// more than one dash present in octet, malformed range
+		default:
 
-				rangeStart, err := strconv.Atoi(halves[0])
-				if err != nil {
-					return HostPattern{}, fmt.Errorf(
-						"octet %q of IP pattern %q invalid; non-numeric values present: %w",
-						octets[octIdx],
-						hostPattern,
-						ErrUnrecognizedIPRange,
-					)
-				}
+			numDashes := strings.Count(octets[octIdx], "-")
+			return nil, fmt.Errorf(
+				"%d dash separators in octet %q (%d of %d); expected one: %w",
+				numDashes,
+				octIdx,
+				octIdx+1,
+				len(octets),
+				ErrUnrecognizedIPRange,
+			)
+		}
 
-				rangeEnd, err := strconv.Atoi(halves[1])
-				if err != nil {
-					return HostPattern{}, fmt.Errorf(
-						"octet %q of IP pattern %q invalid; non-numeric values present: %w",
-						octets[octIdx],
-						hostPattern,
-						ErrUnrecognizedIPRange,
-					)
-				}
+	}
 
-				switch {
-				case rangeStart > rangeEnd:
-					return HostPattern{}, fmt.Errorf(
-						"%q is invalid octet range; "+
-							"given start value %d greater than end value %d: %w",
-						octets[octIdx],
-						rangeStart,
-						rangeEnd,
-						ErrUnrecognizedIPRange,
-					)
+	// internal state validity check
+	if len(ipAddrOctIdx) != len(octets) {
+		return nil, fmt.Errorf(
+			"ipAddress octet map size incorrect; got %d, wanted %d: %w",
+			len(ipAddrOctIdx),
+			len(octets),
+			ErrIPAddrOctectIdxValidityFailure,
+		)
+	}
 
-				case rangeStart == rangeEnd:
-					return HostPattern{}, fmt.Errorf(
-						"%q is invalid octet range; "+
-							"given start value %d equal to end value %d: %w",
-						octets[octIdx],
-						rangeStart,
-						rangeEnd,
-						ErrUnrecognizedIPRange,
-					)
-				}
+	expandedIPAddresses := make([]string, 0, 1024)
+	for i := range ipAddrOctIdx[0] {
+		// Example IP: 192.168.5.10
+		// 192(w).168(x).5(y).10(z)
+
+		w := strconv.Itoa(ipAddrOctIdx[0][i])
 
-				for i := rangeStart; i <= rangeEnd; i++ {
-					if !octetWithinBounds(i) {
-						return HostPattern{}, fmt.Errorf(
-							"octet %q of IP pattern %q outside lower (0), upper (255) bounds: %w",
-							octets[octIdx],
-							hostPattern,
-							ErrUnrecognizedIPRange,
+		for j := range ipAddrOctIdx[1] {
+			x := strconv.Itoa(ipAddrOctIdx[1][j])
+
+			for k := range ipAddrOctIdx[2] {
+				y := strconv.Itoa(ipAddrOctIdx[2][k])
+
+				for l := range ipAddrOctIdx[3] {
+					z := strconv.Itoa(ipAddrOctIdx[3][l])
+
+					// fmt.Println(strings.Join([]string{w, x, y, z}, "."))
+					ipAddrString := strings.Join([]string{w, x, y, z}, ".")
+
+					// TODO: Is this worth failing execution, or should we
+					// emit a WARNING level message instead? Probably best
+					// to implement a specific error type that we can
+					// match on to determine severity.
+					if net.ParseIP(ipAddrString) == nil {
+						return nil, fmt.Errorf(
+							"%q (from parsed range) invalid: %w",
+							ipAddrString,
+
+							// TODO: Do we need a more specific error?
+							ErrUnrecognizedIPAddress,
 						)
 					}
 
-					// extend values for this octet
-					ipAddrOctIdx[octIdx] = append(ipAddrOctIdx[octIdx], i)
-					// fmt.Printf("DEBUG: %+v\n", ipAddrOctIdx)
+					// Collect expanded IP Addresses for IP Address range
+					expandedIPAddresses = append(
+						expandedIPAddresses, ipAddrString,
+					)
 				}
+			}
+		}
+	}
 
-			// more than one dash present in octet, malformed range
-			default:
+	return expandedIPAddresses, nil
+}
 
-				numDashes := strings.Count(octets[octIdx], "-")
-				return HostPattern{}, fmt.Errorf(
-					"%d dash separators in octet %q (%d of %d); expected one: %w",
-					numDashes,
-					octIdx,
-					octIdx+1,
-					len(octets),
-					ErrUnrecognizedIPRange,
-				)
+// ExpandHost accepts a host pattern as a string value that represents either
+// an individual IP Address, a CIDR IP range or a partial (dash-separated)
+// range (e.g., 192.168.2.10-15 or 2001:db8::10-1f) and returns a HostPattern
+// value. The
+// HostPattern value represents the original host pattern and a collection of
+// IP Addresses expanded from the original pattern.
+//
+// An error is returned if an invalid host pattern is provided (e.g., invalid
+// IP Address range) or if it fails name resolution (e.g., invalid hostname or
+// FQDN).
+//
+// IPv6 Addresses may be enclosed in square brackets (e.g., [2001:db8::1]) and
+// may include a zone (e.g., fe80::1%eth0). IP Addresses expanded from an IPv6
+// range are limited to the first MaxIPv6RangeHosts addresses.
+func ExpandHost(hostPattern string) (HostPattern, error) {
+	return ExpandHostWithTimeout(hostPattern, 0)
+}
+
+// ExpandHostWithTimeout behaves like ExpandHost, but abandons name resolution
+// of a hostname or FQDN if it is not completed within the given timeout. A
+// timeout of zero applies no limit beyond that of the system resolver.
+func ExpandHostWithTimeout(hostPattern string, timeout time.Duration) (HostPattern, error) {
+	return ExpandHostWithResolver(hostPattern, net.DefaultResolver, timeout)
+}
+
+// ExpandHostWithResolver behaves like ExpandHostWithTimeout, but uses the
+// given resolver (e.g., one created by NewResolver) for name resolution of a
+// hostname or FQDN. The system resolver is used if a resolver is not
+// provided.
+func ExpandHostWithResolver(hostPattern string, resolver *net.Resolver, timeout time.Duration) (HostPattern, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	switch {
+
+	// assume that user specified a CIDR mask
+	case strings.Contains(hostPattern, "/"):
+
+		if IsCIDR(hostPattern) {
+			ipAddrs, truncated, err := cidrHosts(hostPattern)
+			if err != nil {
+				return HostPattern{}, fmt.Errorf("error parsing CIDR range: %w", err)
 			}
+			// fmt.Printf("%q is a CIDR rangeof %d IPs\n", s, count)
 
+			return HostPattern{
+				Given:     hostPattern,
+				Expanded:  ipAddrs,
+				Range:     true,
+				Truncated: truncated,
+			}, nil
 		}
 
-		// internal state validity check
-		if len(ipAddrOctIdx) != len(octets) {
-			return HostPattern{}, fmt.Errorf(
-				"ipAddress octet map size incorrect; got %d, wanted %d: %w",
-				len(ipAddrOctIdx),
-				len(octets),
-				ErrIPAddrOctectIdxValidityFailure,
-			)
-		}
+		return HostPattern{}, fmt.Errorf("%q contains slash, but fails CIDR parsing", hostPattern)
+
+	// valid (presumably single) IPv4 or IPv6 address
+	case net.ParseIP(hostPattern) != nil:
 
-		expandedIPAddresses := make([]string, 0, 1024)
-		for i := range ipAddrOctIdx[0] {
-			// Example IP: 192.168.5.10
-			// 192(w).168(x).5(y).10(z)
+		// fmt.Printf("%q is an IP Address\n", s)
+		return HostPattern{
+			Given:    hostPattern,
+			Expanded: []string{hostPattern},
+		}, nil
 
-			w := strconv.Itoa(ipAddrOctIdx[0][i])
+	// not a plain IPv6 Address (earlier check would have triggered), so
+	// potentially a bracketed or zoned IPv6 Address or a partial range of
+	// IPv6 Addresses; hostnames and FQDNs do not contain colons
+	case strings.Contains(hostPattern, ":"):
 
-			for j := range ipAddrOctIdx[1] {
-				x := strconv.Itoa(ipAddrOctIdx[1][j])
+		ipv6Pattern := strings.TrimSuffix(strings.TrimPrefix(hostPattern, "["), "]")
 
-				for k := range ipAddrOctIdx[2] {
-					y := strconv.Itoa(ipAddrOctIdx[2][k])
+		if strings.Contains(ipv6Pattern, "-") {
+			ipAddrs, truncated, err := ipv6RangeHosts(ipv6Pattern)
+			if err != nil {
+				return HostPattern{}, err
+			}
 
-					for l := range ipAddrOctIdx[3] {
-						z := strconv.Itoa(ipAddrOctIdx[3][l])
+			return HostPattern{
+				Given:     hostPattern,
+				Expanded:  ipAddrs,
+				Range:     true,
+				Truncated: truncated,
+			}, nil
+		}
 
-						// fmt.Println(strings.Join([]string{w, x, y, z}, "."))
-						ipAddrString := strings.Join([]string{w, x, y, z}, ".")
+		addr, err := netip.ParseAddr(ipv6Pattern)
+		if err != nil || !addr.Is6() {
+			return HostPattern{}, fmt.Errorf(
+				"%q not IPv6 Address: %w",
+				hostPattern,
+				ErrUnrecognizedIPAddress,
+			)
+		}
 
-						// TODO: Is this worth failing execution, or should we
-						// emit a WARNING level message instead? Probably best
-						// to implement a specific error type that we can
-						// match on to determine severity.
-						if net.ParseIP(ipAddrString) == nil {
-							return HostPattern{}, fmt.Errorf(
-								"%q (from parsed range) invalid: %w",
-								ipAddrString,
+		return HostPattern{
+			Given:    hostPattern,
+			Expanded: []string{addr.String()},
+		}, nil
 
-								// TODO: Do we need a more specific error?
-								ErrUnrecognizedIPAddress,
-							)
-						}
+	// no CIDR mask, not a single IP Address (earlier check would have
+	// triggered), and so potentially a partial range of IPv4 Addresses
+	case isIPv4AddrCandidate(hostPattern) && strings.Contains(hostPattern, "."):
 
-						// Collect expanded IP Addresses for IP Address range
-						expandedIPAddresses = append(
-							expandedIPAddresses, ipAddrString,
-						)
-					}
-				}
-			}
+		expandedIPAddresses, err := ExpandOctetRange(hostPattern)
+		if err != nil {
+			return HostPattern{}, err
 		}
 
 		return HostPattern{
@@ -584,25 +942,66 @@ func ExpandHost(hostPattern string) (HostPattern, error) {
 		// successful, indicate as much. The given host pattern can be used to
 		// provide SNI support for valid cert retrieval (instead of just the
 		// default cert on a port).
-		ipAddrs, lookupErr := net.LookupHost(hostPattern)
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		resolveStart := time.Now()
+		ipAddrs, lookupErr := resolver.LookupHost(ctx, hostPattern)
+		resolveTime := time.Since(resolveStart)
 		if lookupErr != nil {
 			return HostPattern{}, fmt.Errorf(
 				"%q invalid; %w: %w",
 				hostPattern,
 				ErrHostnameFailsNameResolution,
-				lookupErr,
+				phaseErr(PhaseDNSResolution, timeout, lookupErr),
 			)
 		}
 
 		return HostPattern{
-			Given:    hostPattern,
-			Expanded: ipAddrs,
-			Resolved: true,
+			Given:       hostPattern,
+			Expanded:    ipAddrs,
+			Resolved:    true,
+			ResolveTime: resolveTime,
 		}, nil
 
 	}
 }
 
+// ResolvedNamesIndex returns an index of IP Addresses to the hostname or
+// FQDN values from the given host patterns which resolved to them. Each list
+// of names is sorted and free of duplicates. IP Addresses not resolved from
+// a hostname or FQDN are not included.
+func ResolvedNamesIndex(hosts []HostPattern) map[string][]string {
+	index := make(map[string][]string)
+	seen := make(map[string]struct{})
+
+	for _, host := range hosts {
+		if !host.Resolved {
+			continue
+		}
+
+		for _, ipAddr := range host.Expanded {
+			key := ipAddr + " " + strings.ToLower(host.Given)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			index[ipAddr] = append(index[ipAddr], host.Given)
+		}
+	}
+
+	for ipAddr := range index {
+		sort.Strings(index[ipAddr])
+	}
+
+	return index
+}
+
 // DedupeHosts accepts a collection of HostPattern values and returns an
 // unordered, but deduped/unique collection of HostPattern values.
 //
@@ -628,11 +1027,116 @@ func DedupeHosts(hosts []HostPattern) []HostPattern {
 	uniqHosts := make([]HostPattern, 0, len(hosts))
 
 	for _, host := range hosts {
-		if _, inMap := uniqHostsIdx[host.Given]; !inMap {
+		// Host patterns with different port overrides are kept.
+		key := fmt.Sprintf("%s %v", host.Given, host.Ports)
+
+		if _, inMap := uniqHostsIdx[key]; !inMap {
 			uniqHosts = append(uniqHosts, host)
 		}
-		uniqHostsIdx[host.Given] = host
+		uniqHostsIdx[key] = host
 	}
 
 	return uniqHosts
 }
+
+// ExcludeHosts removes IP Addresses matching the given exclusion host
+// patterns from the given (expanded) host patterns. Host patterns left
+// without any IP Addresses are removed. The filtered host patterns are
+// returned along with the unique IP Addresses which were removed.
+//
+// CIDR exclusion patterns are matched by range (regardless of size). A
+// hostname or FQDN exclusion pattern removes the IP Addresses it resolved to
+// along with any host pattern specifying the same name. Other exclusion
+// patterns (single IP Addresses and partial ranges) are matched against
+// their expanded IP Addresses.
+func ExcludeHosts(hosts []HostPattern, exclusions []HostPattern) ([]HostPattern, []string) {
+	if len(exclusions) == 0 {
+		return hosts, nil
+	}
+
+	var excludedPrefixes []netip.Prefix
+	excludedAddrs := make(map[string]struct{})
+	excludedNames := make(map[string]struct{})
+
+	for _, exclusion := range exclusions {
+		if prefix, err := netip.ParsePrefix(exclusion.Given); err == nil {
+			excludedPrefixes = append(excludedPrefixes, prefix.Masked())
+
+			continue
+		}
+
+		if exclusion.Resolved {
+			excludedNames[strings.ToLower(exclusion.Given)] = struct{}{}
+		}
+
+		for _, ipAddr := range exclusion.Expanded {
+			excludedAddrs[normalizeIPAddr(ipAddr)] = struct{}{}
+		}
+	}
+
+	isExcluded := func(ipAddr string) bool {
+		if _, ok := excludedAddrs[normalizeIPAddr(ipAddr)]; ok {
+			return true
+		}
+
+		addr, err := netip.ParseAddr(ipAddr)
+		if err != nil {
+			return false
+		}
+		addr = addr.WithZone("").Unmap()
+
+		for _, prefix := range excludedPrefixes {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var removed []string
+	removedIdx := make(map[string]struct{})
+	recordRemoved := func(ipAddr string) {
+		if _, ok := removedIdx[ipAddr]; !ok {
+			removedIdx[ipAddr] = struct{}{}
+			removed = append(removed, ipAddr)
+		}
+	}
+
+	filtered := make([]HostPattern, 0, len(hosts))
+	for _, host := range hosts {
+		_, nameExcluded := excludedNames[strings.ToLower(host.Given)]
+
+		kept := make([]string, 0, len(host.Expanded))
+		for _, ipAddr := range host.Expanded {
+			if nameExcluded || isExcluded(ipAddr) {
+				recordRemoved(ipAddr)
+
+				continue
+			}
+			kept = append(kept, ipAddr)
+		}
+
+		if len(kept) == 0 {
+			continue
+		}
+
+		host.Expanded = kept
+		filtered = append(filtered, host)
+	}
+
+	return filtered, removed
+}
+
+// normalizeIPAddr returns the canonical form of the given IP Address (e.g.,
+// without a zone and with IPv4-mapped IPv6 Addresses unmapped) for
+// comparison purposes. The given value is returned as-is if it cannot be
+// parsed.
+func normalizeIPAddr(ipAddr string) string {
+	addr, err := netip.ParseAddr(ipAddr)
+	if err != nil {
+		return ipAddr
+	}
+
+	return addr.WithZone("").Unmap().String()
+}
@@ -0,0 +1,416 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExpandOctetRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected []string
+		err      error
+	}{
+		{
+			name:    "LastOctetRange",
+			pattern: "192.168.2.10-12",
+			expected: []string{
+				"192.168.2.10",
+				"192.168.2.11",
+				"192.168.2.12",
+			},
+		},
+		{
+			name:    "MultipleOctetRanges",
+			pattern: "192.168.1-2.10-11",
+			expected: []string{
+				"192.168.1.10",
+				"192.168.1.11",
+				"192.168.2.10",
+				"192.168.2.11",
+			},
+		},
+		{
+			name:    "FirstOctetRange",
+			pattern: "10-11.0.0.1",
+			expected: []string{
+				"10.0.0.1",
+				"11.0.0.1",
+			},
+		},
+		{
+			name:    "FullOctetBounds",
+			pattern: "192.168.2.254-255",
+			expected: []string{
+				"192.168.2.254",
+				"192.168.2.255",
+			},
+		},
+		{
+			name:    "SingleIPAddress",
+			pattern: "192.168.2.10",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "TooFewOctets",
+			pattern: "192.168.2-3",
+			err:     ErrUnrecognizedIPAddress,
+		},
+		{
+			name:    "TooManyOctets",
+			pattern: "192.168.2.10.1-3",
+			err:     ErrUnrecognizedIPAddress,
+		},
+		{
+			name:    "NonNumericOctet",
+			pattern: "192.168.x.1-3",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "OctetOutOfBounds",
+			pattern: "192.168.256.1-3",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "OctetRangeOutOfBounds",
+			pattern: "192.168.2.250-256",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "ReversedOctetRange",
+			pattern: "192.168.2.15-10",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "EqualOctetRange",
+			pattern: "192.168.2.10-10",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "MultipleDashesInOctet",
+			pattern: "192.168.2.1-5-10",
+			err:     ErrUnrecognizedIPRange,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandOctetRange(tt.pattern)
+
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Errorf("want error %v for %q; got %v", tt.err, tt.pattern, err)
+				}
+
+			case err != nil:
+				t.Errorf("want no error for %q; got %v", tt.pattern, err)
+
+			case !reflect.DeepEqual(got, tt.expected):
+				t.Errorf("want %v for %q; got %v", tt.expected, tt.pattern, got)
+			}
+		})
+	}
+}
+
+func TestExpandHostOctetRange(t *testing.T) {
+	host, err := ExpandHost("192.168.1-2.10-11")
+	if err != nil {
+		t.Fatalf("want no error; got %v", err)
+	}
+
+	if !host.Range {
+		t.Error("want host pattern flagged as range")
+	}
+
+	if len(host.Expanded) != 4 {
+		t.Errorf("want 4 expanded IP Addresses; got %d", len(host.Expanded))
+	}
+}
+
+func TestExpandHostIPv6(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		expected  []string
+		count     int
+		truncated bool
+		err       error
+	}{
+		{
+			name:     "BracketedAddress",
+			pattern:  "[2001:db8::1]",
+			expected: []string{"2001:db8::1"},
+		},
+		{
+			name:     "ZonedAddress",
+			pattern:  "fe80::1%eth0",
+			expected: []string{"fe80::1%eth0"},
+		},
+		{
+			name:     "SingleAddressCIDR",
+			pattern:  "2001:db8::1/128",
+			expected: []string{"2001:db8::1"},
+		},
+		{
+			name:    "SmallCIDR",
+			pattern: "2001:db8::/126",
+			expected: []string{
+				"2001:db8::1",
+				"2001:db8::2",
+				"2001:db8::3",
+			},
+		},
+		{
+			name:      "LargeCIDR",
+			pattern:   "2001:db8::/64",
+			count:     MaxIPv6RangeHosts,
+			truncated: true,
+		},
+		{
+			name:    "LastGroupRange",
+			pattern: "2001:db8::10-12",
+			expected: []string{
+				"2001:db8::10",
+				"2001:db8::11",
+				"2001:db8::12",
+			},
+		},
+		{
+			name:    "BracketedLastGroupRange",
+			pattern: "[2001:db8::10-11]",
+			expected: []string{
+				"2001:db8::10",
+				"2001:db8::11",
+			},
+		},
+		{
+			name:    "CompleteAddressRange",
+			pattern: "2001:db8::ff-2001:db8::101",
+			expected: []string{
+				"2001:db8::ff",
+				"2001:db8::100",
+				"2001:db8::101",
+			},
+		},
+		{
+			name:    "ZonedLastGroupRange",
+			pattern: "fe80::10%eth0-12",
+			expected: []string{
+				"fe80::10%eth0",
+				"fe80::11%eth0",
+				"fe80::12%eth0",
+			},
+		},
+		{
+			name:    "ZonedCompleteAddressRange",
+			pattern: "fe80::10%eth0-fe80::11%eth0",
+			expected: []string{
+				"fe80::10%eth0",
+				"fe80::11%eth0",
+			},
+		},
+		{
+			name:      "LargeRange",
+			pattern:   "2001:db8::-2001:db8::1:0",
+			count:     MaxIPv6RangeHosts,
+			truncated: true,
+		},
+		{
+			name:    "MismatchedZoneRange",
+			pattern: "fe80::10%eth0-fe80::11%eth1",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "ReversedRange",
+			pattern: "2001:db8::12-10",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "EqualRange",
+			pattern: "2001:db8::10-10",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "InvalidRangeEnd",
+			pattern: "2001:db8::10-zz",
+			err:     ErrUnrecognizedIPRange,
+		},
+		{
+			name:    "InvalidAddress",
+			pattern: "2001:db8::zz",
+			err:     ErrUnrecognizedIPAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			host, err := ExpandHost(tt.pattern)
+
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Errorf("want error %v for %q; got %v", tt.err, tt.pattern, err)
+				}
+
+				return
+
+			case err != nil:
+				t.Fatalf("want no error for %q; got %v", tt.pattern, err)
+			}
+
+			if host.Truncated != tt.truncated {
+				t.Errorf("want truncated %t for %q; got %t", tt.truncated, tt.pattern, host.Truncated)
+			}
+
+			switch {
+			case tt.expected != nil && !reflect.DeepEqual(host.Expanded, tt.expected):
+				t.Errorf("want %v for %q; got %v", tt.expected, tt.pattern, host.Expanded)
+
+			case tt.expected == nil && len(host.Expanded) != tt.count:
+				t.Errorf("want %d IP Addresses for %q; got %d", tt.count, tt.pattern, len(host.Expanded))
+			}
+		})
+	}
+}
+
+func TestCIDRHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		count   int
+		err     error
+	}{
+		{
+			name:    "IPv4Network",
+			pattern: "192.168.2.0/30",
+			count:   2,
+		},
+		{
+			name:    "IPv6Network",
+			pattern: "2001:db8::/112",
+			count:   MaxIPv6RangeHosts - 1,
+		},
+		{
+			name:    "IPv6NetworkTooLarge",
+			pattern: "2001:db8::/64",
+			err:     ErrIPv6RangeTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ips, count, err := CIDRHosts(tt.pattern)
+
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Errorf("want error %v for %q; got %v", tt.err, tt.pattern, err)
+				}
+
+			case err != nil:
+				t.Errorf("want no error for %q; got %v", tt.pattern, err)
+
+			case count != tt.count || len(ips) != tt.count:
+				t.Errorf("want %d hosts for %q; got %d (%d listed)", tt.count, tt.pattern, count, len(ips))
+			}
+		})
+	}
+}
+
+func TestExcludeHosts(t *testing.T) {
+	hosts := []HostPattern{
+		{Given: "192.168.2.0/29", Expanded: []string{
+			"192.168.2.1", "192.168.2.2", "192.168.2.3",
+			"192.168.2.4", "192.168.2.5", "192.168.2.6",
+		}, Range: true},
+		{Given: "printer.example.com", Expanded: []string{"192.168.3.10"}, Resolved: true},
+		{Given: "2001:db8::1", Expanded: []string{"2001:db8::1"}},
+	}
+
+	tests := []struct {
+		name            string
+		exclusions      []HostPattern
+		expectedHosts   []string
+		expectedRemoved []string
+	}{
+		{
+			name:            "NoExclusions",
+			expectedHosts:   []string{"192.168.2.0/29", "printer.example.com", "2001:db8::1"},
+			expectedRemoved: nil,
+		},
+		{
+			name: "SingleIPAddress",
+			exclusions: []HostPattern{
+				{Given: "192.168.2.3", Expanded: []string{"192.168.2.3"}},
+			},
+			expectedHosts:   []string{"192.168.2.0/29", "printer.example.com", "2001:db8::1"},
+			expectedRemoved: []string{"192.168.2.3"},
+		},
+		{
+			name: "CIDRRange",
+			exclusions: []HostPattern{
+				{Given: "192.168.2.4/30", Range: true},
+				{Given: "2001:db8::/32", Range: true},
+			},
+			expectedHosts:   []string{"192.168.2.0/29", "printer.example.com"},
+			expectedRemoved: []string{"192.168.2.4", "192.168.2.5", "192.168.2.6", "2001:db8::1"},
+		},
+		{
+			name: "HostnameByName",
+			exclusions: []HostPattern{
+				{Given: "PRINTER.example.com", Expanded: []string{"192.168.9.9"}, Resolved: true},
+			},
+			expectedHosts:   []string{"192.168.2.0/29", "2001:db8::1"},
+			expectedRemoved: []string{"192.168.3.10"},
+		},
+		{
+			name: "HostnameByResolvedIPAddress",
+			exclusions: []HostPattern{
+				{Given: "ilo.example.com", Expanded: []string{"192.168.2.1"}, Resolved: true},
+			},
+			expectedHosts:   []string{"192.168.2.0/29", "printer.example.com", "2001:db8::1"},
+			expectedRemoved: []string{"192.168.2.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed := ExcludeHosts(hosts, tt.exclusions)
+
+			gotHosts := make([]string, 0, len(got))
+			for _, host := range got {
+				gotHosts = append(gotHosts, host.Given)
+			}
+
+			if !reflect.DeepEqual(gotHosts, tt.expectedHosts) {
+				t.Errorf("want hosts %v; got %v", tt.expectedHosts, gotHosts)
+			}
+
+			if !reflect.DeepEqual(removed, tt.expectedRemoved) {
+				t.Errorf("want removed %v; got %v", tt.expectedRemoved, removed)
+			}
+		})
+	}
+
+	if len(hosts[0].Expanded) != 6 {
+		t.Errorf("want given host patterns unmodified; got %d IP Addresses", len(hosts[0].Expanded))
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"crypto/tls"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// maxRetryBackoffExponent is the largest exponent applied when calculating
+// the exponential backoff delay between retry attempts.
+const maxRetryBackoffExponent = 10
+
+// Dialer is the interface implemented by types able to establish a network
+// connection (e.g., *net.Dialer or a proxy-aware dialer).
+type Dialer interface {
+	DialContext(ctx context.Context, network string, address string) (net.Conn, error)
+}
+
+// CertRetrievalOptions is the collection of optional settings used to
+// customize how a certificate chain is retrieved. Unspecified settings fall
+// back to the defaults used by GetCerts.
+type CertRetrievalOptions struct {
+	// TLSConfig is an optional TLS client configuration used in place of the
+	// default configuration (e.g., to supply client certificates or custom
+	// root certificates). The configuration is cloned before use and the
+	// target host value is used as the ServerName if one is not already
+	// set.
+	//
+	// NOTE: Unlike the default configuration, certificate verification is
+	// enforced unless InsecureSkipVerify is set. Certificate chains which
+	// fail verification cannot be retrieved for evaluation.
+	TLSConfig *tls.Config
+
+	// Dialer is an optional dialer used in place of the default dialer to
+	// establish the network connection to the target.
+	Dialer Dialer
+
+	// DialTimeout is the time allowed to establish the TCP connection to
+	// the target. The general timeout given to the retrieval function is
+	// used if not specified.
+	DialTimeout time.Duration
+
+	// HandshakeTimeout is the time allowed to complete the TLS handshake
+	// once connected. The general timeout given to the retrieval function is
+	// used if not specified.
+	HandshakeTimeout time.Duration
+
+	// Retries is the number of additional attempts made to retrieve the
+	// certificate chain after a transient connection failure (e.g.,
+	// connection timeout or reset). Retries are disabled if not specified.
+	Retries int
+
+	// RetryDelay is the delay before the first retry attempt. The delay is
+	// doubled for each subsequent retry attempt and a random jitter of up to
+	// half of the delay is added to each.
+	RetryDelay time.Duration
+}
+
+// tlsConfig returns the TLS client configuration to use when connecting to
+// the given host.
+func (opts CertRetrievalOptions) tlsConfig(host string) *tls.Config {
+	if opts.TLSConfig != nil {
+		cfg := opts.TLSConfig.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName = host
+		}
+
+		return cfg
+	}
+
+	return &tls.Config{
+		// Permit insecure connection.
+		//
+		// This is needed so that we can examine not only valid certificates,
+		// but certs that are expired, self-signed or having other properties
+		// which make them invalid. This is also needed so that we can examine
+		// not only the initial certificate, but others in the chain also.
+		// This allows us to flag any intermediate or root certs which may
+		// also be expired.
+		//
+		// Ignore security (gosec) linting warnings re this choice.
+		// nolint:gosec
+		InsecureSkipVerify: true,
+
+		// ServerName is included in the client's handshake to support virtual
+		// hosting. Specifying the value here allows us to connect to a
+		// specific IP Address while also retrieving a certificate chain for a
+		// specific host value.
+		ServerName: host,
+	}
+}
+
+// maxAttempts returns the total number of attempts permitted to retrieve a
+// certificate chain.
+func (opts CertRetrievalOptions) maxAttempts() int {
+	if opts.Retries < 0 {
+		return 1
+	}
+
+	return opts.Retries + 1
+}
+
+// retryDelay returns the delay to wait after the given (failed) attempt
+// before making the next attempt.
+func (opts CertRetrievalOptions) retryDelay(attempt int) time.Duration {
+	if opts.RetryDelay <= 0 {
+		return 0
+	}
+
+	// Cap the exponent to prevent overflow for unreasonably large retry
+	// counts.
+	exp := attempt - 1
+	if exp > maxRetryBackoffExponent {
+		exp = maxRetryBackoffExponent
+	}
+
+	delay := opts.RetryDelay * (1 << exp)
+
+	// Add jitter to prevent synchronized retry attempts.
+	//
+	// nolint:gosec
+	jitter := time.Duration(rand.Int63n(int64(delay)/2 + 1))
+
+	return delay + jitter
+}
+
+// dialTimeout returns the time allowed to establish the TCP connection,
+// falling back to the given general timeout if not specified.
+func (opts CertRetrievalOptions) dialTimeout(timeout time.Duration) time.Duration {
+	if opts.DialTimeout > 0 {
+		return opts.DialTimeout
+	}
+
+	return timeout
+}
+
+// handshakeTimeout returns the time allowed to complete the TLS handshake,
+// falling back to the given general timeout if not specified.
+func (opts CertRetrievalOptions) handshakeTimeout(timeout time.Duration) time.Duration {
+	if opts.HandshakeTimeout > 0 {
+		return opts.HandshakeTimeout
+	}
+
+	return timeout
+}
+
+// dialer returns the dialer to use when connecting to a target.
+func (opts CertRetrievalOptions) dialer(timeout time.Duration) Dialer {
+	if opts.Dialer != nil {
+		return opts.Dialer
+	}
+
+	// Create custom dialer with user-specified timeout value
+	return &net.Dialer{
+		Timeout: timeout,
+	}
+}
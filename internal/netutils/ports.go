@@ -0,0 +1,95 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPortRange indicates that a port or port range could not be
+// parsed.
+var ErrInvalidPortRange = errors.New("invalid port or port range")
+
+// ParsePortsList parses a comma-separated list of ports and dash-separated
+// port ranges (e.g., 443,8000-8100,9443) into the ports listed. Ranges are
+// expanded to each port in the range (inclusive) and duplicate ports are
+// removed, preserving the order in which ports were first listed.
+//
+// Port values are not checked against the valid TCP port range; callers are
+// expected to apply any range validation required.
+func ParsePortsList(value string) ([]int, error) {
+	items := strings.Split(value, ",")
+	ports := make([]int, 0, len(items))
+	seen := make(map[int]struct{}, len(items))
+
+	add := func(port int) {
+		if _, ok := seen[port]; ok {
+			return
+		}
+		seen[port] = struct{}{}
+		ports = append(ports, port)
+	}
+
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+
+		start, end, isRange := strings.Cut(item, "-")
+		if !isRange {
+			port, err := strconv.Atoi(item)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"failed to convert %q to port number: %w",
+					item,
+					ErrInvalidPortRange,
+				)
+			}
+
+			add(port)
+
+			continue
+		}
+
+		rangeStart, startErr := strconv.Atoi(strings.TrimSpace(start))
+		rangeEnd, endErr := strconv.Atoi(strings.TrimSpace(end))
+		switch {
+		case startErr != nil || endErr != nil:
+			return nil, fmt.Errorf(
+				"failed to convert %q to port range; expected start-end: %w",
+				item,
+				ErrInvalidPortRange,
+			)
+
+		case rangeStart > rangeEnd:
+			return nil, fmt.Errorf(
+				"%q is invalid port range; given start value %d greater than end value %d: %w",
+				item,
+				rangeStart,
+				rangeEnd,
+				ErrInvalidPortRange,
+			)
+
+		// Guard against expanding an unreasonably large range before the
+		// caller has a chance to validate the port values.
+		case rangeStart < 0 || rangeEnd > 65535:
+			return nil, fmt.Errorf(
+				"%q is invalid port range; values outside of 0-65535: %w",
+				item,
+				ErrInvalidPortRange,
+			)
+		}
+
+		for port := rangeStart; port <= rangeEnd; port++ {
+			add(port)
+		}
+	}
+
+	return ports, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParsePortsList(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		want        []int
+		errExpected error
+	}{
+		{
+			name:  "SinglePort",
+			value: "443",
+			want:  []int{443},
+		},
+		{
+			name:  "MixedList",
+			value: "443, 8000-8003,9443",
+			want:  []int{443, 8000, 8001, 8002, 8003, 9443},
+		},
+		{
+			name:  "SinglePortRange",
+			value: "8443-8443",
+			want:  []int{8443},
+		},
+		{
+			name:  "DuplicatesRemoved",
+			value: "8001,8000-8002,443,8002",
+			want:  []int{8001, 8000, 8002, 443},
+		},
+		{
+			name:        "ReversedRange",
+			value:       "8100-8000",
+			errExpected: ErrInvalidPortRange,
+		},
+		{
+			name:        "IncompleteRange",
+			value:       "443,8000-",
+			errExpected: ErrInvalidPortRange,
+		},
+		{
+			name:        "RangeOutOfBounds",
+			value:       "1-70000",
+			errExpected: ErrInvalidPortRange,
+		},
+		{
+			name:        "NonNumeric",
+			value:       "https",
+			errExpected: ErrInvalidPortRange,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePortsList(tt.value)
+
+			switch {
+			case tt.errExpected != nil:
+				if !errors.Is(err, tt.errExpected) {
+					t.Errorf("want error %v; got %v", tt.errExpected, err)
+				}
+			case err != nil:
+				t.Errorf("want no error; got %v", err)
+			case !reflect.DeepEqual(got, tt.want):
+				t.Errorf("want %v; got %v", tt.want, got)
+			}
+		})
+	}
+}
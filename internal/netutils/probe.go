@@ -0,0 +1,214 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrUnsupportedTLSVersion indicates that a given TLS protocol version is
+// not recognized.
+var ErrUnsupportedTLSVersion = errors.New("unsupported TLS protocol version")
+
+// tlsVersions is the collection of supported TLS protocol versions in
+// ascending order along with the keywords used to refer to them.
+var tlsVersions = []struct {
+	version uint16
+	keyword string
+}{
+	{version: tls.VersionTLS10, keyword: "1.0"},
+	{version: tls.VersionTLS11, keyword: "1.1"},
+	{version: tls.VersionTLS12, keyword: "1.2"},
+	{version: tls.VersionTLS13, keyword: "1.3"},
+}
+
+// SupportedTLSVersionKeywords returns the keywords (e.g., "1.2") for the
+// supported TLS protocol versions in ascending order.
+func SupportedTLSVersionKeywords() []string {
+	keywords := make([]string, 0, len(tlsVersions))
+	for _, v := range tlsVersions {
+		keywords = append(keywords, v.keyword)
+	}
+
+	return keywords
+}
+
+// ParseTLSVersion converts the given keyword (e.g., "1.2" or "TLS 1.2") to
+// the matching TLS protocol version.
+func ParseTLSVersion(s string) (uint16, error) {
+	keyword := strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "TLS"))
+	for _, v := range tlsVersions {
+		if v.keyword == keyword {
+			return v.version, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%q: %w", s, ErrUnsupportedTLSVersion)
+}
+
+// TLSVersionName returns the human-readable name (e.g., "TLS 1.2") for the
+// given TLS protocol version.
+func TLSVersionName(version uint16) string {
+	for _, v := range tlsVersions {
+		if v.version == version {
+			return "TLS " + v.keyword
+		}
+	}
+
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// TLSVersionsBelow returns the supported TLS protocol versions older than
+// the given version in ascending order.
+func TLSVersionsBelow(version uint16) []uint16 {
+	var versions []uint16
+	for _, v := range tlsVersions {
+		if v.version < version {
+			versions = append(versions, v.version)
+		}
+	}
+
+	return versions
+}
+
+// ProbeTLSVersions attempts a separate TLS handshake with the specified IP
+// Address & port for each of the given TLS protocol versions and returns the
+// versions negotiated by the server. Legacy protocol versions and cipher
+// suites are enabled for each probe so that servers still accepting them are
+// identified.
+//
+// A handshake rejected by the server indicates that the version is not
+// accepted and is not treated as an error. An error is returned if a
+// connection to the server cannot be established.
+func ProbeTLSVersions(
+	host string,
+	ipAddr string,
+	port int,
+	versions []uint16,
+	timeout time.Duration,
+	opts CertRetrievalOptions,
+	logger zerolog.Logger,
+) ([]uint16, error) {
+
+	if strings.TrimSpace(ipAddr) == "" {
+		return nil, fmt.Errorf(
+			"target IP Address not specified: %w",
+			ErrMissingValue,
+		)
+	}
+
+	host = strings.TrimSpace(host)
+
+	accepted := make([]uint16, 0, len(versions))
+
+	for _, version := range versions {
+		probeLogger := logger.With().
+			Str("tls_version", TLSVersionName(version)).
+			Logger()
+
+		ok, err := probeTLSVersion(host, ipAddr, port, version, timeout, opts)
+		switch {
+		case err != nil:
+			return nil, err
+
+		case ok:
+			probeLogger.Debug().Msg("TLS protocol version accepted")
+			accepted = append(accepted, version)
+
+		default:
+			probeLogger.Debug().Msg("TLS protocol version rejected")
+		}
+	}
+
+	return accepted, nil
+}
+
+// probeTLSVersion makes a single attempt to complete a TLS handshake limited
+// to the given TLS protocol version, indicating whether the handshake
+// succeeded.
+func probeTLSVersion(
+	host string,
+	ipAddr string,
+	port int,
+	version uint16,
+	timeout time.Duration,
+	opts CertRetrievalOptions,
+) (bool, error) {
+
+	dialTimeout := opts.dialTimeout(timeout)
+	dialCtx, dialCancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer dialCancel()
+
+	serverConnStr := net.JoinHostPort(ipAddr, strconv.Itoa(port))
+	rawConn, connErr := opts.dialer(dialTimeout).DialContext(dialCtx, "tcp", serverConnStr)
+	if connErr != nil {
+		return false, fmt.Errorf(
+			"error connecting to server (host: %s, IP: %s): %w",
+			host,
+			ipAddr,
+			phaseErr(PhaseTCPConnect, dialTimeout, connErr),
+		)
+	}
+
+	cfg := opts.tlsConfig(host)
+	cfg.MinVersion = version
+	cfg.MaxVersion = version
+
+	// Only the negotiated protocol version is of interest, so certificate
+	// verification (if enabled by a custom TLS configuration) is skipped in
+	// order to avoid mistaking a verification failure for a rejected
+	// protocol version.
+	//
+	// nolint:gosec
+	cfg.InsecureSkipVerify = true
+
+	// Enable insecure cipher suites for legacy protocol versions so that
+	// servers only offering these cipher suites are identified. Cipher
+	// suites are not configurable for TLS 1.3.
+	if version < tls.VersionTLS12 {
+		cfg.CipherSuites = legacyCipherSuites()
+	}
+
+	handshakeTimeout := opts.handshakeTimeout(timeout)
+	handshakeCtx, handshakeCancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer handshakeCancel()
+
+	conn := tls.Client(rawConn, cfg)
+	if err := conn.HandshakeContext(handshakeCtx); err != nil {
+		_ = rawConn.Close()
+
+		return false, nil
+	}
+
+	negotiated := conn.ConnectionState().Version
+	_ = conn.Close()
+
+	return negotiated == version, nil
+}
+
+// legacyCipherSuites returns the IDs of all cipher suites implemented by the
+// crypto/tls package, including those with security issues.
+func legacyCipherSuites() []uint16 {
+	suites := append(tls.CipherSuites(), tls.InsecureCipherSuites()...)
+
+	ids := make([]uint16, 0, len(suites))
+	for _, suite := range suites {
+		ids = append(ids, suite.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newVersionPinnedTLSServer starts a TLS server accepting TLS protocol
+// versions from TLS 1.0 up to the given maximum version. The IP Address and
+// port of the server are returned.
+func newVersionPinnedTLSServer(t *testing.T, maxVersion uint16) (string, int) {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	// Rejected handshakes are expected and are not of interest.
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+
+	server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS10,
+		MaxVersion: maxVersion,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse server address: %v", err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("failed to parse server port: %v", err)
+	}
+
+	return host, port
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyword string
+		want    uint16
+		err     error
+	}{
+		{name: "Keyword", keyword: "1.2", want: tls.VersionTLS12},
+		{name: "Name", keyword: "TLS 1.3", want: tls.VersionTLS13},
+		{name: "LowercaseName", keyword: " tls1.0 ", want: tls.VersionTLS10},
+		{name: "Unsupported", keyword: "1.4", err: ErrUnsupportedTLSVersion},
+		{name: "SSL", keyword: "SSL 3.0", err: ErrUnsupportedTLSVersion},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTLSVersion(tt.keyword)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if got != tt.want {
+				t.Errorf("want %s; got %s", TLSVersionName(tt.want), TLSVersionName(got))
+			}
+		})
+	}
+}
+
+func TestTLSVersionsBelow(t *testing.T) {
+	want := []uint16{tls.VersionTLS10, tls.VersionTLS11}
+	if got := TLSVersionsBelow(tls.VersionTLS12); !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v; got %v", want, got)
+	}
+
+	if got := TLSVersionsBelow(tls.VersionTLS10); len(got) != 0 {
+		t.Errorf("want no versions; got %v", got)
+	}
+}
+
+func TestProbeTLSVersions(t *testing.T) {
+	allVersions := []uint16{
+		tls.VersionTLS10,
+		tls.VersionTLS11,
+		tls.VersionTLS12,
+		tls.VersionTLS13,
+	}
+
+	tests := []struct {
+		name       string
+		maxVersion uint16
+		want       []uint16
+	}{
+		{
+			name:       "MaxTLS11",
+			maxVersion: tls.VersionTLS11,
+			want:       []uint16{tls.VersionTLS10, tls.VersionTLS11},
+		},
+		{
+			name:       "MaxTLS12",
+			maxVersion: tls.VersionTLS12,
+			want:       []uint16{tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			ipAddr, port := newVersionPinnedTLSServer(t, tt.maxVersion)
+
+			got, err := ProbeTLSVersions(
+				"localhost",
+				ipAddr,
+				port,
+				allVersions,
+				5*time.Second,
+				CertRetrievalOptions{},
+				zerolog.Nop(),
+			)
+			if err != nil {
+				t.Fatalf("want no error; got %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %v; got %v", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("ConnectionRefused", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to start listener: %v", err)
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		_ = listener.Close()
+
+		_, err = ProbeTLSVersions("localhost", "127.0.0.1", port, allVersions, time.Second, CertRetrievalOptions{}, zerolog.Nop())
+		if err == nil {
+			t.Error("want error connecting to closed port; got nil")
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDNSServer indicates that a given DNS server value is not a valid
+// IP Address or hostname with an optional port.
+var ErrInvalidDNSServer = errors.New("invalid DNS server")
+
+// defaultDNSPort is the port used for a DNS server specified without one.
+const defaultDNSPort int = 53
+
+// DNSServerAddress returns the given DNS server value (an IP Address or
+// hostname with an optional port, e.g., 10.0.0.53, 10.0.0.53:5353 or
+// [2001:db8::53]:53) in host:port form. The default DNS port is used if a
+// port is not specified.
+func DNSServerAddress(server string) (string, error) {
+	server = strings.TrimSpace(server)
+	if server == "" {
+		return "", fmt.Errorf(
+			"DNS server not specified: %w",
+			ErrInvalidDNSServer,
+		)
+	}
+
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		// No port specified; bare IPv6 addresses are accepted as-is.
+		host = strings.TrimSuffix(strings.TrimPrefix(server, "["), "]")
+		port = strconv.Itoa(defaultDNSPort)
+	}
+
+	portNum, err := strconv.Atoi(port)
+	switch {
+	case host == "" || strings.ContainsAny(host, "[]/ "):
+		return "", fmt.Errorf(
+			"%q does not specify a valid host: %w",
+			server,
+			ErrInvalidDNSServer,
+		)
+
+	case err != nil || portNum < 1 || portNum > 65535:
+		return "", fmt.Errorf(
+			"%q does not specify a valid port: %w",
+			server,
+			ErrInvalidDNSServer,
+		)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
+
+// NewResolver returns a resolver which sends queries to the given DNS server
+// (e.g., an internal split-horizon DNS server) instead of the DNS servers
+// used by the system resolver. Connection attempts to the DNS server are
+// abandoned if not completed within the given timeout; a timeout of zero
+// applies no limit. The system resolver is returned if a DNS server is not
+// specified.
+func NewResolver(server string, timeout time.Duration) (*net.Resolver, error) {
+	if server == "" {
+		return net.DefaultResolver, nil
+	}
+
+	address, err := DNSServerAddress(server)
+	if err != nil {
+		return nil, err
+	}
+
+	return &net.Resolver{
+		// The Go resolver is required to override the DNS server used.
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: timeout}
+
+			return dialer.DialContext(ctx, network, address)
+		},
+	}, nil
+}
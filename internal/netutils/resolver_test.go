@@ -0,0 +1,89 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDNSServerAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   string
+		expected string
+		err      error
+	}{
+		{
+			name:     "IPv4AddressWithoutPort",
+			server:   "10.0.0.53",
+			expected: "10.0.0.53:53",
+		},
+		{
+			name:     "IPv4AddressWithPort",
+			server:   "10.0.0.53:5353",
+			expected: "10.0.0.53:5353",
+		},
+		{
+			name:     "IPv6AddressWithoutPort",
+			server:   "2001:db8::53",
+			expected: "[2001:db8::53]:53",
+		},
+		{
+			name:     "BracketedIPv6AddressWithPort",
+			server:   "[2001:db8::53]:5353",
+			expected: "[2001:db8::53]:5353",
+		},
+		{
+			name:     "Hostname",
+			server:   "ns1.example.com",
+			expected: "ns1.example.com:53",
+		},
+		{
+			name:   "Empty",
+			server: "",
+			err:    ErrInvalidDNSServer,
+		},
+		{
+			name:   "InvalidPort",
+			server: "10.0.0.53:99999",
+			err:    ErrInvalidDNSServer,
+		},
+		{
+			name:   "MissingHost",
+			server: ":53",
+			err:    ErrInvalidDNSServer,
+		},
+		{
+			name:   "CIDRRange",
+			server: "10.0.0.0/24",
+			err:    ErrInvalidDNSServer,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DNSServerAddress(tt.server)
+
+			switch {
+			case tt.err != nil:
+				if !errors.Is(err, tt.err) {
+					t.Errorf("want error %v for %q; got %v", tt.err, tt.server, err)
+				}
+
+			case err != nil:
+				t.Errorf("want no error for %q; got %v", tt.server, err)
+
+			case got != tt.expected:
+				t.Errorf("want %q for %q; got %q", tt.expected, tt.server, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ErrNoSRVTargets indicates that a DNS SRV record did not provide any
+// usable targets.
+var ErrNoSRVTargets = errors.New("no usable SRV record targets")
+
+// IsSRVName indicates whether the given host pattern is a DNS SRV record
+// name (e.g., _ldaps._tcp.example.com) as opposed to a hostname or FQDN.
+// The service and protocol labels of a SRV record name are prefixed with an
+// underscore and are followed by the domain name.
+func IsSRVName(hostPattern string) bool {
+	labels := strings.Split(strings.TrimSuffix(hostPattern, "."), ".")
+	if len(labels) < 3 {
+		return false
+	}
+
+	for _, label := range labels[:2] {
+		if len(label) < 2 || !strings.HasPrefix(label, "_") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// srvTargets returns the target hostnames from the given DNS SRV records
+// (in the order provided, trailing dot removed) along with the ports
+// advertised for each target. A target of "." indicates that the service
+// is not available at the domain and is skipped.
+func srvTargets(records []*net.SRV) ([]string, map[string][]int) {
+	var targets []string
+	ports := make(map[string][]int)
+
+	for _, record := range records {
+		target := strings.TrimSuffix(record.Target, ".")
+		if target == "" {
+			continue
+		}
+
+		if _, ok := ports[target]; !ok {
+			targets = append(targets, target)
+		}
+
+		var seen bool
+		for _, port := range ports[target] {
+			if port == int(record.Port) {
+				seen = true
+				break
+			}
+		}
+
+		if !seen {
+			ports[target] = append(ports[target], int(record.Port))
+		}
+	}
+
+	return targets, ports
+}
+
+// ExpandSRVRecord looks up the given DNS SRV record name (e.g.,
+// _ldaps._tcp.example.com) and expands each target host advertised by the
+// record to the IP Addresses it resolves to. The ports advertised for each
+// target are recorded for the host pattern, overriding the ports specified
+// for all host patterns. The given resolver (the system resolver if not
+// provided) is used for all lookups with the given timeout (if non-zero)
+// applied to each.
+func ExpandSRVRecord(srvName string, resolver *net.Resolver, timeout time.Duration) ([]HostPattern, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// An empty service and protocol requests a lookup of the name as-is.
+	_, records, lookupErr := resolver.LookupSRV(ctx, "", "", srvName)
+	if lookupErr != nil {
+		return nil, fmt.Errorf(
+			"SRV record %q invalid; %w: %w",
+			srvName,
+			ErrHostnameFailsNameResolution,
+			phaseErr(PhaseDNSResolution, timeout, lookupErr),
+		)
+	}
+
+	targets, ports := srvTargets(records)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf(
+			"SRV record %q: %w",
+			srvName,
+			ErrNoSRVTargets,
+		)
+	}
+
+	hosts := make([]HostPattern, 0, len(targets))
+	for _, target := range targets {
+		host, err := ExpandHostWithResolver(target, resolver, timeout)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"target of SRV record %q: %w",
+				srvName,
+				err,
+			)
+		}
+		host.Ports = ports[target]
+
+		hosts = append(hosts, host)
+	}
+
+	return hosts, nil
+}
+
+// ExpandHostsWithResolver expands the given host pattern using the given
+// resolver and timeout. A DNS SRV record name is expanded to a host pattern
+// for each advertised target, all other host patterns are expanded as-is
+// (see ExpandHostWithResolver).
+func ExpandHostsWithResolver(hostPattern string, resolver *net.Resolver, timeout time.Duration) ([]HostPattern, error) {
+	if IsSRVName(hostPattern) {
+		return ExpandSRVRecord(hostPattern, resolver, timeout)
+	}
+
+	host, err := ExpandHostWithResolver(hostPattern, resolver, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return []HostPattern{host}, nil
+}
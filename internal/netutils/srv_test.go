@@ -0,0 +1,65 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestIsSRVName(t *testing.T) {
+	tests := []struct {
+		hostPattern string
+		expected    bool
+	}{
+		{hostPattern: "_ldaps._tcp.example.com", expected: true},
+		{hostPattern: "_sips._tcp.example.com.", expected: true},
+		{hostPattern: "_ldaps._tcp", expected: false},
+		{hostPattern: "_._tcp.example.com", expected: false},
+		{hostPattern: "ldaps._tcp.example.com", expected: false},
+		{hostPattern: "_dmarc.example.com", expected: false},
+		{hostPattern: "www.example.com", expected: false},
+		{hostPattern: "192.168.2.10", expected: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.hostPattern, func(t *testing.T) {
+			if got := IsSRVName(tt.hostPattern); got != tt.expected {
+				t.Errorf("want %t for %q; got %t", tt.expected, tt.hostPattern, got)
+			}
+		})
+	}
+}
+
+func TestSRVTargets(t *testing.T) {
+	records := []*net.SRV{
+		{Target: "dc1.example.com.", Port: 636, Priority: 0, Weight: 100},
+		{Target: "dc2.example.com.", Port: 636, Priority: 0, Weight: 50},
+		{Target: "dc1.example.com.", Port: 3269, Priority: 10, Weight: 0},
+		{Target: "dc1.example.com.", Port: 636, Priority: 20, Weight: 0},
+		{Target: ".", Port: 0},
+	}
+
+	targets, ports := srvTargets(records)
+
+	expectedTargets := []string{"dc1.example.com", "dc2.example.com"}
+	if !reflect.DeepEqual(targets, expectedTargets) {
+		t.Errorf("want targets %v; got %v", expectedTargets, targets)
+	}
+
+	expectedPorts := map[string][]int{
+		"dc1.example.com": {636, 3269},
+		"dc2.example.com": {636},
+	}
+	if !reflect.DeepEqual(ports, expectedPorts) {
+		t.Errorf("want ports %v; got %v", expectedPorts, ports)
+	}
+}
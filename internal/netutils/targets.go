@@ -0,0 +1,200 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package netutils
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidTargetEntry indicates that a targets file entry could not be
+	// parsed.
+	ErrInvalidTargetEntry = errors.New("invalid target entry")
+
+	// ErrNoTargetsFound indicates that no target entries were found.
+	ErrNoTargetsFound = errors.New("no target entries found")
+)
+
+// Target is a certificate-enabled service to evaluate.
+type Target struct {
+	// Server is the FQDN or IP Address used for certificate chain retrieval.
+	Server string
+
+	// Port is the TCP port of the certificate-enabled service. This is zero
+	// if a port was not specified for the target.
+	Port int
+
+	// DNSName is the (optional) DNS Name used for SNI support and hostname
+	// verification.
+	DNSName string
+
+	// Tags is the (optional) collection of labels assigned to the target.
+	// Tags are used to select the policy applied to the target.
+	Tags []string
+}
+
+// targetTagsPrefix is the prefix of the optional target entry field listing
+// the tags assigned to the target.
+const targetTagsPrefix string = "tags="
+
+// ParseTargets parses certificate-enabled service targets from the given
+// reader. Each line is expected to contain a server value with an optional
+// port (e.g., www.example.com:8443 or [2001:db8::1]:443) followed by an
+// optional DNS Name value separated by whitespace. A comma-separated list of
+// tags may be assigned to the target by including a tags= field (e.g.,
+// tags=public,web) after the server value. Blank lines and text following a
+// # character are ignored.
+func ParseTargets(r io.Reader) ([]Target, error) {
+	var targets []Target
+
+	scanner := bufio.NewScanner(r)
+
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx != -1 {
+			line = line[:idx]
+		}
+
+		fields, tags := splitTargetTags(strings.Fields(line))
+		switch {
+		case len(fields) == 0:
+			continue
+
+		case len(fields) > 2:
+			return nil, fmt.Errorf(
+				"entry %q on line %d has too many fields: %w",
+				strings.TrimSpace(line),
+				lineNum,
+				ErrInvalidTargetEntry,
+			)
+		}
+
+		target, err := ParseTargetServer(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf(
+				"entry %q on line %d: %w",
+				strings.TrimSpace(line),
+				lineNum,
+				err,
+			)
+		}
+
+		if len(fields) == 2 {
+			target.DNSName = fields[1]
+		}
+
+		target.Tags = tags
+
+		targets = append(targets, target)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to read target entries: %w",
+			err,
+		)
+	}
+
+	if len(targets) == 0 {
+		return nil, ErrNoTargetsFound
+	}
+
+	return targets, nil
+}
+
+// splitTargetTags separates the tags field (if present) following the server
+// value from the other fields of a target entry, returning the remaining
+// fields and the tags listed by the tags field.
+func splitTargetTags(fields []string) ([]string, []string) {
+	remaining := make([]string, 0, len(fields))
+	var tags []string
+
+	for i, field := range fields {
+		if i == 0 || !strings.HasPrefix(strings.ToLower(field), targetTagsPrefix) {
+			remaining = append(remaining, field)
+			continue
+		}
+
+		for _, tag := range strings.Split(field[len(targetTagsPrefix):], ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return remaining, tags
+}
+
+// ParseTargetServer parses a server value with an optional port (e.g.,
+// www.example.com:8443). The port is zero if not specified.
+func ParseTargetServer(value string) (Target, error) {
+	host, portStr, err := net.SplitHostPort(value)
+	if err != nil {
+		// No port specified; bare IPv6 addresses are accepted as-is.
+		return Target{Server: strings.Trim(value, "[]")}, nil
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return Target{}, fmt.Errorf(
+			"invalid port %q: %w",
+			portStr,
+			ErrInvalidTargetEntry,
+		)
+	}
+
+	if host == "" {
+		return Target{}, fmt.Errorf(
+			"missing server value: %w",
+			ErrInvalidTargetEntry,
+		)
+	}
+
+	return Target{Server: host, Port: port}, nil
+}
+
+// LoadTargetsFile parses certificate-enabled service targets from the
+// specified file. See ParseTargets for the expected format.
+func LoadTargetsFile(filename string) ([]Target, error) {
+	// Open the targets file after first attempting to sanitize the input
+	// file variable contents.
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to open targets file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	targets, err := ParseTargets(f)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse targets file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return targets, nil
+}
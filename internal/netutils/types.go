@@ -7,7 +7,10 @@
 
 package netutils
 
-import "net"
+import (
+	"net"
+	"time"
+)
 
 // PortCheckResult indicates the discovered TCP port state for a given host
 // and what error (if any) occurred while checking the port.
@@ -29,6 +32,10 @@ type PortCheckResult struct {
 
 	// Err is what error (if any) which occurred while checking a TCP port.
 	Err error
+
+	// CorrelationID is an optional identifier used to associate log events
+	// and results with a specific scan target (IP Address and port).
+	CorrelationID string
 }
 
 // PortCheckTarget specifies values used to check the TCP port state for a
@@ -79,7 +86,50 @@ type HostPattern struct {
 	// Addresses.
 	Resolved bool
 
+	// ResolveTime is the time taken to resolve the given host pattern to one
+	// or more IP Addresses. This is zero for host patterns which did not
+	// require name resolution.
+	ResolveTime time.Duration
+
 	// Range indicates whether the given host pattern was determined to be a
 	// CIDR or partial IP Address range.
 	Range bool
+
+	// Truncated indicates whether the IP Addresses expanded from the given
+	// host pattern were limited to the first MaxIPv6RangeHosts addresses of
+	// a (large) IPv6 range.
+	Truncated bool
+
+	// Ports optionally records the ports to check for this host pattern,
+	// overriding the ports specified for all host patterns.
+	Ports []int
+}
+
+// CertRetrievalStats records details of the attempts made to retrieve a
+// certificate chain.
+type CertRetrievalStats struct {
+	// Attempts is the number of connection attempts made.
+	Attempts int
+
+	// ConnectTime is the time taken to establish the TCP connection for the
+	// final connection attempt.
+	ConnectTime time.Duration
+
+	// HandshakeTime is the time taken to complete the TLS handshake for the
+	// final connection attempt.
+	HandshakeTime time.Duration
+
+	// TLSVersion is the TLS protocol version negotiated by the final
+	// connection attempt. This is zero if the TLS handshake did not
+	// complete.
+	TLSVersion uint16
+
+	// CipherSuite is the cipher suite negotiated by the final connection
+	// attempt. This is zero if the TLS handshake did not complete.
+	CipherSuite uint16
+
+	// ServerName is the Server Name Indication (SNI) value sent by the final
+	// connection attempt. This is empty if SNI was not used (e.g., when
+	// connecting by IP Address).
+	ServerName string
 }
@@ -0,0 +1,18 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package pkcs12 provides support for encoding a private key and certificate
+// chain as a password protected PKCS #12 (PFX) bundle as required by
+// Windows/IIS and many Java applications.
+//
+// Encoding is provided by the software.sslmate.com/src/go-pkcs12 package
+// using its modern encoder: the private key and certificates are encrypted
+// using PBES2 (PBKDF2 with HMAC-SHA-256 and AES-256-CBC) and the bundle
+// integrity is protected using a HMAC-SHA-256 MAC. Bundles produced by this
+// package can be read by OpenSSL 1.1.1 and newer, Java 12 and newer and
+// Windows Server 2019 and newer.
+package pkcs12
@@ -0,0 +1,89 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package pkcs12
+
+import (
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	gopkcs12 "software.sslmate.com/src/go-pkcs12"
+)
+
+var (
+	// ErrMissingValue indicates that an expected value was missing.
+	ErrMissingValue = errors.New("missing expected value")
+
+	// ErrKeyMismatch indicates that the private key does not match any
+	// certificate in the certificate chain.
+	ErrKeyMismatch = errors.New("private key does not match any certificate in chain")
+)
+
+// publicKeyer is implemented by all private key types supported by the
+// crypto/x509 package.
+type publicKeyer interface {
+	Public() crypto.PublicKey
+}
+
+// equaler is implemented by all public key types supported by the
+// crypto/x509 package.
+type equaler interface {
+	Equal(crypto.PublicKey) bool
+}
+
+// Encode encodes the given private key and certificate chain as a PKCS #12
+// bundle protected by the given password. The private key is associated
+// with the certificate in the chain containing the matching public key; all
+// other certificates in the chain are included as CA certificates in their
+// original order.
+func Encode(privateKey crypto.PrivateKey, certChain []*x509.Certificate, password string) ([]byte, error) {
+	if privateKey == nil {
+		return nil, fmt.Errorf("private key not provided: %w", ErrMissingValue)
+	}
+
+	if len(certChain) == 0 {
+		return nil, fmt.Errorf("certificate chain not provided: %w", ErrMissingValue)
+	}
+
+	keyCert, err := matchingCert(privateKey, certChain)
+	if err != nil {
+		return nil, err
+	}
+
+	caCerts := make([]*x509.Certificate, 0, len(certChain)-1)
+	for _, cert := range certChain {
+		if cert != keyCert {
+			caCerts = append(caCerts, cert)
+		}
+	}
+
+	pfxData, err := gopkcs12.Modern.Encode(privateKey, keyCert, caCerts, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PKCS #12 bundle: %w", err)
+	}
+
+	return pfxData, nil
+}
+
+// matchingCert returns the certificate from the given chain whose public key
+// matches the given private key.
+func matchingCert(privateKey crypto.PrivateKey, certChain []*x509.Certificate) (*x509.Certificate, error) {
+	signer, ok := privateKey.(publicKeyer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T: %w", privateKey, ErrKeyMismatch)
+	}
+
+	for _, cert := range certChain {
+		if pub, ok := cert.PublicKey.(equaler); ok && pub.Equal(signer.Public()) {
+			return cert, nil
+		}
+	}
+
+	return nil, ErrKeyMismatch
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package pkcs12
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	gopkcs12 "software.sslmate.com/src/go-pkcs12"
+)
+
+// newTestCert generates a self-signed certificate for the given private key.
+func newTestCert(t *testing.T, key *ecdsa.PrivateKey, commonName string) *x509.Certificate {
+	t.Helper()
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	return key
+}
+
+func TestEncode(t *testing.T) {
+	key := newTestKey(t)
+	cert := newTestCert(t, key, "www.example.test")
+	intermediate := newTestCert(t, newTestKey(t), "Intermediate")
+	root := newTestCert(t, newTestKey(t), "Root")
+
+	const password = "s3cr3t"
+
+	// The key certificate is intentionally not listed first.
+	pfxData, err := Encode(key, []*x509.Certificate{intermediate, cert, root}, password)
+	if err != nil {
+		t.Fatalf("failed to encode bundle: %v", err)
+	}
+
+	gotKey, gotCert, gotCACerts, err := gopkcs12.DecodeChain(pfxData, password)
+	if err != nil {
+		t.Fatalf("failed to decode bundle: %v", err)
+	}
+
+	if !key.Equal(gotKey) {
+		t.Error("decoded private key does not match encoded private key")
+	}
+
+	if !gotCert.Equal(cert) {
+		t.Errorf("want certificate %q; got %q", cert.Subject, gotCert.Subject)
+	}
+
+	wantCACerts := []*x509.Certificate{intermediate, root}
+	if len(gotCACerts) != len(wantCACerts) {
+		t.Fatalf("want %d CA certificates; got %d", len(wantCACerts), len(gotCACerts))
+	}
+
+	for i := range wantCACerts {
+		if !gotCACerts[i].Equal(wantCACerts[i]) {
+			t.Errorf("want CA certificate %q; got %q", wantCACerts[i].Subject, gotCACerts[i].Subject)
+		}
+	}
+
+	if _, _, _, err := gopkcs12.DecodeChain(pfxData, "wrong"); !errors.Is(err, gopkcs12.ErrIncorrectPassword) {
+		t.Errorf("want error %v using wrong password; got %v", gopkcs12.ErrIncorrectPassword, err)
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	key := newTestKey(t)
+	cert := newTestCert(t, key, "www.example.test")
+	otherCert := newTestCert(t, newTestKey(t), "Other")
+
+	tests := []struct {
+		name      string
+		key       *ecdsa.PrivateKey
+		certChain []*x509.Certificate
+		err       error
+	}{
+		{
+			name:      "MissingKey",
+			certChain: []*x509.Certificate{cert},
+			err:       ErrMissingValue,
+		},
+		{
+			name: "MissingCertChain",
+			key:  key,
+			err:  ErrMissingValue,
+		},
+		{
+			name:      "KeyMismatch",
+			key:       key,
+			certChain: []*x509.Certificate{otherCert},
+			err:       ErrKeyMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var privateKey interface{}
+			if tt.key != nil {
+				privateKey = tt.key
+			}
+
+			_, err := Encode(privateKey, tt.certChain, "")
+			if !errors.Is(err, tt.err) {
+				t.Errorf("want error %v; got %v", tt.err, err)
+			}
+		})
+	}
+}
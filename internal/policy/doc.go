@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package policy provides support for loading policy files which map
+// certificate-enabled service targets (by server pattern or tag) to the
+// validation check settings applied when evaluating them.
+package policy
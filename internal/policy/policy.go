@@ -0,0 +1,314 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/atc0005/check-cert/internal/textutils"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrInvalidPolicyFile indicates that a policy file could not be
+	// parsed.
+	ErrInvalidPolicyFile = errors.New("invalid policy file")
+
+	// ErrNoPoliciesFound indicates that no policies were found.
+	ErrNoPoliciesFound = errors.New("no policies found")
+)
+
+// Keys with special meaning within a policy file. All other keys within a
+// policy are treated as settings.
+const (
+	keyPolicies string = "policies"
+	keyName     string = "name"
+	keyServers  string = "servers"
+	keyTags     string = "tags"
+)
+
+// Policy is a named collection of settings applied to matching
+// certificate-enabled service targets.
+type Policy struct {
+	// Name identifies the policy in log messages and report output.
+	Name string
+
+	// Servers is the collection of glob patterns (e.g., *.example.com)
+	// matched against the server and DNS Name values of a target.
+	Servers []string
+
+	// Tags is the collection of tags matched against the tags assigned to
+	// a target.
+	Tags []string
+
+	// Settings is the collection of setting values (indexed by flag name)
+	// applied to matching targets. Multiple values are provided for list
+	// settings.
+	Settings map[string][]string
+
+	// lineNum is the line in the policy file the policy was found on.
+	lineNum int
+}
+
+// Policies is a collection of policies evaluated in order.
+type Policies []Policy
+
+// Matches indicates whether this policy applies to a target with the given
+// server, DNS Name and tags. A target matches if any server pattern matches
+// the server or DNS Name value (case-insensitive) or if any tag is assigned
+// to the target. A policy without server patterns or tags matches all
+// targets.
+func (p Policy) Matches(server string, dnsName string, tags []string) bool {
+	if len(p.Servers) == 0 && len(p.Tags) == 0 {
+		return true
+	}
+
+	for _, pattern := range p.Servers {
+		pattern = strings.ToLower(pattern)
+		for _, value := range []string{server, dnsName} {
+			if value == "" {
+				continue
+			}
+
+			if ok, _ := path.Match(pattern, strings.ToLower(value)); ok {
+				return true
+			}
+		}
+	}
+
+	for _, tag := range p.Tags {
+		if textutils.InList(tag, tags, true) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match returns the first policy in the collection applying to a target
+// with the given server, DNS Name and tags. False is returned if no policy
+// applies.
+func (ps Policies) Match(server string, dnsName string, tags []string) (Policy, bool) {
+	for _, p := range ps {
+		if p.Matches(server, dnsName, tags) {
+			return p, true
+		}
+	}
+
+	return Policy{}, false
+}
+
+// LoadFile parses policies from the specified file. See Parse for the
+// expected format.
+func LoadFile(filename string) (Policies, error) {
+	content, err := os.ReadFile(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to read policy file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	policies, err := Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse policy file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return policies, nil
+}
+
+// Parse parses policies from the given content.
+//
+// The content is parsed as YAML. A top-level policies key holds a sequence
+// of policies, each a mapping of keys to a scalar value or a sequence of
+// scalar values. The name, servers and tags keys identify the policy and the
+// targets it applies to; all other keys are settings named after the flag
+// they override (e.g., age-warning). Other YAML constructs (e.g., nested
+// mappings, nested sequences or aliases) are rejected.
+func Parse(content string) (Policies, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, fmt.Errorf(
+			"%v: %w",
+			err,
+			ErrInvalidPolicyFile,
+		)
+	}
+
+	// Empty content (or content with only comments) has no document.
+	if len(doc.Content) == 0 {
+		return nil, ErrNoPoliciesFound
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode || len(root.Content) != 2 || root.Content[0].Value != keyPolicies {
+		return nil, fmt.Errorf(
+			"expected only a top-level %s key on line %d: %w",
+			keyPolicies,
+			root.Line,
+			ErrInvalidPolicyFile,
+		)
+	}
+
+	items := root.Content[1]
+	switch {
+	case items.Kind == yaml.ScalarNode && items.Tag == "!!null":
+		return nil, ErrNoPoliciesFound
+
+	case items.Kind != yaml.SequenceNode:
+		return nil, fmt.Errorf(
+			"expected a sequence of policies on line %d: %w",
+			items.Line,
+			ErrInvalidPolicyFile,
+		)
+
+	case len(items.Content) == 0:
+		return nil, ErrNoPoliciesFound
+	}
+
+	policies := make(Policies, 0, len(items.Content))
+	for _, item := range items.Content {
+		p, err := parsePolicy(item)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+// parsePolicy parses a single policy from the given YAML mapping node.
+func parsePolicy(item *yaml.Node) (Policy, error) {
+	if item.Kind != yaml.MappingNode {
+		return Policy{}, fmt.Errorf(
+			"expected a mapping of policy keys on line %d: %w",
+			item.Line,
+			ErrInvalidPolicyFile,
+		)
+	}
+
+	p := Policy{
+		Settings: make(map[string][]string),
+		lineNum:  item.Line,
+	}
+
+	// Mapping node content alternates between key and value nodes.
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		keyNode, valueNode := item.Content[i], item.Content[i+1]
+
+		if keyNode.Kind != yaml.ScalarNode || keyNode.Value == "" {
+			return Policy{}, fmt.Errorf(
+				"expected key: value on line %d: %w",
+				keyNode.Line,
+				ErrInvalidPolicyFile,
+			)
+		}
+
+		values, err := parseValues(valueNode)
+		if err != nil {
+			return Policy{}, fmt.Errorf(
+				"invalid value for %q on line %d: %w",
+				keyNode.Value,
+				valueNode.Line,
+				err,
+			)
+		}
+
+		if err := p.set(keyNode.Value, values, keyNode.Line); err != nil {
+			return Policy{}, err
+		}
+	}
+
+	if p.Name == "" {
+		return Policy{}, fmt.Errorf(
+			"policy on line %d is missing a name: %w",
+			p.lineNum,
+			ErrInvalidPolicyFile,
+		)
+	}
+
+	return p, nil
+}
+
+// parseValues returns the value of the given scalar node or the values of
+// the given sequence of scalar nodes. No values are returned for a null
+// value.
+func parseValues(node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return nil, nil
+		}
+
+		return []string{node.Value}, nil
+
+	case yaml.SequenceNode:
+		values := make([]string, 0, len(node.Content))
+		for _, entry := range node.Content {
+			if entry.Kind != yaml.ScalarNode || entry.Tag == "!!null" {
+				return nil, fmt.Errorf(
+					"unsupported sequence entry on line %d: %w",
+					entry.Line,
+					ErrInvalidPolicyFile,
+				)
+			}
+
+			values = append(values, entry.Value)
+		}
+
+		return values, nil
+
+	default:
+		return nil, fmt.Errorf(
+			"unsupported value: %w",
+			ErrInvalidPolicyFile,
+		)
+	}
+}
+
+// set records the given values for the specified key, appending to any
+// values previously recorded for list keys.
+func (p *Policy) set(key string, values []string, lineNum int) error {
+	switch key {
+	case keyName:
+		if len(values) > 1 {
+			return fmt.Errorf(
+				"policy name on line %d must be a single value: %w",
+				lineNum,
+				ErrInvalidPolicyFile,
+			)
+		}
+
+		if len(values) == 1 {
+			p.Name = values[0]
+		}
+
+	case keyServers:
+		p.Servers = append(p.Servers, values...)
+
+	case keyTags:
+		p.Tags = append(p.Tags, values...)
+
+	default:
+		p.Settings[key] = append(p.Settings[key], values...)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package policy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		policies Policies
+		err      error
+	}{
+		{
+			name: "FlowAndBlockSequences",
+			input: `# TLS policy
+policies:
+  - name: public-web
+    servers: ["*.example.com", 'www.example.org'] # trailing comment
+    tags: [public]
+    age-warning: 30
+    apply-validation-result:
+      - tls-version
+      - self-signed
+
+  - name: default
+    ignore-validation-result: [ct]
+`,
+			policies: Policies{
+				{
+					Name:    "public-web",
+					Servers: []string{"*.example.com", "www.example.org"},
+					Tags:    []string{"public"},
+					Settings: map[string][]string{
+						"age-warning":             {"30"},
+						"apply-validation-result": {"tls-version", "self-signed"},
+					},
+					lineNum: 3,
+				},
+				{
+					Name: "default",
+					Settings: map[string][]string{
+						"ignore-validation-result": {"ct"},
+					},
+					lineNum: 11,
+				},
+			},
+		},
+		{
+			name:  "MissingTopLevelKey",
+			input: "- name: default\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "MissingName",
+			input: "policies:\n  - age-warning: 30\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "UnexpectedIndentation",
+			input: "policies:\n  - name: default\n      age-warning: 30\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "UnterminatedList",
+			input: "policies:\n  - name: default\n    tags: [public\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "NoPolicies",
+			input: "policies:\n",
+			err:   ErrNoPoliciesFound,
+		},
+		{
+			name:  "UnknownTopLevelKey",
+			input: "policies:\n  - name: default\ntargets: []\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "UnsupportedNestedMapping",
+			input: "policies:\n  - name: default\n    thresholds:\n      warning: 30\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "UnsupportedNestedSequence",
+			input: "policies:\n  - name: default\n    tags: [[public]]\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "UnsupportedAlias",
+			input: "policies:\n  - name: &n default\n    tags: *n\n",
+			err:   ErrInvalidPolicyFile,
+		},
+		{
+			name:  "EmptyFile",
+			input: "# no policies yet\n",
+			err:   ErrNoPoliciesFound,
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := Parse(tt.input)
+
+			switch {
+			case tt.err != nil && !errors.Is(err, tt.err):
+				t.Fatalf("want: %v; got: %v", tt.err, err)
+
+			case tt.err == nil && err != nil:
+				t.Fatalf("want: no error; got: %v", err)
+
+			case tt.err != nil:
+				t.Logf("got expected error: %v", err)
+
+				return
+			}
+
+			if !reflect.DeepEqual(policies, tt.policies) {
+				t.Errorf("want: %+v; got: %+v", tt.policies, policies)
+			}
+		})
+	}
+}
+
+func TestPoliciesMatch(t *testing.T) {
+	policies := Policies{
+		{Name: "public-web", Servers: []string{"*.example.com"}},
+		{Name: "internal", Tags: []string{"internal"}},
+		{Name: "default"},
+	}
+
+	tests := []struct {
+		name    string
+		server  string
+		dnsName string
+		tags    []string
+		policy  string
+	}{
+		{
+			name:   "ServerPattern",
+			server: "WWW.example.com",
+			policy: "public-web",
+		},
+		{
+			name:    "DNSNamePattern",
+			server:  "192.0.2.10",
+			dnsName: "www.example.com",
+			policy:  "public-web",
+		},
+		{
+			name:   "Tag",
+			server: "ldap.example.net",
+			tags:   []string{"INTERNAL"},
+			policy: "internal",
+		},
+		{
+			name:   "CatchAll",
+			server: "ldap.example.net",
+			policy: "default",
+		},
+	}
+
+	for _, tt := range tests {
+
+		// Make scopelint linter happy
+		// https://stackoverflow.com/questions/68559574/using-the-variable-on-range-scope-x-in-function-literal-scopelint
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := policies.Match(tt.server, tt.dnsName, tt.tags)
+			if !ok {
+				t.Fatal("want: matching policy; got: none")
+			}
+
+			if got.Name != tt.policy {
+				t.Errorf("want: %q; got: %q", tt.policy, got.Name)
+			}
+		})
+	}
+}
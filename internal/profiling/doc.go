@@ -0,0 +1,11 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package profiling provides optional CPU and memory profiling in the pprof
+// format for long-running applications so that performance issues can be
+// diagnosed without custom builds.
+package profiling
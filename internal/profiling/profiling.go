@@ -0,0 +1,122 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package profiling
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+)
+
+// ErrProfilingFailed indicates that a profile could not be collected or
+// written.
+var ErrProfilingFailed = errors.New("profiling failed")
+
+// StopFunc stops any active profiling and writes the collected profiles.
+type StopFunc func() error
+
+// Start begins CPU profiling if a CPU profile filename is given. The returned
+// function stops CPU profiling and, if a memory profile filename is given,
+// writes a heap profile reflecting memory in use and allocations made since
+// the application started. Profiling is skipped entirely if neither filename
+// is given.
+func Start(cpuProfile string, memProfile string) (StopFunc, error) {
+	var cpuFile *os.File
+
+	if cpuProfile != "" {
+		f, err := os.Create(filepath.Clean(cpuProfile))
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to create CPU profile %q: %w: %w",
+				cpuProfile,
+				ErrProfilingFailed,
+				err,
+			)
+		}
+
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+
+			return nil, fmt.Errorf(
+				"failed to start CPU profile: %w: %w",
+				ErrProfilingFailed,
+				err,
+			)
+		}
+
+		cpuFile = f
+	}
+
+	stop := func() error {
+		var errs []error
+
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+
+			if err := cpuFile.Close(); err != nil {
+				errs = append(errs, fmt.Errorf(
+					"failed to close CPU profile %q: %w: %w",
+					cpuProfile,
+					ErrProfilingFailed,
+					err,
+				))
+			}
+		}
+
+		if memProfile != "" {
+			if err := writeHeapProfile(memProfile); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	return stop, nil
+}
+
+// writeHeapProfile writes a heap profile to the specified file.
+func writeHeapProfile(filename string) error {
+	f, err := os.Create(filepath.Clean(filename))
+	if err != nil {
+		return fmt.Errorf(
+			"failed to create memory profile %q: %w: %w",
+			filename,
+			ErrProfilingFailed,
+			err,
+		)
+	}
+
+	// Collect garbage so that the profile reflects current memory usage.
+	runtime.GC()
+
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf(
+			"failed to write memory profile %q: %w: %w",
+			filename,
+			ErrProfilingFailed,
+			err,
+		)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf(
+			"failed to close memory profile %q: %w: %w",
+			filename,
+			ErrProfilingFailed,
+			err,
+		)
+	}
+
+	return nil
+}
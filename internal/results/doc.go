@@ -0,0 +1,12 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package results provides a transport-neutral representation of
+// certificate chain validation results. This allows consumers other than
+// Nagios plugins (e.g., CLI tools, exporters, chat bots) to encode and
+// evaluate validation results without depending on go-nagios types.
+package results
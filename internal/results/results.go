@@ -0,0 +1,202 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package results
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+)
+
+// State is the overall state of a validation check or set of validation
+// checks.
+type State string
+
+// Supported State values.
+const (
+	StateOK       State = "ok"
+	StateUnknown  State = "unknown"
+	StateWarning  State = "warning"
+	StateCritical State = "critical"
+)
+
+// Check is the result of a single validation check.
+type Check struct {
+	// Name is the human-readable name of the validation check.
+	Name string `json:"name"`
+
+	// State is the state of the validation check result. Ignored validation
+	// check results are reported as OK.
+	State State `json:"state"`
+
+	// Status is a one word status for the validation check result (e.g.,
+	// "failed", "ignored", "successful" or "skipped").
+	Status string `json:"status"`
+
+	// Summary is a brief, one line summary of the validation check result.
+	Summary string `json:"summary"`
+
+	// Detail is additional detail explaining the validation check result.
+	// This may span multiple lines.
+	Detail string `json:"detail,omitempty"`
+
+	// Error is the underlying error for the validation check result (if
+	// any).
+	Error string `json:"error,omitempty"`
+
+	// Priority is the level of importance for the validation check result.
+	// Higher values indicate greater importance.
+	Priority int `json:"priority"`
+}
+
+// Certificate is a brief summary of a certificate from the evaluated
+// certificate chain.
+type Certificate struct {
+	// Position is the position of the certificate in the chain (e.g.,
+	// "leaf", "intermediate" or "root").
+	Position string `json:"position"`
+
+	// Subject is the subject of the certificate.
+	Subject string `json:"subject"`
+
+	// Issuer is the issuer of the certificate.
+	Issuer string `json:"issuer"`
+
+	// SerialNumber is the formatted serial number of the certificate.
+	SerialNumber string `json:"serial"`
+
+	// SANsEntries is the list of DNS Name SANs entries for the certificate.
+	SANsEntries []string `json:"sans_entries,omitempty"`
+
+	// NotBefore is the time the certificate becomes valid.
+	NotBefore time.Time `json:"not_before"`
+
+	// NotAfter is the expiration time of the certificate.
+	NotAfter time.Time `json:"not_after"`
+}
+
+// Details provides counts and a summary of the evaluated certificate chain
+// for a set of validation check results.
+type Details struct {
+	// TotalChecks is the number of validation checks performed.
+	TotalChecks int `json:"total_checks"`
+
+	// Succeeded is the number of successful validation checks.
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of failed (non-ignored) validation checks.
+	Failed int `json:"failed"`
+
+	// Ignored is the number of validation checks with ignored results.
+	Ignored int `json:"ignored"`
+
+	// Certificates is a summary of each certificate in the evaluated
+	// certificate chain.
+	Certificates []Certificate `json:"certificates"`
+}
+
+// Result is the transport-neutral representation of a set of certificate
+// chain validation check results.
+type Result struct {
+	// State is the overall state for the validation check results. The
+	// state is unknown if no validation checks were performed.
+	State State `json:"state"`
+
+	// Checks is the result of each validation check, ordered by priority.
+	Checks []Check `json:"checks"`
+
+	// Details provides counts and a summary of the evaluated certificate
+	// chain.
+	Details Details `json:"details"`
+}
+
+// stateOf maps the state of the given validation check result (or
+// collection of results) to a State value using the same precedence applied
+// when determining the Nagios service state.
+func stateOf(val certs.ServiceStater) State {
+	switch {
+	case val.IsCriticalState():
+		return StateCritical
+	case val.IsWarningState():
+		return StateWarning
+	case val.IsOKState():
+		return StateOK
+	default:
+		return StateUnknown
+	}
+}
+
+// NewCheck converts the given validation check result into a Check.
+func NewCheck(result certs.CertChainValidationResult) Check {
+	check := Check{
+		Name:     result.CheckName(),
+		State:    stateOf(result),
+		Status:   result.ValidationStatus(),
+		Summary:  result.String(),
+		Detail:   result.StatusDetail(),
+		Priority: result.Priority(),
+	}
+
+	if err := result.Err(); err != nil {
+		check.Error = err.Error()
+	}
+
+	return check
+}
+
+// NewCertificates summarizes each certificate in the given certificate
+// chain.
+func NewCertificates(certChain []*x509.Certificate) []Certificate {
+	summaries := make([]Certificate, 0, len(certChain))
+
+	for _, cert := range certChain {
+		summaries = append(summaries, Certificate{
+			Position:     certs.ChainPosition(cert, certChain).String(),
+			Subject:      cert.Subject.String(),
+			Issuer:       cert.Issuer.String(),
+			SerialNumber: certs.FormatCertSerialNumber(cert.SerialNumber),
+			SANsEntries:  cert.DNSNames,
+			NotBefore:    cert.NotBefore.UTC(),
+			NotAfter:     cert.NotAfter.UTC(),
+		})
+	}
+
+	return summaries
+}
+
+// FromValidationResults converts the given validation check results into a
+// Result. The given results are not modified.
+func FromValidationResults(validationResults certs.CertChainValidationResults) Result {
+	sorted := make(certs.CertChainValidationResults, len(validationResults))
+	copy(sorted, validationResults)
+	sorted.Sort()
+
+	result := Result{
+		State:  stateOf(sorted),
+		Checks: make([]Check, 0, len(sorted)),
+		Details: Details{
+			TotalChecks:  sorted.Total(),
+			Succeeded:    sorted.NumSucceeded(),
+			Failed:       sorted.NumFailed(),
+			Ignored:      sorted.NumIgnored(),
+			Certificates: []Certificate{},
+		},
+	}
+
+	for _, validationResult := range sorted {
+		result.Checks = append(result.Checks, NewCheck(validationResult))
+	}
+
+	// All validation check results are for the same certificate chain.
+	if len(sorted) > 0 {
+		result.Details.Certificates = NewCertificates(sorted[0].CertChain())
+	}
+
+	return result
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package results
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/go-nagios"
+)
+
+// stubResult is a minimal validation check result used to exercise the
+// conversion logic without evaluating a real certificate chain.
+type stubResult struct {
+	name     string
+	err      error
+	state    State
+	ignored  bool
+	priority int
+}
+
+func (sr stubResult) Err() error                        { return sr.err }
+func (sr stubResult) CheckName() string                 { return sr.name }
+func (sr stubResult) Status() string                    { return sr.name + " status" }
+func (sr stubResult) Overview() string                  { return "[overview]" }
+func (sr stubResult) StatusDetail() string              { return sr.name + " detail" }
+func (sr stubResult) String() string                    { return sr.Status() + " " + sr.Overview() }
+func (sr stubResult) ServiceState() nagios.ServiceState { return certs.ServiceState(sr) }
+func (sr stubResult) Report() string                    { return sr.String() }
+func (sr stubResult) TotalCerts() int                   { return 0 }
+func (sr stubResult) Priority() int                     { return sr.priority }
+func (sr stubResult) CertChain() []*x509.Certificate    { return nil }
+func (sr stubResult) IsIgnored() bool                   { return sr.ignored }
+func (sr stubResult) IsFailed() bool                    { return sr.err != nil && !sr.ignored }
+func (sr stubResult) IsSucceeded() bool                 { return sr.err == nil && !sr.ignored }
+func (sr stubResult) IsOKState() bool                   { return sr.ignored || sr.state == StateOK }
+
+func (sr stubResult) IsWarningState() bool {
+	return !sr.ignored && sr.state == StateWarning
+}
+
+func (sr stubResult) IsCriticalState() bool {
+	return !sr.ignored && sr.state == StateCritical
+}
+
+func (sr stubResult) IsUnknownState() bool {
+	return !sr.ignored && sr.state == StateUnknown
+}
+
+func (sr stubResult) ValidationStatus() string {
+	switch {
+	case sr.ignored:
+		return certs.ValidationStatusIgnored
+	case sr.err != nil:
+		return certs.ValidationStatusFailed
+	default:
+		return certs.ValidationStatusSuccessful
+	}
+}
+
+func TestFromValidationResults(t *testing.T) {
+	validationResults := certs.CertChainValidationResults{
+		stubResult{name: "Hostname", state: StateOK, priority: 1},
+		stubResult{name: "Expiration", state: StateWarning, err: errors.New("expiring"), priority: 5},
+		stubResult{name: "SANs List", state: StateCritical, err: errors.New("mismatch"), ignored: true, priority: 3},
+	}
+
+	result := FromValidationResults(validationResults)
+
+	if result.State != StateWarning {
+		t.Errorf("want state %q; got %q", StateWarning, result.State)
+	}
+
+	wantOrder := []string{"Expiration", "SANs List", "Hostname"}
+	if len(result.Checks) != len(wantOrder) {
+		t.Fatalf("want %d checks; got %d", len(wantOrder), len(result.Checks))
+	}
+
+	for i, name := range wantOrder {
+		if result.Checks[i].Name != name {
+			t.Errorf("want check %d to be %q; got %q", i, name, result.Checks[i].Name)
+		}
+	}
+
+	if result.Checks[0].Error != "expiring" || result.Checks[0].Status != certs.ValidationStatusFailed {
+		t.Errorf("want failed Expiration check with error; got %+v", result.Checks[0])
+	}
+
+	if result.Checks[1].State != StateOK || result.Checks[1].Status != certs.ValidationStatusIgnored {
+		t.Errorf("want ignored SANs List check reported as OK; got %+v", result.Checks[1])
+	}
+
+	if result.Details.TotalChecks != 3 || result.Details.Succeeded != 1 ||
+		result.Details.Failed != 1 || result.Details.Ignored != 1 {
+		t.Errorf("want 3 checks (1 succeeded, 1 failed, 1 ignored); got %+v", result.Details)
+	}
+
+	if validationResults[0].CheckName() != "Hostname" {
+		t.Error("want given validation results unmodified")
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("want no error encoding result; got %v", err)
+	}
+
+	if !strings.Contains(string(encoded), `"state":"warning"`) {
+		t.Errorf("want encoded state; got %s", encoded)
+	}
+}
+
+func TestFromValidationResultsEmpty(t *testing.T) {
+	result := FromValidationResults(nil)
+
+	if result.State != StateUnknown {
+		t.Errorf("want state %q; got %q", StateUnknown, result.State)
+	}
+
+	if result.Checks == nil || result.Details.Certificates == nil {
+		t.Error("want empty (non-nil) checks and certificates")
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package secrets provides support for retrieving secret values (e.g.,
+// passwords and API tokens) from environment variables, files or the output
+// of external commands so that plaintext secrets do not need to be provided
+// on the command line.
+package secrets
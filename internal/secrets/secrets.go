@@ -0,0 +1,256 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Prefixes used by provider specifications to indicate where a secret value
+// is retrieved from.
+const (
+	EnvPrefix     string = "env:"
+	FilePrefix    string = "file:"
+	CommandPrefix string = "cmd:"
+)
+
+var (
+	// ErrPlaintextSecret indicates that a provider specification was given
+	// in place of a secret value instead of a supported provider prefix.
+	ErrPlaintextSecret = errors.New("plaintext secret values are not supported")
+
+	// ErrInvalidProvider indicates that a provider specification is
+	// incomplete.
+	ErrInvalidProvider = errors.New("invalid secret provider")
+
+	// ErrSecretNotFound indicates that the secret value could not be found
+	// at the location given by a provider.
+	ErrSecretNotFound = errors.New("secret not found")
+
+	// ErrEmptySecret indicates that a provider returned an empty secret
+	// value.
+	ErrEmptySecret = errors.New("secret value is empty")
+)
+
+// Provider retrieves a secret value.
+type Provider interface {
+	// Secret retrieves the secret value. The given context limits the time
+	// spent retrieving the value.
+	Secret(ctx context.Context) (string, error)
+
+	// Source describes where the secret value is retrieved from without
+	// disclosing the value.
+	Source() string
+}
+
+// EnvProvider retrieves a secret value from an environment variable.
+type EnvProvider struct {
+	// Name is the name of the environment variable.
+	Name string
+}
+
+// FileProvider retrieves a secret value from the first line of a file.
+type FileProvider struct {
+	// Path is the path to the file.
+	Path string
+}
+
+// CommandProvider retrieves a secret value from the output of an external
+// command (e.g., a password manager CLI).
+type CommandProvider struct {
+	// Command is the command and whitespace separated arguments to run. The
+	// command is executed directly instead of via a shell.
+	Command string
+}
+
+// cachedProvider retrieves a secret value from another provider once and
+// returns the same result for later requests.
+type cachedProvider struct {
+	provider Provider
+	once     sync.Once
+	value    string
+	err      error
+}
+
+// ParseProvider returns the provider for the given specification. Supported
+// specifications are env:NAME, file:PATH and cmd:COMMAND. ErrPlaintextSecret
+// is returned for values without a supported prefix.
+func ParseProvider(spec string) (Provider, error) {
+	var provider Provider
+	var value string
+
+	switch {
+	case strings.HasPrefix(spec, EnvPrefix):
+		value = strings.TrimPrefix(spec, EnvPrefix)
+		provider = EnvProvider{Name: value}
+
+	case strings.HasPrefix(spec, FilePrefix):
+		value = strings.TrimPrefix(spec, FilePrefix)
+		provider = FileProvider{Path: value}
+
+	case strings.HasPrefix(spec, CommandPrefix):
+		value = strings.TrimPrefix(spec, CommandPrefix)
+		provider = CommandProvider{Command: value}
+
+	default:
+		return nil, fmt.Errorf(
+			"%w; use one of %s, %s or %s prefixes",
+			ErrPlaintextSecret,
+			EnvPrefix+"NAME",
+			FilePrefix+"PATH",
+			CommandPrefix+"COMMAND",
+		)
+	}
+
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf(
+			"missing value for %q secret provider: %w",
+			spec,
+			ErrInvalidProvider,
+		)
+	}
+
+	return provider, nil
+}
+
+// Cached returns a provider which retrieves the secret value from the given
+// provider at most once. This avoids repeatedly running external commands
+// when the same secret is used for multiple targets.
+func Cached(provider Provider) Provider {
+	return &cachedProvider{provider: provider}
+}
+
+// Secret retrieves the secret value from the environment variable.
+func (ep EnvProvider) Secret(_ context.Context) (string, error) {
+	value, ok := os.LookupEnv(ep.Name)
+	if !ok {
+		return "", fmt.Errorf(
+			"environment variable %q not set: %w",
+			ep.Name,
+			ErrSecretNotFound,
+		)
+	}
+
+	return nonEmpty(ep, value)
+}
+
+// Source describes the environment variable providing the secret value.
+func (ep EnvProvider) Source() string {
+	return "environment variable " + ep.Name
+}
+
+// Secret retrieves the secret value from the first line of the file.
+func (fp FileProvider) Secret(_ context.Context) (string, error) {
+	content, err := os.ReadFile(fp.Path)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to read secret file %q: %w: %w",
+			fp.Path,
+			ErrSecretNotFound,
+			err,
+		)
+	}
+
+	return nonEmpty(fp, firstLine(content))
+}
+
+// Source describes the file providing the secret value.
+func (fp FileProvider) Source() string {
+	return "file " + fp.Path
+}
+
+// Secret runs the command and retrieves the secret value from the first line
+// of its output.
+func (cp CommandProvider) Secret(ctx context.Context) (string, error) {
+	args := strings.Fields(cp.Command)
+	if len(args) == 0 {
+		return "", fmt.Errorf(
+			"command for secret not provided: %w",
+			ErrInvalidProvider,
+		)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	// #nosec G204 -- the command is provided by the sysadmin for this purpose
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = "no error output"
+		}
+
+		return "", fmt.Errorf(
+			"failed to run secret command %q (%s): %w",
+			args[0],
+			msg,
+			err,
+		)
+	}
+
+	return nonEmpty(cp, firstLine(stdout.Bytes()))
+}
+
+// Source describes the command providing the secret value. Only the command
+// name is included as arguments may contain sensitive values.
+func (cp CommandProvider) Source() string {
+	args := strings.Fields(cp.Command)
+	if len(args) == 0 {
+		return "command"
+	}
+
+	return "command " + args[0]
+}
+
+// Secret retrieves the secret value from the wrapped provider on first use
+// and returns the same result afterwards.
+func (cp *cachedProvider) Secret(ctx context.Context) (string, error) {
+	cp.once.Do(func() {
+		cp.value, cp.err = cp.provider.Secret(ctx)
+	})
+
+	return cp.value, cp.err
+}
+
+// Source describes where the wrapped provider retrieves the secret value
+// from.
+func (cp *cachedProvider) Source() string {
+	return cp.provider.Source()
+}
+
+// firstLine returns the first line of the given content without the line
+// ending.
+func firstLine(content []byte) string {
+	line, _, _ := strings.Cut(string(content), "\n")
+
+	return strings.TrimSuffix(line, "\r")
+}
+
+// nonEmpty returns the given secret value or ErrEmptySecret if the value is
+// empty.
+func nonEmpty(provider Provider, value string) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf(
+			"%s provided %w",
+			provider.Source(),
+			ErrEmptySecret,
+		)
+	}
+
+	return value, nil
+}
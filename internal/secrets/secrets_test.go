@@ -0,0 +1,151 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		provider Provider
+		err      error
+	}{
+		{
+			name:     "Env",
+			spec:     "env:CT_TOKEN",
+			provider: EnvProvider{Name: "CT_TOKEN"},
+		},
+		{
+			name:     "File",
+			spec:     "file:/etc/nagios/ct-token",
+			provider: FileProvider{Path: "/etc/nagios/ct-token"},
+		},
+		{
+			name:     "Command",
+			spec:     "cmd:pass show monitoring/ct-token",
+			provider: CommandProvider{Command: "pass show monitoring/ct-token"},
+		},
+		{
+			name: "Plaintext",
+			spec: "s3cr3t",
+			err:  ErrPlaintextSecret,
+		},
+		{
+			name: "MissingValue",
+			spec: "cmd: ",
+			err:  ErrInvalidProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := ParseProvider(tt.spec)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if provider != tt.provider {
+				t.Errorf("want provider %#v; got %#v", tt.provider, provider)
+			}
+		})
+	}
+}
+
+func TestProviderSecret(t *testing.T) {
+	t.Setenv("CHECK_CERT_TEST_TOKEN", "env-token")
+	t.Setenv("CHECK_CERT_TEST_EMPTY", "")
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\r\nignored\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		provider Provider
+		secret   string
+		err      error
+	}{
+		{
+			name:     "Env",
+			provider: EnvProvider{Name: "CHECK_CERT_TEST_TOKEN"},
+			secret:   "env-token",
+		},
+		{
+			name:     "EnvNotSet",
+			provider: EnvProvider{Name: "CHECK_CERT_TEST_MISSING"},
+			err:      ErrSecretNotFound,
+		},
+		{
+			name:     "EnvEmpty",
+			provider: EnvProvider{Name: "CHECK_CERT_TEST_EMPTY"},
+			err:      ErrEmptySecret,
+		},
+		{
+			name:     "File",
+			provider: FileProvider{Path: tokenFile},
+			secret:   "file-token",
+		},
+		{
+			name:     "FileMissing",
+			provider: FileProvider{Path: filepath.Join(dir, "missing")},
+			err:      ErrSecretNotFound,
+		},
+	}
+
+	if _, err := exec.LookPath("echo"); err == nil {
+		tests = append(tests, struct {
+			name     string
+			provider Provider
+			secret   string
+			err      error
+		}{
+			name:     "Command",
+			provider: CommandProvider{Command: "echo cmd-token"},
+			secret:   "cmd-token",
+		})
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			secret, err := tt.provider.Secret(context.Background())
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("want error %v; got %v", tt.err, err)
+			}
+
+			if secret != tt.secret {
+				t.Errorf("want secret %q; got %q", tt.secret, secret)
+			}
+		})
+	}
+}
+
+func TestCached(t *testing.T) {
+	t.Setenv("CHECK_CERT_TEST_TOKEN", "first")
+
+	provider := Cached(EnvProvider{Name: "CHECK_CERT_TEST_TOKEN"})
+	if secret, err := provider.Secret(context.Background()); err != nil || secret != "first" {
+		t.Fatalf("want secret %q; got %q (error: %v)", "first", secret, err)
+	}
+
+	t.Setenv("CHECK_CERT_TEST_TOKEN", "second")
+	if secret, err := provider.Secret(context.Background()); err != nil || secret != "first" {
+		t.Errorf("want cached secret %q; got %q (error: %v)", "first", secret, err)
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package stats provides local-only, aggregate run statistics (targets
+// checked, failures by category and runtime) recorded to a file so that
+// sysadmins are able to review monitoring coverage over time. No statistics
+// are sent anywhere; the file is only read and written locally.
+package stats
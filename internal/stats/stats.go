@@ -0,0 +1,408 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/netutils"
+)
+
+// ErrInvalidRecord indicates that a run statistics record could not be
+// decoded.
+var ErrInvalidRecord = errors.New("invalid run statistics record")
+
+// Failure categories used to group the errors recorded for a run.
+const (
+	CategoryDNS        string = "dns"
+	CategoryConnection string = "connection"
+	CategoryNoCerts    string = "no_certs"
+	CategoryExpiration string = "expiration"
+	CategoryHostname   string = "hostname"
+	CategorySANs       string = "sans"
+	CategoryChain      string = "chain"
+	CategoryProtocol   string = "protocol"
+	CategoryOther      string = "other"
+)
+
+// categoryErrors maps failure categories to the errors associated with
+// each. Categories are evaluated in order; the first match wins.
+var categoryErrors = []struct {
+	category string
+	errs     []error
+}{
+	{CategoryDNS, []error{netutils.ErrHostnameFailsNameResolution}},
+	{CategoryNoCerts, []error{certs.ErrNoCertsFound}},
+	{CategoryExpiration, []error{
+		certs.ErrExpiredCertsFound,
+		certs.ErrExpiringCertsFound,
+		certs.ErrCertChainExpiryCliff,
+		certs.ErrRenewalWindowStarted,
+		certs.ErrNewerCertInCTLogs,
+	}},
+	{CategoryHostname, []error{certs.ErrHostnameVerificationFailed}},
+	{CategorySANs, []error{
+		certs.ErrCertMissingSANsEntries,
+		certs.ErrCertHasUnexpectedSANsEntries,
+		certs.ErrCertHasMissingAndUnexpectedSANsEntries,
+	}},
+	{CategoryChain, []error{
+		certs.ErrIncompleteCertificateChain,
+		certs.ErrSignatureVerificationFailed,
+		certs.ErrCertChainHasDuplicateCerts,
+		certs.ErrCertIsSelfSigned,
+		certs.ErrCertChainConstraintsViolation,
+		certs.ErrCertChainKeyUsageMismatch,
+		certs.ErrCertChainHasDistrustedCA,
+	}},
+	{CategoryProtocol, []error{
+		certs.ErrLegacyTLSVersionAccepted,
+		certs.ErrWeakCipherSuitesAccepted,
+	}},
+}
+
+// FailureCategory returns the failure category for the given error (e.g.,
+// "dns", "connection" or "expiration").
+func FailureCategory(err error) string {
+	for _, entry := range categoryErrors {
+		for _, categoryErr := range entry.errs {
+			if errors.Is(err, categoryErr) {
+				return entry.category
+			}
+		}
+	}
+
+	if netutils.IsConnectionFailure(err) {
+		return CategoryConnection
+	}
+
+	return CategoryOther
+}
+
+// Record is the aggregate statistics for a single run.
+type Record struct {
+	// Time is when the run completed.
+	Time time.Time `json:"time"`
+
+	// App is the name of the application which recorded the run.
+	App string `json:"app"`
+
+	// Targets is the number of targets (e.g., certificate chains) checked.
+	Targets int `json:"targets"`
+
+	// Failures is the number of failures recorded for the run by failure
+	// category.
+	Failures map[string]int `json:"failures,omitempty"`
+
+	// RuntimeSeconds is the time taken by the run in seconds.
+	RuntimeSeconds float64 `json:"runtime_seconds"`
+}
+
+// NewRecord creates a run statistics record for the given application,
+// number of targets checked, recorded errors (grouped by failure category)
+// and runtime.
+func NewRecord(app string, targets int, errs []error, runtime time.Duration) Record {
+	record := Record{
+		Time:           time.Now().UTC(),
+		App:            app,
+		Targets:        targets,
+		RuntimeSeconds: runtime.Seconds(),
+	}
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if record.Failures == nil {
+			record.Failures = make(map[string]int)
+		}
+		record.Failures[FailureCategory(err)]++
+	}
+
+	return record
+}
+
+// TotalFailures returns the number of failures recorded for the run across
+// all failure categories.
+func (r Record) TotalFailures() int {
+	var total int
+	for _, count := range r.Failures {
+		total += count
+	}
+
+	return total
+}
+
+// AppendFile appends the given run statistics record to the specified file
+// as a single line of JSON. The file is created if it does not already
+// exist.
+func AppendFile(filename string, record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to encode run statistics for file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	f, err := os.OpenFile(filepath.Clean(filename), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to open run statistics file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf(
+			"failed to write run statistics file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf(
+			"failed to close run statistics file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return nil
+}
+
+// ReadRecords decodes run statistics records (one JSON object per line)
+// from the given reader. Blank lines are ignored.
+func ReadRecords(r io.Reader) ([]Record, error) {
+	var records []Record
+
+	scanner := bufio.NewScanner(r)
+
+	var lineNum int
+	for scanner.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, fmt.Errorf(
+				"line %d: %w: %w",
+				lineNum,
+				ErrInvalidRecord,
+				err,
+			)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf(
+			"failed to read run statistics records: %w",
+			err,
+		)
+	}
+
+	return records, nil
+}
+
+// LoadFile reads run statistics records from the specified file.
+func LoadFile(filename string) ([]Record, error) {
+	f, err := os.Open(filepath.Clean(filename))
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to open run statistics file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	records, err := ReadRecords(f)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to parse run statistics file %q: %w",
+			filename,
+			err,
+		)
+	}
+
+	return records, nil
+}
+
+// Period is the aggregate statistics for all runs recorded on a single day
+// (UTC).
+type Period struct {
+	// Day is the start of the day (UTC) for the period.
+	Day time.Time
+
+	// Runs is the number of runs recorded.
+	Runs int
+
+	// Targets is the number of targets checked across all runs.
+	Targets int
+
+	// Failures is the number of failures recorded across all runs.
+	Failures int
+
+	// Runtime is the total time taken by all runs.
+	Runtime time.Duration
+}
+
+// AverageRuntime returns the average time taken by runs in the period.
+func (p Period) AverageRuntime() time.Duration {
+	if p.Runs == 0 {
+		return 0
+	}
+
+	return p.Runtime / time.Duration(p.Runs)
+}
+
+// Summarize groups the given run statistics records by day (UTC), returning
+// the periods in chronological order.
+func Summarize(records []Record) []Period {
+	index := make(map[time.Time]*Period)
+
+	for _, record := range records {
+		day := record.Time.UTC().Truncate(24 * time.Hour)
+
+		period, ok := index[day]
+		if !ok {
+			period = &Period{Day: day}
+			index[day] = period
+		}
+
+		period.Runs++
+		period.Targets += record.Targets
+		period.Failures += record.TotalFailures()
+		period.Runtime += time.Duration(record.RuntimeSeconds * float64(time.Second))
+	}
+
+	periods := make([]Period, 0, len(index))
+	for _, period := range index {
+		periods = append(periods, *period)
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Day.Before(periods[j].Day)
+	})
+
+	return periods
+}
+
+// trend returns a brief indicator of the change between the previous and
+// current values.
+func trend(previous int, current int, first bool) string {
+	switch {
+	case first:
+		return ""
+	case current > previous:
+		return "+" + strconv.Itoa(current-previous)
+	case current < previous:
+		return "-" + strconv.Itoa(previous-current)
+	default:
+		return "="
+	}
+}
+
+// WriteSummary writes a daily summary of the given run statistics records
+// to the given writer, followed by the total number of failures recorded
+// for each failure category.
+func WriteSummary(w io.Writer, records []Record) error {
+	periods := Summarize(records)
+
+	_, _ = fmt.Fprintf(w, "%d runs recorded over %d days\n\n", len(records), len(periods))
+
+	tw := tabwriter.NewWriter(w, 4, 4, 4, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Day\tRuns\tTargets\tTargets (trend)\tFailures\tFailures (trend)\tAvg Runtime")
+	_, _ = fmt.Fprintln(tw, "---\t----\t-------\t---------------\t--------\t----------------\t-----------")
+
+	for i, period := range periods {
+		var previous Period
+		if i > 0 {
+			previous = periods[i-1]
+		}
+
+		_, _ = fmt.Fprintf(
+			tw,
+			"%s\t%d\t%d\t%s\t%d\t%s\t%v\n",
+			period.Day.Format("2006-01-02"),
+			period.Runs,
+			period.Targets,
+			trend(previous.Targets, period.Targets, i == 0),
+			period.Failures,
+			trend(previous.Failures, period.Failures, i == 0),
+			period.AverageRuntime().Round(time.Millisecond),
+		)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("error occurred flushing tabwriter: %w", err)
+	}
+
+	categories := make(map[string]int)
+	for _, record := range records {
+		for category, count := range record.Failures {
+			categories[category] += count
+		}
+	}
+
+	names := make([]string, 0, len(categories))
+	for category := range categories {
+		names = append(names, category)
+	}
+
+	// Most frequent failure categories first.
+	sort.Slice(names, func(i, j int) bool {
+		if categories[names[i]] != categories[names[j]] {
+			return categories[names[i]] > categories[names[j]]
+		}
+
+		return names[i] < names[j]
+	})
+
+	_, _ = fmt.Fprintln(w, "\nFailures by category:")
+	_, _ = fmt.Fprintln(w)
+
+	if len(names) == 0 {
+		_, _ = fmt.Fprintln(w, "  None")
+
+		return nil
+	}
+
+	for _, category := range names {
+		_, _ = fmt.Fprintf(w, "  %s: %d\n", category, categories[category])
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package stats
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/netutils"
+)
+
+func TestNewRecordFailureCategories(t *testing.T) {
+	errs := []error{
+		fmt.Errorf("leaf certificate: %w", certs.ErrExpiringCertsFound),
+		fmt.Errorf("www.example.com invalid; %w", netutils.ErrHostnameFailsNameResolution),
+		certs.ErrExpiredCertsFound,
+		errors.New("something unexpected"),
+		nil,
+	}
+
+	record := NewRecord("check_cert", 3, errs, 1500*time.Millisecond)
+
+	expected := map[string]int{
+		CategoryExpiration: 2,
+		CategoryDNS:        1,
+		CategoryOther:      1,
+	}
+
+	if !reflect.DeepEqual(record.Failures, expected) {
+		t.Errorf("want failures %v; got %v", expected, record.Failures)
+	}
+
+	if record.TotalFailures() != 4 {
+		t.Errorf("want 4 total failures; got %d", record.TotalFailures())
+	}
+
+	if record.RuntimeSeconds != 1.5 {
+		t.Errorf("want runtime of 1.5 seconds; got %v", record.RuntimeSeconds)
+	}
+}
+
+func TestAppendFileAndLoadFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "stats.jsonl")
+
+	first := Record{
+		Time:           time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+		App:            "certsum",
+		Targets:        10,
+		Failures:       map[string]int{CategoryConnection: 2},
+		RuntimeSeconds: 4,
+	}
+	second := Record{
+		Time:           time.Date(2024, 6, 1, 22, 0, 0, 0, time.UTC),
+		App:            "certsum",
+		Targets:        12,
+		RuntimeSeconds: 2,
+	}
+	third := Record{
+		Time:           time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC),
+		App:            "check_cert",
+		Targets:        1,
+		Failures:       map[string]int{CategoryExpiration: 1},
+		RuntimeSeconds: 1,
+	}
+
+	for _, record := range []Record{first, second, third} {
+		if err := AppendFile(filename, record); err != nil {
+			t.Fatalf("want no error appending record; got %v", err)
+		}
+	}
+
+	records, err := LoadFile(filename)
+	if err != nil {
+		t.Fatalf("want no error loading records; got %v", err)
+	}
+
+	if !reflect.DeepEqual(records, []Record{first, second, third}) {
+		t.Fatalf("want appended records; got %+v", records)
+	}
+
+	periods := Summarize(records)
+	if len(periods) != 2 {
+		t.Fatalf("want 2 periods; got %d", len(periods))
+	}
+
+	if periods[0].Runs != 2 || periods[0].Targets != 22 || periods[0].Failures != 2 {
+		t.Errorf("want 2 runs, 22 targets and 2 failures for first day; got %+v", periods[0])
+	}
+
+	if periods[0].AverageRuntime() != 3*time.Second {
+		t.Errorf("want average runtime of 3s for first day; got %v", periods[0].AverageRuntime())
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSummary(&buf, records); err != nil {
+		t.Fatalf("want no error writing summary; got %v", err)
+	}
+
+	for _, want := range []string{"3 runs recorded over 2 days", "2024-06-02", "connection: 2", "expiration: 1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("want summary to contain %q; got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestReadRecordsInvalidLine(t *testing.T) {
+	input := "{\"app\":\"certsum\",\"targets\":1}\n\nnot json\n"
+
+	_, err := ReadRecords(strings.NewReader(input))
+	if !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("want error %v; got %v", ErrInvalidRecord, err)
+	}
+}
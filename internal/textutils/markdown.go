@@ -0,0 +1,43 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package textutils
+
+import (
+	"strings"
+)
+
+// markdownCellReplacer escapes characters which would otherwise break the
+// layout of a Markdown table cell.
+var markdownCellReplacer = strings.NewReplacer(
+	`|`, `\|`,
+	"\r\n", "<br>",
+	"\n", "<br>",
+	"\t", " ",
+)
+
+// MarkdownTableRow formats the given values as a row in a Markdown table.
+// Pipe characters and newlines within values are escaped.
+func MarkdownTableRow(cells ...string) string {
+	escaped := make([]string, 0, len(cells))
+	for _, cell := range cells {
+		escaped = append(escaped, markdownCellReplacer.Replace(strings.TrimSpace(cell)))
+	}
+
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+// MarkdownTableHeader formats the given column names as the header and
+// delimiter rows of a Markdown table.
+func MarkdownTableHeader(columns ...string) string {
+	delimiters := make([]string, 0, len(columns))
+	for range columns {
+		delimiters = append(delimiters, "---")
+	}
+
+	return MarkdownTableRow(columns...) + "\n" + MarkdownTableRow(delimiters...)
+}
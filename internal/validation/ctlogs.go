@@ -0,0 +1,68 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package validation
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// ctLogsSearchDomain returns the name used to search Certificate
+// Transparency logs for the given certificate chain. The sysadmin-specified
+// DNS Name or server value is preferred. If the server was specified by IP
+// Address (or not specified), the first SANs entry (or Subject CommonName) of
+// the leaf certificate is used instead.
+func ctLogsSearchDomain(server string, dnsName string, certChain []*x509.Certificate) string {
+	leafCerts := certs.LeafCerts(certChain)
+
+	switch {
+	case dnsName != "":
+		return dnsName
+
+	case server != "" && net.ParseIP(server) == nil:
+		return server
+
+	case len(leafCerts) == 0:
+		return ""
+
+	case len(leafCerts[0].DNSNames) > 0:
+		return leafCerts[0].DNSNames[0]
+
+	default:
+		return leafCerts[0].Subject.CommonName
+	}
+}
+
+// ctSearchAPIToken retrieves the API token sent with CT search API requests
+// from the user-specified secret provider. An empty string is returned if an
+// API token was not specified.
+func ctSearchAPIToken(cfg *config.Config) (string, error) {
+	provider := cfg.CTSearchTokenProvider()
+	if provider == nil {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout())
+	defer cancel()
+
+	token, err := provider.Secret(ctx)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed to retrieve CT search API token from %s: %w",
+			provider.Source(),
+			err,
+		)
+	}
+
+	return token, nil
+}
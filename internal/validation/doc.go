@@ -0,0 +1,12 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+// Package validation applies the collection of validation checks provided by
+// this project to a certificate chain using the sysadmin-specified
+// configuration. This allows each application evaluating certificate chains
+// to share the same validation logic.
+package validation
@@ -0,0 +1,71 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package validation
+
+import (
+	"crypto/x509"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// ExpirationThresholds returns the CRITICAL and WARNING certificate age
+// thresholds in days for the given certificate chain. Thresholds specified
+// as a percentage of certificate lifetime remaining are converted to days
+// using the lifetime of the leaf certificate (or the next certificate to
+// expire if a leaf certificate is not present). The thresholds specified in
+// days are used otherwise.
+func ExpirationThresholds(cfg *config.Config, certChain []*x509.Certificate) (int, int) {
+	if cfg.AgeCriticalPercent == 0 || cfg.AgeWarningPercent == 0 {
+		return cfg.AgeCritical, cfg.AgeWarning
+	}
+
+	cert := percentThresholdsCert(certChain)
+
+	ageCritical, critErr := certs.LifetimePercentageInDays(cert, cfg.AgeCriticalPercent)
+	ageWarning, warnErr := certs.LifetimePercentageInDays(cert, cfg.AgeWarningPercent)
+	if critErr != nil || warnErr != nil {
+		return cfg.AgeCritical, cfg.AgeWarning
+	}
+
+	return ageCritical, ageWarning
+}
+
+// ExpirationPercentThresholds returns the CRITICAL and WARNING percentage of
+// lifetime remaining thresholds used by ExpirationThresholds to calculate
+// the number of days thresholds for the given certificate chain. Zero values
+// are returned if percentage thresholds were not specified or could not be
+// applied.
+func ExpirationPercentThresholds(cfg *config.Config, certChain []*x509.Certificate) (int, int) {
+	if cfg.AgeCriticalPercent == 0 || cfg.AgeWarningPercent == 0 {
+		return 0, 0
+	}
+
+	cert := percentThresholdsCert(certChain)
+
+	_, critErr := certs.LifetimePercentageInDays(cert, cfg.AgeCriticalPercent)
+	_, warnErr := certs.LifetimePercentageInDays(cert, cfg.AgeWarningPercent)
+	if critErr != nil || warnErr != nil {
+		return 0, 0
+	}
+
+	return cfg.AgeCriticalPercent, cfg.AgeWarningPercent
+}
+
+// percentThresholdsCert returns the certificate whose lifetime is used to
+// convert percentage of lifetime remaining thresholds to a number of days;
+// the oldest leaf certificate or the next certificate to expire if a leaf
+// certificate is not present.
+func percentThresholdsCert(certChain []*x509.Certificate) *x509.Certificate {
+	cert := certs.OldestLeafCert(certChain)
+	if cert == nil {
+		cert = certs.NextToExpire(certChain, false)
+	}
+
+	return cert
+}
@@ -0,0 +1,162 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// newTestCertWithValidity generates a certificate with the given Common Name
+// and validity period issued by the given parent (or self-signed if parent
+// is nil). The certificate and its private key are returned.
+func newTestCertWithValidity(
+	t *testing.T,
+	commonName string,
+	isCA bool,
+	notBefore time.Time,
+	notAfter time.Time,
+	parent *x509.Certificate,
+	parentKey *ecdsa.PrivateKey,
+) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	switch {
+	case isCA:
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	default:
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+		template.DNSNames = []string{commonName}
+	}
+
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatalf("failed to create certificate %q: %v", commonName, err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate %q: %v", commonName, err)
+	}
+
+	return cert, key
+}
+
+func TestExpirationThresholds(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	const day = 24 * time.Hour
+
+	// The intermediate certificate (200 day lifetime) expires before the
+	// leaf certificate (100 day lifetime) so that the certificate used to
+	// convert percentage thresholds is evident from the result.
+	root, rootKey := newTestCertWithValidity(t, "Test Root CA", true, now.Add(-day), now.Add(999*day), nil, nil)
+	intermediate, intermediateKey := newTestCertWithValidity(t, "Test Intermediate CA", true, now.Add(-170*day), now.Add(30*day), root, rootKey)
+	leaf, _ := newTestCertWithValidity(t, "www.example.com", false, now.Add(-day), now.Add(99*day), intermediate, intermediateKey)
+
+	withLeaf := []*x509.Certificate{leaf, intermediate, root}
+	withoutLeaf := []*x509.Certificate{intermediate, root}
+
+	tests := []struct {
+		name                string
+		cfg                 config.Config
+		certChain           []*x509.Certificate
+		wantCritical        int
+		wantWarning         int
+		wantCriticalPercent int
+		wantWarningPercent  int
+	}{
+		{
+			name:         "DaysThresholds",
+			cfg:          config.Config{AgeCritical: 15, AgeWarning: 30},
+			certChain:    withLeaf,
+			wantCritical: 15,
+			wantWarning:  30,
+		},
+		{
+			name:         "SinglePercentThresholdUsesDays",
+			cfg:          config.Config{AgeCritical: 15, AgeWarning: 30, AgeWarningPercent: 20},
+			certChain:    withLeaf,
+			wantCritical: 15,
+			wantWarning:  30,
+		},
+		{
+			name:                "PercentThresholdsUseOldestLeafCert",
+			cfg:                 config.Config{AgeCritical: 15, AgeWarning: 30, AgeCriticalPercent: 10, AgeWarningPercent: 20},
+			certChain:           withLeaf,
+			wantCritical:        10,
+			wantWarning:         20,
+			wantCriticalPercent: 10,
+			wantWarningPercent:  20,
+		},
+		{
+			name:                "PercentThresholdsUseNextToExpireWithoutLeafCert",
+			cfg:                 config.Config{AgeCritical: 15, AgeWarning: 30, AgeCriticalPercent: 10, AgeWarningPercent: 20},
+			certChain:           withoutLeaf,
+			wantCritical:        20,
+			wantWarning:         40,
+			wantCriticalPercent: 10,
+			wantWarningPercent:  20,
+		},
+		{
+			name:         "PercentThresholdsFallBackToDaysWithoutCerts",
+			cfg:          config.Config{AgeCritical: 15, AgeWarning: 30, AgeCriticalPercent: 10, AgeWarningPercent: 20},
+			wantCritical: 15,
+			wantWarning:  30,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			critical, warning := ExpirationThresholds(&tt.cfg, tt.certChain)
+			if critical != tt.wantCritical || warning != tt.wantWarning {
+				t.Errorf(
+					"want thresholds %d, %d; got %d, %d",
+					tt.wantCritical, tt.wantWarning,
+					critical, warning,
+				)
+			}
+
+			criticalPercent, warningPercent := ExpirationPercentThresholds(&tt.cfg, tt.certChain)
+			if criticalPercent != tt.wantCriticalPercent || warningPercent != tt.wantWarningPercent {
+				t.Errorf(
+					"want percent thresholds %d, %d; got %d, %d",
+					tt.wantCriticalPercent, tt.wantWarningPercent,
+					criticalPercent, warningPercent,
+				)
+			}
+		})
+	}
+}
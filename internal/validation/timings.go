@@ -0,0 +1,63 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package validation
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Timing records the time taken to perform a validation check.
+type Timing struct {
+	// Name is the name of the validation check.
+	Name string
+
+	// Duration is the time taken to perform the validation check.
+	Duration time.Duration
+}
+
+// Timings is a collection of validation check timings in the order the
+// validation checks were performed.
+type Timings []Timing
+
+// record adds the time elapsed since the given start time for the named
+// validation check to the collection.
+func (t *Timings) record(name string, start time.Time) {
+	*t = append(*t, Timing{
+		Name:     name,
+		Duration: time.Since(start),
+	})
+}
+
+// Total returns the combined time taken to perform all recorded validation
+// checks.
+func (t Timings) Total() time.Duration {
+	var total time.Duration
+	for _, timing := range t {
+		total += timing.Duration
+	}
+
+	return total
+}
+
+// log emits a debug message for each recorded validation check timing
+// followed by the combined total.
+func (t Timings) log(logger zerolog.Logger) {
+	for _, timing := range t {
+		logger.Debug().
+			Str("check", timing.Name).
+			Dur("duration", timing.Duration).
+			Msg("Validation check timing")
+	}
+
+	logger.Debug().
+		Int("checks_timed", len(t)).
+		Dur("total_duration", t.Total()).
+		Msg("Validation checks completed")
+}
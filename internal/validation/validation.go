@@ -0,0 +1,916 @@
+// Copyright 2022 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package validation
+
+import (
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/atc0005/check-cert/internal/budget"
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+	"github.com/atc0005/check-cert/internal/netutils"
+	"github.com/rs/zerolog"
+)
+
+// skippedTimeBudgetReason is the reason recorded for validation checks
+// skipped because the plugin timeout is approaching.
+const skippedTimeBudgetReason string = "time budget"
+
+// skippedNoConnectionReason is the reason recorded for validation checks
+// requiring a connection to the server skipped because the certificate
+// chain was not retrieved from a server.
+const skippedNoConnectionReason string = "certificate chain not retrieved from server"
+
+// Endpoint is the certificate-enabled service a certificate chain was
+// retrieved from. This is the zero value if the certificate chain was read
+// from a file.
+type Endpoint struct {
+	// HostVal is the host value used for SNI-enabled connections.
+	HostVal string
+
+	// IPAddr is the IP Address used to connect to the service.
+	IPAddr string
+
+	// Port is the TCP port used to connect to the service.
+	Port int
+}
+
+// validationCheck is a validation check applied to a certificate chain.
+type validationCheck struct {
+	// name is the human-readable name of the validation check.
+	name string
+
+	// keyword is the keyword used to refer to the validation check when
+	// specifying validation check related flags.
+	keyword string
+
+	// optional indicates whether the validation check may be skipped if the
+	// plugin timeout is approaching.
+	optional bool
+
+	// reserve is the minimum time required before the plugin timeout in
+	// order to perform an optional validation check. This is non-zero for
+	// validation checks which depend on external services.
+	reserve time.Duration
+
+	// run performs the validation check.
+	run func() certs.CertChainValidationResult
+}
+
+// Run acts as a wrapper around the validation checks applied to a
+// certificate chain retrieved from (or intended for) the given server and DNS
+// Name values. Validation checks requiring additional connections to the
+// server use the given endpoint. The time taken to perform each validation
+// check is returned alongside the validation results.
+//
+// Hostname validation check results are ignored if neither a server nor a
+// DNS Name value is given (e.g., for a host specified by IP Address as part
+// of a range).
+//
+// If a non-zero deadline is given, optional validation checks are skipped
+// once the time remaining before the deadline is too short to perform them.
+func Run(
+	cfg *config.Config,
+	server string,
+	dnsName string,
+	endpoint Endpoint,
+	certChain []*x509.Certificate,
+	blocklist certs.CertBlocklist,
+	netBudget *budget.Budget,
+	deadline time.Time,
+	log zerolog.Logger,
+) (certs.CertChainValidationResults, Timings) {
+
+	checks := []validationCheck{
+		{
+			name:    certs.HostnameValidationResult{}.CheckName(),
+			keyword: config.ValidationKeywordHostname,
+			run: func() certs.CertChainValidationResult {
+				hostnameValidationOptions := certs.CertChainValidationOptions{
+					IgnoreHostnameVerificationFailureIfEmptySANsList: cfg.IgnoreHostnameVerificationFailureIfEmptySANsList,
+					IgnoreValidationResultHostname:                   !cfg.ApplyCertHostnameValidationResults() || (server == "" && dnsName == ""),
+				}
+
+				log.Debug().
+					Interface("validation_options", hostnameValidationOptions).
+					Msg("Hostname Validation Options")
+
+				hostnameValidationResult := certs.ValidateHostname(
+					certChain,
+					server,
+					dnsName,
+					config.IgnoreHostnameVerificationFailureIfEmptySANsListFlag,
+					hostnameValidationOptions,
+				)
+
+				switch {
+				case hostnameValidationResult.IsFailed():
+					log.Debug().
+						Err(hostnameValidationResult.Err()).
+						Msgf("%s validation failure", hostnameValidationResult.CheckName())
+
+				case hostnameValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", hostnameValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", hostnameValidationResult.CheckName())
+				}
+
+				return hostnameValidationResult
+			},
+		},
+		{
+			name:     certs.SANsListValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordSANsList,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				sansValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultSANs: !cfg.ApplyCertSANsListValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", sansValidationOptions).
+					Msg("SANs Validation Options")
+
+				sansValidationResult := certs.ValidateSANsList(
+					certChain,
+					cfg.SANsEntries,
+					sansValidationOptions,
+				)
+
+				switch {
+				case sansValidationResult.IsFailed():
+					log.Debug().
+						Err(sansValidationResult.Err()).
+						Int("sans_entries_requested", sansValidationResult.NumExpected()).
+						Int("sans_entries_found", sansValidationResult.NumMatched()).
+						Int("sans_entries_mismatched", sansValidationResult.NumMismatched()).
+						Msgf("%s validation failure", sansValidationResult.CheckName())
+
+				case sansValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", sansValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Int("sans_entries_requested", sansValidationResult.NumExpected()).
+						Int("sans_entries_found", sansValidationResult.NumMatched()).
+						Msgf("%s validation successful", sansValidationResult.CheckName())
+				}
+
+				return sansValidationResult
+			},
+		},
+		{
+			name:     certs.SerialNumberValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordSerial,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				serialNumberValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultSerialNumber: !cfg.ApplyCertSerialNumberValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", serialNumberValidationOptions).
+					Msg("Serial Number Validation Options")
+
+				serialNumberValidationResult := certs.ValidateSerialNumber(
+					certChain,
+					cfg.ExpectedSerial,
+					serialNumberValidationOptions,
+				)
+
+				switch {
+				case serialNumberValidationResult.IsFailed():
+					log.Debug().
+						Err(serialNumberValidationResult.Err()).
+						Str("expected_serial", serialNumberValidationResult.ExpectedSerial()).
+						Str("actual_serial", serialNumberValidationResult.ActualSerial()).
+						Msgf("%s validation failure", serialNumberValidationResult.CheckName())
+
+				case serialNumberValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", serialNumberValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Str("expected_serial", serialNumberValidationResult.ExpectedSerial()).
+						Msgf("%s validation successful", serialNumberValidationResult.CheckName())
+				}
+
+				return serialNumberValidationResult
+			},
+		},
+		{
+			name:     certs.BlocklistValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordBlocklist,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				blocklistValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultBlocklist: !cfg.ApplyCertBlocklistValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", blocklistValidationOptions).
+					Msg("Blocklist Validation Options")
+
+				blocklistValidationResult := certs.ValidateBlocklist(
+					certChain,
+					blocklist,
+					blocklistValidationOptions,
+				)
+
+				switch {
+				case blocklistValidationResult.IsFailed():
+					log.Debug().
+						Err(blocklistValidationResult.Err()).
+						Int("blocklist_entries", blocklistValidationResult.NumEntries()).
+						Str("match_type", blocklistValidationResult.MatchType()).
+						Msgf("%s validation failure", blocklistValidationResult.CheckName())
+
+				case blocklistValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", blocklistValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Int("blocklist_entries", blocklistValidationResult.NumEntries()).
+						Msgf("%s validation successful", blocklistValidationResult.CheckName())
+				}
+
+				return blocklistValidationResult
+			},
+		},
+		{
+			name:     certs.ExtKeyUsageValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordEKU,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				extKeyUsageValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultExtKeyUsage: !cfg.ApplyCertExtKeyUsageValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", extKeyUsageValidationOptions).
+					Msg("Extended Key Usage Validation Options")
+
+				extKeyUsageValidationResult := certs.ValidateExtKeyUsage(
+					certChain,
+					cfg.RequiredEKUs(),
+					cfg.DisallowedEKUs(),
+					extKeyUsageValidationOptions,
+				)
+
+				switch {
+				case extKeyUsageValidationResult.IsFailed():
+					log.Debug().
+						Err(extKeyUsageValidationResult.Err()).
+						Strs("ekus_present", extKeyUsageValidationResult.PresentEKUs()).
+						Int("ekus_missing", extKeyUsageValidationResult.NumMissing()).
+						Int("ekus_disallowed", extKeyUsageValidationResult.NumUnexpected()).
+						Msgf("%s validation failure", extKeyUsageValidationResult.CheckName())
+
+				case extKeyUsageValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", extKeyUsageValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Strs("ekus_present", extKeyUsageValidationResult.PresentEKUs()).
+						Msgf("%s validation successful", extKeyUsageValidationResult.CheckName())
+				}
+
+				return extKeyUsageValidationResult
+			},
+		},
+		{
+			name:     certs.ChainConstraintsValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordConstraints,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				chainConstraintsValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultChainConstraints: !cfg.ApplyCertChainConstraintsValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", chainConstraintsValidationOptions).
+					Msg("Chain Constraints Validation Options")
+
+				chainConstraintsValidationResult := certs.ValidateChainConstraints(
+					certChain,
+					chainConstraintsValidationOptions,
+				)
+
+				switch {
+				case chainConstraintsValidationResult.IsFailed():
+					log.Debug().
+						Err(chainConstraintsValidationResult.Err()).
+						Int("violations", chainConstraintsValidationResult.NumViolations()).
+						Msgf("%s validation failure", chainConstraintsValidationResult.CheckName())
+
+				case chainConstraintsValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", chainConstraintsValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", chainConstraintsValidationResult.CheckName())
+				}
+
+				return chainConstraintsValidationResult
+			},
+		},
+		{
+			name:     certs.KeyUsageValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordKeyUsage,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				keyUsageValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultKeyUsage: !cfg.ApplyCertKeyUsageValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", keyUsageValidationOptions).
+					Msg("Key Usage Validation Options")
+
+				keyUsageValidationResult := certs.ValidateKeyUsage(
+					certChain,
+					keyUsageValidationOptions,
+				)
+
+				switch {
+				case keyUsageValidationResult.IsFailed():
+					log.Debug().
+						Err(keyUsageValidationResult.Err()).
+						Int("mismatches", keyUsageValidationResult.NumViolations()).
+						Msgf("%s validation failure", keyUsageValidationResult.CheckName())
+
+				case keyUsageValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", keyUsageValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", keyUsageValidationResult.CheckName())
+				}
+
+				return keyUsageValidationResult
+			},
+		},
+		{
+			name:     certs.SelfSignedValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordSelfSigned,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				selfSignedValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultSelfSigned: !cfg.ApplyCertSelfSignedValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", selfSignedValidationOptions).
+					Msg("Self-Signed Leaf Validation Options")
+
+				selfSignedValidationResult := certs.ValidateSelfSigned(
+					certChain,
+					selfSignedValidationOptions,
+				)
+
+				switch {
+				case selfSignedValidationResult.IsFailed():
+					log.Debug().
+						Err(selfSignedValidationResult.Err()).
+						Msgf("%s validation failure", selfSignedValidationResult.CheckName())
+
+				case selfSignedValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", selfSignedValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", selfSignedValidationResult.CheckName())
+				}
+
+				return selfSignedValidationResult
+			},
+		},
+		{
+			name:     certs.DistrustedCAsValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordDistrusted,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				distrustedCAsValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultDistrustedCAs: !cfg.ApplyCertDistrustedCAsValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", distrustedCAsValidationOptions).
+					Msg("Distrusted CAs Validation Options")
+
+				distrustedCAsValidationResult := certs.ValidateDistrustedCAs(
+					certChain,
+					cfg.DistrustedCAs(),
+					distrustedCAsValidationOptions,
+				)
+
+				switch {
+				case distrustedCAsValidationResult.IsFailed():
+					log.Debug().
+						Err(distrustedCAsValidationResult.Err()).
+						Int("distrusted_cas", distrustedCAsValidationResult.NumMatches()).
+						Msgf("%s validation failure", distrustedCAsValidationResult.CheckName())
+
+				case distrustedCAsValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", distrustedCAsValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", distrustedCAsValidationResult.CheckName())
+				}
+
+				return distrustedCAsValidationResult
+			},
+		},
+		{
+			name:     certs.DuplicateCertsValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordDuplicates,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				duplicateCertsValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultDuplicateCerts: !cfg.ApplyCertDuplicateCertsValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", duplicateCertsValidationOptions).
+					Msg("Duplicate Certificates Validation Options")
+
+				duplicateCertsValidationResult := certs.ValidateDuplicateCerts(
+					certChain,
+					duplicateCertsValidationOptions,
+				)
+
+				switch {
+				case duplicateCertsValidationResult.IsFailed():
+					log.Debug().
+						Err(duplicateCertsValidationResult.Err()).
+						Int("duplicate_certificates", duplicateCertsValidationResult.NumDuplicates()).
+						Msgf("%s validation failure", duplicateCertsValidationResult.CheckName())
+
+				case duplicateCertsValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", duplicateCertsValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", duplicateCertsValidationResult.CheckName())
+				}
+
+				return duplicateCertsValidationResult
+			},
+		},
+		{
+			name:     certs.ExpiryCliffValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordExpiryCliff,
+			optional: true,
+			run: func() certs.CertChainValidationResult {
+				expiryCliffValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultExpiryCliff: !cfg.ApplyExpiryCliffValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", expiryCliffValidationOptions).
+					Msg("Expiry Cliff Validation Options")
+
+				expiryCliffValidationResult := certs.ValidateExpiryCliff(
+					certChain,
+					cfg.ExpiryCliffWindow(),
+					cfg.ExpiryCliffLeadTime(),
+					expiryCliffValidationOptions,
+				)
+
+				switch {
+				case expiryCliffValidationResult.IsFailed():
+					log.Debug().
+						Err(expiryCliffValidationResult.Err()).
+						Int("expiry_cliffs", len(expiryCliffValidationResult.Cliffs())).
+						Int("expiring_together", expiryCliffValidationResult.NumCliffCerts()).
+						Msgf("%s validation failure", expiryCliffValidationResult.CheckName())
+
+				case expiryCliffValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", expiryCliffValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", expiryCliffValidationResult.CheckName())
+				}
+
+				return expiryCliffValidationResult
+			},
+		},
+		{
+			name:     certs.CTLogsValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordCTLogs,
+			optional: true,
+			reserve:  cfg.Timeout(),
+			run: func() certs.CertChainValidationResult {
+				ctLogsValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultCTLogs: !cfg.ApplyCertCTLogsValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", ctLogsValidationOptions).
+					Msg("CT Logs Validation Options")
+
+				// The CT search API is only queried if this validation check is applied.
+				ctLogsDomain := ctLogsSearchDomain(server, dnsName, certChain)
+				var ctLogEntries []certs.CTLogEntry
+				var ctLookupErr error
+				if !ctLogsValidationOptions.IgnoreValidationResultCTLogs {
+					var apiToken string
+					apiToken, ctLookupErr = ctSearchAPIToken(cfg)
+					if ctLookupErr == nil {
+						ctLogEntries, ctLookupErr = certs.FetchCTLogEntries(
+							cfg.CTSearchURL,
+							apiToken,
+							ctLogsDomain,
+							cfg.Timeout(),
+							netBudget,
+						)
+					}
+
+					// Degrade gracefully to an ignored validation check result instead
+					// of reporting a problem with the certificate chain.
+					if errors.Is(ctLookupErr, budget.ErrExceeded) {
+						log.Warn().
+							Err(ctLookupErr).
+							Msg("CT Logs validation skipped")
+
+						ctLogsValidationOptions.IgnoreValidationResultCTLogs = true
+					}
+				}
+
+				ctLogsValidationResult := certs.ValidateCTLogs(
+					certChain,
+					ctLogsDomain,
+					ctLogEntries,
+					ctLookupErr,
+					ctLogsValidationOptions,
+				)
+
+				switch {
+				case ctLogsValidationResult.IsFailed():
+					log.Debug().
+						Err(ctLogsValidationResult.Err()).
+						Str("domain", ctLogsDomain).
+						Int("ct_log_entries", ctLogsValidationResult.NumEntries()).
+						Int("newer_certificates", ctLogsValidationResult.NumNewerCerts()).
+						Msgf("%s validation failure", ctLogsValidationResult.CheckName())
+
+				case ctLogsValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", ctLogsValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Str("domain", ctLogsDomain).
+						Int("ct_log_entries", ctLogsValidationResult.NumEntries()).
+						Bool("served_cert_logged", ctLogsValidationResult.ServedCertLogged()).
+						Msgf("%s validation successful", ctLogsValidationResult.CheckName())
+				}
+
+				return ctLogsValidationResult
+			},
+		},
+		{
+			name:     certs.RenewalInfoValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordRenewalInfo,
+			optional: true,
+			reserve:  cfg.Timeout(),
+			run: func() certs.CertChainValidationResult {
+				renewalInfoValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultRenewalInfo: !cfg.ApplyRenewalInfoValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", renewalInfoValidationOptions).
+					Msg("Renewal Info Validation Options")
+
+				// The ACME server is only queried if this validation check is
+				// applied and a leaf certificate is present; the validation
+				// check reports a missing leaf certificate.
+				var renewalInfo certs.RenewalInfo
+				var renewalInfoErr error
+				leafCerts := certs.LeafCerts(certChain)
+				if !renewalInfoValidationOptions.IgnoreValidationResultRenewalInfo && len(leafCerts) > 0 {
+					renewalInfo, renewalInfoErr = certs.FetchRenewalInfo(
+						cfg.ACMEDirectoryURL,
+						leafCerts[0],
+						cfg.Timeout(),
+						netBudget,
+					)
+
+					// Renewal information is supplemental; an unreachable ACME
+					// server (or one which does not recognize the certificate)
+					// degrades gracefully to an ignored validation check result
+					// instead of reporting a problem with the certificate chain.
+					if renewalInfoErr != nil {
+						log.Warn().
+							Err(renewalInfoErr).
+							Msg("Renewal Info validation skipped")
+
+						renewalInfoValidationOptions.IgnoreValidationResultRenewalInfo = true
+					}
+				}
+
+				renewalInfoValidationResult := certs.ValidateRenewalInfo(
+					certChain,
+					renewalInfo,
+					renewalInfoErr,
+					renewalInfoValidationOptions,
+				)
+
+				switch {
+				case renewalInfoValidationResult.IsFailed():
+					log.Debug().
+						Err(renewalInfoValidationResult.Err()).
+						Time("window_start", renewalInfo.WindowStart).
+						Time("window_end", renewalInfo.WindowEnd).
+						Msgf("%s validation failure", renewalInfoValidationResult.CheckName())
+
+				case renewalInfoValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", renewalInfoValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Time("window_start", renewalInfo.WindowStart).
+						Time("window_end", renewalInfo.WindowEnd).
+						Msgf("%s validation successful", renewalInfoValidationResult.CheckName())
+				}
+
+				return renewalInfoValidationResult
+			},
+		},
+		{
+			name:     certs.MinTLSVersionValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordTLSVersion,
+			optional: true,
+			reserve:  cfg.Timeout(),
+			run: func() certs.CertChainValidationResult {
+				minTLSVersionValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultMinTLSVersion: !cfg.ApplyMinTLSVersionValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", minTLSVersionValidationOptions).
+					Msg("Minimum TLS Version Validation Options")
+
+				minVersion := cfg.MinTLSVersion()
+
+				// The server is only probed if this validation check is applied.
+				var acceptedVersions []uint16
+				var probeErr error
+				if !minTLSVersionValidationOptions.IgnoreValidationResultMinTLSVersion {
+					if endpoint.IPAddr == "" {
+						return certs.NewSkippedValidationResult(
+							certChain,
+							certs.MinTLSVersionValidationResult{}.CheckName(),
+							skippedNoConnectionReason,
+						)
+					}
+
+					acceptedVersions, probeErr = netutils.ProbeTLSVersions(
+						endpoint.HostVal,
+						endpoint.IPAddr,
+						endpoint.Port,
+						netutils.TLSVersionsBelow(minVersion),
+						cfg.Timeout(),
+						cfg.CertRetrievalOptions(),
+						log,
+					)
+				}
+
+				minTLSVersionValidationResult := certs.ValidateMinTLSVersion(
+					certChain,
+					minVersion,
+					acceptedVersions,
+					probeErr,
+					minTLSVersionValidationOptions,
+				)
+
+				switch {
+				case minTLSVersionValidationResult.IsFailed():
+					log.Debug().
+						Err(minTLSVersionValidationResult.Err()).
+						Str("min_tls_version", netutils.TLSVersionName(minVersion)).
+						Strs("legacy_versions_accepted", minTLSVersionValidationResult.LegacyVersions()).
+						Msgf("%s validation failure", minTLSVersionValidationResult.CheckName())
+
+				case minTLSVersionValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", minTLSVersionValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Str("min_tls_version", netutils.TLSVersionName(minVersion)).
+						Msgf("%s validation successful", minTLSVersionValidationResult.CheckName())
+				}
+
+				return minTLSVersionValidationResult
+			},
+		},
+		{
+			name:     certs.WeakCipherSuitesValidationResult{}.CheckName(),
+			keyword:  config.ValidationKeywordWeakCiphers,
+			optional: true,
+			reserve:  cfg.Timeout(),
+			run: func() certs.CertChainValidationResult {
+				weakCipherSuitesValidationOptions := certs.CertChainValidationOptions{
+					IgnoreValidationResultWeakCipherSuites: !cfg.ApplyWeakCipherSuitesValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", weakCipherSuitesValidationOptions).
+					Msg("Weak Cipher Suites Validation Options")
+
+				// The server is only probed if this validation check is applied.
+				var acceptedSuites []netutils.WeakCipherSuite
+				var probeErr error
+				if !weakCipherSuitesValidationOptions.IgnoreValidationResultWeakCipherSuites {
+					if endpoint.IPAddr == "" {
+						return certs.NewSkippedValidationResult(
+							certChain,
+							certs.WeakCipherSuitesValidationResult{}.CheckName(),
+							skippedNoConnectionReason,
+						)
+					}
+
+					acceptedSuites, probeErr = netutils.ProbeWeakCipherSuites(
+						endpoint.HostVal,
+						endpoint.IPAddr,
+						endpoint.Port,
+						cfg.Timeout(),
+						cfg.CertRetrievalOptions(),
+						log,
+					)
+				}
+
+				weakCipherSuitesValidationResult := certs.ValidateWeakCipherSuites(
+					certChain,
+					acceptedSuites,
+					probeErr,
+					weakCipherSuitesValidationOptions,
+				)
+
+				switch {
+				case weakCipherSuitesValidationResult.IsFailed():
+					log.Debug().
+						Err(weakCipherSuitesValidationResult.Err()).
+						Strs("weak_suites_accepted", weakCipherSuitesValidationResult.WeakSuites()).
+						Msgf("%s validation failure", weakCipherSuitesValidationResult.CheckName())
+
+				case weakCipherSuitesValidationResult.IsIgnored():
+					log.Debug().
+						Msgf("%s validation ignored", weakCipherSuitesValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Msgf("%s validation successful", weakCipherSuitesValidationResult.CheckName())
+				}
+
+				return weakCipherSuitesValidationResult
+			},
+		},
+		{
+			name:    certs.ExpirationValidationResult{}.CheckName(),
+			keyword: config.ValidationKeywordExpiration,
+			run: func() certs.CertChainValidationResult {
+				expirationValidationOptions := certs.CertChainValidationOptions{
+					IgnoreExpiredIntermediateCertificates:  cfg.IgnoreExpiredIntermediateCertificates,
+					IgnoreExpiredRootCertificates:          cfg.IgnoreExpiredRootCertificates,
+					IgnoreExpiringIntermediateCertificates: cfg.IgnoreExpiringIntermediateCertificates,
+					IgnoreExpiringRootCertificates:         cfg.IgnoreExpiringRootCertificates,
+					IgnoredSerialNumbers:                   cfg.IgnoredSerials(),
+					IgnoredFingerprints:                    cfg.IgnoredFingerprints(),
+					IgnoreValidationResultExpiration:       !cfg.ApplyCertExpirationValidationResults(),
+				}
+
+				log.Debug().
+					Interface("validation_options", expirationValidationOptions).
+					Msg("Expiration Validation Options")
+
+				ageCritical, ageWarning := ExpirationThresholds(cfg, certChain)
+
+				thresholds := certs.ExpirationThresholds{
+					LeafCritical:         ageCritical,
+					LeafWarning:          ageWarning,
+					IntermediateCritical: cfg.AgeCriticalIntermediate,
+					IntermediateWarning:  cfg.AgeWarningIntermediate,
+					RootCritical:         cfg.AgeCriticalRoot,
+					RootWarning:          cfg.AgeWarningRoot,
+				}
+
+				// Percentage thresholds are noted in the report output when
+				// used to calculate the leaf certificate thresholds.
+				thresholds.LeafCriticalPercent, thresholds.LeafWarningPercent = ExpirationPercentThresholds(cfg, certChain)
+
+				log.Debug().
+					Interface("thresholds", thresholds).
+					Msg("Expiration thresholds")
+
+				expirationValidationResult := certs.ValidateExpirationByPosition(
+					certChain,
+					thresholds,
+					cfg.VerboseOutput,
+					cfg.OmitSANsEntries,
+					expirationValidationOptions,
+				)
+
+				switch {
+				case expirationValidationResult.IsFailed():
+					log.Debug().
+						Err(expirationValidationResult.Err()).
+						Int("total_certificates", expirationValidationResult.TotalCerts()).
+						Int("expired_certificates", expirationValidationResult.NumExpiredCerts()).
+						Int("expiring_certificates", expirationValidationResult.NumExpiringCerts()).
+						Int("valid_certificates", expirationValidationResult.NumValidCerts()).
+						Msgf("%s validation failure", expirationValidationResult.CheckName())
+
+				case expirationValidationResult.IsIgnored():
+					log.Debug().
+						Int("total_certificates", expirationValidationResult.TotalCerts()).
+						Msgf("%s validation ignored", expirationValidationResult.CheckName())
+
+				default:
+					log.Debug().
+						Int("total_certificates", expirationValidationResult.TotalCerts()).
+						Int("expired_certificates", expirationValidationResult.NumExpiredCerts()).
+						Int("expiring_certificates", expirationValidationResult.NumExpiringCerts()).
+						Int("valid_certificates", expirationValidationResult.NumValidCerts()).
+						Msgf("%s validation successful", expirationValidationResult.CheckName())
+
+				}
+
+				return expirationValidationResult
+			},
+		},
+	}
+
+	maxSeverity := cfg.MaxSeverity()
+
+	// Create "bucket" to collect validation results.
+	validationResults := make(certs.CertChainValidationResults, 0, len(checks))
+	timings := make(Timings, 0, len(checks))
+
+	for _, check := range checks {
+		// Optional validation checks are skipped if the time remaining
+		// before the plugin timeout is too short to perform them. This
+		// allows results for completed validation checks to be emitted
+		// instead of the plugin being terminated without any output.
+		if check.optional && !deadline.IsZero() && time.Until(deadline) <= check.reserve {
+			skippedResult := certs.NewSkippedValidationResult(
+				certChain,
+				check.name,
+				skippedTimeBudgetReason,
+			)
+			validationResults.Add(skippedResult)
+
+			log.Warn().
+				Dur("time_remaining", time.Until(deadline)).
+				Msgf("%s validation skipped", check.name)
+
+			continue
+		}
+
+		checkStart := time.Now()
+		result := check.run()
+		timings.record(result.CheckName(), checkStart)
+
+		// A failing validation check may be limited to a less severe state
+		// so that known problems are still surfaced (e.g., during a
+		// migration) without triggering a CRITICAL state.
+		if maxState, ok := maxSeverity[check.keyword]; ok {
+			log.Debug().
+				Str("max_state", maxState).
+				Msgf("Limiting %s validation state", check.name)
+
+			result = certs.NewSeverityLimitedValidationResult(result, maxState)
+		}
+
+		validationResults.Add(result)
+	}
+
+	timings.log(log)
+
+	return validationResults, timings
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 Adam Chalkley
+//
+// https://github.com/atc0005/check-cert
+//
+// Licensed under the MIT License. See LICENSE file in the project root for
+// full license information.
+
+package validation
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/atc0005/check-cert/internal/certs"
+	"github.com/atc0005/check-cert/internal/config"
+)
+
+// newTestLeafCert generates a self-signed leaf certificate for the given
+// DNS names.
+func newTestLeafCert(t *testing.T, dnsNames ...string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	return cert
+}
+
+// findResult returns the validation check result with the given name.
+func findResult(t *testing.T, results certs.CertChainValidationResults, name string) certs.CertChainValidationResult {
+	t.Helper()
+
+	for _, result := range results {
+		if result.CheckName() == name {
+			return result
+		}
+	}
+
+	t.Fatalf("validation check result %q not found", name)
+
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	certChain := []*x509.Certificate{newTestLeafCert(t, "www.example.com")}
+	hostnameCheck := certs.HostnameValidationResult{}.CheckName()
+	expirationCheck := certs.ExpirationValidationResult{}.CheckName()
+
+	t.Run("AllChecks", func(t *testing.T) {
+		results, timings := Run(
+			&config.Config{},
+			"www.example.com",
+			"",
+			Endpoint{},
+			certChain,
+			certs.CertBlocklist{},
+			nil,
+			time.Time{},
+			zerolog.Nop(),
+		)
+
+		if results.Total() != len(timings) {
+			t.Errorf("want timing for each of %d validation checks; got %d", results.Total(), len(timings))
+		}
+
+		hostname := findResult(t, results, hostnameCheck)
+		if hostname.IsFailed() || hostname.IsIgnored() {
+			t.Errorf("want applied, successful hostname validation; got %v", hostname)
+		}
+
+		for _, result := range results {
+			if _, ok := result.(certs.SkippedValidationResult); ok {
+				t.Errorf("want no skipped validation checks; got %s", result.CheckName())
+			}
+		}
+	})
+
+	t.Run("WithoutServer", func(t *testing.T) {
+		results, _ := Run(
+			&config.Config{},
+			"",
+			"",
+			Endpoint{},
+			certChain,
+			certs.CertBlocklist{},
+			nil,
+			time.Time{},
+			zerolog.Nop(),
+		)
+
+		if hostname := findResult(t, results, hostnameCheck); !hostname.IsIgnored() {
+			t.Errorf("want ignored hostname validation without server; got %v", hostname)
+		}
+	})
+
+	t.Run("PastDeadline", func(t *testing.T) {
+		results, _ := Run(
+			&config.Config{},
+			"www.example.com",
+			"",
+			Endpoint{},
+			certChain,
+			certs.CertBlocklist{},
+			nil,
+			time.Now().Add(-time.Second),
+			zerolog.Nop(),
+		)
+
+		for _, result := range results {
+			_, skipped := result.(certs.SkippedValidationResult)
+			required := result.CheckName() == hostnameCheck || result.CheckName() == expirationCheck
+
+			if skipped == required {
+				t.Errorf("want only optional validation checks skipped; %s skipped: %t", result.CheckName(), skipped)
+			}
+		}
+	})
+}
+
+func TestCTLogsSearchDomain(t *testing.T) {
+	certChain := []*x509.Certificate{newTestLeafCert(t, "www.example.com", "example.com")}
+
+	tests := []struct {
+		name      string
+		server    string
+		dnsName   string
+		certChain []*x509.Certificate
+		want      string
+	}{
+		{
+			name:      "DNSName",
+			server:    "192.0.2.10",
+			dnsName:   "mail.example.com",
+			certChain: certChain,
+			want:      "mail.example.com",
+		},
+		{
+			name:      "Server",
+			server:    "api.example.com",
+			certChain: certChain,
+			want:      "api.example.com",
+		},
+		{
+			name:      "ServerIPAddress",
+			server:    "192.0.2.10",
+			certChain: certChain,
+			want:      "www.example.com",
+		},
+		{
+			name:      "NoServer",
+			certChain: certChain,
+			want:      "www.example.com",
+		},
+		{
+			name:   "NoLeafCert",
+			server: "192.0.2.10",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got := ctLogsSearchDomain(tt.server, tt.dnsName, tt.certChain)
+			if got != tt.want {
+				t.Errorf("want %q; got %q", tt.want, got)
+			}
+		})
+	}
+}